@@ -22,6 +22,11 @@ func main() {
 		logger.Fatal("Database error", zap.Error(err))
 	}
 
+	store.BlobStore, err = storage.NewBlobStore(cfg)
+	if err != nil {
+		logger.Fatal("Blob store error", zap.Error(err))
+	}
+
 	server := grpc.NewServer(cfg, store, logger)
 	err = server.Start()
 	if err != nil {
@@ -4,6 +4,8 @@ package config
 import (
 	"errors"
 	"github.com/spf13/viper"
+	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -13,6 +15,7 @@ type Config struct {
 	BuildDate     string // Информация о сборке (дата)
 	BuildVersion  string // Информация о сборке (версия)
 	ServerAddress string // Address определяет адрес сервера.
+	DeviceFile    string // DeviceFile определяет путь к файлу с идентификатором устройства; пустое значение отключает его сохранение.
 }
 
 // LoadConfig инициализирует и возвращает новый экземпляр конфигурации.
@@ -28,7 +31,13 @@ func LoadConfig() (*Config, error) {
 		return nil, errors.New("server address is not set: set GOPHKEEPER_ADDRESS environment variable")
 	}
 
+	if configDir, err := os.UserConfigDir(); err == nil {
+		viper.SetDefault("device-file", filepath.Join(configDir, "gophkeeper", "device.json"))
+	}
+	deviceFile := viper.GetString("device-file")
+
 	return &Config{
 		ServerAddress: address,
+		DeviceFile:    deviceFile,
 	}, nil
 }
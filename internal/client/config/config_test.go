@@ -4,10 +4,16 @@ import (
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
 func TestLoadConfig(t *testing.T) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Skipf("user config dir is not available: %v", err)
+	}
+
 	tests := []struct {
 		name           string
 		setupEnv       func()
@@ -18,9 +24,21 @@ func TestLoadConfig(t *testing.T) {
 			name: "All_Variables_Set_Correctly",
 			setupEnv: func() {
 				os.Setenv("GOPHKEEPER_ADDRESS", "127.0.0.1:5000")
+				os.Setenv("GOPHKEEPER_DEVICE_FILE", "/tmp/device.json")
 			},
 			expectedConfig: &Config{
 				ServerAddress: "127.0.0.1:5000",
+				DeviceFile:    "/tmp/device.json",
+			},
+		},
+		{
+			name: "Default_Device_File",
+			setupEnv: func() {
+				os.Setenv("GOPHKEEPER_ADDRESS", "127.0.0.1:5000")
+			},
+			expectedConfig: &Config{
+				ServerAddress: "127.0.0.1:5000",
+				DeviceFile:    filepath.Join(configDir, "gophkeeper", "device.json"),
 			},
 		},
 	}
@@ -32,6 +50,8 @@ func TestLoadConfig(t *testing.T) {
 
 			config, err := LoadConfig()
 
+			os.Unsetenv("GOPHKEEPER_DEVICE_FILE")
+
 			if tc.expectedError != "" {
 				assert.Error(t, err)
 				assert.Nil(t, config)
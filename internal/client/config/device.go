@@ -0,0 +1,96 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// defaultDeviceName используется, если имя хоста определить не удалось.
+const defaultDeviceName = "GophKeeper client"
+
+// Device описывает устройство, на котором запущен клиент.
+// Идентификатор устройства передаётся серверу при входе и в заголовке X-Client-ID,
+// по нему сервер связывает сессии и потоки уведомлений с устройством.
+type Device struct {
+	// ID - постоянный идентификатор устройства.
+	ID uint64 `json:"id"`
+	// Name - имя устройства, под которым оно отображается в списке сессий.
+	Name string `json:"name"`
+}
+
+// LoadDevice читает описание устройства из файла path. Если файла ещё нет, создаёт устройство
+// со случайным идентификатором и сохраняет его, чтобы при следующих запусках клиент оставался
+// тем же устройством. Для пустого path возвращает новое устройство без сохранения.
+func LoadDevice(path string) (*Device, error) {
+	if path == "" {
+		return newDevice()
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		device, err := newDevice()
+		if err != nil {
+			return nil, err
+		}
+		if err = saveDevice(path, device); err != nil {
+			return nil, err
+		}
+		return device, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read device file: %w", err)
+	}
+
+	var device Device
+	if err = json.Unmarshal(data, &device); err != nil {
+		return nil, fmt.Errorf("failed to parse device file: %w", err)
+	}
+	if device.ID == 0 || device.ID > math.MaxInt32 {
+		return nil, fmt.Errorf("invalid device id in %s", path)
+	}
+
+	return &device, nil
+}
+
+// newDevice создаёт устройство со случайным идентификатором и именем хоста.
+// Идентификатор ограничен диапазоном int32, в котором он передаётся в заголовке X-Client-ID.
+func newDevice() (*Device, error) {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate device id: %w", err)
+	}
+
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		name = defaultDeviceName
+	}
+
+	return &Device{
+		ID:   binary.BigEndian.Uint64(buf[:])%(math.MaxInt32-1) + 1,
+		Name: name,
+	}, nil
+}
+
+// saveDevice сохраняет описание устройства в файл, доступный только текущему пользователю.
+func saveDevice(path string, device *Device) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create device file directory: %w", err)
+	}
+
+	data, err := json.Marshal(device)
+	if err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write device file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadDevice(t *testing.T) {
+	t.Run("Persisted_Between_Runs", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "gophkeeper", "device.json")
+
+		first, err := LoadDevice(path)
+		require.NoError(t, err)
+		second, err := LoadDevice(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, first, second)
+		assert.NotZero(t, first.ID)
+		assert.LessOrEqual(t, first.ID, uint64(math.MaxInt32))
+		assert.NotEmpty(t, first.Name)
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	})
+
+	t.Run("Ephemeral_Without_Path", func(t *testing.T) {
+		device, err := LoadDevice("")
+		require.NoError(t, err)
+		assert.NotZero(t, device.ID)
+	})
+
+	t.Run("Invalid_File", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "device.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"id":0}`), 0o600))
+
+		_, err := LoadDevice(path)
+		assert.Error(t, err)
+
+		require.NoError(t, os.WriteFile(path, []byte(`not json`), 0o600))
+
+		_, err = LoadDevice(path)
+		assert.Error(t, err)
+	})
+}
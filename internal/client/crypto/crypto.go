@@ -1,13 +1,22 @@
 // Package crypto предоставляет функции для безопасного шифрования и дешифрования строк,
 // а также для генерации криптографических ключей из паролей и соли. Пакет использует алгоритмы AES-GCM
-// для шифрования и scrypt для генерации ключей, обеспечивая высокий уровень безопасности
-// при обработке конфиденциальных данных.
+// и XChaCha20-Poly1305 для шифрования и scrypt или Argon2id для генерации ключей, обеспечивая высокий
+// уровень безопасности при обработке конфиденциальных данных.
 //
 // Основные возможности пакета:
 //
 // - DeriveKey: генерация криптографического ключа из пароля и соли.
+// - DeriveKeys: получение из мастер-пароля раздельных хэша аутентификации и ключа шифрования.
 // - Encrypt: шифрование строки с использованием AES-GCM.
 // - Decrypt: расшифровка строки, зашифрованной с помощью Encrypt.
+// - EncryptBound и DecryptBound: шифрование с привязкой к владельцу, идентификатору и типу секрета.
+// - Seal и Open: двоичный конверт с версией формата, идентификатором шифра и ключа; Open читает и прежние форматы.
+// - SealString и OpenString: конверт Seal в кодировке base64 для текстовых полей.
+// - OpenSealed и OpenSealedString: расшифровка только конверта Seal, без прежних форматов.
+// - NewKey, WrapKey и UnwrapKey: случайные ключи хранилища и данных и их шифрование другим ключом.
+// - NewKeyPair, WrapKeyToPublic и UnwrapKeyWithPrivate: шифрование ключа данных для другого пользователя.
+// - EncryptChunk и DecryptChunk: шифрование фрагментов файлов при потоковой передаче.
+// - SearchTokens: токены слепого индекса слов для поиска по зашифрованным заголовкам.
 // - Обработка ошибок, связанных с недостаточной длиной зашифрованной строки.
 //
 // Пример использования:
@@ -28,26 +37,185 @@
 package crypto
 
 import (
+	"beliaev-aa/GophKeeper/pkg/models"
+	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
 	"golang.org/x/crypto/scrypt"
 	"io"
+	"slices"
+	"strings"
+	"unicode"
 )
 
+const (
+	// keySize - размер выводимых ключей в байтах.
+	keySize = 32
+	// authInfo - контекст HKDF для вывода хэша аутентификации.
+	authInfo = "gophkeeper/auth"
+	// encryptionInfo - контекст HKDF для вывода ключа шифрования хранилища.
+	encryptionInfo = "gophkeeper/encryption"
+	// searchIndexInfo - контекст HKDF для вывода ключа слепого индекса из ключа шифрования.
+	searchIndexInfo = "gophkeeper/search-index"
+	// searchTokenSize - длина токена слепого индекса в байтах.
+	searchTokenSize = 16
+	// shareInfo - контекст HKDF для вывода ключа, которым ключ данных переданного секрета шифруется для получателя.
+	shareInfo = "gophkeeper/share"
+	// senderShareInfo - контекст HKDF для вывода ключа, которым ключ данных шифруется для получателя
+	// с подтверждением отправителя (WrapKeyFrom).
+	senderShareInfo = "gophkeeper/share-from"
+	// fingerprintSize - длина отпечатка открытого ключа в байтах.
+	fingerprintSize = 16
+)
+
+// CipherID определяет алгоритм AEAD, которым зашифрован конверт.
+type CipherID byte
+
+const (
+	// CipherAES256GCM - AES-256 в режиме GCM с 12-байтовым nonce.
+	CipherAES256GCM CipherID = 1
+	// CipherXChaCha20Poly1305 - XChaCha20-Poly1305 с 24-байтовым nonce.
+	CipherXChaCha20Poly1305 CipherID = 2
+)
+
+// DefaultCipher - алгоритм, которым Seal шифрует новые данные.
+const DefaultCipher = CipherAES256GCM
+
+// Format - версия формата шифротекста. Хранится первым байтом конверта; более ранние форматы
+// определяются по виду шифротекста.
+type Format byte
+
+const (
+	// FormatLegacy - шестнадцатеричная запись nonce и шифротекста AES-GCM без привязки к секрету (Encrypt).
+	FormatLegacy Format = iota
+	// FormatBoundHex - шестнадцатеричная запись с префиксом "v1:" и привязкой к секрету (EncryptBound).
+	FormatBoundHex
+	// FormatEnvelope - двоичный конверт с заголовком (Seal).
+	FormatEnvelope
+)
+
+const (
+	// keyIDSize - длина идентификатора ключа в заголовке конверта.
+	keyIDSize = 8
+	// keyIDInfo - контекст HKDF для вывода идентификатора ключа из самого ключа.
+	keyIDInfo = "gophkeeper/key-id"
+	// envelopeHeaderSize - длина заголовка конверта: версия формата, идентификатор шифра и идентификатор ключа.
+	envelopeHeaderSize = 2 + keyIDSize
+)
+
+// boundPrefix - префикс шифротекста версии 1, привязанного к секрету дополнительными аутентифицируемыми данными.
+// Шифротекст без префикса записан Encrypt до введения привязки.
+const boundPrefix = "v1:"
+
+// BlobChunkSize - размер фрагмента открытых данных файла при потоковой передаче.
+const BlobChunkSize = 1 << 20
+
 // ErrCiphertextTooShort указывает, что переданная зашифрованная строка
 // (ciphertext) недостаточно длинная для корректной расшифровки.
 // Как правило, требуется минимум 12 байт для nonce в AES-GCM.
 var ErrCiphertextTooShort = errors.New("ciphertext too short")
 
+// ErrBindingMismatch указывает, что шифротекст не прошёл проверку: он привязан к другому секрету
+// или полю, подменён или зашифрован другим ключом.
+var ErrBindingMismatch = errors.New("ciphertext does not belong to this secret")
+
+// ErrWrongKey указывает, что конверт зашифрован другим ключом: идентификатор ключа в заголовке не совпадает.
+var ErrWrongKey = errors.New("ciphertext is encrypted with another key")
+
+// ErrUnsupportedCipher указывает, что конверт зашифрован неизвестным алгоритмом.
+var ErrUnsupportedCipher = errors.New("unsupported cipher")
+
+// ErrNotSealed указывает, что шифротекст записан в прежнем формате, тогда как ожидается конверт Seal.
+var ErrNotSealed = errors.New("ciphertext is not a sealed envelope")
+
+// ErrInvalidWrappedKey указывает, что зашифрованный ключ записан не в формате конверта или имеет неверную длину.
+var ErrInvalidWrappedKey = errors.New("invalid wrapped key")
+
+// ErrFingerprintMismatch указывает, что отпечаток открытого ключа не совпадает с ожидаемым:
+// ключ мог быть подменён сервером.
+var ErrFingerprintMismatch = errors.New("public key fingerprint mismatch")
+
 // DeriveKey - Генерация ключа из мастер-пароля и соли
 func DeriveKey(password, salt string) ([]byte, error) {
 	if password == "" {
 		return []byte(password), errors.New("empty password")
 	}
-	return scrypt.Key([]byte(password), []byte(salt), 32768, 8, 1, 32)
+	return scrypt.Key([]byte(password), []byte(salt), 32768, 8, 1, keySize)
+}
+
+// Keys содержит значения, выводимые из мастер-пароля пользователя.
+type Keys struct {
+	// AuthHash - хэш для аутентификации на сервере в шестнадцатеричном виде.
+	// Единственное производное от мастер-пароля значение, которое покидает клиент.
+	AuthHash string
+	// EncryptionKey - ключ шифрования хранилища. Никогда не передаётся на сервер.
+	EncryptionKey []byte
+}
+
+// DeriveKeys выводит из мастер-пароля хэш аутентификации и ключ шифрования по параметрам KDF пользователя.
+// Мастер-ключ получается через scrypt или Argon2id, после чего из него через HKDF-SHA256 с разными контекстами
+// выводятся два независимых значения: знание хэша аутентификации не позволяет восстановить ключ шифрования.
+func DeriveKeys(password string, params *models.KDFParams) (*Keys, error) {
+	masterKey, err := deriveMasterKey(password, params)
+	if err != nil {
+		return nil, err
+	}
+
+	authKey, err := expandKey(masterKey, authInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptionKey, err := expandKey(masterKey, encryptionInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Keys{
+		AuthHash:      hex.EncodeToString(authKey),
+		EncryptionKey: encryptionKey,
+	}, nil
+}
+
+// deriveMasterKey вычисляет мастер-ключ из мастер-пароля алгоритмом, заданным в параметрах KDF.
+func deriveMasterKey(password string, params *models.KDFParams) ([]byte, error) {
+	if password == "" {
+		return nil, errors.New("empty password")
+	}
+	if params == nil {
+		return nil, errors.New("kdf params are not set")
+	}
+
+	switch params.Algorithm {
+	case models.KDFScrypt:
+		return scrypt.Key([]byte(password), params.Salt, int(params.ScryptN), int(params.ScryptR), int(params.ScryptP), keySize)
+	case models.KDFArgon2id:
+		return argon2.IDKey([]byte(password), params.Salt, params.Argon2Time, params.Argon2Memory, uint8(params.Argon2Threads), keySize), nil
+	default:
+		return nil, fmt.Errorf("unsupported kdf algorithm %q", params.Algorithm)
+	}
+}
+
+// expandKey выводит из мастер-ключа подключ для заданного контекста с помощью HKDF-SHA256.
+func expandKey(masterKey []byte, info string) ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, masterKey, []byte(info)), key); err != nil {
+		return nil, err
+	}
+	return key, nil
 }
 
 // Encrypt - Шифрование строки
@@ -104,3 +272,577 @@ func Decrypt(encrypted string, key []byte) (string, error) {
 
 	return string(plaintext), nil
 }
+
+// SecretAAD формирует дополнительные аутентифицируемые данные поля field секрета: логин владельца owner,
+// идентификатор и тип секрета. Логин нормализуется так же, как в LegacyKDFParams. Строки предваряются длиной,
+// поэтому разные наборы значений не совпадают.
+func SecretAAD(owner string, secretID uint64, secretType, field string) []byte {
+	owner = strings.ToLower(strings.TrimSpace(owner))
+	aad := make([]byte, 0, len(owner)+len(secretType)+len(field)+20)
+	for _, value := range []string{owner, secretType, field} {
+		aad = binary.BigEndian.AppendUint32(aad, uint32(len(value)))
+		aad = append(aad, value...)
+	}
+	return binary.BigEndian.AppendUint64(aad, secretID)
+}
+
+// VaultKeyAAD формирует дополнительные аутентифицируемые данные ключа хранилища пользователя owner.
+func VaultKeyAAD(owner string) []byte {
+	return SecretAAD(owner, 0, "", "vault_key")
+}
+
+// PrivateKeyAAD формирует дополнительные аутентифицируемые данные закрытого ключа пользователя owner.
+func PrivateKeyAAD(owner string) []byte {
+	return SecretAAD(owner, 0, "", "private_key")
+}
+
+// ShareAAD формирует дополнительные аутентифицируемые данные ключа данных секрета владельца owner,
+// зашифрованного для получателя recipient: ключ нельзя выдать за ключ другого секрета или другого получателя.
+func ShareAAD(owner, recipient string, secretID uint64, secretType string) []byte {
+	return SecretAAD(owner, secretID, secretType, "share/"+strings.ToLower(strings.TrimSpace(recipient)))
+}
+
+// OrganizationKeyAAD формирует дополнительные аутентифицируемые данные ключа хранилища организации name,
+// зашифрованного для участника member. Идентификатор организации при её создании ещё неизвестен,
+// поэтому ключ привязывается к названию.
+func OrganizationKeyAAD(name, member string) []byte {
+	return SecretAAD("org:"+strings.TrimSpace(name), 0, "", "org_key/"+strings.ToLower(strings.TrimSpace(member)))
+}
+
+// EmergencyKeyAAD формирует дополнительные аутентифицируемые данные ключа хранилища владельца grantor,
+// зашифрованного для доверенного пользователя grantee: ключ нельзя выдать за ключ другого хранилища.
+func EmergencyKeyAAD(grantor, grantee string) []byte {
+	return SecretAAD(grantor, 0, "", "emergency/"+strings.ToLower(strings.TrimSpace(grantee)))
+}
+
+// EncryptBound шифрует строку с помощью AES-GCM, привязывая шифротекст к дополнительным данным aad,
+// обычно полученным из SecretAAD. Результат - префикс версии и шестнадцатеричная запись nonce и шифротекста;
+// версия также входит в проверяемые данные.
+func EncryptBound(plaintext string, key, aad []byte) (string, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), boundAAD(aad))
+	return boundPrefix + hex.EncodeToString(sealed), nil
+}
+
+// DecryptBound расшифровывает строку, зашифрованную EncryptBound с теми же дополнительными данными aad.
+// Строка без префикса версии расшифровывается как записанная Encrypt, без проверки привязки; в этом случае
+// bound равен false, и шифротекст следует перезаписать через EncryptBound. Если привязанный шифротекст
+// не проходит проверку, возвращается ErrBindingMismatch.
+func DecryptBound(encrypted string, key, aad []byte) (plaintext string, bound bool, err error) {
+	data, bound := strings.CutPrefix(encrypted, boundPrefix)
+	if !bound {
+		plaintext, err = Decrypt(encrypted, key)
+		return plaintext, false, err
+	}
+
+	raw, err := hex.DecodeString(data)
+	if err != nil {
+		return "", true, err
+	}
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return "", true, err
+	}
+	if len(raw) < aead.NonceSize() {
+		return "", true, ErrCiphertextTooShort
+	}
+
+	nonce, ciphertext := raw[:aead.NonceSize()], raw[aead.NonceSize():]
+	opened, err := aead.Open(nil, nonce, ciphertext, boundAAD(aad))
+	if err != nil {
+		return "", true, fmt.Errorf("%w: %v", ErrBindingMismatch, err)
+	}
+
+	return string(opened), true, nil
+}
+
+// boundAAD дополняет данные привязки версией формата шифротекста.
+func boundAAD(aad []byte) []byte {
+	return append([]byte(boundPrefix), aad...)
+}
+
+// Seal шифрует данные алгоритмом DefaultCipher в двоичный конверт, привязанный к дополнительным данным aad.
+// Конверт состоит из версии формата (1 байт), идентификатора шифра (1 байт), идентификатора ключа (8 байт),
+// nonce и шифротекста. Заголовок входит в проверяемые данные, поэтому его нельзя изменить незаметно.
+func Seal(plaintext, key, aad []byte) ([]byte, error) {
+	return SealWith(DefaultCipher, plaintext, key, aad)
+}
+
+// SealWith шифрует данные в двоичный конверт заданным алгоритмом cipherID.
+func SealWith(cipherID CipherID, plaintext, key, aad []byte) ([]byte, error) {
+	aead, err := newAEAD(cipherID, key)
+	if err != nil {
+		return nil, err
+	}
+	kid, err := keyID(key)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope := make([]byte, 0, envelopeHeaderSize+aead.NonceSize()+len(plaintext)+aead.Overhead())
+	envelope = append(envelope, byte(FormatEnvelope), byte(cipherID))
+	envelope = append(envelope, kid...)
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	envelope = append(envelope, nonce...)
+
+	return aead.Seal(envelope, nonce, plaintext, envelopeAAD(envelope[:envelopeHeaderSize], aad)), nil
+}
+
+// Open расшифровывает данные, выбирая формат по заголовку: конверт Seal, шифротекст EncryptBound
+// или шестнадцатеричный шифротекст Encrypt, который расшифровывается без проверки привязки.
+// Возвращает формат шифротекста, чтобы данные прежних форматов можно было перешифровать.
+// Если конверт зашифрован другим ключом, возвращается ErrWrongKey, а если он привязан к другим данным
+// или подменён - ErrBindingMismatch.
+func Open(data, key, aad []byte) ([]byte, Format, error) {
+	switch {
+	case len(data) > 0 && data[0] == byte(FormatEnvelope):
+		plaintext, err := openEnvelope(data, key, aad)
+		return plaintext, FormatEnvelope, err
+	case bytes.HasPrefix(data, []byte(boundPrefix)):
+		plaintext, _, err := DecryptBound(string(data), key, aad)
+		return []byte(plaintext), FormatBoundHex, err
+	default:
+		plaintext, err := Decrypt(string(data), key)
+		return []byte(plaintext), FormatLegacy, err
+	}
+}
+
+// SealString шифрует строку в конверт Seal и возвращает его в кодировке base64 для хранения в текстовых полях.
+func SealString(plaintext string, key, aad []byte) (string, error) {
+	envelope, err := Seal([]byte(plaintext), key, aad)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(envelope), nil
+}
+
+// OpenString расшифровывает строку, зашифрованную SealString, EncryptBound или Encrypt, и возвращает её формат.
+// Запись base64 конверта начинается с символа, который не встречается в начале шифротекстов прежних форматов.
+func OpenString(encrypted string, key, aad []byte) (string, Format, error) {
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil || len(data) == 0 || data[0] != byte(FormatEnvelope) {
+		data = []byte(encrypted)
+	}
+
+	plaintext, format, err := Open(data, key, aad)
+	return string(plaintext), format, err
+}
+
+// OpenSealed расшифровывает только конверт Seal, проверяя его привязку к дополнительным данным aad.
+// Шифротексты прежних форматов, которые Open принимает, в том числе без проверки привязки, отклоняются
+// с ErrNotSealed.
+func OpenSealed(data, key, aad []byte) ([]byte, error) {
+	if len(data) == 0 || data[0] != byte(FormatEnvelope) {
+		return nil, ErrNotSealed
+	}
+	return openEnvelope(data, key, aad)
+}
+
+// OpenSealedString расшифровывает строку, зашифрованную SealString. Строки прежних форматов отклоняются
+// с ErrNotSealed.
+func OpenSealedString(encrypted string, key, aad []byte) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", ErrNotSealed
+	}
+
+	plaintext, err := OpenSealed(data, key, aad)
+	return string(plaintext), err
+}
+
+// openEnvelope расшифровывает двоичный конверт, проверяя шифр и идентификатор ключа из заголовка.
+func openEnvelope(envelope, key, aad []byte) ([]byte, error) {
+	if len(envelope) < envelopeHeaderSize {
+		return nil, ErrCiphertextTooShort
+	}
+
+	aead, err := newAEAD(CipherID(envelope[1]), key)
+	if err != nil {
+		return nil, err
+	}
+	kid, err := keyID(key)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(envelope[2:envelopeHeaderSize], kid) != 1 {
+		return nil, ErrWrongKey
+	}
+
+	body := envelope[envelopeHeaderSize:]
+	if len(body) < aead.NonceSize() {
+		return nil, ErrCiphertextTooShort
+	}
+
+	nonce, ciphertext := body[:aead.NonceSize()], body[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, envelopeAAD(envelope[:envelopeHeaderSize], aad))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBindingMismatch, err)
+	}
+	return plaintext, nil
+}
+
+// newAEAD создаёт шифр AEAD алгоритма cipherID для ключа key.
+func newAEAD(cipherID CipherID, key []byte) (cipher.AEAD, error) {
+	switch cipherID {
+	case CipherAES256GCM:
+		if len(key) != keySize {
+			return nil, fmt.Errorf("invalid AES-256 key size %d", len(key))
+		}
+		return newGCM(key)
+	case CipherXChaCha20Poly1305:
+		return chacha20poly1305.NewX(key)
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedCipher, cipherID)
+	}
+}
+
+// keyID выводит из ключа его идентификатор для заголовка конверта. Идентификатор не раскрывает ключ,
+// но позволяет отличить расшифровку другим ключом от подмены шифротекста.
+func keyID(key []byte) ([]byte, error) {
+	id := make([]byte, keyIDSize)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, key, []byte(keyIDInfo)), id); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+// envelopeAAD формирует проверяемые данные конверта из его заголовка и данных привязки aad.
+func envelopeAAD(header, aad []byte) []byte {
+	result := make([]byte, 0, len(header)+len(aad))
+	result = append(result, header...)
+	return append(result, aad...)
+}
+
+// SearchTokens возвращает токены слепого индекса слов текста text для поиска на сервере без раскрытия слов.
+// Слова приводятся к нижнему регистру и выделяются по буквам и цифрам; токен слова - усечённый HMAC-SHA256
+// на ключе, выведенном из ключа шифрования key через HKDF. Одинаковые слова дают одинаковые токены,
+// поэтому токены уникальны и отсортированы, чтобы не раскрывать порядок и повторы слов.
+func SearchTokens(text string, key []byte) ([]string, error) {
+	indexKey, err := expandKey(key, searchIndexInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	tokens := make([]string, 0, len(words))
+	for _, word := range words {
+		mac := hmac.New(sha256.New, indexKey)
+		mac.Write([]byte(word))
+		tokens = append(tokens, hex.EncodeToString(mac.Sum(nil)[:searchTokenSize]))
+	}
+
+	slices.Sort(tokens)
+	return slices.Compact(tokens), nil
+}
+
+// NewBlobID генерирует случайный идентификатор бинарного объекта в шестнадцатеричном виде.
+func NewBlobID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// NewBlobKey генерирует случайный ключ шифрования фрагментов бинарного объекта.
+// Ключ хранится в зашифрованных данных секрета, поэтому смена мастер-пароля
+// не требует перешифрования самого объекта.
+func NewBlobKey() ([]byte, error) {
+	return NewKey()
+}
+
+// NewKey генерирует случайный ключ шифрования: ключ хранилища или ключ данных секрета.
+func NewKey() ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// WrapKey шифрует ключ key ключом wrappingKey в формате конверта Seal, привязывая его к данным aad.
+func WrapKey(key, wrappingKey, aad []byte) ([]byte, error) {
+	return Seal(key, wrappingKey, aad)
+}
+
+// UnwrapKey расшифровывает ключ, зашифрованный WrapKey. Ключ в прежних форматах шифротекста не принимается.
+func UnwrapKey(wrapped, wrappingKey, aad []byte) ([]byte, error) {
+	if len(wrapped) == 0 || wrapped[0] != byte(FormatEnvelope) {
+		return nil, ErrInvalidWrappedKey
+	}
+
+	key, _, err := Open(wrapped, wrappingKey, aad)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != keySize {
+		return nil, ErrInvalidWrappedKey
+	}
+
+	return key, nil
+}
+
+// NewKeyPair генерирует пару ключей X25519, через которую пользователю передают секреты.
+func NewKeyPair() (publicKey, privateKey []byte, err error) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key.PublicKey().Bytes(), key.Bytes(), nil
+}
+
+// WrapKeyToPublic шифрует ключ key для владельца открытого ключа publicKey, привязывая его к данным aad.
+// Для каждого вызова генерируется одноразовая пара ключей X25519; из общего секрета с publicKey через
+// HKDF-SHA256 выводится ключ, которым key шифруется WrapKey. Результат - открытый ключ одноразовой пары,
+// за которым следует конверт.
+func WrapKeyToPublic(key, publicKey, aad []byte) ([]byte, error) {
+	recipient, err := ecdh.X25519().NewPublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	wrappingKey, err := shareKey(ephemeral, recipient, ephemeral.PublicKey().Bytes(), publicKey)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := WrapKey(key, wrappingKey, aad)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ephemeral.PublicKey().Bytes(), wrapped...), nil
+}
+
+// UnwrapKeyWithPrivate расшифровывает ключ, зашифрованный WrapKeyToPublic для открытого ключа пары privateKey.
+func UnwrapKeyWithPrivate(wrapped, privateKey, aad []byte) ([]byte, error) {
+	private, err := ecdh.X25519().NewPrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	if len(wrapped) <= keySize {
+		return nil, ErrInvalidWrappedKey
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(wrapped[:keySize])
+	if err != nil {
+		return nil, ErrInvalidWrappedKey
+	}
+
+	wrappingKey, err := shareKey(private, ephemeral, wrapped[:keySize], private.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return UnwrapKey(wrapped[keySize:], wrappingKey, aad)
+}
+
+// WrapKeyFrom шифрует ключ key для владельца открытого ключа recipientPublic от имени владельца закрытого
+// ключа senderPrivate, привязывая его к данным aad. В отличие от WrapKeyToPublic ключ шифрования выводится
+// из двух общих секретов X25519: одноразовой пары с получателем и пары отправителя с получателем. Поэтому
+// получатель, расшифровывающий ключ открытым ключом отправителя, убеждается, что его зашифровал владелец
+// senderPrivate, а не сервер. Результат - открытый ключ одноразовой пары, за которым следует конверт.
+func WrapKeyFrom(key, senderPrivate, recipientPublic, aad []byte) ([]byte, error) {
+	sender, err := ecdh.X25519().NewPrivateKey(senderPrivate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	recipient, err := ecdh.X25519().NewPublicKey(recipientPublic)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	ephemeralSecret, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return nil, err
+	}
+	staticSecret, err := sender.ECDH(recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	wrappingKey, err := senderShareKey(ephemeralSecret, staticSecret, ephemeral.PublicKey().Bytes(), recipientPublic, sender.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := WrapKey(key, wrappingKey, aad)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ephemeral.PublicKey().Bytes(), wrapped...), nil
+}
+
+// UnwrapKeyFrom расшифровывает ключ, зашифрованный WrapKeyFrom для открытого ключа пары privateKey
+// отправителем с открытым ключом senderPublic. Ключ, зашифрованный другим отправителем или WrapKeyToPublic,
+// не расшифровывается.
+func UnwrapKeyFrom(wrapped, privateKey, senderPublic, aad []byte) ([]byte, error) {
+	private, err := ecdh.X25519().NewPrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	sender, err := ecdh.X25519().NewPublicKey(senderPublic)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender public key: %w", err)
+	}
+	if len(wrapped) <= keySize {
+		return nil, ErrInvalidWrappedKey
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(wrapped[:keySize])
+	if err != nil {
+		return nil, ErrInvalidWrappedKey
+	}
+
+	ephemeralSecret, err := private.ECDH(ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	staticSecret, err := private.ECDH(sender)
+	if err != nil {
+		return nil, err
+	}
+
+	wrappingKey, err := senderShareKey(ephemeralSecret, staticSecret, wrapped[:keySize], private.PublicKey().Bytes(), senderPublic)
+	if err != nil {
+		return nil, err
+	}
+	return UnwrapKey(wrapped[keySize:], wrappingKey, aad)
+}
+
+// PublicKey возвращает открытый ключ пары X25519 с закрытым ключом privateKey.
+func PublicKey(privateKey []byte) ([]byte, error) {
+	private, err := ecdh.X25519().NewPrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	return private.PublicKey().Bytes(), nil
+}
+
+// Fingerprint возвращает отпечаток открытого ключа publicKey: начало его хэша SHA-256 в шестнадцатеричном
+// виде группами по четыре символа. Пользователи сверяют отпечатки по независимому от сервера каналу,
+// чтобы убедиться, что сервер не подменил открытый ключ.
+func Fingerprint(publicKey []byte) string {
+	sum := sha256.Sum256(publicKey)
+	encoded := hex.EncodeToString(sum[:fingerprintSize])
+
+	groups := make([]string, 0, len(encoded)/4)
+	for i := 0; i < len(encoded); i += 4 {
+		groups = append(groups, encoded[i:i+4])
+	}
+	return strings.Join(groups, " ")
+}
+
+// VerifyFingerprint проверяет, что отпечаток открытого ключа publicKey совпадает с fingerprint.
+// Регистр символов, пробелы и двоеточия в fingerprint не учитываются.
+// Возвращает ErrFingerprintMismatch, если отпечатки не совпадают.
+func VerifyFingerprint(publicKey []byte, fingerprint string) error {
+	normalize := strings.NewReplacer(" ", "", ":", "", "-", "")
+	want := strings.ToLower(normalize.Replace(fingerprint))
+	got := normalize.Replace(Fingerprint(publicKey))
+	if subtle.ConstantTimeCompare([]byte(want), []byte(got)) != 1 {
+		return ErrFingerprintMismatch
+	}
+	return nil
+}
+
+// shareKey выводит ключ шифрования ключа данных из общего секрета X25519 ключей private и peer.
+// Открытые ключи одноразовой пары и получателя входят в соль HKDF, чтобы ключ зависел от обоих участников.
+func shareKey(private *ecdh.PrivateKey, peer *ecdh.PublicKey, ephemeralPublic, recipientPublic []byte) ([]byte, error) {
+	secret, err := private.ECDH(peer)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := append(slices.Clone(ephemeralPublic), recipientPublic...)
+	key := make([]byte, keySize)
+	if _, err = io.ReadFull(hkdf.New(sha256.New, secret, salt, []byte(shareInfo)), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// senderShareKey выводит ключ шифрования ключа данных из общих секретов одноразовой пары и пары отправителя
+// с получателем. Открытые ключи одноразовой пары, получателя и отправителя входят в соль HKDF.
+func senderShareKey(ephemeralSecret, staticSecret, ephemeralPublic, recipientPublic, senderPublic []byte) ([]byte, error) {
+	secret := append(slices.Clone(ephemeralSecret), staticSecret...)
+	salt := append(append(slices.Clone(ephemeralPublic), recipientPublic...), senderPublic...)
+
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, []byte(senderShareInfo)), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// EncryptChunk шифрует фрагмент бинарного объекта с помощью AES-GCM.
+// Идентификатор объекта, индекс фрагмента и их общее количество передаются как дополнительные
+// аутентифицируемые данные: фрагмент нельзя переставить, подменить фрагментом другого объекта
+// или отбросить хвост объекта без ошибки при расшифровке.
+func EncryptChunk(key []byte, blobID string, index, count uint32, plaintext []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, chunkAAD(blobID, index, count)), nil
+}
+
+// DecryptChunk расшифровывает фрагмент, зашифрованный с помощью EncryptChunk,
+// проверяя его принадлежность объекту и позицию в нём.
+func DecryptChunk(key []byte, blobID string, index, count uint32, data []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < aead.NonceSize() {
+		return nil, ErrCiphertextTooShort
+	}
+
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, chunkAAD(blobID, index, count))
+}
+
+// newGCM создаёт шифр AES-GCM для ключа key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkAAD формирует дополнительные аутентифицируемые данные фрагмента.
+func chunkAAD(blobID string, index, count uint32) []byte {
+	aad := make([]byte, 0, len(blobID)+8)
+	aad = append(aad, blobID...)
+	aad = binary.BigEndian.AppendUint32(aad, index)
+	return binary.BigEndian.AppendUint32(aad, count)
+}
@@ -1,7 +1,12 @@
 package crypto
 
 import (
+	"beliaev-aa/GophKeeper/pkg/models"
 	"bytes"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -52,6 +57,119 @@ func TestDeriveKey(t *testing.T) {
 	}
 }
 
+func TestDeriveKeys(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+
+	type testCase struct {
+		name     string
+		password string
+		params   *models.KDFParams
+		wantErr  bool
+	}
+
+	testCases := []testCase{
+		{
+			name:     "scrypt",
+			password: "secretPassword",
+			params:   &models.KDFParams{Algorithm: models.KDFScrypt, Salt: salt, ScryptN: 1 << 15, ScryptR: 8, ScryptP: 1},
+			wantErr:  false,
+		},
+		{
+			name:     "argon2id",
+			password: "secretPassword",
+			params:   &models.KDFParams{Algorithm: models.KDFArgon2id, Salt: salt, Argon2Memory: 19 * 1024, Argon2Time: 1, Argon2Threads: 1},
+			wantErr:  false,
+		},
+		{
+			name:     "legacy_login_salt",
+			password: "secretPassword",
+			params:   models.LegacyKDFParams("user"),
+			wantErr:  false,
+		},
+		{
+			name:     "empty_password",
+			password: "",
+			params:   models.LegacyKDFParams("user"),
+			wantErr:  true,
+		},
+		{
+			name:     "nil_params",
+			password: "secretPassword",
+			params:   nil,
+			wantErr:  true,
+		},
+		{
+			name:     "unknown_algorithm",
+			password: "secretPassword",
+			params:   &models.KDFParams{Algorithm: "pbkdf2", Salt: salt},
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			keys, err := DeriveKeys(tc.password, tc.params)
+
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("DeriveKeys() error = %v, wantErr = %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(keys.AuthHash) != 2*keySize {
+				t.Errorf("DeriveKeys() auth hash length = %d, want %d", len(keys.AuthHash), 2*keySize)
+			}
+			if len(keys.EncryptionKey) != keySize {
+				t.Errorf("DeriveKeys() encryption key length = %d, want %d", len(keys.EncryptionKey), keySize)
+			}
+			if strings.Contains(keys.AuthHash, hex.EncodeToString(keys.EncryptionKey)) {
+				t.Error("DeriveKeys() auth hash must not reveal encryption key")
+			}
+		})
+	}
+}
+
+func TestDeriveKeys_Deterministic(t *testing.T) {
+	params := &models.KDFParams{Algorithm: models.KDFArgon2id, Salt: []byte("0123456789abcdef"), Argon2Memory: 19 * 1024, Argon2Time: 1, Argon2Threads: 1}
+	otherParams := *params
+	otherParams.Salt = []byte("fedcba9876543210")
+
+	first, err := DeriveKeys("secretPassword", params)
+	if err != nil {
+		t.Fatalf("DeriveKeys() error = %v", err)
+	}
+	second, err := DeriveKeys("secretPassword", params)
+	if err != nil {
+		t.Fatalf("DeriveKeys() error = %v", err)
+	}
+	other, err := DeriveKeys("secretPassword", &otherParams)
+	if err != nil {
+		t.Fatalf("DeriveKeys() error = %v", err)
+	}
+
+	if first.AuthHash != second.AuthHash || !bytes.Equal(first.EncryptionKey, second.EncryptionKey) {
+		t.Error("DeriveKeys() must be deterministic for the same params")
+	}
+	if first.AuthHash == other.AuthHash {
+		t.Error("DeriveKeys() must depend on salt")
+	}
+}
+
+func TestDeriveKeys_LegacyLoginNormalization(t *testing.T) {
+	first, err := DeriveKeys("secretPassword", models.LegacyKDFParams("User"))
+	if err != nil {
+		t.Fatalf("DeriveKeys() error = %v", err)
+	}
+	second, err := DeriveKeys("secretPassword", models.LegacyKDFParams(" user "))
+	if err != nil {
+		t.Fatalf("DeriveKeys() error = %v", err)
+	}
+
+	if first.AuthHash != second.AuthHash {
+		t.Error("DeriveKeys() must normalize login in legacy params")
+	}
+}
+
 func TestEncrypt(t *testing.T) {
 	type testCase struct {
 		name      string
@@ -163,3 +281,556 @@ func TestDecrypt(t *testing.T) {
 		})
 	}
 }
+
+func TestNewBlobID(t *testing.T) {
+	first, err := NewBlobID()
+	if err != nil {
+		t.Fatalf("NewBlobID() error = %v", err)
+	}
+	second, err := NewBlobID()
+	if err != nil {
+		t.Fatalf("NewBlobID() error = %v", err)
+	}
+
+	if len(first) != 32 {
+		t.Errorf("NewBlobID() length = %d, want 32", len(first))
+	}
+	if _, err = hex.DecodeString(first); err != nil {
+		t.Errorf("NewBlobID() is not hex: %v", err)
+	}
+	if first == second {
+		t.Errorf("NewBlobID() returned the same id twice")
+	}
+}
+
+func TestEncryptChunk(t *testing.T) {
+	key, err := NewBlobKey()
+	if err != nil {
+		t.Fatalf("NewBlobKey() error = %v", err)
+	}
+	otherKey, _ := NewBlobKey()
+
+	plaintext := []byte("chunk of file data")
+	encrypted, err := EncryptChunk(key, "blob", 1, 3, plaintext)
+	if err != nil {
+		t.Fatalf("EncryptChunk() error = %v", err)
+	}
+
+	type testCase struct {
+		name    string
+		key     []byte
+		blobID  string
+		index   uint32
+		count   uint32
+		data    []byte
+		wantErr bool
+	}
+
+	testCases := []testCase{
+		{name: "valid_chunk", key: key, blobID: "blob", index: 1, count: 3, data: encrypted},
+		{name: "wrong_key", key: otherKey, blobID: "blob", index: 1, count: 3, data: encrypted, wantErr: true},
+		{name: "other_blob", key: key, blobID: "other", index: 1, count: 3, data: encrypted, wantErr: true},
+		{name: "reordered_chunk", key: key, blobID: "blob", index: 0, count: 3, data: encrypted, wantErr: true},
+		{name: "truncated_blob", key: key, blobID: "blob", index: 1, count: 2, data: encrypted, wantErr: true},
+		{name: "tampered_data", key: key, blobID: "blob", index: 1, count: 3, data: append(append([]byte{}, encrypted[:len(encrypted)-1]...), encrypted[len(encrypted)-1]^1), wantErr: true},
+		{name: "too_short", key: key, blobID: "blob", index: 1, count: 3, data: encrypted[:5], wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			decrypted, err := DecryptChunk(tc.key, tc.blobID, tc.index, tc.count, tc.data)
+
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("DecryptChunk() error = %v, wantErr = %v", err, tc.wantErr)
+			}
+			if err == nil && !bytes.Equal(decrypted, plaintext) {
+				t.Errorf("DecryptChunk() = %q, want %q", decrypted, plaintext)
+			}
+		})
+	}
+}
+
+func TestDecryptBound(t *testing.T) {
+	key := make([]byte, 32)
+	otherKey := bytes.Repeat([]byte{1}, 32)
+	aad := SecretAAD("User", 7, string(models.CredSecret), "payload")
+
+	encrypted, err := EncryptBound("my secret", key, aad)
+	if err != nil {
+		t.Fatalf("EncryptBound() error = %v", err)
+	}
+	if !strings.HasPrefix(encrypted, boundPrefix) {
+		t.Fatalf("EncryptBound() = %q, want version prefix %q", encrypted, boundPrefix)
+	}
+	legacy, err := Encrypt("my secret", key)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	type testCase struct {
+		name      string
+		key       []byte
+		aad       []byte
+		encrypted string
+		wantBound bool
+		wantErr   error
+	}
+
+	testCases := []testCase{
+		{name: "bound", key: key, aad: aad, encrypted: encrypted, wantBound: true},
+		{name: "normalized_owner", key: key, aad: SecretAAD(" user ", 7, string(models.CredSecret), "payload"), encrypted: encrypted, wantBound: true},
+		{name: "legacy", key: key, aad: aad, encrypted: legacy},
+		{name: "other_owner", key: key, aad: SecretAAD("other", 7, string(models.CredSecret), "payload"), encrypted: encrypted, wantBound: true, wantErr: ErrBindingMismatch},
+		{name: "other_secret", key: key, aad: SecretAAD("User", 8, string(models.CredSecret), "payload"), encrypted: encrypted, wantBound: true, wantErr: ErrBindingMismatch},
+		{name: "other_type", key: key, aad: SecretAAD("User", 7, string(models.TextSecret), "payload"), encrypted: encrypted, wantBound: true, wantErr: ErrBindingMismatch},
+		{name: "other_field", key: key, aad: SecretAAD("User", 7, string(models.CredSecret), "title"), encrypted: encrypted, wantBound: true, wantErr: ErrBindingMismatch},
+		{name: "wrong_key", key: otherKey, aad: aad, encrypted: encrypted, wantBound: true, wantErr: ErrBindingMismatch},
+		{name: "too_short", key: key, aad: aad, encrypted: boundPrefix + "00", wantBound: true, wantErr: ErrCiphertextTooShort},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			decrypted, bound, err := DecryptBound(tc.encrypted, tc.key, tc.aad)
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("DecryptBound() error = %v, want %v", err, tc.wantErr)
+			}
+			if bound != tc.wantBound {
+				t.Errorf("DecryptBound() bound = %v, want %v", bound, tc.wantBound)
+			}
+			if err == nil && decrypted != "my secret" {
+				t.Errorf("DecryptBound() = %q, want %q", decrypted, "my secret")
+			}
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	key := make([]byte, keySize)
+	otherKey := bytes.Repeat([]byte{1}, keySize)
+	aad := SecretAAD("User", 7, string(models.CredSecret), "payload")
+
+	sealed, err := Seal([]byte("my secret"), key, aad)
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	if sealed[0] != byte(FormatEnvelope) || CipherID(sealed[1]) != DefaultCipher {
+		t.Fatalf("Seal() header = %x, want format %d and cipher %d", sealed[:2], FormatEnvelope, DefaultCipher)
+	}
+	xchacha, err := SealWith(CipherXChaCha20Poly1305, []byte("my secret"), key, aad)
+	if err != nil {
+		t.Fatalf("SealWith() error = %v", err)
+	}
+	bound, err := EncryptBound("my secret", key, aad)
+	if err != nil {
+		t.Fatalf("EncryptBound() error = %v", err)
+	}
+	legacy, err := Encrypt("my secret", key)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	tampered := slices.Clone(sealed)
+	tampered[len(tampered)-1] ^= 1
+	swapped := slices.Clone(xchacha)
+	swapped[1] = byte(CipherAES256GCM)
+	unknown := slices.Clone(sealed)
+	unknown[1] = 9
+
+	type testCase struct {
+		name       string
+		key        []byte
+		aad        []byte
+		data       []byte
+		wantFormat Format
+		wantErr    error
+	}
+
+	testCases := []testCase{
+		{name: "aes_gcm", key: key, aad: aad, data: sealed, wantFormat: FormatEnvelope},
+		{name: "xchacha20_poly1305", key: key, aad: aad, data: xchacha, wantFormat: FormatEnvelope},
+		{name: "bound_hex", key: key, aad: aad, data: []byte(bound), wantFormat: FormatBoundHex},
+		{name: "legacy_hex", key: key, aad: aad, data: []byte(legacy), wantFormat: FormatLegacy},
+		{name: "other_secret", key: key, aad: SecretAAD("User", 8, string(models.CredSecret), "payload"), data: sealed, wantFormat: FormatEnvelope, wantErr: ErrBindingMismatch},
+		{name: "wrong_key", key: otherKey, aad: aad, data: sealed, wantFormat: FormatEnvelope, wantErr: ErrWrongKey},
+		{name: "tampered", key: key, aad: aad, data: tampered, wantFormat: FormatEnvelope, wantErr: ErrBindingMismatch},
+		{name: "swapped_cipher", key: key, aad: aad, data: swapped, wantFormat: FormatEnvelope, wantErr: ErrBindingMismatch},
+		{name: "unknown_cipher", key: key, aad: aad, data: unknown, wantFormat: FormatEnvelope, wantErr: ErrUnsupportedCipher},
+		{name: "too_short", key: key, aad: aad, data: sealed[:envelopeHeaderSize-1], wantFormat: FormatEnvelope, wantErr: ErrCiphertextTooShort},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			decrypted, format, err := Open(tc.data, tc.key, tc.aad)
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Open() error = %v, want %v", err, tc.wantErr)
+			}
+			if format != tc.wantFormat {
+				t.Errorf("Open() format = %d, want %d", format, tc.wantFormat)
+			}
+			if err == nil && string(decrypted) != "my secret" {
+				t.Errorf("Open() = %q, want %q", decrypted, "my secret")
+			}
+		})
+	}
+}
+
+func TestOpenString(t *testing.T) {
+	key := make([]byte, keySize)
+	aad := SecretAAD("User", 7, string(models.CredSecret), "title")
+
+	sealed, err := SealString("Bank card", key, aad)
+	if err != nil {
+		t.Fatalf("SealString() error = %v", err)
+	}
+	if _, err = hex.DecodeString(sealed); err == nil || strings.HasPrefix(sealed, boundPrefix) {
+		t.Fatalf("SealString() = %q, want text distinguishable from previous formats", sealed)
+	}
+	bound, err := EncryptBound("Bank card", key, aad)
+	if err != nil {
+		t.Fatalf("EncryptBound() error = %v", err)
+	}
+	legacy, err := Encrypt("Bank card", key)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	for encrypted, wantFormat := range map[string]Format{sealed: FormatEnvelope, bound: FormatBoundHex, legacy: FormatLegacy} {
+		decrypted, format, err := OpenString(encrypted, key, aad)
+		if err != nil {
+			t.Fatalf("OpenString(%q) error = %v", encrypted, err)
+		}
+		if decrypted != "Bank card" || format != wantFormat {
+			t.Errorf("OpenString(%q) = %q, %d, want %q, %d", encrypted, decrypted, format, "Bank card", wantFormat)
+		}
+	}
+}
+
+func TestOpenSealed(t *testing.T) {
+	key := make([]byte, keySize)
+	aad := SecretAAD("User", 7, string(models.CredSecret), "payload")
+
+	sealed, err := Seal([]byte("my secret"), key, aad)
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	sealedString, err := SealString("my secret", key, aad)
+	if err != nil {
+		t.Fatalf("SealString() error = %v", err)
+	}
+	bound, err := EncryptBound("my secret", key, aad)
+	if err != nil {
+		t.Fatalf("EncryptBound() error = %v", err)
+	}
+	legacy, err := Encrypt("my secret", key)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	testCases := []struct {
+		name    string
+		aad     []byte
+		data    string
+		wantErr error
+	}{
+		{name: "sealed", aad: aad, data: sealedString},
+		{name: "other_secret", aad: SecretAAD("User", 8, string(models.CredSecret), "payload"), data: sealedString, wantErr: ErrBindingMismatch},
+		{name: "bound_hex", aad: aad, data: bound, wantErr: ErrNotSealed},
+		{name: "legacy_hex", aad: aad, data: legacy, wantErr: ErrNotSealed},
+		{name: "empty", aad: aad, data: "", wantErr: ErrNotSealed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			decrypted, err := OpenSealedString(tc.data, key, tc.aad)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("OpenSealedString() error = %v, want %v", err, tc.wantErr)
+			}
+			if err == nil && decrypted != "my secret" {
+				t.Errorf("OpenSealedString() = %q, want %q", decrypted, "my secret")
+			}
+		})
+	}
+
+	if decrypted, err := OpenSealed(sealed, key, aad); err != nil || string(decrypted) != "my secret" {
+		t.Errorf("OpenSealed() = %q, %v, want %q", decrypted, err, "my secret")
+	}
+	for _, data := range []string{bound, legacy} {
+		if _, err := OpenSealed([]byte(data), key, aad); !errors.Is(err, ErrNotSealed) {
+			t.Errorf("OpenSealed(%q) error = %v, want %v", data, err, ErrNotSealed)
+		}
+	}
+}
+
+func TestUnwrapKey(t *testing.T) {
+	vaultKey, err := NewKey()
+	if err != nil {
+		t.Fatalf("NewKey() error = %v", err)
+	}
+	dataKey, err := NewKey()
+	if err != nil {
+		t.Fatalf("NewKey() error = %v", err)
+	}
+	aad := VaultKeyAAD("User")
+
+	wrapped, err := WrapKey(dataKey, vaultKey, aad)
+	if err != nil {
+		t.Fatalf("WrapKey() error = %v", err)
+	}
+	short, err := WrapKey(dataKey[:16], vaultKey, aad)
+	if err != nil {
+		t.Fatalf("WrapKey() error = %v", err)
+	}
+	legacy, err := Encrypt(string(dataKey), vaultKey)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	type testCase struct {
+		name    string
+		wrapped []byte
+		key     []byte
+		aad     []byte
+		wantErr error
+	}
+
+	testCases := []testCase{
+		{name: "success", wrapped: wrapped, key: vaultKey, aad: aad},
+		{name: "wrong_key", wrapped: wrapped, key: dataKey, aad: aad, wantErr: ErrWrongKey},
+		{name: "other_owner", wrapped: wrapped, key: vaultKey, aad: VaultKeyAAD("other"), wantErr: ErrBindingMismatch},
+		{name: "short_key", wrapped: short, key: vaultKey, aad: aad, wantErr: ErrInvalidWrappedKey},
+		{name: "legacy_format", wrapped: []byte(legacy), key: vaultKey, aad: aad, wantErr: ErrInvalidWrappedKey},
+		{name: "empty", key: vaultKey, aad: aad, wantErr: ErrInvalidWrappedKey},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := UnwrapKey(tc.wrapped, tc.key, tc.aad)
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("UnwrapKey() error = %v, want %v", err, tc.wantErr)
+			}
+			if err == nil && !bytes.Equal(key, dataKey) {
+				t.Errorf("UnwrapKey() = %x, want %x", key, dataKey)
+			}
+		})
+	}
+}
+
+func TestUnwrapKeyWithPrivate(t *testing.T) {
+	publicKey, privateKey, err := NewKeyPair()
+	if err != nil {
+		t.Fatalf("NewKeyPair() error = %v", err)
+	}
+	_, otherPrivateKey, err := NewKeyPair()
+	if err != nil {
+		t.Fatalf("NewKeyPair() error = %v", err)
+	}
+	dataKey, err := NewKey()
+	if err != nil {
+		t.Fatalf("NewKey() error = %v", err)
+	}
+	aad := ShareAAD("alice", "Bob", 10, "credential")
+
+	wrapped, err := WrapKeyToPublic(dataKey, publicKey, aad)
+	if err != nil {
+		t.Fatalf("WrapKeyToPublic() error = %v", err)
+	}
+	if _, err = WrapKeyToPublic(dataKey, []byte("short"), aad); err == nil {
+		t.Errorf("WrapKeyToPublic() with invalid public key expected error")
+	}
+
+	type testCase struct {
+		name       string
+		wrapped    []byte
+		privateKey []byte
+		aad        []byte
+		wantErr    error
+	}
+
+	testCases := []testCase{
+		{name: "success", wrapped: wrapped, privateKey: privateKey, aad: ShareAAD("Alice", " bob", 10, "credential")},
+		{name: "other_recipient", wrapped: wrapped, privateKey: otherPrivateKey, aad: aad, wantErr: ErrWrongKey},
+		{name: "other_secret", wrapped: wrapped, privateKey: privateKey, aad: ShareAAD("alice", "bob", 11, "credential"), wantErr: ErrBindingMismatch},
+		{name: "other_recipient_binding", wrapped: wrapped, privateKey: privateKey, aad: ShareAAD("alice", "carol", 10, "credential"), wantErr: ErrBindingMismatch},
+		{name: "organization_key_binding", wrapped: wrapped, privateKey: privateKey, aad: OrganizationKeyAAD("alice", "bob"), wantErr: ErrBindingMismatch},
+		{name: "emergency_key_binding", wrapped: wrapped, privateKey: privateKey, aad: EmergencyKeyAAD("alice", "bob"), wantErr: ErrBindingMismatch},
+		{name: "truncated", wrapped: wrapped[:keySize], privateKey: privateKey, aad: aad, wantErr: ErrInvalidWrappedKey},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := UnwrapKeyWithPrivate(tc.wrapped, tc.privateKey, tc.aad)
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("UnwrapKeyWithPrivate() error = %v, want %v", err, tc.wantErr)
+			}
+			if err == nil && !bytes.Equal(key, dataKey) {
+				t.Errorf("UnwrapKeyWithPrivate() = %x, want %x", key, dataKey)
+			}
+		})
+	}
+}
+
+func TestUnwrapKeyFrom(t *testing.T) {
+	recipientPublic, recipientPrivate, err := NewKeyPair()
+	if err != nil {
+		t.Fatalf("NewKeyPair() error = %v", err)
+	}
+	senderPublic, senderPrivate, err := NewKeyPair()
+	if err != nil {
+		t.Fatalf("NewKeyPair() error = %v", err)
+	}
+	serverPublic, serverPrivate, err := NewKeyPair()
+	if err != nil {
+		t.Fatalf("NewKeyPair() error = %v", err)
+	}
+	dataKey, err := NewKey()
+	if err != nil {
+		t.Fatalf("NewKey() error = %v", err)
+	}
+	aad := ShareAAD("alice", "bob", 10, "credential")
+
+	wrapped, err := WrapKeyFrom(dataKey, senderPrivate, recipientPublic, aad)
+	if err != nil {
+		t.Fatalf("WrapKeyFrom() error = %v", err)
+	}
+	forged, err := WrapKeyFrom(dataKey, serverPrivate, recipientPublic, aad)
+	if err != nil {
+		t.Fatalf("WrapKeyFrom() error = %v", err)
+	}
+	anonymous, err := WrapKeyToPublic(dataKey, recipientPublic, aad)
+	if err != nil {
+		t.Fatalf("WrapKeyToPublic() error = %v", err)
+	}
+	if _, err = WrapKeyFrom(dataKey, senderPrivate, []byte("short"), aad); err == nil {
+		t.Errorf("WrapKeyFrom() with invalid public key expected error")
+	}
+
+	type testCase struct {
+		name         string
+		wrapped      []byte
+		senderPublic []byte
+		aad          []byte
+		wantErr      error
+	}
+
+	testCases := []testCase{
+		{name: "success", wrapped: wrapped, senderPublic: senderPublic, aad: aad},
+		{name: "forged_by_other_key", wrapped: forged, senderPublic: senderPublic, aad: aad, wantErr: ErrWrongKey},
+		{name: "substituted_sender_key", wrapped: wrapped, senderPublic: serverPublic, aad: aad, wantErr: ErrWrongKey},
+		{name: "anonymous_wrap", wrapped: anonymous, senderPublic: senderPublic, aad: aad, wantErr: ErrWrongKey},
+		{name: "other_secret", wrapped: wrapped, senderPublic: senderPublic, aad: ShareAAD("alice", "bob", 11, "credential"), wantErr: ErrBindingMismatch},
+		{name: "truncated", wrapped: wrapped[:keySize], senderPublic: senderPublic, aad: aad, wantErr: ErrInvalidWrappedKey},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := UnwrapKeyFrom(tc.wrapped, recipientPrivate, tc.senderPublic, tc.aad)
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("UnwrapKeyFrom() error = %v, want %v", err, tc.wantErr)
+			}
+			if err == nil && !bytes.Equal(key, dataKey) {
+				t.Errorf("UnwrapKeyFrom() = %x, want %x", key, dataKey)
+			}
+		})
+	}
+}
+
+func TestVerifyFingerprint(t *testing.T) {
+	publicKey, privateKey, err := NewKeyPair()
+	if err != nil {
+		t.Fatalf("NewKeyPair() error = %v", err)
+	}
+	otherPublicKey, _, err := NewKeyPair()
+	if err != nil {
+		t.Fatalf("NewKeyPair() error = %v", err)
+	}
+
+	derived, err := PublicKey(privateKey)
+	if err != nil {
+		t.Fatalf("PublicKey() error = %v", err)
+	}
+	if !bytes.Equal(derived, publicKey) {
+		t.Errorf("PublicKey() = %x, want %x", derived, publicKey)
+	}
+
+	fingerprint := Fingerprint(publicKey)
+	if len(fingerprint) != fingerprintSize*2+fingerprintSize/2-1 {
+		t.Errorf("Fingerprint() = %q, unexpected length", fingerprint)
+	}
+
+	type testCase struct {
+		name        string
+		fingerprint string
+		wantErr     error
+	}
+
+	testCases := []testCase{
+		{name: "exact", fingerprint: fingerprint},
+		{name: "upper_case_without_spaces", fingerprint: strings.ToUpper(strings.ReplaceAll(fingerprint, " ", ""))},
+		{name: "colon_separated", fingerprint: strings.ReplaceAll(fingerprint, " ", ":")},
+		{name: "other_key", fingerprint: Fingerprint(otherPublicKey), wantErr: ErrFingerprintMismatch},
+		{name: "truncated", fingerprint: fingerprint[:9], wantErr: ErrFingerprintMismatch},
+		{name: "empty", fingerprint: "", wantErr: ErrFingerprintMismatch},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := VerifyFingerprint(publicKey, tc.fingerprint); !errors.Is(err, tc.wantErr) {
+				t.Errorf("VerifyFingerprint() error = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestSearchTokens(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, keySize)
+	otherKey := bytes.Repeat([]byte{2}, keySize)
+
+	tokens, err := SearchTokens("Bank card, BANK of Moscow", key)
+	if err != nil {
+		t.Fatalf("SearchTokens() error = %v", err)
+	}
+	if len(tokens) != 4 {
+		t.Fatalf("SearchTokens() = %v, want 4 unique tokens", tokens)
+	}
+	for _, token := range tokens {
+		if len(token) != 2*searchTokenSize {
+			t.Errorf("SearchTokens() token %q length = %d, want %d", token, len(token), 2*searchTokenSize)
+		}
+	}
+
+	type testCase struct {
+		name      string
+		query     string
+		key       []byte
+		wantMatch bool
+	}
+
+	testCases := []testCase{
+		{name: "same_word_other_case", query: "bank", key: key, wantMatch: true},
+		{name: "several_words", query: "moscow card", key: key, wantMatch: true},
+		{name: "missing_word", query: "bank moscow visa", key: key, wantMatch: false},
+		{name: "other_key", query: "bank", key: otherKey, wantMatch: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			queryTokens, err := SearchTokens(tc.query, tc.key)
+			if err != nil {
+				t.Fatalf("SearchTokens() error = %v", err)
+			}
+
+			match := true
+			for _, token := range queryTokens {
+				match = match && slices.Contains(tokens, token)
+			}
+			if match != tc.wantMatch {
+				t.Errorf("SearchTokens(%q) match = %v, want %v", tc.query, match, tc.wantMatch)
+			}
+		})
+	}
+}
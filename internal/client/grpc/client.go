@@ -5,71 +5,198 @@ package grpc
 import (
 	"beliaev-aa/GophKeeper/certs"
 	"beliaev-aa/GophKeeper/internal/client/config"
+	"beliaev-aa/GophKeeper/internal/client/crypto"
 	"beliaev-aa/GophKeeper/internal/client/grpc/interceptors"
+	"beliaev-aa/GophKeeper/pkg/consts"
 	"beliaev-aa/GophKeeper/pkg/converter"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
 	"beliaev-aa/GophKeeper/pkg/models"
 	"beliaev-aa/GophKeeper/pkg/proto"
 	"context"
+	"crypto/subtle"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
 	"fmt"
 	"github.com/charmbracelet/bubbletea"
 	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"io"
 	"math"
-	"math/rand/v2"
+	"strconv"
 	"sync"
 	"time"
 )
 
+var (
+	// ErrNoRefreshToken возникает при попытке обновить токен доступа до входа в систему.
+	ErrNoRefreshToken = errors.New("no refresh token")
+	// ErrTOTPRequired возвращается из Login, если для завершения входа нужен код второго фактора.
+	// Вход завершается вызовом VerifyTOTP.
+	ErrTOTPRequired = errors.New("authentication code required")
+	// ErrLegacyMigrationRequired возвращается из Login, если учётная запись ещё хранит мастер-пароль по старой
+	// схеме. Мастер-пароль передаётся серверу для миграции только вызовом MigrateLegacyLogin с согласия пользователя.
+	ErrLegacyMigrationRequired = errors.New("account uses the legacy password scheme and requires migration")
+	// ErrNoPendingLogin возникает при вызове VerifyTOTP без предшествующего Login.
+	ErrNoPendingLogin = errors.New("no login awaiting authentication code")
+	// ErrWrongPassword возвращается из ChangePassword, если текущий мастер-пароль указан неверно.
+	ErrWrongPassword = errors.New("current password is incorrect")
+	// ErrNotLoggedIn возникает при вызове операций с учётной записью до входа в систему.
+	ErrNotLoggedIn = errors.New("not logged in")
+	// ErrVaultKeyExists возвращается из CreateVaultKey, если ключ хранилища уже создан с другого устройства.
+	ErrVaultKeyExists = errors.New("vault key already exists")
+	// ErrKeyPairExists возвращается из CreateKeyPair, если пара ключей уже создана с другого устройства.
+	ErrKeyPairExists = errors.New("key pair already exists")
+)
+
 type ClientGRPCInterface interface {
 	Login(ctx context.Context, login, password string) (string, error)
+	MigrateLegacyLogin(ctx context.Context, login, password string) (string, error)
 	Register(ctx context.Context, login, password string) (string, error)
 	LoadSecrets(ctx context.Context) ([]*models.Secret, error)
+	SyncSecrets(ctx context.Context, sinceRevision uint64) (*models.SecretsDelta, error)
+	ListSecrets(ctx context.Context, query *models.SecretListQuery) (*models.SecretsPage, error)
 	LoadSecret(ctx context.Context, ID uint64) (*models.Secret, error)
+	ReserveSecretID(ctx context.Context) (uint64, error)
 	SaveSecret(ctx context.Context, secret *models.Secret) error
 	DeleteSecret(ctx context.Context, id uint64) error
+	LoadSecretVersions(ctx context.Context, id uint64) ([]*models.SecretVersion, error)
+	RestoreSecretVersion(ctx context.Context, id, versionID uint64) error
+	LoadTrash(ctx context.Context) ([]*models.Secret, error)
+	RestoreSecret(ctx context.Context, id uint64) error
+	PurgeSecret(ctx context.Context, id uint64) error
+	EncryptSecretLabels(ctx context.Context, secrets []*models.Secret) error
+	ListFolders(ctx context.Context) ([]*models.Folder, error)
+	CreateFolder(ctx context.Context, parentID uint64, name string) (*models.Folder, error)
+	UpdateFolder(ctx context.Context, id, parentID uint64, name string) error
+	DeleteFolder(ctx context.Context, id uint64) error
+	ListTags(ctx context.Context) ([]*models.Tag, error)
+	CreateTag(ctx context.Context, name string) (*models.Tag, error)
+	UpdateTag(ctx context.Context, id uint64, name string) error
+	DeleteTag(ctx context.Context, id uint64) error
+	GetBlobStatus(ctx context.Context, blobID string) (*models.BlobStatus, error)
+	UploadBlob(ctx context.Context, blobID string, chunkCount, from uint32, chunk func(index uint32) ([]byte, error)) (uint32, error)
+	DownloadBlob(ctx context.Context, blobID string, from uint32, handle func(index, count uint32, data []byte) error) error
 	SetToken(token string)
 	GetToken() string
 	SetPassword(password string)
 	GetPassword() string
+	GetEncryptionKey() []byte
+	GetLogin() string
+	KDFUpgradeRequired() bool
+	NewKDFParams(ctx context.Context) (*models.KDFParams, error)
+	UpgradeKDF(ctx context.Context, currentAuthHash string, params *models.KDFParams, keys *crypto.Keys, vaultKey []byte, secrets map[uint64]*models.Secret) error
+	GetKDFParams() *models.KDFParams
+	ChangePassword(ctx context.Context, currentAuthHash string, params *models.KDFParams, keys *crypto.Keys, vaultKey []byte, secrets map[uint64]*models.Secret) error
+	GetVaultKey(ctx context.Context) ([]byte, error)
+	CreateVaultKey(ctx context.Context, vaultKey []byte, secrets map[uint64]*models.Secret) error
+	GetKeyPair(ctx context.Context) (*models.KeyPair, error)
+	CreateKeyPair(ctx context.Context, keyPair *models.KeyPair) error
+	GetPublicKey(ctx context.Context, login string) ([]byte, error)
+	ShareSecret(ctx context.Context, share *models.Share) (*models.Share, error)
+	ListShares(ctx context.Context, secretID uint64) ([]*models.Share, error)
+	ListSharedWithMe(ctx context.Context) ([]*models.Secret, error)
+	RevokeShare(ctx context.Context, id uint64) error
+	UpdateSharedSecret(ctx context.Context, secret *models.Secret) (uint64, error)
+	CreateOrganization(ctx context.Context, name string, vaultKey []byte) (*models.Organization, error)
+	ListOrganizations(ctx context.Context) ([]*models.Organization, error)
+	ListOrgMembers(ctx context.Context, orgID uint64) ([]*models.OrgMember, error)
+	AddOrgMember(ctx context.Context, member *models.OrgMember) (*models.OrgMember, error)
+	UpdateOrgMemberRole(ctx context.Context, orgID, userID uint64, role models.OrgRole) error
+	RemoveOrgMember(ctx context.Context, orgID, userID uint64) error
+	DeleteOrganization(ctx context.Context, orgID uint64) error
+	AddEmergencyContact(ctx context.Context, access *models.EmergencyAccess) (*models.EmergencyAccess, error)
+	ListEmergencyContacts(ctx context.Context) ([]*models.EmergencyAccess, error)
+	ListEmergencyGrants(ctx context.Context) ([]*models.EmergencyAccess, error)
+	RequestEmergencyAccess(ctx context.Context, id uint64) error
+	RejectEmergencyAccess(ctx context.Context, id uint64) error
+	DeleteEmergencyAccess(ctx context.Context, id uint64) error
+	DeleteAccount(ctx context.Context, password string) error
+	ListSessions(ctx context.Context) ([]*models.DeviceSession, error)
+	RenameSession(ctx context.Context, id uint64, name string) error
+	RevokeSession(ctx context.Context, id uint64) error
+	LogoutAll(ctx context.Context) error
+	VerifyTOTP(ctx context.Context, code string) (string, error)
+	EnableTOTP(ctx context.Context) (*models.TOTPSetup, error)
+	ConfirmTOTP(ctx context.Context, code string) ([]string, error)
+	DisableTOTP(ctx context.Context, code string) error
 	Notifications(p *tea.Program, logger *zap.Logger)
 }
 
 type (
 	// ClientGRPC управляет соединением с gRPC сервером и реализует методы для работы с серверными ресурсами.
 	ClientGRPC struct {
-		config        *config.Config
-		UsersClient   proto.UsersClient
-		SecretsClient proto.SecretsClient
-		notifyClient  proto.NotificationClient
-		accessToken   string
-		password      string
-		clientID      uint64
-		previews      sync.Map
-	}
-	// ReloadSecretList метка для обработчика
-	ReloadSecretList struct{}
+		config              *config.Config
+		UsersClient         proto.UsersClient
+		SecretsClient       proto.SecretsClient
+		BlobsClient         proto.BlobsClient
+		FoldersClient       proto.FoldersClient
+		SharesClient        proto.SharesClient
+		OrganizationsClient proto.OrganizationsClient
+		EmergencyClient     proto.EmergencyClient
+		notifyClient        proto.NotificationClient
+		accessToken         string
+		refreshToken        string
+		refreshMu           sync.Mutex
+		password            string
+		login               string
+		encryptionKey       []byte
+		kdfParams           *models.KDFParams
+		kdfUpgrade          bool
+		clientID            uint64
+		deviceName          string
+		pendingLogin        *pendingLogin
+		previews            sync.Map
+	}
+	// pendingLogin хранит состояние входа, ожидающего кода второго фактора.
+	pendingLogin struct {
+		challenge     string
+		login         string
+		encryptionKey []byte
+		kdfParams     *models.KDFParams
+		kdfUpgrade    bool
+	}
+	// ReloadSecretList метка для обработчика. OrgID - организация, в хранилище которой изменились секреты,
+	// или 0 для личного хранилища.
+	ReloadSecretList struct {
+		OrgID uint64
+	}
+	// PasswordChanged сообщает, что мастер-пароль изменён на другом устройстве,
+	// а сессия этого устройства отозвана и требуется повторный вход.
+	PasswordChanged struct{}
+	// AccountDeleted сообщает, что учётная запись удалена с другого устройства.
+	AccountDeleted struct{}
+	// EmergencyAccessChanged сообщает, что экстренный доступ, в котором участвует пользователь, изменён:
+	// назначен, запрошен, отклонён, предоставлен или удалён.
+	EmergencyAccessChanged struct{}
 )
 
 // NewClientGRPC создаёт новый экземпляр ClientGRPC с предварительной настройкой подключения к серверу.
 func NewClientGRPC(cfg *config.Config) (ClientGRPCInterface, error) {
 	var opts []grpc.DialOption
 
+	device, err := config.LoadDevice(cfg.DeviceFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load device: %w", err)
+	}
+
 	newClient := ClientGRPC{
-		config:   cfg,
-		clientID: uint64(rand.IntN(math.MaxInt32)),
+		config:     cfg,
+		clientID:   device.ID,
+		deviceName: device.Name,
 	}
 
 	opts = append(
 		opts,
 		grpc.WithChainUnaryInterceptor(
+			interceptors.Refresh(&newClient.accessToken, newClient.refresh),
 			interceptors.Timeout(time.Second*5),
 			interceptors.AddAuth(&newClient.accessToken, uint32(newClient.clientID)),
 		),
@@ -94,33 +221,164 @@ func NewClientGRPC(cfg *config.Config) (ClientGRPCInterface, error) {
 
 	newClient.UsersClient = proto.NewUsersClient(c)
 	newClient.SecretsClient = proto.NewSecretsClient(c)
+	newClient.BlobsClient = proto.NewBlobsClient(c)
+	newClient.FoldersClient = proto.NewFoldersClient(c)
+	newClient.SharesClient = proto.NewSharesClient(c)
+	newClient.OrganizationsClient = proto.NewOrganizationsClient(c)
+	newClient.EmergencyClient = proto.NewEmergencyClient(c)
 	newClient.notifyClient = proto.NewNotificationClient(c)
 
 	return &newClient, nil
 }
 
 // Login авторизует пользователя на сервере и получает токен доступа.
+// Мастер-пароль на сервер не передаётся: из него по параметрам KDF пользователя выводятся
+// хэш аутентификации и ключ шифрования хранилища. Если учётная запись ещё не переведена
+// на хэш аутентификации, возвращается ErrLegacyMigrationRequired: мастер-пароль не отправляется
+// только потому, что этого потребовал сервер.
 func (c *ClientGRPC) Login(ctx context.Context, login string, password string) (string, error) {
+	return c.authenticate(ctx, login, password, false)
+}
+
+// MigrateLegacyLogin авторизует пользователя, учётная запись которого ещё хранит мастер-пароль по старой
+// схеме: мастер-пароль однократно передаётся серверу, который проверяет его и заменяет хэшем аутентификации.
+// Вызывается только после явного согласия пользователя в ответ на ErrLegacyMigrationRequired из Login.
+func (c *ClientGRPC) MigrateLegacyLogin(ctx context.Context, login string, password string) (string, error) {
+	return c.authenticate(ctx, login, password, true)
+}
+
+// authenticate выполняет вход. Сервер не сообщает в PreLogin, что учётная запись создана до перехода
+// на хэш аутентификации, поэтому о ней клиент узнаёт из ошибки входа. При migrate ключи выводятся
+// с параметрами старой схемы, полученными из логина, а мастер-пароль передаётся серверу для миграции.
+func (c *ClientGRPC) authenticate(ctx context.Context, login string, password string, migrate bool) (string, error) {
+	params, upgradeRequired := models.LegacyKDFParams(login), true
+	if !migrate {
+		preLogin, err := c.UsersClient.PreLogin(ctx, &proto.PreLoginRequest{Login: login})
+		if err != nil {
+			return "", parseError(err)
+		}
+		params, upgradeRequired = converter.ProtoToKDFParams(preLogin.Kdf), preLogin.UpgradeRequired
+		if err = params.Validate(); err != nil {
+			return "", err
+		}
+	}
+
+	keys, err := crypto.DeriveKeys(password, params)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive keys: %w", err)
+	}
+
 	req := &proto.LoginRequest{
-		Login:    login,
-		Password: password,
+		Login:      login,
+		AuthHash:   keys.AuthHash,
+		DeviceId:   c.clientID,
+		DeviceName: c.deviceName,
+	}
+	if migrate {
+		req.LegacyPassword = password
 	}
 
 	response, err := c.UsersClient.Login(ctx, req)
+	if status.Code(err) == codes.FailedPrecondition && !migrate {
+		return "", ErrLegacyMigrationRequired
+	}
 	if err != nil {
 		return "", parseError(err)
 	}
 
-	c.accessToken = response.AccessToken
+	if response.TotpRequired {
+		c.pendingLogin = &pendingLogin{
+			challenge:     response.TotpChallenge,
+			login:         login,
+			encryptionKey: keys.EncryptionKey,
+			kdfParams:     params,
+			kdfUpgrade:    upgradeRequired,
+		}
+		return "", ErrTOTPRequired
+	}
+
+	c.pendingLogin = nil
+	c.setTokens(response.AccessToken, response.RefreshToken)
+	c.login = login
+	c.encryptionKey = keys.EncryptionKey
+	c.kdfParams = params
+	c.kdfUpgrade = upgradeRequired
 
 	return response.AccessToken, nil
 }
 
+// VerifyTOTP завершает вход, начатый Login, кодом из приложения-аутентификатора или кодом восстановления.
+// Ключ шифрования, выведенный при вводе пароля, начинает использоваться только после успешной проверки кода.
+func (c *ClientGRPC) VerifyTOTP(ctx context.Context, code string) (string, error) {
+	if c.pendingLogin == nil {
+		return "", ErrNoPendingLogin
+	}
+
+	response, err := c.UsersClient.VerifyTOTP(ctx, &proto.VerifyTOTPRequest{
+		Challenge: c.pendingLogin.challenge,
+		Code:      code,
+	})
+	if err != nil {
+		return "", parseError(err)
+	}
+
+	c.setTokens(response.AccessToken, response.RefreshToken)
+	c.login = c.pendingLogin.login
+	c.encryptionKey = c.pendingLogin.encryptionKey
+	c.kdfParams = c.pendingLogin.kdfParams
+	c.kdfUpgrade = c.pendingLogin.kdfUpgrade
+	c.pendingLogin = nil
+
+	return response.AccessToken, nil
+}
+
+// EnableTOTP начинает включение второго фактора и возвращает секрет для приложения-аутентификатора.
+func (c *ClientGRPC) EnableTOTP(ctx context.Context) (*models.TOTPSetup, error) {
+	response, err := c.UsersClient.EnableTOTP(ctx, &proto.EnableTOTPRequest{})
+	if err != nil {
+		return nil, parseError(err)
+	}
+
+	return &models.TOTPSetup{Secret: response.Secret, URI: response.Uri}, nil
+}
+
+// ConfirmTOTP подтверждает включение второго фактора кодом и возвращает одноразовые коды восстановления.
+func (c *ClientGRPC) ConfirmTOTP(ctx context.Context, code string) ([]string, error) {
+	response, err := c.UsersClient.ConfirmTOTP(ctx, &proto.ConfirmTOTPRequest{Code: code})
+	if err != nil {
+		return nil, parseError(err)
+	}
+
+	return response.RecoveryCodes, nil
+}
+
+// DisableTOTP отключает второй фактор после проверки кода или кода восстановления.
+func (c *ClientGRPC) DisableTOTP(ctx context.Context, code string) error {
+	_, err := c.UsersClient.DisableTOTP(ctx, &proto.DisableTOTPRequest{Code: code})
+
+	return parseError(err)
+}
+
 // Register регистрирует нового пользователя и получает токен доступа.
+// Параметры KDF со случайной солью генерирует сервер; на сервер передаётся только
+// хэш аутентификации, выведенный из мастер-пароля с этими параметрами.
 func (c *ClientGRPC) Register(ctx context.Context, login string, password string) (string, error) {
+	params, err := c.NewKDFParams(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	keys, err := crypto.DeriveKeys(password, params)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive keys: %w", err)
+	}
+
 	req := &proto.RegisterRequest{
-		Login:    login,
-		Password: password,
+		Login:      login,
+		AuthHash:   keys.AuthHash,
+		Kdf:        converter.KDFParamsToProto(params),
+		DeviceId:   c.clientID,
+		DeviceName: c.deviceName,
 	}
 
 	response, err := c.UsersClient.Register(ctx, req)
@@ -128,14 +386,220 @@ func (c *ClientGRPC) Register(ctx context.Context, login string, password string
 		return "", parseError(err)
 	}
 
-	c.accessToken = response.AccessToken
+	c.setTokens(response.AccessToken, response.RefreshToken)
+	c.login = login
+	c.encryptionKey = keys.EncryptionKey
+	c.kdfParams = params
+	c.kdfUpgrade = false
 
 	return response.AccessToken, nil
 }
 
+// NewKDFParams запрашивает у сервера новые параметры KDF со случайной солью и проверяет их.
+func (c *ClientGRPC) NewKDFParams(ctx context.Context) (*models.KDFParams, error) {
+	response, err := c.UsersClient.PreRegister(ctx, &proto.PreRegisterRequest{})
+	if err != nil {
+		return nil, parseError(err)
+	}
+
+	params := converter.ProtoToKDFParams(response.Kdf)
+	if err = params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+// UpgradeKDF отправляет на сервер хэш аутентификации, выведенный с текущими параметрами KDF, новые параметры
+// KDF, выведенный с ними хэш аутентификации, ключ хранилища, зашифрованный новым ключом, и перешифрованные
+// новым ключом секреты пользователя. Если ключ хранилища не передан, передаются все секреты. После успешного
+// ответа клиент переключается на новый ключ шифрования; сессии остальных устройств пользователя сервер отзывает.
+func (c *ClientGRPC) UpgradeKDF(ctx context.Context, currentAuthHash string, params *models.KDFParams, keys *crypto.Keys, vaultKey []byte, secrets map[uint64]*models.Secret) error {
+	req := &proto.UpgradeKDFRequest{
+		CurrentAuthHash: currentAuthHash,
+		AuthHash:        keys.AuthHash,
+		Kdf:             converter.KDFParamsToProto(params),
+		Secrets:         converter.SecretPayloadsToProto(secrets),
+		VaultKey:        vaultKey,
+	}
+
+	if _, err := c.UsersClient.UpgradeKDF(ctx, req); err != nil {
+		return parseError(err)
+	}
+
+	c.encryptionKey = keys.EncryptionKey
+	c.kdfParams = params
+	c.kdfUpgrade = false
+
+	return nil
+}
+
+// GetKDFParams возвращает параметры KDF, с которыми из мастер-пароля выведены текущие ключи.
+func (c *ClientGRPC) GetKDFParams() *models.KDFParams {
+	return c.kdfParams
+}
+
+// ChangePassword отправляет на сервер хэш аутентификации текущего мастер-пароля, новые параметры KDF,
+// хэш аутентификации нового мастер-пароля, ключ хранилища, зашифрованный новым ключом, и перешифрованные
+// новым ключом секреты пользователя. Если ключ хранилища не передан, передаются все секреты.
+// После успешного ответа клиент переключается на новый ключ шифрования; сессии остальных устройств
+// пользователя сервер отзывает.
+func (c *ClientGRPC) ChangePassword(ctx context.Context, currentAuthHash string, params *models.KDFParams, keys *crypto.Keys, vaultKey []byte, secrets map[uint64]*models.Secret) error {
+	req := &proto.ChangePasswordRequest{
+		CurrentAuthHash: currentAuthHash,
+		AuthHash:        keys.AuthHash,
+		Kdf:             converter.KDFParamsToProto(params),
+		Secrets:         converter.SecretPayloadsToProto(secrets),
+		VaultKey:        vaultKey,
+	}
+
+	if _, err := c.UsersClient.ChangePassword(ctx, req); err != nil {
+		if status.Code(err) == codes.PermissionDenied {
+			return ErrWrongPassword
+		}
+		return parseError(err)
+	}
+
+	c.encryptionKey = keys.EncryptionKey
+	c.kdfParams = params
+	c.kdfUpgrade = false
+
+	return nil
+}
+
+// GetVaultKey возвращает ключ хранилища пользователя, зашифрованный ключом мастер-пароля.
+// Если ключ хранилища ещё не создан, возвращается пустой ключ.
+func (c *ClientGRPC) GetVaultKey(ctx context.Context) ([]byte, error) {
+	response, err := c.UsersClient.GetVaultKey(ctx, &proto.GetVaultKeyRequest{})
+	if err != nil {
+		return nil, parseError(err)
+	}
+
+	return response.VaultKey, nil
+}
+
+// CreateVaultKey сохраняет на сервере ключ хранилища, зашифрованный ключом мастер-пароля, вместе с секретами,
+// перешифрованными собственными ключами данных. Если ключ хранилища уже создан, возвращается ErrVaultKeyExists.
+func (c *ClientGRPC) CreateVaultKey(ctx context.Context, vaultKey []byte, secrets map[uint64]*models.Secret) error {
+	req := &proto.CreateVaultKeyRequest{
+		VaultKey: vaultKey,
+		Secrets:  converter.SecretPayloadsToProto(secrets),
+	}
+
+	if _, err := c.UsersClient.CreateVaultKey(ctx, req); err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			return ErrVaultKeyExists
+		}
+		return parseError(err)
+	}
+
+	return nil
+}
+
+// GetKeyPair возвращает пару ключей пользователя: открытый ключ и закрытый ключ, зашифрованный ключом хранилища.
+// Если пара ещё не создана, ключи пустые.
+func (c *ClientGRPC) GetKeyPair(ctx context.Context) (*models.KeyPair, error) {
+	response, err := c.UsersClient.GetKeyPair(ctx, &proto.GetKeyPairRequest{})
+	if err != nil {
+		return nil, parseError(err)
+	}
+
+	return &models.KeyPair{PublicKey: response.PublicKey, PrivateKey: response.PrivateKey}, nil
+}
+
+// CreateKeyPair сохраняет на сервере пару ключей пользователя. Если пара уже создана, возвращается ErrKeyPairExists.
+func (c *ClientGRPC) CreateKeyPair(ctx context.Context, keyPair *models.KeyPair) error {
+	req := &proto.CreateKeyPairRequest{PublicKey: keyPair.PublicKey, PrivateKey: keyPair.PrivateKey}
+	if _, err := c.UsersClient.CreateKeyPair(ctx, req); err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			return ErrKeyPairExists
+		}
+		return parseError(err)
+	}
+
+	return nil
+}
+
+// DeleteAccount удаляет учётную запись пользователя вместе со всеми секретами.
+// Мастер-пароль проверяется локально по ключу шифрования и на сервере по хэшу аутентификации.
+// Если пользователь - единственный владелец организации с другими участниками, сервер отказывает
+// в удалении, и возвращается ошибка с названием организации.
+// После успешного удаления клиент забывает токены и ключи.
+func (c *ClientGRPC) DeleteAccount(ctx context.Context, password string) error {
+	if c.kdfParams == nil {
+		return ErrNotLoggedIn
+	}
+
+	keys, err := crypto.DeriveKeys(password, c.kdfParams)
+	if err != nil {
+		return fmt.Errorf("failed to derive keys: %w", err)
+	}
+	if subtle.ConstantTimeCompare(keys.EncryptionKey, c.encryptionKey) != 1 {
+		return ErrWrongPassword
+	}
+
+	if _, err = c.UsersClient.DeleteAccount(ctx, &proto.DeleteAccountRequest{AuthHash: keys.AuthHash}); err != nil {
+		switch status.Code(err) {
+		case codes.PermissionDenied:
+			return ErrWrongPassword
+		case codes.FailedPrecondition:
+			return errors.New(status.Convert(err).Message())
+		}
+		return parseError(err)
+	}
+
+	c.setTokens("", "")
+	c.login = ""
+	c.encryptionKey = nil
+	c.kdfParams = nil
+	c.password = ""
+
+	return nil
+}
+
+// KDFUpgradeRequired сообщает, что сервер запросил перешифрование хранилища с новыми параметрами KDF.
+func (c *ClientGRPC) KDFUpgradeRequired() bool {
+	return c.kdfUpgrade
+}
+
+// ListSessions загружает активные сессии пользователя на всех его устройствах.
+func (c *ClientGRPC) ListSessions(ctx context.Context) ([]*models.DeviceSession, error) {
+	response, err := c.UsersClient.ListSessions(ctx, &proto.ListSessionsRequest{})
+	if err != nil {
+		return nil, parseError(err)
+	}
+
+	return converter.ProtoToDeviceSessions(response.Sessions), nil
+}
+
+// RenameSession изменяет имя устройства сессии.
+func (c *ClientGRPC) RenameSession(ctx context.Context, id uint64, name string) error {
+	_, err := c.UsersClient.RenameSession(ctx, &proto.RenameSessionRequest{Id: id, DeviceName: name})
+
+	return parseError(err)
+}
+
+// RevokeSession отзывает сессию на другом устройстве или текущую сессию.
+func (c *ClientGRPC) RevokeSession(ctx context.Context, id uint64) error {
+	_, err := c.UsersClient.RevokeSession(ctx, &proto.RevokeSessionRequest{Id: id})
+
+	return parseError(err)
+}
+
+// LogoutAll завершает все сессии пользователя, включая текущую, и сбрасывает токены клиента.
+func (c *ClientGRPC) LogoutAll(ctx context.Context) error {
+	if _, err := c.UsersClient.LogoutAll(ctx, &proto.LogoutAllRequest{}); err != nil {
+		return parseError(err)
+	}
+
+	c.setTokens("", "")
+
+	return nil
+}
+
 // LoadSecrets загружает список секретов пользователя.
 func (c *ClientGRPC) LoadSecrets(ctx context.Context) ([]*models.Secret, error) {
-	request := emptypb.Empty{}
+	request := proto.GetUserSecretsRequest{}
 
 	response, err := c.SecretsClient.GetUserSecrets(ctx, &request)
 	if err != nil {
@@ -146,13 +610,42 @@ func (c *ClientGRPC) LoadSecrets(ctx context.Context) ([]*models.Secret, error)
 	return secrets, nil
 }
 
+// SyncSecrets загружает изменения секретов пользователя после ревизии хранилища sinceRevision.
+// Нулевая ревизия запрашивает все секреты.
+func (c *ClientGRPC) SyncSecrets(ctx context.Context, sinceRevision uint64) (*models.SecretsDelta, error) {
+	response, err := c.SecretsClient.SyncSecrets(ctx, &proto.SyncSecretsRequest{SinceRevision: sinceRevision})
+	if err != nil {
+		return nil, parseError(err)
+	}
+
+	return &models.SecretsDelta{
+		Secrets:    converter.ProtoToSecrets(response.Secrets),
+		DeletedIDs: response.DeletedIds,
+		Revision:   response.Revision,
+		Full:       response.Full,
+	}, nil
+}
+
+// ListSecrets загружает страницу заголовков секретов пользователя без зашифрованных данных.
+func (c *ClientGRPC) ListSecrets(ctx context.Context, query *models.SecretListQuery) (*models.SecretsPage, error) {
+	response, err := c.SecretsClient.ListSecrets(ctx, converter.SecretListQueryToProto(query))
+	if err != nil {
+		return nil, parseError(err)
+	}
+
+	return &models.SecretsPage{
+		Secrets:       converter.ProtoToSecrets(response.Secrets),
+		NextPageToken: response.NextPageToken,
+	}, nil
+}
+
 // LoadSecret загружает информацию о конкретном секрете.
-func (c *ClientGRPC) LoadSecret(_ context.Context, ID uint64) (*models.Secret, error) {
+func (c *ClientGRPC) LoadSecret(ctx context.Context, ID uint64) (*models.Secret, error) {
 	request := &proto.GetUserSecretRequest{
 		Id: ID,
 	}
 
-	response, err := c.SecretsClient.GetUserSecret(context.Background(), request)
+	response, err := c.SecretsClient.GetUserSecret(ctx, request)
 	if err != nil {
 		return nil, parseError(err)
 	}
@@ -162,15 +655,34 @@ func (c *ClientGRPC) LoadSecret(_ context.Context, ID uint64) (*models.Secret, e
 	return secret, nil
 }
 
+// ReserveSecretID резервирует на сервере идентификатор нового секрета, к которому клиент привязывает
+// шифротекст до создания секрета.
+func (c *ClientGRPC) ReserveSecretID(ctx context.Context) (uint64, error) {
+	response, err := c.SecretsClient.ReserveSecretID(ctx, &emptypb.Empty{})
+	if err != nil {
+		return 0, parseError(err)
+	}
+
+	return response.Id, nil
+}
+
 // SaveSecret сохраняет или обновляет секрет пользователя на сервере.
+// Если секрет был изменён на сервере после загрузки, возвращает RevisionConflictError с текущей ревизией.
 func (c *ClientGRPC) SaveSecret(ctx context.Context, secret *models.Secret) error {
 	sec := &proto.Secret{
-		Title:      secret.Title,
-		Metadata:   secret.Metadata,
-		SecretType: converter.TypeToProto(secret.SecretType),
-		Payload:    secret.Payload,
-		CreatedAt:  timestamppb.New(secret.CreatedAt),
-		UpdatedAt:  timestamppb.New(secret.UpdatedAt),
+		Title:           secret.Title,
+		Metadata:        secret.Metadata,
+		SecretType:      converter.TypeToProto(secret.SecretType),
+		Payload:         secret.Payload,
+		CreatedAt:       timestamppb.New(secret.CreatedAt),
+		UpdatedAt:       timestamppb.New(secret.UpdatedAt),
+		Revision:        secret.Revision,
+		BlobId:          secret.BlobID,
+		FolderId:        secret.FolderID,
+		TagIds:          secret.TagIDs,
+		LabelsEncrypted: secret.LabelsEncrypted,
+		SearchTokens:    secret.SearchTokens,
+		DataKey:         secret.DataKey,
 	}
 
 	if secret.ID > 0 {
@@ -183,7 +695,16 @@ func (c *ClientGRPC) SaveSecret(ctx context.Context, secret *models.Secret) erro
 	return parseError(err)
 }
 
-// DeleteSecret удаляет секрет пользователя.
+// EncryptSecretLabels сохраняет на сервере заголовки и метаданные секретов, зашифрованные вместо открытых,
+// вместе с токенами слепого индекса.
+func (c *ClientGRPC) EncryptSecretLabels(ctx context.Context, secrets []*models.Secret) error {
+	request := &proto.EncryptSecretLabelsRequest{Secrets: converter.SecretLabelsToProto(secrets)}
+	_, err := c.SecretsClient.EncryptSecretLabels(ctx, request)
+
+	return parseError(err)
+}
+
+// DeleteSecret перемещает секрет пользователя в корзину.
 func (c *ClientGRPC) DeleteSecret(ctx context.Context, id uint64) error {
 	request := &proto.DeleteUserSecretRequest{Id: id}
 	_, err := c.SecretsClient.DeleteUserSecret(ctx, request)
@@ -191,16 +712,453 @@ func (c *ClientGRPC) DeleteSecret(ctx context.Context, id uint64) error {
 	return parseError(err)
 }
 
+// LoadSecretVersions загружает сохранённые версии секрета от новых к старым.
+func (c *ClientGRPC) LoadSecretVersions(ctx context.Context, id uint64) ([]*models.SecretVersion, error) {
+	request := &proto.ListSecretVersionsRequest{SecretId: id}
+	response, err := c.SecretsClient.ListSecretVersions(ctx, request)
+	if err != nil {
+		return nil, parseError(err)
+	}
+
+	return converter.ProtoToSecretVersions(response.Versions), nil
+}
+
+// RestoreSecretVersion восстанавливает секрет из сохранённой версии.
+func (c *ClientGRPC) RestoreSecretVersion(ctx context.Context, id, versionID uint64) error {
+	request := &proto.RestoreSecretVersionRequest{SecretId: id, VersionId: versionID}
+	_, err := c.SecretsClient.RestoreSecretVersion(ctx, request)
+
+	return parseError(err)
+}
+
+// LoadTrash загружает секреты пользователя, находящиеся в корзине.
+func (c *ClientGRPC) LoadTrash(ctx context.Context) ([]*models.Secret, error) {
+	response, err := c.SecretsClient.ListTrash(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, parseError(err)
+	}
+
+	return converter.ProtoToSecrets(response.Secrets), nil
+}
+
+// RestoreSecret возвращает секрет из корзины.
+func (c *ClientGRPC) RestoreSecret(ctx context.Context, id uint64) error {
+	_, err := c.SecretsClient.RestoreSecret(ctx, &proto.RestoreSecretRequest{Id: id})
+
+	return parseError(err)
+}
+
+// PurgeSecret окончательно удаляет секрет из корзины.
+func (c *ClientGRPC) PurgeSecret(ctx context.Context, id uint64) error {
+	_, err := c.SecretsClient.PurgeSecret(ctx, &proto.PurgeSecretRequest{Id: id})
+
+	return parseError(err)
+}
+
+// ListFolders загружает все папки пользователя.
+func (c *ClientGRPC) ListFolders(ctx context.Context) ([]*models.Folder, error) {
+	response, err := c.FoldersClient.ListFolders(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, parseFolderError(err)
+	}
+
+	return converter.ProtoToFolders(response.Folders), nil
+}
+
+// CreateFolder создаёт папку name в родительской папке parentID; нулевой parentID создаёт папку верхнего уровня.
+func (c *ClientGRPC) CreateFolder(ctx context.Context, parentID uint64, name string) (*models.Folder, error) {
+	response, err := c.FoldersClient.CreateFolder(ctx, &proto.CreateFolderRequest{ParentId: parentID, Name: name})
+	if err != nil {
+		return nil, parseFolderError(err)
+	}
+
+	return converter.ProtoToFolder(response.Folder), nil
+}
+
+// UpdateFolder переименовывает папку или перемещает её в родительскую папку parentID.
+func (c *ClientGRPC) UpdateFolder(ctx context.Context, id, parentID uint64, name string) error {
+	_, err := c.FoldersClient.UpdateFolder(ctx, &proto.UpdateFolderRequest{Id: id, ParentId: parentID, Name: name})
+
+	return parseFolderError(err)
+}
+
+// DeleteFolder удаляет папку вместе с вложенными папками.
+func (c *ClientGRPC) DeleteFolder(ctx context.Context, id uint64) error {
+	_, err := c.FoldersClient.DeleteFolder(ctx, &proto.DeleteFolderRequest{Id: id})
+
+	return parseFolderError(err)
+}
+
+// ListTags загружает все метки пользователя.
+func (c *ClientGRPC) ListTags(ctx context.Context) ([]*models.Tag, error) {
+	response, err := c.FoldersClient.ListTags(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, parseFolderError(err)
+	}
+
+	return converter.ProtoToTags(response.Tags), nil
+}
+
+// CreateTag создаёт метку name.
+func (c *ClientGRPC) CreateTag(ctx context.Context, name string) (*models.Tag, error) {
+	response, err := c.FoldersClient.CreateTag(ctx, &proto.CreateTagRequest{Name: name})
+	if err != nil {
+		return nil, parseFolderError(err)
+	}
+
+	return converter.ProtoToTag(response.Tag), nil
+}
+
+// UpdateTag переименовывает метку.
+func (c *ClientGRPC) UpdateTag(ctx context.Context, id uint64, name string) error {
+	_, err := c.FoldersClient.UpdateTag(ctx, &proto.UpdateTagRequest{Id: id, Name: name})
+
+	return parseFolderError(err)
+}
+
+// DeleteTag удаляет метку и снимает её с секретов.
+func (c *ClientGRPC) DeleteTag(ctx context.Context, id uint64) error {
+	_, err := c.FoldersClient.DeleteTag(ctx, &proto.DeleteTagRequest{Id: id})
+
+	return parseFolderError(err)
+}
+
+// GetPublicKey загружает открытый ключ пользователя login, которому передаётся секрет.
+func (c *ClientGRPC) GetPublicKey(ctx context.Context, login string) ([]byte, error) {
+	response, err := c.SharesClient.GetPublicKey(ctx, &proto.GetPublicKeyRequest{Login: login})
+	if err != nil {
+		return nil, parseShareError(err)
+	}
+
+	return response.PublicKey, nil
+}
+
+// ShareSecret передаёт секрет пользователю share.RecipientLogin с ключом данных, зашифрованным для него.
+// Возвращает созданный доступ.
+func (c *ClientGRPC) ShareSecret(ctx context.Context, share *models.Share) (*models.Share, error) {
+	response, err := c.SharesClient.ShareSecret(ctx, &proto.ShareSecretRequest{
+		SecretId:       share.SecretID,
+		RecipientLogin: share.RecipientLogin,
+		DataKey:        share.DataKey,
+		CanEdit:        share.CanEdit,
+	})
+	if err != nil {
+		return nil, parseShareError(err)
+	}
+
+	return converter.ProtoToShare(response.Share), nil
+}
+
+// ListShares загружает доступы, выданные к секрету secretID; нулевой secretID загружает доступы ко всем секретам.
+func (c *ClientGRPC) ListShares(ctx context.Context, secretID uint64) ([]*models.Share, error) {
+	response, err := c.SharesClient.ListShares(ctx, &proto.ListSharesRequest{SecretId: secretID})
+	if err != nil {
+		return nil, parseShareError(err)
+	}
+
+	return converter.ProtoToShares(response.Shares), nil
+}
+
+// ListSharedWithMe загружает секреты других пользователей, переданные пользователю, с заполненным доступом Share.
+func (c *ClientGRPC) ListSharedWithMe(ctx context.Context) ([]*models.Secret, error) {
+	response, err := c.SharesClient.ListSharedWithMe(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, parseShareError(err)
+	}
+
+	return converter.ProtoToSharedSecrets(response.Secrets), nil
+}
+
+// RevokeShare удаляет доступ к секрету: отзывает выданный доступ или отказывается от полученного.
+func (c *ClientGRPC) RevokeShare(ctx context.Context, id uint64) error {
+	_, err := c.SharesClient.RevokeShare(ctx, &proto.RevokeShareRequest{Id: id})
+
+	return parseShareError(err)
+}
+
+// UpdateSharedSecret сохраняет изменения переданного секрета и возвращает его новую ревизию.
+// Если секрет был изменён после загрузки, возвращает RevisionConflictError с текущей ревизией.
+func (c *ClientGRPC) UpdateSharedSecret(ctx context.Context, secret *models.Secret) (uint64, error) {
+	response, err := c.SharesClient.UpdateSharedSecret(ctx, &proto.UpdateSharedSecretRequest{Secret: &proto.Secret{
+		Id:              secret.ID,
+		Title:           secret.Title,
+		Metadata:        secret.Metadata,
+		SecretType:      converter.TypeToProto(secret.SecretType),
+		Payload:         secret.Payload,
+		UpdatedAt:       timestamppb.New(secret.UpdatedAt),
+		Revision:        secret.Revision,
+		LabelsEncrypted: secret.LabelsEncrypted,
+	}})
+	if err != nil {
+		return 0, parseShareError(err)
+	}
+
+	return response.Revision, nil
+}
+
+// WithShare возвращает контекст, запросы с которым скачивают файл секрета, переданного пользователю доступом shareID.
+func WithShare(ctx context.Context, shareID uint64) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, consts.ShareIDHeader, strconv.FormatUint(shareID, 10))
+}
+
+// WithOrganization возвращает контекст, запросы с которым выполняются в хранилище организации orgID.
+func WithOrganization(ctx context.Context, orgID uint64) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, consts.OrganizationIDHeader, strconv.FormatUint(orgID, 10))
+}
+
+// CreateOrganization создаёт организацию name, владельцем которой становится пользователь.
+// vaultKey - ключ хранилища организации, зашифрованный открытым ключом пользователя.
+func (c *ClientGRPC) CreateOrganization(ctx context.Context, name string, vaultKey []byte) (*models.Organization, error) {
+	response, err := c.OrganizationsClient.CreateOrganization(ctx, &proto.CreateOrganizationRequest{Name: name, VaultKey: vaultKey})
+	if err != nil {
+		return nil, parseOrganizationError(err)
+	}
+
+	return converter.ProtoToOrganization(response.Organization), nil
+}
+
+// ListOrganizations загружает организации пользователя вместе с его ролью и ключом хранилища.
+func (c *ClientGRPC) ListOrganizations(ctx context.Context) ([]*models.Organization, error) {
+	response, err := c.OrganizationsClient.ListOrganizations(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, parseOrganizationError(err)
+	}
+
+	return converter.ProtoToOrganizations(response.Organizations), nil
+}
+
+// ListOrgMembers загружает участников организации orgID.
+func (c *ClientGRPC) ListOrgMembers(ctx context.Context, orgID uint64) ([]*models.OrgMember, error) {
+	response, err := c.OrganizationsClient.ListMembers(ctx, &proto.ListOrgMembersRequest{OrgId: orgID})
+	if err != nil {
+		return nil, parseOrganizationError(err)
+	}
+
+	return converter.ProtoToOrgMembers(response.Members), nil
+}
+
+// AddOrgMember добавляет пользователя member.Login в организацию member.OrgID с ролью member.Role
+// и ключом хранилища, зашифрованным для него. Возвращает добавленного участника.
+func (c *ClientGRPC) AddOrgMember(ctx context.Context, member *models.OrgMember) (*models.OrgMember, error) {
+	response, err := c.OrganizationsClient.AddMember(ctx, &proto.AddOrgMemberRequest{
+		OrgId:    member.OrgID,
+		Login:    member.Login,
+		Role:     converter.OrgRoleToProto(member.Role),
+		VaultKey: member.VaultKey,
+	})
+	if err != nil {
+		return nil, parseOrganizationError(err)
+	}
+
+	return converter.ProtoToOrgMember(response.Member), nil
+}
+
+// UpdateOrgMemberRole изменяет роль участника userID организации orgID.
+func (c *ClientGRPC) UpdateOrgMemberRole(ctx context.Context, orgID, userID uint64, role models.OrgRole) error {
+	_, err := c.OrganizationsClient.UpdateMemberRole(ctx, &proto.UpdateOrgMemberRoleRequest{
+		OrgId:  orgID,
+		UserId: userID,
+		Role:   converter.OrgRoleToProto(role),
+	})
+
+	return parseOrganizationError(err)
+}
+
+// RemoveOrgMember исключает участника userID из организации orgID или выводит из неё самого пользователя.
+func (c *ClientGRPC) RemoveOrgMember(ctx context.Context, orgID, userID uint64) error {
+	_, err := c.OrganizationsClient.RemoveMember(ctx, &proto.RemoveOrgMemberRequest{OrgId: orgID, UserId: userID})
+
+	return parseOrganizationError(err)
+}
+
+// DeleteOrganization удаляет организацию orgID вместе с секретами её хранилища.
+func (c *ClientGRPC) DeleteOrganization(ctx context.Context, orgID uint64) error {
+	_, err := c.OrganizationsClient.DeleteOrganization(ctx, &proto.DeleteOrganizationRequest{OrgId: orgID})
+
+	return parseOrganizationError(err)
+}
+
+// WithEmergencyAccess возвращает контекст, запросы с которым читают секреты хранилища владельца,
+// экстренный доступ id к которому предоставлен пользователю.
+func WithEmergencyAccess(ctx context.Context, id uint64) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, consts.EmergencyAccessIDHeader, strconv.FormatUint(id, 10))
+}
+
+// AddEmergencyContact назначает пользователя access.GranteeLogin доверенным со сроком ожидания access.WaitDays
+// и ключом хранилища, зашифрованным для него. Возвращает назначенный доступ.
+func (c *ClientGRPC) AddEmergencyContact(ctx context.Context, access *models.EmergencyAccess) (*models.EmergencyAccess, error) {
+	response, err := c.EmergencyClient.AddContact(ctx, &proto.AddEmergencyContactRequest{
+		GranteeLogin: access.GranteeLogin,
+		WaitDays:     uint32(access.WaitDays),
+		VaultKey:     access.VaultKey,
+	})
+	if err != nil {
+		return nil, parseEmergencyError(err)
+	}
+
+	return converter.ProtoToEmergencyAccess(response.Access), nil
+}
+
+// ListEmergencyContacts загружает доверенных пользователей и состояние их доступа.
+func (c *ClientGRPC) ListEmergencyContacts(ctx context.Context) ([]*models.EmergencyAccess, error) {
+	response, err := c.EmergencyClient.ListContacts(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, parseEmergencyError(err)
+	}
+
+	return converter.ProtoToEmergencyAccesses(response.Accesses), nil
+}
+
+// ListEmergencyGrants загружает хранилища, владельцы которых назначили пользователя доверенным,
+// вместе с ключами хранилищ, доступ к которым предоставлен.
+func (c *ClientGRPC) ListEmergencyGrants(ctx context.Context) ([]*models.EmergencyAccess, error) {
+	response, err := c.EmergencyClient.ListGrants(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, parseEmergencyError(err)
+	}
+
+	return converter.ProtoToEmergencyAccesses(response.Accesses), nil
+}
+
+// RequestEmergencyAccess запрашивает экстренный доступ id к хранилищу владельца.
+func (c *ClientGRPC) RequestEmergencyAccess(ctx context.Context, id uint64) error {
+	_, err := c.EmergencyClient.RequestAccess(ctx, &proto.EmergencyAccessRequest{Id: id})
+
+	return parseEmergencyError(err)
+}
+
+// RejectEmergencyAccess отклоняет запрос экстренного доступа id или отзывает предоставленный доступ.
+func (c *ClientGRPC) RejectEmergencyAccess(ctx context.Context, id uint64) error {
+	_, err := c.EmergencyClient.RejectAccess(ctx, &proto.EmergencyAccessRequest{Id: id})
+
+	return parseEmergencyError(err)
+}
+
+// DeleteEmergencyAccess удаляет экстренный доступ id.
+func (c *ClientGRPC) DeleteEmergencyAccess(ctx context.Context, id uint64) error {
+	_, err := c.EmergencyClient.DeleteAccess(ctx, &proto.EmergencyAccessRequest{Id: id})
+
+	return parseEmergencyError(err)
+}
+
+// GetBlobStatus возвращает количество фрагментов бинарного объекта и количество уже полученных сервером.
+// Возвращает ErrNotFound, если сервер ещё не начинал приём объекта.
+func (c *ClientGRPC) GetBlobStatus(ctx context.Context, blobID string) (*models.BlobStatus, error) {
+	response, err := c.BlobsClient.GetBlobStatus(ctx, &proto.GetBlobStatusRequest{BlobId: blobID})
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("blob %w", gophKeeperErrors.ErrNotFound)
+	}
+	if err != nil {
+		return nil, parseError(err)
+	}
+
+	return &models.BlobStatus{ID: blobID, ChunkCount: response.ChunkCount, ReceivedChunks: response.ReceivedChunks}, nil
+}
+
+// UploadBlob отправляет на сервер фрагменты объекта, начиная с индекса from.
+// Зашифрованный фрагмент с индексом index возвращает функция chunk.
+// Возвращает количество фрагментов, полученных сервером.
+func (c *ClientGRPC) UploadBlob(ctx context.Context, blobID string, chunkCount, from uint32, chunk func(index uint32) ([]byte, error)) (uint32, error) {
+	stream, err := c.BlobsClient.UploadBlob(ctx)
+	if err != nil {
+		return 0, parseError(err)
+	}
+
+	for index := from; index < chunkCount; index++ {
+		data, err := chunk(index)
+		if err != nil {
+			_ = stream.CloseSend()
+			return 0, err
+		}
+
+		request := &proto.UploadBlobRequest{BlobId: blobID, ChunkCount: chunkCount, ChunkIndex: index, Data: data}
+		if err = stream.Send(request); err != nil {
+			// Причину обрыва потока сервер возвращает при закрытии.
+			_, err = stream.CloseAndRecv()
+			return 0, parseError(err)
+		}
+	}
+
+	response, err := stream.CloseAndRecv()
+	if err != nil {
+		return 0, parseError(err)
+	}
+
+	return response.ReceivedChunks, nil
+}
+
+// DownloadBlob получает с сервера фрагменты объекта, начиная с индекса from, и передаёт их handle по порядку.
+func (c *ClientGRPC) DownloadBlob(ctx context.Context, blobID string, from uint32, handle func(index, count uint32, data []byte) error) error {
+	stream, err := c.BlobsClient.DownloadBlob(ctx, &proto.DownloadBlobRequest{BlobId: blobID, FromChunk: from})
+	if err != nil {
+		return parseError(err)
+	}
+
+	for {
+		response, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return parseError(err)
+		}
+
+		if err = handle(response.ChunkIndex, response.ChunkCount, response.Data); err != nil {
+			return err
+		}
+	}
+}
+
 // SetToken устанавливает текущий токен доступа клиента.
 func (c *ClientGRPC) SetToken(token string) {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
 	c.accessToken = token
 }
 
 // GetToken возвращает текущий токен доступа клиента.
 func (c *ClientGRPC) GetToken() string {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
 	return c.accessToken
 }
 
+// setTokens сохраняет пару токенов, выданную сервером при входе, регистрации или обновлении сессии.
+func (c *ClientGRPC) setTokens(accessToken, refreshToken string) {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	c.accessToken = accessToken
+	c.refreshToken = refreshToken
+}
+
+// refresh обменивает refresh-токен на новую пару токенов.
+// Одновременные вызовы с одним и тем же устаревшим токеном выполняют обмен один раз:
+// refresh-токен одноразовый, и его повторное предъявление привело бы к отзыву сессии.
+func (c *ClientGRPC) refresh(ctx context.Context, staleToken string) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	if c.accessToken != staleToken {
+		return nil
+	}
+	if len(c.refreshToken) == 0 {
+		return ErrNoRefreshToken
+	}
+
+	response, err := c.UsersClient.RefreshToken(ctx, &proto.RefreshTokenRequest{RefreshToken: c.refreshToken})
+	if err != nil {
+		return parseError(err)
+	}
+
+	c.accessToken = response.AccessToken
+	c.refreshToken = response.RefreshToken
+
+	return nil
+}
+
 // SetPassword устанавливает текущий пароль клиента.
 func (c *ClientGRPC) SetPassword(password string) {
 	c.password = password
@@ -211,17 +1169,30 @@ func (c *ClientGRPC) GetPassword() string {
 	return c.password
 }
 
+// GetEncryptionKey возвращает ключ шифрования хранилища, выведенный при входе или регистрации.
+func (c *ClientGRPC) GetEncryptionKey() []byte {
+	return c.encryptionKey
+}
+
+// GetLogin возвращает логин, с которым выполнены вход или регистрация.
+func (c *ClientGRPC) GetLogin() string {
+	return c.login
+}
+
 // Notifications подписывается на уведомления сервера и обновляет UI при получении новых данных.
 func (c *ClientGRPC) Notifications(p *tea.Program, logger *zap.Logger) {
 	var (
-		err        error
-		maxRetries = 5
-		retryCount int
-		stream     proto.Notification_SubscribeClient
+		err         error
+		maxRetries  = 5
+		retryCount  int
+		response    *proto.SubscribeResponse
+		stream      proto.Notification_SubscribeClient
+		streamToken string
 	)
 
 	for {
 		if stream == nil {
+			streamToken = c.GetToken()
 			if stream, err = c.subscribe(); err != nil {
 				if retryCount < maxRetries {
 					waitTime := time.Duration(math.Pow(2, float64(retryCount))) * time.Second
@@ -236,16 +1207,51 @@ func (c *ClientGRPC) Notifications(p *tea.Program, logger *zap.Logger) {
 			retryCount = 0
 		}
 
-		_, err = stream.Recv()
+		response, err = stream.Recv()
+		if msg := sessionEndedMsg(response.GetEvent()); msg != nil {
+			logger.Info("session ended by server, notification stream stopped", zap.Stringer("event", response.GetEvent()))
+			c.setTokens("", "")
+			c.encryptionKey = nil
+			if p != nil {
+				p.Send(msg)
+			}
+			break
+		}
+		if status.Code(err) == codes.Unauthenticated {
+			stream = nil
+			if err = c.refresh(context.Background(), streamToken); err != nil {
+				logger.Info("session expired, notification stream stopped", zap.Error(err))
+				break
+			}
+			continue
+		}
 		if err != nil {
 			stream = nil
 			time.Sleep(time.Second * 2)
 			continue
 		}
 
-		if p != nil {
-			p.Send(ReloadSecretList{})
+		if p == nil {
+			continue
+		}
+		if response.GetEvent() == proto.EventType_EVENT_TYPE_EMERGENCY_ACCESS {
+			p.Send(EmergencyAccessChanged{})
+			continue
 		}
+		p.Send(ReloadSecretList{OrgID: response.GetOrgId()})
+	}
+}
+
+// sessionEndedMsg возвращает сообщение UI для событий, после которых сессия устройства завершена сервером,
+// и nil для остальных событий.
+func sessionEndedMsg(event proto.EventType) tea.Msg {
+	switch event {
+	case proto.EventType_EVENT_TYPE_PASSWORD_CHANGED:
+		return PasswordChanged{}
+	case proto.EventType_EVENT_TYPE_ACCOUNT_DELETED:
+		return AccountDeleted{}
+	default:
+		return nil
 	}
 }
 
@@ -302,11 +1308,89 @@ func parseError(err error) error {
 		return errors.New("failed to authenticate")
 	case codes.AlreadyExists:
 		return errors.New("user already exists")
+	case codes.ResourceExhausted:
+		for _, detail := range st.Details() {
+			if retryInfo, ok := detail.(*errdetails.RetryInfo); ok {
+				return fmt.Errorf("too many login attempts, retry in %s", retryInfo.GetRetryDelay().AsDuration())
+			}
+		}
+		return errors.New("too many login attempts, retry later")
+	case codes.Aborted:
+		for _, detail := range st.Details() {
+			info, ok := detail.(*errdetails.ErrorInfo)
+			if !ok || info.GetReason() != consts.RevisionConflictReason {
+				continue
+			}
+			current, parseErr := strconv.ParseUint(info.GetMetadata()[consts.CurrentRevisionKey], 10, 64)
+			if parseErr == nil {
+				return &gophKeeperErrors.RevisionConflictError{Current: current}
+			}
+		}
+		return err
 	default:
 		return err
 	}
 }
 
+// parseFolderError преобразует ошибку сервера папок и меток в понятную пользователю ошибку.
+// Сообщения о занятом или недопустимом названии передаются без изменений, остальные ошибки обрабатывает parseError.
+func parseFolderError(err error) error {
+	switch status.Code(err) {
+	case codes.AlreadyExists:
+		return errors.New("folder or tag with this name already exists")
+	case codes.InvalidArgument, codes.NotFound:
+		return errors.New(status.Convert(err).Message())
+	default:
+		return parseError(err)
+	}
+}
+
+// parseShareError преобразует ошибку операций с переданными секретами: запрет изменения секрета
+// без права изменения - в ErrShareReadOnly, а сообщение о неверном запросе или ненайденных пользователе
+// и секрете передаёт пользователю как есть.
+func parseShareError(err error) error {
+	switch status.Code(err) {
+	case codes.PermissionDenied:
+		return gophKeeperErrors.ErrShareReadOnly
+	case codes.InvalidArgument, codes.NotFound:
+		return errors.New(status.Convert(err).Message())
+	default:
+		return parseError(err)
+	}
+}
+
+// parseOrganizationError преобразует ошибку операций с организациями: недостаток роли - в ErrPermissionDenied,
+// попытку понизить или исключить последнего владельца - в ErrLastOwner, а сообщение о неверном запросе
+// или ненайденных организации и пользователе передаёт пользователю как есть.
+func parseOrganizationError(err error) error {
+	switch status.Code(err) {
+	case codes.PermissionDenied:
+		return gophKeeperErrors.ErrPermissionDenied
+	case codes.FailedPrecondition:
+		return gophKeeperErrors.ErrLastOwner
+	case codes.InvalidArgument, codes.NotFound:
+		return errors.New(status.Convert(err).Message())
+	default:
+		return parseError(err)
+	}
+}
+
+// parseEmergencyError преобразует ошибку операций с экстренным доступом: действие, недопустимое в текущем
+// состоянии доступа, - в ErrEmergencyState, чтение хранилища до предоставления доступа - в ErrPermissionDenied,
+// а сообщение о неверном запросе или ненайденных доступе и пользователе передаёт пользователю как есть.
+func parseEmergencyError(err error) error {
+	switch status.Code(err) {
+	case codes.FailedPrecondition:
+		return gophKeeperErrors.ErrEmergencyState
+	case codes.PermissionDenied:
+		return gophKeeperErrors.ErrPermissionDenied
+	case codes.InvalidArgument, codes.NotFound:
+		return errors.New(status.Convert(err).Message())
+	default:
+		return parseError(err)
+	}
+}
+
 // Инициирует подписку на серверные уведомления, используя ID клиента.
 func (c *ClientGRPC) subscribe() (proto.Notification_SubscribeClient, error) {
 	return c.notifyClient.Subscribe(context.Background(), &proto.SubscribeRequest{
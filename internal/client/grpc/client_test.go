@@ -2,6 +2,10 @@ package grpc
 
 import (
 	"beliaev-aa/GophKeeper/internal/client/config"
+	"beliaev-aa/GophKeeper/internal/client/crypto"
+	"beliaev-aa/GophKeeper/pkg/consts"
+	"beliaev-aa/GophKeeper/pkg/converter"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
 	"beliaev-aa/GophKeeper/pkg/models"
 	"beliaev-aa/GophKeeper/pkg/proto"
 	"beliaev-aa/GophKeeper/tests/mocks"
@@ -9,10 +13,16 @@ import (
 	"context"
 	"errors"
 	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"io"
 	"reflect"
 	"strings"
 	"testing"
@@ -38,6 +48,17 @@ func TestNewClientGRPC(t *testing.T) {
 	}
 }
 
+// testKDFParams возвращает допустимые параметры KDF с минимальной стоимостью для тестов.
+func testKDFParams() *models.KDFParams {
+	return &models.KDFParams{
+		Algorithm:     models.KDFArgon2id,
+		Salt:          []byte("0123456789abcdef"),
+		Argon2Memory:  19 * 1024,
+		Argon2Time:    1,
+		Argon2Threads: 1,
+	}
+}
+
 func TestClientGRPC_Login(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -45,34 +66,85 @@ func TestClientGRPC_Login(t *testing.T) {
 	mockUsersClient := mocks.NewMockUsersClient(ctrl)
 	client := &ClientGRPC{
 		UsersClient: mockUsersClient,
+		clientID:    42,
+		deviceName:  "laptop",
+	}
+
+	keys, err := crypto.DeriveKeys("1234", testKDFParams())
+	if err != nil {
+		t.Fatalf("Failed to derive keys: %v", err)
 	}
+	preLoginReq := &proto.PreLoginRequest{Login: "test"}
+	preLoginResp := &proto.PreLoginResponse{Kdf: converter.KDFParamsToProto(testKDFParams())}
 
 	tests := []struct {
-		name        string
-		setupMock   func()
-		expectedErr error
+		name          string
+		setupMock     func()
+		expectedErr   error
+		expectedKey   []byte
+		expectUpgrade bool
 	}{
 		{
 			name: "Login_Success",
 			setupMock: func() {
 				req := &proto.LoginRequest{
-					Login:    "test",
-					Password: "1234",
+					Login:      "test",
+					AuthHash:   keys.AuthHash,
+					DeviceId:   42,
+					DeviceName: "laptop",
 				}
 				resp := &proto.LoginResponse{
-					AccessToken: "access_token",
+					AccessToken:  "access_token",
+					RefreshToken: "refresh_token",
 				}
+				mockUsersClient.EXPECT().PreLogin(gomock.Any(), preLoginReq).Return(preLoginResp, nil)
 				mockUsersClient.EXPECT().Login(gomock.Any(), req).Return(resp, nil)
 			},
 			expectedErr: nil,
+			expectedKey: keys.EncryptionKey,
+		},
+		{
+			name: "Login_Failed_LegacyMigrationRequired",
+			setupMock: func() {
+				req := &proto.LoginRequest{
+					Login:      "test",
+					AuthHash:   keys.AuthHash,
+					DeviceId:   42,
+					DeviceName: "laptop",
+				}
+				gomock.InOrder(
+					mockUsersClient.EXPECT().PreLogin(gomock.Any(), preLoginReq).Return(preLoginResp, nil),
+					mockUsersClient.EXPECT().Login(gomock.Any(), req).Return(nil, status.Error(codes.FailedPrecondition, "legacy account")),
+				)
+			},
+			expectedErr: ErrLegacyMigrationRequired,
+		},
+		{
+			name: "Login_Failed_NoKDFParams",
+			setupMock: func() {
+				mockUsersClient.EXPECT().PreLogin(gomock.Any(), preLoginReq).Return(&proto.PreLoginResponse{}, nil)
+			},
+			expectedErr: errors.New("invalid kdf params: params are not set"),
+		},
+		{
+			name: "Login_Failed_WeakKDFParams",
+			setupMock: func() {
+				weak := testKDFParams()
+				weak.Argon2Memory = 1024
+				mockUsersClient.EXPECT().PreLogin(gomock.Any(), preLoginReq).Return(&proto.PreLoginResponse{Kdf: converter.KDFParamsToProto(weak)}, nil)
+			},
+			expectedErr: errors.New("invalid kdf params: argon2id memory must be between 19456 and 1048576 KiB"),
 		},
 		{
 			name: "Login_Failed_Unavailable",
 			setupMock: func() {
 				req := &proto.LoginRequest{
-					Login:    "test",
-					Password: "1234",
+					Login:      "test",
+					AuthHash:   keys.AuthHash,
+					DeviceId:   42,
+					DeviceName: "laptop",
 				}
+				mockUsersClient.EXPECT().PreLogin(gomock.Any(), preLoginReq).Return(preLoginResp, nil)
 				mockUsersClient.EXPECT().Login(gomock.Any(), req).Return(nil, status.Error(codes.Unavailable, "server unavailable"))
 			},
 			expectedErr: errors.New("server unavailable"),
@@ -81,15 +153,148 @@ func TestClientGRPC_Login(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
+			client.encryptionKey = nil
+			client.kdfUpgrade = false
 			tc.setupMock()
 			_, err := client.Login(context.Background(), "test", "1234")
 			if (err != nil && tc.expectedErr == nil) || (err == nil && tc.expectedErr != nil) || (err != nil && tc.expectedErr != nil && err.Error() != tc.expectedErr.Error()) {
 				t.Errorf("Expected error: %v, got: %v", tc.expectedErr, err)
 			}
+			if !bytes.Equal(client.GetEncryptionKey(), tc.expectedKey) {
+				t.Errorf("Expected encryption key %x, got %x", tc.expectedKey, client.GetEncryptionKey())
+			}
+			if client.KDFUpgradeRequired() != tc.expectUpgrade {
+				t.Errorf("Expected upgrade required: %v, got: %v", tc.expectUpgrade, client.KDFUpgradeRequired())
+			}
 		})
 	}
 }
 
+func TestClientGRPC_MigrateLegacyLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsersClient := mocks.NewMockUsersClient(ctrl)
+	client := &ClientGRPC{
+		UsersClient: mockUsersClient,
+		clientID:    42,
+		deviceName:  "laptop",
+	}
+	ctx := context.Background()
+
+	legacyKeys, err := crypto.DeriveKeys("1234", models.LegacyKDFParams("test"))
+	if err != nil {
+		t.Fatalf("Failed to derive keys: %v", err)
+	}
+
+	// Параметры старой схемы выводятся из логина: PreLogin их не сообщает.
+	mockUsersClient.EXPECT().Login(gomock.Any(), &proto.LoginRequest{
+		Login:          "test",
+		AuthHash:       legacyKeys.AuthHash,
+		LegacyPassword: "1234",
+		DeviceId:       42,
+		DeviceName:     "laptop",
+	}).Return(&proto.LoginResponse{AccessToken: "access_token"}, nil)
+
+	token, err := client.MigrateLegacyLogin(ctx, "test", "1234")
+	if err != nil || token != "access_token" {
+		t.Fatalf("Expected token, got %q, err = %v", token, err)
+	}
+	if !bytes.Equal(client.GetEncryptionKey(), legacyKeys.EncryptionKey) || !client.KDFUpgradeRequired() {
+		t.Error("Expected legacy keys and pending KDF upgrade")
+	}
+
+	mockUsersClient.EXPECT().Login(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.FailedPrecondition, "legacy account"))
+	if _, err = client.MigrateLegacyLogin(ctx, "test", "1234"); errors.Is(err, ErrLegacyMigrationRequired) || err == nil {
+		t.Errorf("Expected migration error to be reported as is, got %v", err)
+	}
+}
+
+func TestClientGRPC_LoginTOTP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsersClient := mocks.NewMockUsersClient(ctrl)
+	client := &ClientGRPC{
+		UsersClient: mockUsersClient,
+		clientID:    42,
+		deviceName:  "laptop",
+	}
+	ctx := context.Background()
+
+	keys, err := crypto.DeriveKeys("1234", testKDFParams())
+	if err != nil {
+		t.Fatalf("Failed to derive keys: %v", err)
+	}
+
+	if _, err = client.VerifyTOTP(ctx, "123456"); !errors.Is(err, ErrNoPendingLogin) {
+		t.Errorf("Expected ErrNoPendingLogin, got %v", err)
+	}
+
+	mockUsersClient.EXPECT().PreLogin(gomock.Any(), &proto.PreLoginRequest{Login: "test"}).
+		Return(&proto.PreLoginResponse{Kdf: converter.KDFParamsToProto(testKDFParams()), UpgradeRequired: true}, nil)
+	mockUsersClient.EXPECT().Login(gomock.Any(), gomock.Any()).
+		Return(&proto.LoginResponse{TotpRequired: true, TotpChallenge: "challenge"}, nil)
+
+	if _, err = client.Login(ctx, "test", "1234"); !errors.Is(err, ErrTOTPRequired) {
+		t.Fatalf("Expected ErrTOTPRequired, got %v", err)
+	}
+	if client.GetToken() != "" || client.GetEncryptionKey() != nil || client.GetLogin() != "" {
+		t.Error("Expected no token, key and login before the second factor is verified")
+	}
+
+	mockUsersClient.EXPECT().VerifyTOTP(gomock.Any(), &proto.VerifyTOTPRequest{Challenge: "challenge", Code: "000000"}).
+		Return(nil, status.Error(codes.Unauthenticated, "invalid authentication code"))
+	if _, err = client.VerifyTOTP(ctx, "000000"); err == nil || err.Error() != "failed to authenticate" {
+		t.Errorf("Expected authentication error, got %v", err)
+	}
+
+	mockUsersClient.EXPECT().VerifyTOTP(gomock.Any(), &proto.VerifyTOTPRequest{Challenge: "challenge", Code: "123456"}).
+		Return(&proto.VerifyTOTPResponse{AccessToken: "access", RefreshToken: "refresh"}, nil)
+	token, err := client.VerifyTOTP(ctx, "123456")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if token != "access" || client.refreshToken != "refresh" {
+		t.Errorf("Unexpected tokens %q, %q", token, client.refreshToken)
+	}
+	if !bytes.Equal(client.GetEncryptionKey(), keys.EncryptionKey) || !client.KDFUpgradeRequired() || client.GetLogin() != "test" {
+		t.Error("Expected keys and login from the first login step to be applied")
+	}
+	if _, err = client.VerifyTOTP(ctx, "123456"); !errors.Is(err, ErrNoPendingLogin) {
+		t.Errorf("Expected pending login to be cleared, got %v", err)
+	}
+}
+
+func TestClientGRPC_ManageTOTP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsersClient := mocks.NewMockUsersClient(ctrl)
+	client := &ClientGRPC{UsersClient: mockUsersClient}
+	ctx := context.Background()
+
+	mockUsersClient.EXPECT().EnableTOTP(gomock.Any(), &proto.EnableTOTPRequest{}).
+		Return(&proto.EnableTOTPResponse{Secret: "SECRET", Uri: "otpauth://totp/x"}, nil)
+	setup, err := client.EnableTOTP(ctx)
+	if err != nil || setup.Secret != "SECRET" || setup.URI != "otpauth://totp/x" {
+		t.Errorf("Unexpected setup %+v, error %v", setup, err)
+	}
+
+	mockUsersClient.EXPECT().ConfirmTOTP(gomock.Any(), &proto.ConfirmTOTPRequest{Code: "123456"}).
+		Return(&proto.ConfirmTOTPResponse{RecoveryCodes: []string{"abcde-fghij"}}, nil)
+	recoveryCodes, err := client.ConfirmTOTP(ctx, "123456")
+	if err != nil || len(recoveryCodes) != 1 {
+		t.Errorf("Unexpected recovery codes %v, error %v", recoveryCodes, err)
+	}
+
+	mockUsersClient.EXPECT().DisableTOTP(gomock.Any(), &proto.DisableTOTPRequest{Code: "abcde-fghij"}).
+		Return(nil, status.Error(codes.InvalidArgument, "invalid authentication code"))
+	if err = client.DisableTOTP(ctx, "abcde-fghij"); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %v", err)
+	}
+}
+
 func TestClientGRPC_Register(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -99,59 +304,600 @@ func TestClientGRPC_Register(t *testing.T) {
 		UsersClient: mockUsersClient,
 	}
 
-	tests := []struct {
-		name          string
-		login         string
-		password      string
-		setupMock     func(login, password string)
-		expectedErr   error
-		expectedToken string
-	}{
-		{
-			name:     "Register_Success",
-			login:    "new_user",
-			password: "password123",
-			setupMock: func(login, password string) {
-				req := &proto.RegisterRequest{
-					Login: login,
+	preRegisterResp := &proto.PreRegisterResponse{Kdf: converter.KDFParamsToProto(testKDFParams())}
+
+	tests := []struct {
+		name          string
+		login         string
+		password      string
+		setupMock     func(login, password string)
+		expectedErr   error
+		expectedToken string
+	}{
+		{
+			name:     "Register_Success",
+			login:    "new_user",
+			password: "password123",
+			setupMock: func(login, password string) {
+				keys, err := crypto.DeriveKeys(password, testKDFParams())
+				if err != nil {
+					t.Fatalf("Failed to derive keys: %v", err)
+				}
+				req := &proto.RegisterRequest{
+					Login:    login,
+					AuthHash: keys.AuthHash,
+					Kdf:      converter.KDFParamsToProto(testKDFParams()),
+				}
+				resp := &proto.RegisterResponse{
+					AccessToken: "new_access_token",
+				}
+				mockUsersClient.EXPECT().PreRegister(gomock.Any(), gomock.Any()).Return(preRegisterResp, nil)
+				mockUsersClient.EXPECT().Register(gomock.Any(), gomock.Eq(req)).Return(resp, nil)
+			},
+			expectedErr:   nil,
+			expectedToken: "new_access_token",
+		},
+		{
+			name:     "Register_Failed_AlreadyExists",
+			login:    "existing_user",
+			password: "password123",
+			setupMock: func(login, password string) {
+				keys, err := crypto.DeriveKeys(password, testKDFParams())
+				if err != nil {
+					t.Fatalf("Failed to derive keys: %v", err)
+				}
+				req := &proto.RegisterRequest{
+					Login:    login,
+					AuthHash: keys.AuthHash,
+					Kdf:      converter.KDFParamsToProto(testKDFParams()),
+				}
+				mockUsersClient.EXPECT().PreRegister(gomock.Any(), gomock.Any()).Return(preRegisterResp, nil)
+				mockUsersClient.EXPECT().Register(gomock.Any(), gomock.Eq(req)).Return(nil, status.Error(codes.AlreadyExists, "user already exists"))
+			},
+			expectedErr:   errors.New("user already exists"),
+			expectedToken: "",
+		},
+		{
+			name:     "Register_Failed_PreRegister",
+			login:    "new_user",
+			password: "password123",
+			setupMock: func(login, password string) {
+				mockUsersClient.EXPECT().PreRegister(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.Unavailable, "server unavailable"))
+			},
+			expectedErr:   errors.New("server unavailable"),
+			expectedToken: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMock(tc.login, tc.password)
+			token, err := client.Register(context.Background(), tc.login, tc.password)
+			if (err != nil && tc.expectedErr == nil) || (err == nil && tc.expectedErr != nil) || (err != nil && tc.expectedErr != nil && err.Error() != tc.expectedErr.Error()) {
+				t.Errorf("Expected error: %v, got: %v", tc.expectedErr, err)
+			}
+			if token != tc.expectedToken {
+				t.Errorf("Expected token: %s, got: %s", tc.expectedToken, token)
+			}
+		})
+	}
+}
+
+func TestClientGRPC_UpgradeKDF(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsersClient := mocks.NewMockUsersClient(ctrl)
+	client := &ClientGRPC{
+		UsersClient: mockUsersClient,
+		kdfUpgrade:  true,
+	}
+
+	keys := &crypto.Keys{AuthHash: "hash", EncryptionKey: []byte("new-key")}
+	req := &proto.UpgradeKDFRequest{
+		CurrentAuthHash: "current",
+		AuthHash:        "hash",
+		Kdf:             converter.KDFParamsToProto(testKDFParams()),
+		Secrets:         []*proto.SecretPayload{{Id: 1, Payload: []byte("payload"), Title: "title", SearchTokens: []string{"token"}}},
+		VaultKey:        []byte("vault"),
+	}
+
+	mockUsersClient.EXPECT().UpgradeKDF(gomock.Any(), gomock.Eq(req)).Return(nil, status.Error(codes.FailedPrecondition, "not all secrets"))
+	err := client.UpgradeKDF(context.Background(), "current", testKDFParams(), keys, []byte("vault"), map[uint64]*models.Secret{1: {Payload: []byte("payload"), Title: "title", SearchTokens: []string{"token"}}})
+	if err == nil || !client.KDFUpgradeRequired() || client.GetEncryptionKey() != nil {
+		t.Errorf("Expected failed upgrade to keep previous state, got err: %v", err)
+	}
+
+	mockUsersClient.EXPECT().UpgradeKDF(gomock.Any(), gomock.Eq(req)).Return(&proto.UpgradeKDFResponse{}, nil)
+	err = client.UpgradeKDF(context.Background(), "current", testKDFParams(), keys, []byte("vault"), map[uint64]*models.Secret{1: {Payload: []byte("payload"), Title: "title", SearchTokens: []string{"token"}}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if client.KDFUpgradeRequired() || !bytes.Equal(client.GetEncryptionKey(), keys.EncryptionKey) {
+		t.Error("Expected client to switch to the new encryption key")
+	}
+}
+
+func TestClientGRPC_ChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsersClient := mocks.NewMockUsersClient(ctrl)
+	client := &ClientGRPC{
+		UsersClient:   mockUsersClient,
+		encryptionKey: []byte("old-key"),
+	}
+
+	keys := &crypto.Keys{AuthHash: "new-hash", EncryptionKey: []byte("new-key")}
+	payloads := map[uint64]*models.Secret{1: {Payload: []byte("payload"), Title: "title", SearchTokens: []string{"token"}}}
+	req := &proto.ChangePasswordRequest{
+		CurrentAuthHash: "current-hash",
+		AuthHash:        "new-hash",
+		Kdf:             converter.KDFParamsToProto(testKDFParams()),
+		Secrets:         []*proto.SecretPayload{{Id: 1, Payload: []byte("payload"), Title: "title", SearchTokens: []string{"token"}}},
+		VaultKey:        []byte("vault"),
+	}
+
+	mockUsersClient.EXPECT().ChangePassword(gomock.Any(), gomock.Eq(req)).Return(nil, status.Error(codes.PermissionDenied, "bad auth credentials"))
+	err := client.ChangePassword(context.Background(), "current-hash", testKDFParams(), keys, []byte("vault"), payloads)
+	if !errors.Is(err, ErrWrongPassword) || !bytes.Equal(client.GetEncryptionKey(), []byte("old-key")) {
+		t.Errorf("Expected failed change to keep previous key, got err: %v", err)
+	}
+
+	mockUsersClient.EXPECT().ChangePassword(gomock.Any(), gomock.Eq(req)).Return(&proto.ChangePasswordResponse{}, nil)
+	err = client.ChangePassword(context.Background(), "current-hash", testKDFParams(), keys, []byte("vault"), payloads)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !bytes.Equal(client.GetEncryptionKey(), keys.EncryptionKey) || !reflect.DeepEqual(client.GetKDFParams(), testKDFParams()) {
+		t.Error("Expected client to switch to the new encryption key and kdf params")
+	}
+}
+
+func TestClientGRPC_GetVaultKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsersClient := mocks.NewMockUsersClient(ctrl)
+	client := &ClientGRPC{UsersClient: mockUsersClient}
+
+	mockUsersClient.EXPECT().GetVaultKey(gomock.Any(), gomock.Any()).Return(&proto.GetVaultKeyResponse{VaultKey: []byte("vault")}, nil)
+	vaultKey, err := client.GetVaultKey(context.Background())
+	if err != nil || !bytes.Equal(vaultKey, []byte("vault")) {
+		t.Errorf("Expected vault key, got %q, err: %v", vaultKey, err)
+	}
+
+	mockUsersClient.EXPECT().GetVaultKey(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.Internal, "internal error"))
+	if _, err = client.GetVaultKey(context.Background()); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestClientGRPC_CreateVaultKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsersClient := mocks.NewMockUsersClient(ctrl)
+	client := &ClientGRPC{UsersClient: mockUsersClient}
+
+	secrets := map[uint64]*models.Secret{1: {Payload: []byte("payload"), DataKey: []byte("wrapped")}}
+	req := &proto.CreateVaultKeyRequest{
+		VaultKey: []byte("vault"),
+		Secrets:  []*proto.SecretPayload{{Id: 1, Payload: []byte("payload"), DataKey: []byte("wrapped")}},
+	}
+
+	mockUsersClient.EXPECT().CreateVaultKey(gomock.Any(), gomock.Eq(req)).Return(&proto.CreateVaultKeyResponse{}, nil)
+	if err := client.CreateVaultKey(context.Background(), []byte("vault"), secrets); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	mockUsersClient.EXPECT().CreateVaultKey(gomock.Any(), gomock.Eq(req)).Return(nil, status.Error(codes.AlreadyExists, "vault key already exists"))
+	if err := client.CreateVaultKey(context.Background(), []byte("vault"), secrets); !errors.Is(err, ErrVaultKeyExists) {
+		t.Errorf("Expected ErrVaultKeyExists, got %v", err)
+	}
+
+	mockUsersClient.EXPECT().CreateVaultKey(gomock.Any(), gomock.Eq(req)).Return(nil, status.Error(codes.FailedPrecondition, "not all secrets"))
+	if err := client.CreateVaultKey(context.Background(), []byte("vault"), secrets); err == nil || errors.Is(err, ErrVaultKeyExists) {
+		t.Errorf("Expected generic error, got %v", err)
+	}
+}
+
+func TestClientGRPC_KeyPair(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsersClient := mocks.NewMockUsersClient(ctrl)
+	client := &ClientGRPC{UsersClient: mockUsersClient}
+
+	mockUsersClient.EXPECT().GetKeyPair(gomock.Any(), gomock.Any()).
+		Return(&proto.GetKeyPairResponse{PublicKey: []byte("public"), PrivateKey: []byte("private")}, nil)
+	keyPair, err := client.GetKeyPair(context.Background())
+	if err != nil || !bytes.Equal(keyPair.PublicKey, []byte("public")) || !bytes.Equal(keyPair.PrivateKey, []byte("private")) {
+		t.Errorf("Expected key pair, got %+v, err: %v", keyPair, err)
+	}
+
+	req := &proto.CreateKeyPairRequest{PublicKey: []byte("public"), PrivateKey: []byte("private")}
+	mockUsersClient.EXPECT().CreateKeyPair(gomock.Any(), gomock.Eq(req)).Return(nil, status.Error(codes.AlreadyExists, "key pair already exists"))
+	if err = client.CreateKeyPair(context.Background(), keyPair); !errors.Is(err, ErrKeyPairExists) {
+		t.Errorf("Expected ErrKeyPairExists, got %v", err)
+	}
+}
+
+func TestClientGRPC_Shares(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSharesClient := mocks.NewMockSharesClient(ctrl)
+	client := &ClientGRPC{SharesClient: mockSharesClient}
+	ctx := context.Background()
+
+	mockSharesClient.EXPECT().GetPublicKey(gomock.Any(), &proto.GetPublicKeyRequest{Login: "bob"}).
+		Return(nil, status.Error(codes.NotFound, `public key of user "bob": not found`))
+	if _, err := client.GetPublicKey(ctx, "bob"); err == nil || err.Error() != `public key of user "bob": not found` {
+		t.Errorf("GetPublicKey() expected not found error, got %v", err)
+	}
+
+	mockSharesClient.EXPECT().ShareSecret(gomock.Any(), &proto.ShareSecretRequest{SecretId: 10, RecipientLogin: "bob", DataKey: []byte("wrapped"), CanEdit: true}).
+		Return(&proto.ShareSecretResponse{Share: &proto.Share{Id: 5, SecretId: 10, RecipientLogin: "bob", CanEdit: true}}, nil)
+	share, err := client.ShareSecret(ctx, &models.Share{SecretID: 10, RecipientLogin: "bob", DataKey: []byte("wrapped"), CanEdit: true})
+	if err != nil || share.ID != 5 || !share.CanEdit {
+		t.Errorf("ShareSecret() got share = %+v, err = %v", share, err)
+	}
+
+	mockSharesClient.EXPECT().ListShares(gomock.Any(), &proto.ListSharesRequest{SecretId: 10}).
+		Return(&proto.ListSharesResponse{Shares: []*proto.Share{{Id: 5, RecipientLogin: "bob"}}}, nil)
+	shares, err := client.ListShares(ctx, 10)
+	if err != nil || len(shares) != 1 || shares[0].RecipientLogin != "bob" {
+		t.Errorf("ListShares() got %+v, err = %v", shares, err)
+	}
+
+	mockSharesClient.EXPECT().ListSharedWithMe(gomock.Any(), gomock.Any()).Return(&proto.ListSharedWithMeResponse{
+		Secrets: []*proto.SharedSecret{{Secret: &proto.Secret{Id: 10, Title: "title"}, Share: &proto.Share{Id: 5, OwnerLogin: "alice"}}},
+	}, nil)
+	secrets, err := client.ListSharedWithMe(ctx)
+	if err != nil || len(secrets) != 1 || secrets[0].Share == nil || secrets[0].Share.OwnerLogin != "alice" {
+		t.Errorf("ListSharedWithMe() got %+v, err = %v", secrets, err)
+	}
+
+	mockSharesClient.EXPECT().RevokeShare(gomock.Any(), &proto.RevokeShareRequest{Id: 5}).Return(&emptypb.Empty{}, nil)
+	if err = client.RevokeShare(ctx, 5); err != nil {
+		t.Errorf("RevokeShare() unexpected error: %v", err)
+	}
+
+	mockSharesClient.EXPECT().UpdateSharedSecret(gomock.Any(), gomock.Any()).Return(&proto.UpdateSharedSecretResponse{Revision: 4}, nil)
+	revision, err := client.UpdateSharedSecret(ctx, &models.Secret{ID: 10, Revision: 3})
+	if err != nil || revision != 4 {
+		t.Errorf("UpdateSharedSecret() got revision = %d, err = %v", revision, err)
+	}
+
+	mockSharesClient.EXPECT().UpdateSharedSecret(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.PermissionDenied, "shared secret is read-only"))
+	if _, err = client.UpdateSharedSecret(ctx, &models.Secret{ID: 10, Revision: 3}); !errors.Is(err, gophKeeperErrors.ErrShareReadOnly) {
+		t.Errorf("UpdateSharedSecret() expected ErrShareReadOnly, got %v", err)
+	}
+}
+
+func TestClientGRPC_Organizations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrganizationsClient := mocks.NewMockOrganizationsClient(ctrl)
+	client := &ClientGRPC{OrganizationsClient: mockOrganizationsClient}
+	ctx := context.Background()
+
+	mockOrganizationsClient.EXPECT().CreateOrganization(gomock.Any(), &proto.CreateOrganizationRequest{Name: "Team", VaultKey: []byte("wrapped")}).
+		Return(&proto.CreateOrganizationResponse{Organization: &proto.Organization{Id: 7, Name: "Team", Role: proto.OrgRole_ORG_ROLE_OWNER}}, nil)
+	org, err := client.CreateOrganization(ctx, "Team", []byte("wrapped"))
+	if err != nil || org.ID != 7 || org.Role != models.OrgOwner {
+		t.Errorf("CreateOrganization() got org = %+v, err = %v", org, err)
+	}
+
+	mockOrganizationsClient.EXPECT().ListOrganizations(gomock.Any(), gomock.Any()).
+		Return(&proto.ListOrganizationsResponse{Organizations: []*proto.Organization{{Id: 7, Name: "Team", Role: proto.OrgRole_ORG_ROLE_VIEWER}}}, nil)
+	orgs, err := client.ListOrganizations(ctx)
+	if err != nil || len(orgs) != 1 || orgs[0].Role != models.OrgViewer {
+		t.Errorf("ListOrganizations() got %+v, err = %v", orgs, err)
+	}
+
+	mockOrganizationsClient.EXPECT().ListMembers(gomock.Any(), &proto.ListOrgMembersRequest{OrgId: 7}).
+		Return(nil, status.Error(codes.NotFound, "organization 7: not found"))
+	if _, err = client.ListOrgMembers(ctx, 7); err == nil || err.Error() != "organization 7: not found" {
+		t.Errorf("ListOrgMembers() expected not found error, got %v", err)
+	}
+
+	mockOrganizationsClient.EXPECT().AddMember(gomock.Any(), &proto.AddOrgMemberRequest{OrgId: 7, Login: "bob", Role: proto.OrgRole_ORG_ROLE_EDITOR, VaultKey: []byte("wrapped")}).
+		Return(&proto.AddOrgMemberResponse{Member: &proto.OrgMember{UserId: 2, Login: "bob", Role: proto.OrgRole_ORG_ROLE_EDITOR}}, nil)
+	member, err := client.AddOrgMember(ctx, &models.OrgMember{OrgID: 7, Login: "bob", Role: models.OrgEditor, VaultKey: []byte("wrapped")})
+	if err != nil || member.UserID != 2 || member.Role != models.OrgEditor {
+		t.Errorf("AddOrgMember() got member = %+v, err = %v", member, err)
+	}
+
+	mockOrganizationsClient.EXPECT().UpdateMemberRole(gomock.Any(), &proto.UpdateOrgMemberRoleRequest{OrgId: 7, UserId: 1, Role: proto.OrgRole_ORG_ROLE_VIEWER}).
+		Return(nil, status.Error(codes.FailedPrecondition, "organization must keep at least one owner"))
+	if err = client.UpdateOrgMemberRole(ctx, 7, 1, models.OrgViewer); !errors.Is(err, gophKeeperErrors.ErrLastOwner) {
+		t.Errorf("UpdateOrgMemberRole() expected ErrLastOwner, got %v", err)
+	}
+
+	mockOrganizationsClient.EXPECT().RemoveMember(gomock.Any(), &proto.RemoveOrgMemberRequest{OrgId: 7, UserId: 2}).Return(&emptypb.Empty{}, nil)
+	if err = client.RemoveOrgMember(ctx, 7, 2); err != nil {
+		t.Errorf("RemoveOrgMember() unexpected error: %v", err)
+	}
+
+	mockOrganizationsClient.EXPECT().DeleteOrganization(gomock.Any(), &proto.DeleteOrganizationRequest{OrgId: 7}).
+		Return(nil, status.Error(codes.PermissionDenied, "permission denied"))
+	if err = client.DeleteOrganization(ctx, 7); !errors.Is(err, gophKeeperErrors.ErrPermissionDenied) {
+		t.Errorf("DeleteOrganization() expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestWithOrganization(t *testing.T) {
+	ctx := WithOrganization(context.Background(), 7)
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if values := md.Get(consts.OrganizationIDHeader); len(values) != 1 || values[0] != "7" {
+		t.Errorf("Expected organization header 7, got %v", values)
+	}
+}
+
+func TestWithShare(t *testing.T) {
+	ctx := WithShare(context.Background(), 5)
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if values := md.Get(consts.ShareIDHeader); len(values) != 1 || values[0] != "5" {
+		t.Errorf("Expected share header 5, got %v", values)
+	}
+}
+
+func TestClientGRPC_Emergency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEmergencyClient := mocks.NewMockEmergencyClient(ctrl)
+	client := &ClientGRPC{EmergencyClient: mockEmergencyClient}
+	ctx := context.Background()
+
+	mockEmergencyClient.EXPECT().AddContact(gomock.Any(), &proto.AddEmergencyContactRequest{GranteeLogin: "bob", WaitDays: 7, VaultKey: []byte("wrapped")}).
+		Return(&proto.AddEmergencyContactResponse{Access: &proto.EmergencyAccess{Id: 5, GranteeLogin: "bob", WaitDays: 7, Status: proto.EmergencyStatus_EMERGENCY_STATUS_IDLE}}, nil)
+	access, err := client.AddEmergencyContact(ctx, &models.EmergencyAccess{GranteeLogin: "bob", WaitDays: 7, VaultKey: []byte("wrapped")})
+	if err != nil || access.ID != 5 || access.Status != models.EmergencyIdle {
+		t.Errorf("AddEmergencyContact() got access = %+v, err = %v", access, err)
+	}
+
+	mockEmergencyClient.EXPECT().ListContacts(gomock.Any(), gomock.Any()).
+		Return(nil, status.Error(codes.Unavailable, "server unavailable"))
+	if _, err = client.ListEmergencyContacts(ctx); err == nil {
+		t.Error("ListEmergencyContacts() expected error, got nil")
+	}
+
+	mockEmergencyClient.EXPECT().ListGrants(gomock.Any(), gomock.Any()).
+		Return(&proto.ListEmergencyAccessResponse{Accesses: []*proto.EmergencyAccess{{Id: 5, GrantorLogin: "alice", Status: proto.EmergencyStatus_EMERGENCY_STATUS_GRANTED, VaultKey: []byte("wrapped")}}}, nil)
+	grants, err := client.ListEmergencyGrants(ctx)
+	if err != nil || len(grants) != 1 || grants[0].Status != models.EmergencyGranted || string(grants[0].VaultKey) != "wrapped" {
+		t.Errorf("ListEmergencyGrants() got %+v, err = %v", grants, err)
+	}
+
+	mockEmergencyClient.EXPECT().RequestAccess(gomock.Any(), &proto.EmergencyAccessRequest{Id: 5}).
+		Return(nil, status.Error(codes.FailedPrecondition, "emergency access is not in a suitable state"))
+	if err = client.RequestEmergencyAccess(ctx, 5); !errors.Is(err, gophKeeperErrors.ErrEmergencyState) {
+		t.Errorf("RequestEmergencyAccess() expected ErrEmergencyState, got %v", err)
+	}
+
+	mockEmergencyClient.EXPECT().RejectAccess(gomock.Any(), &proto.EmergencyAccessRequest{Id: 5}).
+		Return(nil, status.Error(codes.NotFound, "emergency access not found (id=5)"))
+	if err = client.RejectEmergencyAccess(ctx, 5); err == nil || err.Error() != "emergency access not found (id=5)" {
+		t.Errorf("RejectEmergencyAccess() expected not found error, got %v", err)
+	}
+
+	mockEmergencyClient.EXPECT().DeleteAccess(gomock.Any(), &proto.EmergencyAccessRequest{Id: 5}).Return(&emptypb.Empty{}, nil)
+	if err = client.DeleteEmergencyAccess(ctx, 5); err != nil {
+		t.Errorf("DeleteEmergencyAccess() unexpected error: %v", err)
+	}
+}
+
+func TestWithEmergencyAccess(t *testing.T) {
+	ctx := WithEmergencyAccess(context.Background(), 5)
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if values := md.Get(consts.EmergencyAccessIDHeader); len(values) != 1 || values[0] != "5" {
+		t.Errorf("Expected emergency access header 5, got %v", values)
+	}
+}
+
+func TestClientGRPC_DeleteAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	keys, err := crypto.DeriveKeys("1234", testKDFParams())
+	if err != nil {
+		t.Fatalf("Failed to derive keys: %v", err)
+	}
+
+	mockUsersClient := mocks.NewMockUsersClient(ctrl)
+	client := &ClientGRPC{
+		UsersClient:   mockUsersClient,
+		accessToken:   "access",
+		login:         "test",
+		encryptionKey: keys.EncryptionKey,
+		kdfParams:     testKDFParams(),
+	}
+
+	if err = client.DeleteAccount(context.Background(), "wrong"); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("Expected ErrWrongPassword for a wrong password, got %v", err)
+	}
+
+	mockUsersClient.EXPECT().DeleteAccount(gomock.Any(), &proto.DeleteAccountRequest{AuthHash: keys.AuthHash}).
+		Return(nil, status.Error(codes.PermissionDenied, "bad auth credentials"))
+	if err = client.DeleteAccount(context.Background(), "1234"); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("Expected ErrWrongPassword when the server rejects the hash, got %v", err)
+	}
+
+	mockUsersClient.EXPECT().DeleteAccount(gomock.Any(), &proto.DeleteAccountRequest{AuthHash: keys.AuthHash}).
+		Return(nil, status.Error(codes.FailedPrecondition, "failed to delete account: organization Team: organization must keep at least one owner"))
+	if err = client.DeleteAccount(context.Background(), "1234"); err == nil || !strings.Contains(err.Error(), "organization Team") {
+		t.Errorf("Expected the organization to be named when deletion is blocked, got %v", err)
+	}
+
+	mockUsersClient.EXPECT().DeleteAccount(gomock.Any(), &proto.DeleteAccountRequest{AuthHash: keys.AuthHash}).
+		Return(&proto.DeleteAccountResponse{}, nil)
+	if err = client.DeleteAccount(context.Background(), "1234"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if client.GetToken() != "" || client.GetEncryptionKey() != nil || client.GetKDFParams() != nil || client.GetLogin() != "" {
+		t.Error("Expected tokens, keys and login to be cleared after account deletion")
+	}
+
+	if err = client.DeleteAccount(context.Background(), "1234"); !errors.Is(err, ErrNotLoggedIn) {
+		t.Errorf("Expected ErrNotLoggedIn after deletion, got %v", err)
+	}
+}
+
+// fakeNotificationClient возвращает заранее подготовленный поток уведомлений.
+type fakeNotificationClient struct {
+	stream *fakeNotificationStream
+}
+
+func (f *fakeNotificationClient) Subscribe(_ context.Context, _ *proto.SubscribeRequest, _ ...grpc.CallOption) (grpc.ServerStreamingClient[proto.SubscribeResponse], error) {
+	return f.stream, nil
+}
+
+// fakeNotificationStream отдаёт события из списка, а после его окончания - ошибку Unavailable.
+type fakeNotificationStream struct {
+	grpc.ClientStream
+	events []*proto.SubscribeResponse
+}
+
+func (f *fakeNotificationStream) Recv() (*proto.SubscribeResponse, error) {
+	if len(f.events) == 0 {
+		return nil, status.Error(codes.Unavailable, "stream closed")
+	}
+	event := f.events[0]
+	f.events = f.events[1:]
+	return event, nil
+}
+
+func TestClientGRPC_Notifications_SessionEnded(t *testing.T) {
+	for _, event := range []proto.EventType{proto.EventType_EVENT_TYPE_PASSWORD_CHANGED, proto.EventType_EVENT_TYPE_ACCOUNT_DELETED} {
+		t.Run(event.String(), func(t *testing.T) {
+			stream := &fakeNotificationStream{events: []*proto.SubscribeResponse{{Event: event}}}
+			client := &ClientGRPC{
+				notifyClient:  &fakeNotificationClient{stream: stream},
+				accessToken:   "access",
+				refreshToken:  "refresh",
+				encryptionKey: []byte("key"),
+			}
+
+			done := make(chan struct{})
+			go func() {
+				client.Notifications(nil, zap.NewNop())
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("Expected notification loop to stop after the session has ended")
+			}
+			if client.GetToken() != "" || client.refreshToken != "" || client.GetEncryptionKey() != nil {
+				t.Error("Expected tokens and encryption key to be cleared")
+			}
+		})
+	}
+}
+
+func TestSessionEndedMsg(t *testing.T) {
+	if _, ok := sessionEndedMsg(proto.EventType_EVENT_TYPE_PASSWORD_CHANGED).(PasswordChanged); !ok {
+		t.Error("Expected PasswordChanged message")
+	}
+	if _, ok := sessionEndedMsg(proto.EventType_EVENT_TYPE_ACCOUNT_DELETED).(AccountDeleted); !ok {
+		t.Error("Expected AccountDeleted message")
+	}
+	if msg := sessionEndedMsg(proto.EventType_EVENT_TYPE_SECRET_CREATED); msg != nil {
+		t.Errorf("Expected no message for secret events, got %T", msg)
+	}
+}
+
+func TestClientGRPC_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsersClient := mocks.NewMockUsersClient(ctrl)
+	client := &ClientGRPC{
+		UsersClient:  mockUsersClient,
+		accessToken:  "old_access",
+		refreshToken: "old_refresh",
+	}
+
+	mockUsersClient.EXPECT().
+		RefreshToken(gomock.Any(), &proto.RefreshTokenRequest{RefreshToken: "old_refresh"}).
+		Return(&proto.RefreshTokenResponse{AccessToken: "new_access", RefreshToken: "new_refresh"}, nil).
+		Times(1)
+
+	if err := client.refresh(context.Background(), "old_access"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if client.GetToken() != "new_access" || client.refreshToken != "new_refresh" {
+		t.Errorf("Expected rotated tokens, got %s / %s", client.GetToken(), client.refreshToken)
+	}
+
+	// Токен уже обновлён другим вызовом: повторного обмена быть не должно.
+	if err := client.refresh(context.Background(), "old_access"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	mockUsersClient.EXPECT().
+		RefreshToken(gomock.Any(), &proto.RefreshTokenRequest{RefreshToken: "new_refresh"}).
+		Return(nil, status.Error(codes.Unauthenticated, "invalid refresh token")).
+		Times(1)
+
+	err := client.refresh(context.Background(), "new_access")
+	if err == nil || err.Error() != "failed to authenticate" {
+		t.Errorf("Expected authentication error, got %v", err)
+	}
+
+	client = &ClientGRPC{UsersClient: mockUsersClient}
+	if err = client.refresh(context.Background(), ""); !errors.Is(err, ErrNoRefreshToken) {
+		t.Errorf("Expected ErrNoRefreshToken, got %v", err)
+	}
+}
+
+func TestClientGRPC_Sessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsersClient := mocks.NewMockUsersClient(ctrl)
+	client := &ClientGRPC{
+		UsersClient:  mockUsersClient,
+		accessToken:  "access",
+		refreshToken: "refresh",
+	}
+	ctx := context.Background()
+
+	mockUsersClient.EXPECT().ListSessions(gomock.Any(), &proto.ListSessionsRequest{}).Return(&proto.ListSessionsResponse{
+		Sessions: []*proto.DeviceSession{{Id: 7, DeviceId: 42, DeviceName: "laptop", Current: true}},
+	}, nil)
+	sessions, err := client.ListSessions(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].DeviceName != "laptop" || !sessions[0].Current {
+		t.Errorf("Unexpected sessions %+v", sessions)
+	}
+
+	mockUsersClient.EXPECT().RenameSession(gomock.Any(), &proto.RenameSessionRequest{Id: 7, DeviceName: "work"}).Return(&proto.RenameSessionResponse{}, nil)
+	if err = client.RenameSession(ctx, 7, "work"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
 
-					Password: password,
-				}
-				resp := &proto.RegisterResponse{
-					AccessToken: "new_access_token",
-				}
-				mockUsersClient.EXPECT().Register(gomock.Any(), gomock.Eq(req)).Return(resp, nil)
-			},
-			expectedErr:   nil,
-			expectedToken: "new_access_token",
-		},
-		{
-			name:     "Register_Failed_AlreadyExists",
-			login:    "existing_user",
-			password: "password123",
-			setupMock: func(login, password string) {
-				req := &proto.RegisterRequest{
-					Login:    login,
-					Password: password,
-				}
-				mockUsersClient.EXPECT().Register(gomock.Any(), gomock.Eq(req)).Return(nil, status.Error(codes.AlreadyExists, "user already exists"))
-			},
-			expectedErr:   errors.New("user already exists"),
-			expectedToken: "",
-		},
+	mockUsersClient.EXPECT().RevokeSession(gomock.Any(), &proto.RevokeSessionRequest{Id: 8}).Return(nil, status.Error(codes.NotFound, "not found"))
+	if err = client.RevokeSession(ctx, 8); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
 	}
 
-	for _, tc := range tests {
-		t.Run(tc.name, func(t *testing.T) {
-			tc.setupMock(tc.login, tc.password)
-			token, err := client.Register(context.Background(), tc.login, tc.password)
-			if (err != nil && tc.expectedErr == nil) || (err == nil && tc.expectedErr != nil) || (err != nil && tc.expectedErr != nil && err.Error() != tc.expectedErr.Error()) {
-				t.Errorf("Expected error: %v, got: %v", tc.expectedErr, err)
-			}
-			if token != tc.expectedToken {
-				t.Errorf("Expected token: %s, got: %s", tc.expectedToken, token)
-			}
-		})
+	mockUsersClient.EXPECT().LogoutAll(gomock.Any(), &proto.LogoutAllRequest{}).Return(&proto.LogoutAllResponse{}, nil)
+	if err = client.LogoutAll(ctx); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if client.GetToken() != "" || client.refreshToken != "" {
+		t.Error("Expected tokens to be cleared after logout")
 	}
 }
 
@@ -182,7 +928,7 @@ func TestClientGRPC_LoadSecrets(t *testing.T) {
 			name: "Load_Secrets_Success",
 			setupMock: func() {
 				resp := &proto.GetUserSecretsResponse{Secrets: protoSecrets}
-				mockSecretsClient.EXPECT().GetUserSecrets(gomock.Any(), &emptypb.Empty{}).Return(resp, nil)
+				mockSecretsClient.EXPECT().GetUserSecrets(gomock.Any(), &proto.GetUserSecretsRequest{}).Return(resp, nil)
 			},
 			expectedErr: "",
 			expected:    testSecrets,
@@ -190,7 +936,7 @@ func TestClientGRPC_LoadSecrets(t *testing.T) {
 		{
 			name: "Load_Secrets_Failed",
 			setupMock: func() {
-				mockSecretsClient.EXPECT().GetUserSecrets(gomock.Any(), &emptypb.Empty{}).Return(nil, status.Error(codes.Internal, "internal error"))
+				mockSecretsClient.EXPECT().GetUserSecrets(gomock.Any(), &proto.GetUserSecretsRequest{}).Return(nil, status.Error(codes.Internal, "internal error"))
 			},
 			expectedErr: "internal error",
 			expected:    nil,
@@ -208,6 +954,84 @@ func TestClientGRPC_LoadSecrets(t *testing.T) {
 	}
 }
 
+func TestClientGRPC_SyncSecrets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSecretsClient := mocks.NewMockSecretsClient(ctrl)
+	client := &ClientGRPC{
+		SecretsClient: mockSecretsClient,
+	}
+
+	t.Run("Sync_Secrets_Success", func(t *testing.T) {
+		mockSecretsClient.EXPECT().SyncSecrets(gomock.Any(), &proto.SyncSecretsRequest{SinceRevision: 3}).Return(&proto.SyncSecretsResponse{
+			Secrets:    []*proto.Secret{{Id: 1, Title: "Changed", SecretType: proto.SecretType_SECRET_TYPE_TEXT}},
+			DeletedIds: []uint64{2},
+			Revision:   5,
+		}, nil)
+
+		delta, err := client.SyncSecrets(context.Background(), 3)
+		if err != nil {
+			t.Fatalf("SyncSecrets() unexpected error: %v", err)
+		}
+		if len(delta.Secrets) != 1 || delta.Secrets[0].Title != "Changed" {
+			t.Errorf("SyncSecrets() got secrets = %v, want one changed secret", delta.Secrets)
+		}
+		if len(delta.DeletedIDs) != 1 || delta.DeletedIDs[0] != 2 || delta.Revision != 5 || delta.Full {
+			t.Errorf("SyncSecrets() got delta = %+v, want deleted [2] at revision 5", delta)
+		}
+	})
+
+	t.Run("Sync_Secrets_Failed", func(t *testing.T) {
+		mockSecretsClient.EXPECT().SyncSecrets(gomock.Any(), &proto.SyncSecretsRequest{}).Return(nil, status.Error(codes.Internal, "internal error"))
+
+		delta, err := client.SyncSecrets(context.Background(), 0)
+		if delta != nil || !compareErrors(err, "internal error") {
+			t.Errorf("SyncSecrets() got delta = %v, err = %v, want error 'internal error'", delta, err)
+		}
+	})
+}
+
+func TestClientGRPC_ListSecrets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSecretsClient := mocks.NewMockSecretsClient(ctrl)
+	client := &ClientGRPC{
+		SecretsClient: mockSecretsClient,
+	}
+
+	t.Run("List_Secrets_Success", func(t *testing.T) {
+		request := &proto.ListSecretsRequest{
+			PageToken:  "page",
+			SecretType: proto.SecretType_SECRET_TYPE_TEXT,
+			Order:      proto.SecretOrder_SECRET_ORDER_TITLE_ASC,
+		}
+		mockSecretsClient.EXPECT().ListSecrets(gomock.Any(), request).Return(&proto.ListSecretsResponse{
+			Secrets:       []*proto.Secret{{Id: 1, Title: "Note", SecretType: proto.SecretType_SECRET_TYPE_TEXT}},
+			NextPageToken: "next",
+		}, nil)
+
+		query := &models.SecretListQuery{PageToken: "page", SecretType: string(models.TextSecret), Order: models.SecretOrderTitleAsc}
+		page, err := client.ListSecrets(context.Background(), query)
+		if err != nil {
+			t.Fatalf("ListSecrets() unexpected error: %v", err)
+		}
+		if len(page.Secrets) != 1 || page.Secrets[0].Title != "Note" || page.NextPageToken != "next" {
+			t.Errorf("ListSecrets() got page = %+v, want one secret and next page token", page)
+		}
+	})
+
+	t.Run("List_Secrets_Failed", func(t *testing.T) {
+		mockSecretsClient.EXPECT().ListSecrets(gomock.Any(), &proto.ListSecretsRequest{}).Return(nil, status.Error(codes.InvalidArgument, "invalid page token"))
+
+		page, err := client.ListSecrets(context.Background(), &models.SecretListQuery{})
+		if page != nil || !compareErrors(err, "invalid page token") {
+			t.Errorf("ListSecrets() got page = %v, err = %v, want error 'invalid page token'", page, err)
+		}
+	})
+}
+
 func compareSecrets(got, want []*models.Secret) bool {
 	if len(got) != len(want) {
 		return false
@@ -256,6 +1080,9 @@ func TestClientGRPC_LoadSecret(t *testing.T) {
 		Payload:    []byte("Test Payload"),
 		CreatedAt:  fixedTime,
 		UpdatedAt:  fixedTime,
+		BlobID:     "0123456789abcdef0123456789abcdef",
+		FolderID:   2,
+		TagIDs:     models.IDList{3, 4},
 	}
 
 	protoSecret := &proto.Secret{
@@ -266,6 +1093,9 @@ func TestClientGRPC_LoadSecret(t *testing.T) {
 		Payload:    []byte("Test Payload"),
 		CreatedAt:  timestamppb.New(fixedTime),
 		UpdatedAt:  timestamppb.New(fixedTime),
+		BlobId:     "0123456789abcdef0123456789abcdef",
+		FolderId:   2,
+		TagIds:     []uint64{3, 4},
 	}
 
 	tests := []struct {
@@ -323,23 +1153,27 @@ func TestClientGRPC_SaveSecret(t *testing.T) {
 	fixedTime := time.Date(2025, time.January, 15, 10, 49, 5, 278598, time.UTC)
 
 	testSecret := &models.Secret{
-		ID:         1,
-		Title:      "Test Secret",
-		Metadata:   "Test Metadata",
-		SecretType: "text",
-		Payload:    []byte("Test Payload"),
-		CreatedAt:  fixedTime,
-		UpdatedAt:  fixedTime,
+		ID:              1,
+		Title:           "Test Secret",
+		Metadata:        "Test Metadata",
+		SecretType:      "text",
+		Payload:         []byte("Test Payload"),
+		CreatedAt:       fixedTime,
+		UpdatedAt:       fixedTime,
+		LabelsEncrypted: true,
+		SearchTokens:    []string{"token"},
 	}
 
 	protoSecret := &proto.Secret{
-		Id:         1,
-		Title:      "Test Secret",
-		Metadata:   "Test Metadata",
-		SecretType: proto.SecretType_SECRET_TYPE_TEXT,
-		Payload:    []byte("Test Payload"),
-		CreatedAt:  timestamppb.New(fixedTime),
-		UpdatedAt:  timestamppb.New(fixedTime),
+		Id:              1,
+		Title:           "Test Secret",
+		Metadata:        "Test Metadata",
+		SecretType:      proto.SecretType_SECRET_TYPE_TEXT,
+		Payload:         []byte("Test Payload"),
+		CreatedAt:       timestamppb.New(fixedTime),
+		UpdatedAt:       timestamppb.New(fixedTime),
+		LabelsEncrypted: true,
+		SearchTokens:    []string{"token"},
 	}
 
 	tests := []struct {
@@ -434,6 +1268,162 @@ func TestClientGRPC_DeleteSecret(t *testing.T) {
 	}
 }
 
+func TestClientGRPC_SecretVersions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSecretsClient := mocks.NewMockSecretsClient(ctrl)
+	client := &ClientGRPC{
+		SecretsClient: mockSecretsClient,
+	}
+
+	archivedAt := time.Now().UTC()
+	mockSecretsClient.EXPECT().ListSecretVersions(gomock.Any(), &proto.ListSecretVersionsRequest{SecretId: 1}).Return(&proto.ListSecretVersionsResponse{
+		Versions: []*proto.SecretVersion{
+			{Id: 5, Secret: &proto.Secret{Id: 1, Title: "v2", SecretType: proto.SecretType_SECRET_TYPE_TEXT}, ArchivedAt: timestamppb.New(archivedAt)},
+		},
+	}, nil)
+	versions, err := client.LoadSecretVersions(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("LoadSecretVersions() unexpected error: %v", err)
+	}
+	if len(versions) != 1 || versions[0].VersionID != 5 || versions[0].ID != 1 || versions[0].Title != "v2" || !versions[0].ArchivedAt.Equal(archivedAt) {
+		t.Errorf("LoadSecretVersions() got %+v", versions)
+	}
+
+	mockSecretsClient.EXPECT().ListSecretVersions(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.NotFound, "secret not found (id=2)"))
+	if _, err = client.LoadSecretVersions(context.Background(), 2); !compareErrors(err, "secret not found (id=2)") {
+		t.Errorf("LoadSecretVersions() got err = %v", err)
+	}
+
+	mockSecretsClient.EXPECT().RestoreSecretVersion(gomock.Any(), &proto.RestoreSecretVersionRequest{SecretId: 1, VersionId: 5}).Return(&emptypb.Empty{}, nil)
+	if err = client.RestoreSecretVersion(context.Background(), 1, 5); err != nil {
+		t.Errorf("RestoreSecretVersion() unexpected error: %v", err)
+	}
+
+	mockSecretsClient.EXPECT().RestoreSecretVersion(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.NotFound, "secret version not found (id=1, version=9)"))
+	if err = client.RestoreSecretVersion(context.Background(), 1, 9); !compareErrors(err, "secret version not found (id=1, version=9)") {
+		t.Errorf("RestoreSecretVersion() got err = %v", err)
+	}
+}
+
+func TestClientGRPC_Trash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSecretsClient := mocks.NewMockSecretsClient(ctrl)
+	client := &ClientGRPC{
+		SecretsClient: mockSecretsClient,
+	}
+
+	deletedAt := time.Now().UTC()
+	mockSecretsClient.EXPECT().ListTrash(gomock.Any(), gomock.Any()).Return(&proto.ListTrashResponse{
+		Secrets: []*proto.Secret{
+			{Id: 1, Title: "old", SecretType: proto.SecretType_SECRET_TYPE_TEXT, DeletedAt: timestamppb.New(deletedAt)},
+		},
+	}, nil)
+
+	secrets, err := client.LoadTrash(context.Background())
+	if err != nil {
+		t.Fatalf("LoadTrash() unexpected error: %v", err)
+	}
+	if len(secrets) != 1 || secrets[0].ID != 1 || secrets[0].DeletedAt == nil || !secrets[0].DeletedAt.Equal(deletedAt) {
+		t.Errorf("LoadTrash() got %+v", secrets)
+	}
+
+	mockSecretsClient.EXPECT().ListTrash(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.Unavailable, "unavailable"))
+	if _, err = client.LoadTrash(context.Background()); !compareErrors(err, "server unavailable") {
+		t.Errorf("LoadTrash() got err = %v", err)
+	}
+
+	mockSecretsClient.EXPECT().RestoreSecret(gomock.Any(), &proto.RestoreSecretRequest{Id: 1}).Return(&emptypb.Empty{}, nil)
+	if err = client.RestoreSecret(context.Background(), 1); err != nil {
+		t.Errorf("RestoreSecret() unexpected error: %v", err)
+	}
+
+	mockSecretsClient.EXPECT().RestoreSecret(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.NotFound, "trashed secret not found (id=2)"))
+	if err = client.RestoreSecret(context.Background(), 2); !compareErrors(err, "trashed secret not found (id=2)") {
+		t.Errorf("RestoreSecret() got err = %v", err)
+	}
+
+	mockSecretsClient.EXPECT().PurgeSecret(gomock.Any(), &proto.PurgeSecretRequest{Id: 1}).Return(&emptypb.Empty{}, nil)
+	if err = client.PurgeSecret(context.Background(), 1); err != nil {
+		t.Errorf("PurgeSecret() unexpected error: %v", err)
+	}
+
+	mockSecretsClient.EXPECT().PurgeSecret(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.NotFound, "trashed secret not found (id=2)"))
+	if err = client.PurgeSecret(context.Background(), 2); !compareErrors(err, "trashed secret not found (id=2)") {
+		t.Errorf("PurgeSecret() got err = %v", err)
+	}
+}
+
+func TestClientGRPC_Folders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFoldersClient := mocks.NewMockFoldersClient(ctrl)
+	client := &ClientGRPC{
+		FoldersClient: mockFoldersClient,
+	}
+	ctx := context.Background()
+
+	mockFoldersClient.EXPECT().ListFolders(gomock.Any(), gomock.Any()).Return(&proto.ListFoldersResponse{
+		Folders: []*proto.Folder{{Id: 1, Name: "Banks"}, {Id: 2, ParentId: 1, Name: "Cards"}},
+	}, nil)
+	folders, err := client.ListFolders(ctx)
+	if err != nil {
+		t.Fatalf("ListFolders() unexpected error: %v", err)
+	}
+	if len(folders) != 2 || folders[1].ParentID != 1 || folders[1].Name != "Cards" {
+		t.Errorf("ListFolders() got %+v", folders)
+	}
+
+	mockFoldersClient.EXPECT().CreateFolder(gomock.Any(), &proto.CreateFolderRequest{ParentId: 1, Name: "Cards"}).
+		Return(&proto.CreateFolderResponse{Folder: &proto.Folder{Id: 2, ParentId: 1, Name: "Cards"}}, nil)
+	folder, err := client.CreateFolder(ctx, 1, "Cards")
+	if err != nil || folder.ID != 2 {
+		t.Errorf("CreateFolder() got folder = %+v, err = %v", folder, err)
+	}
+
+	mockFoldersClient.EXPECT().CreateFolder(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.AlreadyExists, "already exists"))
+	if _, err = client.CreateFolder(ctx, 1, "Cards"); !compareErrors(err, "folder or tag with this name already exists") {
+		t.Errorf("CreateFolder() got err = %v", err)
+	}
+
+	mockFoldersClient.EXPECT().UpdateFolder(gomock.Any(), &proto.UpdateFolderRequest{Id: 1, ParentId: 2, Name: "Banks"}).
+		Return(nil, status.Error(codes.InvalidArgument, "folder cannot be moved into itself or its subfolder"))
+	if err = client.UpdateFolder(ctx, 1, 2, "Banks"); !compareErrors(err, "folder cannot be moved into itself or its subfolder") {
+		t.Errorf("UpdateFolder() got err = %v", err)
+	}
+
+	mockFoldersClient.EXPECT().DeleteFolder(gomock.Any(), &proto.DeleteFolderRequest{Id: 1}).Return(&emptypb.Empty{}, nil)
+	if err = client.DeleteFolder(ctx, 1); err != nil {
+		t.Errorf("DeleteFolder() unexpected error: %v", err)
+	}
+
+	mockFoldersClient.EXPECT().ListTags(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.Unavailable, "unavailable"))
+	if _, err = client.ListTags(ctx); !compareErrors(err, "server unavailable") {
+		t.Errorf("ListTags() got err = %v", err)
+	}
+
+	mockFoldersClient.EXPECT().CreateTag(gomock.Any(), &proto.CreateTagRequest{Name: "work"}).
+		Return(&proto.CreateTagResponse{Tag: &proto.Tag{Id: 3, Name: "work"}}, nil)
+	tag, err := client.CreateTag(ctx, "work")
+	if err != nil || tag.ID != 3 {
+		t.Errorf("CreateTag() got tag = %+v, err = %v", tag, err)
+	}
+
+	mockFoldersClient.EXPECT().UpdateTag(gomock.Any(), &proto.UpdateTagRequest{Id: 3, Name: "home"}).Return(&emptypb.Empty{}, nil)
+	if err = client.UpdateTag(ctx, 3, "home"); err != nil {
+		t.Errorf("UpdateTag() unexpected error: %v", err)
+	}
+
+	mockFoldersClient.EXPECT().DeleteTag(gomock.Any(), &proto.DeleteTagRequest{Id: 4}).Return(nil, status.Error(codes.NotFound, "tag not found (id=4)"))
+	if err = client.DeleteTag(ctx, 4); !compareErrors(err, "tag not found (id=4)") {
+		t.Errorf("DeleteTag() got err = %v", err)
+	}
+}
+
 func TestTokenAndPasswordSetGet(t *testing.T) {
 	client := &ClientGRPC{}
 
@@ -481,6 +1471,26 @@ func TestParseError(t *testing.T) {
 			inputError:  status.Error(codes.AlreadyExists, "item already exists"),
 			expectedErr: errors.New("user already exists"),
 		},
+		{
+			name:        "gRPC_ResourceExhausted_error",
+			inputError:  lockoutError(t, 30*time.Second),
+			expectedErr: errors.New("too many login attempts, retry in 30s"),
+		},
+		{
+			name:        "gRPC_ResourceExhausted_without_retry_info",
+			inputError:  status.Error(codes.ResourceExhausted, "too many failed login attempts"),
+			expectedErr: errors.New("too many login attempts, retry later"),
+		},
+		{
+			name:        "gRPC_Aborted_revision_conflict",
+			inputError:  conflictError(t, "5"),
+			expectedErr: &gophKeeperErrors.RevisionConflictError{Current: 5},
+		},
+		{
+			name:        "gRPC_Aborted_without_revision",
+			inputError:  conflictError(t, "bad"),
+			expectedErr: status.Error(codes.Aborted, "secret was modified concurrently"),
+		},
 		{
 			name:        "gRPC_Internal_error",
 			inputError:  status.Error(codes.Internal, "internal server error"),
@@ -498,6 +1508,27 @@ func TestParseError(t *testing.T) {
 	}
 }
 
+func lockoutError(t *testing.T, retryAfter time.Duration) error {
+	st, err := status.New(codes.ResourceExhausted, "too many failed login attempts").
+		WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+	if err != nil {
+		t.Fatalf("Failed to attach retry info: %v", err)
+	}
+	return st.Err()
+}
+
+func conflictError(t *testing.T, revision string) error {
+	st, err := status.New(codes.Aborted, "secret was modified concurrently").
+		WithDetails(&errdetails.ErrorInfo{
+			Reason:   consts.RevisionConflictReason,
+			Metadata: map[string]string{consts.CurrentRevisionKey: revision},
+		})
+	if err != nil {
+		t.Fatalf("Failed to attach error info: %v", err)
+	}
+	return st.Err()
+}
+
 func errorEqual(a, b error) bool {
 	if errors.Is(a, b) {
 		return true
@@ -507,3 +1538,171 @@ func errorEqual(a, b error) bool {
 	}
 	return a.Error() == b.Error()
 }
+
+// fakeBlobsClient хранит загруженные фрагменты в памяти и отдаёт их при скачивании.
+type fakeBlobsClient struct {
+	chunks    map[uint32][]byte
+	count     uint32
+	sendErrAt int
+	statusErr error
+}
+
+func (f *fakeBlobsClient) GetBlobStatus(_ context.Context, _ *proto.GetBlobStatusRequest, _ ...grpc.CallOption) (*proto.GetBlobStatusResponse, error) {
+	if f.statusErr != nil {
+		return nil, f.statusErr
+	}
+	return &proto.GetBlobStatusResponse{ChunkCount: f.count, ReceivedChunks: uint32(len(f.chunks))}, nil
+}
+
+func (f *fakeBlobsClient) UploadBlob(_ context.Context, _ ...grpc.CallOption) (grpc.ClientStreamingClient[proto.UploadBlobRequest, proto.UploadBlobResponse], error) {
+	return &fakeUploadStream{client: f}, nil
+}
+
+func (f *fakeBlobsClient) DownloadBlob(_ context.Context, in *proto.DownloadBlobRequest, _ ...grpc.CallOption) (grpc.ServerStreamingClient[proto.DownloadBlobResponse], error) {
+	return &fakeDownloadStream{client: f, next: in.FromChunk}, nil
+}
+
+// fakeUploadStream сохраняет отправленные фрагменты в fakeBlobsClient и обрывается на фрагменте sendErrAt.
+type fakeUploadStream struct {
+	grpc.ClientStream
+	client *fakeBlobsClient
+	sent   int
+}
+
+func (f *fakeUploadStream) Send(req *proto.UploadBlobRequest) error {
+	f.sent++
+	if f.sent == f.client.sendErrAt {
+		return io.EOF
+	}
+	f.client.count = req.ChunkCount
+	f.client.chunks[req.ChunkIndex] = req.Data
+	return nil
+}
+
+func (f *fakeUploadStream) CloseAndRecv() (*proto.UploadBlobResponse, error) {
+	if f.client.sendErrAt > 0 && f.sent >= f.client.sendErrAt {
+		return nil, status.Error(codes.Unavailable, "connection reset")
+	}
+	return &proto.UploadBlobResponse{ReceivedChunks: uint32(len(f.client.chunks))}, nil
+}
+
+func (f *fakeUploadStream) CloseSend() error {
+	return nil
+}
+
+// fakeDownloadStream отдаёт сохранённые в fakeBlobsClient фрагменты начиная с next.
+type fakeDownloadStream struct {
+	grpc.ClientStream
+	client *fakeBlobsClient
+	next   uint32
+}
+
+func (f *fakeDownloadStream) Recv() (*proto.DownloadBlobResponse, error) {
+	if f.next >= f.client.count {
+		return nil, io.EOF
+	}
+	data, ok := f.client.chunks[f.next]
+	if !ok {
+		return nil, status.Error(codes.FailedPrecondition, "blob upload is not complete")
+	}
+	resp := &proto.DownloadBlobResponse{ChunkIndex: f.next, ChunkCount: f.client.count, Data: data}
+	f.next++
+	return resp, nil
+}
+
+func TestClientGRPC_Blobs(t *testing.T) {
+	blobs := &fakeBlobsClient{chunks: map[uint32][]byte{}}
+	client := &ClientGRPC{BlobsClient: blobs}
+	ctx := context.Background()
+	chunk := func(index uint32) ([]byte, error) { return []byte{byte(index)}, nil }
+
+	blobs.sendErrAt = 2
+	if _, err := client.UploadBlob(ctx, "blob", 3, 0, chunk); !compareErrors(err, "server unavailable") {
+		t.Fatalf("UploadBlob() expected interrupted upload, got err = %v", err)
+	}
+
+	blobs.sendErrAt = 0
+	blob, err := client.GetBlobStatus(ctx, "blob")
+	if err != nil {
+		t.Fatalf("GetBlobStatus() unexpected error: %v", err)
+	}
+	if blob.ChunkCount != 3 || blob.ReceivedChunks != 1 {
+		t.Errorf("GetBlobStatus() got %+v", blob)
+	}
+
+	received, err := client.UploadBlob(ctx, "blob", 3, blob.ReceivedChunks, chunk)
+	if err != nil || received != 3 {
+		t.Fatalf("UploadBlob() resume got %d, err = %v", received, err)
+	}
+
+	if _, err = client.UploadBlob(ctx, "blob", 3, 0, func(uint32) ([]byte, error) { return nil, errors.New("read error") }); !compareErrors(err, "read error") {
+		t.Errorf("UploadBlob() got err = %v", err)
+	}
+
+	var downloaded []byte
+	err = client.DownloadBlob(ctx, "blob", 1, func(index, count uint32, data []byte) error {
+		if count != 3 {
+			t.Errorf("DownloadBlob() chunk count = %d", count)
+		}
+		downloaded = append(downloaded, data...)
+		return nil
+	})
+	if err != nil || !bytes.Equal(downloaded, []byte{1, 2}) {
+		t.Errorf("DownloadBlob() got %v, err = %v", downloaded, err)
+	}
+
+	if err = client.DownloadBlob(ctx, "blob", 0, func(uint32, uint32, []byte) error { return errors.New("write error") }); !compareErrors(err, "write error") {
+		t.Errorf("DownloadBlob() got err = %v", err)
+	}
+
+	blobs.statusErr = status.Error(codes.NotFound, "blob not found")
+	if _, err = client.GetBlobStatus(ctx, "blob"); !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+		t.Errorf("GetBlobStatus() expected ErrNotFound, got %v", err)
+	}
+
+	blobs.statusErr = status.Error(codes.Unavailable, "unavailable")
+	if _, err = client.GetBlobStatus(ctx, "blob"); !compareErrors(err, "server unavailable") {
+		t.Errorf("GetBlobStatus() got err = %v", err)
+	}
+}
+
+func TestClientGRPC_EncryptSecretLabels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSecretsClient := mocks.NewMockSecretsClient(ctrl)
+	client := &ClientGRPC{SecretsClient: mockSecretsClient}
+
+	secrets := []*models.Secret{{ID: 3, Title: "sealed", Metadata: "meta", SearchTokens: []string{"token"}}}
+	req := &proto.EncryptSecretLabelsRequest{
+		Secrets: []*proto.SecretLabels{{Id: 3, Title: "sealed", Metadata: "meta", SearchTokens: []string{"token"}}},
+	}
+
+	mockSecretsClient.EXPECT().EncryptSecretLabels(gomock.Any(), req).Return(&emptypb.Empty{}, nil)
+	if err := client.EncryptSecretLabels(context.Background(), secrets); err != nil {
+		t.Errorf("EncryptSecretLabels() got err = %v", err)
+	}
+
+	mockSecretsClient.EXPECT().EncryptSecretLabels(gomock.Any(), req).Return(nil, status.Error(codes.NotFound, "secret not found"))
+	if err := client.EncryptSecretLabels(context.Background(), secrets); err == nil {
+		t.Error("EncryptSecretLabels() expected an error")
+	}
+}
+
+func TestClientGRPC_ReserveSecretID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSecretsClient := mocks.NewMockSecretsClient(ctrl)
+	client := &ClientGRPC{SecretsClient: mockSecretsClient}
+
+	mockSecretsClient.EXPECT().ReserveSecretID(gomock.Any(), &emptypb.Empty{}).Return(&proto.ReserveSecretIDResponse{Id: 12}, nil)
+	if id, err := client.ReserveSecretID(context.Background()); err != nil || id != 12 {
+		t.Errorf("ReserveSecretID() got id = %d, err = %v", id, err)
+	}
+
+	mockSecretsClient.EXPECT().ReserveSecretID(gomock.Any(), &emptypb.Empty{}).Return(nil, status.Error(codes.Unavailable, "unavailable"))
+	if _, err := client.ReserveSecretID(context.Background()); err == nil {
+		t.Error("ReserveSecretID() expected an error")
+	}
+}
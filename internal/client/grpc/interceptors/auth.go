@@ -12,6 +12,7 @@ import (
 )
 
 // AddAuth возвращает UnaryClientInterceptor, который добавляет токен доступа и идентификатор клиента в метаданные запроса.
+// Метаданные, уже записанные в контекст вызова, например идентификатор организации, сохраняются.
 // Если токен пуст, вызов переходит к следующему обработчику без изменения контекста.
 func AddAuth(token *string, clientID uint32) grpc.UnaryClientInterceptor {
 	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
@@ -19,12 +20,10 @@ func AddAuth(token *string, clientID uint32) grpc.UnaryClientInterceptor {
 			return invoker(ctx, method, req, reply, cc, opts...)
 		}
 
-		md := metadata.New(map[string]string{
-			consts.AccessTokenHeader: *token,
-			consts.ClientIDHeader:    strconv.Itoa(int(clientID)),
-		})
-
-		mdCtx := metadata.NewOutgoingContext(ctx, md)
+		mdCtx := metadata.AppendToOutgoingContext(ctx,
+			consts.AccessTokenHeader, *token,
+			consts.ClientIDHeader, strconv.Itoa(int(clientID)),
+		)
 		return invoker(mdCtx, method, req, reply, cc, opts...)
 	}
 }
@@ -37,12 +36,10 @@ func AddAuthStream(token *string, clientID uint64) grpc.StreamClientInterceptor
 			return streamer(ctx, desc, cc, method, opts...)
 		}
 
-		md := metadata.New(map[string]string{
-			consts.AccessTokenHeader: *token,
-			consts.ClientIDHeader:    strconv.Itoa(int(clientID)),
-		})
-
-		mdCtx := metadata.NewOutgoingContext(ctx, md)
+		mdCtx := metadata.AppendToOutgoingContext(ctx,
+			consts.AccessTokenHeader, *token,
+			consts.ClientIDHeader, strconv.Itoa(int(clientID)),
+		)
 		return streamer(mdCtx, desc, cc, method, opts...)
 	}
 }
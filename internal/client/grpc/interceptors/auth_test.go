@@ -69,6 +69,18 @@ func TestAddAuth(t *testing.T) {
 	}
 }
 
+func TestAddAuth_KeepsOutgoingMetadata(t *testing.T) {
+	token := testToken
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		assert.Equal(t, []string{"7"}, md.Get(consts.OrganizationIDHeader))
+		return checkCtx(ctx)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), consts.OrganizationIDHeader, "7")
+	assert.NoError(t, AddAuth(&token, 11)(ctx, "SomeMethod", nil, nil, nil, invoker))
+}
+
 func checkStreamCtx(ctx context.Context, expectedToken string, expectedClientID uint64) error {
 	md, ok := metadata.FromOutgoingContext(ctx)
 	if !ok || len(md) == 0 {
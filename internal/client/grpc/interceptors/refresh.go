@@ -0,0 +1,45 @@
+package interceptors
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+// RefreshFunc обновляет токен доступа. staleToken - токен, с которым был выполнен отклонённый вызов;
+// если к моменту обновления токен уже заменён другим вызовом, повторно обновлять его не требуется.
+type RefreshFunc func(ctx context.Context, staleToken string) error
+
+// Refresh возвращает UnaryClientInterceptor, который при ответе сервера с кодом Unauthenticated
+// обновляет токен доступа и однократно повторяет вызов с новым токеном.
+// Методы входа, регистрации и обновления токенов не перехватываются.
+// Если обновить токен не удалось, возвращается исходная ошибка вызова.
+func Refresh(token *string, refresh RefreshFunc) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if isPublicMethod(method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		staleToken := *token
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if status.Code(err) != codes.Unauthenticated || len(staleToken) == 0 {
+			return err
+		}
+
+		if refreshErr := refresh(ctx, staleToken); refreshErr != nil {
+			return err
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// isPublicMethod проверяет, вызывается ли метод без токена доступа.
+func isPublicMethod(method string) bool {
+	return strings.Contains(method, "Register") ||
+		strings.Contains(method, "Login") ||
+		strings.HasSuffix(method, "/VerifyTOTP") ||
+		strings.HasSuffix(method, "/RefreshToken")
+}
@@ -0,0 +1,140 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestRefresh(t *testing.T) {
+	errUnauthenticated := status.Error(codes.Unauthenticated, "token is expired")
+
+	tests := []struct {
+		name           string
+		method         string
+		token          string
+		responses      []error
+		refreshErr     error
+		expectedErr    error
+		expectedCalls  int
+		expectRefresh  bool
+		expectedStaled string
+	}{
+		{
+			name:          "success_without_refresh",
+			method:        "/proto.Secrets/GetUserSecrets",
+			token:         "old",
+			responses:     []error{nil},
+			expectedCalls: 1,
+		},
+		{
+			name:           "refresh_and_retry",
+			method:         "/proto.Secrets/GetUserSecrets",
+			token:          "old",
+			responses:      []error{errUnauthenticated, nil},
+			expectedCalls:  2,
+			expectRefresh:  true,
+			expectedStaled: "old",
+		},
+		{
+			name:           "retry_only_once",
+			method:         "/proto.Secrets/GetUserSecrets",
+			token:          "old",
+			responses:      []error{errUnauthenticated, errUnauthenticated},
+			expectedErr:    errUnauthenticated,
+			expectedCalls:  2,
+			expectRefresh:  true,
+			expectedStaled: "old",
+		},
+		{
+			name:           "refresh_failed",
+			method:         "/proto.Secrets/GetUserSecrets",
+			token:          "old",
+			responses:      []error{errUnauthenticated},
+			refreshErr:     errors.New("invalid refresh token"),
+			expectedErr:    errUnauthenticated,
+			expectedCalls:  1,
+			expectRefresh:  true,
+			expectedStaled: "old",
+		},
+		{
+			name:          "other_error",
+			method:        "/proto.Secrets/GetUserSecrets",
+			token:         "old",
+			responses:     []error{status.Error(codes.NotFound, "not found")},
+			expectedErr:   status.Error(codes.NotFound, "not found"),
+			expectedCalls: 1,
+		},
+		{
+			name:          "public_method_skipped",
+			method:        "/proto.Users/Login",
+			token:         "old",
+			responses:     []error{errUnauthenticated},
+			expectedErr:   errUnauthenticated,
+			expectedCalls: 1,
+		},
+		{
+			name:          "refresh_method_skipped",
+			method:        "/proto.Users/RefreshToken",
+			token:         "old",
+			responses:     []error{errUnauthenticated},
+			expectedErr:   errUnauthenticated,
+			expectedCalls: 1,
+		},
+		{
+			name:          "verify_totp_method_skipped",
+			method:        "/proto.Users/VerifyTOTP",
+			token:         "",
+			responses:     []error{errUnauthenticated},
+			expectedErr:   errUnauthenticated,
+			expectedCalls: 1,
+		},
+		{
+			name:          "no_token",
+			method:        "/proto.Secrets/GetUserSecrets",
+			token:         "",
+			responses:     []error{errUnauthenticated},
+			expectedErr:   errUnauthenticated,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			token := tc.token
+			calls := 0
+			invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				err := tc.responses[calls]
+				calls++
+				return err
+			}
+
+			refreshed := false
+			var staleToken string
+			refresh := func(ctx context.Context, stale string) error {
+				refreshed = true
+				staleToken = stale
+				if tc.refreshErr == nil {
+					token = "new"
+				}
+				return tc.refreshErr
+			}
+
+			interceptor := Refresh(&token, refresh)
+			err := interceptor(context.Background(), tc.method, nil, nil, nil, invoker)
+
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedCalls, calls)
+			assert.Equal(t, tc.expectRefresh, refreshed)
+			assert.Equal(t, tc.expectedStaled, staleToken)
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package storage
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/crypto"
+	"beliaev-aa/GophKeeper/internal/client/grpc"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrEmergencyReadOnly возникает при изменении хранилища, открытого по экстренному доступу: секретов,
+// папок, меток и файлов, а также при передаче его секретов другим пользователям.
+var ErrEmergencyReadOnly = errors.New("emergency access is read-only")
+
+// ErrEmergencyNotGranted возникает при открытии хранилища владельца, экстренный доступ к которому
+// ещё не предоставлен.
+var ErrEmergencyNotGranted = errors.New("emergency access is not granted")
+
+// EmergencyStorage описывает хранилище, из которого открываются хранилища владельцев,
+// предоставивших пользователю экстренный доступ.
+type EmergencyStorage interface {
+	Storage
+	Personal() Storage
+	OpenEmergencyVault(access *models.EmergencyAccess) (Storage, error)
+}
+
+// emergencyClient направляет запросы на чтение секретов, папок, меток и файлов в хранилище владельца
+// по экстренному доступу accessID. Изменять хранилище владельца и передавать его секреты нельзя.
+type emergencyClient struct {
+	grpc.ClientGRPCInterface
+	accessID uint64
+}
+
+func (c *emergencyClient) context(ctx context.Context) context.Context {
+	return grpc.WithEmergencyAccess(ctx, c.accessID)
+}
+
+func (c *emergencyClient) LoadSecrets(ctx context.Context) ([]*models.Secret, error) {
+	return c.ClientGRPCInterface.LoadSecrets(c.context(ctx))
+}
+
+func (c *emergencyClient) SyncSecrets(ctx context.Context, sinceRevision uint64) (*models.SecretsDelta, error) {
+	return c.ClientGRPCInterface.SyncSecrets(c.context(ctx), sinceRevision)
+}
+
+func (c *emergencyClient) ListSecrets(ctx context.Context, query *models.SecretListQuery) (*models.SecretsPage, error) {
+	return c.ClientGRPCInterface.ListSecrets(c.context(ctx), query)
+}
+
+func (c *emergencyClient) LoadSecret(ctx context.Context, id uint64) (*models.Secret, error) {
+	return c.ClientGRPCInterface.LoadSecret(c.context(ctx), id)
+}
+
+func (c *emergencyClient) LoadSecretVersions(ctx context.Context, id uint64) ([]*models.SecretVersion, error) {
+	return c.ClientGRPCInterface.LoadSecretVersions(c.context(ctx), id)
+}
+
+func (c *emergencyClient) LoadTrash(ctx context.Context) ([]*models.Secret, error) {
+	return c.ClientGRPCInterface.LoadTrash(c.context(ctx))
+}
+
+func (c *emergencyClient) ReserveSecretID(context.Context) (uint64, error) {
+	return 0, ErrEmergencyReadOnly
+}
+
+func (c *emergencyClient) SaveSecret(context.Context, *models.Secret) error {
+	return ErrEmergencyReadOnly
+}
+
+func (c *emergencyClient) DeleteSecret(context.Context, uint64) error {
+	return ErrEmergencyReadOnly
+}
+
+func (c *emergencyClient) RestoreSecretVersion(context.Context, uint64, uint64) error {
+	return ErrEmergencyReadOnly
+}
+
+func (c *emergencyClient) RestoreSecret(context.Context, uint64) error {
+	return ErrEmergencyReadOnly
+}
+
+func (c *emergencyClient) PurgeSecret(context.Context, uint64) error {
+	return ErrEmergencyReadOnly
+}
+
+func (c *emergencyClient) EncryptSecretLabels(context.Context, []*models.Secret) error {
+	return ErrEmergencyReadOnly
+}
+
+func (c *emergencyClient) ListFolders(ctx context.Context) ([]*models.Folder, error) {
+	return c.ClientGRPCInterface.ListFolders(c.context(ctx))
+}
+
+func (c *emergencyClient) CreateFolder(context.Context, uint64, string) (*models.Folder, error) {
+	return nil, ErrEmergencyReadOnly
+}
+
+func (c *emergencyClient) UpdateFolder(context.Context, uint64, uint64, string) error {
+	return ErrEmergencyReadOnly
+}
+
+func (c *emergencyClient) DeleteFolder(context.Context, uint64) error {
+	return ErrEmergencyReadOnly
+}
+
+func (c *emergencyClient) ListTags(ctx context.Context) ([]*models.Tag, error) {
+	return c.ClientGRPCInterface.ListTags(c.context(ctx))
+}
+
+func (c *emergencyClient) CreateTag(context.Context, string) (*models.Tag, error) {
+	return nil, ErrEmergencyReadOnly
+}
+
+func (c *emergencyClient) UpdateTag(context.Context, uint64, string) error {
+	return ErrEmergencyReadOnly
+}
+
+func (c *emergencyClient) DeleteTag(context.Context, uint64) error {
+	return ErrEmergencyReadOnly
+}
+
+func (c *emergencyClient) ShareSecret(context.Context, *models.Share) (*models.Share, error) {
+	return nil, ErrEmergencyReadOnly
+}
+
+func (c *emergencyClient) ListShares(context.Context, uint64) ([]*models.Share, error) {
+	return nil, ErrEmergencyReadOnly
+}
+
+func (c *emergencyClient) ListSharedWithMe(context.Context) ([]*models.Secret, error) {
+	return nil, ErrEmergencyReadOnly
+}
+
+func (c *emergencyClient) RevokeShare(context.Context, uint64) error {
+	return ErrEmergencyReadOnly
+}
+
+func (c *emergencyClient) UpdateSharedSecret(context.Context, *models.Secret) (uint64, error) {
+	return 0, ErrEmergencyReadOnly
+}
+
+func (c *emergencyClient) GetBlobStatus(ctx context.Context, blobID string) (*models.BlobStatus, error) {
+	return c.ClientGRPCInterface.GetBlobStatus(c.context(ctx), blobID)
+}
+
+func (c *emergencyClient) UploadBlob(context.Context, string, uint32, uint32, func(index uint32) ([]byte, error)) (uint32, error) {
+	return 0, ErrEmergencyReadOnly
+}
+
+func (c *emergencyClient) DownloadBlob(ctx context.Context, blobID string, from uint32, handle func(index, count uint32, data []byte) error) error {
+	return c.ClientGRPCInterface.DownloadBlob(c.context(ctx), blobID, from, handle)
+}
+
+// GetEmergencyContacts извлекает доверенных пользователей и состояние их экстренного доступа к хранилищу.
+func (store *RemoteStorage) GetEmergencyContacts(ctx context.Context) ([]*models.EmergencyAccess, error) {
+	return store.client.ListEmergencyContacts(ctx)
+}
+
+// GetEmergencyGrants извлекает хранилища, владельцы которых назначили пользователя доверенным.
+func (store *RemoteStorage) GetEmergencyGrants(ctx context.Context) ([]*models.EmergencyAccess, error) {
+	return store.client.ListEmergencyGrants(ctx)
+}
+
+// AddEmergencyContact назначает пользователя login доверенным для личного хранилища пользователя со сроком
+// ожидания waitDays дней: ключ хранилища шифруется открытым ключом доверенного пользователя и станет ему
+// доступен, только если пользователь не отклонит запрос доступа за срок ожидания. Открытый ключ загружается
+// с сервера без дополнительной проверки. Возвращает ErrVaultLocked, если хранилище пользователя не открыто.
+func (store *RemoteStorage) AddEmergencyContact(ctx context.Context, login string, waitDays int) error {
+	personal := store
+	if store.personal != nil {
+		personal = store.personal
+	}
+	if personal.vaultKey == nil {
+		return ErrVaultLocked
+	}
+
+	login = strings.TrimSpace(login)
+	publicKey, err := store.client.GetPublicKey(ctx, login)
+	if err != nil {
+		return fmt.Errorf("AddEmergencyContact(): failed to get public key of %s: %w", login, err)
+	}
+	wrapped, err := crypto.WrapKeyToPublic(personal.vaultKey, publicKey, crypto.EmergencyKeyAAD(personal.owner, login))
+	if err != nil {
+		return fmt.Errorf("AddEmergencyContact(): failed to encrypt vault key: %w", err)
+	}
+
+	_, err = store.client.AddEmergencyContact(ctx, &models.EmergencyAccess{
+		GranteeLogin: login,
+		WaitDays:     waitDays,
+		VaultKey:     wrapped,
+	})
+	return err
+}
+
+// RequestEmergencyAccess запрашивает экстренный доступ id к хранилищу владельца.
+func (store *RemoteStorage) RequestEmergencyAccess(ctx context.Context, id uint64) error {
+	return store.client.RequestEmergencyAccess(ctx, id)
+}
+
+// RejectEmergencyAccess отклоняет запрос экстренного доступа id к хранилищу пользователя или отзывает
+// предоставленный доступ.
+func (store *RemoteStorage) RejectEmergencyAccess(ctx context.Context, id uint64) error {
+	return store.client.RejectEmergencyAccess(ctx, id)
+}
+
+// DeleteEmergencyAccess удаляет экстренный доступ id.
+func (store *RemoteStorage) DeleteEmergencyAccess(ctx context.Context, id uint64) error {
+	return store.client.DeleteEmergencyAccess(ctx, id)
+}
+
+// OpenEmergencyVault открывает для чтения хранилище владельца, предоставившего экстренный доступ access:
+// ключ хранилища владельца расшифровывается закрытым ключом пользователя, а запросы к секретам направляются
+// в хранилище владельца. Секреты владельца, сохранённые до появления у них ключей данных, зашифрованы
+// ключом его мастер-пароля и не расшифровываются.
+// Возвращает ErrEmergencyNotGranted, если доступ не предоставлен, и ErrVaultLocked, если хранилище
+// пользователя не открыто.
+func (store *RemoteStorage) OpenEmergencyVault(access *models.EmergencyAccess) (Storage, error) {
+	if access.Status != models.EmergencyGranted || len(access.VaultKey) == 0 {
+		return nil, ErrEmergencyNotGranted
+	}
+
+	personal := store
+	if store.personal != nil {
+		personal = store.personal
+	}
+	if personal.privateKey == nil {
+		return nil, ErrVaultLocked
+	}
+
+	aad := crypto.EmergencyKeyAAD(access.GrantorLogin, personal.client.GetLogin())
+	vaultKey, err := crypto.UnwrapKeyWithPrivate(access.VaultKey, personal.privateKey, aad)
+	if err != nil {
+		return nil, fmt.Errorf("OpenEmergencyVault(): failed to decrypt vault key: %w", err)
+	}
+
+	return &RemoteStorage{
+		client:     &emergencyClient{ClientGRPCInterface: personal.client, accessID: access.ID},
+		owner:      access.GrantorLogin,
+		vaultKey:   vaultKey,
+		privateKey: personal.privateKey,
+		emergency:  access,
+		personal:   personal,
+	}, nil
+}
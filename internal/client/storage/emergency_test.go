@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/crypto"
+	"beliaev-aa/GophKeeper/pkg/consts"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/metadata"
+	"testing"
+)
+
+func TestRemoteStorage_Emergency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	grantorClient := mocks.NewMockClientGRPCInterface(ctrl)
+	grantorClient.EXPECT().GetPassword().Return("").AnyTimes()
+	grantorClient.EXPECT().GetEncryptionKey().Return(make([]byte, 32)).AnyTimes()
+	grantorClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+	grantor, err := NewRemoteStorage(grantorClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+	grantor.vaultKey = bytes32(1)
+
+	granteeClient := mocks.NewMockClientGRPCInterface(ctrl)
+	granteeClient.EXPECT().GetPassword().Return("").AnyTimes()
+	granteeClient.EXPECT().GetEncryptionKey().Return(bytes32(2)).AnyTimes()
+	granteeClient.EXPECT().GetLogin().Return("bob").AnyTimes()
+	grantee, err := NewRemoteStorage(granteeClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+	granteePublic, granteePrivate, err := crypto.NewKeyPair()
+	if err != nil {
+		t.Fatalf("Failed to generate key pair: %v", err)
+	}
+	grantee.vaultKey, grantee.privateKey = bytes32(3), granteePrivate
+
+	ctx := context.Background()
+	var saved *models.Secret
+	grantorClient.EXPECT().SaveSecret(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, secret *models.Secret) error {
+		saved = secret
+		return nil
+	})
+	secret := &models.Secret{ID: 5, Title: "Bank", SecretType: string(models.TextSecret), Text: &models.Text{Content: "pin"}}
+	if err = grantor.Update(ctx, secret); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var added *models.EmergencyAccess
+	grantorClient.EXPECT().GetPublicKey(gomock.Any(), "bob").Return(granteePublic, nil)
+	grantorClient.EXPECT().AddEmergencyContact(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, access *models.EmergencyAccess) (*models.EmergencyAccess, error) {
+		added = access
+		return access, nil
+	})
+	if err = grantor.AddEmergencyContact(ctx, " bob ", 7); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if added.GranteeLogin != "bob" || added.WaitDays != 7 || len(added.VaultKey) == 0 {
+		t.Fatalf("Unexpected emergency access: %+v", added)
+	}
+
+	access := &models.EmergencyAccess{ID: 9, GrantorLogin: testOwner, GranteeLogin: "bob", Status: models.EmergencyRequested}
+	if _, err = grantee.OpenEmergencyVault(access); !errors.Is(err, ErrEmergencyNotGranted) {
+		t.Errorf("Expected ErrEmergencyNotGranted, got %v", err)
+	}
+
+	// После предоставления доступа доверенный пользователь открывает хранилище владельца своим закрытым ключом.
+	access.Status, access.VaultKey = models.EmergencyGranted, added.VaultKey
+	vault, err := grantee.OpenEmergencyVault(access)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if vault.String() != "emergency access to "+testOwner {
+		t.Errorf("Expected emergency vault of %s, got %s", testOwner, vault)
+	}
+	if personal := vault.(EmergencyStorage).Personal(); personal != grantee {
+		t.Errorf("Expected personal storage of the grantee, got %v", personal)
+	}
+
+	granteeClient.EXPECT().LoadSecret(gomock.Any(), uint64(5)).DoAndReturn(func(ctx context.Context, _ uint64) (*models.Secret, error) {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		if values := md.Get(consts.EmergencyAccessIDHeader); len(values) != 1 || values[0] != "9" {
+			t.Errorf("Expected request by emergency access 9, got %v", values)
+		}
+		return saved, nil
+	})
+	got, err := vault.Get(ctx, 5)
+	if err != nil || got.Title != "Bank" || got.Text == nil || got.Text.Content != "pin" {
+		t.Fatalf("Expected grantee to read the grantor secret, got %+v, err = %v", got, err)
+	}
+
+	if err = vault.Update(ctx, got); !errors.Is(err, ErrEmergencyReadOnly) {
+		t.Errorf("Expected ErrEmergencyReadOnly, got %v", err)
+	}
+	if err = vault.ShareSecret(ctx, got, "carol", "", false); !errors.Is(err, ErrEmergencyReadOnly) {
+		t.Errorf("Expected ErrEmergencyReadOnly, got %v", err)
+	}
+
+	// Файлы владельца читаются по экстренному доступу, но не загружаются.
+	client := vault.(*RemoteStorage).client
+	granteeClient.EXPECT().GetBlobStatus(gomock.Any(), "blob").DoAndReturn(func(ctx context.Context, _ string) (*models.BlobStatus, error) {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		if values := md.Get(consts.EmergencyAccessIDHeader); len(values) != 1 || values[0] != "9" {
+			t.Errorf("Expected request by emergency access 9, got %v", values)
+		}
+		return &models.BlobStatus{ID: "blob", ChunkCount: 1, ReceivedChunks: 1}, nil
+	})
+	if _, err = client.GetBlobStatus(ctx, "blob"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if _, err = client.UploadBlob(ctx, "blob", 1, 0, nil); !errors.Is(err, ErrEmergencyReadOnly) {
+		t.Errorf("Expected ErrEmergencyReadOnly, got %v", err)
+	}
+
+	// Ключ хранилища, зашифрованный для доверенного пользователя, не подходит владельцу хранилища другого логина.
+	access.GrantorLogin = "mallory"
+	if _, err = grantee.OpenEmergencyVault(access); err == nil {
+		t.Error("Expected vault key bound to another grantor to fail")
+	}
+}
+
+func TestRemoteStorage_Emergency_Locked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+	mockClient.EXPECT().GetPassword().Return("").AnyTimes()
+	mockClient.EXPECT().GetEncryptionKey().Return(make([]byte, 32)).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+	rs, err := NewRemoteStorage(mockClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+
+	if err = rs.AddEmergencyContact(context.Background(), "bob", 7); !errors.Is(err, ErrVaultLocked) {
+		t.Errorf("Expected ErrVaultLocked, got %v", err)
+	}
+	access := &models.EmergencyAccess{ID: 9, GrantorLogin: "bob", Status: models.EmergencyGranted, VaultKey: []byte("wrapped")}
+	if _, err = rs.OpenEmergencyVault(access); !errors.Is(err, ErrVaultLocked) {
+		t.Errorf("Expected ErrVaultLocked, got %v", err)
+	}
+}
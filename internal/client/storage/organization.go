@@ -0,0 +1,277 @@
+package storage
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/crypto"
+	"beliaev-aa/GophKeeper/internal/client/grpc"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrOrganizationUnsupported возникает при действиях, недоступных в хранилище организации:
+// передаче секретов другим пользователям. Участники организации получают доступ к её секретам
+// через роли, а не через передачу.
+var ErrOrganizationUnsupported = errors.New("not available in organization vaults")
+
+// OrganizationStorage описывает хранилище, из которого открываются хранилища организаций пользователя.
+type OrganizationStorage interface {
+	Storage
+	Personal() Storage
+	OpenOrganization(org *models.Organization) (Storage, error)
+}
+
+// orgClient направляет запросы к секретам, папкам, меткам и файлам в хранилище организации orgID.
+// Передача секретов в хранилище организации не поддерживается.
+type orgClient struct {
+	grpc.ClientGRPCInterface
+	orgID uint64
+}
+
+func (c *orgClient) context(ctx context.Context) context.Context {
+	return grpc.WithOrganization(ctx, c.orgID)
+}
+
+func (c *orgClient) LoadSecrets(ctx context.Context) ([]*models.Secret, error) {
+	return c.ClientGRPCInterface.LoadSecrets(c.context(ctx))
+}
+
+func (c *orgClient) SyncSecrets(ctx context.Context, sinceRevision uint64) (*models.SecretsDelta, error) {
+	return c.ClientGRPCInterface.SyncSecrets(c.context(ctx), sinceRevision)
+}
+
+func (c *orgClient) ListSecrets(ctx context.Context, query *models.SecretListQuery) (*models.SecretsPage, error) {
+	return c.ClientGRPCInterface.ListSecrets(c.context(ctx), query)
+}
+
+func (c *orgClient) LoadSecret(ctx context.Context, id uint64) (*models.Secret, error) {
+	return c.ClientGRPCInterface.LoadSecret(c.context(ctx), id)
+}
+
+func (c *orgClient) ReserveSecretID(ctx context.Context) (uint64, error) {
+	return c.ClientGRPCInterface.ReserveSecretID(c.context(ctx))
+}
+
+func (c *orgClient) SaveSecret(ctx context.Context, secret *models.Secret) error {
+	return c.ClientGRPCInterface.SaveSecret(c.context(ctx), secret)
+}
+
+func (c *orgClient) DeleteSecret(ctx context.Context, id uint64) error {
+	return c.ClientGRPCInterface.DeleteSecret(c.context(ctx), id)
+}
+
+func (c *orgClient) LoadSecretVersions(ctx context.Context, id uint64) ([]*models.SecretVersion, error) {
+	return c.ClientGRPCInterface.LoadSecretVersions(c.context(ctx), id)
+}
+
+func (c *orgClient) RestoreSecretVersion(ctx context.Context, id, versionID uint64) error {
+	return c.ClientGRPCInterface.RestoreSecretVersion(c.context(ctx), id, versionID)
+}
+
+func (c *orgClient) LoadTrash(ctx context.Context) ([]*models.Secret, error) {
+	return c.ClientGRPCInterface.LoadTrash(c.context(ctx))
+}
+
+func (c *orgClient) RestoreSecret(ctx context.Context, id uint64) error {
+	return c.ClientGRPCInterface.RestoreSecret(c.context(ctx), id)
+}
+
+func (c *orgClient) PurgeSecret(ctx context.Context, id uint64) error {
+	return c.ClientGRPCInterface.PurgeSecret(c.context(ctx), id)
+}
+
+func (c *orgClient) EncryptSecretLabels(ctx context.Context, secrets []*models.Secret) error {
+	return c.ClientGRPCInterface.EncryptSecretLabels(c.context(ctx), secrets)
+}
+
+func (c *orgClient) ListFolders(ctx context.Context) ([]*models.Folder, error) {
+	return c.ClientGRPCInterface.ListFolders(c.context(ctx))
+}
+
+func (c *orgClient) CreateFolder(ctx context.Context, parentID uint64, name string) (*models.Folder, error) {
+	return c.ClientGRPCInterface.CreateFolder(c.context(ctx), parentID, name)
+}
+
+func (c *orgClient) UpdateFolder(ctx context.Context, id, parentID uint64, name string) error {
+	return c.ClientGRPCInterface.UpdateFolder(c.context(ctx), id, parentID, name)
+}
+
+func (c *orgClient) DeleteFolder(ctx context.Context, id uint64) error {
+	return c.ClientGRPCInterface.DeleteFolder(c.context(ctx), id)
+}
+
+func (c *orgClient) ListTags(ctx context.Context) ([]*models.Tag, error) {
+	return c.ClientGRPCInterface.ListTags(c.context(ctx))
+}
+
+func (c *orgClient) CreateTag(ctx context.Context, name string) (*models.Tag, error) {
+	return c.ClientGRPCInterface.CreateTag(c.context(ctx), name)
+}
+
+func (c *orgClient) UpdateTag(ctx context.Context, id uint64, name string) error {
+	return c.ClientGRPCInterface.UpdateTag(c.context(ctx), id, name)
+}
+
+func (c *orgClient) DeleteTag(ctx context.Context, id uint64) error {
+	return c.ClientGRPCInterface.DeleteTag(c.context(ctx), id)
+}
+
+func (c *orgClient) GetBlobStatus(ctx context.Context, blobID string) (*models.BlobStatus, error) {
+	return c.ClientGRPCInterface.GetBlobStatus(c.context(ctx), blobID)
+}
+
+func (c *orgClient) UploadBlob(ctx context.Context, blobID string, chunkCount, from uint32, chunk func(index uint32) ([]byte, error)) (uint32, error) {
+	return c.ClientGRPCInterface.UploadBlob(c.context(ctx), blobID, chunkCount, from, chunk)
+}
+
+func (c *orgClient) DownloadBlob(ctx context.Context, blobID string, from uint32, handle func(index, count uint32, data []byte) error) error {
+	return c.ClientGRPCInterface.DownloadBlob(c.context(ctx), blobID, from, handle)
+}
+
+func (c *orgClient) ShareSecret(context.Context, *models.Share) (*models.Share, error) {
+	return nil, ErrOrganizationUnsupported
+}
+
+func (c *orgClient) ListShares(context.Context, uint64) ([]*models.Share, error) {
+	return nil, ErrOrganizationUnsupported
+}
+
+func (c *orgClient) ListSharedWithMe(context.Context) ([]*models.Secret, error) {
+	return nil, ErrOrganizationUnsupported
+}
+
+func (c *orgClient) RevokeShare(context.Context, uint64) error {
+	return ErrOrganizationUnsupported
+}
+
+func (c *orgClient) UpdateSharedSecret(context.Context, *models.Secret) (uint64, error) {
+	return 0, ErrOrganizationUnsupported
+}
+
+// Organization возвращает организацию, хранилище которой открыто, или nil для личного хранилища.
+func (store *RemoteStorage) Organization() *models.Organization {
+	return store.organization
+}
+
+// Personal возвращает личное хранилище пользователя, из которого открыто хранилище организации.
+func (store *RemoteStorage) Personal() Storage {
+	if store.personal != nil {
+		return store.personal
+	}
+	return store
+}
+
+// GetOrganizations извлекает организации, в которых состоит пользователь.
+func (store *RemoteStorage) GetOrganizations(ctx context.Context) ([]*models.Organization, error) {
+	return store.client.ListOrganizations(ctx)
+}
+
+// CreateOrganization создаёт организацию name, владельцем которой становится пользователь. Ключ хранилища
+// организации генерируется на клиенте и передаётся на сервер зашифрованным открытым ключом пользователя.
+// Возвращает ErrVaultLocked, если хранилище пользователя не открыто.
+func (store *RemoteStorage) CreateOrganization(ctx context.Context, name string) (*models.Organization, error) {
+	if store.privateKey == nil {
+		return nil, ErrVaultLocked
+	}
+
+	name = strings.TrimSpace(name)
+	login := store.client.GetLogin()
+	publicKey, err := store.client.GetPublicKey(ctx, login)
+	if err != nil {
+		return nil, fmt.Errorf("CreateOrganization(): failed to get public key: %w", err)
+	}
+
+	vaultKey, err := crypto.NewKey()
+	if err != nil {
+		return nil, fmt.Errorf("CreateOrganization(): failed to generate vault key: %w", err)
+	}
+	wrapped, err := crypto.WrapKeyToPublic(vaultKey, publicKey, crypto.OrganizationKeyAAD(name, login))
+	if err != nil {
+		return nil, fmt.Errorf("CreateOrganization(): failed to encrypt vault key: %w", err)
+	}
+
+	return store.client.CreateOrganization(ctx, name, wrapped)
+}
+
+// OpenOrganization открывает хранилище организации org: ключ её хранилища расшифровывается закрытым
+// ключом пользователя, а запросы к секретам направляются в хранилище организации.
+// Возвращает ErrVaultLocked, если хранилище пользователя не открыто.
+func (store *RemoteStorage) OpenOrganization(org *models.Organization) (Storage, error) {
+	vaultKey, err := store.organizationKey(org)
+	if err != nil {
+		return nil, fmt.Errorf("OpenOrganization(): failed to decrypt vault key: %w", err)
+	}
+
+	personal := store
+	if store.personal != nil {
+		personal = store.personal
+	}
+
+	return &RemoteStorage{
+		client:       &orgClient{ClientGRPCInterface: personal.client, orgID: org.ID},
+		owner:        "org:" + strconv.FormatUint(org.ID, 10),
+		vaultKey:     vaultKey,
+		privateKey:   personal.privateKey,
+		organization: org,
+		personal:     personal,
+	}, nil
+}
+
+// GetOrgMembers извлекает участников организации orgID.
+func (store *RemoteStorage) GetOrgMembers(ctx context.Context, orgID uint64) ([]*models.OrgMember, error) {
+	return store.client.ListOrgMembers(ctx, orgID)
+}
+
+// AddOrgMember добавляет пользователя login в организацию org с ролью role: ключ хранилища организации
+// шифруется открытым ключом участника. Открытый ключ загружается с сервера без дополнительной проверки.
+// Возвращает ErrVaultLocked, если хранилище пользователя не открыто.
+func (store *RemoteStorage) AddOrgMember(ctx context.Context, org *models.Organization, login string, role models.OrgRole) error {
+	vaultKey, err := store.organizationKey(org)
+	if err != nil {
+		return fmt.Errorf("AddOrgMember(): failed to decrypt vault key: %w", err)
+	}
+
+	login = strings.TrimSpace(login)
+	publicKey, err := store.client.GetPublicKey(ctx, login)
+	if err != nil {
+		return fmt.Errorf("AddOrgMember(): failed to get public key of %s: %w", login, err)
+	}
+	wrapped, err := crypto.WrapKeyToPublic(vaultKey, publicKey, crypto.OrganizationKeyAAD(org.Name, login))
+	if err != nil {
+		return fmt.Errorf("AddOrgMember(): failed to encrypt vault key: %w", err)
+	}
+
+	_, err = store.client.AddOrgMember(ctx, &models.OrgMember{
+		OrgID:    org.ID,
+		Login:    login,
+		Role:     role,
+		VaultKey: wrapped,
+	})
+	return err
+}
+
+// UpdateOrgMemberRole изменяет роль участника userID организации orgID.
+func (store *RemoteStorage) UpdateOrgMemberRole(ctx context.Context, orgID, userID uint64, role models.OrgRole) error {
+	return store.client.UpdateOrgMemberRole(ctx, orgID, userID, role)
+}
+
+// RemoveOrgMember исключает участника userID из организации orgID. Ключ хранилища организации при этом
+// не меняется.
+func (store *RemoteStorage) RemoveOrgMember(ctx context.Context, orgID, userID uint64) error {
+	return store.client.RemoveOrgMember(ctx, orgID, userID)
+}
+
+// DeleteOrganization удаляет организацию orgID вместе с секретами её хранилища.
+func (store *RemoteStorage) DeleteOrganization(ctx context.Context, orgID uint64) error {
+	return store.client.DeleteOrganization(ctx, orgID)
+}
+
+// organizationKey расшифровывает ключ хранилища организации org закрытым ключом пользователя.
+func (store *RemoteStorage) organizationKey(org *models.Organization) ([]byte, error) {
+	if store.privateKey == nil {
+		return nil, ErrVaultLocked
+	}
+	return crypto.UnwrapKeyWithPrivate(org.VaultKey, store.privateKey, crypto.OrganizationKeyAAD(org.Name, store.client.GetLogin()))
+}
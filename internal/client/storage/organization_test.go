@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/crypto"
+	"beliaev-aa/GophKeeper/pkg/consts"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/metadata"
+	"testing"
+)
+
+func TestRemoteStorage_Organization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ownerClient := mocks.NewMockClientGRPCInterface(ctrl)
+	ownerClient.EXPECT().GetPassword().Return("").AnyTimes()
+	ownerClient.EXPECT().GetEncryptionKey().Return(make([]byte, 32)).AnyTimes()
+	ownerClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+	owner, err := NewRemoteStorage(ownerClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+	ownerPublic, ownerPrivate, err := crypto.NewKeyPair()
+	if err != nil {
+		t.Fatalf("Failed to generate key pair: %v", err)
+	}
+	owner.vaultKey, owner.privateKey = bytes32(1), ownerPrivate
+
+	memberClient := mocks.NewMockClientGRPCInterface(ctrl)
+	memberClient.EXPECT().GetPassword().Return("").AnyTimes()
+	memberClient.EXPECT().GetEncryptionKey().Return(bytes32(2)).AnyTimes()
+	memberClient.EXPECT().GetLogin().Return("bob").AnyTimes()
+	member, err := NewRemoteStorage(memberClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+	memberPublic, memberPrivate, err := crypto.NewKeyPair()
+	if err != nil {
+		t.Fatalf("Failed to generate key pair: %v", err)
+	}
+	member.vaultKey, member.privateKey = bytes32(3), memberPrivate
+
+	ctx := context.Background()
+	ownerClient.EXPECT().GetPublicKey(gomock.Any(), testOwner).Return(ownerPublic, nil)
+	ownerClient.EXPECT().CreateOrganization(gomock.Any(), "Team", gomock.Any()).DoAndReturn(func(_ context.Context, name string, vaultKey []byte) (*models.Organization, error) {
+		return &models.Organization{ID: 7, Name: name, Role: models.OrgOwner, VaultKey: vaultKey}, nil
+	})
+	org, err := owner.CreateOrganization(ctx, " Team ")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	orgStore, err := owner.OpenOrganization(org)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if orgStore.Organization() != org || orgStore.String() != "organization Team" {
+		t.Errorf("Expected storage of organization Team, got %s", orgStore)
+	}
+	if personal := orgStore.(OrganizationStorage).Personal(); personal != owner {
+		t.Errorf("Expected personal storage of the owner, got %v", personal)
+	}
+
+	// Секреты сохраняются в хранилище организации с привязкой к организации, а не к владельцу.
+	var saved *models.Secret
+	ownerClient.EXPECT().SaveSecret(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, secret *models.Secret) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		if values := md.Get(consts.OrganizationIDHeader); len(values) != 1 || values[0] != "7" {
+			t.Errorf("Expected request to organization 7, got %v", values)
+		}
+		saved = secret
+		return nil
+	})
+	secret := &models.Secret{ID: 5, Title: "Deploy", SecretType: string(models.TextSecret), Text: &models.Text{Content: "token"}}
+	if err = orgStore.Update(ctx, secret); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(saved.DataKey) == 0 {
+		t.Fatal("Expected organization secret to have a data key")
+	}
+
+	var added *models.OrgMember
+	ownerClient.EXPECT().GetPublicKey(gomock.Any(), "bob").Return(memberPublic, nil)
+	ownerClient.EXPECT().AddOrgMember(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, m *models.OrgMember) (*models.OrgMember, error) {
+		added = m
+		return m, nil
+	})
+	if err = orgStore.AddOrgMember(ctx, org, " bob ", models.OrgViewer); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if added.OrgID != 7 || added.Login != "bob" || added.Role != models.OrgViewer {
+		t.Fatalf("Unexpected member: %+v", added)
+	}
+
+	// Участник открывает хранилище организации своим закрытым ключом и читает секреты.
+	membership := &models.Organization{ID: 7, Name: "Team", Role: models.OrgViewer, VaultKey: added.VaultKey}
+	memberOrg, err := member.OpenOrganization(membership)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	memberClient.EXPECT().LoadSecret(gomock.Any(), uint64(5)).Return(saved, nil)
+	got, err := memberOrg.Get(ctx, 5)
+	if err != nil || got.Title != "Deploy" || got.Text == nil || got.Text.Content != "token" {
+		t.Errorf("Expected member to read the organization secret, got %+v, err = %v", got, err)
+	}
+
+	// Ключ хранилища, зашифрованный для участника, не подходит другому участнику.
+	if _, err = owner.OpenOrganization(membership); err == nil {
+		t.Error("Expected vault key bound to another member to fail")
+	}
+
+	// Папки, метки и файлы запрашиваются из хранилища организации.
+	inOrganization := func(ctx context.Context) {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		if values := md.Get(consts.OrganizationIDHeader); len(values) != 1 || values[0] != "7" {
+			t.Errorf("Expected request to organization 7, got %v", values)
+		}
+	}
+	memberClient.EXPECT().ListFolders(gomock.Any()).DoAndReturn(func(ctx context.Context) ([]*models.Folder, error) {
+		inOrganization(ctx)
+		return []*models.Folder{{ID: 1, UserID: 70, Name: "Infra"}}, nil
+	})
+	if folders, err := memberOrg.GetFolders(ctx); err != nil || len(folders) != 1 {
+		t.Errorf("Expected organization folders, got %v, err = %v", folders, err)
+	}
+	memberClient.EXPECT().GetBlobStatus(gomock.Any(), "blob").DoAndReturn(func(ctx context.Context, _ string) (*models.BlobStatus, error) {
+		inOrganization(ctx)
+		return &models.BlobStatus{ID: "blob", ChunkCount: 1, ReceivedChunks: 1}, nil
+	})
+	if _, err = memberOrg.(*RemoteStorage).client.GetBlobStatus(ctx, "blob"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err = memberOrg.ShareSecret(ctx, got, "carol", "", false); !errors.Is(err, ErrOrganizationUnsupported) {
+		t.Errorf("Expected ErrOrganizationUnsupported, got %v", err)
+	}
+}
+
+func TestRemoteStorage_Organization_Locked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+	mockClient.EXPECT().GetPassword().Return("").AnyTimes()
+	mockClient.EXPECT().GetEncryptionKey().Return(make([]byte, 32)).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+	rs, err := NewRemoteStorage(mockClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+
+	if _, err = rs.CreateOrganization(context.Background(), "Team"); !errors.Is(err, ErrVaultLocked) {
+		t.Errorf("Expected ErrVaultLocked, got %v", err)
+	}
+	if _, err = rs.OpenOrganization(&models.Organization{ID: 7, Name: "Team"}); !errors.Is(err, ErrVaultLocked) {
+		t.Errorf("Expected ErrVaultLocked, got %v", err)
+	}
+	if err = rs.AddOrgMember(context.Background(), &models.Organization{ID: 7, Name: "Team"}, "bob", models.OrgEditor); !errors.Is(err, ErrVaultLocked) {
+		t.Errorf("Expected ErrVaultLocked, got %v", err)
+	}
+}
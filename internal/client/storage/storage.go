@@ -5,19 +5,88 @@ package storage
 import (
 	"beliaev-aa/GophKeeper/internal/client/crypto"
 	"beliaev-aa/GophKeeper/internal/client/grpc"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
 	"beliaev-aa/GophKeeper/pkg/models"
 	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
 )
 
+// Поля секрета, к которым привязываются шифротексты через дополнительные аутентифицируемые данные.
+const (
+	payloadField  = "payload"
+	titleField    = "title"
+	metadataField = "metadata"
+	dataKeyField  = "data_key"
+)
+
+// ErrNoEncryptionKey возникает при создании хранилища до входа пользователя.
+var ErrNoEncryptionKey = errors.New("encryption key is not set")
+
+// ErrNoKDFParams возникает при смене пароля, если клиенту неизвестны параметры KDF текущего ключа.
+var ErrNoKDFParams = errors.New("kdf params are not set")
+
+// ErrVaultLocked возникает при расшифровке секрета с ключом данных до открытия хранилища через UnlockVault.
+var ErrVaultLocked = errors.New("vault is locked")
+
+// ErrNoDataKey возникает при передаче секрета, у которого ещё нет собственного ключа данных.
+var ErrNoDataKey = errors.New("secret has no data key")
+
+// ErrLegacyCiphertext возникает при чтении секрета открытого хранилища, записанного в прежнем формате:
+// без ключа данных, с открытыми заголовком и метаданными или не в конверте. При создании ключа хранилища
+// все секреты перешифровываются ключами данных в конверт, поэтому такой секрет мог подставить только сервер.
+var ErrLegacyCiphertext = errors.New("secret is not encrypted with a data key in the current format")
+
 // Storage описывает интерфейс для базовых операций с хранилищем секретов.
 type Storage interface {
 	Get(ctx context.Context, id uint64) (*models.Secret, error)
 	GetAll(ctx context.Context) ([]*models.Secret, error)
+	List(ctx context.Context, query *models.SecretListQuery) (*models.SecretsPage, error)
 	Create(ctx context.Context, secret *models.Secret) error
 	Update(ctx context.Context, secret *models.Secret) error
 	Delete(ctx context.Context, id uint64) error
+	GetVersions(ctx context.Context, id uint64) ([]*models.SecretVersion, error)
+	RestoreVersion(ctx context.Context, id, versionID uint64) error
+	GetTrash(ctx context.Context) ([]*models.Secret, error)
+	Restore(ctx context.Context, id uint64) error
+	Purge(ctx context.Context, id uint64) error
+	GetFolders(ctx context.Context) ([]*models.Folder, error)
+	CreateFolder(ctx context.Context, parentID uint64, name string) (*models.Folder, error)
+	UpdateFolder(ctx context.Context, id, parentID uint64, name string) error
+	DeleteFolder(ctx context.Context, id uint64) error
+	GetTags(ctx context.Context) ([]*models.Tag, error)
+	CreateTag(ctx context.Context, name string) (*models.Tag, error)
+	UpdateTag(ctx context.Context, id uint64, name string) error
+	DeleteTag(ctx context.Context, id uint64) error
+	UploadFile(ctx context.Context, secret *models.Secret, path string, progress func(done, total int64)) error
+	DownloadFile(ctx context.Context, secret *models.Secret, path string, progress func(done, total int64)) error
+	ChangePassword(ctx context.Context, currentPassword, newPassword string) error
+	Fingerprint() (string, error)
+	ShareSecret(ctx context.Context, secret *models.Secret, recipient, fingerprint string, canEdit bool) error
+	GetShares(ctx context.Context, secretID uint64) ([]*models.Share, error)
+	RevokeShare(ctx context.Context, shareID uint64) error
+	GetSharedWithMe(ctx context.Context) ([]*models.Secret, error)
+	Organization() *models.Organization
+	GetOrganizations(ctx context.Context) ([]*models.Organization, error)
+	CreateOrganization(ctx context.Context, name string) (*models.Organization, error)
+	GetOrgMembers(ctx context.Context, orgID uint64) ([]*models.OrgMember, error)
+	AddOrgMember(ctx context.Context, org *models.Organization, login string, role models.OrgRole) error
+	UpdateOrgMemberRole(ctx context.Context, orgID, userID uint64, role models.OrgRole) error
+	RemoveOrgMember(ctx context.Context, orgID, userID uint64) error
+	DeleteOrganization(ctx context.Context, orgID uint64) error
+	GetEmergencyContacts(ctx context.Context) ([]*models.EmergencyAccess, error)
+	GetEmergencyGrants(ctx context.Context) ([]*models.EmergencyAccess, error)
+	AddEmergencyContact(ctx context.Context, login string, waitDays int) error
+	RequestEmergencyAccess(ctx context.Context, id uint64) error
+	RejectEmergencyAccess(ctx context.Context, id uint64) error
+	DeleteEmergencyAccess(ctx context.Context, id uint64) error
 	String() string
 }
 
@@ -25,18 +94,58 @@ type Storage interface {
 type RemoteStorage struct {
 	client    grpc.ClientGRPCInterface
 	deriveKey []byte
+	// owner - логин владельца хранилища, к которому вместе с идентификатором и типом секрета
+	// привязываются шифротексты секрета.
+	owner string
+	// legacyKey - ключ, которым секреты шифровались до разделения хэша аутентификации и ключа шифрования.
+	// Используется только для расшифровки: такие секреты перешифровываются основным ключом при чтении.
+	legacyKey []byte
+	// vaultKey - ключ хранилища, которым зашифрованы ключи данных секретов. Пустой, пока хранилище
+	// не открыто через UnlockVault: до этого секреты шифруются ключом мастер-пароля.
+	vaultKey []byte
+	// privateKey - закрытый ключ X25519 пользователя, которым расшифровываются ключи данных секретов,
+	// переданных ему другими пользователями. Пустой, пока хранилище не открыто через UnlockVault.
+	privateKey []byte
+	// organization - организация, хранилище которой открыто, а personal - личное хранилище пользователя,
+	// из которого оно открыто. Оба пустые для личного хранилища.
+	organization *models.Organization
+	personal     *RemoteStorage
+	// emergency - экстренный доступ, по которому открыто хранилище другого пользователя; пустой для
+	// собственных хранилищ пользователя. Хранилище, открытое по экстренному доступу, доступно только для чтения.
+	emergency *models.EmergencyAccess
+	// uploads хранит незавершённые загрузки файлов по их путям, чтобы повторная загрузка
+	// того же файла продолжилась с места обрыва.
+	uploads   map[string]*pendingUpload
+	uploadsMu sync.Mutex
+	// secrets хранит зашифрованные секреты, полученные при последней синхронизации, по их идентификаторам,
+	// а revision - ревизию хранилища на сервере, с которой запрашиваются следующие изменения.
+	secrets  map[uint64]*models.Secret
+	revision uint64
+	syncMu   sync.Mutex
 }
 
-// NewRemoteStorage создает новый экземпляр RemoteStorage с предварительно вычисленным ключом шифрования.
+// NewRemoteStorage создает новый экземпляр RemoteStorage с ключом шифрования, полученным клиентом при входе.
 func NewRemoteStorage(client grpc.ClientGRPCInterface) (*RemoteStorage, error) {
-	deriveKey, err := crypto.DeriveKey(client.GetPassword(), "")
-	if err != nil {
-		return nil, err
+	deriveKey := client.GetEncryptionKey()
+	if len(deriveKey) == 0 {
+		return nil, ErrNoEncryptionKey
 	}
-	return &RemoteStorage{
+
+	store := &RemoteStorage{
 		client:    client,
 		deriveKey: deriveKey,
-	}, nil
+		owner:     client.GetLogin(),
+	}
+
+	if password := client.GetPassword(); password != "" {
+		legacyKey, err := crypto.DeriveKey(password, "")
+		if err != nil {
+			return nil, err
+		}
+		store.legacyKey = legacyKey
+	}
+
+	return store, nil
 }
 
 // Get извлекает секрет по его идентификатору, расшифровывает его и возвращает.
@@ -54,85 +163,953 @@ func (store *RemoteStorage) Get(_ context.Context, id uint64) (*models.Secret, e
 	return secret, nil
 }
 
-// GetAll извлекает все секреты пользователя, расшифровывает их и возвращает.
-func (store *RemoteStorage) GetAll(_ context.Context) ([]*models.Secret, error) {
-	secrets, err := store.client.LoadSecrets(context.Background())
+// List возвращает страницу заголовков секретов пользователя, подходящих под запрос query, с расшифрованными
+// заголовками и метаданными. Слова поиска query.Search заменяются токенами слепого индекса и на сервер
+// не передаются. Заголовки не содержат зашифрованных данных: секрет целиком загружается через Get при открытии.
+// Начало заголовка и порядок по заголовку на сервер тоже не передаются, так как заголовки на сервере
+// зашифрованы: такие страницы отбираются и упорядочиваются после расшифровки (см. listByTitle).
+func (store *RemoteStorage) List(ctx context.Context, query *models.SecretListQuery) (*models.SecretsPage, error) {
+	remoteQuery := *query
+	if remoteQuery.Search != "" {
+		tokens, err := crypto.SearchTokens(remoteQuery.Search, store.searchKey())
+		if err != nil {
+			return nil, fmt.Errorf("List(): failed to build search tokens: %w", err)
+		}
+		remoteQuery.SearchTokens = tokens
+		remoteQuery.Search = ""
+	}
+
+	if query.TitlePrefix != "" || query.Order == models.SecretOrderTitleAsc || query.Order == models.SecretOrderTitleDesc {
+		return store.listByTitle(ctx, &remoteQuery)
+	}
+
+	page, err := store.client.ListSecrets(ctx, &remoteQuery)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, s := range secrets {
-		err = store.decryptPayload(s)
+	for _, secret := range page.Secrets {
+		if _, err = store.openLabels(secret); err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
+
+// listByTitle загружает одну страницу сервера, подходящую под запрос без начала заголовка и порядка
+// по заголовку, отбирает её секреты по расшифрованному заголовку и упорядочивает их по заголовку.
+// Законченные слова начала заголовка передаются на сервер токенами слепого индекса, поэтому сервер
+// отбрасывает секреты без этих слов. Токен следующей страницы - токен сервера, и хранилище не загружается
+// целиком: страница может содержать меньше query.PageSize секретов или ни одного, хотя следующая страница есть,
+// а порядок по заголовку соблюдается только внутри страницы.
+func (store *RemoteStorage) listByTitle(ctx context.Context, query *models.SecretListQuery) (*models.SecretsPage, error) {
+	remoteQuery := *query
+	remoteQuery.TitlePrefix = ""
+	remoteQuery.Order = models.SecretOrderUpdatedDesc
+
+	if words := completeWords(query.TitlePrefix); words != "" {
+		tokens, err := crypto.SearchTokens(words, store.searchKey())
 		if err != nil {
+			return nil, fmt.Errorf("List(): failed to build search tokens: %w", err)
+		}
+		tokens = append(tokens, remoteQuery.SearchTokens...)
+		slices.Sort(tokens)
+		remoteQuery.SearchTokens = slices.Compact(tokens)
+	}
+
+	page, err := store.client.ListSecrets(ctx, &remoteQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make(models.Secrets, 0, len(page.Secrets))
+	for _, secret := range page.Secrets {
+		if _, err = store.openLabels(secret); err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(secret.Title, query.TitlePrefix) {
+			secrets = append(secrets, secret)
+		}
+	}
+
+	if query.Order == models.SecretOrderTitleAsc || query.Order == models.SecretOrderTitleDesc {
+		sort.Slice(secrets, func(i, j int) bool {
+			if secrets[i].Title != secrets[j].Title {
+				return (secrets[i].Title < secrets[j].Title) != (query.Order == models.SecretOrderTitleDesc)
+			}
+			return (secrets[i].ID < secrets[j].ID) != (query.Order == models.SecretOrderTitleDesc)
+		})
+	}
+
+	return &models.SecretsPage{Secrets: secrets, NextPageToken: page.NextPageToken}, nil
+}
+
+// completeWords возвращает начало заголовка prefix до последнего разделителя слов: слова в нём законченные
+// и должны входить в заголовок целиком, а последнее слово без разделителя может быть началом более длинного.
+func completeWords(prefix string) string {
+	end := strings.LastIndexFunc(prefix, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if end < 0 {
+		return ""
+	}
+	return prefix[:end]
+}
+
+// GetAll синхронизирует секреты пользователя с сервером и возвращает их расшифрованными,
+// начиная с изменённых последними. С сервера загружаются только изменения после предыдущей синхронизации,
+// которые применяются к уже известным хранилищу секретам.
+func (store *RemoteStorage) GetAll(ctx context.Context) ([]*models.Secret, error) {
+	store.syncMu.Lock()
+	defer store.syncMu.Unlock()
+
+	delta, err := store.client.SyncSecrets(ctx, store.revision)
+	if err != nil {
+		return nil, err
+	}
+	store.merge(delta)
+
+	secrets := make([]*models.Secret, 0, len(store.secrets))
+	for _, cached := range store.secrets {
+		secret := *cached
+		if err = store.decryptPayload(&secret); err != nil {
 			return nil, err
 		}
+		secrets = append(secrets, &secret)
 	}
 
+	sort.Slice(secrets, func(i, j int) bool {
+		if !secrets[i].UpdatedAt.Equal(secrets[j].UpdatedAt) {
+			return secrets[i].UpdatedAt.After(secrets[j].UpdatedAt)
+		}
+		return secrets[i].ID > secrets[j].ID
+	})
+
 	return secrets, nil
 }
 
-// Create создает новый секрет в хранилище, предварительно зашифровав его.
-func (store *RemoteStorage) Create(_ context.Context, secret *models.Secret) (err error) {
+// merge применяет изменения секретов к известным хранилищу секретам и запоминает ревизию хранилища.
+// Полный набор секретов заменяет известные секреты целиком.
+func (store *RemoteStorage) merge(delta *models.SecretsDelta) {
+	if delta.Full || store.secrets == nil {
+		store.secrets = make(map[uint64]*models.Secret, len(delta.Secrets))
+	}
+	for _, secret := range delta.Secrets {
+		store.secrets[secret.ID] = secret
+	}
+	for _, id := range delta.DeletedIDs {
+		delete(store.secrets, id)
+	}
+	store.revision = delta.Revision
+}
+
+// Create создает новый секрет в хранилище, предварительно зашифровав его. Шифротекст привязывается
+// к идентификатору секрета, поэтому секрету без идентификатора он резервируется на сервере до шифрования
+// и записывается в secret.
+func (store *RemoteStorage) Create(ctx context.Context, secret *models.Secret) (err error) {
+	if secret.ID == 0 {
+		if secret.ID, err = store.client.ReserveSecretID(ctx); err != nil {
+			return fmt.Errorf("Create(): failed to reserve secret ID: %w", err)
+		}
+	}
+
 	err = store.encryptPayload(secret)
 	if err != nil {
 		return
 	}
 
-	err = store.client.SaveSecret(context.Background(), secret)
-	return err
+	return store.saveSealed(secret)
 }
 
-// Update обновляет существующий секрет, предварительно зашифровав его.
-func (store *RemoteStorage) Update(_ context.Context, secret *models.Secret) (err error) {
+// Update обновляет существующий секрет, предварительно зашифровав его. Изменения секрета, переданного
+// другим пользователем, сохраняются через доступ к нему; новая ревизия секрета записывается в secret.
+func (store *RemoteStorage) Update(ctx context.Context, secret *models.Secret) (err error) {
+	if secret.Share != nil {
+		return store.updateShared(ctx, secret)
+	}
+
 	err = store.encryptPayload(secret)
 	if err != nil {
 		return
 	}
 
-	err = store.client.SaveSecret(context.Background(), secret)
-	return err
+	return store.saveSealed(secret)
 }
 
-// Delete удаляет секрет по его идентификатору.
+// updateShared сохраняет изменения секрета, переданного пользователю с правом изменения.
+// Возвращает ErrShareReadOnly, если секрет передан только для чтения.
+func (store *RemoteStorage) updateShared(ctx context.Context, secret *models.Secret) error {
+	if !secret.Share.CanEdit {
+		return gophKeeperErrors.ErrShareReadOnly
+	}
+
+	if err := store.encryptPayload(secret); err != nil {
+		return err
+	}
+	sealed, err := store.sealLabels(secret)
+	if err != nil {
+		return err
+	}
+
+	revision, err := store.client.UpdateSharedSecret(ctx, sealed)
+	if err != nil {
+		return err
+	}
+	secret.Revision = revision
+
+	return nil
+}
+
+// saveSealed сохраняет секрет с уже зашифрованными данными, шифруя его заголовок и метаданные.
+// Заголовок и метаданные шифруются в копии секрета, поэтому у вызывающего они остаются открытыми.
+func (store *RemoteStorage) saveSealed(secret *models.Secret) error {
+	sealed, err := store.sealLabels(secret)
+	if err != nil {
+		return err
+	}
+
+	return store.client.SaveSecret(context.Background(), sealed)
+}
+
+// Delete перемещает секрет в корзину по его идентификатору.
 func (store *RemoteStorage) Delete(_ context.Context, id uint64) (err error) {
 	err = store.client.DeleteSecret(context.Background(), id)
 	return err
 }
 
+// GetVersions извлекает сохранённые версии секрета от новых к старым и расшифровывает их.
+func (store *RemoteStorage) GetVersions(ctx context.Context, id uint64) ([]*models.SecretVersion, error) {
+	versions, err := store.client.LoadSecretVersions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range versions {
+		if _, err = store.decrypt(&v.Secret); err != nil {
+			return nil, err
+		}
+	}
+
+	return versions, nil
+}
+
+// RestoreVersion восстанавливает секрет из сохранённой версии. Данные версии уже зашифрованы
+// и не покидают сервер, поэтому восстановление не требует повторного шифрования.
+func (store *RemoteStorage) RestoreVersion(ctx context.Context, id, versionID uint64) error {
+	return store.client.RestoreSecretVersion(ctx, id, versionID)
+}
+
+// GetTrash извлекает секреты из корзины и расшифровывает их.
+func (store *RemoteStorage) GetTrash(ctx context.Context) ([]*models.Secret, error) {
+	secrets, err := store.client.LoadTrash(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range secrets {
+		if _, err = store.decrypt(s); err != nil {
+			return nil, err
+		}
+	}
+
+	return secrets, nil
+}
+
+// Restore возвращает секрет из корзины.
+func (store *RemoteStorage) Restore(ctx context.Context, id uint64) error {
+	return store.client.RestoreSecret(ctx, id)
+}
+
+// Purge окончательно удаляет секрет из корзины.
+func (store *RemoteStorage) Purge(ctx context.Context, id uint64) error {
+	return store.client.PurgeSecret(ctx, id)
+}
+
+// Fingerprint возвращает отпечаток открытого ключа пользователя. Он вычисляется из закрытого ключа,
+// а не загружается с сервера, поэтому пользователь может сообщить его отправителям секретов для сверки.
+// Возвращает ErrVaultLocked, если хранилище не открыто.
+func (store *RemoteStorage) Fingerprint() (string, error) {
+	if store.privateKey == nil {
+		return "", ErrVaultLocked
+	}
+	publicKey, err := crypto.PublicKey(store.privateKey)
+	if err != nil {
+		return "", fmt.Errorf("Fingerprint(): %w", err)
+	}
+	return crypto.Fingerprint(publicKey), nil
+}
+
+// ShareSecret передаёт секрет пользователю recipient: ключ данных секрета шифруется открытым ключом
+// получателя и закрытым ключом пользователя с привязкой к владельцу, получателю и секрету, поэтому получатель
+// убеждается, что секрет передал владелец, а не сервер. При canEdit получатель может изменять секрет.
+// Открытый ключ получателя загружается с сервера и принимается, только если его отпечаток совпадает
+// с fingerprint, полученным от получателя по независимому каналу. Возвращает ErrFingerprintMismatch,
+// если отпечатки не совпадают, ErrVaultLocked, если хранилище не открыто, ErrNoDataKey, если секрет ещё
+// не перешифрован собственным ключом данных, ErrOrganizationUnsupported для секретов хранилища организации
+// и ErrEmergencyReadOnly для секретов хранилища, открытого по экстренному доступу.
+func (store *RemoteStorage) ShareSecret(ctx context.Context, secret *models.Secret, recipient, fingerprint string, canEdit bool) error {
+	if store.organization != nil {
+		return ErrOrganizationUnsupported
+	}
+	if store.emergency != nil {
+		return ErrEmergencyReadOnly
+	}
+	if store.vaultKey == nil || store.privateKey == nil {
+		return ErrVaultLocked
+	}
+	if len(secret.DataKey) == 0 {
+		return ErrNoDataKey
+	}
+
+	recipient = strings.TrimSpace(recipient)
+	publicKey, err := store.client.GetPublicKey(ctx, recipient)
+	if err != nil {
+		return fmt.Errorf("ShareSecret(): failed to get public key of %s: %w", recipient, err)
+	}
+	if err = crypto.VerifyFingerprint(publicKey, fingerprint); err != nil {
+		return fmt.Errorf("ShareSecret(): public key of %s: %w", recipient, err)
+	}
+
+	dataKey, err := store.secretKey(secret)
+	if err != nil {
+		return fmt.Errorf("ShareSecret(): failed to decrypt data key: %w", err)
+	}
+	aad := crypto.ShareAAD(store.owner, recipient, secret.ID, secret.SecretType)
+	wrapped, err := crypto.WrapKeyFrom(dataKey, store.privateKey, publicKey, aad)
+	if err != nil {
+		return fmt.Errorf("ShareSecret(): failed to encrypt data key: %w", err)
+	}
+
+	_, err = store.client.ShareSecret(ctx, &models.Share{
+		SecretID:       secret.ID,
+		RecipientLogin: recipient,
+		DataKey:        wrapped,
+		CanEdit:        canEdit,
+	})
+	return err
+}
+
+// GetShares извлекает доступы, выданные к секрету secretID.
+func (store *RemoteStorage) GetShares(ctx context.Context, secretID uint64) ([]*models.Share, error) {
+	return store.client.ListShares(ctx, secretID)
+}
+
+// RevokeShare удаляет доступ к секрету: владелец отзывает его, получатель отказывается от секрета.
+func (store *RemoteStorage) RevokeShare(ctx context.Context, shareID uint64) error {
+	return store.client.RevokeShare(ctx, shareID)
+}
+
+// GetSharedWithMe извлекает секреты, переданные пользователю другими пользователями, и расшифровывает их.
+// Секреты расшифровываются закрытым ключом пользователя, поэтому хранилище должно быть открыто. Ключ данных
+// проверяется открытым ключом владельца, который записывается в доступ секрета: пользователь сверяет
+// его отпечаток с отпечатком, полученным от владельца по независимому каналу.
+func (store *RemoteStorage) GetSharedWithMe(ctx context.Context) ([]*models.Secret, error) {
+	secrets, err := store.client.ListSharedWithMe(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerKeys := make(map[string][]byte)
+	for _, s := range secrets {
+		publicKey, ok := ownerKeys[s.Share.OwnerLogin]
+		if !ok {
+			publicKey, err = store.client.GetPublicKey(ctx, s.Share.OwnerLogin)
+			if err != nil {
+				return nil, fmt.Errorf("GetSharedWithMe(): failed to get public key of %s: %w", s.Share.OwnerLogin, err)
+			}
+			ownerKeys[s.Share.OwnerLogin] = publicKey
+		}
+		s.Share.OwnerPublicKey = publicKey
+
+		if _, err = store.decrypt(s); err != nil {
+			return nil, err
+		}
+	}
+
+	return secrets, nil
+}
+
+// GetFolders извлекает папки пользователя. Названия папок не шифруются.
+func (store *RemoteStorage) GetFolders(ctx context.Context) ([]*models.Folder, error) {
+	return store.client.ListFolders(ctx)
+}
+
+// CreateFolder создаёт папку name в родительской папке parentID; нулевой parentID создаёт папку верхнего уровня.
+func (store *RemoteStorage) CreateFolder(ctx context.Context, parentID uint64, name string) (*models.Folder, error) {
+	return store.client.CreateFolder(ctx, parentID, name)
+}
+
+// UpdateFolder переименовывает папку или перемещает её в родительскую папку parentID.
+func (store *RemoteStorage) UpdateFolder(ctx context.Context, id, parentID uint64, name string) error {
+	return store.client.UpdateFolder(ctx, id, parentID, name)
+}
+
+// DeleteFolder удаляет папку вместе с вложенными папками; секреты из них остаются без папки.
+func (store *RemoteStorage) DeleteFolder(ctx context.Context, id uint64) error {
+	return store.client.DeleteFolder(ctx, id)
+}
+
+// GetTags извлекает метки пользователя. Названия меток не шифруются.
+func (store *RemoteStorage) GetTags(ctx context.Context) ([]*models.Tag, error) {
+	return store.client.ListTags(ctx)
+}
+
+// CreateTag создаёт метку name.
+func (store *RemoteStorage) CreateTag(ctx context.Context, name string) (*models.Tag, error) {
+	return store.client.CreateTag(ctx, name)
+}
+
+// UpdateTag переименовывает метку.
+func (store *RemoteStorage) UpdateTag(ctx context.Context, id uint64, name string) error {
+	return store.client.UpdateTag(ctx, id, name)
+}
+
+// DeleteTag удаляет метку и снимает её с секретов.
+func (store *RemoteStorage) DeleteTag(ctx context.Context, id uint64) error {
+	return store.client.DeleteTag(ctx, id)
+}
+
+// UpgradeKDF перешифровывает хранилище ключом, выведенным с новыми параметрами KDF, если этого требует сервер.
+// Если хранилище открыто, новым ключом перешифровывается ключ хранилища и секреты без ключа данных, иначе -
+// все секреты. Они отправляются на сервер одним запросом вместе с хэшем аутентификации, выведенным
+// с текущими параметрами, и новым хэшем аутентификации, поэтому при ошибке хранилище остаётся
+// зашифрованным прежним ключом.
+func (store *RemoteStorage) UpgradeKDF(ctx context.Context) error {
+	if !store.client.KDFUpgradeRequired() {
+		return nil
+	}
+
+	currentParams := store.client.GetKDFParams()
+	if currentParams == nil {
+		return ErrNoKDFParams
+	}
+	current, err := crypto.DeriveKeys(store.client.GetPassword(), currentParams)
+	if err != nil {
+		return fmt.Errorf("UpgradeKDF(): failed to derive current keys: %w", err)
+	}
+
+	params, err := store.client.NewKDFParams(ctx)
+	if err != nil {
+		return fmt.Errorf("UpgradeKDF(): failed to get kdf params: %w", err)
+	}
+
+	keys, err := crypto.DeriveKeys(store.client.GetPassword(), params)
+	if err != nil {
+		return fmt.Errorf("UpgradeKDF(): failed to derive keys: %w", err)
+	}
+
+	vaultKey, err := store.wrapVaultKey(keys.EncryptionKey)
+	if err != nil {
+		return fmt.Errorf("UpgradeKDF(): failed to encrypt vault key: %w", err)
+	}
+
+	secrets, err := store.reencrypt(ctx, keys.EncryptionKey, store.vaultKey)
+	if err != nil {
+		return err
+	}
+
+	if err = store.client.UpgradeKDF(ctx, current.AuthHash, params, keys, vaultKey, secrets); err != nil {
+		return fmt.Errorf("UpgradeKDF(): failed to upgrade vault: %w", err)
+	}
+
+	store.deriveKey = keys.EncryptionKey
+	store.legacyKey = nil
+
+	return nil
+}
+
+// ChangePassword заменяет мастер-пароль пользователя. Текущий пароль проверяется локально по ключу
+// шифрования и на сервере по хэшу аутентификации. Ключом, выведенным из нового пароля с новой солью,
+// перешифровывается ключ хранилища и секреты без ключа данных, а если хранилище не открыто - все секреты.
+// Они отправляются на сервер одним запросом, поэтому при ошибке хранилище остаётся зашифрованным прежним ключом.
+// Из хранилища организации пароль меняется в личном хранилище пользователя.
+func (store *RemoteStorage) ChangePassword(ctx context.Context, currentPassword, newPassword string) error {
+	if store.personal != nil {
+		return store.personal.ChangePassword(ctx, currentPassword, newPassword)
+	}
+
+	params := store.client.GetKDFParams()
+	if params == nil {
+		return ErrNoKDFParams
+	}
+
+	current, err := crypto.DeriveKeys(currentPassword, params)
+	if err != nil {
+		return fmt.Errorf("ChangePassword(): failed to derive current keys: %w", err)
+	}
+	if subtle.ConstantTimeCompare(current.EncryptionKey, store.deriveKey) != 1 {
+		return grpc.ErrWrongPassword
+	}
+
+	newParams, err := store.client.NewKDFParams(ctx)
+	if err != nil {
+		return fmt.Errorf("ChangePassword(): failed to get kdf params: %w", err)
+	}
+
+	keys, err := crypto.DeriveKeys(newPassword, newParams)
+	if err != nil {
+		return fmt.Errorf("ChangePassword(): failed to derive keys: %w", err)
+	}
+
+	vaultKey, err := store.wrapVaultKey(keys.EncryptionKey)
+	if err != nil {
+		return fmt.Errorf("ChangePassword(): failed to encrypt vault key: %w", err)
+	}
+
+	secrets, err := store.reencrypt(ctx, keys.EncryptionKey, store.vaultKey)
+	if err != nil {
+		return err
+	}
+
+	if err = store.client.ChangePassword(ctx, current.AuthHash, newParams, keys, vaultKey, secrets); err != nil {
+		return fmt.Errorf("ChangePassword(): failed to change password: %w", err)
+	}
+
+	store.deriveKey = keys.EncryptionKey
+	store.legacyKey = nil
+	store.client.SetPassword(newPassword)
+
+	return nil
+}
+
+// UnlockVault открывает хранилище: загружает ключ хранилища и расшифровывает его ключом мастер-пароля.
+// Если ключ хранилища ещё не создан, он генерируется, и все секреты перешифровываются собственными ключами
+// данных. Если ключ одновременно создан с другого устройства, используется сохранённый им ключ.
+// Затем ключом хранилища расшифровывается закрытый ключ пользователя, через который ему передают секреты.
+func (store *RemoteStorage) UnlockVault(ctx context.Context) error {
+	wrapped, err := store.client.GetVaultKey(ctx)
+	if err != nil {
+		return fmt.Errorf("UnlockVault(): failed to get vault key: %w", err)
+	}
+
+	if len(wrapped) == 0 {
+		vaultKey, err := store.createVault(ctx)
+		if err == nil {
+			store.vaultKey = vaultKey
+			return store.unlockKeyPair(ctx)
+		}
+		if !errors.Is(err, grpc.ErrVaultKeyExists) {
+			return err
+		}
+		if wrapped, err = store.client.GetVaultKey(ctx); err != nil {
+			return fmt.Errorf("UnlockVault(): failed to get vault key: %w", err)
+		}
+	}
+
+	vaultKey, err := crypto.UnwrapKey(wrapped, store.deriveKey, crypto.VaultKeyAAD(store.owner))
+	if err != nil {
+		return fmt.Errorf("UnlockVault(): failed to decrypt vault key: %w", err)
+	}
+	store.vaultKey = vaultKey
+
+	return store.unlockKeyPair(ctx)
+}
+
+// unlockKeyPair загружает пару ключей пользователя и расшифровывает закрытый ключ ключом хранилища.
+// Если пара ещё не создана, она генерируется и сохраняется на сервере с закрытым ключом, зашифрованным
+// ключом хранилища. Если пара одновременно создана с другого устройства, используется сохранённая им пара.
+func (store *RemoteStorage) unlockKeyPair(ctx context.Context) error {
+	keyPair, err := store.client.GetKeyPair(ctx)
+	if err != nil {
+		return fmt.Errorf("UnlockVault(): failed to get key pair: %w", err)
+	}
+
+	if len(keyPair.PublicKey) == 0 {
+		privateKey, err := store.createKeyPair(ctx)
+		if err == nil {
+			store.privateKey = privateKey
+			return nil
+		}
+		if !errors.Is(err, grpc.ErrKeyPairExists) {
+			return err
+		}
+		if keyPair, err = store.client.GetKeyPair(ctx); err != nil {
+			return fmt.Errorf("UnlockVault(): failed to get key pair: %w", err)
+		}
+	}
+
+	privateKey, err := crypto.UnwrapKey(keyPair.PrivateKey, store.vaultKey, crypto.PrivateKeyAAD(store.owner))
+	if err != nil {
+		return fmt.Errorf("UnlockVault(): failed to decrypt private key: %w", err)
+	}
+	store.privateKey = privateKey
+
+	return nil
+}
+
+// createKeyPair генерирует пару ключей X25519 и сохраняет её на сервере с закрытым ключом, зашифрованным
+// ключом хранилища. Возвращает расшифрованный закрытый ключ.
+func (store *RemoteStorage) createKeyPair(ctx context.Context) ([]byte, error) {
+	publicKey, privateKey, err := crypto.NewKeyPair()
+	if err != nil {
+		return nil, fmt.Errorf("UnlockVault(): failed to generate key pair: %w", err)
+	}
+	wrapped, err := crypto.WrapKey(privateKey, store.vaultKey, crypto.PrivateKeyAAD(store.owner))
+	if err != nil {
+		return nil, fmt.Errorf("UnlockVault(): failed to encrypt private key: %w", err)
+	}
+
+	if err = store.client.CreateKeyPair(ctx, &models.KeyPair{PublicKey: publicKey, PrivateKey: wrapped}); err != nil {
+		return nil, fmt.Errorf("UnlockVault(): failed to create key pair: %w", err)
+	}
+
+	return privateKey, nil
+}
+
+// createVault генерирует ключ хранилища и отправляет его на сервер, зашифрованным ключом мастер-пароля,
+// вместе со всеми секретами, перешифрованными собственными ключами данных. Возвращает открытый ключ хранилища.
+func (store *RemoteStorage) createVault(ctx context.Context) ([]byte, error) {
+	vaultKey, err := crypto.NewKey()
+	if err != nil {
+		return nil, fmt.Errorf("UnlockVault(): failed to generate vault key: %w", err)
+	}
+	wrapped, err := crypto.WrapKey(vaultKey, store.deriveKey, crypto.VaultKeyAAD(store.owner))
+	if err != nil {
+		return nil, fmt.Errorf("UnlockVault(): failed to encrypt vault key: %w", err)
+	}
+
+	secrets, err := store.reencrypt(ctx, store.deriveKey, vaultKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = store.client.CreateVaultKey(ctx, wrapped, secrets); err != nil {
+		return nil, fmt.Errorf("UnlockVault(): failed to create vault key: %w", err)
+	}
+
+	return vaultKey, nil
+}
+
+// EncryptLabels шифрует заголовки и метаданные секретов, сохранённых до шифрования заголовков,
+// включая находящиеся в корзине, и вычисляет для них токены слепого индекса. Все такие секреты
+// отправляются на сервер одним запросом; если открытых заголовков нет, запрос не выполняется.
+// В открытом хранилище заголовки всех секретов зашифрованы при создании ключа хранилища, поэтому
+// открытый заголовок мог подставить только сервер: он не шифруется, а возвращается ErrLegacyCiphertext.
+func (store *RemoteStorage) EncryptLabels(ctx context.Context) error {
+	secrets, err := store.loadAll(ctx)
+	if err != nil {
+		return fmt.Errorf("EncryptLabels(): failed to load secrets: %w", err)
+	}
+
+	var sealed []*models.Secret
+	for _, secret := range secrets {
+		if secret.LabelsEncrypted {
+			continue
+		}
+		if store.vaultKey != nil {
+			return fmt.Errorf("EncryptLabels(): secret %d has plaintext labels: %w", secret.ID, ErrLegacyCiphertext)
+		}
+		s, err := store.sealLabels(secret)
+		if err != nil {
+			return err
+		}
+		sealed = append(sealed, s)
+	}
+	if len(sealed) == 0 {
+		return nil
+	}
+
+	if err = store.client.EncryptSecretLabels(ctx, sealed); err != nil {
+		return fmt.Errorf("EncryptLabels(): failed to save secret labels: %w", err)
+	}
+	return nil
+}
+
+// loadAll загружает все секреты пользователя, включая находящиеся в корзине, без расшифровки.
+func (store *RemoteStorage) loadAll(ctx context.Context) ([]*models.Secret, error) {
+	secrets, err := store.client.LoadSecrets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	trash, err := store.client.LoadTrash(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(secrets, trash...), nil
+}
+
+// reencrypt загружает все секреты пользователя, включая находящиеся в корзине, расшифровывает их
+// текущим ключом и шифрует ключом key вместе с заголовками и метаданными. Если передан ключ хранилища
+// vaultKey, секреты с ключом данных пропускаются, а остальные шифруются новыми ключами данных,
+// зашифрованными vaultKey. Возвращает перешифрованные секреты по их идентификаторам с токенами слепого индекса.
+func (store *RemoteStorage) reencrypt(ctx context.Context, key, vaultKey []byte) (map[uint64]*models.Secret, error) {
+	secrets, err := store.loadAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	target := &RemoteStorage{deriveKey: key, owner: store.owner, vaultKey: vaultKey}
+	reencrypted := make(map[uint64]*models.Secret, len(secrets))
+	for _, secret := range secrets {
+		if vaultKey != nil && len(secret.DataKey) != 0 {
+			continue
+		}
+		if _, err = store.decrypt(secret); err != nil {
+			return nil, err
+		}
+		if err = target.encryptPayload(secret); err != nil {
+			return nil, err
+		}
+		if reencrypted[secret.ID], err = target.sealLabels(secret); err != nil {
+			return nil, err
+		}
+	}
+
+	return reencrypted, nil
+}
+
 func (store *RemoteStorage) String() string {
+	if store.organization != nil {
+		return "organization " + store.organization.Name
+	}
+	if store.emergency != nil {
+		return "emergency access to " + store.emergency.GrantorLogin
+	}
 	return "remote storage"
 }
 
-// encryptPayload шифрует данные секрета перед сохранением.
+// encryptPayload шифрует данные секрета перед сохранением в двоичный конверт текущего формата.
+// Данные, прочитанные в прежних форматах, при сохранении записываются в текущем. Если хранилище открыто,
+// секрету без ключа данных генерируется ключ данных, который сохраняется в secret. Секреты, переданные
+// другими пользователями, шифруются ключом данных владельца.
 func (store *RemoteStorage) encryptPayload(secret *models.Secret) (err error) {
 	data, err := marshalSecret(secret)
 	if err != nil {
 		return fmt.Errorf("encryptPayload(): error serializing data: %w", err)
 	}
 
-	encryptedData, err := crypto.Encrypt(string(data), store.deriveKey)
+	if store.vaultKey != nil && len(secret.DataKey) == 0 && secret.Share == nil {
+		if secret.DataKey, err = store.newDataKey(secret); err != nil {
+			return fmt.Errorf("encryptPayload(): error generating data key: %w", err)
+		}
+	}
+	key, err := store.secretKey(secret)
+	if err != nil {
+		return fmt.Errorf("encryptPayload(): %w", err)
+	}
+
+	secret.Payload, err = crypto.Seal(data, key, store.aad(secret, payloadField))
 	if err != nil {
 		return fmt.Errorf("encryptPayload(): error encrypting Data: %w", err)
 	}
 
-	secret.Payload = []byte(encryptedData)
+	// Сервер хранит ссылку на объект с содержимым файла, чтобы удалить его вместе с секретом.
+	if secret.Blob != nil {
+		secret.BlobID = secret.Blob.BlobID
+	}
 	return nil
 }
 
 // decryptPayload расшифровывает данные секрета после извлечения.
+// Секреты, зашифрованные устаревшим ключом или без привязки к секрету, перешифровываются основным ключом
+// с привязкой и сохраняются. Секреты других пользователей не перешифровываются.
 func (store *RemoteStorage) decryptPayload(secret *models.Secret) (err error) {
-	decryptedData, err := crypto.Decrypt(string(secret.Payload), store.deriveKey)
+	legacy, err := store.decrypt(secret)
 	if err != nil {
-		return fmt.Errorf("decryptPayload: failed to decrypt data: %w", err)
+		return err
+	}
 
+	if legacy && secret.Share == nil {
+		store.upgradeSecret(secret)
 	}
 
-	err = unmarshalSecret(secret, []byte(decryptedData))
+	return nil
+}
+
+// decrypt расшифровывает заголовок, метаданные и данные секрета в любом из поддерживаемых форматов,
+// проверяя их привязку к секрету. Данные расшифровываются основным ключом, а непривязанные при неудаче -
+// устаревшим. Возвращает true, если данные были зашифрованы устаревшим ключом или что-либо из них
+// зашифровано без привязки к секрету. Привязанный шифротекст другого секрета или поля приводит к ошибке
+// crypto.ErrBindingMismatch. Привязанные шифротексты прежних форматов не требуют немедленного
+// перешифрования: они записываются в текущем формате при следующем сохранении секрета.
+// Если хранилище открыто, прежние форматы не принимаются: секрет должен иметь ключ данных,
+// а заголовок, метаданные и данные - быть зашифрованы в конверт, иначе возвращается ErrLegacyCiphertext.
+func (store *RemoteStorage) decrypt(secret *models.Secret) (legacy bool, err error) {
+	unboundLabels, err := store.openLabels(secret)
 	if err != nil {
-		return fmt.Errorf("decryptPayload: failed to unmarshal data: %w", err)
+		return false, err
 	}
 
-	return nil
+	key, err := store.secretKey(secret)
+	if err != nil {
+		return false, fmt.Errorf("decryptPayload: failed to decrypt data key of secret %d: %w", secret.ID, err)
+	}
+
+	decryptedData, format, err := store.open(secret.Payload, key, store.aad(secret, payloadField))
+	if err != nil && format == crypto.FormatLegacy && store.legacyKey != nil {
+		var data string
+		data, err = crypto.Decrypt(string(secret.Payload), store.legacyKey)
+		decryptedData = []byte(data)
+	}
+	if err != nil {
+		return false, fmt.Errorf("decryptPayload: failed to decrypt data of secret %d: %w", secret.ID, err)
+	}
+	legacy = format == crypto.FormatLegacy || unboundLabels
+
+	err = unmarshalSecret(secret, decryptedData)
+	if err != nil {
+		return false, fmt.Errorf("decryptPayload: failed to unmarshal data: %w", err)
+	}
+
+	return legacy, nil
+}
+
+// upgradeSecret перешифровывает секрет, расшифрованный устаревшим ключом, без привязки к секрету или
+// без ключа данных, основным ключом или ключом данных с привязкой и сохраняет его. Сервер увеличивает ревизию секрета на единицу при каждом
+// изменении, поэтому ревизия секрета также увеличивается, и последующее сохранение не вызывает конфликта.
+// Ошибка сохранения не прерывает чтение: перешифрование будет повторено при следующем обращении.
+func (store *RemoteStorage) upgradeSecret(secret *models.Secret) {
+	upgraded := *secret
+	if err := store.encryptPayload(&upgraded); err != nil {
+		return
+	}
+	if err := store.saveSealed(&upgraded); err != nil {
+		return
+	}
+	secret.Payload, secret.DataKey = upgraded.Payload, upgraded.DataKey
+	secret.Revision++
+}
+
+// sealLabels возвращает копию секрета с заголовком и метаданными, зашифрованными ключом секрета
+// с привязкой к секрету, и токенами слепого индекса слов заголовка. Секретам других пользователей
+// токены не вычисляются: они строятся ключом хранилища владельца.
+func (store *RemoteStorage) sealLabels(secret *models.Secret) (*models.Secret, error) {
+	sealed := *secret
+
+	key, err := store.secretKey(secret)
+	if err != nil {
+		return nil, fmt.Errorf("sealLabels(): %w", err)
+	}
+	if sealed.Title, err = crypto.SealString(secret.Title, key, store.aad(secret, titleField)); err != nil {
+		return nil, fmt.Errorf("sealLabels(): error encrypting title: %w", err)
+	}
+	if sealed.Metadata, err = crypto.SealString(secret.Metadata, key, store.aad(secret, metadataField)); err != nil {
+		return nil, fmt.Errorf("sealLabels(): error encrypting metadata: %w", err)
+	}
+	if secret.Share == nil {
+		if sealed.SearchTokens, err = crypto.SearchTokens(secret.Title, store.searchKey()); err != nil {
+			return nil, fmt.Errorf("sealLabels(): error building search tokens: %w", err)
+		}
+	}
+	sealed.LabelsEncrypted = true
+
+	return &sealed, nil
+}
+
+// openLabels расшифровывает заголовок и метаданные секрета, если они зашифрованы, проверяя их привязку
+// к секрету. Признак LabelsEncrypted сбрасывается: секрет содержит открытые заголовок и метаданные.
+// Возвращает true, если заголовок или метаданные были зашифрованы без привязки к секрету.
+func (store *RemoteStorage) openLabels(secret *models.Secret) (unbound bool, err error) {
+	if !secret.LabelsEncrypted {
+		if store.vaultKey != nil {
+			return false, fmt.Errorf("openLabels(): secret %d has plaintext labels: %w", secret.ID, ErrLegacyCiphertext)
+		}
+		return false, nil
+	}
+
+	key, err := store.secretKey(secret)
+	if err != nil {
+		return false, fmt.Errorf("openLabels(): failed to decrypt data key of secret %d: %w", secret.ID, err)
+	}
+
+	title, titleFormat, err := store.openString(secret.Title, key, store.aad(secret, titleField))
+	if err != nil {
+		return false, fmt.Errorf("openLabels(): failed to decrypt title of secret %d: %w", secret.ID, err)
+	}
+	metadata, metadataFormat, err := store.openString(secret.Metadata, key, store.aad(secret, metadataField))
+	if err != nil {
+		return false, fmt.Errorf("openLabels(): failed to decrypt metadata of secret %d: %w", secret.ID, err)
+	}
+
+	secret.Title, secret.Metadata, secret.LabelsEncrypted = title, metadata, false
+	return titleFormat == crypto.FormatLegacy || metadataFormat == crypto.FormatLegacy, nil
+}
+
+// secretKey возвращает ключ, которым шифруются данные, заголовок и метаданные секрета: ключ данных секрета,
+// расшифрованный ключом хранилища, а для секретов без ключа данных - ключ мастер-пароля. Ключ данных секрета,
+// переданного другим пользователем, расшифровывается закрытым ключом пользователя и открытым ключом владельца.
+func (store *RemoteStorage) secretKey(secret *models.Secret) ([]byte, error) {
+	if secret.Share != nil {
+		if store.privateKey == nil {
+			return nil, ErrVaultLocked
+		}
+		aad := crypto.ShareAAD(secret.Share.OwnerLogin, store.owner, secret.ID, secret.SecretType)
+		return crypto.UnwrapKeyFrom(secret.Share.DataKey, store.privateKey, secret.Share.OwnerPublicKey, aad)
+	}
+	if len(secret.DataKey) == 0 {
+		if store.vaultKey != nil {
+			return nil, ErrLegacyCiphertext
+		}
+		return store.deriveKey, nil
+	}
+	if store.vaultKey == nil {
+		return nil, ErrVaultLocked
+	}
+	return crypto.UnwrapKey(secret.DataKey, store.vaultKey, store.aad(secret, dataKeyField))
+}
+
+// open расшифровывает поле секрета и возвращает формат шифротекста. Если хранилище открыто, принимается
+// только конверт с проверкой привязки, а шифротекст прежнего формата отклоняется с ErrLegacyCiphertext.
+func (store *RemoteStorage) open(data, key, aad []byte) ([]byte, crypto.Format, error) {
+	if store.vaultKey == nil {
+		return crypto.Open(data, key, aad)
+	}
+	plaintext, err := crypto.OpenSealed(data, key, aad)
+	if errors.Is(err, crypto.ErrNotSealed) {
+		err = ErrLegacyCiphertext
+	}
+	return plaintext, crypto.FormatEnvelope, err
+}
+
+// openString расшифровывает текстовое поле секрета по тем же правилам, что и open.
+func (store *RemoteStorage) openString(encrypted string, key, aad []byte) (string, crypto.Format, error) {
+	if store.vaultKey == nil {
+		return crypto.OpenString(encrypted, key, aad)
+	}
+	plaintext, err := crypto.OpenSealedString(encrypted, key, aad)
+	if errors.Is(err, crypto.ErrNotSealed) {
+		err = ErrLegacyCiphertext
+	}
+	return plaintext, crypto.FormatEnvelope, err
+}
+
+// newDataKey генерирует ключ данных секрета и возвращает его зашифрованным ключом хранилища.
+func (store *RemoteStorage) newDataKey(secret *models.Secret) ([]byte, error) {
+	dataKey, err := crypto.NewKey()
+	if err != nil {
+		return nil, err
+	}
+	return crypto.WrapKey(dataKey, store.vaultKey, store.aad(secret, dataKeyField))
+}
+
+// searchKey возвращает ключ токенов слепого индекса: ключ хранилища, если оно открыто, иначе ключ мастер-пароля.
+// Ключ хранилища не меняется при смене пароля, поэтому токены не требуют пересчёта.
+func (store *RemoteStorage) searchKey() []byte {
+	if store.vaultKey != nil {
+		return store.vaultKey
+	}
+	return store.deriveKey
+}
+
+// wrapVaultKey возвращает ключ хранилища, зашифрованный ключом key, или nil, если хранилище не открыто.
+func (store *RemoteStorage) wrapVaultKey(key []byte) ([]byte, error) {
+	if store.vaultKey == nil {
+		return nil, nil
+	}
+	return crypto.WrapKey(store.vaultKey, key, crypto.VaultKeyAAD(store.owner))
+}
+
+// aad возвращает дополнительные аутентифицируемые данные поля field секрета: владельца хранилища,
+// идентификатор и тип секрета. Секреты других пользователей привязаны к логину их владельца.
+func (store *RemoteStorage) aad(secret *models.Secret, field string) []byte {
+	owner := store.owner
+	if secret.Share != nil {
+		owner = secret.Share.OwnerLogin
+	}
+	return crypto.SecretAAD(owner, secret.ID, secret.SecretType, field)
 }
 
 // marshalSecret кодирует данные секрета в JSON.
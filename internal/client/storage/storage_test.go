@@ -2,13 +2,20 @@ package storage
 
 import (
 	"beliaev-aa/GophKeeper/internal/client/crypto"
+	"beliaev-aa/GophKeeper/internal/client/grpc"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
 	"beliaev-aa/GophKeeper/pkg/models"
 	"beliaev-aa/GophKeeper/tests/mocks"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/golang/mock/gomock"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestRemoteStorage_Get_ErrorDeriveKey(t *testing.T) {
@@ -16,11 +23,13 @@ func TestRemoteStorage_Get_ErrorDeriveKey(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+	mockClient.EXPECT().GetEncryptionKey().Return(nil).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
 	mockClient.EXPECT().GetPassword().Return("").AnyTimes()
 
 	_, err := NewRemoteStorage(mockClient)
-	if err == nil {
-		t.Error("expected error, got none")
+	if !errors.Is(err, ErrNoEncryptionKey) {
+		t.Errorf("expected ErrNoEncryptionKey, got %v", err)
 	}
 }
 
@@ -40,10 +49,7 @@ func TestRemoteStorage_Get(t *testing.T) {
 		Login:    "user",
 		Password: "pass",
 	}
-	encryptedData, err := crypto.Encrypt(`{"Login":"user","Password":"pass"}`, deriveKey)
-	if err != nil {
-		t.Fatalf("Failed to encrypt data: %v", err)
-	}
+	encryptedData := sealPayload(t, `{"Login":"user","Password":"pass"}`, deriveKey, 1, models.CredSecret, payloadField)
 
 	validSecret := &models.Secret{
 		ID:         1,
@@ -63,6 +69,8 @@ func TestRemoteStorage_Get(t *testing.T) {
 	mockClient.EXPECT().LoadSecret(gomock.Any(), gomock.Eq(uint64(2))).Return(invalidPayloadSecret, nil)
 	mockClient.EXPECT().LoadSecret(gomock.Any(), gomock.Eq(uint64(3))).Return(nil, fmt.Errorf("gRPC error"))
 
+	mockClient.EXPECT().GetEncryptionKey().Return(deriveKey).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
 	rs, err := NewRemoteStorage(mockClient)
 	if err != nil {
 		t.Fatalf("Failed to create RemoteStorage: %v", err)
@@ -99,236 +107,1629 @@ func TestRemoteStorage_Get(t *testing.T) {
 			if tc.expectErr && secret != nil {
 				t.Errorf("Expected no secret, got: %v", secret)
 			}
-			if !tc.expectErr && (secret == nil || secret.ID != tc.id) {
-				t.Errorf("Expected secret with ID %d, got: %v", tc.id, secret)
+			if !tc.expectErr && (secret == nil || secret.ID != tc.id) {
+				t.Errorf("Expected secret with ID %d, got: %v", tc.id, secret)
+			}
+		})
+	}
+}
+
+func TestRemoteStorage_GetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+
+	password := "test-password"
+	mockClient.EXPECT().GetPassword().Return(password).AnyTimes()
+
+	deriveKey, err := crypto.DeriveKey(password, "")
+	if err != nil {
+		t.Fatalf("Failed to derive key: %v", err)
+	}
+
+	encryptedData1 := sealPayload(t, `{"Login":"user1","Password":"pass1"}`, deriveKey, 1, models.CredSecret, payloadField)
+
+	encryptedData2 := sealPayload(t, `{"Content":"some text"}`, deriveKey, 2, models.TextSecret, payloadField)
+
+	encryptedData3 := sealPayload(t, `{"FileName":"name.ext","FileBytes":"1"}`, deriveKey, 3, models.BlobSecret, payloadField)
+
+	encryptedData4 := sealPayload(t, `{"Number":"num", "ExpYear":"2025", "ExpMonth": "01", "CVV": 666}`, deriveKey, 4, models.CardSecret, payloadField)
+
+	validSecrets := []*models.Secret{
+		{
+			ID:         1,
+			SecretType: string(models.CredSecret),
+			Payload:    []byte(encryptedData1),
+			Creds: &models.Credentials{
+				Login:    "user1",
+				Password: "pass1",
+			},
+		},
+		{
+			ID:         2,
+			SecretType: string(models.TextSecret),
+			Payload:    []byte(encryptedData2),
+			Text: &models.Text{
+				Content: "some text",
+			},
+		},
+		{
+			ID:         3,
+			SecretType: string(models.BlobSecret),
+			Payload:    []byte(encryptedData3),
+			Blob: &models.Blob{
+				FileName:  "name.ext",
+				FileBytes: []byte("1"),
+			},
+		},
+		{
+			ID:         4,
+			SecretType: string(models.CardSecret),
+			Payload:    []byte(encryptedData4),
+			Card: &models.Card{
+				Number:   "num",
+				ExpYear:  2025,
+				ExpMonth: 01,
+				CVV:      666,
+			},
+		},
+	}
+
+	invalidSecret := []*models.Secret{
+		{
+			ID:         3,
+			SecretType: string(models.CredSecret),
+			Payload:    []byte("invalid-encrypted-data"),
+		},
+	}
+
+	mockClient.EXPECT().GetEncryptionKey().Return(deriveKey).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+	rs, err := NewRemoteStorage(mockClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		mockSetup func()
+		expectErr bool
+		expected  []*models.Secret
+	}{
+		{
+			name: "GetAll_Success",
+			mockSetup: func() {
+				mockClient.EXPECT().SyncSecrets(gomock.Any(), uint64(0)).Return(&models.SecretsDelta{Secrets: validSecrets, Revision: 4, Full: true}, nil).Times(1)
+			},
+			expectErr: false,
+			expected:  validSecrets,
+		},
+		{
+			name: "GetAll_Fail_SyncSecretsError",
+			mockSetup: func() {
+				mockClient.EXPECT().SyncSecrets(gomock.Any(), uint64(4)).Return(nil, fmt.Errorf("failed to sync secrets")).Times(1)
+			},
+			expectErr: true,
+			expected:  nil,
+		},
+		{
+			name: "GetAll_Fail_DecryptPayloadError",
+			mockSetup: func() {
+				mockClient.EXPECT().SyncSecrets(gomock.Any(), uint64(4)).Return(&models.SecretsDelta{Secrets: invalidSecret, Revision: 5, Full: true}, nil).Times(1)
+			},
+			expectErr: true,
+			expected:  nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockSetup()
+			secrets, err := rs.GetAll(context.Background())
+
+			if (err != nil) != tc.expectErr {
+				t.Errorf("Expected error: %v, got: %v", tc.expectErr, err)
+			}
+
+			if !tc.expectErr && len(secrets) != len(tc.expected) {
+				t.Errorf("Expected %d secrets, got %d", len(tc.expected), len(secrets))
+			}
+		})
+	}
+}
+
+func TestRemoteStorage_GetAll_MergesDelta(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+	mockClient.EXPECT().GetEncryptionKey().Return(key).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+	mockClient.EXPECT().GetPassword().Return("").AnyTimes()
+
+	rs, err := NewRemoteStorage(mockClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+
+	now := time.Now()
+	text := func(id uint64, content string, updated time.Time) *models.Secret {
+		payload := sealPayload(t, fmt.Sprintf(`{"Content":%q}`, content), key, id, models.TextSecret, payloadField)
+		return &models.Secret{ID: id, SecretType: string(models.TextSecret), Payload: []byte(payload), UpdatedAt: updated}
+	}
+
+	gomock.InOrder(
+		mockClient.EXPECT().SyncSecrets(gomock.Any(), uint64(0)).Return(&models.SecretsDelta{
+			Secrets:  models.Secrets{text(1, "first", now.Add(-2*time.Hour)), text(2, "second", now.Add(-time.Hour)), text(3, "third", now.Add(-3*time.Hour))},
+			Revision: 3,
+			Full:     true,
+		}, nil),
+		mockClient.EXPECT().SyncSecrets(gomock.Any(), uint64(3)).Return(&models.SecretsDelta{
+			Secrets:    models.Secrets{text(1, "first edited", now), text(4, "fourth", now.Add(-4*time.Hour))},
+			DeletedIDs: []uint64{2},
+			Revision:   6,
+		}, nil),
+		mockClient.EXPECT().SyncSecrets(gomock.Any(), uint64(6)).Return(&models.SecretsDelta{Revision: 6}, nil),
+	)
+
+	contents := func(secrets []*models.Secret) []string {
+		result := make([]string, 0, len(secrets))
+		for _, s := range secrets {
+			result = append(result, s.Text.Content)
+		}
+		return result
+	}
+
+	secrets, err := rs.GetAll(context.Background())
+	if err != nil {
+		t.Fatalf("GetAll() unexpected error: %v", err)
+	}
+	if got := contents(secrets); !reflect.DeepEqual(got, []string{"second", "first", "third"}) {
+		t.Errorf("GetAll() after full sync got %v", got)
+	}
+
+	// Изменённый секрет возвращается расшифрованной копией: правка не затрагивает известные хранилищу данные.
+	secrets[2].Text.Content = "local edit"
+
+	secrets, err = rs.GetAll(context.Background())
+	if err != nil {
+		t.Fatalf("GetAll() unexpected error: %v", err)
+	}
+	want := []string{"first edited", "third", "fourth"}
+	if got := contents(secrets); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAll() after delta got %v, want %v", got, want)
+	}
+
+	secrets, err = rs.GetAll(context.Background())
+	if err != nil {
+		t.Fatalf("GetAll() unexpected error: %v", err)
+	}
+	if got := contents(secrets); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAll() without changes got %v, want %v", got, want)
+	}
+}
+
+func TestRemoteStorage_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+
+	query := &models.SecretListQuery{SecretType: string(models.TextSecret), Order: models.SecretOrderUpdatedAsc}
+	page := &models.SecretsPage{Secrets: []*models.Secret{{ID: 1, Title: "Bank"}}, NextPageToken: "next"}
+	mockClient.EXPECT().ListSecrets(gomock.Any(), query).Return(page, nil)
+	mockClient.EXPECT().GetPassword().Return("").AnyTimes()
+
+	mockClient.EXPECT().GetEncryptionKey().Return(make([]byte, 32)).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+	rs, err := NewRemoteStorage(mockClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+
+	result, err := rs.List(context.Background(), query)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(result, page) {
+		t.Errorf("Expected page %+v, got %+v", page, result)
+	}
+}
+
+func TestRemoteStorage_List_ByTitle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+
+	key := make([]byte, 32)
+	secret := func(id uint64, title string) *models.Secret {
+		return &models.Secret{
+			ID:              id,
+			SecretType:      string(models.TextSecret),
+			Title:           sealLabel(t, title, key, id, models.TextSecret, titleField),
+			Metadata:        sealLabel(t, "", key, id, models.TextSecret, metadataField),
+			LabelsEncrypted: true,
+		}
+	}
+	served := func() *models.SecretsPage {
+		return &models.SecretsPage{
+			Secrets:       []*models.Secret{secret(1, "Bank card"), secret(2, "Mail"), secret(3, "Bank account"), secret(4, "Banking")},
+			NextPageToken: "server-next",
+		}
+	}
+	bankTokens, err := crypto.SearchTokens("bank", key)
+	if err != nil {
+		t.Fatalf("Failed to build search tokens: %v", err)
+	}
+	cardTokens, err := crypto.SearchTokens("card", key)
+	if err != nil {
+		t.Fatalf("Failed to build search tokens: %v", err)
+	}
+	bankCardTokens := append(append([]string{}, bankTokens...), cardTokens...)
+	slices.Sort(bankCardTokens)
+
+	mockClient.EXPECT().GetPassword().Return("").AnyTimes()
+	mockClient.EXPECT().GetEncryptionKey().Return(key).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+	rs, err := NewRemoteStorage(mockClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+
+	titles := func(page *models.SecretsPage) []string {
+		var result []string
+		for _, secret := range page.Secrets {
+			result = append(result, secret.Title)
+		}
+		return result
+	}
+
+	// Начало заголовка и порядок по заголовку не передаются на сервер, законченные слова начала заголовка
+	// передаются токенами слепого индекса, а размер и токен страницы - как есть: загружается одна страница сервера.
+	tests := []struct {
+		name         string
+		query        models.SecretListQuery
+		expectRemote *models.SecretListQuery
+		expectTitles []string
+	}{
+		{
+			name:         "PartialWordPrefix",
+			query:        models.SecretListQuery{SecretType: string(models.TextSecret), TitlePrefix: "Ba", PageSize: 2, PageToken: "token"},
+			expectRemote: &models.SecretListQuery{SecretType: string(models.TextSecret), PageSize: 2, PageToken: "token"},
+			expectTitles: []string{"Bank card", "Bank account", "Banking"},
+		},
+		{
+			name:         "CompleteWordPrefix_OrderAsc",
+			query:        models.SecretListQuery{TitlePrefix: "Bank ", Order: models.SecretOrderTitleAsc},
+			expectRemote: &models.SecretListQuery{SearchTokens: bankTokens},
+			expectTitles: []string{"Bank account", "Bank card"},
+		},
+		{
+			name:         "CompleteWordPrefix_WithSearch",
+			query:        models.SecretListQuery{TitlePrefix: "Bank c", Search: "card"},
+			expectRemote: &models.SecretListQuery{SearchTokens: bankCardTokens},
+			expectTitles: []string{"Bank card"},
+		},
+		{
+			name:         "OrderDesc",
+			query:        models.SecretListQuery{Order: models.SecretOrderTitleDesc},
+			expectRemote: &models.SecretListQuery{},
+			expectTitles: []string{"Mail", "Banking", "Bank card", "Bank account"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient.EXPECT().ListSecrets(gomock.Any(), tc.expectRemote).Return(served(), nil).Times(1)
+
+			page, err := rs.List(context.Background(), &tc.query)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if got := titles(page); !reflect.DeepEqual(got, tc.expectTitles) {
+				t.Errorf("Expected titles %v, got %v", tc.expectTitles, got)
+			}
+			if page.NextPageToken != "server-next" {
+				t.Errorf("Expected server page token, got %q", page.NextPageToken)
+			}
+		})
+	}
+}
+
+func TestRemoteStorage_List_Search(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+
+	key := make([]byte, 32)
+	tokens, err := crypto.SearchTokens("bank", key)
+	if err != nil {
+		t.Fatalf("Failed to build search tokens: %v", err)
+	}
+	title := sealLabel(t, "My Bank", key, 1, models.TextSecret, titleField)
+	metadata := sealLabel(t, "note", key, 1, models.TextSecret, metadataField)
+
+	query := &models.SecretListQuery{Search: "Bank"}
+	remoteQuery := &models.SecretListQuery{SearchTokens: tokens}
+	page := &models.SecretsPage{Secrets: []*models.Secret{{ID: 1, SecretType: string(models.TextSecret), Title: title, Metadata: metadata, LabelsEncrypted: true}}}
+	mockClient.EXPECT().ListSecrets(gomock.Any(), remoteQuery).Return(page, nil)
+	mockClient.EXPECT().GetPassword().Return("").AnyTimes()
+
+	mockClient.EXPECT().GetEncryptionKey().Return(key).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+	rs, err := NewRemoteStorage(mockClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+
+	result, err := rs.List(context.Background(), query)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := result.Secrets[0]; got.Title != "My Bank" || got.Metadata != "note" || got.LabelsEncrypted {
+		t.Errorf("Expected opened labels, got %+v", got)
+	}
+	if query.SearchTokens != nil {
+		t.Errorf("Expected caller query to be kept, got %+v", query)
+	}
+}
+
+func TestRemoteStorage_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+
+	password := "test-password"
+	mockClient.EXPECT().GetPassword().Return(password).AnyTimes()
+
+	deriveKey, err := crypto.DeriveKey(password, "")
+	if err != nil {
+		t.Fatalf("Failed to derive key: %v", err)
+	}
+
+	testSecret := &models.Secret{
+		Title:      "Mail",
+		SecretType: string(models.CredSecret),
+		Creds: &models.Credentials{
+			Login:    "user",
+			Password: "pass",
+		},
+	}
+
+	mockClient.EXPECT().ReserveSecretID(gomock.Any()).Return(uint64(1), nil)
+	mockClient.EXPECT().SaveSecret(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, sealed *models.Secret) error {
+		if sealed.ID != 1 {
+			t.Errorf("Expected secret to be created with reserved ID 1, got %d", sealed.ID)
+		}
+		if _, err := openField(string(sealed.Payload), deriveKey, 1, models.CredSecret, payloadField); err != nil {
+			t.Errorf("Expected payload bound to the reserved ID, got %v", err)
+		}
+		if !sealed.LabelsEncrypted || len(sealed.SearchTokens) != 1 {
+			t.Errorf("Expected sealed labels with search tokens, got %+v", sealed)
+		}
+		if title, err := openField(sealed.Title, deriveKey, 1, models.CredSecret, titleField); err != nil || title != "Mail" {
+			t.Errorf("Expected title to be encrypted, got %q, err = %v", title, err)
+		}
+		return nil
+	})
+
+	mockClient.EXPECT().GetEncryptionKey().Return(deriveKey).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+	rs, err := NewRemoteStorage(mockClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+
+	err = rs.Create(context.Background(), testSecret)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if testSecret.Title != "Mail" || testSecret.LabelsEncrypted {
+		t.Errorf("Expected caller secret to keep plaintext title, got %+v", testSecret)
+	}
+
+	mockClient.EXPECT().ReserveSecretID(gomock.Any()).Return(uint64(0), fmt.Errorf("unavailable"))
+	if err = rs.Create(context.Background(), &models.Secret{SecretType: string(models.TextSecret)}); err == nil {
+		t.Errorf("Expected reservation error")
+	}
+}
+
+func TestRemoteStorage_Update(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+
+	password := "test-password"
+	mockClient.EXPECT().GetPassword().Return(password).AnyTimes()
+	mockClient.EXPECT().SaveSecret(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	deriveKey, err := crypto.DeriveKey(password, "")
+	if err != nil {
+		t.Fatalf("Failed to derive key: %v", err)
+	}
+
+	testSecret := &models.Secret{
+		ID:         1,
+		SecretType: string(models.CredSecret),
+		Creds: &models.Credentials{
+			Login:    "user",
+			Password: "pass",
+		},
+	}
+
+	mockClient.EXPECT().GetEncryptionKey().Return(deriveKey).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+	rs, err := NewRemoteStorage(mockClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+
+	err = rs.Update(context.Background(), testSecret)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestRemoteStorage_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+
+	mockClient.EXPECT().DeleteSecret(gomock.Any(), gomock.Eq(uint64(1))).Return(nil).AnyTimes()
+	mockClient.EXPECT().GetPassword().Return("").AnyTimes()
+
+	mockClient.EXPECT().GetEncryptionKey().Return(make([]byte, 32)).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+	rs, err := NewRemoteStorage(mockClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+
+	err = rs.Delete(context.Background(), 1)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestRemoteStorage_GetVersions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+
+	deriveKey, err := crypto.DeriveKey("test-password", "")
+	if err != nil {
+		t.Fatalf("Failed to derive key: %v", err)
+	}
+	encryptedData, err := crypto.Encrypt(`{"content":"old text"}`, deriveKey)
+	if err != nil {
+		t.Fatalf("Failed to encrypt data: %v", err)
+	}
+
+	mockClient.EXPECT().GetPassword().Return("").AnyTimes()
+	mockClient.EXPECT().GetEncryptionKey().Return(deriveKey).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+	rs, err := NewRemoteStorage(mockClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		versions  []*models.SecretVersion
+		loadErr   error
+		expectErr bool
+	}{
+		{
+			name: "Success",
+			versions: []*models.SecretVersion{
+				{Secret: models.Secret{ID: 1, SecretType: string(models.TextSecret), Payload: []byte(encryptedData)}, VersionID: 3},
+			},
+		},
+		{
+			name: "Invalid_Payload",
+			versions: []*models.SecretVersion{
+				{Secret: models.Secret{ID: 1, SecretType: string(models.TextSecret), Payload: []byte("invalid-encrypted-data")}, VersionID: 3},
+			},
+			expectErr: true,
+		},
+		{
+			name:      "Load_Error",
+			loadErr:   fmt.Errorf("gRPC error"),
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient.EXPECT().LoadSecretVersions(gomock.Any(), uint64(1)).Return(tc.versions, tc.loadErr)
+
+			versions, err := rs.GetVersions(context.Background(), 1)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("GetVersions() error = %v, expectErr %v", err, tc.expectErr)
+			}
+			if !tc.expectErr && (versions[0].Text == nil || versions[0].Text.Content != "old text") {
+				t.Errorf("GetVersions() did not decrypt version: %+v", versions[0])
+			}
+		})
+	}
+}
+
+func TestRemoteStorage_RestoreVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+	mockClient.EXPECT().GetPassword().Return("").AnyTimes()
+	mockClient.EXPECT().GetEncryptionKey().Return([]byte("0123456789abcdef0123456789abcdef")).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+	mockClient.EXPECT().RestoreSecretVersion(gomock.Any(), uint64(1), uint64(3)).Return(nil)
+
+	rs, err := NewRemoteStorage(mockClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+
+	if err = rs.RestoreVersion(context.Background(), 1, 3); err != nil {
+		t.Errorf("RestoreVersion() unexpected error: %v", err)
+	}
+}
+
+func TestRemoteStorage_Trash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+
+	deriveKey, err := crypto.DeriveKey("test-password", "")
+	if err != nil {
+		t.Fatalf("Failed to derive key: %v", err)
+	}
+	encryptedData, err := crypto.Encrypt(`{"content":"deleted text"}`, deriveKey)
+	if err != nil {
+		t.Fatalf("Failed to encrypt data: %v", err)
+	}
+
+	mockClient.EXPECT().GetPassword().Return("").AnyTimes()
+	mockClient.EXPECT().GetEncryptionKey().Return(deriveKey).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+	rs, err := NewRemoteStorage(mockClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+
+	mockClient.EXPECT().LoadTrash(gomock.Any()).Return([]*models.Secret{
+		{ID: 1, SecretType: string(models.TextSecret), Payload: []byte(encryptedData)},
+	}, nil)
+	secrets, err := rs.GetTrash(context.Background())
+	if err != nil {
+		t.Fatalf("GetTrash() unexpected error: %v", err)
+	}
+	if secrets[0].Text == nil || secrets[0].Text.Content != "deleted text" {
+		t.Errorf("GetTrash() did not decrypt secret: %+v", secrets[0])
+	}
+
+	mockClient.EXPECT().LoadTrash(gomock.Any()).Return([]*models.Secret{
+		{ID: 1, SecretType: string(models.TextSecret), Payload: []byte("invalid-encrypted-data")},
+	}, nil)
+	if _, err = rs.GetTrash(context.Background()); err == nil {
+		t.Errorf("GetTrash() expected decryption error")
+	}
+
+	mockClient.EXPECT().RestoreSecret(gomock.Any(), uint64(1)).Return(nil)
+	if err = rs.Restore(context.Background(), 1); err != nil {
+		t.Errorf("Restore() unexpected error: %v", err)
+	}
+
+	mockClient.EXPECT().PurgeSecret(gomock.Any(), uint64(1)).Return(fmt.Errorf("not found"))
+	if err = rs.Purge(context.Background(), 1); err == nil {
+		t.Errorf("Purge() expected error")
+	}
+}
+
+func TestRemoteStorage_Folders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+	mockClient.EXPECT().GetPassword().Return("").AnyTimes()
+	mockClient.EXPECT().GetEncryptionKey().Return([]byte("key")).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+	rs, err := NewRemoteStorage(mockClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+	ctx := context.Background()
+
+	mockClient.EXPECT().ListFolders(ctx).Return([]*models.Folder{{ID: 1, Name: "Banks"}}, nil)
+	folders, err := rs.GetFolders(ctx)
+	if err != nil || len(folders) != 1 {
+		t.Errorf("GetFolders() got folders = %v, err = %v", folders, err)
+	}
+
+	mockClient.EXPECT().CreateFolder(ctx, uint64(1), "Cards").Return(&models.Folder{ID: 2, ParentID: 1, Name: "Cards"}, nil)
+	if folder, err := rs.CreateFolder(ctx, 1, "Cards"); err != nil || folder.ID != 2 {
+		t.Errorf("CreateFolder() got folder = %v, err = %v", folder, err)
+	}
+
+	mockClient.EXPECT().UpdateFolder(ctx, uint64(2), uint64(0), "Cards").Return(nil)
+	if err = rs.UpdateFolder(ctx, 2, 0, "Cards"); err != nil {
+		t.Errorf("UpdateFolder() unexpected error: %v", err)
+	}
+
+	mockClient.EXPECT().DeleteFolder(ctx, uint64(2)).Return(fmt.Errorf("not found"))
+	if err = rs.DeleteFolder(ctx, 2); err == nil {
+		t.Errorf("DeleteFolder() expected error")
+	}
+
+	mockClient.EXPECT().ListTags(ctx).Return([]*models.Tag{{ID: 3, Name: "work"}}, nil)
+	if tags, err := rs.GetTags(ctx); err != nil || len(tags) != 1 {
+		t.Errorf("GetTags() got tags = %v, err = %v", tags, err)
+	}
+
+	mockClient.EXPECT().CreateTag(ctx, "home").Return(&models.Tag{ID: 4, Name: "home"}, nil)
+	if tag, err := rs.CreateTag(ctx, "home"); err != nil || tag.ID != 4 {
+		t.Errorf("CreateTag() got tag = %v, err = %v", tag, err)
+	}
+
+	mockClient.EXPECT().UpdateTag(ctx, uint64(4), "family").Return(nil)
+	if err = rs.UpdateTag(ctx, 4, "family"); err != nil {
+		t.Errorf("UpdateTag() unexpected error: %v", err)
+	}
+
+	mockClient.EXPECT().DeleteTag(ctx, uint64(4)).Return(nil)
+	if err = rs.DeleteTag(ctx, 4); err != nil {
+		t.Errorf("DeleteTag() unexpected error: %v", err)
+	}
+}
+
+func TestRemoteStorage_Get_UpgradesLegacySecret(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+
+	password := "test-password"
+	legacyKey, err := crypto.DeriveKey(password, "")
+	if err != nil {
+		t.Fatalf("Failed to derive key: %v", err)
+	}
+	keys, err := crypto.DeriveKeys(password, models.LegacyKDFParams("user"))
+	if err != nil {
+		t.Fatalf("Failed to derive keys: %v", err)
+	}
+
+	encryptedData, err := crypto.Encrypt(`{"Login":"user","Password":"pass"}`, legacyKey)
+	if err != nil {
+		t.Fatalf("Failed to encrypt data: %v", err)
+	}
+
+	legacySecret := &models.Secret{
+		ID:         1,
+		SecretType: string(models.CredSecret),
+		Payload:    []byte(encryptedData),
+		Revision:   2,
+	}
+
+	mockClient.EXPECT().GetPassword().Return(password).AnyTimes()
+	mockClient.EXPECT().GetEncryptionKey().Return(keys.EncryptionKey).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+	mockClient.EXPECT().LoadSecret(gomock.Any(), gomock.Eq(uint64(1))).Return(legacySecret, nil)
+	mockClient.EXPECT().SaveSecret(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, secret *models.Secret) error {
+		if _, err := openField(string(secret.Payload), keys.EncryptionKey, 1, models.CredSecret, payloadField); err != nil {
+			t.Errorf("Expected payload re-encrypted with the new key, got %v", err)
+		}
+		return nil
+	}).Times(1)
+
+	rs, err := NewRemoteStorage(mockClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+
+	secret, err := rs.Get(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if secret.Creds == nil || secret.Creds.Login != "user" {
+		t.Errorf("Decrypted data does not match expected data: %v", secret.Creds)
+	}
+	if secret.Revision != 3 {
+		t.Errorf("Expected revision of the upgraded secret to be 3, got %d", secret.Revision)
+	}
+}
+
+func TestRemoteStorage_Get_UpgradesUnboundSecret(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+
+	key := make([]byte, 32)
+	payload, err := crypto.Encrypt(`{"Content":"some text"}`, key)
+	if err != nil {
+		t.Fatalf("Failed to encrypt data: %v", err)
+	}
+	title, err := crypto.Encrypt("Notes", key)
+	if err != nil {
+		t.Fatalf("Failed to encrypt title: %v", err)
+	}
+	metadata, err := crypto.Encrypt("", key)
+	if err != nil {
+		t.Fatalf("Failed to encrypt metadata: %v", err)
+	}
+
+	mockClient.EXPECT().GetPassword().Return("").AnyTimes()
+	mockClient.EXPECT().GetEncryptionKey().Return(key).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+	mockClient.EXPECT().LoadSecret(gomock.Any(), uint64(5)).Return(&models.Secret{
+		ID: 5, SecretType: string(models.TextSecret), Title: title, Metadata: metadata, LabelsEncrypted: true, Payload: []byte(payload), Revision: 1,
+	}, nil)
+	mockClient.EXPECT().SaveSecret(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, secret *models.Secret) error {
+		if _, err := openField(string(secret.Payload), key, 5, models.TextSecret, payloadField); err != nil {
+			t.Errorf("Expected payload bound to the secret, got %v", err)
+		}
+		if title, err := openField(secret.Title, key, 5, models.TextSecret, titleField); err != nil || title != "Notes" {
+			t.Errorf("Expected title bound to the secret, got %q, %v", title, err)
+		}
+		return nil
+	}).Times(1)
+
+	rs, err := NewRemoteStorage(mockClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+
+	secret, err := rs.Get(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if secret.Title != "Notes" || secret.Text == nil || secret.Text.Content != "some text" {
+		t.Errorf("Decrypted secret does not match expected data: %+v", secret)
+	}
+}
+
+func TestRemoteStorage_Get_ReadsBoundHexSecret(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+
+	key := make([]byte, 32)
+	payload, err := crypto.EncryptBound(`{"Content":"some text"}`, key, crypto.SecretAAD(testOwner, 5, string(models.TextSecret), payloadField))
+	if err != nil {
+		t.Fatalf("Failed to encrypt data: %v", err)
+	}
+	title, err := crypto.EncryptBound("Notes", key, crypto.SecretAAD(testOwner, 5, string(models.TextSecret), titleField))
+	if err != nil {
+		t.Fatalf("Failed to encrypt title: %v", err)
+	}
+	metadata, err := crypto.EncryptBound("", key, crypto.SecretAAD(testOwner, 5, string(models.TextSecret), metadataField))
+	if err != nil {
+		t.Fatalf("Failed to encrypt metadata: %v", err)
+	}
+
+	mockClient.EXPECT().GetPassword().Return("").AnyTimes()
+	mockClient.EXPECT().GetEncryptionKey().Return(key).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+	mockClient.EXPECT().LoadSecret(gomock.Any(), uint64(5)).Return(&models.Secret{
+		ID: 5, SecretType: string(models.TextSecret), Title: title, Metadata: metadata, LabelsEncrypted: true, Payload: []byte(payload), Revision: 1,
+	}, nil)
+
+	rs, err := NewRemoteStorage(mockClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+
+	secret, err := rs.Get(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if secret.Title != "Notes" || secret.Text == nil || secret.Text.Content != "some text" || secret.Revision != 1 {
+		t.Errorf("Decrypted secret does not match expected data: %+v", secret)
+	}
+
+	mockClient.EXPECT().SaveSecret(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, secret *models.Secret) error {
+		if _, err := openField(string(secret.Payload), key, 5, models.TextSecret, payloadField); err != nil {
+			t.Errorf("Expected payload to be saved in the envelope format, got %v", err)
+		}
+		if title, err := openField(secret.Title, key, 5, models.TextSecret, titleField); err != nil || title != "Notes" {
+			t.Errorf("Expected title to be saved in the envelope format, got %q, %v", title, err)
+		}
+		return nil
+	}).Times(1)
+
+	if err = rs.Update(context.Background(), secret); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestRemoteStorage_UpgradeKDF(t *testing.T) {
+	password := "test-password"
+	params := &models.KDFParams{Algorithm: models.KDFArgon2id, Salt: []byte("0123456789abcdef"), Argon2Memory: 19 * 1024, Argon2Time: 1, Argon2Threads: 1}
+	newKeys, err := crypto.DeriveKeys(password, params)
+	if err != nil {
+		t.Fatalf("Failed to derive keys: %v", err)
+	}
+	oldKeys, err := crypto.DeriveKeys(password, models.LegacyKDFParams("user"))
+	if err != nil {
+		t.Fatalf("Failed to derive keys: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		setupMock func(mockClient *mocks.MockClientGRPCInterface, payload string)
+		expectErr bool
+		expectKey []byte
+	}{
+		{
+			name: "UpgradeKDF_NotRequired",
+			setupMock: func(mockClient *mocks.MockClientGRPCInterface, _ string) {
+				mockClient.EXPECT().KDFUpgradeRequired().Return(false)
+			},
+			expectErr: false,
+			expectKey: oldKeys.EncryptionKey,
+		},
+		{
+			name: "UpgradeKDF_Success",
+			setupMock: func(mockClient *mocks.MockClientGRPCInterface, payload string) {
+				mockClient.EXPECT().KDFUpgradeRequired().Return(true)
+				mockClient.EXPECT().GetKDFParams().Return(models.LegacyKDFParams("user"))
+				mockClient.EXPECT().NewKDFParams(gomock.Any()).Return(params, nil)
+				mockClient.EXPECT().LoadSecrets(gomock.Any()).Return([]*models.Secret{
+					{ID: 7, Title: "Notes", SecretType: string(models.TextSecret), Payload: []byte(payload)},
+				}, nil)
+				mockClient.EXPECT().LoadTrash(gomock.Any()).Return(nil, nil)
+				mockClient.EXPECT().UpgradeKDF(gomock.Any(), oldKeys.AuthHash, params, gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, _ *models.KDFParams, keys *crypto.Keys, vaultKey []byte, secrets map[uint64]*models.Secret) error {
+						if keys.AuthHash != newKeys.AuthHash {
+							t.Errorf("Expected auth hash derived with new params")
+						}
+						if vaultKey != nil {
+							t.Errorf("Expected no vault key for a locked vault, got %x", vaultKey)
+						}
+						decrypted, err := openField(string(secrets[7].Payload), newKeys.EncryptionKey, 7, models.TextSecret, payloadField)
+						if err != nil || decrypted != `{"content":"some text"}` {
+							t.Errorf("Expected payload re-encrypted with new key, got %q, %v", decrypted, err)
+						}
+						title, err := openField(secrets[7].Title, newKeys.EncryptionKey, 7, models.TextSecret, titleField)
+						if err != nil || title != "Notes" {
+							t.Errorf("Expected title encrypted with new key, got %q, %v", title, err)
+						}
+						tokens, _ := crypto.SearchTokens("notes", newKeys.EncryptionKey)
+						if !reflect.DeepEqual(secrets[7].SearchTokens, tokens) {
+							t.Errorf("Expected search tokens built with new key, got %v", secrets[7].SearchTokens)
+						}
+						return nil
+					})
+			},
+			expectErr: false,
+			expectKey: newKeys.EncryptionKey,
+		},
+		{
+			name: "UpgradeKDF_Fail_Server",
+			setupMock: func(mockClient *mocks.MockClientGRPCInterface, payload string) {
+				mockClient.EXPECT().KDFUpgradeRequired().Return(true)
+				mockClient.EXPECT().GetKDFParams().Return(models.LegacyKDFParams("user"))
+				mockClient.EXPECT().NewKDFParams(gomock.Any()).Return(params, nil)
+				mockClient.EXPECT().LoadSecrets(gomock.Any()).Return([]*models.Secret{
+					{ID: 7, SecretType: string(models.TextSecret), Payload: []byte(payload)},
+				}, nil)
+				mockClient.EXPECT().LoadTrash(gomock.Any()).Return(nil, nil)
+				mockClient.EXPECT().UpgradeKDF(gomock.Any(), oldKeys.AuthHash, params, gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("conflict"))
+			},
+			expectErr: true,
+			expectKey: oldKeys.EncryptionKey,
+		},
+		{
+			name: "UpgradeKDF_Fail_Params",
+			setupMock: func(mockClient *mocks.MockClientGRPCInterface, _ string) {
+				mockClient.EXPECT().KDFUpgradeRequired().Return(true)
+				mockClient.EXPECT().GetKDFParams().Return(models.LegacyKDFParams("user"))
+				mockClient.EXPECT().NewKDFParams(gomock.Any()).Return(nil, errors.New("server unavailable"))
+			},
+			expectErr: true,
+			expectKey: oldKeys.EncryptionKey,
+		},
+		{
+			name: "UpgradeKDF_Fail_NoCurrentParams",
+			setupMock: func(mockClient *mocks.MockClientGRPCInterface, _ string) {
+				mockClient.EXPECT().KDFUpgradeRequired().Return(true)
+				mockClient.EXPECT().GetKDFParams().Return(nil)
+			},
+			expectErr: true,
+			expectKey: oldKeys.EncryptionKey,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			payload, err := crypto.Encrypt(`{"Content":"some text"}`, oldKeys.EncryptionKey)
+			if err != nil {
+				t.Fatalf("Failed to encrypt data: %v", err)
+			}
+
+			mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+			mockClient.EXPECT().GetPassword().Return(password).AnyTimes()
+			mockClient.EXPECT().GetEncryptionKey().Return(oldKeys.EncryptionKey).AnyTimes()
+			mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+			tc.setupMock(mockClient, payload)
+
+			rs, err := NewRemoteStorage(mockClient)
+			if err != nil {
+				t.Fatalf("Failed to create RemoteStorage: %v", err)
+			}
+
+			err = rs.UpgradeKDF(context.Background())
+			if (err != nil) != tc.expectErr {
+				t.Errorf("Expected error: %v, got: %v", tc.expectErr, err)
+			}
+			if string(rs.deriveKey) != string(tc.expectKey) {
+				t.Errorf("Unexpected storage key after upgrade")
+			}
+		})
+	}
+}
+
+func TestRemoteStorage_ChangePassword(t *testing.T) {
+	currentParams := &models.KDFParams{Algorithm: models.KDFArgon2id, Salt: []byte("0123456789abcdef"), Argon2Memory: 19 * 1024, Argon2Time: 1, Argon2Threads: 1}
+	newParams := &models.KDFParams{Algorithm: models.KDFArgon2id, Salt: []byte("fedcba9876543210"), Argon2Memory: 19 * 1024, Argon2Time: 1, Argon2Threads: 1}
+	currentKeys, err := crypto.DeriveKeys("current-password", currentParams)
+	if err != nil {
+		t.Fatalf("Failed to derive keys: %v", err)
+	}
+	newKeys, err := crypto.DeriveKeys("new-password", newParams)
+	if err != nil {
+		t.Fatalf("Failed to derive keys: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		currentPassword string
+		setupMock       func(mockClient *mocks.MockClientGRPCInterface, payload string)
+		expectErr       error
+		expectKey       []byte
+	}{
+		{
+			name:            "ChangePassword_Success",
+			currentPassword: "current-password",
+			setupMock: func(mockClient *mocks.MockClientGRPCInterface, payload string) {
+				mockClient.EXPECT().NewKDFParams(gomock.Any()).Return(newParams, nil)
+				mockClient.EXPECT().LoadSecrets(gomock.Any()).Return([]*models.Secret{
+					{ID: 7, SecretType: string(models.TextSecret), Payload: []byte(payload)},
+				}, nil)
+				mockClient.EXPECT().LoadTrash(gomock.Any()).Return([]*models.Secret{
+					{ID: 8, SecretType: string(models.TextSecret), Payload: []byte(payload)},
+				}, nil)
+				mockClient.EXPECT().ChangePassword(gomock.Any(), currentKeys.AuthHash, newParams, gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, _ *models.KDFParams, keys *crypto.Keys, _ []byte, secrets map[uint64]*models.Secret) error {
+						if keys.AuthHash != newKeys.AuthHash {
+							t.Errorf("Expected auth hash derived from the new password")
+						}
+						for _, id := range []uint64{7, 8} {
+							decrypted, err := openField(string(secrets[id].Payload), newKeys.EncryptionKey, id, models.TextSecret, payloadField)
+							if err != nil || decrypted != `{"content":"some text"}` {
+								t.Errorf("Expected payload %d re-encrypted with new key, got %q, %v", id, decrypted, err)
+							}
+						}
+						return nil
+					})
+				mockClient.EXPECT().SetPassword("new-password")
+			},
+			expectKey: newKeys.EncryptionKey,
+		},
+		{
+			name:            "ChangePassword_Fail_WrongPassword",
+			currentPassword: "wrong-password",
+			setupMock:       func(_ *mocks.MockClientGRPCInterface, _ string) {},
+			expectErr:       grpc.ErrWrongPassword,
+			expectKey:       currentKeys.EncryptionKey,
+		},
+		{
+			name:            "ChangePassword_Fail_Server",
+			currentPassword: "current-password",
+			setupMock: func(mockClient *mocks.MockClientGRPCInterface, payload string) {
+				mockClient.EXPECT().NewKDFParams(gomock.Any()).Return(newParams, nil)
+				mockClient.EXPECT().LoadSecrets(gomock.Any()).Return([]*models.Secret{
+					{ID: 7, SecretType: string(models.TextSecret), Payload: []byte(payload)},
+				}, nil)
+				mockClient.EXPECT().LoadTrash(gomock.Any()).Return(nil, nil)
+				mockClient.EXPECT().ChangePassword(gomock.Any(), currentKeys.AuthHash, newParams, gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("conflict"))
+			},
+			expectErr: errors.New("ChangePassword(): failed to change password: conflict"),
+			expectKey: currentKeys.EncryptionKey,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			payload, err := crypto.Encrypt(`{"Content":"some text"}`, currentKeys.EncryptionKey)
+			if err != nil {
+				t.Fatalf("Failed to encrypt data: %v", err)
+			}
+
+			mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+			mockClient.EXPECT().GetPassword().Return("").AnyTimes()
+			mockClient.EXPECT().GetEncryptionKey().Return(currentKeys.EncryptionKey).AnyTimes()
+			mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+			mockClient.EXPECT().GetKDFParams().Return(currentParams).AnyTimes()
+			tc.setupMock(mockClient, payload)
+
+			rs, err := NewRemoteStorage(mockClient)
+			if err != nil {
+				t.Fatalf("Failed to create RemoteStorage: %v", err)
+			}
+
+			err = rs.ChangePassword(context.Background(), tc.currentPassword, "new-password")
+			if tc.expectErr == nil && err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if tc.expectErr != nil && (err == nil || (!errors.Is(err, tc.expectErr) && err.Error() != tc.expectErr.Error())) {
+				t.Errorf("Expected error: %v, got: %v", tc.expectErr, err)
+			}
+			if string(rs.deriveKey) != string(tc.expectKey) {
+				t.Errorf("Unexpected storage key after password change")
 			}
 		})
 	}
 }
 
-func TestRemoteStorage_GetAll(t *testing.T) {
+func TestRemoteStorage_ChangePassword_RewrapsVaultKey(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+	currentParams := &models.KDFParams{Algorithm: models.KDFArgon2id, Salt: []byte("0123456789abcdef"), Argon2Memory: 19 * 1024, Argon2Time: 1, Argon2Threads: 1}
+	newParams := &models.KDFParams{Algorithm: models.KDFArgon2id, Salt: []byte("fedcba9876543210"), Argon2Memory: 19 * 1024, Argon2Time: 1, Argon2Threads: 1}
+	currentKeys, err := crypto.DeriveKeys("current-password", currentParams)
+	if err != nil {
+		t.Fatalf("Failed to derive keys: %v", err)
+	}
+	newKeys, err := crypto.DeriveKeys("new-password", newParams)
+	if err != nil {
+		t.Fatalf("Failed to derive keys: %v", err)
+	}
+	vaultKey := bytes32(1)
+	dataKey := bytes32(2)
 
-	password := "test-password"
-	mockClient.EXPECT().GetPassword().Return(password).AnyTimes()
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+	mockClient.EXPECT().GetPassword().Return("").AnyTimes()
+	mockClient.EXPECT().GetEncryptionKey().Return(currentKeys.EncryptionKey).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+	mockClient.EXPECT().GetKDFParams().Return(currentParams).AnyTimes()
+	mockClient.EXPECT().NewKDFParams(gomock.Any()).Return(newParams, nil)
+	withDataKey := &models.Secret{
+		ID: 7, SecretType: string(models.TextSecret), DataKey: wrapDataKey(t, dataKey, vaultKey, 7, models.TextSecret),
+		Payload: []byte(sealPayload(t, `{"content":"some text"}`, dataKey, 7, models.TextSecret, payloadField)),
+	}
+	mockClient.EXPECT().LoadSecrets(gomock.Any()).Return([]*models.Secret{withDataKey}, nil)
+	mockClient.EXPECT().LoadTrash(gomock.Any()).Return(nil, nil)
+	mockClient.EXPECT().ChangePassword(gomock.Any(), currentKeys.AuthHash, newParams, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ *models.KDFParams, _ *crypto.Keys, wrapped []byte, secrets map[uint64]*models.Secret) error {
+			if key, err := crypto.UnwrapKey(wrapped, newKeys.EncryptionKey, crypto.VaultKeyAAD(testOwner)); err != nil || string(key) != string(vaultKey) {
+				t.Errorf("Expected vault key encrypted with the new key, got %v", err)
+			}
+			if len(secrets) != 0 {
+				t.Errorf("Expected secrets with data key not to be re-encrypted, got %v", secrets)
+			}
+			return nil
+		})
+	mockClient.EXPECT().SetPassword("new-password")
 
-	deriveKey, err := crypto.DeriveKey(password, "")
+	rs, err := NewRemoteStorage(mockClient)
 	if err != nil {
-		t.Fatalf("Failed to derive key: %v", err)
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
 	}
+	rs.vaultKey = vaultKey
 
-	encryptedData1, err := crypto.Encrypt(`{"Login":"user1","Password":"pass1"}`, deriveKey)
-	if err != nil {
-		t.Fatalf("Failed to encrypt data: %v", err)
+	if err = rs.ChangePassword(context.Background(), "current-password", "new-password"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if string(rs.deriveKey) != string(newKeys.EncryptionKey) || string(rs.vaultKey) != string(vaultKey) {
+		t.Errorf("Expected storage to switch to the new key and keep the vault key")
 	}
 
-	encryptedData2, err := crypto.Encrypt(`{"Content":"some text"}`, deriveKey)
-	if err != nil {
-		t.Fatalf("Failed to encrypt data: %v", err)
+	// Секрет без ключа данных в открытом хранилище мог подставить только сервер: смена пароля прерывается.
+	withoutDataKey := &models.Secret{
+		ID: 8, SecretType: string(models.TextSecret), Title: "Notes",
+		Payload: []byte(sealPayload(t, `{"content":"other text"}`, newKeys.EncryptionKey, 8, models.TextSecret, payloadField)),
+	}
+	rs.deriveKey = currentKeys.EncryptionKey
+	mockClient.EXPECT().NewKDFParams(gomock.Any()).Return(newParams, nil)
+	mockClient.EXPECT().LoadSecrets(gomock.Any()).Return([]*models.Secret{withDataKey, withoutDataKey}, nil)
+	mockClient.EXPECT().LoadTrash(gomock.Any()).Return(nil, nil)
+	if err = rs.ChangePassword(context.Background(), "current-password", "new-password"); !errors.Is(err, ErrLegacyCiphertext) {
+		t.Errorf("Expected ErrLegacyCiphertext, got: %v", err)
 	}
+}
 
-	encryptedData3, err := crypto.Encrypt(`{"FileName":"name.ext","FileBytes":"1"}`, deriveKey)
+func TestRemoteStorage_UnlockVault(t *testing.T) {
+	key := make([]byte, 32)
+	vaultKey := bytes32(1)
+	wrapped, err := crypto.WrapKey(vaultKey, key, crypto.VaultKeyAAD(testOwner))
 	if err != nil {
-		t.Fatalf("Failed to encrypt data: %v", err)
+		t.Fatalf("Failed to wrap vault key: %v", err)
 	}
-
-	encryptedData4, err := crypto.Encrypt(`{"Number":"num", "ExpYear":"2025", "ExpMonth": "01", "CVV": 666}`, deriveKey)
+	otherWrapped, err := crypto.WrapKey(vaultKey, bytes32(3), crypto.VaultKeyAAD(testOwner))
 	if err != nil {
-		t.Fatalf("Failed to encrypt data: %v", err)
+		t.Fatalf("Failed to wrap vault key: %v", err)
 	}
+	publicKey, privateKey, err := crypto.NewKeyPair()
+	if err != nil {
+		t.Fatalf("Failed to generate key pair: %v", err)
+	}
+	wrappedPrivate, err := crypto.WrapKey(privateKey, vaultKey, crypto.PrivateKeyAAD(testOwner))
+	if err != nil {
+		t.Fatalf("Failed to wrap private key: %v", err)
+	}
+	keyPair := &models.KeyPair{PublicKey: publicKey, PrivateKey: wrappedPrivate}
 
-	validSecrets := []*models.Secret{
+	var created, createdPrivate []byte
+	tests := []struct {
+		name      string
+		setupMock func(mockClient *mocks.MockClientGRPCInterface)
+		expectErr bool
+		expectKey func() []byte
+		// expectPrivate возвращает ожидаемый закрытый ключ пользователя после открытия хранилища.
+		expectPrivate func() []byte
+	}{
 		{
-			ID:         1,
-			SecretType: string(models.CredSecret),
-			Payload:    []byte(encryptedData1),
-			Creds: &models.Credentials{
-				Login:    "user1",
-				Password: "pass1",
+			name: "UnlockVault_Existing",
+			setupMock: func(mockClient *mocks.MockClientGRPCInterface) {
+				mockClient.EXPECT().GetVaultKey(gomock.Any()).Return(wrapped, nil)
+				mockClient.EXPECT().GetKeyPair(gomock.Any()).Return(keyPair, nil)
 			},
+			expectKey:     func() []byte { return vaultKey },
+			expectPrivate: func() []byte { return privateKey },
 		},
 		{
-			ID:         2,
-			SecretType: string(models.TextSecret),
-			Payload:    []byte(encryptedData2),
-			Text: &models.Text{
-				Content: "some text",
+			name: "UnlockVault_CreateKeyPair",
+			setupMock: func(mockClient *mocks.MockClientGRPCInterface) {
+				mockClient.EXPECT().GetVaultKey(gomock.Any()).Return(wrapped, nil)
+				mockClient.EXPECT().GetKeyPair(gomock.Any()).Return(&models.KeyPair{}, nil)
+				mockClient.EXPECT().CreateKeyPair(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, created *models.KeyPair) error {
+						if len(created.PublicKey) != 32 {
+							t.Errorf("Expected X25519 public key, got %d bytes", len(created.PublicKey))
+						}
+						if createdPrivate, err = crypto.UnwrapKey(created.PrivateKey, vaultKey, crypto.PrivateKeyAAD(testOwner)); err != nil {
+							t.Fatalf("Expected private key encrypted with the vault key, got %v", err)
+						}
+						return nil
+					})
 			},
+			expectKey:     func() []byte { return vaultKey },
+			expectPrivate: func() []byte { return createdPrivate },
 		},
 		{
-			ID:         3,
-			SecretType: string(models.BlobSecret),
-			Payload:    []byte(encryptedData3),
-			Blob: &models.Blob{
-				FileName:  "name.ext",
-				FileBytes: []byte("1"),
+			name: "UnlockVault_KeyPairCreatedByAnotherDevice",
+			setupMock: func(mockClient *mocks.MockClientGRPCInterface) {
+				mockClient.EXPECT().GetVaultKey(gomock.Any()).Return(wrapped, nil)
+				mockClient.EXPECT().GetKeyPair(gomock.Any()).Return(&models.KeyPair{}, nil)
+				mockClient.EXPECT().CreateKeyPair(gomock.Any(), gomock.Any()).Return(grpc.ErrKeyPairExists)
+				mockClient.EXPECT().GetKeyPair(gomock.Any()).Return(keyPair, nil)
 			},
+			expectKey:     func() []byte { return vaultKey },
+			expectPrivate: func() []byte { return privateKey },
 		},
 		{
-			ID:         4,
-			SecretType: string(models.CardSecret),
-			Payload:    []byte(encryptedData4),
-			Card: &models.Card{
-				Number:   "num",
-				ExpYear:  2025,
-				ExpMonth: 01,
-				CVV:      666,
+			name: "UnlockVault_Fail_WrongPrivateKey",
+			setupMock: func(mockClient *mocks.MockClientGRPCInterface) {
+				mockClient.EXPECT().GetVaultKey(gomock.Any()).Return(wrapped, nil)
+				mockClient.EXPECT().GetKeyPair(gomock.Any()).Return(&models.KeyPair{PublicKey: publicKey, PrivateKey: otherWrapped}, nil)
 			},
+			expectErr:     true,
+			expectKey:     func() []byte { return vaultKey },
+			expectPrivate: func() []byte { return nil },
 		},
-	}
-
-	invalidSecret := []*models.Secret{
 		{
-			ID:         3,
-			SecretType: string(models.CredSecret),
-			Payload:    []byte("invalid-encrypted-data"),
+			name: "UnlockVault_Create",
+			setupMock: func(mockClient *mocks.MockClientGRPCInterface) {
+				mockClient.EXPECT().GetVaultKey(gomock.Any()).Return(nil, nil)
+				mockClient.EXPECT().LoadSecrets(gomock.Any()).Return([]*models.Secret{
+					{ID: 7, SecretType: string(models.TextSecret), Title: "Notes", Payload: []byte(sealPayload(t, `{"content":"some text"}`, key, 7, models.TextSecret, payloadField))},
+				}, nil)
+				mockClient.EXPECT().LoadTrash(gomock.Any()).Return(nil, nil)
+				mockClient.EXPECT().CreateVaultKey(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, wrapped []byte, secrets map[uint64]*models.Secret) error {
+						if created, err = crypto.UnwrapKey(wrapped, key, crypto.VaultKeyAAD(testOwner)); err != nil {
+							t.Fatalf("Expected vault key encrypted with the password key, got %v", err)
+						}
+						dataKey, err := crypto.UnwrapKey(secrets[7].DataKey, created, crypto.SecretAAD(testOwner, 7, string(models.TextSecret), dataKeyField))
+						if err != nil {
+							t.Fatalf("Expected data key encrypted with the vault key, got %v", err)
+						}
+						if decrypted, err := openField(string(secrets[7].Payload), dataKey, 7, models.TextSecret, payloadField); err != nil || decrypted != `{"content":"some text"}` {
+							t.Errorf("Expected payload encrypted with the data key, got %q, %v", decrypted, err)
+						}
+						if title, err := openField(secrets[7].Title, dataKey, 7, models.TextSecret, titleField); err != nil || title != "Notes" {
+							t.Errorf("Expected title encrypted with the data key, got %q, %v", title, err)
+						}
+						return nil
+					})
+				mockClient.EXPECT().GetKeyPair(gomock.Any()).Return(&models.KeyPair{}, nil)
+				mockClient.EXPECT().CreateKeyPair(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, keyPair *models.KeyPair) error {
+						createdPrivate, err = crypto.UnwrapKey(keyPair.PrivateKey, created, crypto.PrivateKeyAAD(testOwner))
+						return err
+					})
+			},
+			expectKey:     func() []byte { return created },
+			expectPrivate: func() []byte { return createdPrivate },
 		},
-	}
-
-	rs, err := NewRemoteStorage(mockClient)
-	if err != nil {
-		t.Fatalf("Failed to create RemoteStorage: %v", err)
-	}
-
-	tests := []struct {
-		name      string
-		mockSetup func()
-		expectErr bool
-		expected  []*models.Secret
-	}{
 		{
-			name: "GetAll_Success",
-			mockSetup: func() {
-				mockClient.EXPECT().LoadSecrets(gomock.Any()).Return(validSecrets, nil).Times(1)
+			name: "UnlockVault_CreatedByAnotherDevice",
+			setupMock: func(mockClient *mocks.MockClientGRPCInterface) {
+				mockClient.EXPECT().GetVaultKey(gomock.Any()).Return(nil, nil)
+				mockClient.EXPECT().LoadSecrets(gomock.Any()).Return(nil, nil)
+				mockClient.EXPECT().LoadTrash(gomock.Any()).Return(nil, nil)
+				mockClient.EXPECT().CreateVaultKey(gomock.Any(), gomock.Any(), gomock.Any()).Return(grpc.ErrVaultKeyExists)
+				mockClient.EXPECT().GetVaultKey(gomock.Any()).Return(wrapped, nil)
+				mockClient.EXPECT().GetKeyPair(gomock.Any()).Return(keyPair, nil)
 			},
-			expectErr: false,
-			expected:  validSecrets,
+			expectKey:     func() []byte { return vaultKey },
+			expectPrivate: func() []byte { return privateKey },
 		},
 		{
-			name: "GetAll_Fail_LoadSecretsError",
-			mockSetup: func() {
-				mockClient.EXPECT().LoadSecrets(gomock.Any()).Return(nil, fmt.Errorf("failed to load secrets")).Times(1)
+			name: "UnlockVault_Fail_Create",
+			setupMock: func(mockClient *mocks.MockClientGRPCInterface) {
+				mockClient.EXPECT().GetVaultKey(gomock.Any()).Return(nil, nil)
+				mockClient.EXPECT().LoadSecrets(gomock.Any()).Return(nil, nil)
+				mockClient.EXPECT().LoadTrash(gomock.Any()).Return(nil, nil)
+				mockClient.EXPECT().CreateVaultKey(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("not all secrets"))
 			},
-			expectErr: true,
-			expected:  nil,
+			expectErr:     true,
+			expectKey:     func() []byte { return nil },
+			expectPrivate: func() []byte { return nil },
 		},
 		{
-			name: "GetAll_Fail_DecryptPayloadError",
-			mockSetup: func() {
-				mockClient.EXPECT().LoadSecrets(gomock.Any()).Return(invalidSecret, nil).Times(1)
+			name: "UnlockVault_Fail_WrongKey",
+			setupMock: func(mockClient *mocks.MockClientGRPCInterface) {
+				mockClient.EXPECT().GetVaultKey(gomock.Any()).Return(otherWrapped, nil)
 			},
-			expectErr: true,
-			expected:  nil,
+			expectErr:     true,
+			expectKey:     func() []byte { return nil },
+			expectPrivate: func() []byte { return nil },
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			tc.mockSetup()
-			secrets, err := rs.GetAll(context.Background())
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+			mockClient.EXPECT().GetPassword().Return("").AnyTimes()
+			mockClient.EXPECT().GetEncryptionKey().Return(key).AnyTimes()
+			mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+			tc.setupMock(mockClient)
 
+			rs, err := NewRemoteStorage(mockClient)
+			if err != nil {
+				t.Fatalf("Failed to create RemoteStorage: %v", err)
+			}
+
+			err = rs.UnlockVault(context.Background())
 			if (err != nil) != tc.expectErr {
 				t.Errorf("Expected error: %v, got: %v", tc.expectErr, err)
 			}
-
-			if !tc.expectErr && len(secrets) != len(tc.expected) {
-				t.Errorf("Expected %d secrets, got %d", len(tc.expected), len(secrets))
+			if string(rs.vaultKey) != string(tc.expectKey()) {
+				t.Errorf("Unexpected vault key after unlock")
+			}
+			if string(rs.privateKey) != string(tc.expectPrivate()) {
+				t.Errorf("Unexpected private key after unlock")
 			}
 		})
 	}
 }
 
-func TestRemoteStorage_Create(t *testing.T) {
+func TestRemoteStorage_DataKey(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+	key := make([]byte, 32)
+	vaultKey := bytes32(1)
 
-	password := "test-password"
-	mockClient.EXPECT().GetPassword().Return(password).AnyTimes()
-	mockClient.EXPECT().SaveSecret(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+	mockClient.EXPECT().GetPassword().Return("").AnyTimes()
+	mockClient.EXPECT().GetEncryptionKey().Return(key).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
 
-	_, err := crypto.DeriveKey(password, "")
+	rs, err := NewRemoteStorage(mockClient)
 	if err != nil {
-		t.Fatalf("Failed to derive key: %v", err)
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
 	}
 
-	testSecret := &models.Secret{
-		ID:         1,
-		SecretType: string(models.CredSecret),
-		Creds: &models.Credentials{
-			Login:    "user",
-			Password: "pass",
-		},
+	var saved *models.Secret
+	mockClient.EXPECT().SaveSecret(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, secret *models.Secret) error {
+		saved = secret
+		return nil
+	}).Times(2)
+
+	// До открытия хранилища секрет шифруется ключом мастер-пароля.
+	secret := &models.Secret{ID: 5, Title: "Notes", SecretType: string(models.TextSecret), Text: &models.Text{Content: "some text"}}
+	if err = rs.Update(context.Background(), secret); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(saved.DataKey) != 0 {
+		t.Fatalf("Expected no data key for a locked vault, got %x", saved.DataKey)
 	}
 
-	rs, err := NewRemoteStorage(mockClient)
-	if err != nil {
-		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	// После открытия хранилища секрет без ключа данных не принимается: сервер мог подставить его,
+	// убрав ключ данных. Сохранённый в открытом хранилище секрет получает ключ данных.
+	rs.vaultKey = vaultKey
+	mockClient.EXPECT().LoadSecret(gomock.Any(), uint64(5)).Return(saved, nil)
+	if _, err = rs.Get(context.Background(), 5); !errors.Is(err, ErrLegacyCiphertext) {
+		t.Fatalf("Expected ErrLegacyCiphertext, got %v", err)
+	}
+	if err = rs.Update(context.Background(), secret); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(saved.DataKey) == 0 {
+		t.Fatal("Expected secret to be saved with a data key")
 	}
 
-	err = rs.Create(context.Background(), testSecret)
+	mockClient.EXPECT().LoadSecret(gomock.Any(), uint64(5)).Return(saved, nil)
+	got, err := rs.Get(context.Background(), 5)
 	if err != nil {
-		t.Errorf("Expected no error, got %v", err)
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got.Title != "Notes" || got.Text == nil || got.Text.Content != "some text" {
+		t.Errorf("Decrypted secret does not match expected data: %+v", got)
+	}
+
+	rs.vaultKey = nil
+	mockClient.EXPECT().LoadSecret(gomock.Any(), uint64(5)).Return(saved, nil)
+	if _, err = rs.Get(context.Background(), 5); !errors.Is(err, ErrVaultLocked) {
+		t.Errorf("Expected ErrVaultLocked, got %v", err)
 	}
 }
 
-func TestRemoteStorage_Update(t *testing.T) {
+func TestRemoteStorage_Share(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+	ownerClient := mocks.NewMockClientGRPCInterface(ctrl)
+	ownerClient.EXPECT().GetPassword().Return("").AnyTimes()
+	ownerClient.EXPECT().GetEncryptionKey().Return(make([]byte, 32)).AnyTimes()
+	ownerClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
+	owner, err := NewRemoteStorage(ownerClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+	ownerPublicKey, ownerPrivateKey, err := crypto.NewKeyPair()
+	if err != nil {
+		t.Fatalf("Failed to generate key pair: %v", err)
+	}
+	owner.vaultKey, owner.privateKey = bytes32(1), ownerPrivateKey
 
-	password := "test-password"
-	mockClient.EXPECT().GetPassword().Return(password).AnyTimes()
-	mockClient.EXPECT().SaveSecret(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	recipientClient := mocks.NewMockClientGRPCInterface(ctrl)
+	recipientClient.EXPECT().GetPassword().Return("").AnyTimes()
+	recipientClient.EXPECT().GetEncryptionKey().Return(bytes32(2)).AnyTimes()
+	recipientClient.EXPECT().GetLogin().Return("bob").AnyTimes()
+	recipient, err := NewRemoteStorage(recipientClient)
+	if err != nil {
+		t.Fatalf("Failed to create RemoteStorage: %v", err)
+	}
+	publicKey, privateKey, err := crypto.NewKeyPair()
+	if err != nil {
+		t.Fatalf("Failed to generate key pair: %v", err)
+	}
+	recipient.vaultKey, recipient.privateKey = bytes32(3), privateKey
+
+	var saved *models.Secret
+	ownerClient.EXPECT().SaveSecret(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, secret *models.Secret) error {
+		saved = secret
+		return nil
+	})
+	secret := &models.Secret{ID: 5, Title: "Notes", SecretType: string(models.TextSecret), Text: &models.Text{Content: "some text"}}
+	if err = owner.Update(context.Background(), secret); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
 
-	_, err := crypto.DeriveKey(password, "")
+	// Ключ, подменённый сервером, не проходит сверку отпечатка, полученного от получателя.
+	serverPublicKey, serverPrivateKey, err := crypto.NewKeyPair()
 	if err != nil {
-		t.Fatalf("Failed to derive key: %v", err)
+		t.Fatalf("Failed to generate key pair: %v", err)
+	}
+	fingerprint := crypto.Fingerprint(publicKey)
+	ownerClient.EXPECT().GetPublicKey(gomock.Any(), "bob").Return(serverPublicKey, nil)
+	if err = owner.ShareSecret(context.Background(), secret, "bob", fingerprint, false); !errors.Is(err, crypto.ErrFingerprintMismatch) {
+		t.Fatalf("Expected ErrFingerprintMismatch, got %v", err)
 	}
 
-	testSecret := &models.Secret{
-		ID:         1,
-		SecretType: string(models.CredSecret),
-		Creds: &models.Credentials{
-			Login:    "user",
-			Password: "pass",
-		},
+	var share *models.Share
+	ownerClient.EXPECT().GetPublicKey(gomock.Any(), "bob").Return(publicKey, nil)
+	ownerClient.EXPECT().ShareSecret(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, s *models.Share) (*models.Share, error) {
+		share = s
+		return s, nil
+	})
+	if err = owner.ShareSecret(context.Background(), secret, " bob ", strings.ToUpper(fingerprint), false); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if share.SecretID != 5 || share.RecipientLogin != "bob" || share.CanEdit {
+		t.Fatalf("Unexpected share: %+v", share)
+	}
+
+	// Получатель расшифровывает секрет закрытым ключом без ключа хранилища владельца.
+	shared := *saved
+	shared.DataKey, shared.SearchTokens = nil, nil
+	shared.Share = &models.Share{ID: 9, OwnerLogin: testOwner, DataKey: share.DataKey}
+	recipientClient.EXPECT().ListSharedWithMe(gomock.Any()).Return([]*models.Secret{&shared}, nil)
+	recipientClient.EXPECT().GetPublicKey(gomock.Any(), testOwner).Return(ownerPublicKey, nil)
+	secrets, err := recipient.GetSharedWithMe(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	got := secrets[0]
+	if got.Title != "Notes" || got.Text == nil || got.Text.Content != "some text" {
+		t.Fatalf("Decrypted secret does not match expected data: %+v", got)
+	}
+	if crypto.Fingerprint(got.Share.OwnerPublicKey) != crypto.Fingerprint(ownerPublicKey) {
+		t.Errorf("Expected owner key to be attached to the share, got %x", got.Share.OwnerPublicKey)
+	}
+
+	// Сервер не может выдать свой ключ данных за переданный владельцем: ключ, зашифрованный без закрытого ключа
+	// владельца, не расшифровывается его открытым ключом.
+	forgedKey, err := crypto.WrapKeyFrom(bytes32(7), serverPrivateKey, publicKey, crypto.ShareAAD(testOwner, "bob", 5, string(models.TextSecret)))
+	if err != nil {
+		t.Fatalf("Failed to wrap data key: %v", err)
+	}
+	forged := *saved
+	forged.DataKey, forged.SearchTokens = nil, nil
+	forged.Share = &models.Share{ID: 9, OwnerLogin: testOwner, DataKey: forgedKey}
+	recipientClient.EXPECT().ListSharedWithMe(gomock.Any()).Return([]*models.Secret{&forged}, nil)
+	recipientClient.EXPECT().GetPublicKey(gomock.Any(), testOwner).Return(ownerPublicKey, nil)
+	if _, err = recipient.GetSharedWithMe(context.Background()); !errors.Is(err, crypto.ErrWrongKey) {
+		t.Errorf("Expected forged data key to be rejected with ErrWrongKey, got %v", err)
+	}
+
+	got.Text.Content = "changed"
+	if err = recipient.Update(context.Background(), got); !errors.Is(err, gophKeeperErrors.ErrShareReadOnly) {
+		t.Fatalf("Expected ErrShareReadOnly, got %v", err)
+	}
+
+	var updated *models.Secret
+	got.Share.CanEdit = true
+	recipientClient.EXPECT().UpdateSharedSecret(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, secret *models.Secret) (uint64, error) {
+		updated = secret
+		return 4, nil
+	})
+	if err = recipient.Update(context.Background(), got); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got.Revision != 4 || len(updated.DataKey) != 0 || len(updated.SearchTokens) != 0 {
+		t.Errorf("Unexpected shared update: revision %d, data key %x, tokens %v", got.Revision, updated.DataKey, updated.SearchTokens)
 	}
 
+	// Владелец читает изменения получателя своим ключом данных.
+	changed := *updated
+	changed.Share, changed.DataKey = nil, saved.DataKey
+	ownerClient.EXPECT().LoadSecret(gomock.Any(), uint64(5)).Return(&changed, nil)
+	if got, err = owner.Get(context.Background(), 5); err != nil || got.Text.Content != "changed" || got.Title != "Notes" {
+		t.Errorf("Expected owner to read the recipient's changes, got %+v, err = %v", got, err)
+	}
+
+	// Ключ данных, зашифрованный для одного получателя, не подходит другому.
+	stolen := shared
+	stolen.Share = &models.Share{OwnerLogin: testOwner, DataKey: share.DataKey, OwnerPublicKey: ownerPublicKey}
+	other := &RemoteStorage{owner: "eve", privateKey: privateKey}
+	if _, err = other.decrypt(&stolen); err == nil {
+		t.Error("Expected data key bound to another recipient to fail")
+	}
+}
+
+func TestRemoteStorage_ShareSecret_Fail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+	mockClient.EXPECT().GetPassword().Return("").AnyTimes()
+	mockClient.EXPECT().GetEncryptionKey().Return(make([]byte, 32)).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
 	rs, err := NewRemoteStorage(mockClient)
 	if err != nil {
 		t.Fatalf("Failed to create RemoteStorage: %v", err)
 	}
 
-	err = rs.Update(context.Background(), testSecret)
+	secret := &models.Secret{ID: 5, SecretType: string(models.TextSecret)}
+	if err = rs.ShareSecret(context.Background(), secret, "bob", "", false); !errors.Is(err, ErrVaultLocked) {
+		t.Errorf("Expected ErrVaultLocked, got %v", err)
+	}
+
+	_, privateKey, err := crypto.NewKeyPair()
 	if err != nil {
-		t.Errorf("Expected no error, got %v", err)
+		t.Fatalf("Failed to generate key pair: %v", err)
+	}
+	rs.vaultKey, rs.privateKey = bytes32(1), privateKey
+	if err = rs.ShareSecret(context.Background(), secret, "bob", "", false); !errors.Is(err, ErrNoDataKey) {
+		t.Errorf("Expected ErrNoDataKey, got %v", err)
+	}
+
+	secret.DataKey = wrapDataKey(t, bytes32(4), rs.vaultKey, 5, models.TextSecret)
+	mockClient.EXPECT().GetPublicKey(gomock.Any(), "bob").Return(nil, errors.New("user not found"))
+	if err = rs.ShareSecret(context.Background(), secret, "bob", "", false); err == nil {
+		t.Error("Expected error for an unknown recipient")
+	}
+
+	publicKey, _, err := crypto.NewKeyPair()
+	if err != nil {
+		t.Fatalf("Failed to generate key pair: %v", err)
+	}
+	mockClient.EXPECT().GetPublicKey(gomock.Any(), "bob").Return(publicKey, nil)
+	if err = rs.ShareSecret(context.Background(), secret, "bob", "", false); !errors.Is(err, crypto.ErrFingerprintMismatch) {
+		t.Errorf("Expected ErrFingerprintMismatch without a fingerprint, got %v", err)
 	}
 }
 
-func TestRemoteStorage_Delete(t *testing.T) {
+func TestRemoteStorage_EncryptLabels(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
 
-	mockClient.EXPECT().DeleteSecret(gomock.Any(), gomock.Eq(uint64(1))).Return(nil).AnyTimes()
-	mockClient.EXPECT().GetPassword().Return("test-password").AnyTimes()
-
+	key := make([]byte, 32)
+	mockClient.EXPECT().GetPassword().Return("").AnyTimes()
+	mockClient.EXPECT().GetEncryptionKey().Return(key).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return(testOwner).AnyTimes()
 	rs, err := NewRemoteStorage(mockClient)
 	if err != nil {
 		t.Fatalf("Failed to create RemoteStorage: %v", err)
 	}
 
-	err = rs.Delete(context.Background(), 1)
-	if err != nil {
+	mockClient.EXPECT().LoadSecrets(gomock.Any()).Return([]*models.Secret{
+		{ID: 1, Title: "Bank", Metadata: "note"},
+		{ID: 2, Title: "sealed", LabelsEncrypted: true},
+	}, nil)
+	mockClient.EXPECT().LoadTrash(gomock.Any()).Return([]*models.Secret{{ID: 3, Title: "Old mail"}}, nil)
+	mockClient.EXPECT().EncryptSecretLabels(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, secrets []*models.Secret) error {
+		if len(secrets) != 2 || secrets[0].ID != 1 || secrets[1].ID != 3 {
+			t.Fatalf("Expected secrets 1 and 3 to be sealed, got %+v", secrets)
+		}
+		for _, secret := range secrets {
+			if !secret.LabelsEncrypted || len(secret.SearchTokens) == 0 {
+				t.Errorf("Expected sealed labels with search tokens, got %+v", secret)
+			}
+		}
+		if title, err := openField(secrets[0].Title, key, 1, "", titleField); err != nil || title != "Bank" {
+			t.Errorf("Expected title to be encrypted with the key, got %q, err = %v", title, err)
+		}
+		return nil
+	})
+	if err = rs.EncryptLabels(context.Background()); err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
+
+	mockClient.EXPECT().LoadSecrets(gomock.Any()).Return([]*models.Secret{{ID: 2, LabelsEncrypted: true}}, nil)
+	mockClient.EXPECT().LoadTrash(gomock.Any()).Return(nil, nil)
+	if err = rs.EncryptLabels(context.Background()); err != nil {
+		t.Errorf("Expected no request without plaintext labels, got %v", err)
+	}
+
+	mockClient.EXPECT().LoadSecrets(gomock.Any()).Return(nil, fmt.Errorf("unavailable"))
+	if err = rs.EncryptLabels(context.Background()); err == nil {
+		t.Errorf("Expected load error")
+	}
+
+	// В открытом хранилище открытый заголовок мог подставить только сервер, поэтому он не шифруется.
+	rs.vaultKey = bytes32(1)
+	mockClient.EXPECT().LoadSecrets(gomock.Any()).Return([]*models.Secret{{ID: 4, Title: "Injected", DataKey: []byte("data_key")}}, nil)
+	mockClient.EXPECT().LoadTrash(gomock.Any()).Return(nil, nil)
+	if err = rs.EncryptLabels(context.Background()); !errors.Is(err, ErrLegacyCiphertext) {
+		t.Errorf("Expected ErrLegacyCiphertext, got %v", err)
+	}
 }
 
 func TestEncryptPayload(t *testing.T) {
@@ -343,9 +1744,10 @@ func TestEncryptPayload(t *testing.T) {
 	}
 
 	tests := []struct {
-		name      string
-		secret    *models.Secret
-		expectErr bool
+		name           string
+		secret         *models.Secret
+		expectErr      bool
+		expectedBlobID string
 	}{
 		{
 			name: "Encrypt_Success",
@@ -358,6 +1760,15 @@ func TestEncryptPayload(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name: "Encrypt_Blob_Reference",
+			secret: &models.Secret{
+				SecretType: string(models.BlobSecret),
+				Blob:       &models.Blob{FileName: "file.txt", BlobID: "0123456789abcdef0123456789abcdef"},
+			},
+			expectErr:      false,
+			expectedBlobID: "0123456789abcdef0123456789abcdef",
+		},
 	}
 
 	for _, tc := range tests {
@@ -369,6 +1780,9 @@ func TestEncryptPayload(t *testing.T) {
 			if !tc.expectErr && len(tc.secret.Payload) == 0 {
 				t.Errorf("Expected encrypted payload, got empty payload")
 			}
+			if tc.secret.BlobID != tc.expectedBlobID {
+				t.Errorf("Expected blob reference %q, got %q", tc.expectedBlobID, tc.secret.BlobID)
+			}
 		})
 	}
 }
@@ -382,6 +1796,7 @@ func TestDecryptPayload(t *testing.T) {
 
 	rs := &RemoteStorage{
 		deriveKey: deriveKey,
+		owner:     testOwner,
 	}
 
 	secretData := &models.Credentials{
@@ -389,7 +1804,7 @@ func TestDecryptPayload(t *testing.T) {
 		Password: "pass",
 	}
 	marshaledData, _ := json.Marshal(secretData)
-	encryptedData, _ := crypto.Encrypt(string(marshaledData), deriveKey)
+	encryptedData := sealPayload(t, string(marshaledData), deriveKey, 1, models.CredSecret, payloadField)
 
 	tests := []struct {
 		name      string
@@ -399,11 +1814,30 @@ func TestDecryptPayload(t *testing.T) {
 		{
 			name: "Decrypt_Success",
 			secret: &models.Secret{
+				ID:         1,
 				SecretType: string(models.CredSecret),
 				Payload:    []byte(encryptedData),
 			},
 			expectErr: false,
 		},
+		{
+			name: "Decrypt_Fail_OtherSecret",
+			secret: &models.Secret{
+				ID:         2,
+				SecretType: string(models.CredSecret),
+				Payload:    []byte(encryptedData),
+			},
+			expectErr: true,
+		},
+		{
+			name: "Decrypt_Fail_OtherType",
+			secret: &models.Secret{
+				ID:         1,
+				SecretType: string(models.TextSecret),
+				Payload:    []byte(encryptedData),
+			},
+			expectErr: true,
+		},
 		{
 			name: "Decrypt_Fail_DecryptError",
 			secret: &models.Secret{
@@ -531,3 +1965,58 @@ func TestMarshalSecret(t *testing.T) {
 		})
 	}
 }
+
+// testOwner - логин владельца хранилища в тестах.
+const testOwner = "user"
+
+// sealPayload шифрует данные секрета в конверт с привязкой к секрету так же, как хранилище владельца testOwner.
+func sealPayload(t *testing.T, plaintext string, key []byte, id uint64, secretType models.SecretType, field string) string {
+	t.Helper()
+
+	sealed, err := crypto.Seal([]byte(plaintext), key, crypto.SecretAAD(testOwner, id, string(secretType), field))
+	if err != nil {
+		t.Fatalf("Failed to encrypt data: %v", err)
+	}
+	return string(sealed)
+}
+
+// sealLabel шифрует заголовок или метаданные секрета так же, как хранилище владельца testOwner.
+func sealLabel(t *testing.T, plaintext string, key []byte, id uint64, secretType models.SecretType, field string) string {
+	t.Helper()
+
+	sealed, err := crypto.SealString(plaintext, key, crypto.SecretAAD(testOwner, id, string(secretType), field))
+	if err != nil {
+		t.Fatalf("Failed to encrypt label: %v", err)
+	}
+	return sealed
+}
+
+// openField расшифровывает значение поля секрета владельца testOwner, проверяя привязку к секрету
+// и то, что значение записано в текущем формате конверта.
+func openField(encrypted string, key []byte, id uint64, secretType models.SecretType, field string) (string, error) {
+	plaintext, format, err := crypto.OpenString(encrypted, key, crypto.SecretAAD(testOwner, id, string(secretType), field))
+	if err == nil && format != crypto.FormatEnvelope {
+		return "", fmt.Errorf("unexpected ciphertext format %d", format)
+	}
+	return plaintext, err
+}
+
+// wrapDataKey шифрует ключ данных секрета ключом хранилища так же, как хранилище владельца testOwner.
+func wrapDataKey(t *testing.T, dataKey, vaultKey []byte, id uint64, secretType models.SecretType) []byte {
+	t.Helper()
+
+	wrapped, err := crypto.WrapKey(dataKey, vaultKey, crypto.SecretAAD(testOwner, id, string(secretType), dataKeyField))
+	if err != nil {
+		t.Fatalf("Failed to wrap data key: %v", err)
+	}
+	return wrapped
+}
+
+// bytes32 возвращает 32-байтный ключ, заполненный значением b.
+func bytes32(b byte) []byte {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = b
+	}
+	return key
+}
@@ -0,0 +1,282 @@
+package storage
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/crypto"
+	"beliaev-aa/GophKeeper/internal/client/grpc"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// transferAttempts - количество попыток передачи файла. Каждая следующая попытка
+// продолжает передачу с первого не переданного фрагмента.
+const transferAttempts = 3
+
+// transferRetryDelay - пауза перед повторной попыткой передачи файла.
+var transferRetryDelay = time.Second
+
+// ErrNoFile возникает при скачивании секрета, не содержащего файла.
+var ErrNoFile = errors.New("secret has no file")
+
+// ErrSharedFileReadOnly возникает при загрузке файла в секрет, переданный другим пользователем:
+// файл хранится у владельца, и получатель может только скачать его.
+var ErrSharedFileReadOnly = errors.New("file of a shared secret cannot be replaced")
+
+// pendingUpload описывает незавершённую загрузку файла.
+type pendingUpload struct {
+	blob    models.Blob
+	modTime time.Time
+}
+
+// UploadFile загружает файл на сервер по фрагментам, каждый из которых шифруется отдельным ключом объекта,
+// и записывает в secret ссылку на загруженный объект. Сам секрет не сохраняется.
+// При обрыве связи загрузка продолжается с первого не полученного сервером фрагмента;
+// повторный вызов для того же неизменённого файла также продолжает прерванную загрузку.
+// Возвращает ErrSharedFileReadOnly для секрета, переданного другим пользователем.
+func (store *RemoteStorage) UploadFile(ctx context.Context, secret *models.Secret, path string, progress func(done, total int64)) error {
+	if secret.Share != nil {
+		return ErrSharedFileReadOnly
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("UploadFile(): failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("UploadFile(): failed to stat file: %w", err)
+	}
+
+	blob, err := store.pendingUpload(path, info)
+	if err != nil {
+		return fmt.Errorf("UploadFile(): failed to prepare upload: %w", err)
+	}
+	progress = reportProgress(progress)
+
+	chunk := func(index uint32) ([]byte, error) {
+		buf := make([]byte, crypto.BlobChunkSize)
+		n, err := file.ReadAt(buf, chunkOffset(index))
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to read chunk %d: %w", index, err)
+		}
+
+		data, err := crypto.EncryptChunk(blob.Key, blob.BlobID, index, blob.ChunkCount, buf[:n])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt chunk %d: %w", index, err)
+		}
+
+		progress(min(chunkOffset(index+1), blob.Size), blob.Size)
+		return data, nil
+	}
+
+	err = retryTransfer(ctx, func() error {
+		from, err := store.uploadedChunks(ctx, blob)
+		if err != nil {
+			return err
+		}
+		progress(min(chunkOffset(from), blob.Size), blob.Size)
+		if from >= blob.ChunkCount {
+			return nil
+		}
+
+		_, err = store.client.UploadBlob(ctx, blob.BlobID, blob.ChunkCount, from, chunk)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("UploadFile(): %w", err)
+	}
+
+	store.uploadsMu.Lock()
+	delete(store.uploads, path)
+	store.uploadsMu.Unlock()
+
+	secret.Blob = &blob
+	return nil
+}
+
+// DownloadFile сохраняет файл секрета по пути path. Содержимое скачивается по фрагментам во временный
+// файл рядом с path и переносится на место только после проверки всех фрагментов. Если скачивание
+// прервано, повторный вызов продолжает его с первого не сохранённого фрагмента.
+// Файлы, сохранённые целиком в секрете, записываются без обращения к серверу, а файл секрета, переданного
+// другим пользователем, скачивается из хранилища владельца через доступ к секрету.
+func (store *RemoteStorage) DownloadFile(ctx context.Context, secret *models.Secret, path string, progress func(done, total int64)) error {
+	if secret.Blob == nil {
+		return ErrNoFile
+	}
+	blob := secret.Blob
+	progress = reportProgress(progress)
+
+	if blob.BlobID == "" {
+		if err := os.WriteFile(path, blob.FileBytes, 0644); err != nil {
+			return fmt.Errorf("DownloadFile(): failed to write file: %w", err)
+		}
+		progress(int64(len(blob.FileBytes)), int64(len(blob.FileBytes)))
+		return nil
+	}
+
+	if secret.Share != nil {
+		ctx = grpc.WithShare(ctx, secret.Share.ID)
+	}
+
+	partPath := fmt.Sprintf("%s.%s.part", path, blob.BlobID)
+	file, err := os.OpenFile(partPath, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("DownloadFile(): failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	err = retryTransfer(ctx, func() error {
+		return store.downloadChunks(ctx, blob, file, progress)
+	})
+	if err != nil {
+		return fmt.Errorf("DownloadFile(): %w", err)
+	}
+
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("DownloadFile(): failed to close file: %w", err)
+	}
+	if err = os.Rename(partPath, path); err != nil {
+		return fmt.Errorf("DownloadFile(): failed to move file: %w", err)
+	}
+
+	return nil
+}
+
+// downloadChunks дописывает в file фрагменты объекта, начиная с первого не сохранённого в нём.
+func (store *RemoteStorage) downloadChunks(ctx context.Context, blob *models.Blob, file *os.File, progress func(done, total int64)) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	// Последний фрагмент мог быть записан не полностью, поэтому он скачивается заново.
+	from := uint32(min(info.Size(), blob.Size) / crypto.BlobChunkSize)
+	if err = file.Truncate(chunkOffset(from)); err != nil {
+		return err
+	}
+	progress(min(chunkOffset(from), blob.Size), blob.Size)
+
+	next := from
+	err = store.client.DownloadBlob(ctx, blob.BlobID, from, func(index, count uint32, data []byte) error {
+		if index != next || count != blob.ChunkCount {
+			return fmt.Errorf("unexpected chunk %d of %d", index, count)
+		}
+
+		plaintext, err := crypto.DecryptChunk(blob.Key, blob.BlobID, index, count, data)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt chunk %d: %w", index, err)
+		}
+		if _, err = file.WriteAt(plaintext, chunkOffset(index)); err != nil {
+			return fmt.Errorf("failed to write chunk %d: %w", index, err)
+		}
+
+		next++
+		progress(min(chunkOffset(next), blob.Size), blob.Size)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if next != blob.ChunkCount {
+		return fmt.Errorf("download ended after %d of %d chunks", next, blob.ChunkCount)
+	}
+
+	return nil
+}
+
+// pendingUpload возвращает описание объекта для загрузки файла. Если загрузка того же неизменённого
+// файла была прервана, возвращается прежний объект, иначе создаётся новый со случайными идентификатором и ключом.
+func (store *RemoteStorage) pendingUpload(path string, info os.FileInfo) (models.Blob, error) {
+	store.uploadsMu.Lock()
+	defer store.uploadsMu.Unlock()
+
+	if pending, ok := store.uploads[path]; ok && pending.blob.Size == info.Size() && pending.modTime.Equal(info.ModTime()) {
+		return pending.blob, nil
+	}
+
+	blobID, err := crypto.NewBlobID()
+	if err != nil {
+		return models.Blob{}, err
+	}
+	key, err := crypto.NewBlobKey()
+	if err != nil {
+		return models.Blob{}, err
+	}
+
+	blob := models.Blob{
+		FileName:   filepath.Base(path),
+		BlobID:     blobID,
+		Key:        key,
+		Size:       info.Size(),
+		ChunkCount: chunkCount(info.Size()),
+	}
+
+	if store.uploads == nil {
+		store.uploads = make(map[string]*pendingUpload)
+	}
+	store.uploads[path] = &pendingUpload{blob: blob, modTime: info.ModTime()}
+
+	return blob, nil
+}
+
+// uploadedChunks возвращает количество фрагментов объекта, уже полученных сервером.
+func (store *RemoteStorage) uploadedChunks(ctx context.Context, blob models.Blob) (uint32, error) {
+	status, err := store.client.GetBlobStatus(ctx, blob.BlobID)
+	if errors.Is(err, gophKeeperErrors.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return status.ReceivedChunks, nil
+}
+
+// retryTransfer выполняет попытку передачи transfer, повторяя её после ошибки,
+// пока не будет исчерпано количество попыток или отменён контекст.
+func retryTransfer(ctx context.Context, transfer func() error) error {
+	var err error
+	for attempt := 1; attempt <= transferAttempts; attempt++ {
+		if err = transfer(); err == nil {
+			return nil
+		}
+		if attempt == transferAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(transferRetryDelay):
+		}
+	}
+
+	return err
+}
+
+// reportProgress возвращает функцию уведомления о ходе передачи, заменяя отсутствующую пустой.
+func reportProgress(progress func(done, total int64)) func(done, total int64) {
+	if progress == nil {
+		return func(_, _ int64) {}
+	}
+	return progress
+}
+
+// chunkCount возвращает количество фрагментов файла размером size. Пустой файл передаётся одним пустым фрагментом.
+func chunkCount(size int64) uint32 {
+	return uint32(max(1, (size+crypto.BlobChunkSize-1)/crypto.BlobChunkSize))
+}
+
+// chunkOffset возвращает смещение фрагмента с индексом index в файле.
+func chunkOffset(index uint32) int64 {
+	return int64(index) * crypto.BlobChunkSize
+}
@@ -0,0 +1,281 @@
+package storage
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/crypto"
+	"beliaev-aa/GophKeeper/pkg/consts"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/metadata"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeBlobServer хранит загруженные фрагменты в памяти. Первые failures передач обрываются
+// после failAfter переданных фрагментов.
+type fakeBlobServer struct {
+	chunks    map[string][][]byte
+	failAfter int
+	failures  int
+}
+
+// interrupt сообщает, нужно ли оборвать передачу после sent фрагментов.
+func (srv *fakeBlobServer) interrupt(sent int) bool {
+	if srv.failures > 0 && sent == srv.failAfter {
+		srv.failures--
+		return true
+	}
+	return false
+}
+
+func newFakeBlobServer(mockClient *mocks.MockClientGRPCInterface) *fakeBlobServer {
+	srv := &fakeBlobServer{chunks: make(map[string][][]byte)}
+
+	mockClient.EXPECT().GetBlobStatus(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, blobID string) (*models.BlobStatus, error) {
+		chunks, ok := srv.chunks[blobID]
+		if !ok {
+			return nil, fmt.Errorf("blob %w", gophKeeperErrors.ErrNotFound)
+		}
+		return &models.BlobStatus{ID: blobID, ReceivedChunks: uint32(len(chunks))}, nil
+	}).AnyTimes()
+
+	mockClient.EXPECT().UploadBlob(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, blobID string, count, from uint32, chunk func(uint32) ([]byte, error)) (uint32, error) {
+			if int(from) != len(srv.chunks[blobID]) {
+				return 0, fmt.Errorf("unexpected chunk %d", from)
+			}
+			for i := from; i < count; i++ {
+				if srv.interrupt(int(i - from)) {
+					return i, errors.New("connection lost")
+				}
+
+				data, err := chunk(i)
+				if err != nil {
+					return i, err
+				}
+				srv.chunks[blobID] = append(srv.chunks[blobID], data)
+			}
+			return count, nil
+		}).AnyTimes()
+
+	mockClient.EXPECT().DownloadBlob(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, blobID string, from uint32, handle func(uint32, uint32, []byte) error) error {
+			chunks := srv.chunks[blobID]
+			for i := from; i < uint32(len(chunks)); i++ {
+				if srv.interrupt(int(i - from)) {
+					return errors.New("connection lost")
+				}
+
+				if err := handle(i, uint32(len(chunks)), chunks[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).AnyTimes()
+
+	return srv
+}
+
+func TestRemoteStorage_UploadDownloadFile(t *testing.T) {
+	transferRetryDelay = 0
+
+	tests := []struct {
+		name     string
+		size     int
+		failures int
+	}{
+		{name: "Empty_file", size: 0},
+		{name: "Single_chunk", size: 100},
+		{name: "Several_chunks", size: crypto.BlobChunkSize*2 + 10},
+		{name: "Resumed_after_connection_loss", size: crypto.BlobChunkSize*2 + 10, failures: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+			srv := newFakeBlobServer(mockClient)
+			store := &RemoteStorage{client: mockClient}
+
+			dir := t.TempDir()
+			content := bytes.Repeat([]byte("x"), tt.size)
+			src := filepath.Join(dir, "source.bin")
+			if err := os.WriteFile(src, content, 0600); err != nil {
+				t.Fatalf("failed to write file: %v", err)
+			}
+
+			srv.failAfter, srv.failures = 1, tt.failures
+			var done, total int64
+			secret := &models.Secret{SecretType: string(models.BlobSecret)}
+			err := store.UploadFile(context.Background(), secret, src, func(d, t int64) { done, total = d, t })
+			if err != nil {
+				t.Fatalf("UploadFile() error = %v", err)
+			}
+			if done != int64(tt.size) || total != int64(tt.size) {
+				t.Errorf("upload progress = %d/%d, want %d/%d", done, total, tt.size, tt.size)
+			}
+			if secret.Blob == nil || secret.Blob.FileName != "source.bin" || secret.Blob.Size != int64(tt.size) {
+				t.Fatalf("unexpected blob reference: %+v", secret.Blob)
+			}
+			if len(store.uploads) != 0 {
+				t.Errorf("pending uploads were not cleared")
+			}
+
+			srv.failures = tt.failures
+			dst := filepath.Join(dir, "target.bin")
+			if err = store.DownloadFile(context.Background(), secret, dst, nil); err != nil {
+				t.Fatalf("DownloadFile() error = %v", err)
+			}
+
+			got, err := os.ReadFile(dst)
+			if err != nil {
+				t.Fatalf("failed to read downloaded file: %v", err)
+			}
+			if !bytes.Equal(got, content) {
+				t.Errorf("downloaded content differs from uploaded")
+			}
+			if _, err = os.Stat(fmt.Sprintf("%s.%s.part", dst, secret.Blob.BlobID)); !os.IsNotExist(err) {
+				t.Errorf("part file was not removed")
+			}
+		})
+	}
+}
+
+func TestRemoteStorage_UploadFile_ResumesInterruptedUpload(t *testing.T) {
+	transferRetryDelay = 0
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+	srv := newFakeBlobServer(mockClient)
+	store := &RemoteStorage{client: mockClient}
+
+	src := filepath.Join(t.TempDir(), "source.bin")
+	if err := os.WriteFile(src, bytes.Repeat([]byte("y"), crypto.BlobChunkSize*transferAttempts+1), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	// Каждая попытка обрывается после одного фрагмента, поэтому первый вызов завершается ошибкой.
+	secret := &models.Secret{}
+	srv.failAfter, srv.failures = 1, transferAttempts
+	if err := store.UploadFile(context.Background(), secret, src, nil); err == nil {
+		t.Fatalf("expected UploadFile() to fail")
+	}
+	pending := store.uploads[src]
+	if pending == nil {
+		t.Fatalf("expected pending upload to be remembered")
+	}
+
+	if err := store.UploadFile(context.Background(), secret, src, nil); err != nil {
+		t.Fatalf("UploadFile() error = %v", err)
+	}
+	if got := len(srv.chunks[pending.blob.BlobID]); got != int(pending.blob.ChunkCount) {
+		t.Errorf("server received %d chunks, want %d", got, pending.blob.ChunkCount)
+	}
+	if secret.Blob.BlobID != pending.blob.BlobID {
+		t.Errorf("resumed upload used blob %s, want %s", secret.Blob.BlobID, pending.blob.BlobID)
+	}
+}
+
+func TestRemoteStorage_DownloadFile_Errors(t *testing.T) {
+	transferRetryDelay = 0
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+	store := &RemoteStorage{client: mockClient}
+	dir := t.TempDir()
+
+	t.Run("No_file", func(t *testing.T) {
+		err := store.DownloadFile(context.Background(), &models.Secret{}, filepath.Join(dir, "none"), nil)
+		if !errors.Is(err, ErrNoFile) {
+			t.Errorf("expected ErrNoFile, got %v", err)
+		}
+	})
+
+	t.Run("Legacy_file", func(t *testing.T) {
+		path := filepath.Join(dir, "legacy")
+		secret := &models.Secret{Blob: &models.Blob{FileName: "legacy", FileBytes: []byte("data")}}
+		if err := store.DownloadFile(context.Background(), secret, path, nil); err != nil {
+			t.Fatalf("DownloadFile() error = %v", err)
+		}
+		if got, _ := os.ReadFile(path); string(got) != "data" {
+			t.Errorf("unexpected content %q", got)
+		}
+	})
+
+	t.Run("Tampered_chunk", func(t *testing.T) {
+		key, _ := crypto.NewBlobKey()
+		blob := &models.Blob{BlobID: "abc", Key: key, Size: 4, ChunkCount: 1}
+		mockClient.EXPECT().DownloadBlob(gomock.Any(), "abc", uint32(0), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ string, _ uint32, handle func(uint32, uint32, []byte) error) error {
+				return handle(0, 1, bytes.Repeat([]byte{1}, 64))
+			}).Times(transferAttempts)
+
+		path := filepath.Join(dir, "tampered")
+		if err := store.DownloadFile(context.Background(), &models.Secret{Blob: blob}, path, nil); err == nil {
+			t.Errorf("expected error for tampered chunk")
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("tampered file must not be saved")
+		}
+	})
+}
+
+func TestRemoteStorage_SharedFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+	store := &RemoteStorage{client: mockClient}
+
+	blobID, err := crypto.NewBlobID()
+	if err != nil {
+		t.Fatalf("NewBlobID() error = %v", err)
+	}
+	key, err := crypto.NewBlobKey()
+	if err != nil {
+		t.Fatalf("NewBlobKey() error = %v", err)
+	}
+	chunk, err := crypto.EncryptChunk(key, blobID, 0, 1, []byte("content"))
+	if err != nil {
+		t.Fatalf("EncryptChunk() error = %v", err)
+	}
+
+	// Получатель скачивает файл владельца, указывая доступ к секрету в метаданных запроса.
+	blob := &models.Blob{BlobID: blobID, Key: key, ChunkCount: 1, Size: int64(len("content"))}
+	shared := &models.Secret{SecretType: string(models.BlobSecret), Blob: blob, Share: &models.Share{ID: 5}}
+	var shareID []string
+	mockClient.EXPECT().DownloadBlob(gomock.Any(), blobID, uint32(0), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ string, _ uint32, handle func(uint32, uint32, []byte) error) error {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			shareID = md.Get(consts.ShareIDHeader)
+			return handle(0, 1, chunk)
+		})
+
+	dir := t.TempDir()
+	dst := filepath.Join(dir, "target.bin")
+	if err = store.DownloadFile(context.Background(), shared, dst, nil); err != nil {
+		t.Fatalf("DownloadFile() error = %v", err)
+	}
+	if len(shareID) != 1 || shareID[0] != "5" {
+		t.Errorf("Expected share id 5 in request metadata, got %v", shareID)
+	}
+	if got, err := os.ReadFile(dst); err != nil || string(got) != "content" {
+		t.Errorf("Expected downloaded content, got %q, err = %v", got, err)
+	}
+
+	if err = store.UploadFile(context.Background(), shared, dst, nil); !errors.Is(err, ErrSharedFileReadOnly) {
+		t.Errorf("Expected ErrSharedFileReadOnly, got %v", err)
+	}
+}
@@ -9,6 +9,8 @@ var GlobalKeys = struct {
 	Quit key.Binding
 	// Help Горячая клавиша для вызова помощи
 	Help key.Binding
+	// SwitchPane Горячая клавиша для переключения фокуса между панелями
+	SwitchPane key.Binding
 }{
 	Quit: key.NewBinding(
 		key.WithKeys("ctrl+c", "esc"),
@@ -18,4 +20,8 @@ var GlobalKeys = struct {
 		key.WithKeys("ctrl+h"),
 		key.WithHelp("ctrl+h", "help"),
 	),
+	SwitchPane: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "switch pane"),
+	),
 }
@@ -2,6 +2,10 @@
 package tui
 
 import (
+	"beliaev-aa/GophKeeper/internal/client/storage"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"errors"
 	"fmt"
 	"github.com/charmbracelet/bubbletea"
 )
@@ -34,3 +38,30 @@ func SetBodyPane(screen Screen, opts ...NavigateOption) tea.Cmd {
 	opts = append(opts, WithPosition(BodyPane))
 	return NavigateTo(screen, opts...)
 }
+
+// SetLeftPane создает команду для установки содержимого боковой панели.
+func SetLeftPane(screen Screen, opts ...NavigateOption) tea.Cmd {
+	opts = append(opts, WithPosition(LeftPane))
+	return NavigateTo(screen, opts...)
+}
+
+// ClosePane создает команду для закрытия панели в указанной позиции.
+func ClosePane(position Position) tea.Cmd {
+	return CmdHandler(ClosePaneMsg{Position: position})
+}
+
+// SaveResult создает команду по результату сохранения секрета на экране редактирования.
+// При конфликте ревизий открывает экран разрешения конфликта, при прочих ошибках сообщает о них,
+// а при успехе возвращает к просмотру хранилища.
+func SaveResult(err error, secret *models.Secret, store storage.Storage) tea.Cmd {
+	var conflict *gophKeeperErrors.RevisionConflictError
+	if errors.As(err, &conflict) {
+		return SetBodyPane(SecretConflictScreen, WithSecret(secret), WithStorage(store))
+	}
+
+	if err != nil {
+		return ReportError(err)
+	}
+
+	return SetBodyPane(StorageBrowseScreen, WithStorage(store))
+}
@@ -0,0 +1,62 @@
+package components
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/tui/styles"
+	"fmt"
+	"strings"
+)
+
+// ProgressBar представляет индикатор хода длительной операции, например передачи файла.
+type ProgressBar struct {
+	Width int
+	Done  int64
+	Total int64
+}
+
+// NewProgressBar создает индикатор хода операции заданной ширины.
+func NewProgressBar(width int) ProgressBar {
+	return ProgressBar{Width: width}
+}
+
+// Set обновляет выполненный и общий объем операции.
+func (p *ProgressBar) Set(done, total int64) {
+	p.Done = done
+	p.Total = total
+}
+
+// Percent возвращает долю выполненной операции в диапазоне от 0 до 1.
+func (p ProgressBar) Percent() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+
+	return min(1, max(0, float64(p.Done)/float64(p.Total)))
+}
+
+// View отображает индикатор в виде полосы с процентом выполнения и объемом переданных данных.
+func (p ProgressBar) View() string {
+	filled := int(p.Percent() * float64(p.Width))
+
+	var b strings.Builder
+	b.WriteString(styles.Focused.Render(strings.Repeat("█", filled)))
+	b.WriteString(styles.Blurred.Render(strings.Repeat("░", p.Width-filled)))
+	b.WriteString(fmt.Sprintf(" %3.0f%% %s / %s", p.Percent()*100, formatBytes(p.Done), formatBytes(p.Total)))
+
+	return b.String()
+}
+
+// formatBytes форматирует объем данных в байтах в удобочитаемом виде.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
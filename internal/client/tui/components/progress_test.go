@@ -0,0 +1,46 @@
+package components
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestProgressBar_Percent(t *testing.T) {
+	tests := []struct {
+		name     string
+		done     int64
+		total    int64
+		expected float64
+	}{
+		{name: "Not_started", done: 0, total: 0, expected: 0},
+		{name: "Half", done: 50, total: 100, expected: 0.5},
+		{name: "Complete", done: 100, total: 100, expected: 1},
+		{name: "Overflow", done: 150, total: 100, expected: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewProgressBar(10)
+			p.Set(tc.done, tc.total)
+			assert.Equal(t, tc.expected, p.Percent())
+		})
+	}
+}
+
+func TestProgressBar_View(t *testing.T) {
+	p := NewProgressBar(10)
+	p.Set(3*1024*1024, 4*1024*1024)
+
+	view := p.View()
+	assert.Equal(t, 7, strings.Count(view, "█"))
+	assert.Equal(t, 3, strings.Count(view, "░"))
+	assert.Contains(t, view, "75%")
+	assert.Contains(t, view, "3.0 MiB / 4.0 MiB")
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "1.5 KiB", formatBytes(1536))
+	assert.Equal(t, "2.0 GiB", formatBytes(2<<30))
+}
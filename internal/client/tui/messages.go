@@ -5,22 +5,34 @@ import (
 	"beliaev-aa/GophKeeper/internal/client/grpc"
 	"beliaev-aa/GophKeeper/internal/client/storage"
 	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
 	"github.com/charmbracelet/bubbletea"
 )
 
 // NavigationCallback определяет тип функции обратного вызова для навигационных сообщений.
 type NavigationCallback func(args ...any) tea.Cmd
 
+// Transfer описывает длительную передачу файла, выполняемую на экране передачи.
+// Run выполняет передачу, сообщая о её ходе через progress, а Done по результату передачи
+// возвращает команду, выполняемую после её завершения.
+type Transfer struct {
+	Title string
+	Run   func(ctx context.Context, progress func(done, total int64)) error
+	Done  func(err error) tea.Cmd
+}
+
 // NavigationMsg представляет сообщение, используемое для навигации и конфигурации экранов в TUI.
 type NavigationMsg struct {
 	Callback     NavigationCallback
 	Client       grpc.ClientGRPCInterface
 	DisableFocus bool
+	Organization *models.Organization
 	Page         Page
 	Position     Position
 	Screen       Screen
 	Secret       *models.Secret
 	Storage      storage.Storage
+	Transfer     *Transfer
 }
 
 // NewNavigationMsg создаёт новое навигационное сообщение с указанными настройками экрана и опциональными параметрами.
@@ -49,6 +61,13 @@ func WithClient(c grpc.ClientGRPCInterface) NavigateOption {
 	}
 }
 
+// WithOrganization определяет опцию навигации для установки организации, с которой работает экран.
+func WithOrganization(org *models.Organization) NavigateOption {
+	return func(msg *NavigationMsg) {
+		msg.Organization = org
+	}
+}
+
 // WithPosition определяет опцию навигации для установки позиции элемента.
 func WithPosition(position Position) NavigateOption {
 	return func(msg *NavigationMsg) {
@@ -56,6 +75,13 @@ func WithPosition(position Position) NavigateOption {
 	}
 }
 
+// WithoutFocus определяет опцию навигации, при которой открываемая панель не получает фокус.
+func WithoutFocus() NavigateOption {
+	return func(msg *NavigationMsg) {
+		msg.DisableFocus = true
+	}
+}
+
 // WithStorage определяет опцию навигации для установки объекта хранилища.
 func WithStorage(store storage.Storage) NavigateOption {
 	return func(msg *NavigationMsg) {
@@ -69,3 +95,26 @@ func WithSecret(sec *models.Secret) NavigateOption {
 		msg.Secret = sec
 	}
 }
+
+// WithTransfer определяет опцию навигации для установки выполняемой передачи файла.
+func WithTransfer(transfer *Transfer) NavigateOption {
+	return func(msg *NavigationMsg) {
+		msg.Transfer = transfer
+	}
+}
+
+// ClosePaneMsg представляет сообщение о закрытии панели в указанной позиции.
+type ClosePaneMsg struct {
+	Position Position
+}
+
+// SecretFilterMsg сообщает экрану просмотра хранилища папку и метку, выбранные на боковой панели.
+// Нулевые идентификаторы не ограничивают список секретов.
+type SecretFilterMsg models.SecretFilter
+
+// SecretFilterRequestMsg запрашивает у боковой панели текущий фильтр; панель отвечает сообщением SecretFilterMsg.
+type SecretFilterRequestMsg struct{}
+
+// AssignLabelMsg просит экран просмотра хранилища поместить выбранный секрет в папку FolderID
+// (нулевой идентификатор убирает секрет из папки) или, если указана метка TagID, добавить или снять эту метку.
+type AssignLabelMsg models.SecretFilter
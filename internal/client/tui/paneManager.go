@@ -38,11 +38,47 @@ const (
 
 	// BlobEditScreen Экран редактирования файлов
 	BlobEditScreen
+
+	// PasswordChangeScreen Экран смены мастер-пароля
+	PasswordChangeScreen
+
+	// AccountDeleteScreen Экран удаления учётной записи
+	AccountDeleteScreen
+
+	// SecretHistoryScreen Экран истории версий секрета
+	SecretHistoryScreen
+
+	// TrashScreen Экран корзины
+	TrashScreen
+
+	// SecretConflictScreen Экран разрешения конфликта одновременного изменения секрета
+	SecretConflictScreen
+
+	// TransferScreen Экран передачи файла
+	TransferScreen
+
+	// SidebarScreen Боковая панель папок и меток
+	SidebarScreen
+
+	// OrganizationsScreen Экран организаций пользователя
+	OrganizationsScreen
+
+	// OrgMembersScreen Экран участников организации
+	OrgMembersScreen
+
+	// EmergencyContactsScreen Экран доверенных пользователей с экстренным доступом к хранилищу
+	EmergencyContactsScreen
+
+	// EmergencyGrantsScreen Экран хранилищ, к которым пользователю назначен экстренный доступ
+	EmergencyGrantsScreen
 )
 
 const (
 	// BodyPane Основная панель
 	BodyPane Position = iota
+
+	// LeftPane Боковая панель слева от основной
+	LeftPane
 )
 
 type (
@@ -55,6 +91,9 @@ type (
 
 const borderSize = 2
 
+// leftPaneWidth - ширина боковой панели, включая границы.
+const leftPaneWidth = 30
+
 // PaneManager управляет панелями в текстовом интерфейсе пользователя.
 type PaneManager struct {
 	makers        map[Screen]ScreenMaker
@@ -94,6 +133,10 @@ func (pm *PaneManager) Update(msg tea.Msg) tea.Cmd {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if key.Matches(msg, GlobalKeys.SwitchPane) {
+			pm.cycleFocusedPane()
+			break
+		}
 		commands = append(commands, pm.updateModel(pm.focused, msg))
 
 	case tea.WindowSizeMsg:
@@ -103,6 +146,8 @@ func (pm *PaneManager) Update(msg tea.Msg) tea.Cmd {
 		pm.updateChildSizes()
 	case NavigationMsg:
 		commands = append(commands, pm.setPane(msg))
+	case ClosePaneMsg:
+		pm.closePane(msg.Position)
 	default:
 		commands = pm.cache.UpdateAll(msg)
 	}
@@ -117,10 +162,13 @@ func (pm *PaneManager) FocusedModel() TeaLike {
 
 func (pm *PaneManager) cycleFocusedPane() {
 	positions := maps.Keys(pm.panes)
+	if len(positions) == 0 {
+		return
+	}
 	slices.Sort(positions)
 
-	focusedIndex := int(pm.focused)
-	totalPanes := len(pm.panes)
+	focusedIndex := slices.Index(positions, pm.focused)
+	totalPanes := len(positions)
 
 	if focusedIndex >= totalPanes-1 {
 		focusedIndex = 0
@@ -131,6 +179,20 @@ func (pm *PaneManager) cycleFocusedPane() {
 	pm.focusPane(positions[focusedIndex])
 }
 
+// closePane убирает панель из указанной позиции и передаёт фокус основной панели, если закрытая панель была в фокусе.
+func (pm *PaneManager) closePane(position Position) {
+	if _, ok := pm.panes[position]; !ok {
+		return
+	}
+
+	delete(pm.panes, position)
+	if pm.focused == position {
+		pm.focusPane(BodyPane)
+	}
+
+	pm.updateChildSizes()
+}
+
 func (pm *PaneManager) updateChildSizes() {
 	for position := range pm.panes {
 		pm.updateModel(position, tea.WindowSizeMsg{
@@ -195,8 +257,17 @@ func (pm *PaneManager) focusPane(position Position) {
 	}
 }
 
-func (pm *PaneManager) paneWidth(_ Position) int {
-	return pm.width
+func (pm *PaneManager) paneWidth(position Position) int {
+	_, hasLeftPane := pm.panes[LeftPane]
+
+	switch {
+	case position == LeftPane:
+		return leftPaneWidth
+	case hasLeftPane:
+		return max(0, pm.width-leftPaneWidth)
+	default:
+		return pm.width
+	}
 }
 
 func (pm *PaneManager) paneHeight(_ Position) int {
@@ -207,6 +278,7 @@ func (pm *PaneManager) paneHeight(_ Position) int {
 func (pm *PaneManager) View() string {
 	return lipgloss.JoinVertical(lipgloss.Top,
 		lipgloss.JoinHorizontal(lipgloss.Top,
+			pm.renderPane(LeftPane),
 			pm.renderPane(BodyPane),
 		),
 	)
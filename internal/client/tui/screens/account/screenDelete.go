@@ -0,0 +1,110 @@
+package account
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/grpc"
+	"beliaev-aa/GophKeeper/internal/client/storage"
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/internal/client/tui/components"
+	"beliaev-aa/GophKeeper/internal/client/tui/screens"
+	"beliaev-aa/GophKeeper/internal/client/tui/styles"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+)
+
+// deleteConfirmation фраза, которую пользователь вводит для подтверждения удаления учётной записи.
+const deleteConfirmation = "delete my account"
+
+const (
+	posDeletePassword = iota
+	posDeleteConfirm
+)
+
+// AccountDeleteScreen структура для экрана удаления учётной записи.
+type AccountDeleteScreen struct {
+	client     grpc.ClientGRPCInterface
+	inputGroup components.InputGroup
+	storage    storage.Storage
+}
+
+// AccountDeleteScreenMaker структура для создания экрана AccountDeleteScreen.
+type AccountDeleteScreenMaker struct {
+	Client grpc.ClientGRPCInterface
+}
+
+// Make создаёт новый экран AccountDeleteScreen; хранилище из сообщения навигации используется для возврата назад.
+func (m AccountDeleteScreenMaker) Make(msg tui.NavigationMsg, _, _ int) (tui.TeaLike, error) {
+	return NewAccountDeleteScreen(m.Client, msg.Storage), nil
+}
+
+// NewAccountDeleteScreen создаёт и инициализирует новый экземпляр AccountDeleteScreen.
+func NewAccountDeleteScreen(client grpc.ClientGRPCInterface, store storage.Storage) *AccountDeleteScreen {
+	m := AccountDeleteScreen{
+		client:  client,
+		storage: store,
+	}
+
+	inputs := make([]textinput.Model, 2)
+	inputs[posDeletePassword] = newPasswordInput("Password")
+	inputs[posDeleteConfirm] = textinput.New()
+	inputs[posDeleteConfirm].CharLimit = 64
+	inputs[posDeleteConfirm].Placeholder = fmt.Sprintf("Type %q to confirm", deleteConfirmation)
+
+	var buttons []components.Button
+	buttons = append(buttons, components.Button{Title: "[ Delete account ]", Cmd: func() tea.Cmd {
+		if err := m.Submit(); err != nil {
+			return tui.ReportError(err)
+		}
+		return tea.Batch(
+			tui.ReportInfo("account deleted"),
+			tui.SetBodyPane(tui.LoginScreen, tui.WithClient(m.client)),
+		)
+	}})
+
+	buttons = append(buttons, components.Button{Title: "[ Back ]", Cmd: func() tea.Cmd {
+		return tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(m.storage))
+	}})
+
+	m.inputGroup = components.NewInputGroup(inputs, buttons)
+
+	return &m
+}
+
+// Init инициализирует компоненты экрана.
+func (s *AccountDeleteScreen) Init() tea.Cmd {
+	return s.inputGroup.Init()
+}
+
+// Update обрабатывает пользовательский ввод и обновляет состояние экрана.
+func (s *AccountDeleteScreen) Update(msg tea.Msg) tea.Cmd {
+	ig, cmd := s.inputGroup.Update(msg)
+	s.inputGroup = ig.(components.InputGroup)
+
+	return cmd
+}
+
+// Submit проверяет подтверждение и удаляет учётную запись вместе со всеми секретами.
+// Поле пароля очищается после каждой попытки.
+func (s *AccountDeleteScreen) Submit() error {
+	password := s.inputGroup.Inputs[posDeletePassword].Value()
+	confirm := s.inputGroup.Inputs[posDeleteConfirm].Value()
+
+	defer s.inputGroup.Inputs[posDeletePassword].SetValue("")
+
+	if confirm != deleteConfirmation {
+		return fmt.Errorf("please type %q to confirm", deleteConfirmation)
+	}
+	if len(password) == 0 {
+		return errors.New("please enter password")
+	}
+
+	return s.client.DeleteAccount(context.Background(), password)
+}
+
+// View отображает текущее состояние экрана в виде строки.
+func (s *AccountDeleteScreen) View() string {
+	warning := styles.Highlighted.Render("All secrets will be permanently deleted and all devices will be signed out.")
+	return screens.RenderContent("Delete account:", warning+"\n\n"+s.inputGroup.View())
+}
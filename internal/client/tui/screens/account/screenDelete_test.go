@@ -0,0 +1,101 @@
+package account
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/grpc"
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"github.com/golang/mock/gomock"
+	"strings"
+	"testing"
+)
+
+func Test_AccountDeleteScreenMaker_Make(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	maker := AccountDeleteScreenMaker{Client: mocks.NewMockClientGRPCInterface(ctrl)}
+	result, err := maker.Make(tui.NavigationMsg{Storage: mocks.NewMockStorage(ctrl)}, 0, 0)
+	if err != nil {
+		t.Errorf("Make returned an error: %v", err)
+	}
+
+	if _, ok := result.(*AccountDeleteScreen); !ok {
+		t.Errorf("Expected result to be *AccountDeleteScreen, got %T", result)
+	}
+}
+
+func Test_AccountDeleteScreen_Submit(t *testing.T) {
+	tests := []struct {
+		name      string
+		password  string
+		confirm   string
+		setupMock func(mockClient *mocks.MockClientGRPCInterface)
+		expectErr string
+	}{
+		{
+			name:     "Success",
+			password: "secret",
+			confirm:  deleteConfirmation,
+			setupMock: func(mockClient *mocks.MockClientGRPCInterface) {
+				mockClient.EXPECT().DeleteAccount(gomock.Any(), "secret").Return(nil).Times(1)
+			},
+		},
+		{
+			name:      "Not_Confirmed",
+			password:  "secret",
+			confirm:   "delete",
+			setupMock: func(_ *mocks.MockClientGRPCInterface) {},
+			expectErr: `please type "delete my account" to confirm`,
+		},
+		{
+			name:      "Empty_Password",
+			confirm:   deleteConfirmation,
+			setupMock: func(_ *mocks.MockClientGRPCInterface) {},
+			expectErr: "please enter password",
+		},
+		{
+			name:     "Wrong_Password",
+			password: "wrong",
+			confirm:  deleteConfirmation,
+			setupMock: func(mockClient *mocks.MockClientGRPCInterface) {
+				mockClient.EXPECT().DeleteAccount(gomock.Any(), "wrong").Return(grpc.ErrWrongPassword).Times(1)
+			},
+			expectErr: grpc.ErrWrongPassword.Error(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mocks.NewMockClientGRPCInterface(ctrl)
+			tc.setupMock(mockClient)
+
+			screen := NewAccountDeleteScreen(mockClient, mocks.NewMockStorage(ctrl))
+			screen.inputGroup.Inputs[posDeletePassword].SetValue(tc.password)
+			screen.inputGroup.Inputs[posDeleteConfirm].SetValue(tc.confirm)
+
+			err := screen.Submit()
+			if tc.expectErr == "" && err != nil {
+				t.Errorf("Submit failed with error: %v", err)
+			}
+			if tc.expectErr != "" && (err == nil || err.Error() != tc.expectErr) {
+				t.Errorf("Expected error %q, got %v", tc.expectErr, err)
+			}
+			if screen.inputGroup.Inputs[posDeletePassword].Value() != "" {
+				t.Error("Expected password input to be cleared")
+			}
+		})
+	}
+}
+
+func Test_AccountDeleteScreen_View(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	screen := NewAccountDeleteScreen(mocks.NewMockClientGRPCInterface(ctrl), mocks.NewMockStorage(ctrl))
+
+	view := screen.View()
+	if !strings.Contains(view, "Delete account:") || !strings.Contains(view, "permanently deleted") {
+		t.Errorf("View did not render the warning, got %q", view)
+	}
+}
@@ -0,0 +1,119 @@
+// Package account содержит экраны управления учётной записью пользователя в TUI приложении.
+package account
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/storage"
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/internal/client/tui/components"
+	"beliaev-aa/GophKeeper/internal/client/tui/screens"
+	"context"
+	"errors"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+)
+
+const (
+	posCurrent = iota
+	posNew
+	posConfirm
+)
+
+// PasswordChangeScreen структура для экрана смены мастер-пароля.
+type PasswordChangeScreen struct {
+	inputGroup components.InputGroup
+	storage    storage.Storage
+}
+
+// Make создаёт новый экран PasswordChangeScreen на основе переданных параметров.
+func (s *PasswordChangeScreen) Make(msg tui.NavigationMsg, _, _ int) (tui.TeaLike, error) {
+	return NewPasswordChangeScreen(msg.Storage), nil
+}
+
+// NewPasswordChangeScreen создаёт и инициализирует новый экземпляр PasswordChangeScreen.
+func NewPasswordChangeScreen(store storage.Storage) *PasswordChangeScreen {
+	m := PasswordChangeScreen{
+		storage: store,
+	}
+
+	inputs := make([]textinput.Model, 3)
+	inputs[posCurrent] = newPasswordInput("Current password")
+	inputs[posNew] = newPasswordInput("New password")
+	inputs[posConfirm] = newPasswordInput("Repeat new password")
+
+	var buttons []components.Button
+	buttons = append(buttons, components.Button{Title: "[ Change ]", Cmd: func() tea.Cmd {
+		if err := m.Submit(); err != nil {
+			return tui.ReportError(err)
+		}
+		return tea.Batch(
+			tui.ReportInfo("password changed, other devices were signed out"),
+			tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(m.storage)),
+		)
+	}})
+
+	buttons = append(buttons, components.Button{Title: "[ Back ]", Cmd: func() tea.Cmd {
+		return tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(m.storage))
+	}})
+
+	m.inputGroup = components.NewInputGroup(inputs, buttons)
+
+	return &m
+}
+
+// Init инициализирует компоненты экрана.
+func (s *PasswordChangeScreen) Init() tea.Cmd {
+	return s.inputGroup.Init()
+}
+
+// Update обрабатывает пользовательский ввод и обновляет состояние экрана.
+func (s *PasswordChangeScreen) Update(msg tea.Msg) tea.Cmd {
+	ig, cmd := s.inputGroup.Update(msg)
+	s.inputGroup = ig.(components.InputGroup)
+
+	return cmd
+}
+
+// Submit проверяет введённые пароли и перешифровывает хранилище новым мастер-паролем.
+// Поля паролей очищаются после каждой попытки.
+func (s *PasswordChangeScreen) Submit() error {
+	current := s.inputGroup.Inputs[posCurrent].Value()
+	password := s.inputGroup.Inputs[posNew].Value()
+	confirm := s.inputGroup.Inputs[posConfirm].Value()
+
+	defer func() {
+		for i := range s.inputGroup.Inputs {
+			s.inputGroup.Inputs[i].SetValue("")
+		}
+	}()
+
+	if len(current) == 0 {
+		return errors.New("please enter current password")
+	}
+	if len(password) == 0 {
+		return errors.New("please enter new password")
+	}
+	if password != confirm {
+		return errors.New("new passwords do not match")
+	}
+	if password == current {
+		return errors.New("new password must differ from the current one")
+	}
+
+	return s.storage.ChangePassword(context.Background(), current, password)
+}
+
+// View отображает текущее состояние экрана в виде строки.
+func (s *PasswordChangeScreen) View() string {
+	return screens.RenderContent("Change master password:", s.inputGroup.View())
+}
+
+// newPasswordInput создаёт поле ввода пароля со скрытыми символами.
+func newPasswordInput(placeholder string) textinput.Model {
+	t := textinput.New()
+	t.CharLimit = 64
+	t.Placeholder = placeholder
+	t.EchoMode = textinput.EchoPassword
+	t.EchoCharacter = '*'
+
+	return t
+}
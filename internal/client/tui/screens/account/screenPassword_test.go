@@ -0,0 +1,116 @@
+package account
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"strings"
+	"testing"
+)
+
+func Test_PasswordChangeScreen_Make(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	screen := &PasswordChangeScreen{}
+	result, err := screen.Make(tui.NavigationMsg{Storage: mocks.NewMockStorage(ctrl)}, 0, 0)
+	if err != nil {
+		t.Errorf("Make returned an error: %v", err)
+	}
+
+	if _, ok := result.(*PasswordChangeScreen); !ok {
+		t.Errorf("Expected result to be *PasswordChangeScreen, got %T", result)
+	}
+}
+
+func Test_PasswordChangeScreen_Submit(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   string
+		password  string
+		confirm   string
+		setupMock func(mockStorage *mocks.MockStorage)
+		expectErr string
+	}{
+		{
+			name:     "Success",
+			current:  "old",
+			password: "new",
+			confirm:  "new",
+			setupMock: func(mockStorage *mocks.MockStorage) {
+				mockStorage.EXPECT().ChangePassword(gomock.Any(), "old", "new").Return(nil).Times(1)
+			},
+		},
+		{
+			name:      "Empty_Current",
+			password:  "new",
+			confirm:   "new",
+			setupMock: func(_ *mocks.MockStorage) {},
+			expectErr: "please enter current password",
+		},
+		{
+			name:      "Mismatch",
+			current:   "old",
+			password:  "new",
+			confirm:   "other",
+			setupMock: func(_ *mocks.MockStorage) {},
+			expectErr: "new passwords do not match",
+		},
+		{
+			name:      "Same_Password",
+			current:   "old",
+			password:  "old",
+			confirm:   "old",
+			setupMock: func(_ *mocks.MockStorage) {},
+			expectErr: "new password must differ from the current one",
+		},
+		{
+			name:     "Storage_Error",
+			current:  "old",
+			password: "new",
+			confirm:  "new",
+			setupMock: func(mockStorage *mocks.MockStorage) {
+				mockStorage.EXPECT().ChangePassword(gomock.Any(), "old", "new").Return(errors.New("current password is incorrect")).Times(1)
+			},
+			expectErr: "current password is incorrect",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := mocks.NewMockStorage(ctrl)
+			tc.setupMock(mockStorage)
+
+			screen := NewPasswordChangeScreen(mockStorage)
+			screen.inputGroup.Inputs[posCurrent].SetValue(tc.current)
+			screen.inputGroup.Inputs[posNew].SetValue(tc.password)
+			screen.inputGroup.Inputs[posConfirm].SetValue(tc.confirm)
+
+			err := screen.Submit()
+			if tc.expectErr == "" && err != nil {
+				t.Errorf("Submit failed with error: %v", err)
+			}
+			if tc.expectErr != "" && (err == nil || err.Error() != tc.expectErr) {
+				t.Errorf("Expected error %q, got %v", tc.expectErr, err)
+			}
+			for i, input := range screen.inputGroup.Inputs {
+				if input.Value() != "" {
+					t.Errorf("Expected input %d to be cleared", i)
+				}
+			}
+		})
+	}
+}
+
+func Test_PasswordChangeScreen_View(t *testing.T) {
+	screen := NewPasswordChangeScreen(mocks.NewMockStorage(gomock.NewController(t)))
+
+	view := screen.View()
+	if !strings.Contains(view, "Change master password:") {
+		t.Errorf("View did not render the header, got %q", view)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"beliaev-aa/GophKeeper/internal/client/tui/styles"
 	"context"
 	"errors"
+	"fmt"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbletea"
 )
@@ -19,6 +20,9 @@ const (
 	posPassword
 )
 
+// posCode позиция поля кода второго фактора на шаге подтверждения входа.
+const posCode = 0
+
 // Mode режимы работы экрана: вход или регистрация.
 type Mode int
 
@@ -27,10 +31,24 @@ const (
 	modeRegister
 )
 
+// legacyPrompt - вопрос о согласии передать мастер-пароль серверу для миграции учётной записи со старой схемой.
+const legacyPrompt = "this account uses the legacy password scheme: send the master password to the server once to migrate it?"
+
+// legacyConsentMsg сообщает о согласии пользователя однократно передать мастер-пароль серверу
+// для миграции учётной записи.
+type legacyConsentMsg struct {
+	login    string
+	password string
+}
+
 // AuthenticateScreen структура для экрана входа и регистрации.
 type AuthenticateScreen struct {
 	client     grpc.ClientGRPCInterface
 	inputGroup components.InputGroup
+	// password хранит мастер-пароль между вводом учётных данных и вводом кода второго фактора.
+	password string
+	// awaitingCode признак того, что экран ожидает код второго фактора.
+	awaitingCode bool
 }
 
 type inputOpts struct {
@@ -50,6 +68,15 @@ func NewLoginScreen(client grpc.ClientGRPCInterface) *AuthenticateScreen {
 	m := AuthenticateScreen{
 		client: client,
 	}
+	m.showCredentials()
+
+	return &m
+}
+
+// showCredentials переключает экран на ввод логина и пароля.
+func (s *AuthenticateScreen) showCredentials() {
+	s.password = ""
+	s.awaitingCode = false
 
 	inputs := make([]textinput.Model, 2)
 	inputs[posLogin] = newInput(inputOpts{placeholder: "Login", charLimit: 64})
@@ -57,21 +84,40 @@ func NewLoginScreen(client grpc.ClientGRPCInterface) *AuthenticateScreen {
 
 	var buttons []components.Button
 	buttons = append(buttons, components.Button{Title: "[ Login ]", Cmd: func() tea.Cmd {
-		return m.Submit(modeLogin)
+		return s.Submit(modeLogin)
 	}})
 
 	buttons = append(buttons, components.Button{Title: "[ Register ]", Cmd: func() tea.Cmd {
-		return m.Submit(modeRegister)
+		return s.Submit(modeRegister)
 	}})
 
-	m.inputGroup = components.NewInputGroup(inputs, buttons)
+	s.inputGroup = components.NewInputGroup(inputs, buttons)
+}
 
-	return &m
+// showCodeInput переключает экран на ввод кода второго фактора.
+func (s *AuthenticateScreen) showCodeInput(password string) {
+	s.password = password
+	s.awaitingCode = true
+
+	inputs := make([]textinput.Model, 1)
+	inputs[posCode] = newInput(inputOpts{placeholder: "Authentication or recovery code", charLimit: 16})
+
+	var buttons []components.Button
+	buttons = append(buttons, components.Button{Title: "[ Verify ]", Cmd: func() tea.Cmd {
+		return s.SubmitCode()
+	}})
+
+	buttons = append(buttons, components.Button{Title: "[ Cancel ]", Cmd: func() tea.Cmd {
+		s.showCredentials()
+		return s.inputGroup.Init()
+	}})
+
+	s.inputGroup = components.NewInputGroup(inputs, buttons)
 }
 
-// Init инициализирует компоненты экрана.
+// Init инициализирует компоненты экрана и закрывает боковую панель папок и меток предыдущего пользователя.
 func (s *AuthenticateScreen) Init() tea.Cmd {
-	return s.inputGroup.Init()
+	return tea.Batch(s.inputGroup.Init(), tui.ClosePane(tui.LeftPane))
 }
 
 // Update обрабатывает пользовательский ввод и обновляет состояние экрана.
@@ -81,6 +127,11 @@ func (s *AuthenticateScreen) Update(msg tea.Msg) tea.Cmd {
 		commands []tea.Cmd
 	)
 
+	if msg, ok := msg.(legacyConsentMsg); ok {
+		token, err := s.client.MigrateLegacyLogin(context.Background(), msg.login, msg.password)
+		return s.handleLogin(token, msg.password, err)
+	}
+
 	ig, cmd := s.inputGroup.Update(msg)
 	s.inputGroup = ig.(components.InputGroup)
 
@@ -92,9 +143,8 @@ func (s *AuthenticateScreen) Update(msg tea.Msg) tea.Cmd {
 // Submit обрабатывает отправку данных для входа или регистрации.
 func (s *AuthenticateScreen) Submit(mode Mode) tea.Cmd {
 	var (
-		token    string
-		err      error
-		commands []tea.Cmd
+		token string
+		err   error
 	)
 
 	login := s.inputGroup.Inputs[posLogin].Value()
@@ -114,20 +164,69 @@ func (s *AuthenticateScreen) Submit(mode Mode) tea.Cmd {
 		token, err = s.client.Register(context.Background(), login, password)
 	}
 
+	if errors.Is(err, grpc.ErrLegacyMigrationRequired) {
+		return tui.YesNoPrompt(legacyPrompt, func() tea.Msg {
+			return legacyConsentMsg{login: login, password: password}
+		})
+	}
+
+	return s.handleLogin(token, password, err)
+}
+
+// handleLogin завершает вход по результату запроса к серверу или переходит к вводу кода второго фактора.
+func (s *AuthenticateScreen) handleLogin(token, password string, err error) tea.Cmd {
+	if errors.Is(err, grpc.ErrTOTPRequired) {
+		s.showCodeInput(password)
+		return tea.Batch(s.inputGroup.Init(), tui.ReportInfo("enter authentication code"))
+	}
+	if err != nil {
+		return tui.ReportError(err)
+	}
+
+	return s.completeLogin(token, password)
+}
+
+// SubmitCode отправляет код второго фактора и завершает вход.
+func (s *AuthenticateScreen) SubmitCode() tea.Cmd {
+	code := s.inputGroup.Inputs[posCode].Value()
+	if len(code) == 0 {
+		return tui.ReportError(errors.New("please enter authentication code"))
+	}
+
+	token, err := s.client.VerifyTOTP(context.Background(), code)
+	if err != nil {
+		s.inputGroup.Inputs[posCode].SetValue("")
+		return tui.ReportError(err)
+	}
+
+	return s.completeLogin(token, s.password)
+}
+
+// completeLogin сохраняет токен и пароль в клиенте и открывает хранилище пользователя. Перед открытием
+// расшифровывается ключ хранилища, хранилище при необходимости перешифровывается, а открытые заголовки
+// секретов шифруются.
+func (s *AuthenticateScreen) completeLogin(token, password string) tea.Cmd {
+	var commands []tea.Cmd
+
+	s.client.SetToken(token)
+	s.client.SetPassword(password)
+	s.password = ""
+
+	store, err := storage.NewRemoteStorage(s.client)
 	if err != nil {
 		commands = append(commands, tui.ReportError(err))
+	} else if err = store.UnlockVault(context.Background()); err != nil {
+		commands = append(commands, tui.ReportError(fmt.Errorf("failed to unlock vault: %w", err)))
+		commands = append(commands, tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(store)))
+	} else if err = store.UpgradeKDF(context.Background()); err != nil {
+		commands = append(commands, tui.ReportError(fmt.Errorf("failed to upgrade vault encryption: %w", err)))
+		commands = append(commands, tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(store)))
+	} else if err = store.EncryptLabels(context.Background()); err != nil {
+		commands = append(commands, tui.ReportError(fmt.Errorf("failed to encrypt secret titles: %w", err)))
+		commands = append(commands, tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(store)))
 	} else {
-		s.client.SetToken(token)
-		s.client.SetPassword(password)
-
-		var store storage.Storage
-		store, err = storage.NewRemoteStorage(s.client)
-		if err != nil {
-			commands = append(commands, tui.ReportError(err))
-		} else {
-			commands = append(commands, tui.ReportInfo("success!"))
-			commands = append(commands, tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(store)))
-		}
+		commands = append(commands, tui.ReportInfo("success!"))
+		commands = append(commands, tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(store)))
 	}
 
 	return tea.Batch(commands...)
@@ -135,6 +234,9 @@ func (s *AuthenticateScreen) Submit(mode Mode) tea.Cmd {
 
 // View отображает текущее состояние экрана в виде строки.
 func (s *AuthenticateScreen) View() string {
+	if s.awaitingCode {
+		return screens.RenderContent("Enter authentication code:", s.inputGroup.View())
+	}
 	return screens.RenderContent("Fill in credentials:", s.inputGroup.View())
 }
 
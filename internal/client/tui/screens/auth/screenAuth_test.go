@@ -1,8 +1,11 @@
 package auth
 
 import (
+	"beliaev-aa/GophKeeper/internal/client/crypto"
+	"beliaev-aa/GophKeeper/internal/client/grpc"
 	"beliaev-aa/GophKeeper/internal/client/tui"
 	"beliaev-aa/GophKeeper/internal/client/tui/components"
+	"beliaev-aa/GophKeeper/pkg/models"
 	"beliaev-aa/GophKeeper/tests/mocks"
 	"context"
 	"errors"
@@ -36,6 +39,13 @@ func TestAuthenticateScreen_Submit(t *testing.T) {
 				client.EXPECT().SetToken("test-token").Times(1)
 				client.EXPECT().SetPassword("password").Times(1)
 				client.EXPECT().GetPassword().Return("password").AnyTimes()
+				client.EXPECT().GetEncryptionKey().Return(make([]byte, 32)).AnyTimes()
+				client.EXPECT().GetLogin().Return("user").AnyTimes()
+				client.EXPECT().GetVaultKey(gomock.Any()).Return(wrappedVaultKey(t), nil).AnyTimes()
+				client.EXPECT().GetKeyPair(gomock.Any()).Return(wrappedKeyPair(t), nil).AnyTimes()
+				client.EXPECT().KDFUpgradeRequired().Return(false).AnyTimes()
+				client.EXPECT().LoadSecrets(gomock.Any()).Return([]*models.Secret{{ID: 1, Title: "sealed", LabelsEncrypted: true, DataKey: []byte("data_key")}}, nil).Times(1)
+				client.EXPECT().LoadTrash(gomock.Any()).Return(nil, nil).Times(1)
 			},
 			mode:      modeLogin,
 			login:     "test",
@@ -58,6 +68,8 @@ func TestAuthenticateScreen_Submit(t *testing.T) {
 				client.EXPECT().Register(context.Background(), "test", "password").Return("test-token", nil).Times(1)
 				client.EXPECT().SetToken("test-token").Times(1)
 				client.EXPECT().SetPassword("password").Times(1)
+				client.EXPECT().LoadSecrets(gomock.Any()).Return(nil, nil).Times(1)
+				client.EXPECT().LoadTrash(gomock.Any()).Return(nil, nil).Times(1)
 			},
 			mode:      modeRegister,
 			login:     "test",
@@ -122,6 +134,94 @@ func TestAuthenticateScreen_Submit(t *testing.T) {
 	}
 }
 
+func TestAuthenticateScreen_SubmitCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mocks.NewMockClientGRPCInterface(ctrl)
+	screen := NewLoginScreen(client)
+	screen.inputGroup.Inputs[posLogin].SetValue("test")
+	screen.inputGroup.Inputs[posPassword].SetValue("password")
+
+	client.EXPECT().Login(context.Background(), "test", "password").Return("", grpc.ErrTOTPRequired).Times(1)
+
+	assert.NotNil(t, screen.Submit(modeLogin))
+	assert.True(t, screen.awaitingCode)
+	assert.Len(t, screen.inputGroup.Inputs, 1)
+	assert.Contains(t, screen.View(), "Enter authentication code:")
+	assert.Contains(t, screen.View(), "[ Verify ]")
+
+	t.Run("Empty_Code", func(t *testing.T) {
+		msg := screen.SubmitCode()()
+		assert.EqualError(t, msg.(error), "please enter authentication code")
+	})
+
+	t.Run("Invalid_Code", func(t *testing.T) {
+		screen.inputGroup.Inputs[posCode].SetValue("000000")
+		client.EXPECT().VerifyTOTP(context.Background(), "000000").Return("", errors.New("failed to authenticate")).Times(1)
+
+		msg := screen.SubmitCode()()
+
+		assert.EqualError(t, msg.(error), "failed to authenticate")
+		assert.Empty(t, screen.inputGroup.Inputs[posCode].Value())
+		assert.True(t, screen.awaitingCode)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		screen.inputGroup.Inputs[posCode].SetValue("123456")
+		client.EXPECT().VerifyTOTP(context.Background(), "123456").Return("test-token", nil).Times(1)
+		client.EXPECT().SetToken("test-token").Times(1)
+		client.EXPECT().SetPassword("password").Times(1)
+		client.EXPECT().GetPassword().Return("password").AnyTimes()
+		client.EXPECT().GetEncryptionKey().Return(make([]byte, 32)).AnyTimes()
+		client.EXPECT().GetLogin().Return("user").AnyTimes()
+		client.EXPECT().GetVaultKey(gomock.Any()).Return(wrappedVaultKey(t), nil).AnyTimes()
+		client.EXPECT().GetKeyPair(gomock.Any()).Return(wrappedKeyPair(t), nil).AnyTimes()
+		client.EXPECT().KDFUpgradeRequired().Return(false).AnyTimes()
+		client.EXPECT().LoadSecrets(gomock.Any()).Return(nil, nil).Times(1)
+		client.EXPECT().LoadTrash(gomock.Any()).Return(nil, nil).Times(1)
+
+		assert.NotNil(t, screen.SubmitCode())
+		assert.Empty(t, screen.password)
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		screen.showCodeInput("password")
+
+		screen.inputGroup.Buttons[1].Cmd()
+
+		assert.False(t, screen.awaitingCode)
+		assert.Empty(t, screen.password)
+		assert.Contains(t, screen.View(), "Fill in credentials:")
+	})
+}
+
+func TestAuthenticateScreen_LegacyMigration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mocks.NewMockClientGRPCInterface(ctrl)
+	screen := NewLoginScreen(client)
+	screen.inputGroup.Inputs[posLogin].SetValue("test")
+	screen.inputGroup.Inputs[posPassword].SetValue("password")
+
+	client.EXPECT().Login(context.Background(), "test", "password").Return("", grpc.ErrLegacyMigrationRequired).Times(1)
+
+	// Без согласия пользователя мастер-пароль серверу не передаётся.
+	prompt, ok := screen.Submit(modeLogin)().(tui.PromptMsg)
+	if !ok {
+		t.Fatal("Expected consent prompt")
+	}
+	assert.Contains(t, prompt.Prompt, "send the master password to the server once")
+
+	client.EXPECT().MigrateLegacyLogin(context.Background(), "test", "password").Return("", grpc.ErrTOTPRequired).Times(1)
+	consent := prompt.Action("y")()
+
+	assert.NotNil(t, screen.Update(consent))
+	assert.True(t, screen.awaitingCode)
+	assert.Equal(t, "password", screen.password)
+}
+
 func TestAuthenticateScreen_View(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -284,3 +384,25 @@ func TestAuthenticateScreen_Make(t *testing.T) {
 	_, ok := result.(*AuthenticateScreen)
 	assert.True(t, ok, "Expected result to be of type *AuthenticateScreen")
 }
+
+// wrappedVaultKey возвращает ключ хранилища пользователя "user", зашифрованный нулевым ключом мастер-пароля.
+func wrappedVaultKey(t *testing.T) []byte {
+	wrapped, err := crypto.WrapKey(make([]byte, 32), make([]byte, 32), crypto.VaultKeyAAD("user"))
+	if err != nil {
+		t.Fatalf("Failed to wrap vault key: %v", err)
+	}
+	return wrapped
+}
+
+// wrappedKeyPair возвращает пару ключей пользователя "user" с закрытым ключом, зашифрованным нулевым ключом хранилища.
+func wrappedKeyPair(t *testing.T) *models.KeyPair {
+	publicKey, privateKey, err := crypto.NewKeyPair()
+	if err != nil {
+		t.Fatalf("Failed to generate key pair: %v", err)
+	}
+	wrapped, err := crypto.WrapKey(privateKey, make([]byte, 32), crypto.PrivateKeyAAD("user"))
+	if err != nil {
+		t.Fatalf("Failed to wrap private key: %v", err)
+	}
+	return &models.KeyPair{PublicKey: publicKey, PrivateKey: wrapped}
+}
@@ -7,13 +7,14 @@ import (
 	"beliaev-aa/GophKeeper/internal/client/tui/components"
 	"beliaev-aa/GophKeeper/internal/client/tui/screens"
 	"beliaev-aa/GophKeeper/internal/client/tui/styles"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
 	"beliaev-aa/GophKeeper/pkg/models"
 	"context"
 	"errors"
 	"fmt"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbletea"
-	"os"
+	"path/filepath"
 	"time"
 )
 
@@ -60,17 +61,18 @@ func NewBlobEditScreen(secret *models.Secret, store storage.Storage) *BlobEditSc
 		}
 
 		f := func(args ...any) tea.Cmd {
-			str, ok := args[0].(string)
+			path, ok := args[0].(string)
 			if !ok {
 				return tui.ReportError(fmt.Errorf("error opening file"))
 			}
 
-			err := m.Submit(str)
-			if err != nil {
-				return tui.ReportError(fmt.Errorf("error uploading file: %w", err))
-			}
-
-			return tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(m.storage))
+			return tui.SetBodyPane(tui.TransferScreen, tui.WithTransfer(&tui.Transfer{
+				Title: fmt.Sprintf("Uploading %s", filepath.Base(path)),
+				Run: func(ctx context.Context, progress func(done, total int64)) error {
+					return m.Submit(ctx, path, progress)
+				},
+				Done: m.uploadDone,
+			}))
 		}
 
 		return tui.SetBodyPane(tui.FilePickScreen, tui.WithStorage(m.storage), tui.WithCallback(f), tui.WithSecret(secret))
@@ -80,10 +82,9 @@ func NewBlobEditScreen(secret *models.Secret, store storage.Storage) *BlobEditSc
 		return tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(m.storage))
 	}})
 
-	if secret.ID > 0 {
-		inputs[blobTitle].SetValue(secret.Title)
-		inputs[blobMetadata].SetValue(secret.Metadata)
-	}
+	// Поля заполняются и для нового секрета, если экран открыт повторно после неудачной загрузки файла.
+	inputs[blobTitle].SetValue(secret.Title)
+	inputs[blobMetadata].SetValue(secret.Metadata)
 
 	m.inputGroup = components.NewInputGroup(inputs, buttons)
 
@@ -131,47 +132,46 @@ func (s *BlobEditScreen) validateInputs() error {
 	return nil
 }
 
-// Submit отправляет данные на сервер после проверки валидности.
-func (s *BlobEditScreen) Submit(path string) error {
-	var (
-		err error
-	)
-
-	err = s.validateInputs()
+// Submit загружает файл на сервер по фрагментам, сообщая о ходе загрузки через progress,
+// и сохраняет секрет со ссылкой на загруженный файл.
+func (s *BlobEditScreen) Submit(ctx context.Context, path string, progress func(done, total int64)) error {
+	err := s.validateInputs()
 	if err != nil {
 		return err
 	}
 
-	bts, err := readFileToBytes(path)
+	s.secret.Title = s.inputGroup.Inputs[blobTitle].Value()
+	s.secret.Metadata = s.inputGroup.Inputs[blobMetadata].Value()
+
+	err = s.storage.UploadFile(ctx, s.secret, path, progress)
 	if err != nil {
 		return err
 	}
 
-	s.secret.Title = s.inputGroup.Inputs[blobTitle].Value()
-	s.secret.Metadata = s.inputGroup.Inputs[blobMetadata].Value()
-	s.secret.Blob = &models.Blob{
-		FileName:  path,
-		FileBytes: bts,
-	}
 	s.secret.UpdatedAt = time.Now()
 
 	if s.secret.ID == 0 {
 		s.secret.CreatedAt = time.Now()
-		err = s.storage.Create(context.Background(), s.secret)
+		err = s.storage.Create(ctx, s.secret)
 	} else {
-		err = s.storage.Update(context.Background(), s.secret)
+		err = s.storage.Update(ctx, s.secret)
 	}
 
 	return err
 }
 
-// Читает файл по указанному пути и возвращает его содержимое в виде байтового массива.
-func readFileToBytes(filePath string) ([]byte, error) {
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, err
+// uploadDone возвращает команду по результату загрузки файла. При ошибке загрузки
+// возвращает к редактированию секрета, чтобы загрузку можно было повторить.
+func (s *BlobEditScreen) uploadDone(err error) tea.Cmd {
+	var conflict *gophKeeperErrors.RevisionConflictError
+	if err == nil || errors.As(err, &conflict) {
+		return tui.SaveResult(err, s.secret, s.storage)
 	}
-	return data, nil
+
+	return tea.Batch(
+		tui.SetBodyPane(tui.BlobEditScreen, tui.WithSecret(s.secret), tui.WithStorage(s.storage)),
+		tui.ReportError(fmt.Errorf("error uploading file: %w", err)),
+	)
 }
 
 // Создаёт новую модель ввода текста с заданными параметрами.
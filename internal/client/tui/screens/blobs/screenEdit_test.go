@@ -2,12 +2,14 @@ package blobs
 
 import (
 	"beliaev-aa/GophKeeper/internal/client/tui"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
 	"beliaev-aa/GophKeeper/pkg/models"
 	"beliaev-aa/GophKeeper/tests/mocks"
+	"context"
+	"errors"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
-	"os"
 	"testing"
 )
 
@@ -24,11 +26,16 @@ func TestBlobEditScreen_Submit(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockStorage := mocks.NewMockStorage(ctrl)
+	uploaded := func(_ context.Context, secret *models.Secret, path string, _ func(done, total int64)) error {
+		secret.Blob = &models.Blob{FileName: path, BlobID: "blob"}
+		return nil
+	}
 
 	tests := []testCase{
 		{
 			name: "Submit_Success_Create",
 			setupMock: func(mockStorage *mocks.MockStorage) {
+				mockStorage.EXPECT().UploadFile(gomock.Any(), gomock.Any(), "test_file.txt", gomock.Any()).DoAndReturn(uploaded).Times(1)
 				mockStorage.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 			},
 			inputs: func(screen *BlobEditScreen) {
@@ -41,6 +48,7 @@ func TestBlobEditScreen_Submit(t *testing.T) {
 		{
 			name: "Submit_Success_Update",
 			setupMock: func(mockStorage *mocks.MockStorage) {
+				mockStorage.EXPECT().UploadFile(gomock.Any(), gomock.Any(), "test_file.txt", gomock.Any()).DoAndReturn(uploaded).Times(1)
 				mockStorage.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 			},
 			inputs: func(screen *BlobEditScreen) {
@@ -72,25 +80,21 @@ func TestBlobEditScreen_Submit(t *testing.T) {
 			expectErr: "please enter metadata",
 		},
 		{
-			name:      "Submit_ReadFile_Error",
-			setupMock: func(mockStorage *mocks.MockStorage) {},
+			name: "Submit_Upload_Error",
+			setupMock: func(mockStorage *mocks.MockStorage) {
+				mockStorage.EXPECT().UploadFile(gomock.Any(), gomock.Any(), "nonexistent_file.txt", gomock.Any()).Return(errors.New("no such file or directory")).Times(1)
+			},
 			inputs: func(screen *BlobEditScreen) {
 				screen.inputGroup.Inputs[blobTitle].SetValue("Test Title")
 				screen.inputGroup.Inputs[blobMetadata].SetValue("Test Metadata")
 			},
 			filePath:  "nonexistent_file.txt",
-			expectErr: "open nonexistent_file.txt: no such file or directory",
+			expectErr: "no such file or directory",
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			// Создаем временный файл
-			content := []byte("test content")
-			err := os.WriteFile("test_file.txt", content, 0644)
-			assert.NoError(t, err)
-			defer os.Remove("test_file.txt")
-
 			secret := &models.Secret{}
 			screen := NewBlobEditScreen(secret, mockStorage)
 
@@ -101,16 +105,46 @@ func TestBlobEditScreen_Submit(t *testing.T) {
 				tc.setupMock(mockStorage)
 			}
 
-			err = screen.Submit(tc.filePath)
+			err := screen.Submit(context.Background(), tc.filePath, nil)
 			if tc.expectErr != "" {
 				assert.EqualError(t, err, tc.expectErr)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, "blob", secret.Blob.BlobID)
 			}
 		})
 	}
 }
 
+func TestBlobEditScreen_UploadDone(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedScreen tui.Screen
+	}{
+		{name: "Success", err: nil, expectedScreen: tui.StorageBrowseScreen},
+		{name: "Revision_conflict", err: &gophKeeperErrors.RevisionConflictError{Current: 2}, expectedScreen: tui.SecretConflictScreen},
+		{name: "Upload_error", err: errors.New("connection lost"), expectedScreen: tui.BlobEditScreen},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			screen := NewBlobEditScreen(&models.Secret{Title: "File"}, nil)
+
+			msg := screen.uploadDone(tc.err)()
+			if batch, ok := msg.(tea.BatchMsg); ok {
+				msg = batch[0]()
+			}
+
+			nav, ok := msg.(tui.NavigationMsg)
+			if !ok {
+				t.Fatalf("expected NavigationMsg, got %T", msg)
+			}
+			assert.Equal(t, tc.expectedScreen, nav.Page.Screen)
+		})
+	}
+}
+
 func TestBlobEditScreen_ValidateInputs(t *testing.T) {
 	tests := []testCase{
 		{
@@ -163,25 +197,6 @@ func TestBlobEditScreen_View(t *testing.T) {
 	assert.Contains(t, view, "Fill in file details:")
 }
 
-func TestReadFileToBytes(t *testing.T) {
-	t.Run("ReadFile_Success", func(t *testing.T) {
-		content := []byte("test content")
-		err := os.WriteFile("test_file.txt", content, 0644)
-		assert.NoError(t, err)
-		defer os.Remove("test_file.txt")
-
-		data, err := readFileToBytes("test_file.txt")
-		assert.NoError(t, err)
-		assert.Equal(t, content, data)
-	})
-
-	t.Run("ReadFile_Error", func(t *testing.T) {
-		_, err := readFileToBytes("nonexistent_file.txt")
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "no such file or directory")
-	})
-}
-
 func TestBlobEditScreen_Init(t *testing.T) {
 	screen := NewBlobEditScreen(&models.Secret{}, nil)
 	cmd := screen.Init()
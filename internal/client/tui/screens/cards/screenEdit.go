@@ -62,12 +62,7 @@ func NewCardEditScreen(secret *models.Secret, store storage.Storage) *CardEditSc
 
 	var buttons []components.Button
 	buttons = append(buttons, components.Button{Title: "[ Submit ]", Cmd: func() tea.Cmd {
-		err := m.Submit()
-		if err != nil {
-			return tui.ReportError(err)
-		} else {
-			return tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(m.storage))
-		}
+		return tui.SaveResult(m.Submit(), m.secret, m.storage)
 	}})
 
 	buttons = append(buttons, components.Button{Title: "[ Back ]", Cmd: func() tea.Cmd {
@@ -0,0 +1,129 @@
+// Package conflict предоставляет экран разрешения конфликта, возникающего, когда секрет
+// был изменён на другом устройстве, пока пользователь его редактировал.
+package conflict
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/storage"
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/internal/client/tui/components"
+	"beliaev-aa/GophKeeper/internal/client/tui/screens"
+	"beliaev-aa/GophKeeper/internal/client/tui/styles"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"fmt"
+	"github.com/charmbracelet/bubbletea"
+	"strings"
+	"time"
+)
+
+// SecretConflictScreen предоставляет модель экрана разрешения конфликта ревизий.
+// Экран сравнивает локальную версию секрета с версией на сервере и предлагает
+// сохранить свою версию, принять серверную или сохранить свою как копию.
+type SecretConflictScreen struct {
+	err        error
+	inputGroup components.InputGroup
+	mine       *models.Secret
+	storage    storage.Storage
+	theirs     *models.Secret
+}
+
+// Make создает экран конфликта для секрета, переданного в сообщении навигации.
+func (s *SecretConflictScreen) Make(msg tui.NavigationMsg, _, _ int) (tui.TeaLike, error) {
+	return NewSecretConflictScreen(msg.Secret, msg.Storage), nil
+}
+
+// NewSecretConflictScreen создает экран конфликта и загружает текущую версию секрета с сервера.
+func NewSecretConflictScreen(mine *models.Secret, store storage.Storage) *SecretConflictScreen {
+	scr := &SecretConflictScreen{
+		mine:    mine,
+		storage: store,
+	}
+
+	scr.theirs, scr.err = store.Get(context.Background(), mine.ID)
+
+	buttons := []components.Button{
+		{Title: "[ Keep mine ]", Cmd: scr.keepMine},
+		{Title: "[ Take theirs ]", Cmd: scr.takeTheirs},
+		{Title: "[ Save as copy ]", Cmd: scr.saveAsCopy},
+	}
+
+	scr.inputGroup = components.NewInputGroup(nil, buttons)
+
+	return scr
+}
+
+// Init инициализирует экран и сообщает об ошибке загрузки серверной версии.
+func (s *SecretConflictScreen) Init() tea.Cmd {
+	if s.err != nil {
+		return tui.ReportError(fmt.Errorf("failed to load server version: %w", s.err))
+	}
+	return nil
+}
+
+// Update обновляет состояние экрана в ответ на сообщения.
+func (s *SecretConflictScreen) Update(msg tea.Msg) tea.Cmd {
+	ig, cmd := s.inputGroup.Update(msg)
+	s.inputGroup = ig.(components.InputGroup)
+
+	return cmd
+}
+
+// View отображает сравнение локальной и серверной версий секрета.
+func (s *SecretConflictScreen) View() string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("%s was modified on another device.\n\n", styles.Highlighted.Render(s.mine.Title)))
+	writeVersion(&b, "Mine", s.mine)
+	if s.theirs != nil {
+		writeVersion(&b, "Server", s.theirs)
+	}
+	b.WriteString(s.inputGroup.View())
+
+	return screens.RenderContent("Resolve conflict:", b.String())
+}
+
+// keepMine перезаписывает серверную версию локальной, используя актуальную ревизию.
+func (s *SecretConflictScreen) keepMine() tea.Cmd {
+	if s.theirs == nil {
+		return tui.ReportError(fmt.Errorf("server version is not loaded"))
+	}
+
+	s.mine.Revision = s.theirs.Revision
+	s.mine.UpdatedAt = time.Now()
+
+	return tui.SaveResult(s.storage.Update(context.Background(), s.mine), s.mine, s.storage)
+}
+
+// takeTheirs отбрасывает локальные изменения.
+func (s *SecretConflictScreen) takeTheirs() tea.Cmd {
+	return tea.Batch(
+		tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(s.storage)),
+		tui.ReportInfo("local changes to %s discarded", s.mine.Title),
+	)
+}
+
+// saveAsCopy сохраняет локальную версию как новый секрет, оставляя серверную без изменений.
+func (s *SecretConflictScreen) saveAsCopy() tea.Cmd {
+	now := time.Now()
+
+	cp := *s.mine
+	cp.ID = 0
+	cp.Revision = 0
+	cp.Title = s.mine.Title + " (copy)"
+	cp.CreatedAt = now
+	cp.UpdatedAt = now
+
+	return tui.SaveResult(s.storage.Create(context.Background(), &cp), &cp, s.storage)
+}
+
+func writeVersion(b *strings.Builder, label string, secret *models.Secret) {
+	b.WriteString(styles.Highlighted.Render(label))
+	b.WriteRune('\n')
+	b.WriteString(fmt.Sprintf("  Title: %s\n", secret.Title))
+	b.WriteString(fmt.Sprintf("  Metadata: %s\n", secret.Metadata))
+	b.WriteString(fmt.Sprintf("  Updated: %s\n", secret.UpdatedAt.Format("02 Jan 06 15:04")))
+	for _, line := range strings.Split(strings.TrimRight(secret.ToClipboard(), "\n"), "\n") {
+		b.WriteString(fmt.Sprintf("  %s\n", line))
+	}
+	b.WriteRune('\n')
+}
@@ -0,0 +1,246 @@
+package conflict
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"errors"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/golang/mock/gomock"
+	"strings"
+	"testing"
+)
+
+const (
+	keepMineButton = iota
+	takeTheirsButton
+	saveAsCopyButton
+)
+
+func testMine() *models.Secret {
+	return &models.Secret{
+		ID:         7,
+		Title:      "Note",
+		Metadata:   "local",
+		SecretType: string(models.TextSecret),
+		Text:       &models.Text{Content: "mine"},
+		Revision:   1,
+	}
+}
+
+func testTheirs() *models.Secret {
+	return &models.Secret{
+		ID:         7,
+		Title:      "Note",
+		Metadata:   "remote",
+		SecretType: string(models.TextSecret),
+		Text:       &models.Text{Content: "theirs"},
+		Revision:   3,
+	}
+}
+
+// collectMsgs выполняет команду и возвращает все сообщения, включая сообщения вложенных пакетов команд.
+func collectMsgs(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, c := range batch {
+			msgs = append(msgs, collectMsgs(c)...)
+		}
+		return msgs
+	}
+
+	return []tea.Msg{msg}
+}
+
+func Test_SecretConflictScreen_Make(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().Get(gomock.Any(), uint64(7)).Return(testTheirs(), nil)
+
+	maker := &SecretConflictScreen{}
+	result, err := maker.Make(tui.NavigationMsg{Secret: testMine(), Storage: mockStorage}, 0, 0)
+	if err != nil {
+		t.Errorf("Make returned an error: %v", err)
+	}
+
+	screen, ok := result.(*SecretConflictScreen)
+	if !ok {
+		t.Fatalf("Expected result to be *SecretConflictScreen, got %T", result)
+	}
+	if screen.theirs == nil || screen.theirs.Revision != 3 {
+		t.Errorf("Expected server version to be loaded, got %v", screen.theirs)
+	}
+	if cmd := screen.Init(); cmd != nil {
+		t.Errorf("Expected no Init command, got %v", cmd())
+	}
+}
+
+func Test_SecretConflictScreen_Init(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().Get(gomock.Any(), uint64(7)).Return(nil, errors.New("not found"))
+
+	screen := NewSecretConflictScreen(testMine(), mockStorage)
+	cmd := screen.Init()
+	if cmd == nil {
+		t.Fatal("Expected Init to report the load error")
+	}
+	if msg, ok := cmd().(tui.ErrorMsg); !ok || !strings.Contains(msg.Error(), "not found") {
+		t.Errorf("Expected error message, got %#v", cmd())
+	}
+}
+
+func Test_SecretConflictScreen_View(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().Get(gomock.Any(), uint64(7)).Return(testTheirs(), nil)
+
+	view := NewSecretConflictScreen(testMine(), mockStorage).View()
+	for _, want := range []string{"Text: mine", "Text: theirs", "Keep mine", "Take theirs", "Save as copy"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View does not contain %q, got %q", want, view)
+		}
+	}
+}
+
+func Test_SecretConflictScreen_Buttons(t *testing.T) {
+	tests := []struct {
+		name          string
+		button        int
+		theirs        *models.Secret
+		setupMock     func(mockStorage *mocks.MockStorage)
+		expectScreen  tui.Screen
+		expectMessage string
+	}{
+		{
+			name:   "KeepMine_Success",
+			button: keepMineButton,
+			theirs: testTheirs(),
+			setupMock: func(mockStorage *mocks.MockStorage) {
+				mockStorage.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, secret *models.Secret) error {
+					if secret.Revision != 3 || secret.Text.Content != "mine" {
+						t.Errorf("Unexpected secret saved: %+v", secret)
+					}
+					return nil
+				})
+			},
+			expectScreen: tui.StorageBrowseScreen,
+		},
+		{
+			name:   "KeepMine_ConflictAgain",
+			button: keepMineButton,
+			theirs: testTheirs(),
+			setupMock: func(mockStorage *mocks.MockStorage) {
+				mockStorage.EXPECT().Update(gomock.Any(), gomock.Any()).Return(&gophKeeperErrors.RevisionConflictError{Current: 4})
+			},
+			expectScreen: tui.SecretConflictScreen,
+		},
+		{
+			name:          "KeepMine_ServerVersionMissing",
+			button:        keepMineButton,
+			setupMock:     func(_ *mocks.MockStorage) {},
+			expectMessage: "server version is not loaded",
+		},
+		{
+			name:          "TakeTheirs",
+			button:        takeTheirsButton,
+			theirs:        testTheirs(),
+			setupMock:     func(_ *mocks.MockStorage) {},
+			expectScreen:  tui.StorageBrowseScreen,
+			expectMessage: "local changes to Note discarded",
+		},
+		{
+			name:   "SaveAsCopy_Success",
+			button: saveAsCopyButton,
+			theirs: testTheirs(),
+			setupMock: func(mockStorage *mocks.MockStorage) {
+				mockStorage.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, secret *models.Secret) error {
+					if secret.ID != 0 || secret.Revision != 0 || secret.Title != "Note (copy)" {
+						t.Errorf("Unexpected copy saved: %+v", secret)
+					}
+					return nil
+				})
+			},
+			expectScreen: tui.StorageBrowseScreen,
+		},
+		{
+			name:   "SaveAsCopy_Fail",
+			button: saveAsCopyButton,
+			theirs: testTheirs(),
+			setupMock: func(mockStorage *mocks.MockStorage) {
+				mockStorage.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("network error"))
+			},
+			expectMessage: "network error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := mocks.NewMockStorage(ctrl)
+			if tt.theirs != nil {
+				mockStorage.EXPECT().Get(gomock.Any(), uint64(7)).Return(tt.theirs, nil)
+			} else {
+				mockStorage.EXPECT().Get(gomock.Any(), uint64(7)).Return(nil, errors.New("not found"))
+			}
+			tt.setupMock(mockStorage)
+
+			screen := NewSecretConflictScreen(testMine(), mockStorage)
+			msgs := collectMsgs(screen.inputGroup.Buttons[tt.button].Cmd())
+
+			var (
+				navigated bool
+				texts     []string
+			)
+			for _, msg := range msgs {
+				switch m := msg.(type) {
+				case tui.NavigationMsg:
+					navigated = true
+					if m.Page.Screen != tt.expectScreen {
+						t.Errorf("Expected screen %v, got %v", tt.expectScreen, m.Page.Screen)
+					}
+				case tui.ErrorMsg:
+					texts = append(texts, m.Error())
+				case tui.InfoMsg:
+					texts = append(texts, string(m))
+				}
+			}
+
+			if tt.expectMessage == "" && !navigated {
+				t.Errorf("Expected navigation, got %v", msgs)
+			}
+			if tt.expectMessage != "" && !strings.Contains(strings.Join(texts, "\n"), tt.expectMessage) {
+				t.Errorf("Expected message %q, got %v", tt.expectMessage, texts)
+			}
+		})
+	}
+}
+
+func Test_SecretConflictScreen_Update(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().Get(gomock.Any(), uint64(7)).Return(testTheirs(), nil)
+
+	screen := NewSecretConflictScreen(testMine(), mockStorage)
+	screen.Update(tea.KeyMsg{Type: tea.KeyDown})
+
+	if screen.inputGroup.FocusIndex != takeTheirsButton {
+		t.Errorf("Expected focus on button %d, got %d", takeTheirsButton, screen.inputGroup.FocusIndex)
+	}
+}
@@ -55,10 +55,7 @@ func NewCredentialEditScreen(secret *models.Secret, store storage.Storage) *Cred
 
 	var buttons []components.Button
 	buttons = append(buttons, components.Button{Title: "[ Submit ]", Cmd: func() tea.Cmd {
-		if err := m.Submit(); err != nil {
-			return tui.ReportError(err)
-		}
-		return tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(m.storage))
+		return tui.SaveResult(m.Submit(), m.secret, m.storage)
 	}})
 
 	buttons = append(buttons, components.Button{Title: "[ Back ]", Cmd: func() tea.Cmd {
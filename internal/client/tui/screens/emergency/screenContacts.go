@@ -0,0 +1,258 @@
+// Package emergency предоставляет экраны экстренного доступа: доверенных пользователей, которые могут
+// запросить доступ к хранилищу пользователя, и хранилищ других пользователей, доступ к которым может
+// запросить сам пользователь.
+package emergency
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/grpc"
+	"beliaev-aa/GophKeeper/internal/client/storage"
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/internal/client/tui/styles"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbletea"
+	"strconv"
+	"strings"
+)
+
+const (
+	tableBorderSize = 4
+)
+
+// accessChangedMsg сообщает об изменении экстренного доступа пользователем.
+type accessChangedMsg struct {
+	info string
+}
+
+// EmergencyContactsScreen предоставляет модель экрана доверенных пользователей, которые могут запросить
+// экстренный доступ к хранилищу пользователя. Запрос доступа можно отклонить до истечения срока ожидания.
+type EmergencyContactsScreen struct {
+	err      error
+	accesses []*models.EmergencyAccess
+	storage  storage.Storage
+	table    table.Model
+}
+
+// Make создает экран доверенных пользователей для хранилища, переданного в сообщении навигации.
+func (s *EmergencyContactsScreen) Make(msg tui.NavigationMsg, _, _ int) (tui.TeaLike, error) {
+	return NewEmergencyContactsScreen(msg.Storage), nil
+}
+
+// NewEmergencyContactsScreen создает новый экран доверенных пользователей и загружает их.
+func NewEmergencyContactsScreen(store storage.Storage) *EmergencyContactsScreen {
+	scr := &EmergencyContactsScreen{
+		storage: store,
+		table: newTable([]table.Column{
+			{Title: "id", Width: 5},
+			{Title: "Trusted user", Width: 25},
+			{Title: "Wait", Width: 8},
+			{Title: "Status", Width: 35},
+		}),
+	}
+
+	scr.updateRows()
+
+	return scr
+}
+
+// Init инициализирует экран и сообщает об ошибке загрузки доверенных пользователей.
+func (s *EmergencyContactsScreen) Init() tea.Cmd {
+	if s.err != nil {
+		return tui.ReportError(fmt.Errorf("failed to load trusted users: %w", s.err))
+	}
+	return nil
+}
+
+// Update обновляет состояние экрана в ответ на сообщения.
+func (s *EmergencyContactsScreen) Update(msg tea.Msg) tea.Cmd {
+	var (
+		cmd      tea.Cmd
+		commands []tea.Cmd
+	)
+
+	switch msg := msg.(type) {
+	case grpc.EmergencyAccessChanged:
+		s.updateRows()
+	case accessChangedMsg:
+		s.updateRows()
+		commands = append(commands, tui.ReportInfo("%s", msg.info))
+	case tea.WindowSizeMsg:
+		s.table.SetWidth(min(msg.Width, colsWidth(s.table)))
+		s.table.SetHeight(max(msg.Height-tableBorderSize, 1))
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "a":
+			commands = append(commands, s.handleAdd())
+		case "r":
+			commands = append(commands, s.handleReject())
+		case "x":
+			commands = append(commands, s.handleDelete())
+		case "g":
+			commands = append(commands, tui.SetBodyPane(tui.EmergencyGrantsScreen, tui.WithStorage(s.storage)))
+		case "b":
+			commands = append(commands, tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(personal(s.storage))))
+		}
+	}
+
+	s.table.Focus()
+	s.table, cmd = s.table.Update(msg)
+	commands = append(commands, cmd)
+
+	return tea.Batch(commands...)
+}
+
+// View отображает список доверенных пользователей.
+func (s *EmergencyContactsScreen) View() string {
+	var b strings.Builder
+
+	b.WriteString("Emergency access: trusted users of your vault\n")
+	b.WriteString("Use ↑↓ to navigate, add[a], reject request[r], remove[x], vaults you can access[g], back[b]\n")
+
+	if len(s.accesses) == 0 {
+		b.WriteString("\nNobody can request emergency access to your vault\n")
+		return styles.StorageScreenStyle.Render(b.String())
+	}
+
+	b.WriteString(styles.TableStyle.Render(s.table.View()))
+
+	return styles.StorageScreenStyle.Render(b.String())
+}
+
+// HelpBindings возвращает набор горячих клавиш для экрана.
+func (s *EmergencyContactsScreen) HelpBindings() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "add trusted user")),
+		key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "reject request")),
+		key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "remove trusted user")),
+		key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "vaults you can access")),
+		key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "back")),
+	}
+}
+
+func (s *EmergencyContactsScreen) updateRows() {
+	s.accesses, s.err = s.storage.GetEmergencyContacts(context.Background())
+
+	var rows []table.Row
+	for _, access := range s.accesses {
+		rows = append(rows, table.Row{
+			strconv.FormatUint(access.ID, 10),
+			access.GranteeLogin,
+			waitText(access),
+			statusText(access),
+		})
+	}
+
+	s.table.SetRows(rows)
+}
+
+func (s *EmergencyContactsScreen) handleAdd() tea.Cmd {
+	return tui.StringPrompt("login of the trusted user", func(login string) tea.Cmd {
+		return tui.StringPrompt("days to reject a request", func(str string) tea.Cmd {
+			return func() tea.Msg {
+				waitDays, err := parseWaitDays(str)
+				if err != nil {
+					return tui.ErrorMsg(err)
+				}
+				if err = s.storage.AddEmergencyContact(context.Background(), login, waitDays); err != nil {
+					return tui.ErrorMsg(fmt.Errorf("failed to add trusted user: %w", err))
+				}
+				return accessChangedMsg{info: fmt.Sprintf("%s can request access after %d days of waiting", strings.TrimSpace(login), waitDays)}
+			}
+		})
+	})
+}
+
+func (s *EmergencyContactsScreen) handleReject() tea.Cmd {
+	access := selectedAccess(s.table, s.accesses)
+	if access == nil {
+		return tui.ReportError(fmt.Errorf("no trusted user selected"))
+	}
+
+	return tui.YesNoPrompt(fmt.Sprintf("reject emergency access of %s?", access.GranteeLogin), func() tea.Msg {
+		if err := s.storage.RejectEmergencyAccess(context.Background(), access.ID); err != nil {
+			return tui.ErrorMsg(fmt.Errorf("failed to reject access: %w", err))
+		}
+		return accessChangedMsg{info: fmt.Sprintf("emergency access of %s rejected", access.GranteeLogin)}
+	})
+}
+
+func (s *EmergencyContactsScreen) handleDelete() tea.Cmd {
+	access := selectedAccess(s.table, s.accesses)
+	if access == nil {
+		return tui.ReportError(fmt.Errorf("no trusted user selected"))
+	}
+
+	return tui.YesNoPrompt(fmt.Sprintf("remove %s from trusted users?", access.GranteeLogin), func() tea.Msg {
+		if err := s.storage.DeleteEmergencyAccess(context.Background(), access.ID); err != nil {
+			return tui.ErrorMsg(fmt.Errorf("failed to remove trusted user: %w", err))
+		}
+		return accessChangedMsg{info: fmt.Sprintf("%s removed from trusted users", access.GranteeLogin)}
+	})
+}
+
+// personal возвращает личное хранилище пользователя.
+func personal(store storage.Storage) storage.Storage {
+	if emergencyStore, ok := store.(storage.EmergencyStorage); ok {
+		return emergencyStore.Personal()
+	}
+	return store
+}
+
+// selectedAccess возвращает экстренный доступ, выбранный в таблице, или nil, если таблица пуста.
+func selectedAccess(t table.Model, accesses []*models.EmergencyAccess) *models.EmergencyAccess {
+	cursor := t.Cursor()
+	if cursor < 0 || cursor >= len(accesses) {
+		return nil
+	}
+	return accesses[cursor]
+}
+
+// parseWaitDays преобразует введённый срок ожидания в количество дней.
+func parseWaitDays(str string) (int, error) {
+	waitDays, err := strconv.Atoi(strings.TrimSpace(str))
+	if err != nil || waitDays < 1 {
+		return 0, fmt.Errorf("invalid number of days %q", str)
+	}
+	return waitDays, nil
+}
+
+// waitText возвращает срок ожидания экстренного доступа для отображения в таблице.
+func waitText(access *models.EmergencyAccess) string {
+	return fmt.Sprintf("%d days", access.WaitDays)
+}
+
+// statusText возвращает состояние экстренного доступа для отображения в таблице: для запрошенного
+// доступа добавляется время, когда он будет предоставлен.
+func statusText(access *models.EmergencyAccess) string {
+	if access.Status == models.EmergencyRequested {
+		return fmt.Sprintf("requested, granted %s", access.GrantsAt().Local().Format("02 Jan 06 15:04"))
+	}
+	return string(access.Status)
+}
+
+func colsWidth(t table.Model) int {
+	total := tableBorderSize
+	for _, c := range t.Columns() {
+		total += c.Width
+	}
+
+	return total
+}
+
+// newTable создаёт таблицу с колонками columns в стиле приложения.
+func newTable(columns []table.Column) table.Model {
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithFocused(true),
+	)
+
+	st := table.DefaultStyles()
+	st.Header = styles.TableHeaderStyle
+	st.Selected = styles.TableSelectedStyle
+	t.SetStyles(st)
+
+	return t
+}
@@ -0,0 +1,244 @@
+package emergency
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/grpc"
+	"beliaev-aa/GophKeeper/internal/client/storage"
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"errors"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/golang/mock/gomock"
+	"strings"
+	"testing"
+	"time"
+)
+
+// emergencyStorage дополняет мок хранилища открытием хранилищ по экстренному доступу.
+type emergencyStorage struct {
+	*mocks.MockStorage
+	personal storage.Storage
+	opened   *models.EmergencyAccess
+	openErr  error
+}
+
+func (s *emergencyStorage) Personal() storage.Storage {
+	return s.personal
+}
+
+func (s *emergencyStorage) OpenEmergencyVault(access *models.EmergencyAccess) (storage.Storage, error) {
+	s.opened = access
+	return s.MockStorage, s.openErr
+}
+
+func testContacts() []*models.EmergencyAccess {
+	requestedAt := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	return []*models.EmergencyAccess{
+		{ID: 5, GranteeLogin: "bob", WaitDays: 7, Status: models.EmergencyRequested, RequestedAt: &requestedAt},
+		{ID: 6, GranteeLogin: "carol", WaitDays: 3, Status: models.EmergencyIdle},
+	}
+}
+
+func Test_EmergencyContactsScreen_Make(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().GetEmergencyContacts(gomock.Any()).Return(testContacts(), nil)
+
+	maker := &EmergencyContactsScreen{}
+	result, err := maker.Make(tui.NavigationMsg{Storage: mockStorage}, 0, 0)
+	if err != nil {
+		t.Errorf("Make returned an error: %v", err)
+	}
+
+	screen, ok := result.(*EmergencyContactsScreen)
+	if !ok {
+		t.Fatalf("Expected result to be *EmergencyContactsScreen, got %T", result)
+	}
+	rows := screen.table.Rows()
+	if len(rows) != 2 || rows[0][1] != "bob" || rows[0][2] != "7 days" || !strings.HasPrefix(rows[0][3], "requested, granted ") || rows[1][3] != "idle" {
+		t.Errorf("Unexpected rows: %v", rows)
+	}
+	if view := screen.View(); !strings.Contains(view, "carol") {
+		t.Errorf("View does not contain trusted users, got %q", view)
+	}
+}
+
+func Test_EmergencyContactsScreen_Init(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().GetEmergencyContacts(gomock.Any()).Return(nil, errors.New("server unavailable"))
+
+	screen := NewEmergencyContactsScreen(mockStorage)
+	cmd := screen.Init()
+	if cmd == nil {
+		t.Fatal("Expected Init to report the load error")
+	}
+	if msg, ok := cmd().(tui.ErrorMsg); !ok || !strings.Contains(msg.Error(), "server unavailable") {
+		t.Errorf("Expected error message, got %#v", cmd())
+	}
+	if !strings.Contains(screen.View(), "Nobody can request emergency access") {
+		t.Errorf("View does not report missing trusted users")
+	}
+}
+
+func Test_EmergencyContactsScreen_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		key            string
+		inputs         []string
+		mockSetup      func(store *emergencyStorage)
+		expectedScreen tui.Screen
+		expectedInfo   string
+		expectErr      bool
+	}{
+		{
+			name:   "Add",
+			key:    "a",
+			inputs: []string{" dave ", "14"},
+			mockSetup: func(store *emergencyStorage) {
+				store.EXPECT().AddEmergencyContact(gomock.Any(), " dave ", 14).Return(nil)
+				store.EXPECT().GetEmergencyContacts(gomock.Any()).Return(testContacts(), nil)
+			},
+			expectedScreen: -1,
+			expectedInfo:   "dave can request access after 14 days of waiting",
+		},
+		{
+			name:           "Add_InvalidDays",
+			key:            "a",
+			inputs:         []string{"dave", "soon"},
+			mockSetup:      func(_ *emergencyStorage) {},
+			expectedScreen: -1,
+			expectErr:      true,
+		},
+		{
+			name:   "Add_Error",
+			key:    "a",
+			inputs: []string{"dave", "14"},
+			mockSetup: func(store *emergencyStorage) {
+				store.EXPECT().AddEmergencyContact(gomock.Any(), "dave", 14).Return(storage.ErrVaultLocked)
+			},
+			expectedScreen: -1,
+			expectErr:      true,
+		},
+		{
+			name:   "Reject",
+			key:    "r",
+			inputs: []string{"y"},
+			mockSetup: func(store *emergencyStorage) {
+				store.EXPECT().RejectEmergencyAccess(gomock.Any(), uint64(5)).Return(nil)
+				store.EXPECT().GetEmergencyContacts(gomock.Any()).Return(testContacts(), nil)
+			},
+			expectedScreen: -1,
+			expectedInfo:   "emergency access of bob rejected",
+		},
+		{
+			name:   "Delete",
+			key:    "x",
+			inputs: []string{"y"},
+			mockSetup: func(store *emergencyStorage) {
+				store.EXPECT().DeleteEmergencyAccess(gomock.Any(), uint64(5)).Return(nil)
+				store.EXPECT().GetEmergencyContacts(gomock.Any()).Return(testContacts()[1:], nil)
+			},
+			expectedScreen: -1,
+			expectedInfo:   "bob removed from trusted users",
+		},
+		{
+			name:           "Grants",
+			key:            "g",
+			mockSetup:      func(_ *emergencyStorage) {},
+			expectedScreen: tui.EmergencyGrantsScreen,
+		},
+		{
+			name:           "Back",
+			key:            "b",
+			mockSetup:      func(_ *emergencyStorage) {},
+			expectedScreen: tui.StorageBrowseScreen,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := &emergencyStorage{MockStorage: mocks.NewMockStorage(ctrl)}
+			store.EXPECT().GetEmergencyContacts(gomock.Any()).Return(testContacts(), nil)
+			tc.mockSetup(store)
+
+			screen := NewEmergencyContactsScreen(store)
+			gotScreen, gotInfo, gotErr := run(screen, screen.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tc.key)}), tc.inputs)
+
+			if gotScreen != tc.expectedScreen {
+				t.Errorf("Expected screen %v, got %v", tc.expectedScreen, gotScreen)
+			}
+			if tc.expectErr != gotErr {
+				t.Errorf("Expected error %v, got %v", tc.expectErr, gotErr)
+			}
+			if tc.expectedInfo != "" && gotInfo != tc.expectedInfo {
+				t.Errorf("Expected info %q, got %q", tc.expectedInfo, gotInfo)
+			}
+		})
+	}
+}
+
+func Test_EmergencyContactsScreen_Notification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().GetEmergencyContacts(gomock.Any()).Return(testContacts()[1:], nil)
+	mockStorage.EXPECT().GetEmergencyContacts(gomock.Any()).Return(testContacts(), nil)
+
+	screen := NewEmergencyContactsScreen(mockStorage)
+	screen.Update(grpc.EmergencyAccessChanged{})
+
+	if len(screen.table.Rows()) != 2 {
+		t.Errorf("Expected trusted users to be reloaded, got %v", screen.table.Rows())
+	}
+}
+
+// run выполняет команду экрана, отвечая на запросы ввода строками inputs по порядку, и возвращает экран,
+// на который выполнен переход, последнее информационное сообщение и признак ошибки.
+func run(screen tui.TeaLike, cmd tea.Cmd, inputs []string) (gotScreen tui.Screen, gotInfo string, gotErr bool) {
+	gotScreen = -1
+	var handle func(msg tea.Msg)
+	handle = func(msg tea.Msg) {
+		switch msg := msg.(type) {
+		case tui.PromptMsg:
+			var input string
+			if len(inputs) > 0 {
+				input, inputs = inputs[0], inputs[1:]
+			}
+			collect(msg.Action(input), handle)
+		case accessChangedMsg:
+			collect(screen.Update(msg), handle)
+		case tui.NavigationMsg:
+			gotScreen = msg.Page.Screen
+		case tui.InfoMsg:
+			gotInfo = string(msg)
+		case tui.ErrorMsg:
+			gotErr = true
+		}
+	}
+	collect(cmd, handle)
+	return gotScreen, gotInfo, gotErr
+}
+
+// collect выполняет команду и передаёт все полученные сообщения, раскрывая пакеты команд.
+func collect(cmd tea.Cmd, fn func(msg tea.Msg)) {
+	if cmd == nil {
+		return
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, c := range batch {
+			collect(c, fn)
+		}
+		return
+	}
+	fn(msg)
+}
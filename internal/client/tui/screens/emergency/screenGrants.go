@@ -0,0 +1,188 @@
+package emergency
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/grpc"
+	"beliaev-aa/GophKeeper/internal/client/storage"
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/internal/client/tui/styles"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbletea"
+	"strconv"
+	"strings"
+)
+
+// errNoEmergencyVaults возникает, если хранилище не позволяет открывать хранилища по экстренному доступу.
+var errNoEmergencyVaults = errors.New("emergency access is not available for this storage")
+
+// EmergencyGrantsScreen предоставляет модель экрана хранилищ, владельцы которых назначили пользователя
+// доверенным. Хранилище владельца открывается только для чтения после предоставления доступа.
+type EmergencyGrantsScreen struct {
+	err      error
+	accesses []*models.EmergencyAccess
+	storage  storage.Storage
+	table    table.Model
+}
+
+// Make создает экран хранилищ с экстренным доступом для хранилища, переданного в сообщении навигации.
+func (s *EmergencyGrantsScreen) Make(msg tui.NavigationMsg, _, _ int) (tui.TeaLike, error) {
+	return NewEmergencyGrantsScreen(msg.Storage), nil
+}
+
+// NewEmergencyGrantsScreen создает новый экран хранилищ с экстренным доступом и загружает их.
+func NewEmergencyGrantsScreen(store storage.Storage) *EmergencyGrantsScreen {
+	scr := &EmergencyGrantsScreen{
+		storage: store,
+		table: newTable([]table.Column{
+			{Title: "id", Width: 5},
+			{Title: "Owner", Width: 25},
+			{Title: "Wait", Width: 8},
+			{Title: "Status", Width: 35},
+		}),
+	}
+
+	scr.updateRows()
+
+	return scr
+}
+
+// Init инициализирует экран и сообщает об ошибке загрузки хранилищ.
+func (s *EmergencyGrantsScreen) Init() tea.Cmd {
+	if s.err != nil {
+		return tui.ReportError(fmt.Errorf("failed to load emergency access: %w", s.err))
+	}
+	return nil
+}
+
+// Update обновляет состояние экрана в ответ на сообщения.
+func (s *EmergencyGrantsScreen) Update(msg tea.Msg) tea.Cmd {
+	var (
+		cmd      tea.Cmd
+		commands []tea.Cmd
+	)
+
+	switch msg := msg.(type) {
+	case grpc.EmergencyAccessChanged:
+		s.updateRows()
+	case accessChangedMsg:
+		s.updateRows()
+		commands = append(commands, tui.ReportInfo("%s", msg.info))
+	case tea.WindowSizeMsg:
+		s.table.SetWidth(min(msg.Width, colsWidth(s.table)))
+		s.table.SetHeight(max(msg.Height-tableBorderSize, 1))
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "enter":
+			commands = append(commands, s.handleOpen())
+		case "q":
+			commands = append(commands, s.handleRequest())
+		case "x":
+			commands = append(commands, s.handleDelete())
+		case "c":
+			commands = append(commands, tui.SetBodyPane(tui.EmergencyContactsScreen, tui.WithStorage(s.storage)))
+		case "b":
+			commands = append(commands, tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(personal(s.storage))))
+		}
+	}
+
+	s.table.Focus()
+	s.table, cmd = s.table.Update(msg)
+	commands = append(commands, cmd)
+
+	return tea.Batch(commands...)
+}
+
+// View отображает список хранилищ с экстренным доступом.
+func (s *EmergencyGrantsScreen) View() string {
+	var b strings.Builder
+
+	b.WriteString("Emergency access: vaults you can access\n")
+	b.WriteString("Use ↑↓ to navigate, open vault[enter], request access[q], remove[x], your trusted users[c], back[b]\n")
+
+	if len(s.accesses) == 0 {
+		b.WriteString("\nNobody has made you a trusted user\n")
+		return styles.StorageScreenStyle.Render(b.String())
+	}
+
+	b.WriteString(styles.TableStyle.Render(s.table.View()))
+
+	return styles.StorageScreenStyle.Render(b.String())
+}
+
+// HelpBindings возвращает набор горячих клавиш для экрана.
+func (s *EmergencyGrantsScreen) HelpBindings() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "open vault")),
+		key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "request access")),
+		key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "remove access")),
+		key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "your trusted users")),
+		key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "back")),
+	}
+}
+
+func (s *EmergencyGrantsScreen) updateRows() {
+	s.accesses, s.err = s.storage.GetEmergencyGrants(context.Background())
+
+	var rows []table.Row
+	for _, access := range s.accesses {
+		rows = append(rows, table.Row{
+			strconv.FormatUint(access.ID, 10),
+			access.GrantorLogin,
+			waitText(access),
+			statusText(access),
+		})
+	}
+
+	s.table.SetRows(rows)
+}
+
+func (s *EmergencyGrantsScreen) handleOpen() tea.Cmd {
+	access := selectedAccess(s.table, s.accesses)
+	if access == nil {
+		return tui.ReportError(fmt.Errorf("no vault selected"))
+	}
+
+	store, ok := s.storage.(storage.EmergencyStorage)
+	if !ok {
+		return tui.ReportError(errNoEmergencyVaults)
+	}
+	vault, err := store.OpenEmergencyVault(access)
+	if err != nil {
+		return tui.ReportError(fmt.Errorf("failed to open vault of %s: %w", access.GrantorLogin, err))
+	}
+
+	return tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(vault))
+}
+
+func (s *EmergencyGrantsScreen) handleRequest() tea.Cmd {
+	access := selectedAccess(s.table, s.accesses)
+	if access == nil {
+		return tui.ReportError(fmt.Errorf("no vault selected"))
+	}
+
+	prompt := fmt.Sprintf("request access to vault of %s? %s can reject it within %d days", access.GrantorLogin, access.GrantorLogin, access.WaitDays)
+	return tui.YesNoPrompt(prompt, func() tea.Msg {
+		if err := s.storage.RequestEmergencyAccess(context.Background(), access.ID); err != nil {
+			return tui.ErrorMsg(fmt.Errorf("failed to request access: %w", err))
+		}
+		return accessChangedMsg{info: fmt.Sprintf("emergency access to vault of %s requested", access.GrantorLogin)}
+	})
+}
+
+func (s *EmergencyGrantsScreen) handleDelete() tea.Cmd {
+	access := selectedAccess(s.table, s.accesses)
+	if access == nil {
+		return tui.ReportError(fmt.Errorf("no vault selected"))
+	}
+
+	return tui.YesNoPrompt(fmt.Sprintf("give up emergency access to vault of %s?", access.GrantorLogin), func() tea.Msg {
+		if err := s.storage.DeleteEmergencyAccess(context.Background(), access.ID); err != nil {
+			return tui.ErrorMsg(fmt.Errorf("failed to remove access: %w", err))
+		}
+		return accessChangedMsg{info: fmt.Sprintf("emergency access to vault of %s removed", access.GrantorLogin)}
+	})
+}
@@ -0,0 +1,176 @@
+package emergency
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/storage"
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"errors"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/golang/mock/gomock"
+	"strings"
+	"testing"
+)
+
+func testGrants() []*models.EmergencyAccess {
+	return []*models.EmergencyAccess{
+		{ID: 5, GrantorLogin: "alice", WaitDays: 7, Status: models.EmergencyGranted, VaultKey: []byte("wrapped")},
+		{ID: 6, GrantorLogin: "dave", WaitDays: 3, Status: models.EmergencyIdle},
+	}
+}
+
+func Test_EmergencyGrantsScreen_Make(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().GetEmergencyGrants(gomock.Any()).Return(testGrants(), nil)
+
+	maker := &EmergencyGrantsScreen{}
+	result, err := maker.Make(tui.NavigationMsg{Storage: mockStorage}, 0, 0)
+	if err != nil {
+		t.Errorf("Make returned an error: %v", err)
+	}
+
+	screen, ok := result.(*EmergencyGrantsScreen)
+	if !ok {
+		t.Fatalf("Expected result to be *EmergencyGrantsScreen, got %T", result)
+	}
+	rows := screen.table.Rows()
+	if len(rows) != 2 || rows[0][1] != "alice" || rows[0][3] != "granted" {
+		t.Errorf("Unexpected rows: %v", rows)
+	}
+	if view := screen.View(); !strings.Contains(view, "dave") {
+		t.Errorf("View does not contain vaults, got %q", view)
+	}
+}
+
+func Test_EmergencyGrantsScreen_Init(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().GetEmergencyGrants(gomock.Any()).Return(nil, errors.New("server unavailable"))
+
+	screen := NewEmergencyGrantsScreen(mockStorage)
+	cmd := screen.Init()
+	if cmd == nil {
+		t.Fatal("Expected Init to report the load error")
+	}
+	if msg, ok := cmd().(tui.ErrorMsg); !ok || !strings.Contains(msg.Error(), "server unavailable") {
+		t.Errorf("Expected error message, got %#v", cmd())
+	}
+	if !strings.Contains(screen.View(), "Nobody has made you a trusted user") {
+		t.Errorf("View does not report missing vaults")
+	}
+}
+
+func Test_EmergencyGrantsScreen_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		key            string
+		cursor         int
+		input          string
+		mockSetup      func(store *emergencyStorage)
+		expectedScreen tui.Screen
+		expectedInfo   string
+		expectErr      bool
+		expectOpened   bool
+	}{
+		{
+			name:           "Open",
+			key:            "enter",
+			mockSetup:      func(_ *emergencyStorage) {},
+			expectedScreen: tui.StorageBrowseScreen,
+			expectOpened:   true,
+		},
+		{
+			name: "Open_Error",
+			key:  "enter",
+			mockSetup: func(store *emergencyStorage) {
+				store.openErr = storage.ErrEmergencyNotGranted
+			},
+			expectedScreen: -1,
+			expectErr:      true,
+			expectOpened:   true,
+		},
+		{
+			name:   "Request",
+			key:    "q",
+			cursor: 1,
+			input:  "y",
+			mockSetup: func(store *emergencyStorage) {
+				store.EXPECT().RequestEmergencyAccess(gomock.Any(), uint64(6)).Return(nil)
+				store.EXPECT().GetEmergencyGrants(gomock.Any()).Return(testGrants(), nil)
+			},
+			expectedScreen: -1,
+			expectedInfo:   "emergency access to vault of dave requested",
+		},
+		{
+			name:  "Request_Error",
+			key:   "q",
+			input: "y",
+			mockSetup: func(store *emergencyStorage) {
+				store.EXPECT().RequestEmergencyAccess(gomock.Any(), uint64(5)).Return(errors.New("emergency access is already requested or granted"))
+			},
+			expectedScreen: -1,
+			expectErr:      true,
+		},
+		{
+			name:  "Delete",
+			key:   "x",
+			input: "y",
+			mockSetup: func(store *emergencyStorage) {
+				store.EXPECT().DeleteEmergencyAccess(gomock.Any(), uint64(5)).Return(nil)
+				store.EXPECT().GetEmergencyGrants(gomock.Any()).Return(testGrants()[1:], nil)
+			},
+			expectedScreen: -1,
+			expectedInfo:   "emergency access to vault of alice removed",
+		},
+		{
+			name:           "Contacts",
+			key:            "c",
+			mockSetup:      func(_ *emergencyStorage) {},
+			expectedScreen: tui.EmergencyContactsScreen,
+		},
+		{
+			name:           "Back",
+			key:            "b",
+			mockSetup:      func(_ *emergencyStorage) {},
+			expectedScreen: tui.StorageBrowseScreen,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := &emergencyStorage{MockStorage: mocks.NewMockStorage(ctrl)}
+			store.EXPECT().GetEmergencyGrants(gomock.Any()).Return(testGrants(), nil)
+			tc.mockSetup(store)
+
+			screen := NewEmergencyGrantsScreen(store)
+			screen.table.SetCursor(tc.cursor)
+			key := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tc.key)}
+			if tc.key == "enter" {
+				key = tea.KeyMsg{Type: tea.KeyEnter}
+			}
+
+			gotScreen, gotInfo, gotErr := run(screen, screen.Update(key), []string{tc.input})
+
+			if gotScreen != tc.expectedScreen {
+				t.Errorf("Expected screen %v, got %v", tc.expectedScreen, gotScreen)
+			}
+			if tc.expectErr != gotErr {
+				t.Errorf("Expected error %v, got %v", tc.expectErr, gotErr)
+			}
+			if tc.expectedInfo != "" && gotInfo != tc.expectedInfo {
+				t.Errorf("Expected info %q, got %q", tc.expectedInfo, gotInfo)
+			}
+			if opened := store.opened != nil && store.opened.ID == 5; opened != tc.expectOpened {
+				t.Errorf("Expected vault opened %v, got %v", tc.expectOpened, store.opened)
+			}
+		})
+	}
+}
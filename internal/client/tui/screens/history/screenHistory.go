@@ -0,0 +1,192 @@
+// Package history предоставляет экран просмотра и восстановления предыдущих версий секрета.
+package history
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/storage"
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/internal/client/tui/styles"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbletea"
+	"strconv"
+	"strings"
+)
+
+const (
+	tableBorderSize = 4
+	// previewHeight - количество строк, отводимых под содержимое выбранной версии.
+	previewHeight = 6
+)
+
+// SecretHistoryScreen предоставляет модель экрана истории версий секрета.
+// Версии расшифровываются на клиенте тем же ключом, что и сами секреты.
+type SecretHistoryScreen struct {
+	err      error
+	secret   *models.Secret
+	storage  storage.Storage
+	table    table.Model
+	versions []*models.SecretVersion
+}
+
+// Make создает экран истории версий секрета, переданного в сообщении навигации.
+func (s *SecretHistoryScreen) Make(msg tui.NavigationMsg, _, _ int) (tui.TeaLike, error) {
+	return NewSecretHistoryScreen(msg.Storage, msg.Secret), nil
+}
+
+// NewSecretHistoryScreen создает новый экран истории версий и загружает версии секрета.
+func NewSecretHistoryScreen(store storage.Storage, secret *models.Secret) *SecretHistoryScreen {
+	scr := &SecretHistoryScreen{
+		secret:  secret,
+		storage: store,
+		table:   prepareTable(),
+	}
+
+	scr.updateRows()
+
+	return scr
+}
+
+// Init инициализирует экран и сообщает об ошибке загрузки версий.
+func (s *SecretHistoryScreen) Init() tea.Cmd {
+	if s.err != nil {
+		return tui.ReportError(fmt.Errorf("failed to load history: %w", s.err))
+	}
+	return nil
+}
+
+// Update обновляет состояние экрана в ответ на сообщения.
+func (s *SecretHistoryScreen) Update(msg tea.Msg) tea.Cmd {
+	var (
+		cmd      tea.Cmd
+		commands []tea.Cmd
+	)
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		s.table.SetWidth(min(msg.Width, s.colsWidth()))
+		s.table.SetHeight(max(msg.Height-tableBorderSize-previewHeight, 1))
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "r":
+			commands = append(commands, s.handleRestore())
+		case "b":
+			commands = append(commands, tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(s.storage)))
+		}
+	}
+
+	s.table.Focus()
+	s.table, cmd = s.table.Update(msg)
+	commands = append(commands, cmd)
+
+	return tea.Batch(commands...)
+}
+
+// View отображает историю версий и содержимое выбранной версии.
+func (s *SecretHistoryScreen) View() string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("History of %s\n", styles.Highlighted.Render(s.secret.Title)))
+	b.WriteString("Use ↑↓ to navigate, restore[r], back[b]\n")
+
+	if len(s.versions) == 0 {
+		b.WriteString("\nNo previous versions\n")
+		return styles.StorageScreenStyle.Render(b.String())
+	}
+
+	b.WriteString(styles.TableStyle.Render(s.table.View()))
+	b.WriteString("\n")
+	if version := s.selectedVersion(); version != nil {
+		b.WriteString(preview(version))
+	}
+
+	return styles.StorageScreenStyle.Render(b.String())
+}
+
+// HelpBindings возвращает набор горячих клавиш для экрана.
+func (s *SecretHistoryScreen) HelpBindings() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "restore version")),
+		key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "back")),
+	}
+}
+
+func (s *SecretHistoryScreen) updateRows() {
+	s.versions, s.err = s.storage.GetVersions(context.Background(), s.secret.ID)
+
+	var rows []table.Row
+	for _, v := range s.versions {
+		rows = append(rows, table.Row{
+			strconv.FormatUint(v.VersionID, 10),
+			v.Title,
+			v.UpdatedAt.Format("02 Jan 06 15:04"),
+			v.ArchivedAt.Format("02 Jan 06 15:04"),
+		})
+	}
+
+	s.table.SetRows(rows)
+}
+
+func (s *SecretHistoryScreen) handleRestore() tea.Cmd {
+	version := s.selectedVersion()
+	if version == nil {
+		return tui.ReportError(fmt.Errorf("no version selected"))
+	}
+
+	if err := s.storage.RestoreVersion(context.Background(), s.secret.ID, version.VersionID); err != nil {
+		return tui.ReportError(fmt.Errorf("failed to restore version: %w", err))
+	}
+
+	return tea.Batch(
+		tui.ReportInfo("version from %s restored", version.UpdatedAt.Format("02 Jan 06 15:04")),
+		tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(s.storage)),
+	)
+}
+
+func (s *SecretHistoryScreen) selectedVersion() *models.SecretVersion {
+	cursor := s.table.Cursor()
+	if cursor < 0 || cursor >= len(s.versions) {
+		return nil
+	}
+	return s.versions[cursor]
+}
+
+func (s *SecretHistoryScreen) colsWidth() int {
+	total := tableBorderSize
+	for _, c := range s.table.Columns() {
+		total += c.Width
+	}
+
+	return total
+}
+
+// preview возвращает содержимое версии для просмотра.
+func preview(version *models.SecretVersion) string {
+	if models.SecretType(version.SecretType) == models.BlobSecret && version.Blob != nil {
+		return fmt.Sprintf("File: %s\n", version.Blob.FileName)
+	}
+	return version.ToClipboard()
+}
+
+func prepareTable() table.Model {
+	columns := []table.Column{
+		{Title: "Version", Width: 8},
+		{Title: "Title", Width: 20},
+		{Title: "Updated", Width: 20},
+		{Title: "Replaced", Width: 20},
+	}
+
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithFocused(true),
+	)
+
+	st := table.DefaultStyles()
+	st.Header = styles.TableHeaderStyle
+	st.Selected = styles.TableSelectedStyle
+	t.SetStyles(st)
+
+	return t
+}
@@ -0,0 +1,170 @@
+package history
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"errors"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/golang/mock/gomock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testVersions() []*models.SecretVersion {
+	now := time.Now()
+	return []*models.SecretVersion{
+		{
+			Secret:     models.Secret{ID: 1, Title: "Note v2", SecretType: string(models.TextSecret), Text: &models.Text{Content: "second"}, UpdatedAt: now},
+			VersionID:  5,
+			ArchivedAt: now,
+		},
+		{
+			Secret:     models.Secret{ID: 1, Title: "Note v1", SecretType: string(models.TextSecret), Text: &models.Text{Content: "first"}, UpdatedAt: now.Add(-time.Hour)},
+			VersionID:  4,
+			ArchivedAt: now.Add(-time.Minute),
+		},
+	}
+}
+
+func Test_SecretHistoryScreen_Make(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().GetVersions(gomock.Any(), uint64(1)).Return(testVersions(), nil)
+
+	maker := &SecretHistoryScreen{}
+	result, err := maker.Make(tui.NavigationMsg{Storage: mockStorage, Secret: &models.Secret{ID: 1}}, 0, 0)
+	if err != nil {
+		t.Errorf("Make returned an error: %v", err)
+	}
+
+	screen, ok := result.(*SecretHistoryScreen)
+	if !ok {
+		t.Fatalf("Expected result to be *SecretHistoryScreen, got %T", result)
+	}
+	if len(screen.table.Rows()) != 2 || screen.table.Rows()[0][0] != "5" {
+		t.Errorf("Unexpected rows: %v", screen.table.Rows())
+	}
+}
+
+func Test_SecretHistoryScreen_Init(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().GetVersions(gomock.Any(), uint64(1)).Return(nil, errors.New("decrypt failed"))
+
+	screen := NewSecretHistoryScreen(mockStorage, &models.Secret{ID: 1})
+	cmd := screen.Init()
+	if cmd == nil {
+		t.Fatal("Expected Init to report the load error")
+	}
+	if msg, ok := cmd().(tui.ErrorMsg); !ok || !strings.Contains(msg.Error(), "decrypt failed") {
+		t.Errorf("Expected error message, got %#v", cmd())
+	}
+}
+
+func Test_SecretHistoryScreen_View(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().GetVersions(gomock.Any(), uint64(1)).Return(testVersions(), nil)
+	mockStorage.EXPECT().GetVersions(gomock.Any(), uint64(2)).Return(nil, nil)
+
+	screen := NewSecretHistoryScreen(mockStorage, &models.Secret{ID: 1, Title: "Note"})
+	view := screen.View()
+	if !strings.Contains(view, "History of") || !strings.Contains(view, "Text: second") {
+		t.Errorf("View does not contain the selected version, got %q", view)
+	}
+
+	empty := NewSecretHistoryScreen(mockStorage, &models.Secret{ID: 2, Title: "Empty"})
+	if !strings.Contains(empty.View(), "No previous versions") {
+		t.Errorf("View does not report empty history")
+	}
+}
+
+func Test_SecretHistoryScreen_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		key            string
+		mockSetup      func(mockStorage *mocks.MockStorage)
+		expectedScreen tui.Screen
+		expectErr      bool
+	}{
+		{
+			name: "Restore",
+			key:  "r",
+			mockSetup: func(mockStorage *mocks.MockStorage) {
+				mockStorage.EXPECT().RestoreVersion(gomock.Any(), uint64(1), uint64(5)).Return(nil)
+			},
+			expectedScreen: tui.StorageBrowseScreen,
+		},
+		{
+			name: "Restore_Error",
+			key:  "r",
+			mockSetup: func(mockStorage *mocks.MockStorage) {
+				mockStorage.EXPECT().RestoreVersion(gomock.Any(), uint64(1), uint64(5)).Return(errors.New("not found"))
+			},
+			expectErr: true,
+		},
+		{
+			name:           "Back",
+			key:            "b",
+			mockSetup:      func(_ *mocks.MockStorage) {},
+			expectedScreen: tui.StorageBrowseScreen,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := mocks.NewMockStorage(ctrl)
+			mockStorage.EXPECT().GetVersions(gomock.Any(), uint64(1)).Return(testVersions(), nil)
+			tc.mockSetup(mockStorage)
+
+			screen := NewSecretHistoryScreen(mockStorage, &models.Secret{ID: 1})
+			cmd := screen.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tc.key)})
+
+			var (
+				gotScreen = tui.Screen(-1)
+				gotErr    bool
+			)
+			collect(cmd, func(msg tea.Msg) {
+				switch msg := msg.(type) {
+				case tui.NavigationMsg:
+					gotScreen = msg.Screen
+				case tui.ErrorMsg:
+					gotErr = true
+				}
+			})
+
+			if tc.expectErr != gotErr {
+				t.Errorf("Expected error %v, got %v", tc.expectErr, gotErr)
+			}
+			if !tc.expectErr && gotScreen != tc.expectedScreen {
+				t.Errorf("Expected screen %v, got %v", tc.expectedScreen, gotScreen)
+			}
+		})
+	}
+}
+
+// collect выполняет команду и передаёт все полученные сообщения, раскрывая пакеты команд.
+func collect(cmd tea.Cmd, fn func(msg tea.Msg)) {
+	if cmd == nil {
+		return
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, c := range batch {
+			collect(c, fn)
+		}
+		return
+	}
+	fn(msg)
+}
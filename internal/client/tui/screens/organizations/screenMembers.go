@@ -0,0 +1,201 @@
+package organizations
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/storage"
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/internal/client/tui/styles"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbletea"
+	"strings"
+)
+
+// rolePrompt - подсказка для ввода роли участника организации.
+const rolePrompt = "role (owner, admin, editor, viewer)"
+
+// membersChangedMsg сообщает об изменении состава или ролей участников организации.
+type membersChangedMsg struct {
+	info string
+}
+
+// OrgMembersScreen предоставляет модель экрана участников организации.
+type OrgMembersScreen struct {
+	err     error
+	members []*models.OrgMember
+	org     *models.Organization
+	storage storage.Storage
+	table   table.Model
+}
+
+// Make создает экран участников организации, переданной в сообщении навигации.
+func (s *OrgMembersScreen) Make(msg tui.NavigationMsg, _, _ int) (tui.TeaLike, error) {
+	if msg.Organization == nil {
+		return nil, fmt.Errorf("no organization selected")
+	}
+	return NewOrgMembersScreen(msg.Storage, msg.Organization), nil
+}
+
+// NewOrgMembersScreen создает новый экран участников организации org и загружает их.
+func NewOrgMembersScreen(store storage.Storage, org *models.Organization) *OrgMembersScreen {
+	scr := &OrgMembersScreen{
+		org:     org,
+		storage: store,
+		table: newTable([]table.Column{
+			{Title: "Login", Width: 25},
+			{Title: "Role", Width: 10},
+			{Title: "Added", Width: 20},
+		}),
+	}
+
+	scr.updateRows()
+
+	return scr
+}
+
+// Init инициализирует экран и сообщает об ошибке загрузки участников.
+func (s *OrgMembersScreen) Init() tea.Cmd {
+	if s.err != nil {
+		return tui.ReportError(fmt.Errorf("failed to load members: %w", s.err))
+	}
+	return nil
+}
+
+// Update обновляет состояние экрана в ответ на сообщения.
+func (s *OrgMembersScreen) Update(msg tea.Msg) tea.Cmd {
+	var (
+		cmd      tea.Cmd
+		commands []tea.Cmd
+	)
+
+	switch msg := msg.(type) {
+	case membersChangedMsg:
+		s.updateRows()
+		commands = append(commands, tui.ReportInfo("%s", msg.info))
+	case tea.WindowSizeMsg:
+		s.table.SetHeight(max(msg.Height-tableBorderSize, 1))
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "a":
+			commands = append(commands, s.handleAdd())
+		case "r":
+			commands = append(commands, s.handleRole())
+		case "x":
+			commands = append(commands, s.handleRemove())
+		case "b":
+			commands = append(commands, tui.SetBodyPane(tui.OrganizationsScreen, tui.WithStorage(s.storage)))
+		}
+	}
+
+	s.table.Focus()
+	s.table, cmd = s.table.Update(msg)
+	commands = append(commands, cmd)
+
+	return tea.Batch(commands...)
+}
+
+// View отображает список участников организации.
+func (s *OrgMembersScreen) View() string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("Members of %s, your role: %s\n", styles.Highlighted.Render(s.org.Name), styles.Highlighted.Render(string(s.org.Role))))
+	b.WriteString("Use ↑↓ to navigate, add[a], change role[r], remove[x], back[b]\n")
+	b.WriteString(styles.TableStyle.Render(s.table.View()))
+
+	return styles.StorageScreenStyle.Render(b.String())
+}
+
+// HelpBindings возвращает набор горячих клавиш для экрана.
+func (s *OrgMembersScreen) HelpBindings() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "add member")),
+		key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "change role")),
+		key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "remove member")),
+		key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "back")),
+	}
+}
+
+func (s *OrgMembersScreen) updateRows() {
+	s.members, s.err = s.storage.GetOrgMembers(context.Background(), s.org.ID)
+
+	var rows []table.Row
+	for _, member := range s.members {
+		rows = append(rows, table.Row{
+			member.Login,
+			string(member.Role),
+			member.CreatedAt.Format("02 Jan 06 15:04"),
+		})
+	}
+
+	s.table.SetRows(rows)
+}
+
+func (s *OrgMembersScreen) handleAdd() tea.Cmd {
+	return tui.StringPrompt("login of the new member", func(login string) tea.Cmd {
+		return tui.StringPrompt(rolePrompt, func(str string) tea.Cmd {
+			return func() tea.Msg {
+				role, err := parseRole(str)
+				if err != nil {
+					return tui.ErrorMsg(err)
+				}
+				if err = s.storage.AddOrgMember(context.Background(), s.org, login, role); err != nil {
+					return tui.ErrorMsg(fmt.Errorf("failed to add member: %w", err))
+				}
+				return membersChangedMsg{info: fmt.Sprintf("%s added as %s", strings.TrimSpace(login), role)}
+			}
+		})
+	})
+}
+
+func (s *OrgMembersScreen) handleRole() tea.Cmd {
+	member := s.selectedMember()
+	if member == nil {
+		return tui.ReportError(fmt.Errorf("no member selected"))
+	}
+
+	return tui.StringPrompt(fmt.Sprintf("new %s of %s", rolePrompt, member.Login), func(str string) tea.Cmd {
+		return func() tea.Msg {
+			role, err := parseRole(str)
+			if err != nil {
+				return tui.ErrorMsg(err)
+			}
+			if err = s.storage.UpdateOrgMemberRole(context.Background(), s.org.ID, member.UserID, role); err != nil {
+				return tui.ErrorMsg(fmt.Errorf("failed to change role: %w", err))
+			}
+			return membersChangedMsg{info: fmt.Sprintf("%s is now %s", member.Login, role)}
+		}
+	})
+}
+
+func (s *OrgMembersScreen) handleRemove() tea.Cmd {
+	member := s.selectedMember()
+	if member == nil {
+		return tui.ReportError(fmt.Errorf("no member selected"))
+	}
+
+	return tui.YesNoPrompt(fmt.Sprintf("remove %s from %s? The vault key is not rotated: secrets %s has already seen stay readable to them", member.Login, s.org.Name, member.Login), func() tea.Msg {
+		if err := s.storage.RemoveOrgMember(context.Background(), s.org.ID, member.UserID); err != nil {
+			return tui.ErrorMsg(fmt.Errorf("failed to remove member: %w", err))
+		}
+		return membersChangedMsg{info: fmt.Sprintf("%s removed from %s", member.Login, s.org.Name)}
+	})
+}
+
+func (s *OrgMembersScreen) selectedMember() *models.OrgMember {
+	cursor := s.table.Cursor()
+	if cursor < 0 || cursor >= len(s.members) {
+		return nil
+	}
+	return s.members[cursor]
+}
+
+// parseRole преобразует введённое название роли в роль участника организации.
+func parseRole(str string) (models.OrgRole, error) {
+	role := models.OrgRole(strings.ToLower(strings.TrimSpace(str)))
+	if !role.Valid() {
+		return "", fmt.Errorf("unknown role %q", str)
+	}
+	return role, nil
+}
@@ -0,0 +1,143 @@
+package organizations
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"errors"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/golang/mock/gomock"
+	"strings"
+	"testing"
+)
+
+func testMembers() []*models.OrgMember {
+	return []*models.OrgMember{
+		{OrgID: 7, UserID: 1, Login: "alice", Role: models.OrgOwner},
+		{OrgID: 7, UserID: 2, Login: "bob", Role: models.OrgEditor},
+	}
+}
+
+func Test_OrgMembersScreen_Make(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().GetOrgMembers(gomock.Any(), uint64(7)).Return(testMembers(), nil)
+
+	maker := &OrgMembersScreen{}
+	if _, err := maker.Make(tui.NavigationMsg{Storage: mockStorage}, 0, 0); err == nil {
+		t.Error("Expected Make to fail without an organization")
+	}
+
+	result, err := maker.Make(tui.NavigationMsg{Storage: mockStorage, Organization: testOrganizations()[0]}, 0, 0)
+	if err != nil {
+		t.Fatalf("Make returned an error: %v", err)
+	}
+	screen, ok := result.(*OrgMembersScreen)
+	if !ok {
+		t.Fatalf("Expected result to be *OrgMembersScreen, got %T", result)
+	}
+	if len(screen.table.Rows()) != 2 || screen.table.Rows()[1][0] != "bob" {
+		t.Errorf("Unexpected rows: %v", screen.table.Rows())
+	}
+	if view := screen.View(); !strings.Contains(view, "Members of") || !strings.Contains(view, "alice") {
+		t.Errorf("View does not contain members, got %q", view)
+	}
+}
+
+func Test_OrgMembersScreen_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		key            string
+		input          string
+		mockSetup      func(mockStorage *mocks.MockStorage)
+		expectedScreen tui.Screen
+		expectedInfo   string
+		expectErr      bool
+	}{
+		{
+			name:  "Add",
+			key:   "a",
+			input: "editor",
+			mockSetup: func(mockStorage *mocks.MockStorage) {
+				mockStorage.EXPECT().AddOrgMember(gomock.Any(), testOrganizations()[0], "editor", models.OrgEditor).Return(nil)
+				mockStorage.EXPECT().GetOrgMembers(gomock.Any(), uint64(7)).Return(testMembers(), nil)
+			},
+			expectedScreen: -1,
+			expectedInfo:   "editor added as editor",
+		},
+		{
+			name:           "Add_UnknownRole",
+			key:            "a",
+			input:          "root",
+			mockSetup:      func(_ *mocks.MockStorage) {},
+			expectedScreen: -1,
+			expectErr:      true,
+		},
+		{
+			name:  "ChangeRole",
+			key:   "r",
+			input: " Viewer ",
+			mockSetup: func(mockStorage *mocks.MockStorage) {
+				mockStorage.EXPECT().UpdateOrgMemberRole(gomock.Any(), uint64(7), uint64(1), models.OrgViewer).Return(nil)
+				mockStorage.EXPECT().GetOrgMembers(gomock.Any(), uint64(7)).Return(testMembers(), nil)
+			},
+			expectedScreen: -1,
+			expectedInfo:   "alice is now viewer",
+		},
+		{
+			name:  "ChangeRole_Error",
+			key:   "r",
+			input: "viewer",
+			mockSetup: func(mockStorage *mocks.MockStorage) {
+				mockStorage.EXPECT().UpdateOrgMemberRole(gomock.Any(), uint64(7), uint64(1), models.OrgViewer).Return(errors.New("organization must keep an owner"))
+			},
+			expectedScreen: -1,
+			expectErr:      true,
+		},
+		{
+			name:  "Remove",
+			key:   "x",
+			input: "y",
+			mockSetup: func(mockStorage *mocks.MockStorage) {
+				mockStorage.EXPECT().RemoveOrgMember(gomock.Any(), uint64(7), uint64(1)).Return(nil)
+				mockStorage.EXPECT().GetOrgMembers(gomock.Any(), uint64(7)).Return(testMembers()[1:], nil)
+			},
+			expectedScreen: -1,
+			expectedInfo:   "alice removed from Team",
+		},
+		{
+			name:           "Back",
+			key:            "b",
+			mockSetup:      func(_ *mocks.MockStorage) {},
+			expectedScreen: tui.OrganizationsScreen,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := mocks.NewMockStorage(ctrl)
+			mockStorage.EXPECT().GetOrgMembers(gomock.Any(), uint64(7)).Return(testMembers(), nil)
+			tc.mockSetup(mockStorage)
+
+			screen := NewOrgMembersScreen(mockStorage, testOrganizations()[0])
+			cmd := screen.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tc.key)})
+
+			gotScreen, gotInfo, gotErr := run(screen, cmd, tc.input)
+
+			if gotScreen != tc.expectedScreen {
+				t.Errorf("Expected screen %v, got %v", tc.expectedScreen, gotScreen)
+			}
+			if tc.expectErr != gotErr {
+				t.Errorf("Expected error %v, got %v", tc.expectErr, gotErr)
+			}
+			if tc.expectedInfo != "" && gotInfo != tc.expectedInfo {
+				t.Errorf("Expected info %q, got %q", tc.expectedInfo, gotInfo)
+			}
+		})
+	}
+}
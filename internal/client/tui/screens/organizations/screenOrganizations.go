@@ -0,0 +1,255 @@
+// Package organizations предоставляет экраны организаций: список организаций пользователя, создание
+// и удаление организаций, переход в их хранилища и управление участниками.
+package organizations
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/storage"
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/internal/client/tui/styles"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbletea"
+	"strconv"
+	"strings"
+)
+
+const (
+	tableBorderSize = 4
+)
+
+// errNoOrganizations возникает, если хранилище не позволяет открывать хранилища организаций.
+var errNoOrganizations = errors.New("organizations are not available for this storage")
+
+// orgCreatedMsg сообщает о создании организации.
+type orgCreatedMsg struct {
+	name string
+}
+
+// orgDeletedMsg сообщает об удалении организации.
+type orgDeletedMsg struct {
+	name string
+}
+
+// OrganizationsScreen предоставляет модель экрана организаций пользователя.
+type OrganizationsScreen struct {
+	err     error
+	orgs    []*models.Organization
+	storage storage.Storage
+	table   table.Model
+}
+
+// Make создает экран организаций для хранилища, переданного в сообщении навигации.
+func (s *OrganizationsScreen) Make(msg tui.NavigationMsg, _, _ int) (tui.TeaLike, error) {
+	return NewOrganizationsScreen(msg.Storage), nil
+}
+
+// NewOrganizationsScreen создает новый экран организаций и загружает организации пользователя.
+func NewOrganizationsScreen(store storage.Storage) *OrganizationsScreen {
+	scr := &OrganizationsScreen{
+		storage: store,
+		table:   prepareTable(),
+	}
+
+	scr.updateRows()
+
+	return scr
+}
+
+// Init инициализирует экран и сообщает об ошибке загрузки организаций.
+func (s *OrganizationsScreen) Init() tea.Cmd {
+	if s.err != nil {
+		return tui.ReportError(fmt.Errorf("failed to load organizations: %w", s.err))
+	}
+	return nil
+}
+
+// Update обновляет состояние экрана в ответ на сообщения.
+func (s *OrganizationsScreen) Update(msg tea.Msg) tea.Cmd {
+	var (
+		cmd      tea.Cmd
+		commands []tea.Cmd
+	)
+
+	switch msg := msg.(type) {
+	case orgCreatedMsg:
+		s.updateRows()
+		commands = append(commands, tui.ReportInfo("organization %s created", msg.name))
+	case orgDeletedMsg:
+		s.updateRows()
+		commands = append(commands, tui.ReportInfo("organization %s deleted", msg.name))
+	case tea.WindowSizeMsg:
+		s.table.SetWidth(min(msg.Width, s.colsWidth()))
+		s.table.SetHeight(max(msg.Height-tableBorderSize, 1))
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "enter":
+			commands = append(commands, s.handleOpen())
+		case "n":
+			commands = append(commands, s.handleCreate())
+		case "m":
+			commands = append(commands, s.handleMembers())
+		case "x":
+			commands = append(commands, s.handleDelete())
+		case "b":
+			commands = append(commands, tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(s.personal())))
+		}
+	}
+
+	s.table.Focus()
+	s.table, cmd = s.table.Update(msg)
+	commands = append(commands, cmd)
+
+	return tea.Batch(commands...)
+}
+
+// View отображает список организаций.
+func (s *OrganizationsScreen) View() string {
+	var b strings.Builder
+
+	b.WriteString("Organizations\n")
+	b.WriteString("Use ↑↓ to navigate, open vault[enter], new[n], members[m], delete[x], back to personal vault[b]\n")
+
+	if len(s.orgs) == 0 {
+		b.WriteString("\nYou are not a member of any organization\n")
+		return styles.StorageScreenStyle.Render(b.String())
+	}
+
+	b.WriteString(styles.TableStyle.Render(s.table.View()))
+
+	return styles.StorageScreenStyle.Render(b.String())
+}
+
+// HelpBindings возвращает набор горячих клавиш для экрана.
+func (s *OrganizationsScreen) HelpBindings() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "open vault")),
+		key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "new organization")),
+		key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "members")),
+		key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "delete organization")),
+		key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "back")),
+	}
+}
+
+func (s *OrganizationsScreen) updateRows() {
+	s.orgs, s.err = s.storage.GetOrganizations(context.Background())
+
+	var rows []table.Row
+	for _, org := range s.orgs {
+		rows = append(rows, table.Row{
+			strconv.FormatUint(org.ID, 10),
+			org.Name,
+			string(org.Role),
+			org.CreatedAt.Format("02 Jan 06 15:04"),
+		})
+	}
+
+	s.table.SetRows(rows)
+}
+
+// personal возвращает личное хранилище пользователя.
+func (s *OrganizationsScreen) personal() storage.Storage {
+	if store, ok := s.storage.(storage.OrganizationStorage); ok {
+		return store.Personal()
+	}
+	return s.storage
+}
+
+func (s *OrganizationsScreen) handleOpen() tea.Cmd {
+	org := s.selectedOrganization()
+	if org == nil {
+		return tui.ReportError(fmt.Errorf("no organization selected"))
+	}
+
+	store, ok := s.storage.(storage.OrganizationStorage)
+	if !ok {
+		return tui.ReportError(errNoOrganizations)
+	}
+	orgStore, err := store.OpenOrganization(org)
+	if err != nil {
+		return tui.ReportError(fmt.Errorf("failed to open organization vault: %w", err))
+	}
+
+	return tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(orgStore))
+}
+
+func (s *OrganizationsScreen) handleCreate() tea.Cmd {
+	return tui.StringPrompt("organization name", func(name string) tea.Cmd {
+		return func() tea.Msg {
+			org, err := s.storage.CreateOrganization(context.Background(), name)
+			if err != nil {
+				return tui.ErrorMsg(fmt.Errorf("failed to create organization: %w", err))
+			}
+			return orgCreatedMsg{name: org.Name}
+		}
+	})
+}
+
+func (s *OrganizationsScreen) handleMembers() tea.Cmd {
+	org := s.selectedOrganization()
+	if org == nil {
+		return tui.ReportError(fmt.Errorf("no organization selected"))
+	}
+
+	return tui.SetBodyPane(tui.OrgMembersScreen, tui.WithStorage(s.storage), tui.WithOrganization(org))
+}
+
+func (s *OrganizationsScreen) handleDelete() tea.Cmd {
+	org := s.selectedOrganization()
+	if org == nil {
+		return tui.ReportError(fmt.Errorf("no organization selected"))
+	}
+
+	return tui.YesNoPrompt(fmt.Sprintf("delete organization %s with all its secrets? this cannot be undone", org.Name), func() tea.Msg {
+		if err := s.storage.DeleteOrganization(context.Background(), org.ID); err != nil {
+			return tui.ErrorMsg(fmt.Errorf("failed to delete organization: %w", err))
+		}
+		return orgDeletedMsg{name: org.Name}
+	})
+}
+
+func (s *OrganizationsScreen) selectedOrganization() *models.Organization {
+	cursor := s.table.Cursor()
+	if cursor < 0 || cursor >= len(s.orgs) {
+		return nil
+	}
+	return s.orgs[cursor]
+}
+
+func (s *OrganizationsScreen) colsWidth() int {
+	total := tableBorderSize
+	for _, c := range s.table.Columns() {
+		total += c.Width
+	}
+
+	return total
+}
+
+func prepareTable() table.Model {
+	columns := []table.Column{
+		{Title: "id", Width: 5},
+		{Title: "Name", Width: 25},
+		{Title: "Role", Width: 10},
+		{Title: "Created", Width: 20},
+	}
+
+	return newTable(columns)
+}
+
+// newTable создаёт таблицу с колонками columns в стиле приложения.
+func newTable(columns []table.Column) table.Model {
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithFocused(true),
+	)
+
+	st := table.DefaultStyles()
+	st.Header = styles.TableHeaderStyle
+	st.Selected = styles.TableSelectedStyle
+	t.SetStyles(st)
+
+	return t
+}
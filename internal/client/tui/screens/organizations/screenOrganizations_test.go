@@ -0,0 +1,227 @@
+package organizations
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/storage"
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"errors"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/golang/mock/gomock"
+	"strings"
+	"testing"
+)
+
+// orgStorage дополняет мок хранилища открытием хранилищ организаций.
+type orgStorage struct {
+	*mocks.MockStorage
+	personal storage.Storage
+	opened   *models.Organization
+	openErr  error
+}
+
+func (s *orgStorage) Personal() storage.Storage {
+	return s.personal
+}
+
+func (s *orgStorage) OpenOrganization(org *models.Organization) (storage.Storage, error) {
+	s.opened = org
+	return s.MockStorage, s.openErr
+}
+
+func testOrganizations() []*models.Organization {
+	return []*models.Organization{
+		{ID: 7, Name: "Team", Role: models.OrgOwner},
+		{ID: 8, Name: "Ops", Role: models.OrgViewer},
+	}
+}
+
+func Test_OrganizationsScreen_Make(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().GetOrganizations(gomock.Any()).Return(testOrganizations(), nil)
+
+	maker := &OrganizationsScreen{}
+	result, err := maker.Make(tui.NavigationMsg{Storage: mockStorage}, 0, 0)
+	if err != nil {
+		t.Errorf("Make returned an error: %v", err)
+	}
+
+	screen, ok := result.(*OrganizationsScreen)
+	if !ok {
+		t.Fatalf("Expected result to be *OrganizationsScreen, got %T", result)
+	}
+	if len(screen.table.Rows()) != 2 || screen.table.Rows()[0][1] != "Team" || screen.table.Rows()[0][2] != "owner" {
+		t.Errorf("Unexpected rows: %v", screen.table.Rows())
+	}
+	if view := screen.View(); !strings.Contains(view, "Ops") {
+		t.Errorf("View does not contain organizations, got %q", view)
+	}
+}
+
+func Test_OrganizationsScreen_Init(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().GetOrganizations(gomock.Any()).Return(nil, errors.New("server unavailable"))
+
+	screen := NewOrganizationsScreen(mockStorage)
+	cmd := screen.Init()
+	if cmd == nil {
+		t.Fatal("Expected Init to report the load error")
+	}
+	if msg, ok := cmd().(tui.ErrorMsg); !ok || !strings.Contains(msg.Error(), "server unavailable") {
+		t.Errorf("Expected error message, got %#v", cmd())
+	}
+	if !strings.Contains(screen.View(), "not a member of any organization") {
+		t.Errorf("View does not report missing organizations")
+	}
+}
+
+func Test_OrganizationsScreen_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		key            string
+		input          string
+		mockSetup      func(store *orgStorage)
+		expectedScreen tui.Screen
+		expectedInfo   string
+		expectErr      bool
+		expectOpened   bool
+	}{
+		{
+			name:           "Open",
+			key:            "enter",
+			mockSetup:      func(_ *orgStorage) {},
+			expectedScreen: tui.StorageBrowseScreen,
+			expectOpened:   true,
+		},
+		{
+			name: "Open_Error",
+			key:  "enter",
+			mockSetup: func(store *orgStorage) {
+				store.openErr = errors.New("vault is locked")
+			},
+			expectedScreen: -1,
+			expectErr:      true,
+			expectOpened:   true,
+		},
+		{
+			name:  "Create",
+			key:   "n",
+			input: "Infra",
+			mockSetup: func(store *orgStorage) {
+				store.EXPECT().CreateOrganization(gomock.Any(), "Infra").Return(&models.Organization{ID: 9, Name: "Infra"}, nil)
+				store.EXPECT().GetOrganizations(gomock.Any()).Return(testOrganizations(), nil)
+			},
+			expectedScreen: -1,
+			expectedInfo:   "organization Infra created",
+		},
+		{
+			name:           "Members",
+			key:            "m",
+			mockSetup:      func(_ *orgStorage) {},
+			expectedScreen: tui.OrgMembersScreen,
+		},
+		{
+			name:  "Delete",
+			key:   "x",
+			input: "y",
+			mockSetup: func(store *orgStorage) {
+				store.EXPECT().DeleteOrganization(gomock.Any(), uint64(7)).Return(nil)
+				store.EXPECT().GetOrganizations(gomock.Any()).Return(testOrganizations()[1:], nil)
+			},
+			expectedScreen: -1,
+			expectedInfo:   "organization Team deleted",
+		},
+		{
+			name:  "Delete_Error",
+			key:   "x",
+			input: "y",
+			mockSetup: func(store *orgStorage) {
+				store.EXPECT().DeleteOrganization(gomock.Any(), uint64(7)).Return(errors.New("permission denied"))
+			},
+			expectedScreen: -1,
+			expectErr:      true,
+		},
+		{
+			name:           "Back",
+			key:            "b",
+			mockSetup:      func(_ *orgStorage) {},
+			expectedScreen: tui.StorageBrowseScreen,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := &orgStorage{MockStorage: mocks.NewMockStorage(ctrl)}
+			store.EXPECT().GetOrganizations(gomock.Any()).Return(testOrganizations(), nil)
+			tc.mockSetup(store)
+
+			screen := NewOrganizationsScreen(store)
+			key := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tc.key)}
+			if tc.key == "enter" {
+				key = tea.KeyMsg{Type: tea.KeyEnter}
+			}
+
+			gotScreen, gotInfo, gotErr := run(screen, screen.Update(key), tc.input)
+
+			if gotScreen != tc.expectedScreen {
+				t.Errorf("Expected screen %v, got %v", tc.expectedScreen, gotScreen)
+			}
+			if tc.expectErr != gotErr {
+				t.Errorf("Expected error %v, got %v", tc.expectErr, gotErr)
+			}
+			if tc.expectedInfo != "" && gotInfo != tc.expectedInfo {
+				t.Errorf("Expected info %q, got %q", tc.expectedInfo, gotInfo)
+			}
+			if opened := store.opened != nil && store.opened.ID == 7; opened != tc.expectOpened {
+				t.Errorf("Expected organization opened %v, got %v", tc.expectOpened, store.opened)
+			}
+		})
+	}
+}
+
+// run выполняет команду экрана, отвечая на запросы ввода строкой input, и возвращает экран, на который
+// выполнен переход, последнее информационное сообщение и признак ошибки.
+func run(screen tui.TeaLike, cmd tea.Cmd, input string) (gotScreen tui.Screen, gotInfo string, gotErr bool) {
+	gotScreen = -1
+	var handle func(msg tea.Msg)
+	handle = func(msg tea.Msg) {
+		switch msg := msg.(type) {
+		case tui.PromptMsg:
+			collect(msg.Action(input), handle)
+		case orgCreatedMsg, orgDeletedMsg, membersChangedMsg:
+			collect(screen.Update(msg), handle)
+		case tui.NavigationMsg:
+			gotScreen = msg.Page.Screen
+		case tui.InfoMsg:
+			gotInfo = string(msg)
+		case tui.ErrorMsg:
+			gotErr = true
+		}
+	}
+	collect(cmd, handle)
+	return gotScreen, gotInfo, gotErr
+}
+
+// collect выполняет команду и передаёт все полученные сообщения, раскрывая пакеты команд.
+func collect(cmd tea.Cmd, fn func(msg tea.Msg)) {
+	if cmd == nil {
+		return
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, c := range batch {
+			collect(c, fn)
+		}
+		return
+	}
+	fn(msg)
+}
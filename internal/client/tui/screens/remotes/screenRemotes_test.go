@@ -57,6 +57,8 @@ func Test_RemoteOpenScreen_Init(t *testing.T) {
 
 	mockClient := mocks.NewMockClientGRPCInterface(ctrl)
 	mockClient.EXPECT().GetPassword().Return("valid_password").AnyTimes()
+	mockClient.EXPECT().GetEncryptionKey().Return(make([]byte, 32)).AnyTimes()
+	mockClient.EXPECT().GetLogin().Return("user").AnyTimes()
 
 	tests := []struct {
 		name        string
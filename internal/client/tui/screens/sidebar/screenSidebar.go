@@ -0,0 +1,317 @@
+// Package sidebar предоставляет боковую панель папок и меток, которая фильтрует список секретов на экране просмотра хранилища.
+package sidebar
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/storage"
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/internal/client/tui/styles"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbletea"
+	"sort"
+	"strings"
+)
+
+// entryKind определяет вид строки боковой панели.
+type entryKind int
+
+const (
+	allEntry entryKind = iota
+	folderEntry
+	tagEntry
+)
+
+// entry описывает строку боковой панели: все секреты, папку или метку.
+type entry struct {
+	kind     entryKind
+	id       uint64
+	parentID uint64
+	name     string
+	depth    int
+}
+
+// filter возвращает фильтр списка секретов, соответствующий строке.
+func (e entry) filter() models.SecretFilter {
+	switch e.kind {
+	case folderEntry:
+		return models.SecretFilter{FolderID: e.id}
+	case tagEntry:
+		return models.SecretFilter{TagID: e.id}
+	default:
+		return models.SecretFilter{}
+	}
+}
+
+// labelsChangedMsg сообщает об изменении папок или меток и содержит текст для пользователя.
+type labelsChangedMsg struct {
+	info string
+}
+
+// SidebarScreen предоставляет модель боковой панели папок и меток.
+// Выбор строки отправляет экрану просмотра хранилища сообщение tui.SecretFilterMsg.
+type SidebarScreen struct {
+	storage storage.Storage
+	entries []entry
+	cursor  int
+	// filter - папка и метка, выбранные для фильтрации списка секретов.
+	filter models.SecretFilter
+	err    error
+}
+
+// Make создает боковую панель для хранилища, переданного в сообщении навигации.
+func (s *SidebarScreen) Make(msg tui.NavigationMsg, _, _ int) (tui.TeaLike, error) {
+	return NewSidebarScreen(msg.Storage), nil
+}
+
+// NewSidebarScreen создает новую боковую панель и загружает папки и метки.
+func NewSidebarScreen(store storage.Storage) *SidebarScreen {
+	scr := &SidebarScreen{
+		storage: store,
+	}
+
+	scr.updateEntries()
+
+	return scr
+}
+
+// Init инициализирует панель и сообщает об ошибке загрузки папок и меток.
+func (s *SidebarScreen) Init() tea.Cmd {
+	if s.err != nil {
+		return tui.ReportError(fmt.Errorf("failed to load folders: %w", s.err))
+	}
+	return nil
+}
+
+// Update обновляет состояние панели в ответ на сообщения.
+func (s *SidebarScreen) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tui.SecretFilterRequestMsg:
+		return s.filterCmd()
+	case labelsChangedMsg:
+		return s.handleLabelsChanged(msg)
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			s.cursor = max(s.cursor-1, 0)
+		case "down", "j":
+			s.cursor = min(s.cursor+1, len(s.entries)-1)
+		case "enter":
+			s.filter = s.entries[s.cursor].filter()
+			return s.filterCmd()
+		case "a":
+			return s.handleAssign()
+		case "n":
+			return s.handleCreateFolder()
+		case "T":
+			return s.handleCreateTag()
+		case "r":
+			return s.handleRename()
+		case "d":
+			return s.handleDelete()
+		}
+	}
+
+	return nil
+}
+
+// View отображает дерево папок и список меток.
+func (s *SidebarScreen) View() string {
+	var b strings.Builder
+
+	for i, e := range s.entries {
+		switch {
+		case e.kind == folderEntry && (i == 0 || s.entries[i-1].kind != folderEntry):
+			b.WriteString("\nFolders\n")
+		case e.kind == tagEntry && (i == 0 || s.entries[i-1].kind != tagEntry):
+			b.WriteString("\nTags\n")
+		}
+
+		line := strings.Repeat("  ", e.depth) + e.name
+		if e.kind == tagEntry {
+			line = "#" + line
+		}
+		if e.filter() == s.filter {
+			line = styles.Highlighted.Render(line)
+		}
+
+		prefix := "  "
+		if i == s.cursor {
+			prefix = "> "
+		}
+		b.WriteString(prefix + line + "\n")
+	}
+
+	return b.String()
+}
+
+// HelpBindings возвращает набор горячих клавиш для панели.
+func (s *SidebarScreen) HelpBindings() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "filter secrets")),
+		key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "put selected secret here")),
+		key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "new folder")),
+		key.NewBinding(key.WithKeys("T"), key.WithHelp("T", "new tag")),
+		key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "rename")),
+		key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete")),
+	}
+}
+
+// updateEntries загружает папки и метки и строит строки панели: все секреты, дерево папок и метки.
+func (s *SidebarScreen) updateEntries() {
+	s.entries = []entry{{kind: allEntry, name: "All secrets"}}
+
+	folders, err := s.storage.GetFolders(context.Background())
+	if err != nil {
+		s.err = err
+		return
+	}
+	tags, err := s.storage.GetTags(context.Background())
+	if err != nil {
+		s.err = err
+		return
+	}
+	s.err = nil
+
+	s.entries = append(s.entries, folderEntries(folders)...)
+	for _, tag := range tags {
+		s.entries = append(s.entries, entry{kind: tagEntry, id: tag.ID, name: tag.Name})
+	}
+
+	s.cursor = min(s.cursor, len(s.entries)-1)
+}
+
+// folderEntries возвращает строки папок в порядке обхода дерева: каждая папка следует за своей родительской папкой.
+func folderEntries(folders []*models.Folder) []entry {
+	children := make(map[uint64][]*models.Folder)
+	for _, folder := range folders {
+		children[folder.ParentID] = append(children[folder.ParentID], folder)
+	}
+
+	var (
+		entries []entry
+		walk    func(parentID uint64, depth int)
+	)
+	walk = func(parentID uint64, depth int) {
+		nested := children[parentID]
+		sort.Slice(nested, func(i, j int) bool { return nested[i].Name < nested[j].Name })
+		for _, folder := range nested {
+			entries = append(entries, entry{kind: folderEntry, id: folder.ID, parentID: folder.ParentID, name: folder.Name, depth: depth})
+			walk(folder.ID, depth+1)
+		}
+	}
+	walk(0, 0)
+
+	return entries
+}
+
+// handleLabelsChanged перезагружает папки и метки. Если выбранные для фильтрации папка или метка удалены,
+// фильтр сбрасывается.
+func (s *SidebarScreen) handleLabelsChanged(msg labelsChangedMsg) tea.Cmd {
+	s.updateEntries()
+	if s.err != nil {
+		return tui.ReportError(fmt.Errorf("failed to load folders: %w", s.err))
+	}
+
+	commands := []tea.Cmd{tui.ReportInfo("%s", msg.info)}
+	if !s.hasFilterEntry() {
+		s.filter = models.SecretFilter{}
+		commands = append(commands, s.filterCmd())
+	}
+
+	return tea.Batch(commands...)
+}
+
+func (s *SidebarScreen) hasFilterEntry() bool {
+	for _, e := range s.entries {
+		if e.filter() == s.filter {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SidebarScreen) filterCmd() tea.Cmd {
+	return tui.CmdHandler(tui.SecretFilterMsg(s.filter))
+}
+
+func (s *SidebarScreen) handleAssign() tea.Cmd {
+	return tui.CmdHandler(tui.AssignLabelMsg(s.entries[s.cursor].filter()))
+}
+
+func (s *SidebarScreen) handleCreateFolder() tea.Cmd {
+	var parentID uint64
+	prompt := "new folder name"
+	if e := s.entries[s.cursor]; e.kind == folderEntry {
+		parentID = e.id
+		prompt = fmt.Sprintf("new folder name in %s", e.name)
+	}
+
+	return tui.StringPrompt(prompt, func(name string) tea.Cmd {
+		return func() tea.Msg {
+			if _, err := s.storage.CreateFolder(context.Background(), parentID, name); err != nil {
+				return tui.ErrorMsg(fmt.Errorf("failed to create folder: %w", err))
+			}
+			return labelsChangedMsg{info: fmt.Sprintf("folder %s created", name)}
+		}
+	})
+}
+
+func (s *SidebarScreen) handleCreateTag() tea.Cmd {
+	return tui.StringPrompt("new tag name", func(name string) tea.Cmd {
+		return func() tea.Msg {
+			if _, err := s.storage.CreateTag(context.Background(), name); err != nil {
+				return tui.ErrorMsg(fmt.Errorf("failed to create tag: %w", err))
+			}
+			return labelsChangedMsg{info: fmt.Sprintf("tag %s created", name)}
+		}
+	})
+}
+
+func (s *SidebarScreen) handleRename() tea.Cmd {
+	e := s.entries[s.cursor]
+	if e.kind == allEntry {
+		return nil
+	}
+
+	return tui.StringPrompt(fmt.Sprintf("rename %s to", e.name), func(name string) tea.Cmd {
+		return func() tea.Msg {
+			var err error
+			if e.kind == folderEntry {
+				err = s.storage.UpdateFolder(context.Background(), e.id, e.parentID, name)
+			} else {
+				err = s.storage.UpdateTag(context.Background(), e.id, name)
+			}
+			if err != nil {
+				return tui.ErrorMsg(fmt.Errorf("failed to rename %s: %w", e.name, err))
+			}
+			return labelsChangedMsg{info: fmt.Sprintf("%s renamed to %s", e.name, name)}
+		}
+	})
+}
+
+func (s *SidebarScreen) handleDelete() tea.Cmd {
+	e := s.entries[s.cursor]
+	if e.kind == allEntry {
+		return nil
+	}
+
+	prompt := fmt.Sprintf("delete tag %s?", e.name)
+	if e.kind == folderEntry {
+		prompt = fmt.Sprintf("delete folder %s with its subfolders? secrets will be kept", e.name)
+	}
+
+	return tui.YesNoPrompt(prompt, func() tea.Msg {
+		var err error
+		if e.kind == folderEntry {
+			err = s.storage.DeleteFolder(context.Background(), e.id)
+		} else {
+			err = s.storage.DeleteTag(context.Background(), e.id)
+		}
+		if err != nil {
+			return tui.ErrorMsg(fmt.Errorf("failed to delete %s: %w", e.name, err))
+		}
+		return labelsChangedMsg{info: fmt.Sprintf("%s deleted", e.name)}
+	})
+}
@@ -0,0 +1,163 @@
+package sidebar
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"fmt"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/golang/mock/gomock"
+	"strings"
+	"testing"
+)
+
+func newTestSidebar(t *testing.T) (*SidebarScreen, *mocks.MockStorage) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().GetFolders(gomock.Any()).Return([]*models.Folder{
+		{ID: 2, ParentID: 1, Name: "Cards"},
+		{ID: 1, Name: "Banks"},
+		{ID: 3, Name: "Airlines"},
+	}, nil)
+	mockStorage.EXPECT().GetTags(gomock.Any()).Return([]*models.Tag{{ID: 7, Name: "work"}}, nil)
+
+	return NewSidebarScreen(mockStorage), mockStorage
+}
+
+func keyMsg(key string) tea.KeyMsg {
+	if key == "enter" {
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+func Test_SidebarScreen_Entries(t *testing.T) {
+	screen, _ := newTestSidebar(t)
+
+	var names []string
+	for _, e := range screen.entries {
+		names = append(names, fmt.Sprintf("%d:%s", e.depth, e.name))
+	}
+	expected := "0:All secrets 0:Airlines 0:Banks 1:Cards 0:work"
+	if got := strings.Join(names, " "); got != expected {
+		t.Errorf("Expected entries %q, got %q", expected, got)
+	}
+
+	if cmd := screen.Init(); cmd != nil {
+		t.Errorf("Init should not return a command")
+	}
+
+	view := screen.View()
+	for _, part := range []string{"> All secrets", "Folders", "    Cards", "Tags", "#work"} {
+		if !strings.Contains(view, part) {
+			t.Errorf("Expected view to contain %q, got:\n%s", part, view)
+		}
+	}
+}
+
+func Test_SidebarScreen_LoadFail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().GetFolders(gomock.Any()).Return(nil, fmt.Errorf("unavailable"))
+
+	screen := NewSidebarScreen(mockStorage)
+	if len(screen.entries) != 1 {
+		t.Errorf("Expected only the 'All secrets' entry, got %v", screen.entries)
+	}
+	if cmd := screen.Init(); cmd == nil {
+		t.Errorf("Init should report the load error")
+	}
+}
+
+func Test_SidebarScreen_Filter(t *testing.T) {
+	screen, _ := newTestSidebar(t)
+
+	screen.Update(keyMsg("down"))
+	screen.Update(keyMsg("down"))
+	screen.Update(keyMsg("down"))
+	cmd := screen.Update(keyMsg("enter"))
+	if cmd == nil {
+		t.Fatal("Expected a filter command")
+	}
+	if msg, ok := cmd().(tui.SecretFilterMsg); !ok || msg.FolderID != 2 {
+		t.Errorf("Expected filter by folder 2, got %v", msg)
+	}
+
+	screen.Update(keyMsg("down"))
+	screen.Update(keyMsg("down"))
+	if screen.cursor != 4 {
+		t.Errorf("Expected cursor to stop at the last entry, got %d", screen.cursor)
+	}
+
+	if msg, ok := screen.Update(keyMsg("a"))().(tui.AssignLabelMsg); !ok || msg.TagID != 7 {
+		t.Errorf("Expected assignment of tag 7, got %v", msg)
+	}
+
+	if msg, ok := screen.Update(tui.SecretFilterRequestMsg{})().(tui.SecretFilterMsg); !ok || msg.FolderID != 2 {
+		t.Errorf("Expected current filter in reply, got %v", msg)
+	}
+}
+
+func Test_SidebarScreen_Manage(t *testing.T) {
+	screen, mockStorage := newTestSidebar(t)
+
+	if cmd := screen.Update(keyMsg("r")); cmd != nil {
+		t.Errorf("Expected 'All secrets' entry not to be renamed")
+	}
+	if cmd := screen.Update(keyMsg("d")); cmd != nil {
+		t.Errorf("Expected 'All secrets' entry not to be deleted")
+	}
+
+	for _, key := range []string{"n", "T"} {
+		if cmd := screen.Update(keyMsg(key)); cmd == nil {
+			t.Errorf("Expected a prompt for key %q", key)
+		}
+	}
+
+	screen.cursor = 2
+	screen.filter = models.SecretFilter{FolderID: 1}
+	cmd := screen.Update(keyMsg("d"))
+	if cmd == nil {
+		t.Fatal("Expected a delete confirmation prompt")
+	}
+	msg, ok := cmd().(tui.PromptMsg)
+	if !ok {
+		t.Fatalf("Expected a prompt message, got %T", cmd())
+	}
+
+	mockStorage.EXPECT().DeleteFolder(gomock.Any(), uint64(1)).Return(nil)
+	changed := msg.Action("y")()
+	if _, ok := changed.(labelsChangedMsg); !ok {
+		t.Fatalf("Expected labels changed message, got %T", changed)
+	}
+
+	mockStorage.EXPECT().GetFolders(gomock.Any()).Return([]*models.Folder{{ID: 3, Name: "Airlines"}}, nil)
+	mockStorage.EXPECT().GetTags(gomock.Any()).Return(nil, nil)
+	if cmd = screen.Update(changed); cmd == nil {
+		t.Fatal("Expected info and filter reset commands")
+	}
+	if screen.filter != (models.SecretFilter{}) {
+		t.Errorf("Expected filter of the deleted folder to be reset, got %+v", screen.filter)
+	}
+	if screen.cursor != 1 {
+		t.Errorf("Expected cursor within entries, got %d", screen.cursor)
+	}
+}
+
+func Test_SidebarScreen_Make(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().GetFolders(gomock.Any()).Return(nil, nil)
+	mockStorage.EXPECT().GetTags(gomock.Any()).Return(nil, nil)
+
+	model, err := (&SidebarScreen{}).Make(tui.NewNavigationMsg(tui.SidebarScreen, tui.WithStorage(mockStorage)), 0, 0)
+	if err != nil || model == nil {
+		t.Errorf("Make() got model = %v, err = %v", model, err)
+	}
+}
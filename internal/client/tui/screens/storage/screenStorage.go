@@ -2,19 +2,20 @@
 package storage
 
 import (
+	"beliaev-aa/GophKeeper/internal/client/crypto"
 	"beliaev-aa/GophKeeper/internal/client/grpc"
 	"beliaev-aa/GophKeeper/internal/client/storage"
 	"beliaev-aa/GophKeeper/internal/client/tui"
 	"beliaev-aa/GophKeeper/internal/client/tui/styles"
 	"beliaev-aa/GophKeeper/pkg/models"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbletea"
-	"os"
-	"sort"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -28,10 +29,49 @@ type savePathMsg = struct {
 	secret *models.Secret
 }
 
+// secretDeletedMsg сообщает о перемещении секрета в корзину.
+type secretDeletedMsg struct {
+	title string
+}
+
+// shareRevokedMsg сообщает об отказе от секрета, переданного другим пользователем.
+type shareRevokedMsg struct {
+	title string
+}
+
+// errSharedSecret возникает при действиях, недоступных для секретов других пользователей.
+var errSharedSecret = errors.New("not available for secrets shared with you")
+
+// searchMsg задаёт слова, которые должен содержать заголовок секретов в списке.
+type searchMsg struct {
+	words string
+}
+
+// secretTypeFilters - фильтры по типу секрета в порядке их переключения; пустой тип показывает все секреты.
+var secretTypeFilters = []models.SecretType{"", models.CredSecret, models.TextSecret, models.BlobSecret, models.CardSecret}
+
+// secretOrderNames - названия порядков списка секретов для отображения на экране. Порядки по заголовку
+// не предлагаются: заголовки зашифрованы, и сервер упорядочил бы их по шифротексту.
+var secretOrderNames = map[models.SecretOrder]string{
+	models.SecretOrderUpdatedDesc: "newest first",
+	models.SecretOrderUpdatedAsc:  "oldest first",
+}
+
 // BrowseStorageScreen предоставляет модель экрана для просмотра хранилища секретов.
+// Таблица содержит только заголовки секретов и загружается постранично: следующая страница
+// запрашивается, когда курсор доходит до последней строки, а данные секрета - при его открытии.
 type BrowseStorageScreen struct {
 	storage storage.Storage
 	table   table.Model
+	// query - фильтр и порядок списка секретов.
+	query models.SecretListQuery
+	// nextPageToken - токен следующей страницы списка; пустой, если загружены все секреты.
+	nextPageToken string
+	// sharedView - true, если таблица содержит секреты, переданные пользователю другими пользователями,
+	// а не его собственные секреты.
+	sharedView bool
+	// shared - показанные в таблице секреты других пользователей по их идентификаторам.
+	shared map[uint64]*models.Secret
 }
 
 // Make создает экран для просмотра хранилища.
@@ -51,10 +91,14 @@ func NewStorageBrowseScreenScreen(storage storage.Storage) *BrowseStorageScreen
 	return scr
 }
 
-// Init инициализирует экран и обновляет строки таблицы.
+// Init инициализирует экран, обновляет строки таблицы и открывает боковую панель папок и меток,
+// запрашивая у неё выбранный фильтр.
 func (s *BrowseStorageScreen) Init() tea.Cmd {
 	s.updateRows()
-	return nil
+	return tea.Sequence(
+		tui.SetLeftPane(tui.SidebarScreen, tui.WithStorage(s.storage), tui.WithoutFocus()),
+		tui.CmdHandler(tui.SecretFilterRequestMsg{}),
+	)
 }
 
 // Update обновляет состояние экрана в ответ на сообщения.
@@ -66,14 +110,25 @@ func (s *BrowseStorageScreen) Update(msg tea.Msg) tea.Cmd {
 
 	switch msg := msg.(type) {
 	case grpc.ReloadSecretList:
+		if msg.OrgID == s.orgID() {
+			s.updateRows()
+		}
+	case searchMsg:
+		s.query.Search = msg.words
+		s.updateRows()
+	case tui.SecretFilterMsg:
+		s.query.SecretFilter = models.SecretFilter(msg)
+		s.updateRows()
+	case tui.AssignLabelMsg:
+		commands = append(commands, s.handleAssign(models.SecretFilter(msg)))
+	case secretDeletedMsg:
+		s.updateRows()
+		commands = append(commands, infoCmd(fmt.Sprintf("secret %s moved to trash", msg.title)))
+	case shareRevokedMsg:
 		s.updateRows()
+		commands = append(commands, infoCmd(fmt.Sprintf("secret %s is no longer shared with you", msg.title)))
 	case savePathMsg:
-		err := os.WriteFile(msg.path, msg.secret.Blob.FileBytes, 0644)
-		if err != nil {
-			commands = append(commands, tui.ReportError(err))
-		} else {
-			commands = append(commands, infoCmd("file saved successfully"))
-		}
+		commands = append(commands, s.handleDownload(msg))
 	case tea.WindowSizeMsg:
 		s.table.SetWidth(min(msg.Width, s.colsWidth()))
 		s.table.SetHeight(msg.Height - tableBorderSize)
@@ -86,11 +141,40 @@ func (s *BrowseStorageScreen) Update(msg tea.Msg) tea.Cmd {
 			commands = append(commands, s.handleEdit())
 		case "c":
 			commands = append(commands, s.handleCopy())
+		case "h":
+			commands = append(commands, s.handleHistory())
+		case "p":
+			commands = append(commands, tui.SetBodyPane(tui.PasswordChangeScreen, tui.WithStorage(s.storage)))
+		case "X":
+			commands = append(commands, tui.SetBodyPane(tui.AccountDeleteScreen, tui.WithStorage(s.storage)))
 		case "d":
 			commands = append(commands, s.handleDelete())
-
+		case "t":
+			commands = append(commands, tui.SetBodyPane(tui.TrashScreen, tui.WithStorage(s.storage)))
+		case "O":
+			commands = append(commands, tui.SetBodyPane(tui.OrganizationsScreen, tui.WithStorage(s.storage)))
+		case "E":
+			commands = append(commands, tui.SetBodyPane(tui.EmergencyContactsScreen, tui.WithStorage(s.storage)))
+		case "s":
+			commands = append(commands, s.handleShare())
+		case "u":
+			commands = append(commands, s.handleUnshare())
+		case "K":
+			commands = append(commands, s.handleFingerprint())
+		case "S":
+			s.sharedView = !s.sharedView
+			s.updateRows()
+			s.table.SetCursor(0)
+		case "/":
+			commands = append(commands, tui.StringPrompt("search by title words", func(str string) tea.Cmd {
+				return func() tea.Msg { return searchMsg{words: str} }
+			}))
+		case "f":
+			s.query.SecretType = string(nextSecretTypeFilter(models.SecretType(s.query.SecretType)))
+			s.updateRows()
+		case "o":
+			s.query.Order = (s.query.Order + 1) % models.SecretOrder(len(secretOrderNames))
 			s.updateRows()
-			commands = append(commands, tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(s.storage)))
 		}
 	}
 
@@ -98,6 +182,12 @@ func (s *BrowseStorageScreen) Update(msg tea.Msg) tea.Cmd {
 	s.table, cmd = s.table.Update(msg)
 	commands = append(commands, cmd)
 
+	if s.nextPageToken != "" && s.table.Cursor() >= len(s.table.Rows())-1 {
+		if err := s.loadNextPage(); err != nil {
+			commands = append(commands, errCmd("failed to load secrets", err))
+		}
+	}
+
 	return tea.Batch(commands...)
 }
 
@@ -106,7 +196,12 @@ func (s *BrowseStorageScreen) View() string {
 	var b strings.Builder
 
 	b.WriteString(fmt.Sprintf("Operating storage %s\n", styles.Highlighted.Render(s.storage.String())))
-	b.WriteString("Use ↑↓ to navigate, add[a], edit[e], delete[d], copy[c]\n")
+	b.WriteString("Use ↑↓ to navigate, add[a], edit[e], delete[d], copy[c], history[h], share[s], unshare[u], key fingerprint[K], trash[t], organizations[O], emergency access[E], change password[p], delete account[X]\n")
+	b.WriteString(fmt.Sprintf("Secrets[S]: %s, search by title[/]: %s, type[f]: %s, order[o]: %s\n",
+		styles.Highlighted.Render(s.viewName()),
+		styles.Highlighted.Render(valueOrAll(s.query.Search)),
+		styles.Highlighted.Render(valueOrAll(s.query.SecretType)),
+		styles.Highlighted.Render(secretOrderNames[s.query.Order])))
 	b.WriteString(styles.TableStyle.Render(s.table.View()))
 
 	return styles.StorageScreenStyle.Render(b.String())
@@ -119,26 +214,151 @@ func (s *BrowseStorageScreen) HelpBindings() []key.Binding {
 		key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit secret")),
 		key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete secret")),
 		key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "copy/save secret")),
+		key.NewBinding(key.WithKeys("h"), key.WithHelp("h", "secret history")),
+		key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "share secret")),
+		key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "revoke share")),
+		key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "shared with me")),
+		key.NewBinding(key.WithKeys("K"), key.WithHelp("K", "key fingerprint")),
+		key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "trash")),
+		key.NewBinding(key.WithKeys("O"), key.WithHelp("O", "organizations")),
+		key.NewBinding(key.WithKeys("E"), key.WithHelp("E", "emergency access")),
+		key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search by title")),
+		key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "filter by type")),
+		key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "change order")),
+		key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "change master password")),
+		key.NewBinding(key.WithKeys("X"), key.WithHelp("X", "delete account")),
 	}
 }
 
+// updateRows загружает первую страницу списка секретов с текущими фильтром и порядком.
 func (s *BrowseStorageScreen) updateRows() {
-	secrets, _ := s.storage.GetAll(context.Background())
+	if s.sharedView {
+		s.updateSharedRows()
+		return
+	}
+
+	query := s.query
+	page, err := s.storage.List(context.Background(), &query)
+	if err != nil {
+		page = &models.SecretsPage{}
+	}
+
+	s.nextPageToken = page.NextPageToken
+	s.table.SetRows(secretRows(nil, page.Secrets))
+	if len(page.Secrets) > 0 && s.table.Cursor() >= len(page.Secrets) {
+		s.table.SetCursor(len(page.Secrets) - 1)
+	}
+}
+
+// loadNextPage загружает следующую страницу списка секретов и добавляет её в конец таблицы.
+func (s *BrowseStorageScreen) loadNextPage() error {
+	query := s.query
+	query.PageToken = s.nextPageToken
+	page, err := s.storage.List(context.Background(), &query)
+	if err != nil {
+		return err
+	}
 
-	sortSecrets(secrets)
+	s.nextPageToken = page.NextPageToken
+	s.table.SetRows(secretRows(s.table.Rows(), page.Secrets))
+	return nil
+}
 
-	var rows []table.Row
+// updateSharedRows загружает секреты, переданные пользователю, подходящие под тип и слова поиска.
+// Секреты других пользователей не входят в папки и метки пользователя, поэтому фильтр боковой панели
+// к ним не применяется. Список не разбит на страницы, а поиск выполняется по расшифрованным заголовкам.
+func (s *BrowseStorageScreen) updateSharedRows() {
+	secrets, err := s.storage.GetSharedWithMe(context.Background())
+	if err != nil {
+		secrets = nil
+	}
+
+	s.shared = make(map[uint64]*models.Secret, len(secrets))
+	visible := make([]*models.Secret, 0, len(secrets))
 	for _, sec := range secrets {
+		if (s.query.SecretType != "" && sec.SecretType != s.query.SecretType) || !matchesSearch(sec.Title, s.query.Search) {
+			continue
+		}
+		s.shared[sec.ID] = sec
+		visible = append(visible, sec)
+	}
+	if s.query.Order == models.SecretOrderUpdatedAsc {
+		slices.Reverse(visible)
+	}
+
+	s.nextPageToken = ""
+	s.table.SetRows(secretRows(nil, visible))
+	if len(visible) > 0 && s.table.Cursor() >= len(visible) {
+		s.table.SetCursor(len(visible) - 1)
+	}
+}
+
+// matchesSearch возвращает true, если заголовок содержит все слова поиска без учёта регистра.
+func matchesSearch(title, search string) bool {
+	title = strings.ToLower(title)
+	for _, word := range strings.Fields(strings.ToLower(search)) {
+		if !strings.Contains(title, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// orgID возвращает идентификатор организации, хранилище которой показано на экране, или 0 для личного хранилища.
+func (s *BrowseStorageScreen) orgID() uint64 {
+	if org := s.storage.Organization(); org != nil {
+		return org.ID
+	}
+	return 0
+}
+
+// viewName возвращает название показанного в таблице набора секретов.
+func (s *BrowseStorageScreen) viewName() string {
+	if s.sharedView {
+		return "shared with me"
+	}
+	return "mine"
+}
+
+// secretRows добавляет к строкам таблицы строки с заголовками секретов. У секретов других пользователей
+// к заголовку добавляются логин владельца и право доступа.
+func secretRows(rows []table.Row, secrets []*models.Secret) []table.Row {
+	for _, sec := range secrets {
+		title := sec.Title
+		if sec.Share != nil {
+			access := "read-only"
+			if sec.Share.CanEdit {
+				access = "editable"
+			}
+			title = fmt.Sprintf("%s (%s, %s)", sec.Title, sec.Share.OwnerLogin, access)
+		}
 		rows = append(rows, table.Row{
 			strconv.Itoa(int(sec.ID)),
-			sec.Title,
+			title,
 			sec.SecretType,
 			sec.CreatedAt.Format("02 Jan 06 15:04"),
 			sec.UpdatedAt.Format("02 Jan 06 15:04"),
 		})
 	}
+	return rows
+}
+
+// nextSecretTypeFilter возвращает фильтр по типу секрета, следующий за текущим.
+func nextSecretTypeFilter(current models.SecretType) models.SecretType {
+	for i, secretType := range secretTypeFilters {
+		if secretType == current {
+			return secretTypeFilters[(i+1)%len(secretTypeFilters)]
+		}
+	}
+	return secretTypeFilters[0]
+}
 
-	s.table.SetRows(rows)
+// valueOrAll возвращает значение фильтра или "all", если фильтр не задан.
+func valueOrAll(value string) string {
+	if value == "" {
+		return "all"
+	}
+	return value
 }
 
 func (s *BrowseStorageScreen) handleEdit() tea.Cmd {
@@ -155,6 +375,18 @@ func (s *BrowseStorageScreen) handleEdit() tea.Cmd {
 	return tui.SetBodyPane(screen, tui.WithSecret(secret), tui.WithStorage(s.storage))
 }
 
+func (s *BrowseStorageScreen) handleHistory() tea.Cmd {
+	secret, err := s.getSelectedSecret()
+	if err != nil {
+		return errCmd("failed to load secret", err)
+	}
+	if secret.Share != nil {
+		return errCmd("failed to open history", errSharedSecret)
+	}
+
+	return tui.SetBodyPane(tui.SecretHistoryScreen, tui.WithSecret(secret), tui.WithStorage(s.storage))
+}
+
 func (s *BrowseStorageScreen) handleCopy() tea.Cmd {
 	secret, err := s.getSelectedSecret()
 	if err != nil {
@@ -178,12 +410,164 @@ func (s *BrowseStorageScreen) handleDelete() tea.Cmd {
 		return errCmd("failed to load secret", err)
 	}
 
-	err = s.storage.Delete(context.Background(), secret.ID)
+	if secret.Share != nil {
+		return tui.YesNoPrompt(fmt.Sprintf("stop receiving %s from %s?", secret.Title, secret.Share.OwnerLogin), func() tea.Msg {
+			if err := s.storage.RevokeShare(context.Background(), secret.Share.ID); err != nil {
+				return tui.ErrorMsg(fmt.Errorf("failed to remove shared secret: %w", err))
+			}
+			return shareRevokedMsg{title: secret.Title}
+		})
+	}
+
+	return tui.YesNoPrompt(fmt.Sprintf("move %s to trash?", secret.Title), func() tea.Msg {
+		if err := s.storage.Delete(context.Background(), secret.ID); err != nil {
+			return tui.ErrorMsg(fmt.Errorf("failed to delete secret: %w", err))
+		}
+		return secretDeletedMsg{title: secret.Title}
+	})
+}
+
+// handleAssign помещает выбранный секрет в папку или добавляет и снимает с него метку, выбранную на боковой панели.
+func (s *BrowseStorageScreen) handleAssign(label models.SecretFilter) tea.Cmd {
+	if len(s.table.Rows()) == 0 {
+		return nil
+	}
+
+	secret, err := s.getSelectedSecret()
+	if err != nil {
+		return errCmd("failed to load secret", err)
+	}
+	if secret.Share != nil {
+		return errCmd("failed to assign label", errSharedSecret)
+	}
+
+	info := fmt.Sprintf("secret %s moved", secret.Title)
+	if label.TagID == 0 {
+		secret.FolderID = label.FolderID
+	} else if i := slices.Index(secret.TagIDs, label.TagID); i >= 0 {
+		secret.TagIDs = slices.Delete(secret.TagIDs, i, i+1)
+		info = fmt.Sprintf("tag removed from secret %s", secret.Title)
+	} else {
+		secret.TagIDs = append(secret.TagIDs, label.TagID)
+		info = fmt.Sprintf("tag added to secret %s", secret.Title)
+	}
+
+	if err = s.storage.Update(context.Background(), secret); err != nil {
+		return tui.SaveResult(err, secret, s.storage)
+	}
+
+	s.updateRows()
+	return infoCmd(info)
+}
+
+// handleShare передаёт выбранный секрет пользователю, логин которого вводится в диалоге, с доступом
+// только для чтения или с правом изменения. Отпечаток открытого ключа получателя, который тот сообщает
+// по независимому каналу, вводится в диалоге и сверяется с ключом, выданным сервером.
+func (s *BrowseStorageScreen) handleShare() tea.Cmd {
+	secret, err := s.getSelectedSecret()
+	if err != nil {
+		return errCmd("failed to load secret", err)
+	}
+	if secret.Share != nil {
+		return errCmd("failed to share secret", errSharedSecret)
+	}
+
+	return tui.StringPrompt(fmt.Sprintf("share %s with login", secret.Title), func(login string) tea.Cmd {
+		prompt := fmt.Sprintf("key fingerprint of %s", strings.TrimSpace(login))
+		return tui.StringPrompt(prompt, func(fingerprint string) tea.Cmd {
+			return tui.StringPrompt("access (view|edit)", func(access string) tea.Cmd {
+				var canEdit bool
+				switch strings.TrimSpace(access) {
+				case "", "view":
+				case "edit":
+					canEdit = true
+				default:
+					return errCmd("failed to share secret", fmt.Errorf("unknown access %q", access))
+				}
+
+				if err := s.storage.ShareSecret(context.Background(), secret, login, fingerprint, canEdit); err != nil {
+					return errCmd("failed to share secret", err)
+				}
+				return infoCmd(fmt.Sprintf("secret %s shared with %s", secret.Title, strings.TrimSpace(login)))
+			})
+		})
+	})
+}
+
+// handleFingerprint показывает отпечаток открытого ключа для сверки по независимому каналу: в таблице
+// переданных пользователю секретов - ключа владельца выбранного секрета, иначе - собственного ключа,
+// который пользователь сообщает отправителям секретов.
+func (s *BrowseStorageScreen) handleFingerprint() tea.Cmd {
+	if s.sharedView {
+		secret, err := s.getSelectedSecret()
+		if err != nil {
+			return errCmd("failed to load secret", err)
+		}
+		fingerprint := crypto.Fingerprint(secret.Share.OwnerPublicKey)
+		return infoCmd(fmt.Sprintf("key fingerprint of %s: %s", secret.Share.OwnerLogin, fingerprint))
+	}
+
+	fingerprint, err := s.storage.Fingerprint()
+	if err != nil {
+		return errCmd("failed to get key fingerprint", err)
+	}
+	return infoCmd(fmt.Sprintf("your key fingerprint: %s", fingerprint))
+}
+
+// handleUnshare отзывает доступ к выбранному секрету у пользователя, логин которого вводится в диалоге.
+// В диалоге перечисляются пользователи, которым секрет передан.
+func (s *BrowseStorageScreen) handleUnshare() tea.Cmd {
+	secret, err := s.getSelectedSecret()
 	if err != nil {
-		return errCmd("failed to delete secret", err)
+		return errCmd("failed to load secret", err)
+	}
+	if secret.Share != nil {
+		return errCmd("failed to revoke share", errSharedSecret)
 	}
 
-	return infoCmd("secret deleted")
+	shares, err := s.storage.GetShares(context.Background(), secret.ID)
+	if err != nil {
+		return errCmd("failed to load shares", err)
+	}
+	if len(shares) == 0 {
+		return infoCmd(fmt.Sprintf("secret %s is not shared", secret.Title))
+	}
+
+	logins := make([]string, 0, len(shares))
+	for _, share := range shares {
+		logins = append(logins, share.RecipientLogin)
+	}
+
+	prompt := fmt.Sprintf("revoke access to %s (shared with %s) for login", secret.Title, strings.Join(logins, ", "))
+	return tui.StringPrompt(prompt, func(login string) tea.Cmd {
+		login = strings.TrimSpace(login)
+		for _, share := range shares {
+			if !strings.EqualFold(share.RecipientLogin, login) {
+				continue
+			}
+			if err := s.storage.RevokeShare(context.Background(), share.ID); err != nil {
+				return errCmd("failed to revoke share", err)
+			}
+			return infoCmd(fmt.Sprintf("access of %s to secret %s revoked", share.RecipientLogin, secret.Title))
+		}
+		return errCmd("failed to revoke share", fmt.Errorf("secret %s is not shared with %s", secret.Title, login))
+	})
+}
+
+func (s *BrowseStorageScreen) handleDownload(msg savePathMsg) tea.Cmd {
+	return tui.SetBodyPane(tui.TransferScreen, tui.WithTransfer(&tui.Transfer{
+		Title: fmt.Sprintf("Downloading %s", msg.secret.Title),
+		Run: func(ctx context.Context, progress func(done, total int64)) error {
+			return s.storage.DownloadFile(ctx, msg.secret, msg.path, progress)
+		},
+		Done: func(err error) tea.Cmd {
+			result := infoCmd("file saved successfully")
+			if err != nil {
+				result = errCmd("failed to save file", err)
+			}
+			return tea.Batch(tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(s.storage)), result)
+		},
+	}))
 }
 
 func errCmd(msg string, err error) tea.Cmd {
@@ -213,6 +597,14 @@ func (s *BrowseStorageScreen) loadSecret(rawID string) (*models.Secret, error) {
 		return nil, err
 	}
 
+	if s.sharedView {
+		sec, ok := s.shared[id]
+		if !ok {
+			return nil, fmt.Errorf("shared secret %d not found", id)
+		}
+		return sec, nil
+	}
+
 	sec, err := s.storage.Get(context.Background(), id)
 	if err != nil {
 		return nil, err
@@ -246,12 +638,6 @@ func (s *BrowseStorageScreen) colsWidth() int {
 	return total
 }
 
-func sortSecrets(secrets []*models.Secret) {
-	sort.Slice(secrets, func(i, j int) bool {
-		return secrets[i].UpdatedAt.After(secrets[j].UpdatedAt)
-	})
-}
-
 func prepareTable() table.Model {
 	columns := []table.Column{
 		{Title: "id", Width: 5},
@@ -1,16 +1,19 @@
 package storage
 
 import (
+	"beliaev-aa/GophKeeper/internal/client/crypto"
 	"beliaev-aa/GophKeeper/internal/client/grpc"
+	"beliaev-aa/GophKeeper/internal/client/storage"
 	"beliaev-aa/GophKeeper/internal/client/tui"
 	"beliaev-aa/GophKeeper/pkg/models"
 	"beliaev-aa/GophKeeper/tests/mocks"
+	"context"
+	"errors"
 	"fmt"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
-	"sort"
 	"strconv"
 	"strings"
 	"testing"
@@ -22,13 +25,13 @@ func Test_BrowseStorageScreen_Init(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockStorage := mocks.NewMockStorage(ctrl)
-	mockStorage.EXPECT().GetAll(gomock.Any()).Return([]*models.Secret{}, nil).AnyTimes()
+	mockStorage.EXPECT().List(gomock.Any(), gomock.Any()).Return(&models.SecretsPage{}, nil).AnyTimes()
 
 	screen := NewStorageBrowseScreenScreen(mockStorage)
 	cmd := screen.Init()
 
-	if cmd != nil {
-		t.Errorf("Init should not return a command")
+	if cmd == nil {
+		t.Errorf("Init should open the folders sidebar")
 	}
 
 	if len(screen.table.Rows()) != 0 {
@@ -41,7 +44,7 @@ func Test_BrowseStorageScreen_View(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockStorage := mocks.NewMockStorage(ctrl)
-	mockStorage.EXPECT().GetAll(gomock.Any()).Return([]*models.Secret{}, nil).AnyTimes()
+	mockStorage.EXPECT().List(gomock.Any(), gomock.Any()).Return(&models.SecretsPage{}, nil).AnyTimes()
 	mockStorage.EXPECT().String().AnyTimes()
 	screen := NewStorageBrowseScreenScreen(mockStorage)
 
@@ -60,26 +63,20 @@ func Test_BrowseStorageScreen_updateRows(t *testing.T) {
 	now := time.Now()
 	earlier := now.Add(-time.Hour)
 	secrets := []*models.Secret{
-		{ID: 2, Title: "Second", SecretType: "Text", CreatedAt: earlier, UpdatedAt: earlier},
 		{ID: 1, Title: "First", SecretType: "Card", CreatedAt: now, UpdatedAt: now},
+		{ID: 2, Title: "Second", SecretType: "Text", CreatedAt: earlier, UpdatedAt: earlier},
 	}
 
-	mockStorage.EXPECT().GetAll(gomock.Any()).Return(secrets, nil).AnyTimes()
+	mockStorage.EXPECT().List(gomock.Any(), &models.SecretListQuery{}).Return(&models.SecretsPage{Secrets: secrets}, nil).AnyTimes()
 
 	screen := NewStorageBrowseScreenScreen(mockStorage)
 	screen.updateRows()
 
-	sortedSecrets := make([]*models.Secret, len(secrets))
-	copy(sortedSecrets, secrets)
-	sort.Slice(sortedSecrets, func(i, j int) bool {
-		return sortedSecrets[i].UpdatedAt.After(sortedSecrets[j].UpdatedAt)
-	})
-
-	if len(screen.table.Rows()) != len(sortedSecrets) {
-		t.Fatalf("Expected %d rows in table, got %d", len(sortedSecrets), len(screen.table.Rows()))
+	if len(screen.table.Rows()) != len(secrets) {
+		t.Fatalf("Expected %d rows in table, got %d", len(secrets), len(screen.table.Rows()))
 	}
 
-	for i, sec := range sortedSecrets {
+	for i, sec := range secrets {
 		row := screen.table.Rows()[i]
 		if row[0] != strconv.Itoa(int(sec.ID)) || row[1] != sec.Title ||
 			row[2] != sec.SecretType || row[3] != sec.CreatedAt.Format("02 Jan 06 15:04") ||
@@ -89,6 +86,252 @@ func Test_BrowseStorageScreen_updateRows(t *testing.T) {
 	}
 }
 
+func Test_BrowseStorageScreen_LoadsNextPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	gomock.InOrder(
+		mockStorage.EXPECT().List(gomock.Any(), &models.SecretListQuery{}).Return(&models.SecretsPage{
+			Secrets:       []*models.Secret{{ID: 1, Title: "First"}, {ID: 2, Title: "Second"}},
+			NextPageToken: "next",
+		}, nil),
+		mockStorage.EXPECT().List(gomock.Any(), &models.SecretListQuery{PageToken: "next"}).Return(&models.SecretsPage{
+			Secrets: []*models.Secret{{ID: 3, Title: "Third"}},
+		}, nil),
+	)
+
+	screen := NewStorageBrowseScreenScreen(mockStorage)
+	screen.Update(tea.KeyMsg{Type: tea.KeyDown})
+
+	rows := screen.table.Rows()
+	if len(rows) != 3 || rows[2][0] != "3" {
+		t.Fatalf("Expected next page appended to the table, got %v", rows)
+	}
+	if screen.nextPageToken != "" {
+		t.Errorf("Expected no next page token after the last page, got %q", screen.nextPageToken)
+	}
+
+	screen.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if len(screen.table.Rows()) != 3 {
+		t.Errorf("Expected no more pages to be loaded, got %d rows", len(screen.table.Rows()))
+	}
+}
+
+func Test_BrowseStorageScreen_Filters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().String().AnyTimes()
+	gomock.InOrder(
+		mockStorage.EXPECT().List(gomock.Any(), &models.SecretListQuery{}).Return(&models.SecretsPage{}, nil),
+		mockStorage.EXPECT().List(gomock.Any(), &models.SecretListQuery{
+			SecretType: string(models.CredSecret),
+		}).Return(&models.SecretsPage{}, nil),
+		mockStorage.EXPECT().List(gomock.Any(), &models.SecretListQuery{
+			SecretType: string(models.CredSecret),
+			Order:      models.SecretOrderUpdatedAsc,
+		}).Return(&models.SecretsPage{}, nil),
+		mockStorage.EXPECT().List(gomock.Any(), &models.SecretListQuery{
+			SecretType: string(models.CredSecret),
+			Search:     "bank card",
+			Order:      models.SecretOrderUpdatedAsc,
+		}).Return(&models.SecretsPage{Secrets: []*models.Secret{{ID: 4, Title: "Bank"}}}, nil),
+	)
+
+	screen := NewStorageBrowseScreenScreen(mockStorage)
+	screen.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	screen.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+
+	if cmd := screen.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")}); cmd == nil {
+		t.Fatal("Expected a title search prompt")
+	}
+	screen.Update(searchMsg{words: "bank card"})
+
+	if len(screen.table.Rows()) != 1 || screen.table.Rows()[0][0] != "4" {
+		t.Errorf("Expected filtered rows, got %v", screen.table.Rows())
+	}
+
+	view := screen.View()
+	for _, expected := range []string{"bank card", string(models.CredSecret), "oldest first"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected view to show active filter %q", expected)
+		}
+	}
+}
+
+func Test_BrowseStorageScreen_SidebarFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	gomock.InOrder(
+		mockStorage.EXPECT().List(gomock.Any(), &models.SecretListQuery{}).Return(&models.SecretsPage{}, nil),
+		mockStorage.EXPECT().List(gomock.Any(), &models.SecretListQuery{
+			SecretFilter: models.SecretFilter{FolderID: 2},
+		}).Return(&models.SecretsPage{Secrets: []*models.Secret{{ID: 5, Title: "Card"}}}, nil),
+	)
+
+	screen := NewStorageBrowseScreenScreen(mockStorage)
+	screen.Update(tui.SecretFilterMsg{FolderID: 2})
+
+	if len(screen.table.Rows()) != 1 || screen.table.Rows()[0][0] != "5" {
+		t.Errorf("Expected rows of folder 2, got %v", screen.table.Rows())
+	}
+}
+
+func Test_BrowseStorageScreen_AssignLabel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	page := &models.SecretsPage{Secrets: []*models.Secret{{ID: 1, Title: "Bank"}}}
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().List(gomock.Any(), gomock.Any()).Return(page, nil).AnyTimes()
+
+	screen := NewStorageBrowseScreenScreen(mockStorage)
+
+	mockStorage.EXPECT().Get(gomock.Any(), uint64(1)).Return(&models.Secret{ID: 1, Title: "Bank", TagIDs: models.IDList{3}}, nil)
+	mockStorage.EXPECT().Update(gomock.Any(), &models.Secret{ID: 1, Title: "Bank", FolderID: 2, TagIDs: models.IDList{3}}).Return(nil)
+	if cmd := screen.Update(tui.AssignLabelMsg{FolderID: 2}); cmd == nil {
+		t.Error("Expected info about moved secret")
+	}
+
+	mockStorage.EXPECT().Get(gomock.Any(), uint64(1)).Return(&models.Secret{ID: 1, Title: "Bank", TagIDs: models.IDList{3}}, nil)
+	mockStorage.EXPECT().Update(gomock.Any(), &models.Secret{ID: 1, Title: "Bank", TagIDs: models.IDList{}}).Return(nil)
+	screen.Update(tui.AssignLabelMsg{TagID: 3})
+
+	mockStorage.EXPECT().Get(gomock.Any(), uint64(1)).Return(&models.Secret{ID: 1, Title: "Bank"}, nil)
+	mockStorage.EXPECT().Update(gomock.Any(), &models.Secret{ID: 1, Title: "Bank", TagIDs: models.IDList{4}}).Return(fmt.Errorf("unavailable"))
+	screen.Update(tui.AssignLabelMsg{TagID: 4})
+}
+
+func Test_BrowseStorageScreen_SharedWithMe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().List(gomock.Any(), gomock.Any()).Return(&models.SecretsPage{}, nil).AnyTimes()
+	screen := NewStorageBrowseScreenScreen(mockStorage)
+
+	shared := []*models.Secret{
+		{ID: 7, Title: "Bank card", SecretType: string(models.CardSecret), Share: &models.Share{ID: 3, OwnerLogin: "alice"}},
+		{ID: 8, Title: "Wifi", SecretType: string(models.TextSecret), Share: &models.Share{ID: 4, OwnerLogin: "bob", CanEdit: true}},
+	}
+	mockStorage.EXPECT().GetSharedWithMe(gomock.Any()).Return(shared, nil).AnyTimes()
+
+	screen.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	rows := screen.table.Rows()
+	if len(rows) != 2 || rows[0][1] != "Bank card (alice, read-only)" || rows[1][1] != "Wifi (bob, editable)" {
+		t.Fatalf("Expected shared secrets with owners, got %v", rows)
+	}
+
+	screen.Update(searchMsg{words: "wifi"})
+	if rows = screen.table.Rows(); len(rows) != 1 || rows[0][0] != "8" {
+		t.Fatalf("Expected search to keep Wifi, got %v", rows)
+	}
+
+	if msg := screen.handleHistory()(); !strings.Contains(fmt.Sprint(msg), errSharedSecret.Error()) {
+		t.Errorf("Expected history to be unavailable, got %v", msg)
+	}
+
+	mockStorage.EXPECT().RevokeShare(gomock.Any(), uint64(4)).Return(nil)
+	prompt, ok := screen.handleDelete()().(tui.PromptMsg)
+	if !ok || !strings.Contains(prompt.Prompt, "stop receiving Wifi from bob?") {
+		t.Fatalf("Expected confirmation prompt, got %v", prompt)
+	}
+	if _, ok = prompt.Action("y")().(shareRevokedMsg); !ok {
+		t.Error("Expected shared secret to be removed")
+	}
+
+	screen.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	if screen.sharedView {
+		t.Error("Expected own secrets after second toggle")
+	}
+}
+
+func Test_BrowseStorageScreen_handleShare(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	secret := &models.Secret{ID: 1, Title: "Mail"}
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().List(gomock.Any(), gomock.Any()).Return(&models.SecretsPage{Secrets: []*models.Secret{secret}}, nil).AnyTimes()
+	mockStorage.EXPECT().Get(gomock.Any(), uint64(1)).Return(secret, nil).AnyTimes()
+	screen := NewStorageBrowseScreenScreen(mockStorage)
+
+	login := screen.handleShare()().(tui.PromptMsg)
+	fingerprint := login.Action("bob")().(tui.PromptMsg)
+	if !strings.Contains(fingerprint.Prompt, "fingerprint of bob") {
+		t.Errorf("Expected fingerprint prompt, got %q", fingerprint.Prompt)
+	}
+	access := fingerprint.Action("ab12 cd34")().(tui.PromptMsg)
+	if msg := access.Action("admin")(); !strings.Contains(fmt.Sprint(msg), "unknown access") {
+		t.Errorf("Expected unknown access error, got %v", msg)
+	}
+
+	mockStorage.EXPECT().ShareSecret(gomock.Any(), secret, "bob", "ab12 cd34", false).Return(crypto.ErrFingerprintMismatch)
+	if msg := access.Action("view")(); !strings.Contains(fmt.Sprint(msg), "fingerprint mismatch") {
+		t.Errorf("Expected fingerprint mismatch error, got %v", msg)
+	}
+
+	mockStorage.EXPECT().ShareSecret(gomock.Any(), secret, "bob", "ab12 cd34", true).Return(nil)
+	if msg, ok := access.Action("edit")().(tui.InfoMsg); !ok || !strings.Contains(string(msg), "shared with bob") {
+		t.Errorf("Expected share info, got %v", msg)
+	}
+
+	mockStorage.EXPECT().GetShares(gomock.Any(), uint64(1)).Return([]*models.Share{{ID: 5, RecipientLogin: "bob"}}, nil)
+	revoke := screen.handleUnshare()().(tui.PromptMsg)
+	if !strings.Contains(revoke.Prompt, "shared with bob") {
+		t.Errorf("Expected recipients in prompt, got %q", revoke.Prompt)
+	}
+	if msg := revoke.Action("carol")(); !strings.Contains(fmt.Sprint(msg), "is not shared with carol") {
+		t.Errorf("Expected unknown recipient error, got %v", msg)
+	}
+	mockStorage.EXPECT().RevokeShare(gomock.Any(), uint64(5)).Return(nil)
+	if msg, ok := revoke.Action("Bob")().(tui.InfoMsg); !ok || !strings.Contains(string(msg), "revoked") {
+		t.Errorf("Expected revoke info, got %v", msg)
+	}
+}
+
+func Test_BrowseStorageScreen_handleFingerprint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ownerKey := []byte("owner public key")
+	shared := &models.Secret{ID: 2, Title: "Wifi", Share: &models.Share{ID: 5, OwnerLogin: "alice", OwnerPublicKey: ownerKey}}
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().List(gomock.Any(), gomock.Any()).Return(&models.SecretsPage{}, nil).AnyTimes()
+	screen := NewStorageBrowseScreenScreen(mockStorage)
+
+	mockStorage.EXPECT().Fingerprint().Return("", storage.ErrVaultLocked)
+	if msg := screen.handleFingerprint()(); !strings.Contains(fmt.Sprint(msg), "vault is locked") {
+		t.Errorf("Expected vault locked error, got %v", msg)
+	}
+
+	mockStorage.EXPECT().Fingerprint().Return("ab12 cd34", nil)
+	if msg, ok := screen.handleFingerprint()().(tui.InfoMsg); !ok || !strings.Contains(string(msg), "your key fingerprint: ab12 cd34") {
+		t.Errorf("Expected own fingerprint, got %v", msg)
+	}
+
+	mockStorage.EXPECT().GetSharedWithMe(gomock.Any()).Return([]*models.Secret{shared}, nil)
+	screen.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	expected := "key fingerprint of alice: " + crypto.Fingerprint(ownerKey)
+	if msg, ok := screen.handleFingerprint()().(tui.InfoMsg); !ok || string(msg) != expected {
+		t.Errorf("Expected %q, got %v", expected, msg)
+	}
+}
+
+func Test_nextSecretTypeFilter(t *testing.T) {
+	current := models.SecretType("")
+	for _, expected := range []models.SecretType{models.CredSecret, models.TextSecret, models.BlobSecret, models.CardSecret, ""} {
+		current = nextSecretTypeFilter(current)
+		if current != expected {
+			t.Errorf("Expected filter %q, got %q", expected, current)
+		}
+	}
+}
+
 func Test_BrowseStorageScreen_HelpBindings(t *testing.T) {
 	screen := BrowseStorageScreen{}
 	bindings := screen.HelpBindings()
@@ -101,6 +344,19 @@ func Test_BrowseStorageScreen_HelpBindings(t *testing.T) {
 		{[]string{"e"}, "edit secret"},
 		{[]string{"d"}, "delete secret"},
 		{[]string{"c"}, "copy/save secret"},
+		{[]string{"h"}, "secret history"},
+		{[]string{"s"}, "share secret"},
+		{[]string{"u"}, "revoke share"},
+		{[]string{"S"}, "shared with me"},
+		{[]string{"K"}, "key fingerprint"},
+		{[]string{"t"}, "trash"},
+		{[]string{"O"}, "organizations"},
+		{[]string{"E"}, "emergency access"},
+		{[]string{"/"}, "search by title"},
+		{[]string{"f"}, "filter by type"},
+		{[]string{"o"}, "change order"},
+		{[]string{"p"}, "change master password"},
+		{[]string{"X"}, "delete account"},
 	}
 
 	if len(bindings) != len(expectedBindings) {
@@ -147,7 +403,7 @@ func Test_BrowseStorageScreen_handleEdit(t *testing.T) {
 			name: "Successful_Edit",
 			mockSetup: func() {
 				mockStorage.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&models.Secret{ID: 1, SecretType: string(models.TextSecret)}, nil).Times(1)
-				mockStorage.EXPECT().GetAll(gomock.Any()).Return([]*models.Secret{}, nil).AnyTimes()
+				mockStorage.EXPECT().List(gomock.Any(), gomock.Any()).Return(&models.SecretsPage{}, nil).AnyTimes()
 			},
 			expectedErrMsg: "",
 		},
@@ -155,7 +411,7 @@ func Test_BrowseStorageScreen_handleEdit(t *testing.T) {
 			name: "getSelectedSecret_Failure",
 			mockSetup: func() {
 				mockStorage.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("failed to load secret")).Times(1)
-				mockStorage.EXPECT().GetAll(gomock.Any()).Return([]*models.Secret{}, nil).AnyTimes()
+				mockStorage.EXPECT().List(gomock.Any(), gomock.Any()).Return(&models.SecretsPage{}, nil).AnyTimes()
 			},
 			expectedErrMsg: "failed to load secret",
 		},
@@ -164,7 +420,7 @@ func Test_BrowseStorageScreen_handleEdit(t *testing.T) {
 			mockSetup: func() {
 				mockStorage.EXPECT().Get(gomock.Any(), gomock.Any()).Return(
 					&models.Secret{ID: 1, SecretType: "UnknownSecretType"}, nil).Times(1)
-				mockStorage.EXPECT().GetAll(gomock.Any()).Return([]*models.Secret{}, nil).AnyTimes()
+				mockStorage.EXPECT().List(gomock.Any(), gomock.Any()).Return(&models.SecretsPage{}, nil).AnyTimes()
 			},
 			expectedErrMsg: "failed to get screen",
 		},
@@ -218,7 +474,7 @@ func Test_BrowseStorageScreen_handleCopy(t *testing.T) {
 					ID:         2,
 					SecretType: string(models.BlobSecret),
 				}, nil).Times(1)
-				mockStorage.EXPECT().GetAll(gomock.Any()).Return([]*models.Secret{}, nil).AnyTimes()
+				mockStorage.EXPECT().List(gomock.Any(), gomock.Any()).Return(&models.SecretsPage{}, nil).AnyTimes()
 			},
 			expectedErrMsg: "",
 			expectedMsg:    "choose path to save",
@@ -228,7 +484,7 @@ func Test_BrowseStorageScreen_handleCopy(t *testing.T) {
 			name: "getSelectedSecret_Failure",
 			mockSetup: func() {
 				mockStorage.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("failed to load secret")).Times(1)
-				mockStorage.EXPECT().GetAll(gomock.Any()).Return([]*models.Secret{}, nil).AnyTimes()
+				mockStorage.EXPECT().List(gomock.Any(), gomock.Any()).Return(&models.SecretsPage{}, nil).AnyTimes()
 			},
 			expectedErrMsg: "failed to load secret",
 			expectedMsg:    "",
@@ -301,19 +557,20 @@ func Test_BrowseStorageScreen_handleDelete(t *testing.T) {
 			name: "Successful_Delete",
 			mockSetup: func() {
 				mockStorage.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&models.Secret{
-					ID: 1,
+					ID:    1,
+					Title: "Mail",
 				}, nil).Times(1)
 				mockStorage.EXPECT().Delete(gomock.Any(), uint64(1)).Return(nil).Times(1)
-				mockStorage.EXPECT().GetAll(gomock.Any()).Return([]*models.Secret{}, nil).AnyTimes()
+				mockStorage.EXPECT().List(gomock.Any(), gomock.Any()).Return(&models.SecretsPage{}, nil).AnyTimes()
 			},
 			expectedErrMsg: "",
-			expectedCmdMsg: "secret deleted",
+			expectedCmdMsg: "secret Mail moved to trash",
 		},
 		{
 			name: "getSelectedSecret_Failure",
 			mockSetup: func() {
 				mockStorage.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("failed to load secret")).Times(1)
-				mockStorage.EXPECT().GetAll(gomock.Any()).Return([]*models.Secret{}, nil).AnyTimes()
+				mockStorage.EXPECT().List(gomock.Any(), gomock.Any()).Return(&models.SecretsPage{}, nil).AnyTimes()
 			},
 			expectedErrMsg: "failed to load secret",
 			expectedCmdMsg: "",
@@ -325,7 +582,7 @@ func Test_BrowseStorageScreen_handleDelete(t *testing.T) {
 					ID: 2,
 				}, nil).Times(1)
 				mockStorage.EXPECT().Delete(gomock.Any(), uint64(2)).Return(fmt.Errorf("delete failed")).Times(1)
-				mockStorage.EXPECT().GetAll(gomock.Any()).Return([]*models.Secret{}, nil).AnyTimes()
+				mockStorage.EXPECT().List(gomock.Any(), gomock.Any()).Return(&models.SecretsPage{}, nil).AnyTimes()
 			},
 			expectedErrMsg: "failed to delete secret",
 			expectedCmdMsg: "",
@@ -344,10 +601,18 @@ func Test_BrowseStorageScreen_handleDelete(t *testing.T) {
 				t.Fatal("Expected a command to be returned")
 			}
 
+			msg := cmd()
+			if prompt, ok := msg.(tui.PromptMsg); ok {
+				if !strings.Contains(prompt.Prompt, "to trash?") {
+					t.Errorf("Expected confirmation prompt, got '%s'", prompt.Prompt)
+				}
+				msg = prompt.Action("y")()
+			}
+
 			if tc.expectedErrMsg != "" {
-				err, ok := cmd().(error)
+				err, ok := msg.(error)
 				if !ok {
-					t.Fatalf("Expected an error, got %T", cmd())
+					t.Fatalf("Expected an error, got %T", msg)
 				}
 				if !strings.Contains(err.Error(), tc.expectedErrMsg) {
 					t.Errorf("Expected error containing '%s', got '%s'", tc.expectedErrMsg, err.Error())
@@ -356,12 +621,18 @@ func Test_BrowseStorageScreen_handleDelete(t *testing.T) {
 			}
 
 			if tc.expectedCmdMsg != "" {
-				msg, ok := cmd().(tui.InfoMsg)
+				deleted, ok := msg.(secretDeletedMsg)
 				if !ok {
-					t.Fatalf("Expected a tui.InfoMsg, got %T", cmd())
+					t.Fatalf("Expected a secretDeletedMsg, got %T", msg)
 				}
-				if !strings.Contains(string(msg), tc.expectedCmdMsg) {
-					t.Errorf("Expected message containing '%s', got '%s'", tc.expectedCmdMsg, msg)
+				var info tui.InfoMsg
+				for _, m := range collectMsgs(screen.Update(deleted)) {
+					if i, ok := m.(tui.InfoMsg); ok {
+						info = i
+					}
+				}
+				if !strings.Contains(string(info), tc.expectedCmdMsg) {
+					t.Errorf("Expected message containing '%s', got '%s'", tc.expectedCmdMsg, info)
 				}
 			}
 		})
@@ -373,7 +644,7 @@ func Test_BrowseStorageScreen_Make(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockStorage := mocks.NewMockStorage(ctrl)
-	mockStorage.EXPECT().GetAll(gomock.Any()).Return([]*models.Secret{}, nil).AnyTimes()
+	mockStorage.EXPECT().List(gomock.Any(), gomock.Any()).Return(&models.SecretsPage{}, nil).AnyTimes()
 
 	msg := tui.NavigationMsg{Storage: mockStorage}
 	screen := &BrowseStorageScreen{}
@@ -453,7 +724,7 @@ func Test_BrowseStorageScreen_Update(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockStorage := mocks.NewMockStorage(ctrl)
-	mockStorage.EXPECT().GetAll(gomock.Any()).Return([]*models.Secret{}, nil).AnyTimes()
+	mockStorage.EXPECT().List(gomock.Any(), gomock.Any()).Return(&models.SecretsPage{}, nil).AnyTimes()
 
 	type testCase struct {
 		name           string
@@ -469,26 +740,60 @@ func Test_BrowseStorageScreen_Update(t *testing.T) {
 			name:    "Reload_Secret_List",
 			message: grpc.ReloadSecretList{},
 			mockSetup: func() {
-				mockStorage.EXPECT().GetAll(gomock.Any()).Return([]*models.Secret{}, nil).AnyTimes()
+				mockStorage.EXPECT().Organization().Return(nil)
+				mockStorage.EXPECT().List(gomock.Any(), gomock.Any()).Return(&models.SecretsPage{}, nil).AnyTimes()
 			},
 		},
 		{
-			name: "Save_Path_File_Error",
-			message: savePathMsg{
-				path:   "/invalid/path",
-				secret: &models.Secret{Blob: &models.Blob{FileBytes: []byte("test content")}},
+			name:    "Reload_Other_Organization",
+			message: grpc.ReloadSecretList{OrgID: 7},
+			mockSetup: func() {
+				mockStorage.EXPECT().Organization().Return(nil)
 			},
+		},
+		{
+			name:           "Key_Shift_O",
+			message:        tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("O")},
+			mockSetup:      func() {},
+			expectedScreen: tui.OrganizationsScreen,
+		},
+		{
+			name:           "Key_Shift_E",
+			message:        tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("E")},
 			mockSetup:      func() {},
-			expectedErrMsg: "no such file or directory",
+			expectedScreen: tui.EmergencyContactsScreen,
 		},
 		{
 			name:    "Key_A",
 			message: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")},
 			mockSetup: func() {
-				mockStorage.EXPECT().GetAll(gomock.Any()).Return([]*models.Secret{}, nil).AnyTimes()
+				mockStorage.EXPECT().List(gomock.Any(), gomock.Any()).Return(&models.SecretsPage{}, nil).AnyTimes()
 			},
 			expectedScreen: tui.SecretTypeScreen,
 		},
+		{
+			name:           "Key_P",
+			message:        tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")},
+			mockSetup:      func() {},
+			expectedScreen: tui.PasswordChangeScreen,
+		},
+		{
+			name:           "Key_Shift_X",
+			message:        tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("X")},
+			mockSetup:      func() {},
+			expectedScreen: tui.AccountDeleteScreen,
+		},
+		{
+			name:    "Key_H",
+			message: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")},
+			mockSetup: func() {
+				mockStorage.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&models.Secret{
+					ID:         1,
+					SecretType: string(models.TextSecret),
+				}, nil).Times(1)
+			},
+			expectedScreen: tui.SecretHistoryScreen,
+		},
 		{
 			name:    "Key_E_Enter",
 			message: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")},
@@ -539,3 +844,80 @@ func Test_BrowseStorageScreen_Update(t *testing.T) {
 		})
 	}
 }
+
+func Test_BrowseStorageScreen_Download(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().List(gomock.Any(), gomock.Any()).Return(&models.SecretsPage{}, nil).AnyTimes()
+
+	secret := &models.Secret{ID: 1, Title: "File", Blob: &models.Blob{BlobID: "blob"}}
+	screen := NewStorageBrowseScreenScreen(mockStorage)
+
+	var transfer *tui.Transfer
+	for _, msg := range collectMsgs(screen.Update(savePathMsg{path: "/tmp/file", secret: secret})) {
+		if nav, ok := msg.(tui.NavigationMsg); ok && nav.Page.Screen == tui.TransferScreen {
+			transfer = nav.Transfer
+		}
+	}
+	if transfer == nil {
+		t.Fatal("Expected navigation to the transfer screen")
+	}
+
+	mockStorage.EXPECT().DownloadFile(gomock.Any(), secret, "/tmp/file", gomock.Any()).Return(nil)
+	if err := transfer.Run(context.Background(), nil); err != nil {
+		t.Errorf("Unexpected transfer error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{name: "Success", err: nil, expected: "file saved successfully"},
+		{name: "Failure", err: errors.New("connection lost"), expected: "failed to save file: connection lost"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				text       string
+				toBrowsing bool
+			)
+			for _, msg := range collectMsgs(transfer.Done(tc.err)) {
+				switch v := msg.(type) {
+				case tui.NavigationMsg:
+					toBrowsing = v.Page.Screen == tui.StorageBrowseScreen
+				case tui.InfoMsg:
+					text = string(v)
+				case tui.ErrorMsg:
+					text = v.Error()
+				}
+			}
+
+			if !toBrowsing {
+				t.Errorf("Expected navigation back to the storage browser")
+			}
+			if text != tc.expected {
+				t.Errorf("Expected message '%s', got '%s'", tc.expected, text)
+			}
+		})
+	}
+}
+
+// collectMsgs выполняет команду и возвращает все полученные сообщения, раскрывая пакеты команд.
+func collectMsgs(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, c := range batch {
+			msgs = append(msgs, collectMsgs(c)...)
+		}
+		return msgs
+	}
+	return []tea.Msg{msg}
+}
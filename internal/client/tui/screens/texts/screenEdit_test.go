@@ -2,6 +2,7 @@ package texts
 
 import (
 	"beliaev-aa/GophKeeper/internal/client/tui"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
 	"beliaev-aa/GophKeeper/pkg/models"
 	"beliaev-aa/GophKeeper/tests/mocks"
 	"github.com/charmbracelet/bubbletea"
@@ -61,6 +62,23 @@ func Test_TextEditScreen(t *testing.T) {
 			},
 			expectedCmd: tui.StorageBrowseScreen,
 		},
+		{
+			name: "Submit_revision_conflict",
+			secret: &models.Secret{
+				ID:       1,
+				Title:    "Existing Title",
+				Metadata: "Existing Metadata",
+				Text:     &models.Text{Content: "Existing Content"},
+				Revision: 1,
+			},
+			mockSetup: func() {
+				mockStorage.EXPECT().Update(gomock.Any(), gomock.Any()).Return(&gophKeeperErrors.RevisionConflictError{Current: 2}).Times(1)
+			},
+			action: func(screen *TextEditScreen) tea.Cmd {
+				return screen.inputGroup.Buttons[0].Cmd()
+			},
+			expectedCmd: tui.SecretConflictScreen,
+		},
 		{
 			name: "Submit_invalid_title",
 			secret: &models.Secret{
@@ -130,8 +148,8 @@ func Test_TextEditScreen(t *testing.T) {
 				} else if tc.expectedCmd != noScreen {
 					switch v := cmd().(type) {
 					case tui.NavigationMsg:
-						if v.Screen != tc.expectedCmd {
-							t.Errorf("Expected screen %v, got %v", tc.expectedCmd, v.Screen)
+						if v.Page.Screen != tc.expectedCmd {
+							t.Errorf("Expected screen %v, got %v", tc.expectedCmd, v.Page.Screen)
 						}
 					default:
 						t.Fatalf("Unexpected command type: %T", v)
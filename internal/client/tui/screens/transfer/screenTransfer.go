@@ -0,0 +1,138 @@
+// Package transfer предоставляет экран выполнения длительной передачи файла с индикатором хода.
+package transfer
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/internal/client/tui/components"
+	"beliaev-aa/GophKeeper/internal/client/tui/screens"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbletea"
+	"strings"
+)
+
+// progressWidth - ширина индикатора хода передачи в символах.
+const progressWidth = 40
+
+// progressMsg сообщает о ходе передачи.
+type progressMsg struct {
+	events *events
+	done   int64
+	total  int64
+}
+
+// doneMsg сообщает о завершении передачи.
+type doneMsg struct {
+	events *events
+	err    error
+}
+
+// events связывает выполняемую в фоне передачу с экраном. Сообщения о ходе передачи
+// не накапливаются: если экран не успел обработать предыдущее, новое отбрасывается.
+type events struct {
+	progress chan progressMsg
+	done     chan doneMsg
+}
+
+// TransferScreen предоставляет модель экрана передачи файла. Передача выполняется в фоне,
+// экран отображает её ход и позволяет отменить её.
+type TransferScreen struct {
+	cancel   context.CancelFunc
+	events   *events
+	progress components.ProgressBar
+	transfer *tui.Transfer
+}
+
+// Make создает экран для передачи, переданной в сообщении навигации.
+func (s *TransferScreen) Make(msg tui.NavigationMsg, _, _ int) (tui.TeaLike, error) {
+	if msg.Transfer == nil {
+		return nil, errors.New("no transfer to run")
+	}
+	return NewTransferScreen(msg.Transfer), nil
+}
+
+// NewTransferScreen создает новый экран передачи файла.
+func NewTransferScreen(transfer *tui.Transfer) *TransferScreen {
+	return &TransferScreen{
+		events: &events{
+			progress: make(chan progressMsg, 1),
+			done:     make(chan doneMsg, 1),
+		},
+		progress: components.NewProgressBar(progressWidth),
+		transfer: transfer,
+	}
+}
+
+// Init запускает передачу в фоне и начинает ожидать сообщения о её ходе.
+func (s *TransferScreen) Init() tea.Cmd {
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+
+	go func() {
+		err := s.transfer.Run(ctx, func(done, total int64) {
+			select {
+			case s.events.progress <- progressMsg{events: s.events, done: done, total: total}:
+			default:
+			}
+		})
+		s.events.done <- doneMsg{events: s.events, err: err}
+	}()
+
+	return s.waitForEvent()
+}
+
+// Update обновляет состояние экрана в ответ на сообщения.
+func (s *TransferScreen) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case progressMsg:
+		if msg.events != s.events {
+			return nil
+		}
+		s.progress.Set(msg.done, msg.total)
+		return s.waitForEvent()
+	case doneMsg:
+		if msg.events != s.events {
+			return nil
+		}
+		s.cancel()
+		return s.transfer.Done(msg.err)
+	case tea.KeyMsg:
+		if msg.String() == "c" && s.cancel != nil {
+			s.cancel()
+		}
+	}
+
+	return nil
+}
+
+// View отображает ход передачи.
+func (s *TransferScreen) View() string {
+	var b strings.Builder
+
+	b.WriteString(s.progress.View())
+	b.WriteString("\n\nPress c to cancel")
+
+	return screens.RenderContent(fmt.Sprintf("%s:", s.transfer.Title), b.String())
+}
+
+// HelpBindings возвращает набор горячих клавиш для экрана.
+func (s *TransferScreen) HelpBindings() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "cancel transfer")),
+	}
+}
+
+// waitForEvent возвращает команду, ожидающую следующее сообщение о передаче.
+func (s *TransferScreen) waitForEvent() tea.Cmd {
+	ev := s.events
+	return func() tea.Msg {
+		select {
+		case msg := <-ev.progress:
+			return msg
+		case msg := <-ev.done:
+			return msg
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package transfer
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	"context"
+	"errors"
+	"github.com/charmbracelet/bubbletea"
+	"strings"
+	"testing"
+)
+
+// runUntilDone обрабатывает сообщения экрана до завершения передачи и возвращает результат передачи.
+func runUntilDone(t *testing.T, screen *TransferScreen, cmd tea.Cmd) tea.Cmd {
+	t.Helper()
+
+	for i := 0; i < 100; i++ {
+		msg := cmd()
+		next := screen.Update(msg)
+		if _, ok := msg.(doneMsg); ok {
+			return next
+		}
+		cmd = next
+	}
+
+	t.Fatalf("transfer did not finish")
+	return nil
+}
+
+func Test_TransferScreen_Make(t *testing.T) {
+	maker := &TransferScreen{}
+
+	if _, err := maker.Make(tui.NavigationMsg{}, 0, 0); err == nil {
+		t.Errorf("expected error without transfer")
+	}
+
+	result, err := maker.Make(tui.NavigationMsg{Transfer: &tui.Transfer{Title: "Uploading"}}, 0, 0)
+	if err != nil {
+		t.Fatalf("Make returned an error: %v", err)
+	}
+	if _, ok := result.(*TransferScreen); !ok {
+		t.Errorf("Expected result to be *TransferScreen, got %T", result)
+	}
+}
+
+func Test_TransferScreen_Run(t *testing.T) {
+	var result error
+	screen := NewTransferScreen(&tui.Transfer{
+		Title: "Uploading file",
+		Run: func(_ context.Context, progress func(done, total int64)) error {
+			progress(50, 100)
+			return nil
+		},
+		Done: func(err error) tea.Cmd {
+			result = err
+			return tui.ReportInfo("done")
+		},
+	})
+
+	cmd := runUntilDone(t, screen, screen.Init())
+	if result != nil {
+		t.Errorf("unexpected transfer error: %v", result)
+	}
+	if msg, ok := cmd().(tui.InfoMsg); !ok || msg != "done" {
+		t.Errorf("expected done info message, got %v", msg)
+	}
+	if !strings.Contains(screen.View(), "Uploading file") {
+		t.Errorf("view does not contain transfer title")
+	}
+}
+
+func Test_TransferScreen_Cancel(t *testing.T) {
+	started := make(chan struct{})
+	var result error
+	screen := NewTransferScreen(&tui.Transfer{
+		Title: "Downloading file",
+		Run: func(ctx context.Context, _ func(done, total int64)) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+		Done: func(err error) tea.Cmd {
+			result = err
+			return nil
+		},
+	})
+
+	cmd := screen.Init()
+	<-started
+	screen.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	runUntilDone(t, screen, cmd)
+
+	if !errors.Is(result, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", result)
+	}
+}
+
+func Test_TransferScreen_IgnoresStaleMessages(t *testing.T) {
+	screen := NewTransferScreen(&tui.Transfer{Title: "Uploading"})
+	stale := &events{}
+
+	if cmd := screen.Update(progressMsg{events: stale, done: 10, total: 10}); cmd != nil {
+		t.Errorf("expected stale progress to be ignored")
+	}
+	if cmd := screen.Update(doneMsg{events: stale}); cmd != nil {
+		t.Errorf("expected stale completion to be ignored")
+	}
+	if screen.progress.Done != 0 {
+		t.Errorf("stale progress changed the progress bar")
+	}
+}
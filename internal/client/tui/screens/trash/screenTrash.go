@@ -0,0 +1,203 @@
+// Package trash предоставляет экран корзины: просмотр удалённых секретов, их восстановление и окончательное удаление.
+package trash
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/storage"
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/internal/client/tui/styles"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbletea"
+	"strconv"
+	"strings"
+)
+
+const (
+	tableBorderSize = 4
+)
+
+// purgedMsg сообщает об окончательном удалении секрета из корзины.
+type purgedMsg struct {
+	title string
+}
+
+// TrashScreen предоставляет модель экрана корзины.
+type TrashScreen struct {
+	err     error
+	secrets []*models.Secret
+	storage storage.Storage
+	table   table.Model
+}
+
+// Make создает экран корзины для хранилища, переданного в сообщении навигации.
+func (s *TrashScreen) Make(msg tui.NavigationMsg, _, _ int) (tui.TeaLike, error) {
+	return NewTrashScreen(msg.Storage), nil
+}
+
+// NewTrashScreen создает новый экран корзины и загружает удалённые секреты.
+func NewTrashScreen(store storage.Storage) *TrashScreen {
+	scr := &TrashScreen{
+		storage: store,
+		table:   prepareTable(),
+	}
+
+	scr.updateRows()
+
+	return scr
+}
+
+// Init инициализирует экран и сообщает об ошибке загрузки корзины.
+func (s *TrashScreen) Init() tea.Cmd {
+	if s.err != nil {
+		return tui.ReportError(fmt.Errorf("failed to load trash: %w", s.err))
+	}
+	return nil
+}
+
+// Update обновляет состояние экрана в ответ на сообщения.
+func (s *TrashScreen) Update(msg tea.Msg) tea.Cmd {
+	var (
+		cmd      tea.Cmd
+		commands []tea.Cmd
+	)
+
+	switch msg := msg.(type) {
+	case purgedMsg:
+		s.updateRows()
+		commands = append(commands, tui.ReportInfo("secret %s deleted permanently", msg.title))
+	case tea.WindowSizeMsg:
+		s.table.SetWidth(min(msg.Width, s.colsWidth()))
+		s.table.SetHeight(max(msg.Height-tableBorderSize, 1))
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "r":
+			commands = append(commands, s.handleRestore())
+		case "x":
+			commands = append(commands, s.handlePurge())
+		case "b":
+			commands = append(commands, tui.SetBodyPane(tui.StorageBrowseScreen, tui.WithStorage(s.storage)))
+		}
+	}
+
+	s.table.Focus()
+	s.table, cmd = s.table.Update(msg)
+	commands = append(commands, cmd)
+
+	return tea.Batch(commands...)
+}
+
+// View отображает содержимое корзины.
+func (s *TrashScreen) View() string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("Trash of %s\n", styles.Highlighted.Render(s.storage.String())))
+	b.WriteString("Use ↑↓ to navigate, restore[r], delete permanently[x], back[b]\n")
+
+	if len(s.secrets) == 0 {
+		b.WriteString("\nTrash is empty\n")
+		return styles.StorageScreenStyle.Render(b.String())
+	}
+
+	b.WriteString(styles.TableStyle.Render(s.table.View()))
+
+	return styles.StorageScreenStyle.Render(b.String())
+}
+
+// HelpBindings возвращает набор горячих клавиш для экрана.
+func (s *TrashScreen) HelpBindings() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "restore secret")),
+		key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "delete permanently")),
+		key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "back")),
+	}
+}
+
+func (s *TrashScreen) updateRows() {
+	s.secrets, s.err = s.storage.GetTrash(context.Background())
+
+	var rows []table.Row
+	for _, sec := range s.secrets {
+		deleted := ""
+		if sec.DeletedAt != nil {
+			deleted = sec.DeletedAt.Format("02 Jan 06 15:04")
+		}
+		rows = append(rows, table.Row{
+			strconv.FormatUint(sec.ID, 10),
+			sec.Title,
+			sec.SecretType,
+			deleted,
+		})
+	}
+
+	s.table.SetRows(rows)
+}
+
+func (s *TrashScreen) handleRestore() tea.Cmd {
+	secret := s.selectedSecret()
+	if secret == nil {
+		return tui.ReportError(fmt.Errorf("no secret selected"))
+	}
+
+	if err := s.storage.Restore(context.Background(), secret.ID); err != nil {
+		return tui.ReportError(fmt.Errorf("failed to restore secret: %w", err))
+	}
+
+	s.updateRows()
+
+	return tui.ReportInfo("secret %s restored", secret.Title)
+}
+
+func (s *TrashScreen) handlePurge() tea.Cmd {
+	secret := s.selectedSecret()
+	if secret == nil {
+		return tui.ReportError(fmt.Errorf("no secret selected"))
+	}
+
+	return tui.YesNoPrompt(fmt.Sprintf("delete %s permanently? this cannot be undone", secret.Title), func() tea.Msg {
+		if err := s.storage.Purge(context.Background(), secret.ID); err != nil {
+			return tui.ErrorMsg(fmt.Errorf("failed to delete secret: %w", err))
+		}
+		return purgedMsg{title: secret.Title}
+	})
+}
+
+func (s *TrashScreen) selectedSecret() *models.Secret {
+	cursor := s.table.Cursor()
+	if cursor < 0 || cursor >= len(s.secrets) {
+		return nil
+	}
+	return s.secrets[cursor]
+}
+
+func (s *TrashScreen) colsWidth() int {
+	total := tableBorderSize
+	for _, c := range s.table.Columns() {
+		total += c.Width
+	}
+
+	return total
+}
+
+func prepareTable() table.Model {
+	columns := []table.Column{
+		{Title: "id", Width: 5},
+		{Title: "Title", Width: 20},
+		{Title: "Secret Type", Width: 20},
+		{Title: "Deleted", Width: 20},
+	}
+
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithFocused(true),
+	)
+
+	st := table.DefaultStyles()
+	st.Header = styles.TableHeaderStyle
+	st.Selected = styles.TableSelectedStyle
+	t.SetStyles(st)
+
+	return t
+}
@@ -0,0 +1,203 @@
+package trash
+
+import (
+	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"errors"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/golang/mock/gomock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testTrash() []*models.Secret {
+	deletedAt := time.Now()
+	return []*models.Secret{
+		{ID: 3, Title: "Old note", SecretType: string(models.TextSecret), DeletedAt: &deletedAt},
+		{ID: 2, Title: "Old card", SecretType: string(models.CardSecret), DeletedAt: &deletedAt},
+	}
+}
+
+func Test_TrashScreen_Make(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().GetTrash(gomock.Any()).Return(testTrash(), nil)
+
+	maker := &TrashScreen{}
+	result, err := maker.Make(tui.NavigationMsg{Storage: mockStorage}, 0, 0)
+	if err != nil {
+		t.Errorf("Make returned an error: %v", err)
+	}
+
+	screen, ok := result.(*TrashScreen)
+	if !ok {
+		t.Fatalf("Expected result to be *TrashScreen, got %T", result)
+	}
+	if len(screen.table.Rows()) != 2 || screen.table.Rows()[0][0] != "3" {
+		t.Errorf("Unexpected rows: %v", screen.table.Rows())
+	}
+}
+
+func Test_TrashScreen_Init(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().GetTrash(gomock.Any()).Return(nil, errors.New("decrypt failed"))
+
+	screen := NewTrashScreen(mockStorage)
+	cmd := screen.Init()
+	if cmd == nil {
+		t.Fatal("Expected Init to report the load error")
+	}
+	if msg, ok := cmd().(tui.ErrorMsg); !ok || !strings.Contains(msg.Error(), "decrypt failed") {
+		t.Errorf("Expected error message, got %#v", cmd())
+	}
+}
+
+func Test_TrashScreen_View(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().String().Return("remote storage").AnyTimes()
+	mockStorage.EXPECT().GetTrash(gomock.Any()).Return(testTrash(), nil)
+	mockStorage.EXPECT().GetTrash(gomock.Any()).Return(nil, nil)
+
+	screen := NewTrashScreen(mockStorage)
+	if view := screen.View(); !strings.Contains(view, "Old note") {
+		t.Errorf("View does not contain trashed secrets, got %q", view)
+	}
+
+	empty := NewTrashScreen(mockStorage)
+	if !strings.Contains(empty.View(), "Trash is empty") {
+		t.Errorf("View does not report empty trash")
+	}
+}
+
+func Test_TrashScreen_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		key            string
+		confirm        bool
+		mockSetup      func(mockStorage *mocks.MockStorage)
+		navigates      bool
+		expectedScreen tui.Screen
+		expectedInfo   string
+		expectErr      bool
+	}{
+		{
+			name: "Restore",
+			key:  "r",
+			mockSetup: func(mockStorage *mocks.MockStorage) {
+				mockStorage.EXPECT().Restore(gomock.Any(), uint64(3)).Return(nil)
+				mockStorage.EXPECT().GetTrash(gomock.Any()).Return(testTrash()[1:], nil)
+			},
+			expectedInfo: "secret Old note restored",
+		},
+		{
+			name: "Restore_Error",
+			key:  "r",
+			mockSetup: func(mockStorage *mocks.MockStorage) {
+				mockStorage.EXPECT().Restore(gomock.Any(), uint64(3)).Return(errors.New("not found"))
+			},
+			expectErr: true,
+		},
+		{
+			name:    "Purge",
+			key:     "x",
+			confirm: true,
+			mockSetup: func(mockStorage *mocks.MockStorage) {
+				mockStorage.EXPECT().Purge(gomock.Any(), uint64(3)).Return(nil)
+				mockStorage.EXPECT().GetTrash(gomock.Any()).Return(testTrash()[1:], nil)
+			},
+			expectedInfo: "secret Old note deleted permanently",
+		},
+		{
+			name:    "Purge_Error",
+			key:     "x",
+			confirm: true,
+			mockSetup: func(mockStorage *mocks.MockStorage) {
+				mockStorage.EXPECT().Purge(gomock.Any(), uint64(3)).Return(errors.New("not found"))
+			},
+			expectErr: true,
+		},
+		{
+			name:           "Back",
+			key:            "b",
+			mockSetup:      func(_ *mocks.MockStorage) {},
+			navigates:      true,
+			expectedScreen: tui.StorageBrowseScreen,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := mocks.NewMockStorage(ctrl)
+			mockStorage.EXPECT().GetTrash(gomock.Any()).Return(testTrash(), nil)
+			tc.mockSetup(mockStorage)
+
+			screen := NewTrashScreen(mockStorage)
+			cmd := screen.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tc.key)})
+
+			var (
+				gotScreen = tui.Screen(-1)
+				gotInfo   string
+				gotErr    bool
+				prompted  bool
+			)
+			var handle func(msg tea.Msg)
+			handle = func(msg tea.Msg) {
+				switch msg := msg.(type) {
+				case tui.PromptMsg:
+					prompted = true
+					collect(msg.Action("y"), handle)
+				case purgedMsg:
+					collect(screen.Update(msg), handle)
+				case tui.NavigationMsg:
+					gotScreen = msg.Screen
+				case tui.InfoMsg:
+					gotInfo = string(msg)
+				case tui.ErrorMsg:
+					gotErr = true
+				}
+			}
+			collect(cmd, handle)
+
+			if tc.confirm != prompted {
+				t.Errorf("Expected confirmation %v, got %v", tc.confirm, prompted)
+			}
+			if tc.expectErr != gotErr {
+				t.Errorf("Expected error %v, got %v", tc.expectErr, gotErr)
+			}
+			if tc.expectedInfo != "" && gotInfo != tc.expectedInfo {
+				t.Errorf("Expected info %q, got %q", tc.expectedInfo, gotInfo)
+			}
+			if tc.navigates && gotScreen != tc.expectedScreen {
+				t.Errorf("Expected screen %v, got %v", tc.expectedScreen, gotScreen)
+			}
+		})
+	}
+}
+
+// collect выполняет команду и передаёт все полученные сообщения, раскрывая пакеты команд.
+func collect(cmd tea.Cmd, fn func(msg tea.Msg)) {
+	if cmd == nil {
+		return
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, c := range batch {
+			collect(c, fn)
+		}
+		return
+	}
+	fn(msg)
+}
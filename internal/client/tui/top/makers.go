@@ -4,26 +4,45 @@ package top
 import (
 	"beliaev-aa/GophKeeper/internal/client/grpc"
 	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/internal/client/tui/screens/account"
 	"beliaev-aa/GophKeeper/internal/client/tui/screens/auth"
 	"beliaev-aa/GophKeeper/internal/client/tui/screens/blobs"
 	"beliaev-aa/GophKeeper/internal/client/tui/screens/cards"
+	"beliaev-aa/GophKeeper/internal/client/tui/screens/conflict"
 	"beliaev-aa/GophKeeper/internal/client/tui/screens/credentials"
+	"beliaev-aa/GophKeeper/internal/client/tui/screens/emergency"
+	"beliaev-aa/GophKeeper/internal/client/tui/screens/history"
+	"beliaev-aa/GophKeeper/internal/client/tui/screens/organizations"
 	"beliaev-aa/GophKeeper/internal/client/tui/screens/remotes"
 	"beliaev-aa/GophKeeper/internal/client/tui/screens/secrets"
+	"beliaev-aa/GophKeeper/internal/client/tui/screens/sidebar"
 	"beliaev-aa/GophKeeper/internal/client/tui/screens/storage"
 	"beliaev-aa/GophKeeper/internal/client/tui/screens/texts"
+	"beliaev-aa/GophKeeper/internal/client/tui/screens/transfer"
+	"beliaev-aa/GophKeeper/internal/client/tui/screens/trash"
 )
 
 func prepareMakers(client grpc.ClientGRPCInterface) map[tui.Screen]tui.ScreenMaker {
 	return map[tui.Screen]tui.ScreenMaker{
-		tui.BlobEditScreen:       &blobs.BlobEditScreen{},
-		tui.CardEditScreen:       &cards.CardEditScreen{},
-		tui.CredentialEditScreen: &credentials.CredentialEditScreen{},
-		tui.FilePickScreen:       &blobs.FilePickScreen{},
-		tui.LoginScreen:          &auth.AuthenticateScreen{},
-		tui.RemoteOpenScreen:     &remotes.RemoteOpenScreenMaker{Client: client},
-		tui.SecretTypeScreen:     &secrets.SecretTypeScreen{},
-		tui.StorageBrowseScreen:  &storage.BrowseStorageScreen{},
-		tui.TextEditScreen:       &texts.TextEditScreen{},
+		tui.AccountDeleteScreen:     &account.AccountDeleteScreenMaker{Client: client},
+		tui.BlobEditScreen:          &blobs.BlobEditScreen{},
+		tui.CardEditScreen:          &cards.CardEditScreen{},
+		tui.CredentialEditScreen:    &credentials.CredentialEditScreen{},
+		tui.EmergencyContactsScreen: &emergency.EmergencyContactsScreen{},
+		tui.EmergencyGrantsScreen:   &emergency.EmergencyGrantsScreen{},
+		tui.FilePickScreen:          &blobs.FilePickScreen{},
+		tui.LoginScreen:             &auth.AuthenticateScreen{},
+		tui.OrganizationsScreen:     &organizations.OrganizationsScreen{},
+		tui.OrgMembersScreen:        &organizations.OrgMembersScreen{},
+		tui.PasswordChangeScreen:    &account.PasswordChangeScreen{},
+		tui.RemoteOpenScreen:        &remotes.RemoteOpenScreenMaker{Client: client},
+		tui.SecretConflictScreen:    &conflict.SecretConflictScreen{},
+		tui.SecretHistoryScreen:     &history.SecretHistoryScreen{},
+		tui.SecretTypeScreen:        &secrets.SecretTypeScreen{},
+		tui.SidebarScreen:           &sidebar.SidebarScreen{},
+		tui.StorageBrowseScreen:     &storage.BrowseStorageScreen{},
+		tui.TextEditScreen:          &texts.TextEditScreen{},
+		tui.TransferScreen:          &transfer.TransferScreen{},
+		tui.TrashScreen:             &trash.TrashScreen{},
 	}
 }
@@ -2,14 +2,22 @@ package top
 
 import (
 	"beliaev-aa/GophKeeper/internal/client/tui"
+	"beliaev-aa/GophKeeper/internal/client/tui/screens/account"
 	"beliaev-aa/GophKeeper/internal/client/tui/screens/auth"
 	"beliaev-aa/GophKeeper/internal/client/tui/screens/blobs"
 	"beliaev-aa/GophKeeper/internal/client/tui/screens/cards"
+	"beliaev-aa/GophKeeper/internal/client/tui/screens/conflict"
 	"beliaev-aa/GophKeeper/internal/client/tui/screens/credentials"
+	"beliaev-aa/GophKeeper/internal/client/tui/screens/emergency"
+	"beliaev-aa/GophKeeper/internal/client/tui/screens/history"
+	"beliaev-aa/GophKeeper/internal/client/tui/screens/organizations"
 	"beliaev-aa/GophKeeper/internal/client/tui/screens/remotes"
 	"beliaev-aa/GophKeeper/internal/client/tui/screens/secrets"
+	"beliaev-aa/GophKeeper/internal/client/tui/screens/sidebar"
 	"beliaev-aa/GophKeeper/internal/client/tui/screens/storage"
 	"beliaev-aa/GophKeeper/internal/client/tui/screens/texts"
+	"beliaev-aa/GophKeeper/internal/client/tui/screens/transfer"
+	"beliaev-aa/GophKeeper/internal/client/tui/screens/trash"
 	"beliaev-aa/GophKeeper/tests/mocks"
 	"github.com/golang/mock/gomock"
 	"reflect"
@@ -29,15 +37,26 @@ func Test_prepareMakers(t *testing.T) {
 	}
 
 	testCases := []testCase{
+		{name: "AccountDeleteScreen", screen: tui.AccountDeleteScreen, expectedMaker: &account.AccountDeleteScreenMaker{Client: mockClient}},
 		{name: "BlobEditScreen", screen: tui.BlobEditScreen, expectedMaker: &blobs.BlobEditScreen{}},
 		{name: "CardEditScreen", screen: tui.CardEditScreen, expectedMaker: &cards.CardEditScreen{}},
 		{name: "CredentialEditScreen", screen: tui.CredentialEditScreen, expectedMaker: &credentials.CredentialEditScreen{}},
+		{name: "EmergencyContactsScreen", screen: tui.EmergencyContactsScreen, expectedMaker: &emergency.EmergencyContactsScreen{}},
+		{name: "EmergencyGrantsScreen", screen: tui.EmergencyGrantsScreen, expectedMaker: &emergency.EmergencyGrantsScreen{}},
 		{name: "FilePickScreen", screen: tui.FilePickScreen, expectedMaker: &blobs.FilePickScreen{}},
 		{name: "LoginScreen", screen: tui.LoginScreen, expectedMaker: &auth.AuthenticateScreen{}},
+		{name: "OrganizationsScreen", screen: tui.OrganizationsScreen, expectedMaker: &organizations.OrganizationsScreen{}},
+		{name: "OrgMembersScreen", screen: tui.OrgMembersScreen, expectedMaker: &organizations.OrgMembersScreen{}},
+		{name: "PasswordChangeScreen", screen: tui.PasswordChangeScreen, expectedMaker: &account.PasswordChangeScreen{}},
 		{name: "RemoteOpenScreen", screen: tui.RemoteOpenScreen, expectedMaker: &remotes.RemoteOpenScreenMaker{Client: mockClient}},
+		{name: "SecretConflictScreen", screen: tui.SecretConflictScreen, expectedMaker: &conflict.SecretConflictScreen{}},
 		{name: "SecretTypeScreen", screen: tui.SecretTypeScreen, expectedMaker: &secrets.SecretTypeScreen{}},
+		{name: "SecretHistoryScreen", screen: tui.SecretHistoryScreen, expectedMaker: &history.SecretHistoryScreen{}},
+		{name: "SidebarScreen", screen: tui.SidebarScreen, expectedMaker: &sidebar.SidebarScreen{}},
 		{name: "StorageBrowseScreen", screen: tui.StorageBrowseScreen, expectedMaker: &storage.BrowseStorageScreen{}},
 		{name: "TextEditScreen", screen: tui.TextEditScreen, expectedMaker: &texts.TextEditScreen{}},
+		{name: "TransferScreen", screen: tui.TransferScreen, expectedMaker: &transfer.TransferScreen{}},
+		{name: "TrashScreen", screen: tui.TrashScreen, expectedMaker: &trash.TrashScreen{}},
 	}
 
 	makers := prepareMakers(mockClient)
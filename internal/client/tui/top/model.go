@@ -6,6 +6,7 @@ import (
 	"beliaev-aa/GophKeeper/internal/client/grpc"
 	"beliaev-aa/GophKeeper/internal/client/tui"
 	"beliaev-aa/GophKeeper/internal/client/tui/styles"
+	"errors"
 	"fmt"
 	"github.com/charmbracelet/bubbles/cursor"
 	"github.com/charmbracelet/bubbles/key"
@@ -36,6 +37,12 @@ const (
 	MinContentWidth = 80
 )
 
+// errPasswordChanged показывается пользователю, когда сессия отозвана из-за смены мастер-пароля на другом устройстве.
+var errPasswordChanged = errors.New("master password was changed on another device, please log in again")
+
+// errAccountDeleted показывается пользователю, когда учётная запись удалена с другого устройства.
+var errAccountDeleted = errors.New("account was deleted on another device")
+
 var (
 	helpStyle    = styles.Padded.Background(styles.Grey).Foreground(styles.White)
 	versionStyle = styles.Padded.Background(styles.DarkGrey).Foreground(styles.White)
@@ -127,6 +134,14 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, m.PaneManager.Update(msg)
 		}
 
+	case grpc.PasswordChanged:
+		m.err = errPasswordChanged
+		return m, tui.SetBodyPane(tui.LoginScreen, tui.WithClient(m.client))
+
+	case grpc.AccountDeleted:
+		m.err = errAccountDeleted
+		return m, tui.SetBodyPane(tui.LoginScreen, tui.WithClient(m.client))
+
 	case tui.ErrorMsg:
 		m.err = error(msg)
 
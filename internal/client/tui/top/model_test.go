@@ -2,6 +2,7 @@ package top
 
 import (
 	"beliaev-aa/GophKeeper/internal/client/config"
+	"beliaev-aa/GophKeeper/internal/client/grpc"
 	"beliaev-aa/GophKeeper/internal/client/tui"
 	"beliaev-aa/GophKeeper/internal/client/tui/styles"
 	"beliaev-aa/GophKeeper/tests/mocks"
@@ -362,6 +363,18 @@ func Test_Model_Update(t *testing.T) {
 			initialMode: normalMode,
 			expectedErr: errors.New("test error"),
 		},
+		{
+			name:        "PasswordChanged_Requires_Login",
+			msg:         grpc.PasswordChanged{},
+			initialMode: normalMode,
+			expectedErr: errPasswordChanged,
+		},
+		{
+			name:        "AccountDeleted_Requires_Login",
+			msg:         grpc.AccountDeleted{},
+			initialMode: normalMode,
+			expectedErr: errAccountDeleted,
+		},
 		{
 			name:         "InfoMsg_Sets_Info",
 			msg:          tui.InfoMsg("info message"),
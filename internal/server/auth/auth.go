@@ -2,12 +2,18 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
 	"github.com/golang-jwt/jwt/v4"
 	"golang.org/x/crypto/bcrypt"
 	"time"
 )
 
+// refreshTokenSize определяет количество случайных байт в refresh-токене.
+const refreshTokenSize = 32
+
 // HashPassword хэширует пароль с использованием алгоритма bcrypt.
 // Возвращает хэшированный пароль или ошибку.
 func HashPassword(password string) (string, error) {
@@ -26,14 +32,15 @@ func CheckPassword(passwordHash, password string) bool {
 }
 
 // CreateToken создает JWT токен для пользователя.
-// Принимает идентификатор пользователя, время истечения токена и секретный ключ.
+// Принимает идентификатор пользователя, идентификатор сессии, время истечения токена и секретный ключ.
 // Возвращает строку с токеном или ошибку.
-func CreateToken(userID int, expireDate time.Time, secretKey []byte) (string, error) {
+func CreateToken(userID int, sessionID uint64, expireDate time.Time, secretKey []byte) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": userID,
-		"iss":     "gophkeeper",
-		"exp":     expireDate.Unix(),
-		"iat":     time.Now().Unix(),
+		"user_id":    userID,
+		"session_id": sessionID,
+		"iss":        "gophkeeper",
+		"exp":        expireDate.Unix(),
+		"iat":        time.Now().Unix(),
 	})
 	tokenString, err := token.SignedString(secretKey)
 	if err != nil {
@@ -92,3 +99,20 @@ func GetClaims(token *jwt.Token) (jwt.MapClaims, error) {
 	}
 	return claims, nil
 }
+
+// NewRefreshToken генерирует случайный refresh-токен.
+// Возвращает токен для передачи клиенту и его хэш для хранения на сервере.
+func NewRefreshToken() (string, []byte, error) {
+	buf := make([]byte, refreshTokenSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken вычисляет хэш refresh-токена, под которым он хранится на сервере.
+func HashRefreshToken(token string) []byte {
+	hash := sha256.Sum256([]byte(token))
+	return hash[:]
+}
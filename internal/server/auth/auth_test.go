@@ -41,16 +41,17 @@ func TestTokens(t *testing.T) {
 	expireDate := time.Now().Add(time.Hour)
 
 	t.Run("create_token", func(t *testing.T) {
-		token, err := CreateToken(testUserID, expireDate, secret)
+		token, err := CreateToken(testUserID, 7, expireDate, secret)
 		assert.NoError(t, err)
 		assert.NotEmpty(t, token)
 	})
 
 	t.Run("successful_token_verification", func(t *testing.T) {
-		tokenString, _ := CreateToken(testUserID, expireDate, secret)
+		tokenString, _ := CreateToken(testUserID, 7, expireDate, secret)
 		claims, err := VerifyToken(tokenString, secret)
 		assert.NoError(t, err)
 		assert.Equal(t, float64(testUserID), claims["user_id"].(float64), "Claims user_id should match")
+		assert.Equal(t, float64(7), claims["session_id"].(float64), "Claims session_id should match")
 	})
 
 	t.Run("verify_invalid_token", func(t *testing.T) {
@@ -64,3 +65,15 @@ func TestTokens(t *testing.T) {
 		assert.Error(t, err, "Parse should fail with incorrect algorithm")
 	})
 }
+
+func TestRefreshTokens(t *testing.T) {
+	first, firstHash, err := NewRefreshToken()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, first)
+	assert.Equal(t, firstHash, HashRefreshToken(first))
+
+	second, secondHash, err := NewRefreshToken()
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, second, "Refresh tokens should be random")
+	assert.NotEqual(t, firstHash, secondHash)
+}
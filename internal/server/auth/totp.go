@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// totpSecretSize определяет размер секрета TOTP в байтах (160 бит, как рекомендует RFC 4226).
+	totpSecretSize = 20
+	// totpDigits определяет количество цифр в коде TOTP.
+	totpDigits = 6
+	// totpPeriod определяет длительность шага TOTP.
+	totpPeriod = 30 * time.Second
+	// totpSkew определяет, на сколько шагов допускается расхождение часов клиента и сервера.
+	totpSkew = 1
+	// recoveryCodeSize определяет количество символов в коде восстановления без разделителя.
+	recoveryCodeSize = 10
+	// challengePurpose помечает токены второго шага входа, чтобы их нельзя было использовать как токены доступа.
+	challengePurpose = "totp"
+)
+
+// totpEncoding используется для представления секрета TOTP в приложениях-аутентификаторах.
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// recoveryAlphabet содержит символы кодов восстановления без легко путаемых 0/o и 1/l.
+const recoveryAlphabet = "23456789abcdefghijkmnpqrstuvwxyz"
+
+// GenerateTOTPSecret генерирует случайный секрет TOTP.
+func GenerateTOTPSecret() ([]byte, error) {
+	secret := make([]byte, totpSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// EncodeTOTPSecret возвращает секрет TOTP в кодировке base32 для ручного ввода в приложение-аутентификатор.
+func EncodeTOTPSecret(secret []byte) string {
+	return totpEncoding.EncodeToString(secret)
+}
+
+// TOTPProvisioningURI формирует URI otpauth://, который приложения-аутентификаторы принимают в виде QR-кода.
+func TOTPProvisioningURI(issuer, account string, secret []byte) string {
+	label := url.PathEscape(issuer + ":" + account)
+	query := url.Values{}
+	query.Set("secret", EncodeTOTPSecret(secret))
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(int(totpPeriod.Seconds())))
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// TOTPStep возвращает номер шага TOTP для момента времени t.
+func TOTPStep(t time.Time) int64 {
+	return t.Unix() / int64(totpPeriod.Seconds())
+}
+
+// TOTPCode вычисляет код TOTP (RFC 6238, HMAC-SHA1) для шага step.
+func TOTPCode(secret []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", totpDigits, value%mod)
+}
+
+// ValidateTOTP проверяет код TOTP на момент времени now с допуском в totpSkew шагов.
+// Коды шагов не новее lastStep отклоняются, чтобы один и тот же код нельзя было использовать повторно.
+// Возвращает шаг, которому соответствует код, и признак успешной проверки.
+func ValidateTOTP(secret []byte, code string, now time.Time, lastStep int64) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := TOTPStep(now)
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(TOTPCode(secret, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// NewRecoveryCodes генерирует count одноразовых кодов восстановления.
+// Возвращает коды для показа пользователю и их хэши для хранения на сервере.
+func NewRecoveryCodes(count int) ([]string, [][]byte, error) {
+	codes := make([]string, 0, count)
+	hashes := make([][]byte, 0, count)
+
+	buf := make([]byte, recoveryCodeSize)
+	for i := 0; i < count; i++ {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		for j, b := range buf {
+			buf[j] = recoveryAlphabet[int(b)%len(recoveryAlphabet)]
+		}
+		code := string(buf[:recoveryCodeSize/2]) + "-" + string(buf[recoveryCodeSize/2:])
+		codes = append(codes, code)
+		hashes = append(hashes, HashRecoveryCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode вычисляет хэш кода восстановления. Регистр и разделители не учитываются.
+func HashRecoveryCode(code string) []byte {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	hash := sha256.Sum256([]byte(normalized))
+	return hash[:]
+}
+
+// TOTPChallenge описывает незавершённый вход, ожидающий второго фактора.
+type TOTPChallenge struct {
+	// UserID - идентификатор пользователя, прошедшего первый шаг входа.
+	UserID int
+	// Login - логин, под которым выполняется вход. Используется для учёта неудачных попыток.
+	Login string
+	// DeviceID - идентификатор устройства, с которого выполняется вход.
+	DeviceID uint64
+	// DeviceName - имя устройства, с которого выполняется вход.
+	DeviceName string
+}
+
+// CreateChallengeToken создаёт подписанный токен второго шага входа.
+// Токен не содержит идентификатора сессии и помечен назначением, поэтому не принимается как токен доступа.
+func CreateChallengeToken(challenge TOTPChallenge, expireDate time.Time, secretKey []byte) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id":     challenge.UserID,
+		"login":       challenge.Login,
+		"device_id":   challenge.DeviceID,
+		"device_name": challenge.DeviceName,
+		"purpose":     challengePurpose,
+		"iss":         "gophkeeper",
+		"exp":         expireDate.Unix(),
+		"iat":         time.Now().Unix(),
+	})
+	return token.SignedString(secretKey)
+}
+
+// VerifyChallengeToken проверяет токен второго шага входа и извлекает из него данные входа.
+func VerifyChallengeToken(tokenText string, secretKey []byte) (*TOTPChallenge, error) {
+	claims, err := VerifyToken(tokenText, secretKey)
+	if err != nil {
+		return nil, err
+	}
+	if purpose, _ := claims["purpose"].(string); purpose != challengePurpose {
+		return nil, fmt.Errorf("token is not a login challenge")
+	}
+
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("no user id in challenge")
+	}
+	login, _ := claims["login"].(string)
+	deviceID, _ := claims["device_id"].(float64)
+	deviceName, _ := claims["device_name"].(string)
+
+	return &TOTPChallenge{
+		UserID:     int(userID),
+		Login:      login,
+		DeviceID:   uint64(deviceID),
+		DeviceName: deviceName,
+	}, nil
+}
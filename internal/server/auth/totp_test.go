@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTOTPCode(t *testing.T) {
+	// Тестовые векторы RFC 6238 (приложение B) для SHA1, усечённые до 6 цифр.
+	secret := []byte("12345678901234567890")
+
+	tests := []struct {
+		unix     int64
+		expected string
+	}{
+		{unix: 59, expected: "287082"},
+		{unix: 1111111109, expected: "081804"},
+		{unix: 1111111111, expected: "050471"},
+		{unix: 1234567890, expected: "005924"},
+		{unix: 2000000000, expected: "279037"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expected, func(t *testing.T) {
+			assert.Equal(t, tc.expected, TOTPCode(secret, TOTPStep(time.Unix(tc.unix, 0))))
+		})
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	now := time.Unix(1111111111, 0)
+	current := TOTPStep(now)
+
+	tests := []struct {
+		name       string
+		code       string
+		lastStep   int64
+		expectStep int64
+		expectOK   bool
+	}{
+		{name: "current_step", code: TOTPCode(secret, current), expectStep: current, expectOK: true},
+		{name: "previous_step", code: TOTPCode(secret, current-1), expectStep: current - 1, expectOK: true},
+		{name: "next_step", code: TOTPCode(secret, current+1), expectStep: current + 1, expectOK: true},
+		{name: "too_old", code: TOTPCode(secret, current-2)},
+		{name: "replayed", code: TOTPCode(secret, current), lastStep: current},
+		{name: "wrong_length", code: "12345"},
+		{name: "spaces_trimmed", code: " " + TOTPCode(secret, current) + " ", expectStep: current, expectOK: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			step, ok := ValidateTOTP(secret, tc.code, now, tc.lastStep)
+			assert.Equal(t, tc.expectOK, ok)
+			assert.Equal(t, tc.expectStep, step)
+		})
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri := TOTPProvisioningURI("GophKeeper", "alice", []byte("12345678901234567890"))
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/GophKeeper:alice?"))
+	assert.Contains(t, uri, "secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ")
+	assert.Contains(t, uri, "issuer=GophKeeper")
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes, err := NewRecoveryCodes(10)
+	require.NoError(t, err)
+	require.Len(t, codes, 10)
+	require.Len(t, hashes, 10)
+
+	for i, code := range codes {
+		assert.Len(t, code, recoveryCodeSize+1)
+		assert.Equal(t, hashes[i], HashRecoveryCode(strings.ToUpper(strings.ReplaceAll(code, "-", ""))))
+	}
+	assert.NotEqual(t, codes[0], codes[1], "Recovery codes should be random")
+}
+
+func TestChallengeTokens(t *testing.T) {
+	secret := []byte("test_secret_key")
+	challenge := TOTPChallenge{UserID: 1337, Login: "alice", DeviceID: 42, DeviceName: "laptop"}
+
+	t.Run("round_trip", func(t *testing.T) {
+		token, err := CreateChallengeToken(challenge, time.Now().Add(time.Minute), secret)
+		require.NoError(t, err)
+
+		parsed, err := VerifyChallengeToken(token, secret)
+		require.NoError(t, err)
+		assert.Equal(t, challenge, *parsed)
+	})
+
+	t.Run("access_token_rejected", func(t *testing.T) {
+		token, _ := CreateToken(1337, 7, time.Now().Add(time.Minute), secret)
+
+		_, err := VerifyChallengeToken(token, secret)
+		assert.Error(t, err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		token, _ := CreateChallengeToken(challenge, time.Now().Add(-time.Minute), secret)
+
+		_, err := VerifyChallengeToken(token, secret)
+		assert.Error(t, err)
+	})
+}
@@ -2,16 +2,58 @@
 package config
 
 import (
+	"beliaev-aa/GophKeeper/pkg/models"
 	"errors"
 	"github.com/spf13/viper"
 	"strings"
+	"time"
 )
 
 // Config представляет основную конфигурацию клиентского приложения.
 type Config struct {
-	Address     string // Address определяет адрес сервера.
-	PostgresDSN string // PostgresDSN содержит строку подключения к PostgreSQL.
-	SecretKey   string // SecretKey используется для подписи JWT.
+	Address      string              // Address определяет адрес сервера.
+	PostgresDSN  string              // PostgresDSN содержит строку подключения к PostgreSQL.
+	SecretKey    string              // SecretKey используется для подписи JWT.
+	KDFAlgorithm models.KDFAlgorithm // KDFAlgorithm определяет алгоритм KDF для новых и обновляемых учётных записей.
+	// AccessTokenTTL определяет время жизни токена доступа.
+	AccessTokenTTL time.Duration
+	// RefreshTokenTTL определяет время жизни refresh-токена; при каждом обновлении отсчёт начинается заново.
+	RefreshTokenTTL time.Duration
+	// LoginMaxAttempts определяет, сколько неудачных попыток входа подряд допускается для логина без задержки.
+	LoginMaxAttempts int
+	// LoginLockout определяет максимальное время блокировки входа после серии неудачных попыток.
+	LoginLockout time.Duration
+	// SecretVersionsLimit определяет, сколько предыдущих версий секретов хранится для одного пользователя.
+	SecretVersionsLimit int
+	// TrashRetention определяет, сколько удалённые секреты хранятся в корзине до окончательного удаления.
+	TrashRetention time.Duration
+	// BlobStore определяет хранилище содержимого файлов: локальный каталог или S3-совместимое хранилище.
+	BlobStore BlobStoreKind
+	// BlobDir определяет каталог для хранения содержимого файлов в локальном хранилище.
+	BlobDir string
+	// BlobUploadTTL определяет, сколько загруженный файл хранится, пока на него не сослался ни один секрет.
+	BlobUploadTTL time.Duration
+	// S3 содержит параметры подключения к S3-совместимому хранилищу.
+	S3 S3Config
+}
+
+// BlobStoreKind определяет вид хранилища содержимого файлов.
+type BlobStoreKind string
+
+const (
+	// BlobStoreFS - хранение файлов в локальном каталоге.
+	BlobStoreFS BlobStoreKind = "fs"
+	// BlobStoreS3 - хранение файлов в S3-совместимом хранилище.
+	BlobStoreS3 BlobStoreKind = "s3"
+)
+
+// S3Config содержит параметры подключения к S3-совместимому хранилищу.
+type S3Config struct {
+	Endpoint  string // Endpoint определяет адрес хранилища, например https://s3.example.com.
+	Bucket    string // Bucket определяет бакет, в котором хранятся файлы.
+	Region    string // Region определяет регион, используемый при подписи запросов.
+	AccessKey string // AccessKey определяет идентификатор ключа доступа.
+	SecretKey string // SecretKey определяет секретный ключ доступа.
 }
 
 // LoadConfig инициализирует и возвращает новый экземпляр конфигурации.
@@ -36,9 +78,89 @@ func LoadConfig() (*Config, error) {
 		return nil, errors.New("secret key for signing JWT is not set: set GOPHKEEPER_SECRET_KEY environment variable")
 	}
 
+	viper.SetDefault("kdf-algorithm", string(models.KDFArgon2id))
+	kdfAlgorithm := models.KDFAlgorithm(viper.GetString("kdf-algorithm"))
+	if kdfAlgorithm != models.KDFArgon2id && kdfAlgorithm != models.KDFScrypt {
+		return nil, errors.New("unknown KDF algorithm: set GOPHKEEPER_KDF_ALGORITHM to argon2id or scrypt")
+	}
+
+	viper.SetDefault("access-token-ttl", 15*time.Minute)
+	accessTokenTTL := viper.GetDuration("access-token-ttl")
+	if accessTokenTTL <= 0 {
+		return nil, errors.New("access token TTL must be positive: set GOPHKEEPER_ACCESS_TOKEN_TTL environment variable")
+	}
+
+	viper.SetDefault("refresh-token-ttl", 30*24*time.Hour)
+	refreshTokenTTL := viper.GetDuration("refresh-token-ttl")
+	if refreshTokenTTL <= accessTokenTTL {
+		return nil, errors.New("refresh token TTL must exceed access token TTL: set GOPHKEEPER_REFRESH_TOKEN_TTL environment variable")
+	}
+
+	viper.SetDefault("login-max-attempts", 5)
+	loginMaxAttempts := viper.GetInt("login-max-attempts")
+	if loginMaxAttempts <= 0 {
+		return nil, errors.New("login max attempts must be positive: set GOPHKEEPER_LOGIN_MAX_ATTEMPTS environment variable")
+	}
+
+	viper.SetDefault("login-lockout", 15*time.Minute)
+	loginLockout := viper.GetDuration("login-lockout")
+	if loginLockout <= 0 {
+		return nil, errors.New("login lockout must be positive: set GOPHKEEPER_LOGIN_LOCKOUT environment variable")
+	}
+
+	viper.SetDefault("secret-versions-limit", 100)
+	secretVersionsLimit := viper.GetInt("secret-versions-limit")
+	if secretVersionsLimit < 0 {
+		return nil, errors.New("secret versions limit must not be negative: set GOPHKEEPER_SECRET_VERSIONS_LIMIT environment variable")
+	}
+
+	viper.SetDefault("trash-retention", 30*24*time.Hour)
+	trashRetention := viper.GetDuration("trash-retention")
+	if trashRetention <= 0 {
+		return nil, errors.New("trash retention must be positive: set GOPHKEEPER_TRASH_RETENTION environment variable")
+	}
+
+	viper.SetDefault("blob-store", string(BlobStoreFS))
+	blobStore := BlobStoreKind(viper.GetString("blob-store"))
+	if blobStore != BlobStoreFS && blobStore != BlobStoreS3 {
+		return nil, errors.New("unknown blob store: set GOPHKEEPER_BLOB_STORE to fs or s3")
+	}
+
+	viper.SetDefault("blob-dir", "blobs")
+	blobDir := viper.GetString("blob-dir")
+
+	viper.SetDefault("blob-upload-ttl", 24*time.Hour)
+	blobUploadTTL := viper.GetDuration("blob-upload-ttl")
+	if blobUploadTTL <= 0 {
+		return nil, errors.New("blob upload TTL must be positive: set GOPHKEEPER_BLOB_UPLOAD_TTL environment variable")
+	}
+
+	viper.SetDefault("s3-region", "us-east-1")
+	s3 := S3Config{
+		Endpoint:  viper.GetString("s3-endpoint"),
+		Bucket:    viper.GetString("s3-bucket"),
+		Region:    viper.GetString("s3-region"),
+		AccessKey: viper.GetString("s3-access-key"),
+		SecretKey: viper.GetString("s3-secret-key"),
+	}
+	if blobStore == BlobStoreS3 && (s3.Endpoint == "" || s3.Bucket == "" || s3.AccessKey == "" || s3.SecretKey == "") {
+		return nil, errors.New("S3 blob store is not configured: set GOPHKEEPER_S3_ENDPOINT, GOPHKEEPER_S3_BUCKET, GOPHKEEPER_S3_ACCESS_KEY and GOPHKEEPER_S3_SECRET_KEY environment variables")
+	}
+
 	return &Config{
-		Address:     address,
-		PostgresDSN: postgresDSN,
-		SecretKey:   secretKey,
+		Address:             address,
+		PostgresDSN:         postgresDSN,
+		SecretKey:           secretKey,
+		KDFAlgorithm:        kdfAlgorithm,
+		AccessTokenTTL:      accessTokenTTL,
+		RefreshTokenTTL:     refreshTokenTTL,
+		LoginMaxAttempts:    loginMaxAttempts,
+		LoginLockout:        loginLockout,
+		SecretVersionsLimit: secretVersionsLimit,
+		TrashRetention:      trashRetention,
+		BlobStore:           blobStore,
+		BlobDir:             blobDir,
+		BlobUploadTTL:       blobUploadTTL,
+		S3:                  s3,
 	}, nil
 }
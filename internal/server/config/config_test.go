@@ -1,10 +1,12 @@
 package config
 
 import (
+	"beliaev-aa/GophKeeper/pkg/models"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"os"
 	"testing"
+	"time"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -58,15 +60,276 @@ func TestLoadConfig(t *testing.T) {
 				os.Setenv("GOPHKEEPER_SECRET_KEY", "some-secret")
 			},
 			expectedConfig: &Config{
-				Address:     "127.0.0.1:5000",
-				PostgresDSN: "some-dsn",
-				SecretKey:   "some-secret",
+				Address:             "127.0.0.1:5000",
+				PostgresDSN:         "some-dsn",
+				SecretKey:           "some-secret",
+				KDFAlgorithm:        models.KDFArgon2id,
+				AccessTokenTTL:      15 * time.Minute,
+				RefreshTokenTTL:     30 * 24 * time.Hour,
+				LoginMaxAttempts:    5,
+				LoginLockout:        15 * time.Minute,
+				SecretVersionsLimit: 100,
+				TrashRetention:      30 * 24 * time.Hour,
+				BlobStore:           BlobStoreFS,
+				BlobDir:             "blobs",
+				BlobUploadTTL:       24 * time.Hour,
+				S3:                  S3Config{Region: "us-east-1"},
 			},
 		},
+		{
+			name: "KDF_Algorithm_Scrypt",
+			setupEnv: func() {
+				os.Setenv("GOPHKEEPER_ADDRESS", "127.0.0.1:5000")
+				os.Setenv("GOPHKEEPER_POSTGRES_DSN", "some-dsn")
+				os.Setenv("GOPHKEEPER_SECRET_KEY", "some-secret")
+				os.Setenv("GOPHKEEPER_KDF_ALGORITHM", "scrypt")
+			},
+			expectedConfig: &Config{
+				Address:             "127.0.0.1:5000",
+				PostgresDSN:         "some-dsn",
+				SecretKey:           "some-secret",
+				KDFAlgorithm:        models.KDFScrypt,
+				AccessTokenTTL:      15 * time.Minute,
+				RefreshTokenTTL:     30 * 24 * time.Hour,
+				LoginMaxAttempts:    5,
+				LoginLockout:        15 * time.Minute,
+				SecretVersionsLimit: 100,
+				TrashRetention:      30 * 24 * time.Hour,
+				BlobStore:           BlobStoreFS,
+				BlobDir:             "blobs",
+				BlobUploadTTL:       24 * time.Hour,
+				S3:                  S3Config{Region: "us-east-1"},
+			},
+		},
+		{
+			name: "KDF_Algorithm_Unknown",
+			setupEnv: func() {
+				os.Setenv("GOPHKEEPER_ADDRESS", "127.0.0.1:5000")
+				os.Setenv("GOPHKEEPER_POSTGRES_DSN", "some-dsn")
+				os.Setenv("GOPHKEEPER_SECRET_KEY", "some-secret")
+				os.Setenv("GOPHKEEPER_KDF_ALGORITHM", "pbkdf2")
+			},
+			expectedError: "unknown KDF algorithm: set GOPHKEEPER_KDF_ALGORITHM to argon2id or scrypt",
+		},
+		{
+			name: "Token_TTL_Set",
+			setupEnv: func() {
+				os.Setenv("GOPHKEEPER_ADDRESS", "127.0.0.1:5000")
+				os.Setenv("GOPHKEEPER_POSTGRES_DSN", "some-dsn")
+				os.Setenv("GOPHKEEPER_SECRET_KEY", "some-secret")
+				os.Setenv("GOPHKEEPER_ACCESS_TOKEN_TTL", "5m")
+				os.Setenv("GOPHKEEPER_REFRESH_TOKEN_TTL", "24h")
+			},
+			expectedConfig: &Config{
+				Address:             "127.0.0.1:5000",
+				PostgresDSN:         "some-dsn",
+				SecretKey:           "some-secret",
+				KDFAlgorithm:        models.KDFArgon2id,
+				AccessTokenTTL:      5 * time.Minute,
+				RefreshTokenTTL:     24 * time.Hour,
+				LoginMaxAttempts:    5,
+				LoginLockout:        15 * time.Minute,
+				SecretVersionsLimit: 100,
+				TrashRetention:      30 * 24 * time.Hour,
+				BlobStore:           BlobStoreFS,
+				BlobDir:             "blobs",
+				BlobUploadTTL:       24 * time.Hour,
+				S3:                  S3Config{Region: "us-east-1"},
+			},
+		},
+		{
+			name: "Refresh_TTL_Too_Short",
+			setupEnv: func() {
+				os.Setenv("GOPHKEEPER_ADDRESS", "127.0.0.1:5000")
+				os.Setenv("GOPHKEEPER_POSTGRES_DSN", "some-dsn")
+				os.Setenv("GOPHKEEPER_SECRET_KEY", "some-secret")
+				os.Setenv("GOPHKEEPER_ACCESS_TOKEN_TTL", "1h")
+				os.Setenv("GOPHKEEPER_REFRESH_TOKEN_TTL", "30m")
+			},
+			expectedError: "refresh token TTL must exceed access token TTL: set GOPHKEEPER_REFRESH_TOKEN_TTL environment variable",
+		},
+		{
+			name: "Login_Lockout_Set",
+			setupEnv: func() {
+				os.Setenv("GOPHKEEPER_ADDRESS", "127.0.0.1:5000")
+				os.Setenv("GOPHKEEPER_POSTGRES_DSN", "some-dsn")
+				os.Setenv("GOPHKEEPER_SECRET_KEY", "some-secret")
+				os.Setenv("GOPHKEEPER_LOGIN_MAX_ATTEMPTS", "3")
+				os.Setenv("GOPHKEEPER_LOGIN_LOCKOUT", "1h")
+			},
+			expectedConfig: &Config{
+				Address:             "127.0.0.1:5000",
+				PostgresDSN:         "some-dsn",
+				SecretKey:           "some-secret",
+				KDFAlgorithm:        models.KDFArgon2id,
+				AccessTokenTTL:      15 * time.Minute,
+				RefreshTokenTTL:     30 * 24 * time.Hour,
+				LoginMaxAttempts:    3,
+				LoginLockout:        time.Hour,
+				SecretVersionsLimit: 100,
+				TrashRetention:      30 * 24 * time.Hour,
+				BlobStore:           BlobStoreFS,
+				BlobDir:             "blobs",
+				BlobUploadTTL:       24 * time.Hour,
+				S3:                  S3Config{Region: "us-east-1"},
+			},
+		},
+		{
+			name: "Login_Max_Attempts_Invalid",
+			setupEnv: func() {
+				os.Setenv("GOPHKEEPER_ADDRESS", "127.0.0.1:5000")
+				os.Setenv("GOPHKEEPER_POSTGRES_DSN", "some-dsn")
+				os.Setenv("GOPHKEEPER_SECRET_KEY", "some-secret")
+				os.Setenv("GOPHKEEPER_LOGIN_MAX_ATTEMPTS", "0")
+			},
+			expectedError: "login max attempts must be positive: set GOPHKEEPER_LOGIN_MAX_ATTEMPTS environment variable",
+		},
+		{
+			name: "Secret_Versions_Limit_Set",
+			setupEnv: func() {
+				os.Setenv("GOPHKEEPER_ADDRESS", "127.0.0.1:5000")
+				os.Setenv("GOPHKEEPER_POSTGRES_DSN", "some-dsn")
+				os.Setenv("GOPHKEEPER_SECRET_KEY", "some-secret")
+				os.Setenv("GOPHKEEPER_SECRET_VERSIONS_LIMIT", "10")
+			},
+			expectedConfig: &Config{
+				Address:             "127.0.0.1:5000",
+				PostgresDSN:         "some-dsn",
+				SecretKey:           "some-secret",
+				KDFAlgorithm:        models.KDFArgon2id,
+				AccessTokenTTL:      15 * time.Minute,
+				RefreshTokenTTL:     30 * 24 * time.Hour,
+				LoginMaxAttempts:    5,
+				LoginLockout:        15 * time.Minute,
+				SecretVersionsLimit: 10,
+				TrashRetention:      30 * 24 * time.Hour,
+				BlobStore:           BlobStoreFS,
+				BlobDir:             "blobs",
+				BlobUploadTTL:       24 * time.Hour,
+				S3:                  S3Config{Region: "us-east-1"},
+			},
+		},
+		{
+			name: "Secret_Versions_Limit_Invalid",
+			setupEnv: func() {
+				os.Setenv("GOPHKEEPER_ADDRESS", "127.0.0.1:5000")
+				os.Setenv("GOPHKEEPER_POSTGRES_DSN", "some-dsn")
+				os.Setenv("GOPHKEEPER_SECRET_KEY", "some-secret")
+				os.Setenv("GOPHKEEPER_SECRET_VERSIONS_LIMIT", "-1")
+			},
+			expectedError: "secret versions limit must not be negative: set GOPHKEEPER_SECRET_VERSIONS_LIMIT environment variable",
+		},
+		{
+			name: "Trash_Retention_Set",
+			setupEnv: func() {
+				os.Setenv("GOPHKEEPER_ADDRESS", "127.0.0.1:5000")
+				os.Setenv("GOPHKEEPER_POSTGRES_DSN", "some-dsn")
+				os.Setenv("GOPHKEEPER_SECRET_KEY", "some-secret")
+				os.Setenv("GOPHKEEPER_TRASH_RETENTION", "168h")
+			},
+			expectedConfig: &Config{
+				Address:             "127.0.0.1:5000",
+				PostgresDSN:         "some-dsn",
+				SecretKey:           "some-secret",
+				KDFAlgorithm:        models.KDFArgon2id,
+				AccessTokenTTL:      15 * time.Minute,
+				RefreshTokenTTL:     30 * 24 * time.Hour,
+				LoginMaxAttempts:    5,
+				LoginLockout:        15 * time.Minute,
+				SecretVersionsLimit: 100,
+				TrashRetention:      7 * 24 * time.Hour,
+				BlobStore:           BlobStoreFS,
+				BlobDir:             "blobs",
+				BlobUploadTTL:       24 * time.Hour,
+				S3:                  S3Config{Region: "us-east-1"},
+			},
+		},
+		{
+			name: "Trash_Retention_Invalid",
+			setupEnv: func() {
+				os.Setenv("GOPHKEEPER_ADDRESS", "127.0.0.1:5000")
+				os.Setenv("GOPHKEEPER_POSTGRES_DSN", "some-dsn")
+				os.Setenv("GOPHKEEPER_SECRET_KEY", "some-secret")
+				os.Setenv("GOPHKEEPER_TRASH_RETENTION", "0s")
+			},
+			expectedError: "trash retention must be positive: set GOPHKEEPER_TRASH_RETENTION environment variable",
+		},
+		{
+			name: "S3_Blob_Store_Set",
+			setupEnv: func() {
+				os.Setenv("GOPHKEEPER_ADDRESS", "127.0.0.1:5000")
+				os.Setenv("GOPHKEEPER_POSTGRES_DSN", "some-dsn")
+				os.Setenv("GOPHKEEPER_SECRET_KEY", "some-secret")
+				os.Setenv("GOPHKEEPER_BLOB_STORE", "s3")
+				os.Setenv("GOPHKEEPER_S3_ENDPOINT", "http://127.0.0.1:9000")
+				os.Setenv("GOPHKEEPER_S3_BUCKET", "gophkeeper")
+				os.Setenv("GOPHKEEPER_S3_ACCESS_KEY", "access")
+				os.Setenv("GOPHKEEPER_S3_SECRET_KEY", "secret")
+				os.Setenv("GOPHKEEPER_BLOB_UPLOAD_TTL", "1h")
+			},
+			expectedConfig: &Config{
+				Address:             "127.0.0.1:5000",
+				PostgresDSN:         "some-dsn",
+				SecretKey:           "some-secret",
+				KDFAlgorithm:        models.KDFArgon2id,
+				AccessTokenTTL:      15 * time.Minute,
+				RefreshTokenTTL:     30 * 24 * time.Hour,
+				LoginMaxAttempts:    5,
+				LoginLockout:        15 * time.Minute,
+				SecretVersionsLimit: 100,
+				TrashRetention:      30 * 24 * time.Hour,
+				BlobStore:           BlobStoreS3,
+				BlobDir:             "blobs",
+				BlobUploadTTL:       time.Hour,
+				S3: S3Config{
+					Endpoint:  "http://127.0.0.1:9000",
+					Bucket:    "gophkeeper",
+					Region:    "us-east-1",
+					AccessKey: "access",
+					SecretKey: "secret",
+				},
+			},
+		},
+		{
+			name: "S3_Blob_Store_Not_Configured",
+			setupEnv: func() {
+				os.Setenv("GOPHKEEPER_ADDRESS", "127.0.0.1:5000")
+				os.Setenv("GOPHKEEPER_POSTGRES_DSN", "some-dsn")
+				os.Setenv("GOPHKEEPER_SECRET_KEY", "some-secret")
+				os.Setenv("GOPHKEEPER_BLOB_STORE", "s3")
+				os.Setenv("GOPHKEEPER_S3_ENDPOINT", "http://127.0.0.1:9000")
+			},
+			expectedError: "S3 blob store is not configured: set GOPHKEEPER_S3_ENDPOINT, GOPHKEEPER_S3_BUCKET, GOPHKEEPER_S3_ACCESS_KEY and GOPHKEEPER_S3_SECRET_KEY environment variables",
+		},
+		{
+			name: "Unknown_Blob_Store",
+			setupEnv: func() {
+				os.Setenv("GOPHKEEPER_ADDRESS", "127.0.0.1:5000")
+				os.Setenv("GOPHKEEPER_POSTGRES_DSN", "some-dsn")
+				os.Setenv("GOPHKEEPER_SECRET_KEY", "some-secret")
+				os.Setenv("GOPHKEEPER_BLOB_STORE", "ftp")
+			},
+			expectedError: "unknown blob store: set GOPHKEEPER_BLOB_STORE to fs or s3",
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
+			os.Unsetenv("GOPHKEEPER_KDF_ALGORITHM")
+			os.Unsetenv("GOPHKEEPER_ACCESS_TOKEN_TTL")
+			os.Unsetenv("GOPHKEEPER_REFRESH_TOKEN_TTL")
+			os.Unsetenv("GOPHKEEPER_LOGIN_MAX_ATTEMPTS")
+			os.Unsetenv("GOPHKEEPER_LOGIN_LOCKOUT")
+			os.Unsetenv("GOPHKEEPER_SECRET_VERSIONS_LIMIT")
+			os.Unsetenv("GOPHKEEPER_TRASH_RETENTION")
+			os.Unsetenv("GOPHKEEPER_BLOB_STORE")
+			os.Unsetenv("GOPHKEEPER_BLOB_DIR")
+			os.Unsetenv("GOPHKEEPER_BLOB_UPLOAD_TTL")
+			os.Unsetenv("GOPHKEEPER_S3_ENDPOINT")
+			os.Unsetenv("GOPHKEEPER_S3_BUCKET")
+			os.Unsetenv("GOPHKEEPER_S3_REGION")
+			os.Unsetenv("GOPHKEEPER_S3_ACCESS_KEY")
+			os.Unsetenv("GOPHKEEPER_S3_SECRET_KEY")
 			tc.setupEnv()
 			viper.Reset()
 
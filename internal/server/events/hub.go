@@ -0,0 +1,209 @@
+// Package events предоставляет общий серверный хаб событий, через который обработчики gRPC
+// публикуют изменения секретов, а открытые потоки уведомлений получают их.
+// Через хаб также закрываются потоки отозванных сессий.
+package events
+
+import (
+	"go.uber.org/zap"
+	"slices"
+	"sync"
+)
+
+// subscriptionBufferSize определяет размер буфера событий одного подписчика.
+// Подписчик, не успевающий вычитывать события, отключается хабом.
+const subscriptionBufferSize = 16
+
+// Kind определяет тип события, произошедшего с секретом.
+type Kind int
+
+const (
+	// SecretCreated - секрет создан.
+	SecretCreated Kind = iota + 1
+	// SecretUpdated - секрет изменён.
+	SecretUpdated
+	// SecretDeleted - секрет удалён.
+	SecretDeleted
+	// PasswordChanged - мастер-пароль пользователя изменён, сессия получателя отозвана.
+	PasswordChanged
+	// AccountDeleted - учётная запись пользователя удалена.
+	AccountDeleted
+	// EmergencyAccessChanged - экстренный доступ, в котором участвует пользователь, изменён.
+	// Идентификатор доступа передаётся в поле SecretID события.
+	EmergencyAccessChanged
+)
+
+// Event описывает событие, рассылаемое подписчикам пользователя.
+type Event struct {
+	// UserID - идентификатор пользователя, которому принадлежит секрет.
+	UserID uint64
+	// ClientID - идентификатор клиента-инициатора изменений, ему событие не отправляется.
+	ClientID uint64
+	// SecretID - идентификатор изменённого секрета.
+	SecretID uint64
+	// OrgID - идентификатор организации, в хранилище которой изменён секрет; 0 для личного хранилища.
+	OrgID uint64
+	// Kind - тип события.
+	Kind Kind
+}
+
+// Subscription представляет подписку клиента на события пользователя.
+type Subscription struct {
+	// Events - канал событий подписки. Закрывается при отписке или принудительном отключении.
+	Events    <-chan Event
+	events    chan Event
+	userID    uint64
+	clientID  uint64
+	sessionID uint64
+	once      sync.Once
+}
+
+// close закрывает канал событий подписки. Повторные вызовы игнорируются.
+func (s *Subscription) close() {
+	s.once.Do(func() {
+		close(s.events)
+	})
+}
+
+// Hub хранит подписки клиентов и рассылает им события.
+// Один экземпляр хаба разделяется между всеми обработчиками сервера.
+type Hub struct {
+	logger        *zap.Logger
+	mu            sync.RWMutex
+	subscriptions map[uint64]map[*Subscription]struct{}
+}
+
+// NewHub создаёт новый экземпляр Hub.
+func NewHub(logger *zap.Logger) *Hub {
+	return &Hub{
+		logger:        logger,
+		subscriptions: make(map[uint64]map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe регистрирует подписку клиента clientID, открытую в сессии sessionID, на события пользователя userID.
+// Подписку необходимо освободить вызовом Unsubscribe.
+func (h *Hub) Subscribe(userID, clientID, sessionID uint64) *Subscription {
+	events := make(chan Event, subscriptionBufferSize)
+	sub := &Subscription{
+		Events:    events,
+		events:    events,
+		userID:    userID,
+		clientID:  clientID,
+		sessionID: sessionID,
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subs, ok := h.subscriptions[userID]
+	if !ok {
+		subs = make(map[*Subscription]struct{})
+		h.subscriptions[userID] = subs
+	}
+	subs[sub] = struct{}{}
+
+	return sub
+}
+
+// Unsubscribe удаляет подписку из хаба и закрывает её канал событий.
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(sub)
+}
+
+// Disconnect закрывает подписки пользователя, открытые в перечисленных сессиях.
+// Используется при отзыве сессий, чтобы отозванное устройство перестало получать события.
+func (h *Hub) Disconnect(userID uint64, sessionIDs ...uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscriptions[userID] {
+		if slices.Contains(sessionIDs, sub.sessionID) {
+			h.remove(sub)
+		}
+	}
+}
+
+// DisconnectWith отправляет событие подпискам пользователя, открытым в перечисленных сессиях,
+// и закрывает их. В отличие от Disconnect, клиент узнаёт причину закрытия потока.
+// Если буфер подписки переполнен, событие не доставляется, но подписка всё равно закрывается.
+func (h *Hub) DisconnectWith(event Event, sessionIDs ...uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscriptions[event.UserID] {
+		if !slices.Contains(sessionIDs, sub.sessionID) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+		}
+		h.remove(sub)
+	}
+}
+
+// DisconnectAll закрывает все подписки пользователя.
+func (h *Hub) DisconnectAll(userID uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscriptions[userID] {
+		h.remove(sub)
+	}
+}
+
+// DisconnectAllWith отправляет событие всем подпискам пользователя и закрывает их.
+// Если буфер подписки переполнен, событие не доставляется, но подписка всё равно закрывается.
+func (h *Hub) DisconnectAllWith(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscriptions[event.UserID] {
+		select {
+		case sub.events <- event:
+		default:
+		}
+		h.remove(sub)
+	}
+}
+
+// Publish рассылает событие всем подпискам пользователя, кроме подписки клиента-инициатора.
+// Подписки с переполненным буфером отключаются, чтобы медленный клиент не блокировал остальных.
+func (h *Hub) Publish(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscriptions[event.UserID] {
+		if event.ClientID != 0 && sub.clientID == event.ClientID {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			h.logger.Warn("subscriber is too slow, disconnecting",
+				zap.Uint64("user_id", sub.userID),
+				zap.Uint64("client_id", sub.clientID),
+			)
+			h.remove(sub)
+		}
+	}
+}
+
+// remove удаляет подписку без захвата блокировки. Вызывающий должен удерживать h.mu.
+func (h *Hub) remove(sub *Subscription) {
+	subs, ok := h.subscriptions[sub.userID]
+	if !ok {
+		return
+	}
+	if _, ok = subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subscriptions, sub.userID)
+	}
+	sub.close()
+}
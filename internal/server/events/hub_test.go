@@ -0,0 +1,157 @@
+package events
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestHub_Publish(t *testing.T) {
+	tests := []struct {
+		name        string
+		event       Event
+		expectFirst bool
+		expectOther bool
+	}{
+		{
+			name:        "Publish_SkipsInitiator",
+			event:       Event{UserID: 1, ClientID: 10, SecretID: 5, Kind: SecretUpdated},
+			expectFirst: false,
+			expectOther: true,
+		},
+		{
+			name:        "Publish_UnknownInitiator",
+			event:       Event{UserID: 1, ClientID: 0, SecretID: 5, Kind: SecretCreated},
+			expectFirst: true,
+			expectOther: true,
+		},
+		{
+			name:        "Publish_OtherUser",
+			event:       Event{UserID: 2, ClientID: 0, SecretID: 5, Kind: SecretDeleted},
+			expectFirst: false,
+			expectOther: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			hub := NewHub(zap.NewNop())
+			first := hub.Subscribe(1, 10, 100)
+			other := hub.Subscribe(1, 20, 200)
+			defer hub.Unsubscribe(first)
+			defer hub.Unsubscribe(other)
+
+			hub.Publish(tc.event)
+
+			assert.Equal(t, tc.expectFirst, len(first.Events) == 1)
+			assert.Equal(t, tc.expectOther, len(other.Events) == 1)
+			if tc.expectOther {
+				assert.Equal(t, tc.event, <-other.Events)
+			}
+		})
+	}
+}
+
+func TestHub_Unsubscribe(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	sub := hub.Subscribe(1, 10, 100)
+
+	hub.Unsubscribe(sub)
+	hub.Unsubscribe(sub)
+
+	_, ok := <-sub.Events
+	assert.False(t, ok)
+	assert.Empty(t, hub.subscriptions)
+}
+
+func TestHub_Publish_DisconnectsSlowSubscriber(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	sub := hub.Subscribe(1, 10, 100)
+
+	for i := 0; i <= subscriptionBufferSize; i++ {
+		hub.Publish(Event{UserID: 1, SecretID: uint64(i), Kind: SecretUpdated})
+	}
+
+	received := 0
+	for range sub.Events {
+		received++
+	}
+	assert.Equal(t, subscriptionBufferSize, received)
+	assert.Empty(t, hub.subscriptions)
+}
+
+func TestHub_Disconnect(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	revoked := hub.Subscribe(1, 10, 100)
+	active := hub.Subscribe(1, 20, 200)
+	other := hub.Subscribe(2, 10, 100)
+	defer hub.Unsubscribe(active)
+	defer hub.Unsubscribe(other)
+
+	hub.Disconnect(1, 100)
+
+	_, ok := <-revoked.Events
+	assert.False(t, ok)
+	assert.Len(t, hub.subscriptions[1], 1)
+	assert.Len(t, hub.subscriptions[2], 1)
+
+	hub.Publish(Event{UserID: 1, SecretID: 5, Kind: SecretCreated})
+	assert.Len(t, active.Events, 1)
+}
+
+func TestHub_DisconnectWith(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	revoked := hub.Subscribe(1, 10, 100)
+	current := hub.Subscribe(1, 20, 200)
+	defer hub.Unsubscribe(current)
+
+	event := Event{UserID: 1, Kind: PasswordChanged}
+	hub.DisconnectWith(event, 100)
+
+	received, ok := <-revoked.Events
+	assert.True(t, ok)
+	assert.Equal(t, event, received)
+	_, ok = <-revoked.Events
+	assert.False(t, ok)
+
+	assert.Empty(t, current.Events)
+	assert.Len(t, hub.subscriptions[1], 1)
+}
+
+func TestHub_DisconnectAllWith(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	first := hub.Subscribe(1, 10, 100)
+	second := hub.Subscribe(1, 20, 200)
+	other := hub.Subscribe(2, 10, 300)
+	defer hub.Unsubscribe(other)
+
+	event := Event{UserID: 1, Kind: AccountDeleted}
+	hub.DisconnectAllWith(event)
+
+	for _, sub := range []*Subscription{first, second} {
+		received, ok := <-sub.Events
+		assert.True(t, ok)
+		assert.Equal(t, event, received)
+		_, ok = <-sub.Events
+		assert.False(t, ok)
+	}
+	assert.NotContains(t, hub.subscriptions, uint64(1))
+	assert.Empty(t, other.Events)
+}
+
+func TestHub_DisconnectAll(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	first := hub.Subscribe(1, 10, 100)
+	second := hub.Subscribe(1, 20, 200)
+	other := hub.Subscribe(2, 10, 300)
+	defer hub.Unsubscribe(other)
+
+	hub.DisconnectAll(1)
+
+	_, ok := <-first.Events
+	assert.False(t, ok)
+	_, ok = <-second.Events
+	assert.False(t, ok)
+	assert.NotContains(t, hub.subscriptions, uint64(1))
+	assert.Contains(t, hub.subscriptions, uint64(2))
+}
@@ -0,0 +1,180 @@
+// Package handlers содержит обработчики gRPC-запросов потоковой передачи бинарных объектов.
+package handlers
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/service"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/pkg/proto"
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+)
+
+// BlobHandler реализует серверные функции для загрузки и скачивания бинарных объектов по фрагментам.
+type BlobHandler struct {
+	proto.UnimplementedBlobsServer
+	blobService   service.IBlobService
+	secretService service.ISecretService
+	shareService  service.IShareService
+	logger        *zap.Logger
+}
+
+// NewBlobHandler создаёт новый экземпляр сервера бинарных объектов.
+// Сервис секретов проверяет доступ к хранилищам организаций, а сервис доступов открывает получателям
+// переданных секретов-файлов их объекты для чтения.
+// Возвращает инициализированный экземпляр BlobHandler.
+func NewBlobHandler(logger *zap.Logger, blobService service.IBlobService, secretService service.ISecretService, shareService service.IShareService) *BlobHandler {
+	return &BlobHandler{
+		blobService:   blobService,
+		secretService: secretService,
+		shareService:  shareService,
+		logger:        logger,
+	}
+}
+
+// UploadBlob принимает поток фрагментов объекта. Первое сообщение начинает или продолжает загрузку,
+// после чего фрагменты сохраняются по порядку. Если поток прерван, уже сохранённые фрагменты
+// остаются на сервере, и клиент может продолжить загрузку с первого не полученного фрагмента.
+// Объекты хранилища организации загружает её редактор.
+// Возвращает количество полученных фрагментов, ошибку InvalidArgument при нарушении протокола
+// и PermissionDenied при загрузке через доступ к переданному секрету или экстренный доступ:
+// такие объекты только читаются.
+func (s *BlobHandler) UploadBlob(stream proto.Blobs_UploadBlobServer) error {
+	ctx := stream.Context()
+
+	userID, err := s.resolveOwner(ctx, "", models.OrgEditor)
+	if err != nil {
+		return err
+	}
+
+	var blob *models.BlobStatus
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if blob == nil {
+			blob, err = s.blobService.StartUpload(ctx, userID, req.BlobId, req.ChunkCount)
+			if err != nil {
+				return blobError(err)
+			}
+		} else if req.BlobId != blob.ID {
+			return status.Error(codes.InvalidArgument, "blob id changed within upload stream")
+		}
+
+		if err = s.blobService.SaveChunk(ctx, userID, blob, req.ChunkIndex, req.Data); err != nil {
+			return blobError(err)
+		}
+	}
+
+	if blob == nil {
+		return status.Error(codes.InvalidArgument, "empty upload stream")
+	}
+
+	s.logger.Debug("blob chunks received", zap.String("blob_id", blob.ID), zap.Uint32("received", blob.ReceivedChunks))
+
+	return stream.SendAndClose(&proto.UploadBlobResponse{ReceivedChunks: blob.ReceivedChunks})
+}
+
+// GetBlobStatus возвращает количество фрагментов объекта и количество уже полученных сервером.
+// Используется клиентом для продолжения прерванной загрузки.
+func (s *BlobHandler) GetBlobStatus(ctx context.Context, in *proto.GetBlobStatusRequest) (*proto.GetBlobStatusResponse, error) {
+	userID, err := s.resolveOwner(ctx, in.BlobId, models.OrgViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	blob, err := s.blobService.GetBlobStatus(ctx, userID, in.BlobId)
+	if err != nil {
+		return nil, blobError(err)
+	}
+
+	return &proto.GetBlobStatusResponse{ChunkCount: blob.ChunkCount, ReceivedChunks: blob.ReceivedChunks}, nil
+}
+
+// DownloadBlob передаёт фрагменты полностью загруженного объекта по порядку, начиная с from_chunk.
+// Получатель секрета-файла скачивает объект владельца, указав в метаданных запроса доступ к секрету,
+// а участник организации или получатель экстренного доступа — объект соответствующего хранилища.
+// Возвращает ошибку FailedPrecondition, если загрузка объекта не завершена.
+func (s *BlobHandler) DownloadBlob(in *proto.DownloadBlobRequest, stream proto.Blobs_DownloadBlobServer) error {
+	ctx := stream.Context()
+
+	userID, err := s.resolveOwner(ctx, in.BlobId, models.OrgViewer)
+	if err != nil {
+		return err
+	}
+
+	blob, err := s.blobService.GetBlobStatus(ctx, userID, in.BlobId)
+	if err != nil {
+		return blobError(err)
+	}
+	if !blob.Complete() {
+		return status.Errorf(codes.FailedPrecondition, "blob upload is not complete: %d of %d chunks received", blob.ReceivedChunks, blob.ChunkCount)
+	}
+	if in.FromChunk > blob.ChunkCount {
+		return status.Errorf(codes.InvalidArgument, "chunk %d is out of range", in.FromChunk)
+	}
+
+	for index := in.FromChunk; index < blob.ChunkCount; index++ {
+		data, err := s.blobService.GetChunk(ctx, userID, blob.ID, index)
+		if err != nil {
+			return blobError(err)
+		}
+
+		resp := &proto.DownloadBlobResponse{ChunkIndex: index, ChunkCount: blob.ChunkCount, Data: data}
+		if err = stream.Send(resp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveOwner возвращает идентификатор владельца объекта blobID: пользователя, хранилища организации
+// или владельца экстренного доступа, определённых resolveTenant с ролью не ниже required, либо владельца
+// секрета-файла, если в метаданных запроса указан доступ к переданному секрету.
+// Возвращает статус InvalidArgument при неверном идентификаторе доступа, NotFound, если доступ
+// не выдан пользователю или переданный секрет не ссылается на объект, и PermissionDenied
+// при изменении объекта через доступ к переданному секрету.
+func (s *BlobHandler) resolveOwner(ctx context.Context, blobID string, required models.OrgRole) (uint64, error) {
+	shareID, err := extractShareID(ctx)
+	if err != nil {
+		return 0, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if shareID == 0 {
+		return resolveTenant(ctx, s.secretService, required)
+	}
+	if required != models.OrgViewer {
+		return 0, status.Error(codes.PermissionDenied, "shared files are read-only")
+	}
+
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return 0, status.Error(codes.Internal, err.Error())
+	}
+	ownerID, err := s.shareService.ResolveSharedBlob(ctx, userID, shareID, blobID)
+	if err != nil {
+		return 0, blobError(err)
+	}
+	return ownerID, nil
+}
+
+// blobError конвертирует ошибку сервиса бинарных объектов в ошибку gRPC.
+func blobError(err error) error {
+	switch {
+	case errors.Is(err, gophKeeperErrors.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, gophKeeperErrors.ErrInvalidBlob):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,576 @@
+package handlers
+
+import (
+	"beliaev-aa/GophKeeper/pkg/consts"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/pkg/proto"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"testing"
+)
+
+const testBlobID = "0123456789abcdef0123456789abcdef"
+
+// fakeUploadStream имитирует входящий поток фрагментов загружаемого объекта.
+type fakeUploadStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	requests []*proto.UploadBlobRequest
+	recvErr  error
+	response *proto.UploadBlobResponse
+}
+
+func (f *fakeUploadStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeUploadStream) Recv() (*proto.UploadBlobRequest, error) {
+	if len(f.requests) == 0 {
+		if f.recvErr != nil {
+			return nil, f.recvErr
+		}
+		return nil, io.EOF
+	}
+	req := f.requests[0]
+	f.requests = f.requests[1:]
+	return req, nil
+}
+
+func (f *fakeUploadStream) SendAndClose(resp *proto.UploadBlobResponse) error {
+	f.response = resp
+	return nil
+}
+
+// fakeDownloadStream имитирует исходящий поток фрагментов скачиваемого объекта.
+type fakeDownloadStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	sent    []*proto.DownloadBlobResponse
+	sendErr error
+}
+
+func (f *fakeDownloadStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeDownloadStream) Send(resp *proto.DownloadBlobResponse) error {
+	if f.sendErr != nil {
+		return f.sendErr
+	}
+	f.sent = append(f.sent, resp)
+	return nil
+}
+
+func userContext() context.Context {
+	return context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123))
+}
+
+// shareContext возвращает контекст запроса пользователя, читающего объект через доступ shareID.
+func shareContext(shareID string) context.Context {
+	return metadata.NewIncomingContext(userContext(), metadata.Pairs(consts.ShareIDHeader, shareID))
+}
+
+// emergencyContext возвращает контекст запроса пользователя к хранилищу владельца по экстренному доступу accessID.
+func emergencyContext(accessID string) context.Context {
+	return metadata.NewIncomingContext(userContext(), metadata.Pairs(consts.EmergencyAccessIDHeader, accessID))
+}
+
+// orgContext возвращает контекст запроса пользователя к хранилищу организации orgID.
+func orgContext(orgID string) context.Context {
+	return metadata.NewIncomingContext(userContext(), metadata.Pairs(consts.OrganizationIDHeader, orgID))
+}
+
+func TestBlobHandler_UploadBlob(t *testing.T) {
+	chunk := func(index uint32) *proto.UploadBlobRequest {
+		return &proto.UploadBlobRequest{BlobId: testBlobID, ChunkCount: 3, ChunkIndex: index, Data: []byte{byte(index)}}
+	}
+
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		requests       []*proto.UploadBlobRequest
+		recvErr        error
+		setupMock      func(mockService *mocks.MockIBlobService)
+		expectedCode   codes.Code
+		expectedChunks uint32
+	}{
+		{
+			name:     "Success",
+			ctx:      userContext(),
+			requests: []*proto.UploadBlobRequest{chunk(0), chunk(1), chunk(2)},
+			setupMock: func(mockService *mocks.MockIBlobService) {
+				blob := &models.BlobStatus{ID: testBlobID, ChunkCount: 3}
+				mockService.EXPECT().StartUpload(gomock.Any(), uint64(123), testBlobID, uint32(3)).Return(blob, nil)
+				mockService.EXPECT().SaveChunk(gomock.Any(), uint64(123), blob, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ uint64, status *models.BlobStatus, _ uint32, _ []byte) error {
+						status.ReceivedChunks++
+						return nil
+					}).Times(3)
+			},
+			expectedCode:   codes.OK,
+			expectedChunks: 3,
+		},
+		{
+			name:     "Resume",
+			ctx:      userContext(),
+			requests: []*proto.UploadBlobRequest{chunk(2)},
+			setupMock: func(mockService *mocks.MockIBlobService) {
+				blob := &models.BlobStatus{ID: testBlobID, ChunkCount: 3, ReceivedChunks: 2}
+				mockService.EXPECT().StartUpload(gomock.Any(), uint64(123), testBlobID, uint32(3)).Return(blob, nil)
+				mockService.EXPECT().SaveChunk(gomock.Any(), uint64(123), blob, uint32(2), []byte{2}).
+					DoAndReturn(func(_ context.Context, _ uint64, status *models.BlobStatus, _ uint32, _ []byte) error {
+						status.ReceivedChunks++
+						return nil
+					})
+			},
+			expectedCode:   codes.OK,
+			expectedChunks: 3,
+		},
+		{
+			name:         "Fail_NoUserID",
+			ctx:          context.Background(),
+			setupMock:    func(_ *mocks.MockIBlobService) {},
+			expectedCode: codes.Internal,
+		},
+		{
+			name:         "Fail_EmptyStream",
+			ctx:          userContext(),
+			setupMock:    func(_ *mocks.MockIBlobService) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Fail_InvalidBlob",
+			ctx:      userContext(),
+			requests: []*proto.UploadBlobRequest{chunk(0)},
+			setupMock: func(mockService *mocks.MockIBlobService) {
+				mockService.EXPECT().StartUpload(gomock.Any(), uint64(123), testBlobID, uint32(3)).
+					Return(nil, fmt.Errorf("%w: malformed id", gophKeeperErrors.ErrInvalidBlob))
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Fail_BlobIDChanged",
+			ctx:      userContext(),
+			requests: []*proto.UploadBlobRequest{chunk(0), {BlobId: "other", ChunkIndex: 1}},
+			setupMock: func(mockService *mocks.MockIBlobService) {
+				blob := &models.BlobStatus{ID: testBlobID, ChunkCount: 3}
+				mockService.EXPECT().StartUpload(gomock.Any(), uint64(123), testBlobID, uint32(3)).Return(blob, nil)
+				mockService.EXPECT().SaveChunk(gomock.Any(), uint64(123), blob, uint32(0), gomock.Any()).Return(nil)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Fail_SaveChunk",
+			ctx:      userContext(),
+			requests: []*proto.UploadBlobRequest{chunk(0)},
+			setupMock: func(mockService *mocks.MockIBlobService) {
+				blob := &models.BlobStatus{ID: testBlobID, ChunkCount: 3}
+				mockService.EXPECT().StartUpload(gomock.Any(), uint64(123), testBlobID, uint32(3)).Return(blob, nil)
+				mockService.EXPECT().SaveChunk(gomock.Any(), uint64(123), blob, uint32(0), gomock.Any()).Return(errors.New("database error"))
+			},
+			expectedCode: codes.Internal,
+		},
+		{
+			name:     "Fail_StreamBroken",
+			ctx:      userContext(),
+			requests: []*proto.UploadBlobRequest{chunk(0)},
+			recvErr:  status.Error(codes.Canceled, "context canceled"),
+			setupMock: func(mockService *mocks.MockIBlobService) {
+				blob := &models.BlobStatus{ID: testBlobID, ChunkCount: 3}
+				mockService.EXPECT().StartUpload(gomock.Any(), uint64(123), testBlobID, uint32(3)).Return(blob, nil)
+				mockService.EXPECT().SaveChunk(gomock.Any(), uint64(123), blob, uint32(0), gomock.Any()).Return(nil)
+			},
+			expectedCode: codes.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockIBlobService(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewBlobHandler(zap.NewNop(), mockService, mocks.NewMockISecretService(ctrl), mocks.NewMockIShareService(ctrl))
+			stream := &fakeUploadStream{ctx: tt.ctx, requests: tt.requests, recvErr: tt.recvErr}
+
+			err := handler.UploadBlob(stream)
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			if tt.expectedCode == codes.OK {
+				assert.Equal(t, tt.expectedChunks, stream.response.GetReceivedChunks())
+			}
+		})
+	}
+}
+
+func TestBlobHandler_GetBlobStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		setupMock    func(mockService *mocks.MockIBlobService)
+		expectedCode codes.Code
+	}{
+		{
+			name: "Success",
+			ctx:  userContext(),
+			setupMock: func(mockService *mocks.MockIBlobService) {
+				mockService.EXPECT().GetBlobStatus(gomock.Any(), uint64(123), testBlobID).
+					Return(&models.BlobStatus{ID: testBlobID, ChunkCount: 3, ReceivedChunks: 2}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Fail_NoUserID",
+			ctx:          context.Background(),
+			setupMock:    func(_ *mocks.MockIBlobService) {},
+			expectedCode: codes.Internal,
+		},
+		{
+			name: "Fail_NotFound",
+			ctx:  userContext(),
+			setupMock: func(mockService *mocks.MockIBlobService) {
+				mockService.EXPECT().GetBlobStatus(gomock.Any(), uint64(123), testBlobID).
+					Return(nil, fmt.Errorf("blob %w", gophKeeperErrors.ErrNotFound))
+			},
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockIBlobService(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewBlobHandler(zap.NewNop(), mockService, mocks.NewMockISecretService(ctrl), mocks.NewMockIShareService(ctrl))
+			resp, err := handler.GetBlobStatus(tt.ctx, &proto.GetBlobStatusRequest{BlobId: testBlobID})
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			if tt.expectedCode == codes.OK {
+				assert.Equal(t, uint32(3), resp.ChunkCount)
+				assert.Equal(t, uint32(2), resp.ReceivedChunks)
+			}
+		})
+	}
+}
+
+func TestBlobHandler_DownloadBlob(t *testing.T) {
+	complete := &models.BlobStatus{ID: testBlobID, ChunkCount: 3, ReceivedChunks: 3}
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		fromChunk    uint32
+		sendErr      error
+		setupMock    func(mockService *mocks.MockIBlobService)
+		expectedCode codes.Code
+		expectedSent []uint32
+	}{
+		{
+			name: "Success",
+			ctx:  userContext(),
+			setupMock: func(mockService *mocks.MockIBlobService) {
+				mockService.EXPECT().GetBlobStatus(gomock.Any(), uint64(123), testBlobID).Return(complete, nil)
+				mockService.EXPECT().GetChunk(gomock.Any(), uint64(123), testBlobID, gomock.Any()).Return([]byte("chunk"), nil).Times(3)
+			},
+			expectedCode: codes.OK,
+			expectedSent: []uint32{0, 1, 2},
+		},
+		{
+			name:      "Resume",
+			ctx:       userContext(),
+			fromChunk: 2,
+			setupMock: func(mockService *mocks.MockIBlobService) {
+				mockService.EXPECT().GetBlobStatus(gomock.Any(), uint64(123), testBlobID).Return(complete, nil)
+				mockService.EXPECT().GetChunk(gomock.Any(), uint64(123), testBlobID, uint32(2)).Return([]byte("chunk"), nil)
+			},
+			expectedCode: codes.OK,
+			expectedSent: []uint32{2},
+		},
+		{
+			name:         "Fail_NoUserID",
+			ctx:          context.Background(),
+			setupMock:    func(_ *mocks.MockIBlobService) {},
+			expectedCode: codes.Internal,
+		},
+		{
+			name: "Fail_NotFound",
+			ctx:  userContext(),
+			setupMock: func(mockService *mocks.MockIBlobService) {
+				mockService.EXPECT().GetBlobStatus(gomock.Any(), uint64(123), testBlobID).Return(nil, gophKeeperErrors.ErrNotFound)
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name: "Fail_Incomplete",
+			ctx:  userContext(),
+			setupMock: func(mockService *mocks.MockIBlobService) {
+				mockService.EXPECT().GetBlobStatus(gomock.Any(), uint64(123), testBlobID).
+					Return(&models.BlobStatus{ID: testBlobID, ChunkCount: 3, ReceivedChunks: 1}, nil)
+			},
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name:      "Fail_OutOfRange",
+			ctx:       userContext(),
+			fromChunk: 4,
+			setupMock: func(mockService *mocks.MockIBlobService) {
+				mockService.EXPECT().GetBlobStatus(gomock.Any(), uint64(123), testBlobID).Return(complete, nil)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Fail_GetChunk",
+			ctx:  userContext(),
+			setupMock: func(mockService *mocks.MockIBlobService) {
+				mockService.EXPECT().GetBlobStatus(gomock.Any(), uint64(123), testBlobID).Return(complete, nil)
+				mockService.EXPECT().GetChunk(gomock.Any(), uint64(123), testBlobID, uint32(0)).Return(nil, errors.New("database error"))
+			},
+			expectedCode: codes.Internal,
+		},
+		{
+			name:    "Fail_Send",
+			ctx:     userContext(),
+			sendErr: status.Error(codes.Canceled, "context canceled"),
+			setupMock: func(mockService *mocks.MockIBlobService) {
+				mockService.EXPECT().GetBlobStatus(gomock.Any(), uint64(123), testBlobID).Return(complete, nil)
+				mockService.EXPECT().GetChunk(gomock.Any(), uint64(123), testBlobID, uint32(0)).Return([]byte("chunk"), nil)
+			},
+			expectedCode: codes.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockIBlobService(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewBlobHandler(zap.NewNop(), mockService, mocks.NewMockISecretService(ctrl), mocks.NewMockIShareService(ctrl))
+			stream := &fakeDownloadStream{ctx: tt.ctx, sendErr: tt.sendErr}
+
+			err := handler.DownloadBlob(&proto.DownloadBlobRequest{BlobId: testBlobID, FromChunk: tt.fromChunk}, stream)
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+
+			var sent []uint32
+			for _, resp := range stream.sent {
+				sent = append(sent, resp.ChunkIndex)
+				assert.Equal(t, uint32(3), resp.ChunkCount)
+			}
+			assert.Equal(t, tt.expectedSent, sent)
+		})
+	}
+}
+
+func TestBlobHandler_SharedBlob(t *testing.T) {
+	complete := &models.BlobStatus{ID: testBlobID, ChunkCount: 1, ReceivedChunks: 1}
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		call         func(handler *BlobHandler, ctx context.Context) error
+		setupMock    func(mockService *mocks.MockIBlobService, mockShares *mocks.MockIShareService)
+		expectedCode codes.Code
+	}{
+		{
+			name: "GetBlobStatus_Success",
+			ctx:  shareContext("5"),
+			call: func(handler *BlobHandler, ctx context.Context) error {
+				_, err := handler.GetBlobStatus(ctx, &proto.GetBlobStatusRequest{BlobId: testBlobID})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIBlobService, mockShares *mocks.MockIShareService) {
+				mockShares.EXPECT().ResolveSharedBlob(gomock.Any(), uint64(123), uint64(5), testBlobID).Return(uint64(7), nil)
+				mockService.EXPECT().GetBlobStatus(gomock.Any(), uint64(7), testBlobID).Return(complete, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "DownloadBlob_Success",
+			ctx:  shareContext("5"),
+			call: func(handler *BlobHandler, ctx context.Context) error {
+				return handler.DownloadBlob(&proto.DownloadBlobRequest{BlobId: testBlobID}, &fakeDownloadStream{ctx: ctx})
+			},
+			setupMock: func(mockService *mocks.MockIBlobService, mockShares *mocks.MockIShareService) {
+				mockShares.EXPECT().ResolveSharedBlob(gomock.Any(), uint64(123), uint64(5), testBlobID).Return(uint64(7), nil)
+				mockService.EXPECT().GetBlobStatus(gomock.Any(), uint64(7), testBlobID).Return(complete, nil)
+				mockService.EXPECT().GetChunk(gomock.Any(), uint64(7), testBlobID, uint32(0)).Return([]byte("chunk"), nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "DownloadBlob_Fail_NotShared",
+			ctx:  shareContext("5"),
+			call: func(handler *BlobHandler, ctx context.Context) error {
+				return handler.DownloadBlob(&proto.DownloadBlobRequest{BlobId: testBlobID}, &fakeDownloadStream{ctx: ctx})
+			},
+			setupMock: func(_ *mocks.MockIBlobService, mockShares *mocks.MockIShareService) {
+				mockShares.EXPECT().ResolveSharedBlob(gomock.Any(), uint64(123), uint64(5), testBlobID).
+					Return(uint64(0), fmt.Errorf("blob %w", gophKeeperErrors.ErrNotFound))
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name: "GetBlobStatus_Fail_InvalidShareID",
+			ctx:  shareContext("five"),
+			call: func(handler *BlobHandler, ctx context.Context) error {
+				_, err := handler.GetBlobStatus(ctx, &proto.GetBlobStatusRequest{BlobId: testBlobID})
+				return err
+			},
+			setupMock:    func(_ *mocks.MockIBlobService, _ *mocks.MockIShareService) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "UploadBlob_Fail_ReadOnly",
+			ctx:  shareContext("5"),
+			call: func(handler *BlobHandler, ctx context.Context) error {
+				return handler.UploadBlob(&fakeUploadStream{ctx: ctx, requests: []*proto.UploadBlobRequest{{BlobId: testBlobID, ChunkCount: 1}}})
+			},
+			setupMock:    func(_ *mocks.MockIBlobService, _ *mocks.MockIShareService) {},
+			expectedCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockIBlobService(ctrl)
+			mockShares := mocks.NewMockIShareService(ctrl)
+			tt.setupMock(mockService, mockShares)
+
+			handler := NewBlobHandler(zap.NewNop(), mockService, mocks.NewMockISecretService(ctrl), mockShares)
+			assert.Equal(t, tt.expectedCode, status.Code(tt.call(handler, tt.ctx)))
+		})
+	}
+}
+
+func TestBlobHandler_Tenant(t *testing.T) {
+	complete := &models.BlobStatus{ID: testBlobID, ChunkCount: 1, ReceivedChunks: 1}
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		call         func(handler *BlobHandler, ctx context.Context) error
+		setupMock    func(mockService *mocks.MockIBlobService, mockSecrets *mocks.MockISecretService)
+		expectedCode codes.Code
+	}{
+		{
+			name: "UploadBlob_Success",
+			ctx:  orgContext("9"),
+			call: func(handler *BlobHandler, ctx context.Context) error {
+				return handler.UploadBlob(&fakeUploadStream{ctx: ctx, requests: []*proto.UploadBlobRequest{{BlobId: testBlobID, ChunkCount: 1}}})
+			},
+			setupMock: func(mockService *mocks.MockIBlobService, mockSecrets *mocks.MockISecretService) {
+				blob := &models.BlobStatus{ID: testBlobID, ChunkCount: 1}
+				mockSecrets.EXPECT().ResolveVault(gomock.Any(), uint64(123), uint64(9), models.OrgEditor).Return(uint64(70), nil)
+				mockService.EXPECT().StartUpload(gomock.Any(), uint64(70), testBlobID, uint32(1)).Return(blob, nil)
+				mockService.EXPECT().SaveChunk(gomock.Any(), uint64(70), blob, uint32(0), gomock.Any()).Return(nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "UploadBlob_Fail_Viewer",
+			ctx:  orgContext("9"),
+			call: func(handler *BlobHandler, ctx context.Context) error {
+				return handler.UploadBlob(&fakeUploadStream{ctx: ctx, requests: []*proto.UploadBlobRequest{{BlobId: testBlobID, ChunkCount: 1}}})
+			},
+			setupMock: func(_ *mocks.MockIBlobService, mockSecrets *mocks.MockISecretService) {
+				mockSecrets.EXPECT().ResolveVault(gomock.Any(), uint64(123), uint64(9), models.OrgEditor).
+					Return(uint64(0), fmt.Errorf("role %w", gophKeeperErrors.ErrPermissionDenied))
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name: "DownloadBlob_Success",
+			ctx:  orgContext("9"),
+			call: func(handler *BlobHandler, ctx context.Context) error {
+				return handler.DownloadBlob(&proto.DownloadBlobRequest{BlobId: testBlobID}, &fakeDownloadStream{ctx: ctx})
+			},
+			setupMock: func(mockService *mocks.MockIBlobService, mockSecrets *mocks.MockISecretService) {
+				mockSecrets.EXPECT().ResolveVault(gomock.Any(), uint64(123), uint64(9), models.OrgViewer).Return(uint64(70), nil)
+				mockService.EXPECT().GetBlobStatus(gomock.Any(), uint64(70), testBlobID).Return(complete, nil)
+				mockService.EXPECT().GetChunk(gomock.Any(), uint64(70), testBlobID, uint32(0)).Return([]byte("chunk"), nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "DownloadBlob_Success_Emergency",
+			ctx:  emergencyContext("5"),
+			call: func(handler *BlobHandler, ctx context.Context) error {
+				return handler.DownloadBlob(&proto.DownloadBlobRequest{BlobId: testBlobID}, &fakeDownloadStream{ctx: ctx})
+			},
+			setupMock: func(mockService *mocks.MockIBlobService, mockSecrets *mocks.MockISecretService) {
+				mockSecrets.EXPECT().ResolveEmergencyVault(gomock.Any(), uint64(123), uint64(5)).Return(uint64(7), nil)
+				mockService.EXPECT().GetBlobStatus(gomock.Any(), uint64(7), testBlobID).Return(complete, nil)
+				mockService.EXPECT().GetChunk(gomock.Any(), uint64(7), testBlobID, uint32(0)).Return([]byte("chunk"), nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "GetBlobStatus_Fail_EmergencyNotGranted",
+			ctx:  emergencyContext("5"),
+			call: func(handler *BlobHandler, ctx context.Context) error {
+				_, err := handler.GetBlobStatus(ctx, &proto.GetBlobStatusRequest{BlobId: testBlobID})
+				return err
+			},
+			setupMock: func(_ *mocks.MockIBlobService, mockSecrets *mocks.MockISecretService) {
+				mockSecrets.EXPECT().ResolveEmergencyVault(gomock.Any(), uint64(123), uint64(5)).
+					Return(uint64(0), fmt.Errorf("emergency access %w", gophKeeperErrors.ErrPermissionDenied))
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name: "UploadBlob_Fail_EmergencyReadOnly",
+			ctx:  emergencyContext("5"),
+			call: func(handler *BlobHandler, ctx context.Context) error {
+				return handler.UploadBlob(&fakeUploadStream{ctx: ctx, requests: []*proto.UploadBlobRequest{{BlobId: testBlobID, ChunkCount: 1}}})
+			},
+			setupMock:    func(_ *mocks.MockIBlobService, _ *mocks.MockISecretService) {},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name: "GetBlobStatus_Fail_NotMember",
+			ctx:  orgContext("9"),
+			call: func(handler *BlobHandler, ctx context.Context) error {
+				_, err := handler.GetBlobStatus(ctx, &proto.GetBlobStatusRequest{BlobId: testBlobID})
+				return err
+			},
+			setupMock: func(_ *mocks.MockIBlobService, mockSecrets *mocks.MockISecretService) {
+				mockSecrets.EXPECT().ResolveVault(gomock.Any(), uint64(123), uint64(9), models.OrgViewer).
+					Return(uint64(0), fmt.Errorf("member %w", gophKeeperErrors.ErrNotFound))
+			},
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockIBlobService(ctrl)
+			mockSecrets := mocks.NewMockISecretService(ctrl)
+			tt.setupMock(mockService, mockSecrets)
+
+			handler := NewBlobHandler(zap.NewNop(), mockService, mockSecrets, mocks.NewMockIShareService(ctrl))
+			assert.Equal(t, tt.expectedCode, status.Code(tt.call(handler, tt.ctx)))
+		})
+	}
+}
@@ -0,0 +1,161 @@
+// Package handlers содержит обработчики gRPC-запросов экстренного доступа к хранилищам.
+package handlers
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/events"
+	"beliaev-aa/GophKeeper/internal/server/service"
+	"beliaev-aa/GophKeeper/pkg/converter"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/pkg/proto"
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// EmergencyHandler реализует серверные функции экстренного доступа доверенных пользователей к хранилищам.
+// Секреты хранилища владельца доверенный пользователь читает через SecretHandler. Об изменениях доступа
+// уведомляется второй его участник.
+type EmergencyHandler struct {
+	proto.UnimplementedEmergencyServer
+	emergencyService service.IEmergencyService
+	hub              *events.Hub
+	logger           *zap.Logger
+}
+
+// NewEmergencyHandler создаёт новый экземпляр сервера экстренного доступа.
+// Возвращает инициализированный экземпляр EmergencyHandler.
+func NewEmergencyHandler(logger *zap.Logger, emergencyService service.IEmergencyService, hub *events.Hub) *EmergencyHandler {
+	return &EmergencyHandler{
+		emergencyService: emergencyService,
+		hub:              hub,
+		logger:           logger,
+	}
+}
+
+// AddContact назначает пользователя доверенным для хранилища текущего пользователя.
+// Возвращает InvalidArgument без логина или ключа хранилища, с недопустимым сроком ожидания
+// или при назначении самого себя и NotFound, если пользователь не найден.
+func (s *EmergencyHandler) AddContact(ctx context.Context, in *proto.AddEmergencyContactRequest) (*proto.AddEmergencyContactResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	access, err := s.emergencyService.AddContact(ctx, userID, &models.EmergencyAccess{
+		GranteeLogin: in.GranteeLogin,
+		WaitDays:     int(in.WaitDays),
+		VaultKey:     in.VaultKey,
+	})
+	if err != nil {
+		return nil, emergencyError(err)
+	}
+
+	s.notify(access.GranteeID, access.ID)
+	return &proto.AddEmergencyContactResponse{Access: converter.EmergencyAccessToProto(access)}, nil
+}
+
+// ListContacts возвращает доверенных пользователей текущего пользователя и состояние их доступа.
+func (s *EmergencyHandler) ListContacts(ctx context.Context, _ *emptypb.Empty) (*proto.ListEmergencyAccessResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	accesses, err := s.emergencyService.ListContacts(ctx, userID)
+	if err != nil {
+		return nil, emergencyError(err)
+	}
+	return &proto.ListEmergencyAccessResponse{Accesses: converter.EmergencyAccessesToProto(accesses)}, nil
+}
+
+// ListGrants возвращает хранилища, владельцы которых назначили текущего пользователя доверенным,
+// вместе с ключами хранилищ, доступ к которым предоставлен.
+func (s *EmergencyHandler) ListGrants(ctx context.Context, _ *emptypb.Empty) (*proto.ListEmergencyAccessResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	accesses, err := s.emergencyService.ListGrants(ctx, userID)
+	if err != nil {
+		return nil, emergencyError(err)
+	}
+	return &proto.ListEmergencyAccessResponse{Accesses: converter.EmergencyAccessesToProto(accesses)}, nil
+}
+
+// RequestAccess запрашивает экстренный доступ от имени доверенного пользователя и уведомляет владельца.
+// Возвращает NotFound, если доступ не назначен пользователю, и FailedPrecondition, если он уже запрошен.
+func (s *EmergencyHandler) RequestAccess(ctx context.Context, in *proto.EmergencyAccessRequest) (*emptypb.Empty, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	access, err := s.emergencyService.RequestAccess(ctx, userID, in.Id)
+	if err != nil {
+		return nil, emergencyError(err)
+	}
+
+	s.notify(access.GrantorID, access.ID)
+	return &emptypb.Empty{}, nil
+}
+
+// RejectAccess отклоняет запрос экстренного доступа или отзывает предоставленный доступ и уведомляет
+// доверенного пользователя. Возвращает NotFound, если доступ не принадлежит пользователю,
+// и FailedPrecondition, если доступ не запрашивался.
+func (s *EmergencyHandler) RejectAccess(ctx context.Context, in *proto.EmergencyAccessRequest) (*emptypb.Empty, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	access, err := s.emergencyService.RejectAccess(ctx, userID, in.Id)
+	if err != nil {
+		return nil, emergencyError(err)
+	}
+
+	s.notify(access.GranteeID, access.ID)
+	return &emptypb.Empty{}, nil
+}
+
+// DeleteAccess удаляет экстренный доступ по запросу владельца или доверенного пользователя
+// и уведомляет второго участника. Возвращает NotFound, если пользователь в доступе не участвует.
+func (s *EmergencyHandler) DeleteAccess(ctx context.Context, in *proto.EmergencyAccessRequest) (*emptypb.Empty, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	access, err := s.emergencyService.DeleteAccess(ctx, userID, in.Id)
+	if err != nil {
+		return nil, emergencyError(err)
+	}
+
+	if access.GrantorID == userID {
+		s.notify(access.GranteeID, access.ID)
+	} else {
+		s.notify(access.GrantorID, access.ID)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// notify уведомляет пользователя userID об изменении экстренного доступа accessID.
+func (s *EmergencyHandler) notify(userID, accessID uint64) {
+	s.hub.Publish(events.Event{
+		UserID:   userID,
+		SecretID: accessID,
+		Kind:     events.EmergencyAccessChanged,
+	})
+}
+
+// emergencyError преобразует ошибку сервиса экстренного доступа в статус gRPC.
+func emergencyError(err error) error {
+	switch {
+	case errors.Is(err, gophKeeperErrors.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, gophKeeperErrors.ErrEmergencyState):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, service.ErrInvalidEmergencyRequest):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/events"
+	"beliaev-aa/GophKeeper/internal/server/service"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/pkg/proto"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"context"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"testing"
+)
+
+func TestEmergencyHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		call         func(handler *EmergencyHandler, ctx context.Context) error
+		setupMock    func(mockService *mocks.MockIEmergencyService)
+		expectedCode codes.Code
+		notified     uint64
+	}{
+		{
+			name: "AddContact_Success",
+			ctx:  userContext(),
+			call: func(handler *EmergencyHandler, ctx context.Context) error {
+				resp, err := handler.AddContact(ctx, &proto.AddEmergencyContactRequest{GranteeLogin: "bob", WaitDays: 7, VaultKey: []byte("wrapped")})
+				if err == nil && resp.Access.Id != 5 {
+					return fmt.Errorf("expected access 5, got %d", resp.Access.Id)
+				}
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIEmergencyService) {
+				access := &models.EmergencyAccess{GranteeLogin: "bob", WaitDays: 7, VaultKey: []byte("wrapped")}
+				mockService.EXPECT().AddContact(gomock.Any(), uint64(123), access).
+					Return(&models.EmergencyAccess{ID: 5, GrantorID: 123, GranteeID: 2, GranteeLogin: "bob"}, nil)
+			},
+			expectedCode: codes.OK,
+			notified:     2,
+		},
+		{
+			name: "AddContact_Fail_Invalid",
+			ctx:  userContext(),
+			call: func(handler *EmergencyHandler, ctx context.Context) error {
+				_, err := handler.AddContact(ctx, &proto.AddEmergencyContactRequest{GranteeLogin: "bob"})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIEmergencyService) {
+				mockService.EXPECT().AddContact(gomock.Any(), uint64(123), gomock.Any()).Return(nil, service.ErrInvalidEmergencyRequest)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "ListContacts_Fail_NoUserID",
+			ctx:  context.Background(),
+			call: func(handler *EmergencyHandler, ctx context.Context) error {
+				_, err := handler.ListContacts(ctx, &emptypb.Empty{})
+				return err
+			},
+			setupMock:    func(_ *mocks.MockIEmergencyService) {},
+			expectedCode: codes.Internal,
+		},
+		{
+			name: "ListGrants_Success",
+			ctx:  userContext(),
+			call: func(handler *EmergencyHandler, ctx context.Context) error {
+				resp, err := handler.ListGrants(ctx, &emptypb.Empty{})
+				if err == nil && (len(resp.Accesses) != 1 || string(resp.Accesses[0].VaultKey) != "wrapped") {
+					return fmt.Errorf("expected granted access with vault key, got %v", resp.Accesses)
+				}
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIEmergencyService) {
+				mockService.EXPECT().ListGrants(gomock.Any(), uint64(123)).
+					Return([]*models.EmergencyAccess{{ID: 5, GrantorLogin: "alice", Status: models.EmergencyGranted, VaultKey: []byte("wrapped")}}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "RequestAccess_Success",
+			ctx:  userContext(),
+			call: func(handler *EmergencyHandler, ctx context.Context) error {
+				_, err := handler.RequestAccess(ctx, &proto.EmergencyAccessRequest{Id: 5})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIEmergencyService) {
+				mockService.EXPECT().RequestAccess(gomock.Any(), uint64(123), uint64(5)).
+					Return(&models.EmergencyAccess{ID: 5, GrantorID: 1, GranteeID: 123}, nil)
+			},
+			expectedCode: codes.OK,
+			notified:     1,
+		},
+		{
+			name: "RequestAccess_Fail_AlreadyRequested",
+			ctx:  userContext(),
+			call: func(handler *EmergencyHandler, ctx context.Context) error {
+				_, err := handler.RequestAccess(ctx, &proto.EmergencyAccessRequest{Id: 5})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIEmergencyService) {
+				mockService.EXPECT().RequestAccess(gomock.Any(), uint64(123), uint64(5)).Return(nil, gophKeeperErrors.ErrEmergencyState)
+			},
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name: "RejectAccess_Success",
+			ctx:  userContext(),
+			call: func(handler *EmergencyHandler, ctx context.Context) error {
+				_, err := handler.RejectAccess(ctx, &proto.EmergencyAccessRequest{Id: 5})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIEmergencyService) {
+				mockService.EXPECT().RejectAccess(gomock.Any(), uint64(123), uint64(5)).
+					Return(&models.EmergencyAccess{ID: 5, GrantorID: 123, GranteeID: 2}, nil)
+			},
+			expectedCode: codes.OK,
+			notified:     2,
+		},
+		{
+			name: "RejectAccess_Fail_NotFound",
+			ctx:  userContext(),
+			call: func(handler *EmergencyHandler, ctx context.Context) error {
+				_, err := handler.RejectAccess(ctx, &proto.EmergencyAccessRequest{Id: 5})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIEmergencyService) {
+				mockService.EXPECT().RejectAccess(gomock.Any(), uint64(123), uint64(5)).Return(nil, gophKeeperErrors.ErrNotFound)
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name: "DeleteAccess_Success_Grantee",
+			ctx:  userContext(),
+			call: func(handler *EmergencyHandler, ctx context.Context) error {
+				_, err := handler.DeleteAccess(ctx, &proto.EmergencyAccessRequest{Id: 5})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIEmergencyService) {
+				mockService.EXPECT().DeleteAccess(gomock.Any(), uint64(123), uint64(5)).
+					Return(&models.EmergencyAccess{ID: 5, GrantorID: 1, GranteeID: 123}, nil)
+			},
+			expectedCode: codes.OK,
+			notified:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockIEmergencyService(ctrl)
+			tt.setupMock(mockService)
+
+			hub := events.NewHub(zap.NewNop())
+			var sub *events.Subscription
+			if tt.notified != 0 {
+				sub = hub.Subscribe(tt.notified, 1, 1)
+				defer hub.Unsubscribe(sub)
+			}
+
+			err := tt.call(NewEmergencyHandler(zap.NewNop(), mockService, hub), tt.ctx)
+			assert.Equal(t, tt.expectedCode, status.Code(err), err)
+
+			if sub != nil {
+				select {
+				case event := <-sub.Events:
+					assert.Equal(t, events.Event{UserID: tt.notified, SecretID: 5, Kind: events.EmergencyAccessChanged}, event)
+				default:
+					t.Errorf("Expected user %d to be notified", tt.notified)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+// Package handlers содержит обработчики gRPC-запросов управления папками и метками секретов.
+package handlers
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/service"
+	"beliaev-aa/GophKeeper/pkg/converter"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/pkg/proto"
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// FolderHandler реализует серверные функции для управления папками и метками секретов.
+type FolderHandler struct {
+	proto.UnimplementedFoldersServer
+	folderService service.IFolderService
+	secretService service.ISecretService
+	logger        *zap.Logger
+}
+
+// NewFolderHandler создаёт новый экземпляр сервера папок и меток.
+// Сервис секретов проверяет доступ к хранилищам организаций: читатель получает их папки и метки,
+// а редактор изменяет их.
+// Возвращает инициализированный экземпляр FolderHandler.
+func NewFolderHandler(logger *zap.Logger, folderService service.IFolderService, secretService service.ISecretService) *FolderHandler {
+	return &FolderHandler{
+		folderService: folderService,
+		secretService: secretService,
+		logger:        logger,
+	}
+}
+
+// ListFolders возвращает все папки пользователя.
+func (s *FolderHandler) ListFolders(ctx context.Context, _ *emptypb.Empty) (*proto.ListFoldersResponse, error) {
+	userID, err := resolveTenant(ctx, s.secretService, models.OrgViewer)
+	if err != nil {
+		return nil, err
+	}
+	folders, err := s.folderService.ListFolders(ctx, userID)
+	if err != nil {
+		return nil, folderError(err)
+	}
+	return &proto.ListFoldersResponse{Folders: converter.FoldersToProto(folders)}, nil
+}
+
+// CreateFolder создаёт папку пользователя и возвращает её.
+// Возвращает ошибку InvalidArgument при недопустимом названии, NotFound, если родительская папка не найдена,
+// и AlreadyExists, если в родительской папке уже есть папка с таким названием.
+func (s *FolderHandler) CreateFolder(ctx context.Context, in *proto.CreateFolderRequest) (*proto.CreateFolderResponse, error) {
+	userID, err := resolveTenant(ctx, s.secretService, models.OrgEditor)
+	if err != nil {
+		return nil, err
+	}
+	folder, err := s.folderService.CreateFolder(ctx, &models.Folder{
+		UserID:   userID,
+		ParentID: in.ParentId,
+		Name:     in.Name,
+	})
+	if err != nil {
+		return nil, folderError(err)
+	}
+	return &proto.CreateFolderResponse{Folder: converter.FolderToProto(folder)}, nil
+}
+
+// UpdateFolder переименовывает папку пользователя или перемещает её в другую родительскую папку.
+// Возвращает ошибку InvalidArgument при недопустимом названии или перемещении папки во вложенную в неё папку,
+// NotFound, если папка не найдена, и AlreadyExists, если название занято.
+func (s *FolderHandler) UpdateFolder(ctx context.Context, in *proto.UpdateFolderRequest) (*emptypb.Empty, error) {
+	userID, err := resolveTenant(ctx, s.secretService, models.OrgEditor)
+	if err != nil {
+		return nil, err
+	}
+	err = s.folderService.UpdateFolder(ctx, &models.Folder{
+		ID:       in.Id,
+		UserID:   userID,
+		ParentID: in.ParentId,
+		Name:     in.Name,
+	})
+	if err != nil {
+		return nil, folderError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// DeleteFolder удаляет папку пользователя вместе с вложенными папками.
+// Возвращает ошибку NotFound, если папка не найдена.
+func (s *FolderHandler) DeleteFolder(ctx context.Context, in *proto.DeleteFolderRequest) (*emptypb.Empty, error) {
+	userID, err := resolveTenant(ctx, s.secretService, models.OrgEditor)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.folderService.DeleteFolder(ctx, userID, in.Id); err != nil {
+		return nil, folderError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// ListTags возвращает все метки пользователя.
+func (s *FolderHandler) ListTags(ctx context.Context, _ *emptypb.Empty) (*proto.ListTagsResponse, error) {
+	userID, err := resolveTenant(ctx, s.secretService, models.OrgViewer)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.folderService.ListTags(ctx, userID)
+	if err != nil {
+		return nil, folderError(err)
+	}
+	return &proto.ListTagsResponse{Tags: converter.TagsToProto(tags)}, nil
+}
+
+// CreateTag создаёт метку пользователя и возвращает её.
+// Возвращает ошибку InvalidArgument при недопустимом названии и AlreadyExists, если название занято.
+func (s *FolderHandler) CreateTag(ctx context.Context, in *proto.CreateTagRequest) (*proto.CreateTagResponse, error) {
+	userID, err := resolveTenant(ctx, s.secretService, models.OrgEditor)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := s.folderService.CreateTag(ctx, &models.Tag{UserID: userID, Name: in.Name})
+	if err != nil {
+		return nil, folderError(err)
+	}
+	return &proto.CreateTagResponse{Tag: converter.TagToProto(tag)}, nil
+}
+
+// UpdateTag переименовывает метку пользователя.
+// Возвращает ошибку InvalidArgument при недопустимом названии, NotFound, если метка не найдена,
+// и AlreadyExists, если название занято.
+func (s *FolderHandler) UpdateTag(ctx context.Context, in *proto.UpdateTagRequest) (*emptypb.Empty, error) {
+	userID, err := resolveTenant(ctx, s.secretService, models.OrgEditor)
+	if err != nil {
+		return nil, err
+	}
+	err = s.folderService.UpdateTag(ctx, &models.Tag{ID: in.Id, UserID: userID, Name: in.Name})
+	if err != nil {
+		return nil, folderError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// DeleteTag удаляет метку пользователя и снимает её с секретов.
+// Возвращает ошибку NotFound, если метка не найдена.
+func (s *FolderHandler) DeleteTag(ctx context.Context, in *proto.DeleteTagRequest) (*emptypb.Empty, error) {
+	userID, err := resolveTenant(ctx, s.secretService, models.OrgEditor)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.folderService.DeleteTag(ctx, userID, in.Id); err != nil {
+		return nil, folderError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// folderError преобразует ошибку сервиса папок и меток в ошибку gRPC с соответствующим кодом.
+func folderError(err error) error {
+	switch {
+	case errors.Is(err, gophKeeperErrors.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, gophKeeperErrors.ErrAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, gophKeeperErrors.ErrInvalidFolder), errors.Is(err, service.ErrInvalidLabelName):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/service"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/pkg/proto"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"testing"
+)
+
+func TestFolderHandler_Folders(t *testing.T) {
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		call         func(handler *FolderHandler, ctx context.Context) error
+		setupMock    func(mockService *mocks.MockIFolderService)
+		expectedCode codes.Code
+	}{
+		{
+			name: "ListFolders_Success",
+			ctx:  userContext(),
+			call: func(handler *FolderHandler, ctx context.Context) error {
+				resp, err := handler.ListFolders(ctx, &emptypb.Empty{})
+				if err == nil && len(resp.Folders) != 1 {
+					return fmt.Errorf("expected 1 folder, got %d", len(resp.Folders))
+				}
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIFolderService) {
+				mockService.EXPECT().ListFolders(gomock.Any(), uint64(123)).Return([]*models.Folder{{ID: 1, Name: "Banks"}}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "ListFolders_Fail_NoUserID",
+			ctx:  context.Background(),
+			call: func(handler *FolderHandler, ctx context.Context) error {
+				_, err := handler.ListFolders(ctx, &emptypb.Empty{})
+				return err
+			},
+			setupMock:    func(_ *mocks.MockIFolderService) {},
+			expectedCode: codes.Internal,
+		},
+		{
+			name: "CreateFolder_Success",
+			ctx:  userContext(),
+			call: func(handler *FolderHandler, ctx context.Context) error {
+				resp, err := handler.CreateFolder(ctx, &proto.CreateFolderRequest{ParentId: 1, Name: "Cards"})
+				if err == nil && resp.Folder.Id != 2 {
+					return fmt.Errorf("expected folder 2, got %d", resp.Folder.Id)
+				}
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIFolderService) {
+				mockService.EXPECT().CreateFolder(gomock.Any(), &models.Folder{UserID: 123, ParentID: 1, Name: "Cards"}).
+					Return(&models.Folder{ID: 2, UserID: 123, ParentID: 1, Name: "Cards"}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "CreateFolder_Fail_Duplicate",
+			ctx:  userContext(),
+			call: func(handler *FolderHandler, ctx context.Context) error {
+				_, err := handler.CreateFolder(ctx, &proto.CreateFolderRequest{Name: "Banks"})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIFolderService) {
+				mockService.EXPECT().CreateFolder(gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("failed to create folder: %w", gophKeeperErrors.ErrAlreadyExists))
+			},
+			expectedCode: codes.AlreadyExists,
+		},
+		{
+			name: "UpdateFolder_Fail_Cycle",
+			ctx:  userContext(),
+			call: func(handler *FolderHandler, ctx context.Context) error {
+				_, err := handler.UpdateFolder(ctx, &proto.UpdateFolderRequest{Id: 1, ParentId: 2, Name: "Banks"})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIFolderService) {
+				mockService.EXPECT().UpdateFolder(gomock.Any(), &models.Folder{ID: 1, UserID: 123, ParentID: 2, Name: "Banks"}).
+					Return(fmt.Errorf("failed to update folder: %w", gophKeeperErrors.ErrInvalidFolder))
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "DeleteFolder_Fail_NotFound",
+			ctx:  userContext(),
+			call: func(handler *FolderHandler, ctx context.Context) error {
+				_, err := handler.DeleteFolder(ctx, &proto.DeleteFolderRequest{Id: 5})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIFolderService) {
+				mockService.EXPECT().DeleteFolder(gomock.Any(), uint64(123), uint64(5)).
+					Return(fmt.Errorf("folder %w", gophKeeperErrors.ErrNotFound))
+			},
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockIFolderService(ctrl)
+			tt.setupMock(mockService)
+
+			err := tt.call(NewFolderHandler(zap.NewNop(), mockService, mocks.NewMockISecretService(ctrl)), tt.ctx)
+			assert.Equal(t, tt.expectedCode, status.Code(err), err)
+		})
+	}
+}
+
+func TestFolderHandler_Tags(t *testing.T) {
+	tests := []struct {
+		name         string
+		call         func(handler *FolderHandler, ctx context.Context) error
+		setupMock    func(mockService *mocks.MockIFolderService)
+		expectedCode codes.Code
+	}{
+		{
+			name: "ListTags_Fail_Internal",
+			call: func(handler *FolderHandler, ctx context.Context) error {
+				_, err := handler.ListTags(ctx, &emptypb.Empty{})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIFolderService) {
+				mockService.EXPECT().ListTags(gomock.Any(), uint64(123)).Return(nil, errors.New("db error"))
+			},
+			expectedCode: codes.Internal,
+		},
+		{
+			name: "CreateTag_Success",
+			call: func(handler *FolderHandler, ctx context.Context) error {
+				resp, err := handler.CreateTag(ctx, &proto.CreateTagRequest{Name: "work"})
+				if err == nil && resp.Tag.Id != 4 {
+					return fmt.Errorf("expected tag 4, got %d", resp.Tag.Id)
+				}
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIFolderService) {
+				mockService.EXPECT().CreateTag(gomock.Any(), &models.Tag{UserID: 123, Name: "work"}).
+					Return(&models.Tag{ID: 4, UserID: 123, Name: "work"}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "UpdateTag_Fail_InvalidName",
+			call: func(handler *FolderHandler, ctx context.Context) error {
+				_, err := handler.UpdateTag(ctx, &proto.UpdateTagRequest{Id: 4})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIFolderService) {
+				mockService.EXPECT().UpdateTag(gomock.Any(), &models.Tag{ID: 4, UserID: 123}).Return(service.ErrInvalidLabelName)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "DeleteTag_Success",
+			call: func(handler *FolderHandler, ctx context.Context) error {
+				_, err := handler.DeleteTag(ctx, &proto.DeleteTagRequest{Id: 4})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIFolderService) {
+				mockService.EXPECT().DeleteTag(gomock.Any(), uint64(123), uint64(4)).Return(nil)
+			},
+			expectedCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockIFolderService(ctrl)
+			tt.setupMock(mockService)
+
+			err := tt.call(NewFolderHandler(zap.NewNop(), mockService, mocks.NewMockISecretService(ctrl)), userContext())
+			assert.Equal(t, tt.expectedCode, status.Code(err), err)
+		})
+	}
+}
+
+func TestFolderHandler_Organization(t *testing.T) {
+	tests := []struct {
+		name         string
+		call         func(handler *FolderHandler, ctx context.Context) error
+		setupMock    func(mockService *mocks.MockIFolderService, mockSecrets *mocks.MockISecretService)
+		expectedCode codes.Code
+	}{
+		{
+			name: "ListFolders_Success",
+			call: func(handler *FolderHandler, ctx context.Context) error {
+				_, err := handler.ListFolders(ctx, &emptypb.Empty{})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIFolderService, mockSecrets *mocks.MockISecretService) {
+				mockSecrets.EXPECT().ResolveVault(gomock.Any(), uint64(123), uint64(9), models.OrgViewer).Return(uint64(70), nil)
+				mockService.EXPECT().ListFolders(gomock.Any(), uint64(70)).Return(nil, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "CreateTag_Success",
+			call: func(handler *FolderHandler, ctx context.Context) error {
+				_, err := handler.CreateTag(ctx, &proto.CreateTagRequest{Name: "work"})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIFolderService, mockSecrets *mocks.MockISecretService) {
+				mockSecrets.EXPECT().ResolveVault(gomock.Any(), uint64(123), uint64(9), models.OrgEditor).Return(uint64(70), nil)
+				mockService.EXPECT().CreateTag(gomock.Any(), &models.Tag{UserID: 70, Name: "work"}).
+					Return(&models.Tag{ID: 4, UserID: 70, Name: "work"}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "DeleteFolder_Fail_Viewer",
+			call: func(handler *FolderHandler, ctx context.Context) error {
+				_, err := handler.DeleteFolder(ctx, &proto.DeleteFolderRequest{Id: 5})
+				return err
+			},
+			setupMock: func(_ *mocks.MockIFolderService, mockSecrets *mocks.MockISecretService) {
+				mockSecrets.EXPECT().ResolveVault(gomock.Any(), uint64(123), uint64(9), models.OrgEditor).
+					Return(uint64(0), fmt.Errorf("role %w", gophKeeperErrors.ErrPermissionDenied))
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockIFolderService(ctrl)
+			mockSecrets := mocks.NewMockISecretService(ctrl)
+			tt.setupMock(mockService, mockSecrets)
+
+			err := tt.call(NewFolderHandler(zap.NewNop(), mockService, mockSecrets), orgContext("9"))
+			assert.Equal(t, tt.expectedCode, status.Code(err), err)
+		})
+	}
+}
@@ -2,32 +2,27 @@
 package handlers
 
 import (
+	"beliaev-aa/GophKeeper/internal/server/events"
 	"beliaev-aa/GophKeeper/pkg/proto"
-	"errors"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"slices"
-	"sync"
 )
 
-// Представляет подписчика на уведомления.
-type subscriber struct {
-	stream   proto.Notification_SubscribeServer
-	id       uint64
-	finished chan<- bool
-}
-
-// NotificationHandler управляет подписчиками на уведомления.
+// NotificationHandler управляет потоками уведомлений клиентов.
 type NotificationHandler struct {
 	proto.UnimplementedNotificationServer
-	logger      *zap.Logger
-	subscribers sync.Map
+	hub    *events.Hub
+	logger *zap.Logger
 }
 
 // NewNotificationHandler создаёт новый экземпляр NotificationHandler.
-func NewNotificationHandler(logger *zap.Logger) *NotificationHandler {
-	return &NotificationHandler{logger: logger}
+// Принимает общий хаб событий, в который публикуют изменения остальные обработчики.
+func NewNotificationHandler(logger *zap.Logger, hub *events.Hub) *NotificationHandler {
+	return &NotificationHandler{
+		hub:    hub,
+		logger: logger,
+	}
 }
 
 // Subscribe обрабатывает подписку клиента на уведомления.
@@ -40,26 +35,28 @@ func (s *NotificationHandler) Subscribe(in *proto.SubscribeRequest, stream proto
 		return status.Error(codes.Internal, err.Error())
 	}
 
-	s.logger.Info("received subscribe from client", zap.Int("client_id", int(in.Id)), zap.Int("user_id", int(userID)))
-
-	fin := make(chan bool)
-	v, ok := s.subscribers.Load(userID)
-	var subscribers []subscriber
-	if ok {
-		subscribers, ok = v.([]subscriber)
-		if !ok {
-			return status.Error(codes.Internal, "failed to cast subscribers")
-		}
+	sessionID, err := extractSessionID(ctx)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
 	}
 
-	subscribers = append(subscribers, subscriber{stream: stream, id: in.Id, finished: fin})
-	s.subscribers.Store(userID, subscribers)
+	s.logger.Info("received subscribe from client", zap.Int("client_id", int(in.Id)), zap.Int("user_id", int(userID)))
+
+	sub := s.hub.Subscribe(userID, in.Id, sessionID)
+	defer s.hub.Unsubscribe(sub)
 
 	for {
 		select {
-		case <-fin:
-			s.logger.Info("closing stream for client", zap.Int("client_id", int(in.Id)))
-			return nil
+		case event, ok := <-sub.Events:
+			if !ok {
+				s.logger.Info("closing stream for client", zap.Int("client_id", int(in.Id)))
+				return nil
+			}
+			resp := &proto.SubscribeResponse{Id: event.SecretID, Event: eventKindToProto(event.Kind), OrgId: event.OrgID}
+			if err = stream.Send(resp); err != nil {
+				s.logger.Error("failed to send notification to client", zap.Error(err))
+				return err
+			}
 		case <-ctx.Done():
 			s.logger.Info("client has disconnected", zap.Int("client_id", int(in.Id)))
 			return nil
@@ -67,40 +64,22 @@ func (s *NotificationHandler) Subscribe(in *proto.SubscribeRequest, stream proto
 	}
 }
 
-// notifyClients отправляет уведомления всем подписчикам, за исключением инициатора изменений.
-// Возвращает ошибку, если не удаётся отправить уведомление или подписчиков нет.
-func (s *NotificationHandler) notifyClients(userID uint64, clientID uint64, ID uint64, updated bool) error {
-	v, ok := s.subscribers.Load(userID)
-	if !ok {
-		return errors.New("no subscribers")
+// eventKindToProto конвертирует тип события хаба в тип события protobuf.
+func eventKindToProto(kind events.Kind) proto.EventType {
+	switch kind {
+	case events.SecretCreated:
+		return proto.EventType_EVENT_TYPE_SECRET_CREATED
+	case events.SecretUpdated:
+		return proto.EventType_EVENT_TYPE_SECRET_UPDATED
+	case events.SecretDeleted:
+		return proto.EventType_EVENT_TYPE_SECRET_DELETED
+	case events.PasswordChanged:
+		return proto.EventType_EVENT_TYPE_PASSWORD_CHANGED
+	case events.AccountDeleted:
+		return proto.EventType_EVENT_TYPE_ACCOUNT_DELETED
+	case events.EmergencyAccessChanged:
+		return proto.EventType_EVENT_TYPE_EMERGENCY_ACCESS
+	default:
+		return proto.EventType_EVENT_TYPE_UNSPECIFIED
 	}
-
-	subs, ok := v.([]subscriber)
-	if !ok {
-		return errors.New("failed to cast to subs")
-	}
-
-	var unsubscribes []int
-	for i, sub := range subs {
-		if sub.id == clientID {
-			continue
-		}
-		resp := &proto.SubscribeResponse{Id: ID, Updated: updated}
-		if err := sub.stream.Send(resp); err != nil {
-			s.logger.Error("failed to send notification to client", zap.Error(err))
-			sub.finished <- true
-			unsubscribes = append(unsubscribes, i)
-		}
-	}
-
-	for _, unsub := range unsubscribes {
-		subs = slices.Delete(subs, unsub, unsub)
-	}
-	if len(subs) > 0 {
-		s.subscribers.Store(userID, subs)
-	} else {
-		s.subscribers.Delete(userID)
-	}
-
-	return nil
 }
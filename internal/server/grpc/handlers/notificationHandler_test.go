@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"beliaev-aa/GophKeeper/internal/server/events"
+	"beliaev-aa/GophKeeper/pkg/consts"
 	"beliaev-aa/GophKeeper/pkg/proto"
 	"context"
 	"github.com/stretchr/testify/assert"
@@ -8,6 +10,7 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc/metadata"
 	"testing"
+	"time"
 )
 
 type mockStream struct {
@@ -44,7 +47,7 @@ func (m *mockStream) RecvMsg(_ any) error {
 
 func TestNotificationHandler_Subscribe(t *testing.T) {
 	logger := zap.NewNop()
-	handler := NewNotificationHandler(logger)
+	handler := NewNotificationHandler(logger, events.NewHub(logger))
 
 	tests := []struct {
 		name      string
@@ -76,6 +79,17 @@ func TestNotificationHandler_Subscribe(t *testing.T) {
 			},
 			expectErr: "rpc error: code = Internal desc = failed to extract user id from context",
 		},
+		{
+			name: "Subscribe_MissingSessionID",
+			setup: func(stream *mockStream) {
+				ctx := context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123))
+				stream.On("Context").Return(ctx)
+			},
+			input: &proto.SubscribeRequest{
+				Id: 1,
+			},
+			expectErr: "rpc error: code = Internal desc = failed to extract session id from context",
+		},
 	}
 
 	for _, tc := range tests {
@@ -93,64 +107,113 @@ func TestNotificationHandler_Subscribe(t *testing.T) {
 	}
 }
 
-func TestNotificationHandler_NotifyClients(t *testing.T) {
+func TestNotificationHandler_Subscribe_ReceivesPublishedEvents(t *testing.T) {
 	logger := zap.NewNop()
-	handler := NewNotificationHandler(logger)
+	hub := events.NewHub(logger)
+	handler := NewNotificationHandler(logger, hub)
 
 	tests := []struct {
-		name      string
-		setup     func(handler *NotificationHandler)
-		userID    uint64
-		clientID  uint64
-		ID        uint64
-		updated   bool
-		expectErr string
+		name     string
+		event    events.Event
+		expected *proto.SubscribeResponse
 	}{
 		{
-			name: "NotifyClients_Success",
-			setup: func(handler *NotificationHandler) {
-				stream := new(mockStream)
-				stream.On("Send", &proto.SubscribeResponse{Id: 1, Updated: true}).Return(nil)
-
-				handler.subscribers.Store(uint64(123), []subscriber{
-					{
-						stream:   stream,
-						id:       2,
-						finished: make(chan bool),
-					},
-				})
-			},
-			userID:    123,
-			clientID:  2,
-			ID:        1,
-			updated:   true,
-			expectErr: "",
+			name:     "Created",
+			event:    events.Event{UserID: 123, ClientID: 2, SecretID: 1, Kind: events.SecretCreated},
+			expected: &proto.SubscribeResponse{Id: 1, Event: proto.EventType_EVENT_TYPE_SECRET_CREATED},
 		},
 		{
-			name: "NotifyClients_NoSubscribers",
-			setup: func(handler *NotificationHandler) {
-				// Оставляем пустую карту подписчиков.
-			},
-			userID:    123,
-			clientID:  2,
-			ID:        1,
-			updated:   true,
-			expectErr: "",
+			name:     "Updated",
+			event:    events.Event{UserID: 123, ClientID: 2, SecretID: 1, Kind: events.SecretUpdated},
+			expected: &proto.SubscribeResponse{Id: 1, Event: proto.EventType_EVENT_TYPE_SECRET_UPDATED},
+		},
+		{
+			name:     "Deleted",
+			event:    events.Event{UserID: 123, ClientID: 2, SecretID: 1, Kind: events.SecretDeleted},
+			expected: &proto.SubscribeResponse{Id: 1, Event: proto.EventType_EVENT_TYPE_SECRET_DELETED},
+		},
+		{
+			name:     "PasswordChanged",
+			event:    events.Event{UserID: 123, Kind: events.PasswordChanged},
+			expected: &proto.SubscribeResponse{Event: proto.EventType_EVENT_TYPE_PASSWORD_CHANGED},
+		},
+		{
+			name:     "AccountDeleted",
+			event:    events.Event{UserID: 123, Kind: events.AccountDeleted},
+			expected: &proto.SubscribeResponse{Event: proto.EventType_EVENT_TYPE_ACCOUNT_DELETED},
+		},
+		{
+			name:     "EmergencyAccessChanged",
+			event:    events.Event{UserID: 123, SecretID: 5, Kind: events.EmergencyAccessChanged},
+			expected: &proto.SubscribeResponse{Id: 5, Event: proto.EventType_EVENT_TYPE_EMERGENCY_ACCESS},
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			if tc.setup != nil {
-				tc.setup(handler)
-			}
+			ctx, cancel := context.WithCancel(sessionContext(123, 7))
+			defer cancel()
 
-			err := handler.notifyClients(tc.userID, tc.clientID, tc.ID, tc.updated)
-			if tc.expectErr != "" {
-				assert.EqualError(t, err, tc.expectErr)
-			} else {
-				assert.NoError(t, err)
-			}
+			received := make(chan *proto.SubscribeResponse, 1)
+			stream := new(mockStream)
+			stream.On("Context").Return(ctx)
+			stream.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+				select {
+				case received <- args.Get(0).(*proto.SubscribeResponse):
+				default:
+				}
+			}).Return(nil)
+
+			done := make(chan error, 1)
+			go func() {
+				done <- handler.Subscribe(&proto.SubscribeRequest{Id: 1}, stream)
+			}()
+
+			assert.Eventually(t, func() bool {
+				hub.Publish(tc.event)
+				return len(received) > 0
+			}, time.Second, 10*time.Millisecond)
+			resp := <-received
+			assert.Equal(t, tc.expected.GetId(), resp.GetId())
+			assert.Equal(t, tc.expected.GetEvent(), resp.GetEvent())
+
+			cancel()
+			assert.NoError(t, <-done)
 		})
 	}
 }
+
+func TestNotificationHandler_Subscribe_ClosedOnRevoke(t *testing.T) {
+	logger := zap.NewNop()
+	hub := events.NewHub(logger)
+	handler := NewNotificationHandler(logger, hub)
+
+	stream := new(mockStream)
+	stream.On("Context").Return(sessionContext(123, 7))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- handler.Subscribe(&proto.SubscribeRequest{Id: 1}, stream)
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("Subscribe returned before revoke: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	hub.Disconnect(123, 7)
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Expected stream of revoked session to be closed")
+	}
+}
+
+// sessionContext возвращает контекст запроса, прошедшего аутентификацию в сессии sessionID.
+func sessionContext(userID, sessionID uint64) context.Context {
+	ctx := context.WithValue(context.Background(), consts.CtxUserIDKey, userID)
+	return context.WithValue(ctx, consts.CtxSessionIDKey, sessionID)
+}
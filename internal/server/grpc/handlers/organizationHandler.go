@@ -0,0 +1,154 @@
+// Package handlers содержит обработчики gRPC-запросов управления организациями и их участниками.
+package handlers
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/service"
+	"beliaev-aa/GophKeeper/pkg/converter"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/pkg/proto"
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// OrganizationHandler реализует серверные функции для управления организациями и их участниками.
+// Секреты хранилищ организаций обрабатывает SecretHandler.
+type OrganizationHandler struct {
+	proto.UnimplementedOrganizationsServer
+	orgService service.IOrganizationService
+	logger     *zap.Logger
+}
+
+// NewOrganizationHandler создаёт новый экземпляр сервера управления организациями.
+// Возвращает инициализированный экземпляр OrganizationHandler.
+func NewOrganizationHandler(logger *zap.Logger, orgService service.IOrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		orgService: orgService,
+		logger:     logger,
+	}
+}
+
+// CreateOrganization создаёт организацию, владельцем которой становится пользователь.
+// Возвращает InvalidArgument без названия или ключа хранилища.
+func (s *OrganizationHandler) CreateOrganization(ctx context.Context, in *proto.CreateOrganizationRequest) (*proto.CreateOrganizationResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	org, err := s.orgService.CreateOrganization(ctx, userID, &models.Organization{Name: in.Name, VaultKey: in.VaultKey})
+	if err != nil {
+		return nil, organizationError(err)
+	}
+	return &proto.CreateOrganizationResponse{Organization: converter.OrganizationToProto(org)}, nil
+}
+
+// ListOrganizations возвращает организации пользователя вместе с его ролью и ключом хранилища.
+func (s *OrganizationHandler) ListOrganizations(ctx context.Context, _ *emptypb.Empty) (*proto.ListOrganizationsResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	orgs, err := s.orgService.ListOrganizations(ctx, userID)
+	if err != nil {
+		return nil, organizationError(err)
+	}
+	return &proto.ListOrganizationsResponse{Organizations: converter.OrganizationsToProto(orgs)}, nil
+}
+
+// ListMembers возвращает участников организации. Возвращает NotFound, если пользователь в ней не состоит.
+func (s *OrganizationHandler) ListMembers(ctx context.Context, in *proto.ListOrgMembersRequest) (*proto.ListOrgMembersResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	members, err := s.orgService.ListMembers(ctx, userID, in.OrgId)
+	if err != nil {
+		return nil, organizationError(err)
+	}
+	return &proto.ListOrgMembersResponse{Members: converter.OrgMembersToProto(members)}, nil
+}
+
+// AddMember добавляет пользователя в организацию или заново выдаёт ему ключ хранилища.
+// Возвращает InvalidArgument без логина, ключа или роли, NotFound, если организация или пользователь
+// не найдены, и PermissionDenied, если роли пользователя недостаточно.
+func (s *OrganizationHandler) AddMember(ctx context.Context, in *proto.AddOrgMemberRequest) (*proto.AddOrgMemberResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	member, err := s.orgService.AddMember(ctx, userID, &models.OrgMember{
+		OrgID:    in.OrgId,
+		Login:    in.Login,
+		Role:     converter.ProtoToOrgRole(in.Role),
+		VaultKey: in.VaultKey,
+	})
+	if err != nil {
+		return nil, organizationError(err)
+	}
+	return &proto.AddOrgMemberResponse{Member: converter.OrgMemberToProto(member)}, nil
+}
+
+// UpdateMemberRole изменяет роль участника организации.
+// Возвращает InvalidArgument без роли, NotFound, если организация или участник не найдены,
+// PermissionDenied, если роли пользователя недостаточно, и FailedPrecondition при понижении
+// последнего владельца.
+func (s *OrganizationHandler) UpdateMemberRole(ctx context.Context, in *proto.UpdateOrgMemberRoleRequest) (*emptypb.Empty, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err = s.orgService.UpdateMemberRole(ctx, userID, in.OrgId, in.UserId, converter.ProtoToOrgRole(in.Role)); err != nil {
+		return nil, organizationError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// RemoveMember исключает участника из организации; участник может исключить и самого себя.
+// Ключ хранилища не меняется: исключённый участник, сохранивший ключ, по-прежнему расшифрует копии секретов,
+// полученные до исключения, но сервер больше не выдаёт ему ни ключ, ни секреты организации.
+// Возвращает NotFound, если организация или участник не найдены, PermissionDenied, если роли
+// пользователя недостаточно, и FailedPrecondition при исключении последнего владельца.
+func (s *OrganizationHandler) RemoveMember(ctx context.Context, in *proto.RemoveOrgMemberRequest) (*emptypb.Empty, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err = s.orgService.RemoveMember(ctx, userID, in.OrgId, in.UserId); err != nil {
+		return nil, organizationError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// DeleteOrganization удаляет организацию вместе с секретами её хранилища.
+// Возвращает NotFound, если пользователь не состоит в организации, и PermissionDenied,
+// если он не является её владельцем.
+func (s *OrganizationHandler) DeleteOrganization(ctx context.Context, in *proto.DeleteOrganizationRequest) (*emptypb.Empty, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err = s.orgService.DeleteOrganization(ctx, userID, in.OrgId); err != nil {
+		return nil, organizationError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// organizationError преобразует ошибку сервиса организаций в статус gRPC.
+func organizationError(err error) error {
+	switch {
+	case errors.Is(err, gophKeeperErrors.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, gophKeeperErrors.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, gophKeeperErrors.ErrLastOwner):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, service.ErrInvalidOrganizationRequest):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/service"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/pkg/proto"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"context"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"testing"
+)
+
+func TestOrganizationHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		call         func(handler *OrganizationHandler, ctx context.Context) error
+		setupMock    func(mockService *mocks.MockIOrganizationService)
+		expectedCode codes.Code
+	}{
+		{
+			name: "CreateOrganization_Success",
+			ctx:  userContext(),
+			call: func(handler *OrganizationHandler, ctx context.Context) error {
+				resp, err := handler.CreateOrganization(ctx, &proto.CreateOrganizationRequest{Name: "Team", VaultKey: []byte("wrapped")})
+				if err == nil && (resp.Organization.Id != 7 || resp.Organization.Role != proto.OrgRole_ORG_ROLE_OWNER) {
+					return fmt.Errorf("expected owned organization 7, got %+v", resp.Organization)
+				}
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIOrganizationService) {
+				mockService.EXPECT().CreateOrganization(gomock.Any(), uint64(123), &models.Organization{Name: "Team", VaultKey: []byte("wrapped")}).
+					Return(&models.Organization{ID: 7, Name: "Team", VaultID: 100, Role: models.OrgOwner}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "CreateOrganization_Fail_Invalid",
+			ctx:  userContext(),
+			call: func(handler *OrganizationHandler, ctx context.Context) error {
+				_, err := handler.CreateOrganization(ctx, &proto.CreateOrganizationRequest{Name: "Team"})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIOrganizationService) {
+				mockService.EXPECT().CreateOrganization(gomock.Any(), uint64(123), gomock.Any()).Return(nil, service.ErrInvalidOrganizationRequest)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "ListOrganizations_Fail_NoUserID",
+			ctx:  context.Background(),
+			call: func(handler *OrganizationHandler, ctx context.Context) error {
+				_, err := handler.ListOrganizations(ctx, &emptypb.Empty{})
+				return err
+			},
+			setupMock:    func(_ *mocks.MockIOrganizationService) {},
+			expectedCode: codes.Internal,
+		},
+		{
+			name: "ListMembers_Fail_NotMember",
+			ctx:  userContext(),
+			call: func(handler *OrganizationHandler, ctx context.Context) error {
+				_, err := handler.ListMembers(ctx, &proto.ListOrgMembersRequest{OrgId: 7})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIOrganizationService) {
+				mockService.EXPECT().ListMembers(gomock.Any(), uint64(123), uint64(7)).Return(nil, gophKeeperErrors.ErrNotFound)
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name: "AddMember_Success",
+			ctx:  userContext(),
+			call: func(handler *OrganizationHandler, ctx context.Context) error {
+				_, err := handler.AddMember(ctx, &proto.AddOrgMemberRequest{OrgId: 7, Login: "bob", Role: proto.OrgRole_ORG_ROLE_EDITOR, VaultKey: []byte("wrapped")})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIOrganizationService) {
+				member := &models.OrgMember{OrgID: 7, Login: "bob", Role: models.OrgEditor, VaultKey: []byte("wrapped")}
+				mockService.EXPECT().AddMember(gomock.Any(), uint64(123), member).Return(&models.OrgMember{OrgID: 7, UserID: 2, Login: "bob", Role: models.OrgEditor}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "AddMember_Fail_PermissionDenied",
+			ctx:  userContext(),
+			call: func(handler *OrganizationHandler, ctx context.Context) error {
+				_, err := handler.AddMember(ctx, &proto.AddOrgMemberRequest{OrgId: 7, Login: "bob", Role: proto.OrgRole_ORG_ROLE_ADMIN, VaultKey: []byte("wrapped")})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIOrganizationService) {
+				mockService.EXPECT().AddMember(gomock.Any(), uint64(123), gomock.Any()).Return(nil, gophKeeperErrors.ErrPermissionDenied)
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name: "UpdateMemberRole_Fail_LastOwner",
+			ctx:  userContext(),
+			call: func(handler *OrganizationHandler, ctx context.Context) error {
+				_, err := handler.UpdateMemberRole(ctx, &proto.UpdateOrgMemberRoleRequest{OrgId: 7, UserId: 123, Role: proto.OrgRole_ORG_ROLE_VIEWER})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIOrganizationService) {
+				mockService.EXPECT().UpdateMemberRole(gomock.Any(), uint64(123), uint64(7), uint64(123), models.OrgViewer).Return(gophKeeperErrors.ErrLastOwner)
+			},
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name: "RemoveMember_Success",
+			ctx:  userContext(),
+			call: func(handler *OrganizationHandler, ctx context.Context) error {
+				_, err := handler.RemoveMember(ctx, &proto.RemoveOrgMemberRequest{OrgId: 7, UserId: 2})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIOrganizationService) {
+				mockService.EXPECT().RemoveMember(gomock.Any(), uint64(123), uint64(7), uint64(2)).Return(nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "DeleteOrganization_Fail_PermissionDenied",
+			ctx:  userContext(),
+			call: func(handler *OrganizationHandler, ctx context.Context) error {
+				_, err := handler.DeleteOrganization(ctx, &proto.DeleteOrganizationRequest{OrgId: 7})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIOrganizationService) {
+				mockService.EXPECT().DeleteOrganization(gomock.Any(), uint64(123), uint64(7)).Return(gophKeeperErrors.ErrPermissionDenied)
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockIOrganizationService(ctrl)
+			tt.setupMock(mockService)
+
+			err := tt.call(NewOrganizationHandler(zap.NewNop(), mockService), tt.ctx)
+			assert.Equal(t, tt.expectedCode, status.Code(err), err)
+		})
+	}
+}
@@ -2,82 +2,111 @@
 package handlers
 
 import (
+	"beliaev-aa/GophKeeper/internal/server/events"
 	"beliaev-aa/GophKeeper/internal/server/service"
 	"beliaev-aa/GophKeeper/pkg/consts"
 	"beliaev-aa/GophKeeper/pkg/converter"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
 	"beliaev-aa/GophKeeper/pkg/proto"
 	"context"
 	"errors"
 	"fmt"
 	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"net"
 	"strconv"
 )
 
 // SecretHandler реализует серверные функции для управления секретами пользователей.
 type SecretHandler struct {
 	proto.UnimplementedSecretsServer
-	logger              *zap.Logger
-	secretService       service.ISecretService
-	notificationHandler *NotificationHandler
+	hub           *events.Hub
+	logger        *zap.Logger
+	secretService service.ISecretService
 }
 
 // NewSecretHandler создаёт новый экземпляр сервера для управления секретами.
+// Изменения секретов публикуются в переданный хаб событий.
 // Возвращает инициализированный экземпляр SecretHandler.
-func NewSecretHandler(logger *zap.Logger, secretService service.ISecretService) *SecretHandler {
+func NewSecretHandler(logger *zap.Logger, secretService service.ISecretService, hub *events.Hub) *SecretHandler {
 	return &SecretHandler{
-		logger:              logger,
-		notificationHandler: NewNotificationHandler(logger),
-		secretService:       secretService,
+		hub:           hub,
+		logger:        logger,
+		secretService: secretService,
 	}
 }
 
-// SaveUserSecret сохраняет или обновляет секрет пользователя.
-// Возвращает пустой ответ или ошибку, если операция не удалась.
+// SaveUserSecret сохраняет или обновляет секрет пользователя. Секрет без идентификатора или с идентификатором,
+// зарезервированным ReserveSecretID, и нулевой ревизией создаётся; ревизия существующего секрета не бывает нулевой.
+// Возвращает пустой ответ, ошибку NotFound, если обновляемый секрет принадлежит другому пользователю
+// или идентификатор создаваемого секрета не зарезервирован пользователем,
+// InvalidArgument, если секрет ссылается на чужой или не загруженный полностью файл,
+// или ошибку Aborted с текущей ревизией, если секрет был изменён после загрузки клиентом.
 func (s *SecretHandler) SaveUserSecret(ctx context.Context, in *proto.SaveUserSecretRequest) (*emptypb.Empty, error) {
-	userID, err := extractUserID(ctx)
+	userID, err := resolveTenant(ctx, s.secretService, models.OrgEditor)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
 	secret := converter.ProtoToSecret(in.Secret)
 	secret.UserID = int(userID)
-	if secret.ID > 0 {
+	kind := events.SecretCreated
+	if secret.ID > 0 && secret.Revision > 0 {
+		kind = events.SecretUpdated
 		_, err = s.secretService.UpdateSecret(ctx, secret)
 	} else {
 		_, err = s.secretService.CreateSecret(ctx, secret)
 	}
+	if errors.Is(err, gophKeeperErrors.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if errors.Is(err, gophKeeperErrors.ErrInvalidBlob) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	var conflict *gophKeeperErrors.RevisionConflictError
+	if errors.As(err, &conflict) {
+		return nil, conflictStatus(conflict)
+	}
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
-	var clientID uint64
-	clientID, err = extractClientID(ctx)
+
+	s.publish(ctx, userID, secret.ID, kind)
+
+	return &emptypb.Empty{}, nil
+}
+
+// ReserveSecretID резервирует за пользователем идентификатор нового секрета, чтобы клиент мог привязать
+// к нему шифротекст до создания секрета.
+func (s *SecretHandler) ReserveSecretID(ctx context.Context, _ *emptypb.Empty) (*proto.ReserveSecretIDResponse, error) {
+	userID, err := resolveTenant(ctx, s.secretService, models.OrgEditor)
 	if err != nil {
-		s.logger.Error("failed to extract client ID", zap.Error(err))
-		return &emptypb.Empty{}, err
+		return nil, err
 	}
 
-	isUpdated := secret.ID > 0
-	err = s.notificationHandler.notifyClients(userID, clientID, secret.ID, isUpdated)
+	id, err := s.secretService.ReserveSecretID(ctx, userID)
 	if err != nil {
-		s.logger.Error("failed to notify clients", zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	return &emptypb.Empty{}, nil
+	return &proto.ReserveSecretIDResponse{Id: id}, nil
 }
 
 // GetUserSecret извлекает конкретный секрет пользователя.
 // Возвращает секрет или ошибку, если секрет не найден или запрос не может быть выполнен.
 func (s *SecretHandler) GetUserSecret(ctx context.Context, in *proto.GetUserSecretRequest) (*proto.GetUserSecretResponse, error) {
-	userID, err := extractUserID(ctx)
+	userID, err := resolveTenant(ctx, s.secretService, models.OrgViewer)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
 	secret, err := s.secretService.GetSecret(ctx, in.Id, userID)
 	if err != nil {
-		if errors.Is(err, fmt.Errorf("secret not found (id=%d)", in.Id)) {
+		if errors.Is(err, gophKeeperErrors.ErrNotFound) {
 			return nil, status.Error(codes.NotFound, err.Error())
 		}
 		return nil, status.Error(codes.Internal, err.Error())
@@ -85,37 +114,299 @@ func (s *SecretHandler) GetUserSecret(ctx context.Context, in *proto.GetUserSecr
 	return &proto.GetUserSecretResponse{Secret: converter.SecretToProto(secret)}, nil
 }
 
-// GetUserSecrets извлекает все секреты пользователя.
+// GetUserSecrets извлекает секреты пользователя. Если в запросе указаны папка или метка,
+// возвращаются только секреты из этой папки и её подпапок или с этой меткой.
 // Возвращает список секретов или ошибку при их отсутствии или других проблемах с запросом.
-func (s *SecretHandler) GetUserSecrets(ctx context.Context, _ *emptypb.Empty) (*proto.GetUserSecretsResponse, error) {
-	userID, err := extractUserID(ctx)
+func (s *SecretHandler) GetUserSecrets(ctx context.Context, in *proto.GetUserSecretsRequest) (*proto.GetUserSecretsResponse, error) {
+	userID, err := resolveTenant(ctx, s.secretService, models.OrgViewer)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
-	secrets, err := s.secretService.GetUserSecrets(ctx, userID)
+	filter := models.SecretFilter{FolderID: in.FolderId, TagID: in.TagId}
+	secrets, err := s.secretService.GetUserSecrets(ctx, userID, filter)
 	if err != nil && !errors.Is(err, errors.New("no secrets found")) {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 	return &proto.GetUserSecretsResponse{Secrets: converter.SecretsToProto(secrets)}, nil
 }
 
-// DeleteUserSecret удаляет секрет пользователя.
+// SyncSecrets возвращает изменения секретов пользователя после ревизии хранилища, известной клиенту:
+// изменённые секреты, идентификаторы удалённых секретов и новую ревизию.
+func (s *SecretHandler) SyncSecrets(ctx context.Context, in *proto.SyncSecretsRequest) (*proto.SyncSecretsResponse, error) {
+	userID, err := resolveTenant(ctx, s.secretService, models.OrgViewer)
+	if err != nil {
+		return nil, err
+	}
+	delta, err := s.secretService.SyncSecrets(ctx, userID, in.SinceRevision)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &proto.SyncSecretsResponse{
+		Secrets:    converter.SecretsToProto(delta.Secrets),
+		DeletedIds: delta.DeletedIDs,
+		Revision:   delta.Revision,
+		Full:       delta.Full,
+	}, nil
+}
+
+// ListSecrets возвращает страницу заголовков секретов пользователя без зашифрованных данных
+// и токен следующей страницы. Данные секрета клиент запрашивает через GetUserSecret. Возвращает InvalidArgument
+// при запросе начала заголовка или порядка по заголовку: заголовки на сервере зашифрованы.
+func (s *SecretHandler) ListSecrets(ctx context.Context, in *proto.ListSecretsRequest) (*proto.ListSecretsResponse, error) {
+	userID, err := resolveTenant(ctx, s.secretService, models.OrgViewer)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := proto.SecretOrder_name[int32(in.Order)]; !ok {
+		return nil, status.Error(codes.InvalidArgument, "unknown secret order")
+	}
+	page, err := s.secretService.ListSecrets(ctx, userID, converter.ProtoToSecretListQuery(in))
+	if errors.Is(err, service.ErrInvalidPageToken) || errors.Is(err, service.ErrTitleQueryUnsupported) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &proto.ListSecretsResponse{
+		Secrets:       converter.SecretsToProto(page.Secrets),
+		NextPageToken: page.NextPageToken,
+	}, nil
+}
+
+// DeleteUserSecret перемещает секрет пользователя в корзину.
 // Возвращает пустой ответ или ошибку, если секрет не найден или не может быть удалён.
 func (s *SecretHandler) DeleteUserSecret(ctx context.Context, in *proto.DeleteUserSecretRequest) (*emptypb.Empty, error) {
-	userID, err := extractUserID(ctx)
+	userID, err := resolveTenant(ctx, s.secretService, models.OrgEditor)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
 	err = s.secretService.DeleteSecret(ctx, in.Id, userID)
-	if errors.Is(err, fmt.Errorf("secret not found (id=%d)", in.Id)) {
+	if errors.Is(err, gophKeeperErrors.ErrNotFound) {
 		return nil, status.Error(codes.NotFound, err.Error())
 	}
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
+
+	s.publish(ctx, userID, in.Id, events.SecretDeleted)
+
 	return &emptypb.Empty{}, nil
 }
 
+// ListSecretVersions возвращает сохранённые версии секрета пользователя от новых к старым.
+// Возвращает ошибку NotFound, если секрет не найден или принадлежит другому пользователю.
+func (s *SecretHandler) ListSecretVersions(ctx context.Context, in *proto.ListSecretVersionsRequest) (*proto.ListSecretVersionsResponse, error) {
+	userID, err := resolveTenant(ctx, s.secretService, models.OrgViewer)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := s.secretService.ListSecretVersions(ctx, in.SecretId, userID)
+	if errors.Is(err, gophKeeperErrors.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &proto.ListSecretVersionsResponse{Versions: converter.SecretVersionsToProto(versions)}, nil
+}
+
+// RestoreSecretVersion восстанавливает секрет пользователя из сохранённой версии.
+// Остальные клиенты пользователя получают уведомление об изменении секрета.
+// Возвращает ошибку NotFound, если секрет или версия не найдены.
+func (s *SecretHandler) RestoreSecretVersion(ctx context.Context, in *proto.RestoreSecretVersionRequest) (*emptypb.Empty, error) {
+	userID, err := resolveTenant(ctx, s.secretService, models.OrgEditor)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.secretService.RestoreSecretVersion(ctx, in.SecretId, in.VersionId, userID)
+	if errors.Is(err, gophKeeperErrors.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	s.publish(ctx, userID, in.SecretId, events.SecretUpdated)
+
+	return &emptypb.Empty{}, nil
+}
+
+// ListTrash возвращает секреты пользователя, находящиеся в корзине.
+func (s *SecretHandler) ListTrash(ctx context.Context, _ *emptypb.Empty) (*proto.ListTrashResponse, error) {
+	userID, err := resolveTenant(ctx, s.secretService, models.OrgViewer)
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := s.secretService.ListTrash(ctx, userID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &proto.ListTrashResponse{Secrets: converter.SecretsToProto(secrets)}, nil
+}
+
+// RestoreSecret возвращает секрет пользователя из корзины.
+// Остальные клиенты пользователя получают уведомление о появлении секрета.
+// Возвращает ошибку NotFound, если секрета нет в корзине.
+func (s *SecretHandler) RestoreSecret(ctx context.Context, in *proto.RestoreSecretRequest) (*emptypb.Empty, error) {
+	userID, err := resolveTenant(ctx, s.secretService, models.OrgEditor)
+	if err != nil {
+		return nil, err
+	}
+	err = s.secretService.RestoreSecret(ctx, in.Id, userID)
+	if errors.Is(err, gophKeeperErrors.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	s.publish(ctx, userID, in.Id, events.SecretCreated)
+
+	return &emptypb.Empty{}, nil
+}
+
+// PurgeSecret окончательно удаляет секрет пользователя из корзины.
+// Возвращает ошибку NotFound, если секрета нет в корзине.
+func (s *SecretHandler) PurgeSecret(ctx context.Context, in *proto.PurgeSecretRequest) (*emptypb.Empty, error) {
+	userID, err := resolveTenant(ctx, s.secretService, models.OrgAdmin)
+	if err != nil {
+		return nil, err
+	}
+	err = s.secretService.PurgeSecret(ctx, in.Id, userID)
+	if errors.Is(err, gophKeeperErrors.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// EncryptSecretLabels сохраняет заголовки и метаданные секретов, зашифрованные клиентом вместо открытых.
+// Возвращает ошибку NotFound, если секрет не найден у пользователя.
+func (s *SecretHandler) EncryptSecretLabels(ctx context.Context, in *proto.EncryptSecretLabelsRequest) (*emptypb.Empty, error) {
+	userID, err := resolveTenant(ctx, s.secretService, models.OrgEditor)
+	if err != nil {
+		return nil, err
+	}
+	err = s.secretService.EncryptSecretLabels(ctx, userID, converter.ProtoToSecretLabels(in.Secrets))
+	if errors.Is(err, gophKeeperErrors.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	for _, secret := range in.Secrets {
+		s.publish(ctx, userID, secret.Id, events.SecretUpdated)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// conflictStatus преобразует конфликт ревизий в статус Aborted с деталью ErrorInfo,
+// из которой клиент узнаёт текущую ревизию секрета на сервере.
+func conflictStatus(conflict *gophKeeperErrors.RevisionConflictError) error {
+	st, detailsErr := status.New(codes.Aborted, conflict.Error()).
+		WithDetails(&errdetails.ErrorInfo{
+			Reason:   consts.RevisionConflictReason,
+			Metadata: map[string]string{consts.CurrentRevisionKey: strconv.FormatUint(conflict.Current, 10)},
+		})
+	if detailsErr != nil {
+		return status.Error(codes.Aborted, conflict.Error())
+	}
+	return st.Err()
+}
+
+// vaultResolver проверяет доступ пользователя к хранилищу организации или к хранилищу владельца,
+// экстренный доступ к которому ему предоставлен, и возвращает идентификатор владельца хранилища.
+type vaultResolver interface {
+	ResolveVault(ctx context.Context, userID, orgID uint64, required models.OrgRole) (uint64, error)
+	ResolveEmergencyVault(ctx context.Context, userID, accessID uint64) (uint64, error)
+}
+
+// resolveTenant возвращает идентификатор владельца секретов, файлов, папок и меток запроса: пользователя,
+// служебной учётной записи хранилища организации, если она указана в метаданных запроса, или владельца
+// хранилища, экстренный доступ к которому предоставлен пользователю. Роль пользователя в организации должна
+// быть не ниже required: читатель читает секреты, редактор изменяет их, а администратор окончательно удаляет
+// из корзины. Экстренный доступ даёт права только читателя.
+// Возвращает статус InvalidArgument при неверном идентификаторе организации или доступа, NotFound, если
+// пользователь не состоит в организации или доступ ему не назначен, и PermissionDenied, если его роли
+// недостаточно или экстренный доступ ещё не предоставлен.
+func resolveTenant(ctx context.Context, vaults vaultResolver, required models.OrgRole) (uint64, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return 0, status.Error(codes.Internal, err.Error())
+	}
+	orgID, err := extractOrgID(ctx)
+	if err != nil {
+		return 0, status.Error(codes.InvalidArgument, err.Error())
+	}
+	accessID, err := extractEmergencyAccessID(ctx)
+	if err != nil {
+		return 0, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var vaultID uint64
+	switch {
+	case orgID != 0:
+		vaultID, err = vaults.ResolveVault(ctx, userID, orgID, required)
+	case accessID != 0:
+		if required != models.OrgViewer {
+			return 0, status.Error(codes.PermissionDenied, "emergency access is read-only")
+		}
+		vaultID, err = vaults.ResolveEmergencyVault(ctx, userID, accessID)
+	default:
+		return userID, nil
+	}
+	if errors.Is(err, gophKeeperErrors.ErrNotFound) {
+		return 0, status.Error(codes.NotFound, err.Error())
+	}
+	if errors.Is(err, gophKeeperErrors.ErrPermissionDenied) {
+		return 0, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return 0, status.Error(codes.Internal, err.Error())
+	}
+	return vaultID, nil
+}
+
+// publish отправляет событие об изменении секрета в хаб событий.
+// Клиент-инициатор определяется по метаданным запроса; если его не удалось определить,
+// событие получат все подписчики пользователя. Об изменении секрета в хранилище организации
+// уведомляются все её участники.
+func (s *SecretHandler) publish(ctx context.Context, userID, secretID uint64, kind events.Kind) {
+	clientID, err := extractClientID(ctx)
+	if err != nil {
+		s.logger.Warn("failed to extract client ID", zap.Error(err))
+	}
+
+	orgID, _ := extractOrgID(ctx)
+	if orgID == 0 {
+		s.hub.Publish(events.Event{
+			UserID:   userID,
+			ClientID: clientID,
+			SecretID: secretID,
+			Kind:     kind,
+		})
+		return
+	}
+
+	members, err := s.secretService.VaultMembers(ctx, orgID)
+	if err != nil {
+		s.logger.Warn("failed to list organization members", zap.Uint64("org_id", orgID), zap.Error(err))
+		return
+	}
+	initiatorID, _ := extractUserID(ctx)
+	for _, memberID := range members {
+		event := events.Event{UserID: memberID, SecretID: secretID, OrgID: orgID, Kind: kind}
+		if memberID == initiatorID {
+			event.ClientID = clientID
+		}
+		s.hub.Publish(event)
+	}
+}
+
 // extractUserID извлекает идентификатор пользователя из контекста запроса.
 // Возвращает идентификатор пользователя или ошибку, если он не может быть извлечен.
 func extractUserID(ctx context.Context) (uint64, error) {
@@ -127,6 +418,31 @@ func extractUserID(ctx context.Context) (uint64, error) {
 	return userID, nil
 }
 
+// extractSessionID извлекает идентификатор сессии токена доступа из контекста запроса.
+// Возвращает идентификатор сессии или ошибку, если он не может быть извлечен.
+func extractSessionID(ctx context.Context) (uint64, error) {
+	sid := ctx.Value(consts.CtxSessionIDKey)
+	sessionID, ok := sid.(uint64)
+	if !ok {
+		return 0, errors.New("failed to extract session id from context")
+	}
+	return sessionID, nil
+}
+
+// extractPeerAddress извлекает IP-адрес клиента из контекста запроса без номера порта.
+// Возвращает пустую строку, если адрес клиента неизвестен.
+func extractPeerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
 // extractClientID извлекает ID клиента из метаданных контекста запроса.
 // Возвращает ID клиента или ошибку, если метаданные отсутствуют или неверны.
 func extractClientID(ctx context.Context) (uint64, error) {
@@ -144,3 +460,60 @@ func extractClientID(ctx context.Context) (uint64, error) {
 	}
 	return uint64(id), nil
 }
+
+// extractOrgID извлекает идентификатор организации из метаданных контекста запроса.
+// Возвращает 0, если запрос относится к личному хранилищу пользователя, или ошибку,
+// если идентификатор организации неверен.
+func extractOrgID(ctx context.Context) (uint64, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0, nil
+	}
+	values := md.Get(consts.OrganizationIDHeader)
+	if len(values) == 0 || values[0] == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(values[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid organization id %q: %w", values[0], err)
+	}
+	return id, nil
+}
+
+// extractEmergencyAccessID извлекает идентификатор экстренного доступа из метаданных контекста запроса.
+// Возвращает 0, если запрос не относится к хранилищу, доступ к которому предоставлен пользователю,
+// или ошибку, если идентификатор доступа неверен.
+func extractEmergencyAccessID(ctx context.Context) (uint64, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0, nil
+	}
+	values := md.Get(consts.EmergencyAccessIDHeader)
+	if len(values) == 0 || values[0] == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(values[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid emergency access id %q: %w", values[0], err)
+	}
+	return id, nil
+}
+
+// extractShareID извлекает идентификатор доступа к переданному секрету из метаданных контекста запроса.
+// Возвращает 0, если запрос не относится к секрету, переданному пользователю, или ошибку,
+// если идентификатор доступа неверен.
+func extractShareID(ctx context.Context) (uint64, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0, nil
+	}
+	values := md.Get(consts.ShareIDHeader)
+	if len(values) == 0 || values[0] == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(values[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid share id %q: %w", values[0], err)
+	}
+	return id, nil
+}
@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"beliaev-aa/GophKeeper/internal/server/events"
+	"beliaev-aa/GophKeeper/internal/server/service"
 	"beliaev-aa/GophKeeper/pkg/consts"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
 	"beliaev-aa/GophKeeper/pkg/models"
 	"beliaev-aa/GophKeeper/pkg/proto"
 	"beliaev-aa/GophKeeper/tests/mocks"
@@ -11,7 +14,10 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"testing"
 )
@@ -22,7 +28,7 @@ func TestSecretHandler_SaveUserSecret(t *testing.T) {
 
 	mockService := mocks.NewMockISecretService(ctrl)
 	logger := zap.NewNop()
-	handler := NewSecretHandler(logger, mockService)
+	handler := NewSecretHandler(logger, mockService, events.NewHub(logger))
 
 	tests := []struct {
 		name      string
@@ -72,6 +78,50 @@ func TestSecretHandler_SaveUserSecret(t *testing.T) {
 			},
 			expectErr: "rpc error: code = Internal desc = create error",
 		},
+		{
+			name: "Error_UpdateOtherUserSecret",
+			setupMock: func() {
+				mockService.EXPECT().UpdateSecret(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, secret *models.Secret) (*models.Secret, error) {
+					if secret.UserID != 123 {
+						t.Errorf("Expected secret owner to be taken from context, got %d", secret.UserID)
+					}
+					return nil, fmt.Errorf("secret %w (id=%d)", gophKeeperErrors.ErrNotFound, secret.ID)
+				}).Times(1)
+			},
+			ctx: metadata.NewIncomingContext(
+				context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123)),
+				metadata.New(map[string]string{consts.ClientIDHeader: "456"}),
+			),
+			input: &proto.SaveUserSecretRequest{
+				Secret: &proto.Secret{Id: 7, Revision: 2},
+			},
+			expectErr: "rpc error: code = NotFound desc = secret not found (id=7)",
+		},
+		{
+			name: "Error_CreateNotReservedID",
+			setupMock: func() {
+				mockService.EXPECT().CreateSecret(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, secret *models.Secret) (*models.Secret, error) {
+					return nil, fmt.Errorf("failed to create secret: secret ID %d reservation: %w", secret.ID, gophKeeperErrors.ErrNotFound)
+				}).Times(1)
+			},
+			ctx: context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123)),
+			input: &proto.SaveUserSecretRequest{
+				Secret: &proto.Secret{Id: 7},
+			},
+			expectErr: "rpc error: code = NotFound desc = failed to create secret: secret ID 7 reservation: not found",
+		},
+		{
+			name: "Error_InvalidBlob",
+			setupMock: func() {
+				mockService.EXPECT().CreateSecret(gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w: unknown blob %q", gophKeeperErrors.ErrInvalidBlob, "abc")).Times(1)
+			},
+			ctx: context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123)),
+			input: &proto.SaveUserSecretRequest{
+				Secret: &proto.Secret{BlobId: "abc"},
+			},
+			expectErr: `rpc error: code = InvalidArgument desc = invalid blob: unknown blob "abc"`,
+		},
 	}
 
 	for _, tc := range tests {
@@ -88,13 +138,63 @@ func TestSecretHandler_SaveUserSecret(t *testing.T) {
 	}
 }
 
+func TestSecretHandler_SaveUserSecret_RevisionConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockISecretService(ctrl)
+	logger := zap.NewNop()
+	handler := NewSecretHandler(logger, mockService, events.NewHub(logger))
+
+	ctx := context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123))
+	mockService.EXPECT().UpdateSecret(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, secret *models.Secret) (*models.Secret, error) {
+		assert.Equal(t, uint64(3), secret.Revision)
+		return nil, fmt.Errorf("failed to store secret: %w", &gophKeeperErrors.RevisionConflictError{Current: 5})
+	}).Times(1)
+
+	_, err := handler.SaveUserSecret(ctx, &proto.SaveUserSecretRequest{Secret: &proto.Secret{Id: 7, Revision: 3}})
+
+	st, ok := status.FromError(err)
+	if assert.True(t, ok) && assert.Equal(t, codes.Aborted, st.Code()) && assert.Len(t, st.Details(), 1) {
+		info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+		if assert.True(t, ok) {
+			assert.Equal(t, consts.RevisionConflictReason, info.Reason)
+			assert.Equal(t, "5", info.Metadata[consts.CurrentRevisionKey])
+		}
+	}
+}
+
+func TestSecretHandler_ReserveSecretID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockISecretService(ctrl)
+	logger := zap.NewNop()
+	handler := NewSecretHandler(logger, mockService, events.NewHub(logger))
+
+	ctx := context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123))
+
+	mockService.EXPECT().ReserveSecretID(gomock.Any(), uint64(123)).Return(uint64(12), nil).Times(1)
+	response, err := handler.ReserveSecretID(ctx, &emptypb.Empty{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, uint64(12), response.Id)
+	}
+
+	mockService.EXPECT().ReserveSecretID(gomock.Any(), uint64(123)).Return(uint64(0), errors.New("database error")).Times(1)
+	_, err = handler.ReserveSecretID(ctx, &emptypb.Empty{})
+	assert.EqualError(t, err, "rpc error: code = Internal desc = database error")
+
+	_, err = handler.ReserveSecretID(context.Background(), &emptypb.Empty{})
+	assert.EqualError(t, err, "rpc error: code = Internal desc = failed to extract user id from context")
+}
+
 func TestSecretHandler_GetUserSecret(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockService := mocks.NewMockISecretService(ctrl)
 	logger := zap.NewNop()
-	handler := NewSecretHandler(logger, mockService)
+	handler := NewSecretHandler(logger, mockService, events.NewHub(logger))
 
 	tests := []struct {
 		name      string
@@ -117,13 +217,24 @@ func TestSecretHandler_GetUserSecret(t *testing.T) {
 		{
 			name: "Error_NotFound",
 			setupMock: func() {
-				mockService.EXPECT().GetSecret(gomock.Any(), uint64(1), uint64(123)).Return(nil, errors.New("secret not found")).Times(1)
+				mockService.EXPECT().GetSecret(gomock.Any(), uint64(1), uint64(123)).Return(nil, fmt.Errorf("secret %w (id=%d)", gophKeeperErrors.ErrNotFound, 1)).Times(1)
+			},
+			ctx: context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123)),
+			input: &proto.GetUserSecretRequest{
+				Id: 1,
+			},
+			expectErr: "rpc error: code = NotFound desc = secret not found (id=1)",
+		},
+		{
+			name: "Error_Internal",
+			setupMock: func() {
+				mockService.EXPECT().GetSecret(gomock.Any(), uint64(1), uint64(123)).Return(nil, errors.New("database error")).Times(1)
 			},
 			ctx: context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123)),
 			input: &proto.GetUserSecretRequest{
 				Id: 1,
 			},
-			expectErr: "rpc error: code = Internal desc = secret not found",
+			expectErr: "rpc error: code = Internal desc = database error",
 		},
 		{
 			name:      "Error_MissingUserID",
@@ -156,44 +267,57 @@ func TestSecretHandler_GetUserSecrets(t *testing.T) {
 
 	mockService := mocks.NewMockISecretService(ctrl)
 	logger := zap.NewNop()
-	handler := NewSecretHandler(logger, mockService)
+	handler := NewSecretHandler(logger, mockService, events.NewHub(logger))
 
 	tests := []struct {
 		name      string
 		setupMock func()
 		ctx       context.Context
-		input     *emptypb.Empty
+		input     *proto.GetUserSecretsRequest
 		expectErr string
 	}{
 		{
 			name: "Success",
 			setupMock: func() {
-				mockService.EXPECT().GetUserSecrets(gomock.Any(), uint64(123)).Return(models.Secrets{}, nil).Times(1)
+				mockService.EXPECT().GetUserSecrets(gomock.Any(), uint64(123), models.SecretFilter{}).Return(models.Secrets{}, nil).Times(1)
+			},
+			ctx: metadata.NewIncomingContext(
+				context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123)),
+				metadata.New(nil),
+			),
+			input:     &proto.GetUserSecretsRequest{},
+			expectErr: "",
+		},
+		{
+			name: "Success_Filtered",
+			setupMock: func() {
+				filter := models.SecretFilter{FolderID: 2, TagID: 3}
+				mockService.EXPECT().GetUserSecrets(gomock.Any(), uint64(123), filter).Return(models.Secrets{}, nil).Times(1)
 			},
 			ctx: metadata.NewIncomingContext(
 				context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123)),
 				metadata.New(nil),
 			),
-			input:     &emptypb.Empty{},
+			input:     &proto.GetUserSecretsRequest{FolderId: 2, TagId: 3},
 			expectErr: "",
 		},
 		{
 			name:      "Error_MissingUserID",
 			setupMock: func() {},
 			ctx:       context.Background(),
-			input:     &emptypb.Empty{},
+			input:     &proto.GetUserSecretsRequest{},
 			expectErr: "rpc error: code = Internal desc = failed to extract user id from context",
 		},
 		{
 			name: "Error_Internal",
 			setupMock: func() {
-				mockService.EXPECT().GetUserSecrets(gomock.Any(), uint64(123)).Return(nil, errors.New("internal error")).Times(1)
+				mockService.EXPECT().GetUserSecrets(gomock.Any(), uint64(123), models.SecretFilter{}).Return(nil, errors.New("internal error")).Times(1)
 			},
 			ctx: metadata.NewIncomingContext(
 				context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123)),
 				metadata.New(nil),
 			),
-			input:     &emptypb.Empty{},
+			input:     &proto.GetUserSecretsRequest{},
 			expectErr: "rpc error: code = Internal desc = internal error",
 		},
 	}
@@ -212,13 +336,114 @@ func TestSecretHandler_GetUserSecrets(t *testing.T) {
 	}
 }
 
+func TestSecretHandler_SyncSecrets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockISecretService(ctrl)
+	logger := zap.NewNop()
+	handler := NewSecretHandler(logger, mockService, events.NewHub(logger))
+
+	t.Run("Success", func(t *testing.T) {
+		mockService.EXPECT().SyncSecrets(gomock.Any(), uint64(123), uint64(4)).Return(&models.SecretsDelta{
+			Secrets:    models.Secrets{{ID: 1, Title: "changed", Revision: 2}},
+			DeletedIDs: []uint64{2, 3},
+			Revision:   9,
+		}, nil).Times(1)
+
+		ctx := context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123))
+		response, err := handler.SyncSecrets(ctx, &proto.SyncSecretsRequest{SinceRevision: 4})
+		if assert.NoError(t, err) {
+			assert.Len(t, response.Secrets, 1)
+			assert.Equal(t, "changed", response.Secrets[0].Title)
+			assert.Equal(t, []uint64{2, 3}, response.DeletedIds)
+			assert.Equal(t, uint64(9), response.Revision)
+			assert.False(t, response.Full)
+		}
+	})
+
+	t.Run("Error_MissingUserID", func(t *testing.T) {
+		_, err := handler.SyncSecrets(context.Background(), &proto.SyncSecretsRequest{})
+		assert.EqualError(t, err, "rpc error: code = Internal desc = failed to extract user id from context")
+	})
+
+	t.Run("Error_Internal", func(t *testing.T) {
+		mockService.EXPECT().SyncSecrets(gomock.Any(), uint64(123), uint64(0)).Return(nil, errors.New("internal error")).Times(1)
+
+		ctx := context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123))
+		_, err := handler.SyncSecrets(ctx, &proto.SyncSecretsRequest{})
+		assert.EqualError(t, err, "rpc error: code = Internal desc = internal error")
+	})
+}
+
+func TestSecretHandler_ListSecrets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockISecretService(ctrl)
+	logger := zap.NewNop()
+	handler := NewSecretHandler(logger, mockService, events.NewHub(logger))
+	ctx := context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123))
+
+	t.Run("Success", func(t *testing.T) {
+		query := &models.SecretListQuery{SecretType: string(models.CardSecret), Order: models.SecretOrderUpdatedAsc, PageSize: 10}
+		mockService.EXPECT().ListSecrets(gomock.Any(), uint64(123), query).Return(&models.SecretsPage{
+			Secrets:       models.Secrets{{ID: 1, Title: "Bank", SecretType: string(models.CardSecret)}},
+			NextPageToken: "next",
+		}, nil).Times(1)
+
+		response, err := handler.ListSecrets(ctx, &proto.ListSecretsRequest{
+			PageSize:   10,
+			SecretType: proto.SecretType_SECRET_TYPE_CARD,
+			Order:      proto.SecretOrder_SECRET_ORDER_UPDATED_ASC,
+		})
+		if assert.NoError(t, err) {
+			assert.Len(t, response.Secrets, 1)
+			assert.Equal(t, "Bank", response.Secrets[0].Title)
+			assert.Empty(t, response.Secrets[0].Payload)
+			assert.Equal(t, "next", response.NextPageToken)
+		}
+	})
+
+	t.Run("Error_MissingUserID", func(t *testing.T) {
+		_, err := handler.ListSecrets(context.Background(), &proto.ListSecretsRequest{})
+		assert.EqualError(t, err, "rpc error: code = Internal desc = failed to extract user id from context")
+	})
+
+	t.Run("Error_UnknownOrder", func(t *testing.T) {
+		_, err := handler.ListSecrets(ctx, &proto.ListSecretsRequest{Order: proto.SecretOrder(42)})
+		assert.EqualError(t, err, "rpc error: code = InvalidArgument desc = unknown secret order")
+	})
+
+	t.Run("Error_InvalidPageToken", func(t *testing.T) {
+		mockService.EXPECT().ListSecrets(gomock.Any(), uint64(123), gomock.Any()).Return(nil, service.ErrInvalidPageToken).Times(1)
+
+		_, err := handler.ListSecrets(ctx, &proto.ListSecretsRequest{PageToken: "bad"})
+		assert.EqualError(t, err, "rpc error: code = InvalidArgument desc = invalid page token")
+	})
+
+	t.Run("Error_TitleQuery", func(t *testing.T) {
+		mockService.EXPECT().ListSecrets(gomock.Any(), uint64(123), gomock.Any()).Return(nil, service.ErrTitleQueryUnsupported).Times(1)
+
+		_, err := handler.ListSecrets(ctx, &proto.ListSecretsRequest{Order: proto.SecretOrder_SECRET_ORDER_TITLE_ASC})
+		assert.EqualError(t, err, "rpc error: code = InvalidArgument desc = title prefix and title order are not supported for encrypted titles")
+	})
+
+	t.Run("Error_Internal", func(t *testing.T) {
+		mockService.EXPECT().ListSecrets(gomock.Any(), uint64(123), gomock.Any()).Return(nil, errors.New("internal error")).Times(1)
+
+		_, err := handler.ListSecrets(ctx, &proto.ListSecretsRequest{})
+		assert.EqualError(t, err, "rpc error: code = Internal desc = internal error")
+	})
+}
+
 func TestSecretHandler_DeleteUserSecret(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockService := mocks.NewMockISecretService(ctrl)
 	logger := zap.NewNop()
-	handler := NewSecretHandler(logger, mockService)
+	handler := NewSecretHandler(logger, mockService, events.NewHub(logger))
 
 	tests := []struct {
 		name      string
@@ -242,14 +467,14 @@ func TestSecretHandler_DeleteUserSecret(t *testing.T) {
 		{
 			name: "Error_NotFound",
 			setupMock: func() {
-				mockService.EXPECT().DeleteSecret(gomock.Any(), uint64(1), uint64(123)).Return(fmt.Errorf("secret not found (id=%d)", 1)).Times(1)
+				mockService.EXPECT().DeleteSecret(gomock.Any(), uint64(1), uint64(123)).Return(fmt.Errorf("secret %w (id=%d)", gophKeeperErrors.ErrNotFound, 1)).Times(1)
 			},
 			ctx: metadata.NewIncomingContext(
 				context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123)),
 				metadata.New(nil),
 			),
 			input:     &proto.DeleteUserSecretRequest{Id: 1},
-			expectErr: "rpc error: code = Internal desc = secret not found (id=1)",
+			expectErr: "rpc error: code = NotFound desc = secret not found (id=1)",
 		},
 		{
 			name:      "Error_MissingUserID",
@@ -285,3 +510,584 @@ func TestSecretHandler_DeleteUserSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestSecretHandler_ListSecretVersions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockISecretService(ctrl)
+	logger := zap.NewNop()
+	handler := NewSecretHandler(logger, mockService, events.NewHub(logger))
+
+	tests := []struct {
+		name          string
+		setupMock     func()
+		ctx           context.Context
+		expectedCount int
+		expectErr     string
+	}{
+		{
+			name: "Success",
+			setupMock: func() {
+				mockService.EXPECT().ListSecretVersions(gomock.Any(), uint64(1), uint64(123)).Return([]*models.SecretVersion{
+					{Secret: models.Secret{ID: 1, Title: "v2"}, VersionID: 5},
+					{Secret: models.Secret{ID: 1, Title: "v1"}, VersionID: 4},
+				}, nil).Times(1)
+			},
+			ctx:           context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123)),
+			expectedCount: 2,
+		},
+		{
+			name: "Error_NotFound",
+			setupMock: func() {
+				mockService.EXPECT().ListSecretVersions(gomock.Any(), uint64(1), uint64(123)).Return(nil, fmt.Errorf("secret %w (id=%d)", gophKeeperErrors.ErrNotFound, 1)).Times(1)
+			},
+			ctx:       context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123)),
+			expectErr: "rpc error: code = NotFound desc = secret not found (id=1)",
+		},
+		{
+			name: "Error_Internal",
+			setupMock: func() {
+				mockService.EXPECT().ListSecretVersions(gomock.Any(), uint64(1), uint64(123)).Return(nil, errors.New("internal error")).Times(1)
+			},
+			ctx:       context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123)),
+			expectErr: "rpc error: code = Internal desc = internal error",
+		},
+		{
+			name:      "Error_MissingUserID",
+			setupMock: func() {},
+			ctx:       context.Background(),
+			expectErr: "rpc error: code = Internal desc = failed to extract user id from context",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMock()
+
+			resp, err := handler.ListSecretVersions(tc.ctx, &proto.ListSecretVersionsRequest{SecretId: 1})
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, resp.Versions, tc.expectedCount)
+			}
+		})
+	}
+}
+
+func TestSecretHandler_RestoreSecretVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockISecretService(ctrl)
+	logger := zap.NewNop()
+	handler := NewSecretHandler(logger, mockService, events.NewHub(logger))
+
+	ctx := metadata.NewIncomingContext(
+		context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123)),
+		metadata.New(nil),
+	)
+
+	tests := []struct {
+		name      string
+		setupMock func()
+		ctx       context.Context
+		expectErr string
+	}{
+		{
+			name: "Success",
+			setupMock: func() {
+				mockService.EXPECT().RestoreSecretVersion(gomock.Any(), uint64(1), uint64(4), uint64(123)).Return(&models.Secret{ID: 1}, nil).Times(1)
+			},
+			ctx: ctx,
+		},
+		{
+			name: "Error_NotFound",
+			setupMock: func() {
+				mockService.EXPECT().RestoreSecretVersion(gomock.Any(), uint64(1), uint64(4), uint64(123)).Return(nil, fmt.Errorf("secret version %w (id=1, version=4)", gophKeeperErrors.ErrNotFound)).Times(1)
+			},
+			ctx:       ctx,
+			expectErr: "rpc error: code = NotFound desc = secret version not found (id=1, version=4)",
+		},
+		{
+			name: "Error_Internal",
+			setupMock: func() {
+				mockService.EXPECT().RestoreSecretVersion(gomock.Any(), uint64(1), uint64(4), uint64(123)).Return(nil, errors.New("internal error")).Times(1)
+			},
+			ctx:       ctx,
+			expectErr: "rpc error: code = Internal desc = internal error",
+		},
+		{
+			name:      "Error_MissingUserID",
+			setupMock: func() {},
+			ctx:       context.Background(),
+			expectErr: "rpc error: code = Internal desc = failed to extract user id from context",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMock()
+
+			_, err := handler.RestoreSecretVersion(tc.ctx, &proto.RestoreSecretVersionRequest{SecretId: 1, VersionId: 4})
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSecretHandler_ListTrash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockISecretService(ctrl)
+	logger := zap.NewNop()
+	handler := NewSecretHandler(logger, mockService, events.NewHub(logger))
+
+	ctx := context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123))
+
+	tests := []struct {
+		name          string
+		setupMock     func()
+		ctx           context.Context
+		expectedCount int
+		expectErr     string
+	}{
+		{
+			name: "Success",
+			setupMock: func() {
+				mockService.EXPECT().ListTrash(gomock.Any(), uint64(123)).Return(models.Secrets{{ID: 1}, {ID: 2}}, nil).Times(1)
+			},
+			ctx:           ctx,
+			expectedCount: 2,
+		},
+		{
+			name: "Error_Internal",
+			setupMock: func() {
+				mockService.EXPECT().ListTrash(gomock.Any(), uint64(123)).Return(nil, errors.New("internal error")).Times(1)
+			},
+			ctx:       ctx,
+			expectErr: "rpc error: code = Internal desc = internal error",
+		},
+		{
+			name:      "Error_MissingUserID",
+			setupMock: func() {},
+			ctx:       context.Background(),
+			expectErr: "rpc error: code = Internal desc = failed to extract user id from context",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMock()
+
+			resp, err := handler.ListTrash(tc.ctx, &emptypb.Empty{})
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, resp.Secrets, tc.expectedCount)
+			}
+		})
+	}
+}
+
+func TestSecretHandler_RestoreSecret(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockISecretService(ctrl)
+	logger := zap.NewNop()
+	handler := NewSecretHandler(logger, mockService, events.NewHub(logger))
+
+	ctx := metadata.NewIncomingContext(
+		context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123)),
+		metadata.New(nil),
+	)
+
+	tests := []struct {
+		name      string
+		setupMock func()
+		ctx       context.Context
+		expectErr string
+	}{
+		{
+			name: "Success",
+			setupMock: func() {
+				mockService.EXPECT().RestoreSecret(gomock.Any(), uint64(1), uint64(123)).Return(nil).Times(1)
+			},
+			ctx: ctx,
+		},
+		{
+			name: "Error_NotFound",
+			setupMock: func() {
+				mockService.EXPECT().RestoreSecret(gomock.Any(), uint64(1), uint64(123)).Return(fmt.Errorf("trashed secret %w (id=1)", gophKeeperErrors.ErrNotFound)).Times(1)
+			},
+			ctx:       ctx,
+			expectErr: "rpc error: code = NotFound desc = trashed secret not found (id=1)",
+		},
+		{
+			name: "Error_Internal",
+			setupMock: func() {
+				mockService.EXPECT().RestoreSecret(gomock.Any(), uint64(1), uint64(123)).Return(errors.New("internal error")).Times(1)
+			},
+			ctx:       ctx,
+			expectErr: "rpc error: code = Internal desc = internal error",
+		},
+		{
+			name:      "Error_MissingUserID",
+			setupMock: func() {},
+			ctx:       context.Background(),
+			expectErr: "rpc error: code = Internal desc = failed to extract user id from context",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMock()
+
+			_, err := handler.RestoreSecret(tc.ctx, &proto.RestoreSecretRequest{Id: 1})
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSecretHandler_PurgeSecret(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockISecretService(ctrl)
+	logger := zap.NewNop()
+	handler := NewSecretHandler(logger, mockService, events.NewHub(logger))
+
+	ctx := metadata.NewIncomingContext(
+		context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123)),
+		metadata.New(nil),
+	)
+
+	tests := []struct {
+		name      string
+		setupMock func()
+		ctx       context.Context
+		expectErr string
+	}{
+		{
+			name: "Success",
+			setupMock: func() {
+				mockService.EXPECT().PurgeSecret(gomock.Any(), uint64(1), uint64(123)).Return(nil).Times(1)
+			},
+			ctx: ctx,
+		},
+		{
+			name: "Error_NotFound",
+			setupMock: func() {
+				mockService.EXPECT().PurgeSecret(gomock.Any(), uint64(1), uint64(123)).Return(fmt.Errorf("trashed secret %w (id=1)", gophKeeperErrors.ErrNotFound)).Times(1)
+			},
+			ctx:       ctx,
+			expectErr: "rpc error: code = NotFound desc = trashed secret not found (id=1)",
+		},
+		{
+			name: "Error_Internal",
+			setupMock: func() {
+				mockService.EXPECT().PurgeSecret(gomock.Any(), uint64(1), uint64(123)).Return(errors.New("internal error")).Times(1)
+			},
+			ctx:       ctx,
+			expectErr: "rpc error: code = Internal desc = internal error",
+		},
+		{
+			name:      "Error_MissingUserID",
+			setupMock: func() {},
+			ctx:       context.Background(),
+			expectErr: "rpc error: code = Internal desc = failed to extract user id from context",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMock()
+
+			_, err := handler.PurgeSecret(tc.ctx, &proto.PurgeSecretRequest{Id: 1})
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSecretHandler_PublishesEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockISecretService(ctrl)
+	logger := zap.NewNop()
+	hub := events.NewHub(logger)
+	handler := NewSecretHandler(logger, mockService, hub)
+
+	ctx := metadata.NewIncomingContext(
+		context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123)),
+		metadata.New(map[string]string{consts.ClientIDHeader: "456"}),
+	)
+
+	tests := []struct {
+		name      string
+		setupMock func()
+		call      func() error
+		expected  events.Event
+	}{
+		{
+			name: "Create",
+			setupMock: func() {
+				mockService.EXPECT().CreateSecret(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, secret *models.Secret) (*models.Secret, error) {
+					secret.ID = 7
+					return secret, nil
+				}).Times(1)
+			},
+			call: func() error {
+				_, err := handler.SaveUserSecret(ctx, &proto.SaveUserSecretRequest{Secret: &proto.Secret{}})
+				return err
+			},
+			expected: events.Event{UserID: 123, ClientID: 456, SecretID: 7, Kind: events.SecretCreated},
+		},
+		{
+			name: "Update",
+			setupMock: func() {
+				mockService.EXPECT().UpdateSecret(gomock.Any(), gomock.Any()).Return(&models.Secret{ID: 7}, nil).Times(1)
+			},
+			call: func() error {
+				_, err := handler.SaveUserSecret(ctx, &proto.SaveUserSecretRequest{Secret: &proto.Secret{Id: 7, Revision: 2}})
+				return err
+			},
+			expected: events.Event{UserID: 123, ClientID: 456, SecretID: 7, Kind: events.SecretUpdated},
+		},
+		{
+			name: "Delete",
+			setupMock: func() {
+				mockService.EXPECT().DeleteSecret(gomock.Any(), uint64(7), uint64(123)).Return(nil).Times(1)
+			},
+			call: func() error {
+				_, err := handler.DeleteUserSecret(ctx, &proto.DeleteUserSecretRequest{Id: 7})
+				return err
+			},
+			expected: events.Event{UserID: 123, ClientID: 456, SecretID: 7, Kind: events.SecretDeleted},
+		},
+		{
+			name: "RestoreVersion",
+			setupMock: func() {
+				mockService.EXPECT().RestoreSecretVersion(gomock.Any(), uint64(7), uint64(3), uint64(123)).Return(&models.Secret{ID: 7}, nil).Times(1)
+			},
+			call: func() error {
+				_, err := handler.RestoreSecretVersion(ctx, &proto.RestoreSecretVersionRequest{SecretId: 7, VersionId: 3})
+				return err
+			},
+			expected: events.Event{UserID: 123, ClientID: 456, SecretID: 7, Kind: events.SecretUpdated},
+		},
+		{
+			name: "RestoreFromTrash",
+			setupMock: func() {
+				mockService.EXPECT().RestoreSecret(gomock.Any(), uint64(7), uint64(123)).Return(nil).Times(1)
+			},
+			call: func() error {
+				_, err := handler.RestoreSecret(ctx, &proto.RestoreSecretRequest{Id: 7})
+				return err
+			},
+			expected: events.Event{UserID: 123, ClientID: 456, SecretID: 7, Kind: events.SecretCreated},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sub := hub.Subscribe(123, 1, 1)
+			defer hub.Unsubscribe(sub)
+
+			tc.setupMock()
+
+			assert.NoError(t, tc.call())
+			assert.Equal(t, tc.expected, <-sub.Events)
+		})
+	}
+}
+
+func TestSecretHandler_Organization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockISecretService(ctrl)
+	logger := zap.NewNop()
+	hub := events.NewHub(logger)
+	handler := NewSecretHandler(logger, mockService, hub)
+
+	ctx := metadata.NewIncomingContext(
+		context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123)),
+		metadata.New(map[string]string{consts.ClientIDHeader: "456", consts.OrganizationIDHeader: "7"}),
+	)
+
+	t.Run("Success_SaveInVaultAndNotifyMembers", func(t *testing.T) {
+		own := hub.Subscribe(123, 1, 1)
+		defer hub.Unsubscribe(own)
+		member := hub.Subscribe(321, 2, 2)
+		defer hub.Unsubscribe(member)
+
+		mockService.EXPECT().ResolveVault(gomock.Any(), uint64(123), uint64(7), models.OrgEditor).Return(uint64(100), nil).Times(1)
+		mockService.EXPECT().CreateSecret(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, secret *models.Secret) (*models.Secret, error) {
+			assert.Equal(t, 100, secret.UserID)
+			secret.ID = 9
+			return secret, nil
+		}).Times(1)
+		mockService.EXPECT().VaultMembers(gomock.Any(), uint64(7)).Return([]uint64{123, 321}, nil).Times(1)
+
+		_, err := handler.SaveUserSecret(ctx, &proto.SaveUserSecretRequest{Secret: &proto.Secret{}})
+		assert.NoError(t, err)
+		assert.Equal(t, events.Event{UserID: 123, ClientID: 456, SecretID: 9, OrgID: 7, Kind: events.SecretCreated}, <-own.Events)
+		assert.Equal(t, events.Event{UserID: 321, SecretID: 9, OrgID: 7, Kind: events.SecretCreated}, <-member.Events)
+	})
+
+	t.Run("Error_ViewerDeletes", func(t *testing.T) {
+		mockService.EXPECT().ResolveVault(gomock.Any(), uint64(123), uint64(7), models.OrgEditor).
+			Return(uint64(0), fmt.Errorf("editor role required: %w", gophKeeperErrors.ErrPermissionDenied)).Times(1)
+
+		_, err := handler.DeleteUserSecret(ctx, &proto.DeleteUserSecretRequest{Id: 9})
+		assert.EqualError(t, err, "rpc error: code = PermissionDenied desc = editor role required: permission denied")
+	})
+
+	t.Run("Error_NotMember", func(t *testing.T) {
+		mockService.EXPECT().ResolveVault(gomock.Any(), uint64(123), uint64(7), models.OrgViewer).
+			Return(uint64(0), fmt.Errorf("organization %w (id=7)", gophKeeperErrors.ErrNotFound)).Times(1)
+
+		_, err := handler.ListTrash(ctx, &emptypb.Empty{})
+		assert.EqualError(t, err, "rpc error: code = NotFound desc = organization not found (id=7)")
+	})
+
+	t.Run("Error_InvalidOrganizationID", func(t *testing.T) {
+		invalid := metadata.NewIncomingContext(
+			context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123)),
+			metadata.New(map[string]string{consts.OrganizationIDHeader: "team"}),
+		)
+
+		_, err := handler.GetUserSecret(invalid, &proto.GetUserSecretRequest{Id: 9})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestSecretHandler_EmergencyAccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockISecretService(ctrl)
+	logger := zap.NewNop()
+	handler := NewSecretHandler(logger, mockService, events.NewHub(logger))
+
+	ctx := metadata.NewIncomingContext(
+		context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123)),
+		metadata.New(map[string]string{consts.EmergencyAccessIDHeader: "5"}),
+	)
+
+	t.Run("Success_ReadGrantorVault", func(t *testing.T) {
+		mockService.EXPECT().ResolveEmergencyVault(gomock.Any(), uint64(123), uint64(5)).Return(uint64(1), nil).Times(1)
+		mockService.EXPECT().GetSecret(gomock.Any(), uint64(9), uint64(1)).Return(&models.Secret{ID: 9, UserID: 1}, nil).Times(1)
+
+		resp, err := handler.GetUserSecret(ctx, &proto.GetUserSecretRequest{Id: 9})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(9), resp.Secret.Id)
+	})
+
+	t.Run("Error_ReadOnly", func(t *testing.T) {
+		_, err := handler.DeleteUserSecret(ctx, &proto.DeleteUserSecretRequest{Id: 9})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("Error_NotGranted", func(t *testing.T) {
+		mockService.EXPECT().ResolveEmergencyVault(gomock.Any(), uint64(123), uint64(5)).
+			Return(uint64(0), fmt.Errorf("emergency access 5 is not granted: %w", gophKeeperErrors.ErrPermissionDenied)).Times(1)
+
+		_, err := handler.ListTrash(ctx, &emptypb.Empty{})
+		assert.EqualError(t, err, "rpc error: code = PermissionDenied desc = emergency access 5 is not granted: permission denied")
+	})
+
+	t.Run("Error_InvalidAccessID", func(t *testing.T) {
+		invalid := metadata.NewIncomingContext(
+			context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123)),
+			metadata.New(map[string]string{consts.EmergencyAccessIDHeader: "bob"}),
+		)
+
+		_, err := handler.GetUserSecret(invalid, &proto.GetUserSecretRequest{Id: 9})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestSecretHandler_EncryptSecretLabels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockISecretService(ctrl)
+	logger := zap.NewNop()
+	handler := NewSecretHandler(logger, mockService, events.NewHub(logger))
+
+	ctx := metadata.NewIncomingContext(
+		context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(123)),
+		metadata.New(nil),
+	)
+	request := &proto.EncryptSecretLabelsRequest{
+		Secrets: []*proto.SecretLabels{{Id: 1, Title: "sealed", Metadata: "meta", SearchTokens: []string{"token"}}},
+	}
+	secrets := models.Secrets{{ID: 1, Title: "sealed", Metadata: "meta", LabelsEncrypted: true, SearchTokens: []string{"token"}}}
+
+	tests := []struct {
+		name      string
+		setupMock func()
+		ctx       context.Context
+		expectErr string
+	}{
+		{
+			name: "Success",
+			setupMock: func() {
+				mockService.EXPECT().EncryptSecretLabels(gomock.Any(), uint64(123), secrets).Return(nil).Times(1)
+			},
+			ctx: ctx,
+		},
+		{
+			name: "Error_NotFound",
+			setupMock: func() {
+				mockService.EXPECT().EncryptSecretLabels(gomock.Any(), uint64(123), secrets).Return(fmt.Errorf("secret with ID 1: %w", gophKeeperErrors.ErrNotFound)).Times(1)
+			},
+			ctx:       ctx,
+			expectErr: "rpc error: code = NotFound desc = secret with ID 1: not found",
+		},
+		{
+			name: "Error_Internal",
+			setupMock: func() {
+				mockService.EXPECT().EncryptSecretLabels(gomock.Any(), uint64(123), secrets).Return(errors.New("internal error")).Times(1)
+			},
+			ctx:       ctx,
+			expectErr: "rpc error: code = Internal desc = internal error",
+		},
+		{
+			name:      "Error_MissingUserID",
+			setupMock: func() {},
+			ctx:       context.Background(),
+			expectErr: "rpc error: code = Internal desc = failed to extract user id from context",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMock()
+
+			_, err := handler.EncryptSecretLabels(tc.ctx, request)
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Package handlers содержит обработчики gRPC-запросов передачи секретов другим пользователям.
+package handlers
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/events"
+	"beliaev-aa/GophKeeper/internal/server/service"
+	"beliaev-aa/GophKeeper/pkg/converter"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/pkg/proto"
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// ShareHandler реализует серверные функции для передачи секретов другим пользователям.
+type ShareHandler struct {
+	proto.UnimplementedSharesServer
+	shareService service.IShareService
+	hub          *events.Hub
+	logger       *zap.Logger
+}
+
+// NewShareHandler создаёт новый экземпляр сервера передачи секретов.
+// Возвращает инициализированный экземпляр ShareHandler.
+func NewShareHandler(logger *zap.Logger, shareService service.IShareService, hub *events.Hub) *ShareHandler {
+	return &ShareHandler{
+		shareService: shareService,
+		hub:          hub,
+		logger:       logger,
+	}
+}
+
+// GetPublicKey возвращает открытый ключ пользователя, которому передаётся секрет.
+// Возвращает NotFound, если пользователь не найден или ещё не создал пару ключей.
+func (s *ShareHandler) GetPublicKey(ctx context.Context, in *proto.GetPublicKeyRequest) (*proto.GetPublicKeyResponse, error) {
+	publicKey, err := s.shareService.GetPublicKey(ctx, in.Login)
+	if err != nil {
+		return nil, shareError(err)
+	}
+	return &proto.GetPublicKeyResponse{PublicKey: publicKey}, nil
+}
+
+// ShareSecret передаёт секрет пользователя другому пользователю и возвращает доступ.
+// Возвращает InvalidArgument без ключа данных или при передаче секрета самому себе и NotFound,
+// если получатель или секрет не найдены.
+func (s *ShareHandler) ShareSecret(ctx context.Context, in *proto.ShareSecretRequest) (*proto.ShareSecretResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	share, err := s.shareService.ShareSecret(ctx, &models.Share{
+		SecretID:       in.SecretId,
+		OwnerID:        userID,
+		RecipientLogin: in.RecipientLogin,
+		DataKey:        in.DataKey,
+		CanEdit:        in.CanEdit,
+	})
+	if err != nil {
+		return nil, shareError(err)
+	}
+	return &proto.ShareSecretResponse{Share: converter.ShareToProto(share)}, nil
+}
+
+// ListShares возвращает доступы, выданные пользователем к секрету; при нулевом идентификаторе секрета -
+// ко всем его секретам.
+func (s *ShareHandler) ListShares(ctx context.Context, in *proto.ListSharesRequest) (*proto.ListSharesResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	shares, err := s.shareService.ListShares(ctx, userID, in.SecretId)
+	if err != nil {
+		return nil, shareError(err)
+	}
+	return &proto.ListSharesResponse{Shares: converter.SharesToProto(shares)}, nil
+}
+
+// ListSharedWithMe возвращает секреты других пользователей, переданные пользователю, вместе с доступами к ним.
+func (s *ShareHandler) ListSharedWithMe(ctx context.Context, _ *emptypb.Empty) (*proto.ListSharedWithMeResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	secrets, err := s.shareService.ListSharedWithMe(ctx, userID)
+	if err != nil {
+		return nil, shareError(err)
+	}
+	return &proto.ListSharedWithMeResponse{Secrets: converter.SharedSecretsToProto(secrets)}, nil
+}
+
+// RevokeShare удаляет доступ к секрету: владелец отзывает доступ, получатель отказывается от него.
+// Возвращает NotFound, если доступ не найден.
+func (s *ShareHandler) RevokeShare(ctx context.Context, in *proto.RevokeShareRequest) (*emptypb.Empty, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err = s.shareService.RevokeShare(ctx, userID, in.Id); err != nil {
+		return nil, shareError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// UpdateSharedSecret сохраняет изменения переданного секрета и возвращает его новую ревизию.
+// Клиенты владельца получают уведомление об изменении секрета. Возвращает NotFound, если секрет
+// не передан пользователю, PermissionDenied без права изменения и Aborted при конфликте ревизий.
+func (s *ShareHandler) UpdateSharedSecret(ctx context.Context, in *proto.UpdateSharedSecretRequest) (*proto.UpdateSharedSecretResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if in.Secret == nil {
+		return nil, status.Error(codes.InvalidArgument, "secret is required")
+	}
+	secret, err := s.shareService.UpdateSharedSecret(ctx, userID, converter.ProtoToSecret(in.Secret))
+	if err != nil {
+		return nil, shareError(err)
+	}
+
+	s.hub.Publish(events.Event{
+		UserID:   uint64(secret.UserID),
+		SecretID: secret.ID,
+		Kind:     events.SecretUpdated,
+	})
+
+	return &proto.UpdateSharedSecretResponse{Revision: secret.Revision}, nil
+}
+
+// shareError преобразует ошибку сервиса передачи секретов в статус gRPC.
+func shareError(err error) error {
+	var conflict *gophKeeperErrors.RevisionConflictError
+	switch {
+	case errors.As(err, &conflict):
+		return conflictStatus(conflict)
+	case errors.Is(err, gophKeeperErrors.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, gophKeeperErrors.ErrShareReadOnly):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, gophKeeperErrors.ErrInvalidShare), errors.Is(err, service.ErrInvalidShareRequest):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/events"
+	"beliaev-aa/GophKeeper/internal/server/service"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/pkg/proto"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"context"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"testing"
+)
+
+func TestShareHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		call         func(handler *ShareHandler, ctx context.Context) error
+		setupMock    func(mockService *mocks.MockIShareService)
+		expectedCode codes.Code
+	}{
+		{
+			name: "GetPublicKey_Fail_NotFound",
+			ctx:  userContext(),
+			call: func(handler *ShareHandler, ctx context.Context) error {
+				_, err := handler.GetPublicKey(ctx, &proto.GetPublicKeyRequest{Login: "bob"})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIShareService) {
+				mockService.EXPECT().GetPublicKey(gomock.Any(), "bob").Return(nil, gophKeeperErrors.ErrNotFound)
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name: "ShareSecret_Success",
+			ctx:  userContext(),
+			call: func(handler *ShareHandler, ctx context.Context) error {
+				resp, err := handler.ShareSecret(ctx, &proto.ShareSecretRequest{SecretId: 10, RecipientLogin: "bob", DataKey: []byte("wrapped"), CanEdit: true})
+				if err == nil && resp.Share.Id != 5 {
+					return fmt.Errorf("expected share 5, got %d", resp.Share.Id)
+				}
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIShareService) {
+				share := &models.Share{SecretID: 10, OwnerID: 123, RecipientLogin: "bob", DataKey: []byte("wrapped"), CanEdit: true}
+				mockService.EXPECT().ShareSecret(gomock.Any(), share).Return(&models.Share{ID: 5, SecretID: 10}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "ShareSecret_Fail_Invalid",
+			ctx:  userContext(),
+			call: func(handler *ShareHandler, ctx context.Context) error {
+				_, err := handler.ShareSecret(ctx, &proto.ShareSecretRequest{SecretId: 10, RecipientLogin: "bob"})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIShareService) {
+				mockService.EXPECT().ShareSecret(gomock.Any(), gomock.Any()).Return(nil, service.ErrInvalidShareRequest)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "ShareSecret_Fail_NoUserID",
+			ctx:  context.Background(),
+			call: func(handler *ShareHandler, ctx context.Context) error {
+				_, err := handler.ShareSecret(ctx, &proto.ShareSecretRequest{SecretId: 10})
+				return err
+			},
+			setupMock:    func(_ *mocks.MockIShareService) {},
+			expectedCode: codes.Internal,
+		},
+		{
+			name: "ListShares_Success",
+			ctx:  userContext(),
+			call: func(handler *ShareHandler, ctx context.Context) error {
+				resp, err := handler.ListShares(ctx, &proto.ListSharesRequest{SecretId: 10})
+				if err == nil && len(resp.Shares) != 1 {
+					return fmt.Errorf("expected 1 share, got %d", len(resp.Shares))
+				}
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIShareService) {
+				mockService.EXPECT().ListShares(gomock.Any(), uint64(123), uint64(10)).Return([]*models.Share{{ID: 5, RecipientLogin: "bob"}}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "ListSharedWithMe_Success",
+			ctx:  userContext(),
+			call: func(handler *ShareHandler, ctx context.Context) error {
+				resp, err := handler.ListSharedWithMe(ctx, &emptypb.Empty{})
+				if err == nil && (len(resp.Secrets) != 1 || resp.Secrets[0].Share.OwnerLogin != "alice") {
+					return fmt.Errorf("expected secret shared by alice, got %v", resp.Secrets)
+				}
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIShareService) {
+				mockService.EXPECT().ListSharedWithMe(gomock.Any(), uint64(123)).
+					Return(models.Secrets{{ID: 10, Share: &models.Share{ID: 5, OwnerLogin: "alice"}}}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "RevokeShare_Fail_NotFound",
+			ctx:  userContext(),
+			call: func(handler *ShareHandler, ctx context.Context) error {
+				_, err := handler.RevokeShare(ctx, &proto.RevokeShareRequest{Id: 5})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIShareService) {
+				mockService.EXPECT().RevokeShare(gomock.Any(), uint64(123), uint64(5)).Return(gophKeeperErrors.ErrNotFound)
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name: "UpdateSharedSecret_Success",
+			ctx:  userContext(),
+			call: func(handler *ShareHandler, ctx context.Context) error {
+				resp, err := handler.UpdateSharedSecret(ctx, &proto.UpdateSharedSecretRequest{Secret: &proto.Secret{Id: 10, Revision: 3}})
+				if err == nil && resp.Revision != 4 {
+					return fmt.Errorf("expected revision 4, got %d", resp.Revision)
+				}
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIShareService) {
+				mockService.EXPECT().UpdateSharedSecret(gomock.Any(), uint64(123), gomock.Any()).
+					Return(&models.Secret{ID: 10, UserID: 1, Revision: 4}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "UpdateSharedSecret_Fail_ReadOnly",
+			ctx:  userContext(),
+			call: func(handler *ShareHandler, ctx context.Context) error {
+				_, err := handler.UpdateSharedSecret(ctx, &proto.UpdateSharedSecretRequest{Secret: &proto.Secret{Id: 10}})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIShareService) {
+				mockService.EXPECT().UpdateSharedSecret(gomock.Any(), uint64(123), gomock.Any()).Return(nil, gophKeeperErrors.ErrShareReadOnly)
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name: "UpdateSharedSecret_Fail_Conflict",
+			ctx:  userContext(),
+			call: func(handler *ShareHandler, ctx context.Context) error {
+				_, err := handler.UpdateSharedSecret(ctx, &proto.UpdateSharedSecretRequest{Secret: &proto.Secret{Id: 10}})
+				return err
+			},
+			setupMock: func(mockService *mocks.MockIShareService) {
+				mockService.EXPECT().UpdateSharedSecret(gomock.Any(), uint64(123), gomock.Any()).
+					Return(nil, &gophKeeperErrors.RevisionConflictError{Current: 5})
+			},
+			expectedCode: codes.Aborted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockIShareService(ctrl)
+			tt.setupMock(mockService)
+
+			err := tt.call(NewShareHandler(zap.NewNop(), mockService, events.NewHub(zap.NewNop())), tt.ctx)
+			assert.Equal(t, tt.expectedCode, status.Code(err), err)
+		})
+	}
+}
+
+func TestShareHandler_UpdateSharedSecretNotifiesOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hub := events.NewHub(zap.NewNop())
+	sub := hub.Subscribe(1, 7, 1)
+	defer hub.Unsubscribe(sub)
+
+	mockService := mocks.NewMockIShareService(ctrl)
+	mockService.EXPECT().UpdateSharedSecret(gomock.Any(), uint64(123), gomock.Any()).Return(&models.Secret{ID: 10, UserID: 1, Revision: 4}, nil)
+
+	handler := NewShareHandler(zap.NewNop(), mockService, hub)
+	if _, err := handler.UpdateSharedSecret(userContext(), &proto.UpdateSharedSecretRequest{Secret: &proto.Secret{Id: 10, Revision: 3}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	select {
+	case event := <-sub.Events:
+		if event.SecretID != 10 || event.Kind != events.SecretUpdated {
+			t.Errorf("Expected update of secret 10, got %+v", event)
+		}
+	default:
+		t.Error("Expected owner to be notified")
+	}
+}
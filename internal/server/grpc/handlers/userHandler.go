@@ -4,65 +4,560 @@ package handlers
 import (
 	"beliaev-aa/GophKeeper/internal/server/auth"
 	"beliaev-aa/GophKeeper/internal/server/config"
+	"beliaev-aa/GophKeeper/internal/server/events"
+	serverModels "beliaev-aa/GophKeeper/internal/server/models"
 	"beliaev-aa/GophKeeper/internal/server/service"
+	"beliaev-aa/GophKeeper/internal/server/storage/repository"
+	"beliaev-aa/GophKeeper/pkg/converter"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
 	"beliaev-aa/GophKeeper/pkg/proto"
 	"context"
 	"errors"
 	"fmt"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"time"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // UserHandler реализует интерфейс UnimplementedUsersServer для обработки запросов пользователей.
 type UserHandler struct {
 	proto.UnimplementedUsersServer
-	config      *config.Config
-	userService service.IUserService
+	config         *config.Config
+	userService    service.IUserService
+	sessionService service.ISessionService
+	totpService    service.ITOTPService
+	attemptService service.ILoginAttemptService
 }
 
 // NewUserHandler создает новый экземпляр UserHandler.
-// Принимает конфигурацию сервера и сервис пользователя, возвращая инициализированный сервер пользователей.
-func NewUserHandler(config *config.Config, userService service.IUserService) *UserHandler {
+// Принимает конфигурацию сервера, сервис пользователя, сервис сессий, сервис двухфакторной аутентификации
+// и сервис защиты входа от перебора, возвращая инициализированный сервер пользователей.
+func NewUserHandler(
+	config *config.Config,
+	userService service.IUserService,
+	sessionService service.ISessionService,
+	totpService service.ITOTPService,
+	attemptService service.ILoginAttemptService,
+) *UserHandler {
 	return &UserHandler{
-		config:      config,
-		userService: userService,
+		config:         config,
+		userService:    userService,
+		sessionService: sessionService,
+		totpService:    totpService,
+		attemptService: attemptService,
 	}
 }
 
-// Register регистрирует нового пользователя в системе и возвращает токен доступа.
+// Register регистрирует нового пользователя в системе и возвращает токены новой сессии.
 // Принимает контекст и запрос регистрации, возвращая ответ регистрации или ошибку.
 func (s *UserHandler) Register(ctx context.Context, in *proto.RegisterRequest) (*proto.RegisterResponse, error) {
-	user, err := s.userService.RegisterUser(ctx, in.Login, in.Password)
+	user, err := s.userService.RegisterUser(ctx, in.Login, in.AuthHash, converter.ProtoToKDFParams(in.Kdf))
+	if errors.Is(err, service.ErrInvalidAuthHash) || errors.Is(err, service.ErrReservedLogin) || errors.Is(err, models.ErrInvalidKDFParams) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	if errors.Is(err, fmt.Errorf("user already exists (%s)", in.Login)) {
 		return nil, status.Error(codes.AlreadyExists, err.Error())
 	}
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
-	token, err := s.authUser(user.ID)
+	tokens, err := s.authUser(ctx, user.ID, in.DeviceId, in.DeviceName)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to auth: %v", err)
 	}
-	return &proto.RegisterResponse{AccessToken: token}, nil
+	return &proto.RegisterResponse{AccessToken: tokens.AccessToken, RefreshToken: tokens.RefreshToken}, nil
 }
 
-// Login аутентифицирует пользователя и возвращает токен доступа.
+// Login аутентифицирует пользователя и возвращает токены новой сессии.
 // Принимает контекст и запрос на вход, возвращая ответ входа или ошибку.
+// Для учётной записи со старой схемой хранения без мастер-пароля возвращает FailedPrecondition.
+// Если у пользователя включён второй фактор, токены не выдаются: ответ содержит токен второго шага,
+// и вход завершается вызовом VerifyTOTP.
+// После серии неудачных попыток для логина или адреса клиента возвращает ResourceExhausted
+// с временем до окончания блокировки.
 func (s *UserHandler) Login(ctx context.Context, in *proto.LoginRequest) (*proto.LoginResponse, error) {
-	user, err := s.userService.LoginUser(ctx, in.Login, in.Password)
+	attemptID, err := s.attemptService.Reserve(ctx, in.Login, extractPeerAddress(ctx))
+	if err != nil {
+		return nil, lockoutStatus(err)
+	}
+	settled := false
+	defer s.releaseAttempt(ctx, attemptID, &settled)
+
+	user, err := s.userService.LoginUser(ctx, in.Login, in.AuthHash, in.LegacyPassword)
+	if errors.Is(err, service.ErrLegacyAuth) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if errors.Is(err, service.ErrInvalidAuthHash) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, service.ErrBadCredentials) {
+		settled = true
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	totpEnabled, err := s.totpService.IsEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if totpEnabled {
+		challenge, err := s.totpService.CreateChallenge(auth.TOTPChallenge{
+			UserID:     user.ID,
+			Login:      in.Login,
+			DeviceID:   in.DeviceId,
+			DeviceName: in.DeviceName,
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return &proto.LoginResponse{TotpRequired: true, TotpChallenge: challenge}, nil
+	}
+
+	settled = true
+	if err = s.attemptService.RecordSuccess(ctx, attemptID); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	tokens, err := s.authUser(ctx, user.ID, in.DeviceId, in.DeviceName)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to auth: %v", err)
+	}
+	return &proto.LoginResponse{AccessToken: tokens.AccessToken, RefreshToken: tokens.RefreshToken}, nil
+}
+
+// RefreshToken обменивает refresh-токен на новую пару токенов сессии.
+// Не требует токена доступа; возвращает Unauthenticated, если refresh-токен недействителен.
+func (s *UserHandler) RefreshToken(ctx context.Context, in *proto.RefreshTokenRequest) (*proto.RefreshTokenResponse, error) {
+	tokens, err := s.sessionService.RefreshSession(ctx, in.RefreshToken)
+	if errors.Is(err, service.ErrInvalidRefreshToken) {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &proto.RefreshTokenResponse{AccessToken: tokens.AccessToken, RefreshToken: tokens.RefreshToken}, nil
+}
+
+// PreLogin возвращает параметры KDF, необходимые клиенту для вывода ключей перед входом.
+// Не требует аутентификации и не раскрывает, зарегистрирован ли логин.
+func (s *UserHandler) PreLogin(ctx context.Context, in *proto.PreLoginRequest) (*proto.PreLoginResponse, error) {
+	kdf, upgradeRequired, err := s.userService.PreLogin(ctx, in.Login)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &proto.PreLoginResponse{
+		Kdf:             converter.KDFParamsToProto(kdf),
+		UpgradeRequired: upgradeRequired,
+	}, nil
+}
+
+// PreRegister возвращает сгенерированные параметры KDF для новой учётной записи.
+func (s *UserHandler) PreRegister(_ context.Context, _ *proto.PreRegisterRequest) (*proto.PreRegisterResponse, error) {
+	kdf, err := s.userService.NewKDFParams()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &proto.PreRegisterResponse{Kdf: converter.KDFParamsToProto(kdf)}, nil
+}
+
+// UpgradeKDF заменяет параметры KDF пользователя и сохраняет ключ хранилища и секреты, перешифрованные новым ключом.
+// Возвращает PermissionDenied, если хэш аутентификации текущего пароля неверен, и FailedPrecondition, если
+// перешифрование не требуется, перешифрованы не все требуемые секреты пользователя или не передан уже созданный
+// ключ хранилища. После перешифрования все сессии пользователя, кроме текущей, отзываются.
+func (s *UserHandler) UpgradeKDF(ctx context.Context, in *proto.UpgradeKDFRequest) (*proto.UpgradeKDFResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	sessionID, err := extractSessionID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	err = s.userService.UpgradeKDF(ctx, int(userID), in.CurrentAuthHash, in.AuthHash, converter.ProtoToKDFParams(in.Kdf), in.VaultKey, converter.ProtoToSecretPayloads(in.Secrets))
+	switch {
+	case errors.Is(err, service.ErrBadCredentials):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, service.ErrInvalidAuthHash), errors.Is(err, models.ErrInvalidKDFParams):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrKDFUpgradeNotRequired), errors.Is(err, repository.ErrIncompleteRekey), errors.Is(err, repository.ErrVaultKeyRequired):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, gophKeeperErrors.ErrNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err = s.sessionService.RevokeOtherSessions(ctx, int(userID), sessionID, events.PasswordChanged); err != nil {
+		return nil, status.Errorf(codes.Internal, "kdf upgraded, but other sessions were not revoked: %v", err)
+	}
+
+	return &proto.UpgradeKDFResponse{}, nil
+}
+
+// ChangePassword заменяет мастер-пароль пользователя и сохраняет ключ хранилища и секреты, перешифрованные
+// новым ключом. Возвращает PermissionDenied, если хэш аутентификации текущего пароля неверен, и FailedPrecondition,
+// если перешифрованы не все требуемые секреты или не передан уже созданный ключ хранилища. После смены пароля все сессии пользователя, кроме текущей,
+// отзываются, а их устройства получают уведомление о необходимости войти заново.
+func (s *UserHandler) ChangePassword(ctx context.Context, in *proto.ChangePasswordRequest) (*proto.ChangePasswordResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	sessionID, err := extractSessionID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	err = s.userService.ChangePassword(ctx, int(userID), in.CurrentAuthHash, in.AuthHash, converter.ProtoToKDFParams(in.Kdf), in.VaultKey, converter.ProtoToSecretPayloads(in.Secrets))
+	switch {
+	case errors.Is(err, service.ErrBadCredentials):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, service.ErrInvalidAuthHash), errors.Is(err, models.ErrInvalidKDFParams):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, repository.ErrIncompleteRekey), errors.Is(err, repository.ErrVaultKeyRequired):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, gophKeeperErrors.ErrNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err = s.sessionService.RevokeOtherSessions(ctx, int(userID), sessionID, events.PasswordChanged); err != nil {
+		return nil, status.Errorf(codes.Internal, "password changed, but other sessions were not revoked: %v", err)
+	}
+
+	return &proto.ChangePasswordResponse{}, nil
+}
+
+// DeleteAccount удаляет учётную запись пользователя вместе со всеми секретами и сессиями.
+// Требует повторного подтверждения мастер-паролем; возвращает PermissionDenied, если хэш
+// аутентификации неверен, и FailedPrecondition, если пользователь - единственный владелец
+// организации с другими участниками. Потоки уведомлений всех устройств пользователя закрываются.
+func (s *UserHandler) DeleteAccount(ctx context.Context, in *proto.DeleteAccountRequest) (*proto.DeleteAccountResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	err = s.userService.DeleteAccount(ctx, int(userID), in.AuthHash)
+	switch {
+	case errors.Is(err, service.ErrBadCredentials):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, gophKeeperErrors.ErrNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, gophKeeperErrors.ErrLastOwner):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	s.sessionService.CloseStreams(int(userID), events.AccountDeleted)
+
+	return &proto.DeleteAccountResponse{}, nil
+}
+
+// GetVaultKey возвращает ключ хранилища пользователя, зашифрованный ключом из мастер-пароля.
+// Если ключ хранилища ещё не создан, возвращается пустой ключ.
+func (s *UserHandler) GetVaultKey(ctx context.Context, _ *proto.GetVaultKeyRequest) (*proto.GetVaultKeyResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	vaultKey, err := s.userService.GetVaultKey(ctx, int(userID))
+	switch {
+	case errors.Is(err, gophKeeperErrors.ErrNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &proto.GetVaultKeyResponse{VaultKey: vaultKey}, nil
+}
+
+// CreateVaultKey сохраняет новый ключ хранилища пользователя вместе с секретами, перешифрованными
+// ключами данных. Возвращает AlreadyExists, если ключ хранилища уже создан, и FailedPrecondition,
+// если перешифрованы не все секреты без ключа данных.
+func (s *UserHandler) CreateVaultKey(ctx context.Context, in *proto.CreateVaultKeyRequest) (*proto.CreateVaultKeyResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	err = s.userService.CreateVaultKey(ctx, int(userID), in.VaultKey, converter.ProtoToSecretPayloads(in.Secrets))
+	switch {
+	case errors.Is(err, service.ErrInvalidVaultKey):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, repository.ErrVaultKeyExists):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, repository.ErrIncompleteRekey):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, gophKeeperErrors.ErrNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &proto.CreateVaultKeyResponse{}, nil
+}
+
+// GetKeyPair возвращает пару ключей пользователя: открытый ключ и закрытый ключ, зашифрованный
+// ключом хранилища. Если пара ещё не создана, возвращаются пустые ключи.
+func (s *UserHandler) GetKeyPair(ctx context.Context, _ *proto.GetKeyPairRequest) (*proto.GetKeyPairResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	keyPair, err := s.userService.GetKeyPair(ctx, int(userID))
+	switch {
+	case errors.Is(err, gophKeeperErrors.ErrNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &proto.GetKeyPairResponse{PublicKey: keyPair.PublicKey, PrivateKey: keyPair.PrivateKey}, nil
+}
+
+// CreateKeyPair сохраняет пару ключей пользователя. Возвращает InvalidArgument при неверных ключах
+// и AlreadyExists, если пара ключей уже создана.
+func (s *UserHandler) CreateKeyPair(ctx context.Context, in *proto.CreateKeyPairRequest) (*proto.CreateKeyPairResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	err = s.userService.CreateKeyPair(ctx, int(userID), &models.KeyPair{PublicKey: in.PublicKey, PrivateKey: in.PrivateKey})
+	switch {
+	case errors.Is(err, service.ErrInvalidKeyPair):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, repository.ErrKeyPairExists):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &proto.CreateKeyPairResponse{}, nil
+}
+
+// ListSessions возвращает активные сессии пользователя на всех его устройствах.
+// Сессия, с которой выполнен запрос, отмечается признаком current.
+func (s *UserHandler) ListSessions(ctx context.Context, _ *proto.ListSessionsRequest) (*proto.ListSessionsResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	currentID, err := extractSessionID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	sessions, err := s.sessionService.ListSessions(ctx, int(userID))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	response := &proto.ListSessionsResponse{Sessions: make([]*proto.DeviceSession, 0, len(sessions))}
+	for _, session := range sessions {
+		response.Sessions = append(response.Sessions, &proto.DeviceSession{
+			Id:         session.ID,
+			DeviceId:   session.DeviceID,
+			DeviceName: session.DeviceName,
+			CreatedAt:  timestamppb.New(session.CreatedAt),
+			LastUsedAt: timestamppb.New(session.LastUsedAt),
+			Current:    session.ID == currentID,
+		})
+	}
+
+	return response, nil
+}
+
+// RenameSession изменяет имя устройства одной из сессий пользователя.
+func (s *UserHandler) RenameSession(ctx context.Context, in *proto.RenameSessionRequest) (*proto.RenameSessionResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	err = s.sessionService.RenameSession(ctx, int(userID), in.Id, in.DeviceName)
+	switch {
+	case errors.Is(err, service.ErrInvalidDeviceName):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, gophKeeperErrors.ErrNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &proto.RenameSessionResponse{}, nil
+}
+
+// RevokeSession отзывает одну из сессий пользователя. Отозванное устройство теряет доступ
+// при следующем запросе, а его поток уведомлений закрывается.
+func (s *UserHandler) RevokeSession(ctx context.Context, in *proto.RevokeSessionRequest) (*proto.RevokeSessionResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	err = s.sessionService.RevokeSession(ctx, int(userID), in.Id)
+	if errors.Is(err, gophKeeperErrors.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &proto.RevokeSessionResponse{}, nil
+}
+
+// LogoutAll отзывает все сессии пользователя, включая текущую.
+func (s *UserHandler) LogoutAll(ctx context.Context, _ *proto.LogoutAllRequest) (*proto.LogoutAllResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err = s.sessionService.LogoutAll(ctx, int(userID)); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &proto.LogoutAllResponse{}, nil
+}
+
+// VerifyTOTP завершает вход пользователя со включённым вторым фактором.
+// Принимает токен второго шага из ответа Login и код из приложения-аутентификатора или код восстановления.
+// Не требует токена доступа; возвращает Unauthenticated при неверном коде или истёкшем токене второго шага.
+// Неверные коды учитываются как неудачные попытки входа под логином из токена второго шага.
+func (s *UserHandler) VerifyTOTP(ctx context.Context, in *proto.VerifyTOTPRequest) (*proto.VerifyTOTPResponse, error) {
+	challenge, err := s.totpService.ParseChallenge(in.Challenge)
 	if err != nil {
 		return nil, status.Error(codes.Unauthenticated, err.Error())
 	}
-	token, err := s.authUser(user.ID)
+
+	attemptID, err := s.attemptService.Reserve(ctx, challenge.Login, extractPeerAddress(ctx))
+	if err != nil {
+		return nil, lockoutStatus(err)
+	}
+	settled := false
+	defer s.releaseAttempt(ctx, attemptID, &settled)
+
+	err = s.totpService.VerifyCode(ctx, challenge.UserID, in.Code)
+	if errors.Is(err, service.ErrInvalidTOTPCode) {
+		settled = true
+	}
+	if errors.Is(err, service.ErrInvalidTOTPCode) || errors.Is(err, service.ErrInvalidChallenge) {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	settled = true
+	if err = s.attemptService.RecordSuccess(ctx, attemptID); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	tokens, err := s.authUser(ctx, challenge.UserID, challenge.DeviceID, challenge.DeviceName)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to auth: %v", err)
 	}
-	return &proto.LoginResponse{AccessToken: token}, nil
+	return &proto.VerifyTOTPResponse{AccessToken: tokens.AccessToken, RefreshToken: tokens.RefreshToken}, nil
+}
+
+// EnableTOTP начинает включение второго фактора и возвращает секрет для приложения-аутентификатора.
+// Второй фактор начинает действовать только после подтверждения кодом в ConfirmTOTP.
+func (s *UserHandler) EnableTOTP(ctx context.Context, _ *proto.EnableTOTPRequest) (*proto.EnableTOTPResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	setup, err := s.totpService.Enable(ctx, int(userID))
+	if errors.Is(err, service.ErrTOTPAlreadyEnabled) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &proto.EnableTOTPResponse{Secret: setup.Secret, Uri: setup.URI}, nil
+}
+
+// ConfirmTOTP включает второй фактор после проверки кода и возвращает одноразовые коды восстановления.
+func (s *UserHandler) ConfirmTOTP(ctx context.Context, in *proto.ConfirmTOTPRequest) (*proto.ConfirmTOTPResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	recoveryCodes, err := s.totpService.Confirm(ctx, int(userID), in.Code)
+	switch {
+	case errors.Is(err, service.ErrTOTPNotPending):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, service.ErrInvalidTOTPCode):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &proto.ConfirmTOTPResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+// DisableTOTP отключает второй фактор после проверки кода или кода восстановления.
+func (s *UserHandler) DisableTOTP(ctx context.Context, in *proto.DisableTOTPRequest) (*proto.DisableTOTPResponse, error) {
+	userID, err := extractUserID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	err = s.totpService.Disable(ctx, int(userID), in.Code)
+	switch {
+	case errors.Is(err, service.ErrTOTPNotEnabled):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, service.ErrInvalidTOTPCode):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &proto.DisableTOTPResponse{}, nil
+}
+
+// lockoutStatus преобразует ошибку проверки попыток входа в статус gRPC.
+// Для блокировки возвращает ResourceExhausted с деталью RetryInfo, по которой клиент узнаёт,
+// когда можно повторить вход.
+func lockoutStatus(err error) error {
+	var lockout *service.LockoutError
+	if !errors.As(err, &lockout) {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	st, detailsErr := status.New(codes.ResourceExhausted, lockout.Error()).
+		WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(lockout.RetryAfter)})
+	if detailsErr != nil {
+		return status.Error(codes.ResourceExhausted, lockout.Error())
+	}
+	return st.Err()
+}
+
+// releaseAttempt освобождает попытку входа attemptID, если она не была учтена как неудачная или успешная:
+// попытки, прерванные до сравнения учётных данных или внутренней ошибкой, не должны приближать блокировку.
+// Освобождение выполняется и после отмены запроса клиентом. Ошибка освобождения не возвращается клиенту:
+// неосвобождённая попытка лишь остаётся в журнале неудачной.
+func (s *UserHandler) releaseAttempt(ctx context.Context, attemptID uint64, settled *bool) {
+	if *settled {
+		return
+	}
+	_ = s.attemptService.Release(context.WithoutCancel(ctx), attemptID)
 }
 
-// authUser генерирует токен доступа для идентифицированного пользователя.
-// Возвращает строку с токеном или ошибку.
-func (s *UserHandler) authUser(userID int) (string, error) {
-	return auth.CreateToken(userID, time.Now().Add(time.Hour), []byte(s.config.SecretKey))
+// authUser открывает сессию для идентифицированного пользователя на устройстве клиента.
+// Возвращает токен доступа и refresh-токен сессии или ошибку.
+func (s *UserHandler) authUser(ctx context.Context, userID int, deviceID uint64, deviceName string) (*serverModels.Tokens, error) {
+	return s.sessionService.CreateSession(ctx, userID, deviceID, deviceName)
 }
@@ -1,15 +1,29 @@
 package handlers
 
 import (
+	"beliaev-aa/GophKeeper/internal/server/auth"
 	"beliaev-aa/GophKeeper/internal/server/config"
+	"beliaev-aa/GophKeeper/internal/server/events"
 	"beliaev-aa/GophKeeper/internal/server/models"
+	"beliaev-aa/GophKeeper/internal/server/service"
+	"beliaev-aa/GophKeeper/internal/server/storage/repository"
+	"beliaev-aa/GophKeeper/pkg/consts"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	pkgModels "beliaev-aa/GophKeeper/pkg/models"
 	"beliaev-aa/GophKeeper/pkg/proto"
 	"beliaev-aa/GophKeeper/tests/mocks"
 	"context"
 	"errors"
+	"fmt"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net"
 	"testing"
+	"time"
 )
 
 func TestUserHandler_Register(t *testing.T) {
@@ -17,8 +31,9 @@ func TestUserHandler_Register(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mocks.NewMockIUserService(ctrl)
+	mockSessions := mocks.NewMockISessionService(ctrl)
 	cfg := &config.Config{SecretKey: "test-secret-key"}
-	handler := NewUserHandler(cfg, mockService)
+	handler := NewUserHandler(cfg, mockService, mockSessions, mocks.NewMockITOTPService(ctrl), mocks.NewMockILoginAttemptService(ctrl))
 
 	tests := []struct {
 		name      string
@@ -29,27 +44,53 @@ func TestUserHandler_Register(t *testing.T) {
 		{
 			name: "Success",
 			setupMock: func() {
-				mockService.EXPECT().RegisterUser(gomock.Any(), "new_user", "password123").Return(&models.User{ID: 1}, nil).Times(1)
+				mockService.EXPECT().RegisterUser(gomock.Any(), "new_user", "password123", nil).Return(&models.User{ID: 1}, nil).Times(1)
+				mockSessions.EXPECT().CreateSession(gomock.Any(), 1, uint64(0), "").Return(&models.Tokens{AccessToken: "access", RefreshToken: "refresh"}, nil).Times(1)
 			},
-			input:     &proto.RegisterRequest{Login: "new_user", Password: "password123"},
+			input:     &proto.RegisterRequest{Login: "new_user", AuthHash: "password123"},
 			expectErr: "",
 		},
+		{
+			name: "Session_Error",
+			setupMock: func() {
+				mockService.EXPECT().RegisterUser(gomock.Any(), "new_user", "password123", nil).Return(&models.User{ID: 1}, nil).Times(1)
+				mockSessions.EXPECT().CreateSession(gomock.Any(), 1, uint64(0), "").Return(nil, errors.New("db error")).Times(1)
+			},
+			input:     &proto.RegisterRequest{Login: "new_user", AuthHash: "password123"},
+			expectErr: "rpc error: code = Internal desc = failed to auth: db error",
+		},
 		{
 			name: "User_Already_Exists",
 			setupMock: func() {
-				mockService.EXPECT().RegisterUser(gomock.Any(), "existing_user", "password123").Return(nil, errors.New("user already exists (existing_user)")).Times(1)
+				mockService.EXPECT().RegisterUser(gomock.Any(), "existing_user", "password123", nil).Return(nil, errors.New("user already exists (existing_user)")).Times(1)
 			},
-			input:     &proto.RegisterRequest{Login: "existing_user", Password: "password123"},
+			input:     &proto.RegisterRequest{Login: "existing_user", AuthHash: "password123"},
 			expectErr: "rpc error: code = Internal desc = user already exists (existing_user)",
 		},
 		{
 			name: "Internal_Error",
 			setupMock: func() {
-				mockService.EXPECT().RegisterUser(gomock.Any(), "new_user", "password123").Return(nil, errors.New("internal error")).Times(1)
+				mockService.EXPECT().RegisterUser(gomock.Any(), "new_user", "password123", nil).Return(nil, errors.New("internal error")).Times(1)
 			},
-			input:     &proto.RegisterRequest{Login: "new_user", Password: "password123"},
+			input:     &proto.RegisterRequest{Login: "new_user", AuthHash: "password123"},
 			expectErr: "rpc error: code = Internal desc = internal error",
 		},
+		{
+			name: "Invalid_Auth_Hash",
+			setupMock: func() {
+				mockService.EXPECT().RegisterUser(gomock.Any(), "new_user", "password123", nil).Return(nil, service.ErrInvalidAuthHash).Times(1)
+			},
+			input:     &proto.RegisterRequest{Login: "new_user", AuthHash: "password123"},
+			expectErr: "rpc error: code = InvalidArgument desc = invalid auth hash",
+		},
+		{
+			name: "Reserved_Login",
+			setupMock: func() {
+				mockService.EXPECT().RegisterUser(gomock.Any(), "org:vault", "password123", nil).Return(nil, service.ErrReservedLogin).Times(1)
+			},
+			input:     &proto.RegisterRequest{Login: "org:vault", AuthHash: "password123"},
+			expectErr: "rpc error: code = InvalidArgument desc = login is reserved",
+		},
 	}
 
 	for _, tc := range tests {
@@ -74,39 +115,126 @@ func TestUserHandler_Login(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mocks.NewMockIUserService(ctrl)
+	mockSessions := mocks.NewMockISessionService(ctrl)
+	mockTOTP := mocks.NewMockITOTPService(ctrl)
+	mockAttempts := mocks.NewMockILoginAttemptService(ctrl)
 	cfg := &config.Config{SecretKey: "test-secret-key"}
-	handler := NewUserHandler(cfg, mockService)
+	handler := NewUserHandler(cfg, mockService, mockSessions, mockTOTP, mockAttempts)
 
 	tests := []struct {
-		name      string
-		setupMock func()
-		input     *proto.LoginRequest
-		expectErr string
+		name       string
+		setupMock  func()
+		input      *proto.LoginRequest
+		expectTOTP bool
+		expectErr  string
 	}{
 		{
 			name: "Success",
 			setupMock: func() {
-				mockService.EXPECT().LoginUser(gomock.Any(), "valid_user", "password123").Return(&models.User{ID: 1}, nil).Times(1)
+				mockAttempts.EXPECT().Reserve(gomock.Any(), "valid_user", "").Return(uint64(1), nil).Times(1)
+				mockService.EXPECT().LoginUser(gomock.Any(), "valid_user", "password123", "").Return(&models.User{ID: 1}, nil).Times(1)
+				mockTOTP.EXPECT().IsEnabled(gomock.Any(), 1).Return(false, nil).Times(1)
+				mockAttempts.EXPECT().RecordSuccess(gomock.Any(), uint64(1)).Return(nil).Times(1)
+				mockSessions.EXPECT().CreateSession(gomock.Any(), 1, uint64(0), "").Return(&models.Tokens{AccessToken: "access", RefreshToken: "refresh"}, nil).Times(1)
 			},
-			input:     &proto.LoginRequest{Login: "valid_user", Password: "password123"},
+			input:     &proto.LoginRequest{Login: "valid_user", AuthHash: "password123"},
 			expectErr: "",
 		},
 		{
 			name: "Invalid_Credentials",
 			setupMock: func() {
-				mockService.EXPECT().LoginUser(gomock.Any(), "invalid_user", "password123").Return(nil, errors.New("invalid credentials")).Times(1)
+				mockAttempts.EXPECT().Reserve(gomock.Any(), "invalid_user", "").Return(uint64(1), nil).Times(1)
+				mockService.EXPECT().LoginUser(gomock.Any(), "invalid_user", "password123", "").Return(nil, errors.New("invalid credentials")).Times(1)
+				mockAttempts.EXPECT().Release(gomock.Any(), uint64(1)).Return(nil).Times(1)
 			},
-			input:     &proto.LoginRequest{Login: "invalid_user", Password: "password123"},
+			input:     &proto.LoginRequest{Login: "invalid_user", AuthHash: "password123"},
 			expectErr: "rpc error: code = Unauthenticated desc = invalid credentials",
 		},
 		{
 			name: "Internal_Error",
 			setupMock: func() {
-				mockService.EXPECT().LoginUser(gomock.Any(), "valid_user", "password123").Return(nil, errors.New("internal error")).Times(1)
+				mockAttempts.EXPECT().Reserve(gomock.Any(), "valid_user", "").Return(uint64(1), nil).Times(1)
+				mockService.EXPECT().LoginUser(gomock.Any(), "valid_user", "password123", "").Return(nil, errors.New("internal error")).Times(1)
+				mockAttempts.EXPECT().Release(gomock.Any(), uint64(1)).Return(nil).Times(1)
 			},
-			input:     &proto.LoginRequest{Login: "valid_user", Password: "password123"},
+			input:     &proto.LoginRequest{Login: "valid_user", AuthHash: "password123"},
 			expectErr: "rpc error: code = Unauthenticated desc = internal error",
 		},
+		{
+			name: "Legacy_Account",
+			setupMock: func() {
+				mockAttempts.EXPECT().Reserve(gomock.Any(), "legacy_user", "").Return(uint64(1), nil).Times(1)
+				mockService.EXPECT().LoginUser(gomock.Any(), "legacy_user", "password123", "").Return(nil, service.ErrLegacyAuth).Times(1)
+				mockAttempts.EXPECT().Release(gomock.Any(), uint64(1)).Return(nil).Times(1)
+			},
+			input:     &proto.LoginRequest{Login: "legacy_user", AuthHash: "password123"},
+			expectErr: "rpc error: code = FailedPrecondition desc = legacy account requires password migration",
+		},
+		{
+			name: "Device_Session",
+			setupMock: func() {
+				mockAttempts.EXPECT().Reserve(gomock.Any(), "valid_user", "").Return(uint64(1), nil).Times(1)
+				mockService.EXPECT().LoginUser(gomock.Any(), "valid_user", "password123", "").Return(&models.User{ID: 1}, nil).Times(1)
+				mockTOTP.EXPECT().IsEnabled(gomock.Any(), 1).Return(false, nil).Times(1)
+				mockAttempts.EXPECT().RecordSuccess(gomock.Any(), uint64(1)).Return(nil).Times(1)
+				mockSessions.EXPECT().CreateSession(gomock.Any(), 1, uint64(42), "laptop").Return(&models.Tokens{AccessToken: "access", RefreshToken: "refresh"}, nil).Times(1)
+			},
+			input:     &proto.LoginRequest{Login: "valid_user", AuthHash: "password123", DeviceId: 42, DeviceName: "laptop"},
+			expectErr: "",
+		},
+		{
+			name: "Legacy_Account_Migration",
+			setupMock: func() {
+				mockAttempts.EXPECT().Reserve(gomock.Any(), "legacy_user", "").Return(uint64(1), nil).Times(1)
+				mockService.EXPECT().LoginUser(gomock.Any(), "legacy_user", "password123", "master").Return(&models.User{ID: 1}, nil).Times(1)
+				mockTOTP.EXPECT().IsEnabled(gomock.Any(), 1).Return(false, nil).Times(1)
+				mockAttempts.EXPECT().RecordSuccess(gomock.Any(), uint64(1)).Return(nil).Times(1)
+				mockSessions.EXPECT().CreateSession(gomock.Any(), 1, uint64(0), "").Return(&models.Tokens{AccessToken: "access", RefreshToken: "refresh"}, nil).Times(1)
+			},
+			input:     &proto.LoginRequest{Login: "legacy_user", AuthHash: "password123", LegacyPassword: "master"},
+			expectErr: "",
+		},
+		{
+			name: "TOTP_Required",
+			setupMock: func() {
+				mockAttempts.EXPECT().Reserve(gomock.Any(), "valid_user", "").Return(uint64(1), nil).Times(1)
+				mockService.EXPECT().LoginUser(gomock.Any(), "valid_user", "password123", "").Return(&models.User{ID: 1}, nil).Times(1)
+				mockTOTP.EXPECT().IsEnabled(gomock.Any(), 1).Return(true, nil).Times(1)
+				mockTOTP.EXPECT().CreateChallenge(auth.TOTPChallenge{UserID: 1, Login: "valid_user", DeviceID: 42, DeviceName: "laptop"}).Return("challenge", nil).Times(1)
+				mockAttempts.EXPECT().Release(gomock.Any(), uint64(1)).Return(nil).Times(1)
+			},
+			input:      &proto.LoginRequest{Login: "valid_user", AuthHash: "password123", DeviceId: 42, DeviceName: "laptop"},
+			expectTOTP: true,
+			expectErr:  "",
+		},
+		{
+			name: "Bad_Credentials_Recorded",
+			setupMock: func() {
+				mockAttempts.EXPECT().Reserve(gomock.Any(), "valid_user", "").Return(uint64(1), nil).Times(1)
+				mockService.EXPECT().LoginUser(gomock.Any(), "valid_user", "password123", "").Return(nil, service.ErrBadCredentials).Times(1)
+			},
+			input:     &proto.LoginRequest{Login: "valid_user", AuthHash: "password123"},
+			expectErr: "rpc error: code = Unauthenticated desc = bad auth credentials",
+		},
+		{
+			name: "Locked_Out",
+			setupMock: func() {
+				mockAttempts.EXPECT().Reserve(gomock.Any(), "valid_user", "").Return(uint64(0), &service.LockoutError{RetryAfter: 8 * time.Second}).Times(1)
+			},
+			input:     &proto.LoginRequest{Login: "valid_user", AuthHash: "password123"},
+			expectErr: "rpc error: code = ResourceExhausted desc = too many failed login attempts, retry in 8s",
+		},
+		{
+			name: "TOTP_Check_Error",
+			setupMock: func() {
+				mockAttempts.EXPECT().Reserve(gomock.Any(), "valid_user", "").Return(uint64(1), nil).Times(1)
+				mockService.EXPECT().LoginUser(gomock.Any(), "valid_user", "password123", "").Return(&models.User{ID: 1}, nil).Times(1)
+				mockTOTP.EXPECT().IsEnabled(gomock.Any(), 1).Return(false, errors.New("db error")).Times(1)
+				mockAttempts.EXPECT().Release(gomock.Any(), uint64(1)).Return(nil).Times(1)
+			},
+			input:     &proto.LoginRequest{Login: "valid_user", AuthHash: "password123"},
+			expectErr: "rpc error: code = Internal desc = db error",
+		},
 	}
 
 	for _, tc := range tests {
@@ -115,7 +243,882 @@ func TestUserHandler_Login(t *testing.T) {
 				tc.setupMock()
 			}
 
-			_, err := handler.Login(context.Background(), tc.input)
+			res, err := handler.Login(context.Background(), tc.input)
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			if tc.expectTOTP {
+				assert.True(t, res.TotpRequired)
+				assert.Equal(t, "challenge", res.TotpChallenge)
+				assert.Empty(t, res.AccessToken)
+				assert.Empty(t, res.RefreshToken)
+			} else {
+				assert.False(t, res.TotpRequired)
+				assert.Equal(t, "access", res.AccessToken)
+			}
+		})
+	}
+}
+
+func TestUserHandler_PreLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIUserService(ctrl)
+	mockSessions := mocks.NewMockISessionService(ctrl)
+	cfg := &config.Config{SecretKey: "test-secret-key"}
+	handler := NewUserHandler(cfg, mockService, mockSessions, mocks.NewMockITOTPService(ctrl), mocks.NewMockILoginAttemptService(ctrl))
+
+	kdf := &pkgModels.KDFParams{Algorithm: pkgModels.KDFArgon2id, Salt: []byte("0123456789abcdef"), Argon2Memory: 65536, Argon2Time: 3, Argon2Threads: 4}
+
+	tests := []struct {
+		name          string
+		setupMock     func()
+		expectErr     string
+		expectKDF     bool
+		expectUpgrade bool
+	}{
+		{
+			name: "Success",
+			setupMock: func() {
+				mockService.EXPECT().PreLogin(gomock.Any(), "user").Return(kdf, false, nil).Times(1)
+			},
+			expectKDF: true,
+		},
+		{
+			name: "Legacy_Account",
+			setupMock: func() {
+				mockService.EXPECT().PreLogin(gomock.Any(), "user").Return(nil, true, nil).Times(1)
+			},
+			expectUpgrade: true,
+		},
+		{
+			name: "Internal_Error",
+			setupMock: func() {
+				mockService.EXPECT().PreLogin(gomock.Any(), "user").Return(nil, false, errors.New("internal error")).Times(1)
+			},
+			expectErr: "rpc error: code = Internal desc = internal error",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMock()
+
+			resp, err := handler.PreLogin(context.Background(), &proto.PreLoginRequest{Login: "user"})
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectKDF, resp.GetKdf() != nil)
+			assert.Equal(t, tc.expectUpgrade, resp.GetUpgradeRequired())
+		})
+	}
+}
+
+func TestUserHandler_PreRegister(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIUserService(ctrl)
+	handler := NewUserHandler(&config.Config{SecretKey: "test-secret-key"}, mockService, mocks.NewMockISessionService(ctrl), mocks.NewMockITOTPService(ctrl), mocks.NewMockILoginAttemptService(ctrl))
+
+	kdf := &pkgModels.KDFParams{Algorithm: pkgModels.KDFArgon2id, Salt: []byte("0123456789abcdef"), Argon2Memory: 65536, Argon2Time: 3, Argon2Threads: 4}
+	mockService.EXPECT().NewKDFParams().Return(kdf, nil).Times(1)
+
+	resp, err := handler.PreRegister(context.Background(), &proto.PreRegisterRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, "argon2id", resp.GetKdf().GetAlgorithm())
+	assert.Equal(t, kdf.Salt, resp.GetKdf().GetSalt())
+
+	mockService.EXPECT().NewKDFParams().Return(nil, errors.New("internal error")).Times(1)
+
+	_, err = handler.PreRegister(context.Background(), &proto.PreRegisterRequest{})
+	assert.EqualError(t, err, "rpc error: code = Internal desc = internal error")
+}
+
+func TestUserHandler_UpgradeKDF(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIUserService(ctrl)
+	mockSessions := mocks.NewMockISessionService(ctrl)
+	handler := NewUserHandler(&config.Config{SecretKey: "test-secret-key"}, mockService, mockSessions, mocks.NewMockITOTPService(ctrl), mocks.NewMockILoginAttemptService(ctrl))
+
+	input := &proto.UpgradeKDFRequest{
+		CurrentAuthHash: "current",
+		AuthHash:        "hash",
+		Secrets:         []*proto.SecretPayload{{Id: 5, Payload: []byte("payload"), Title: "title", SearchTokens: []string{"token"}}},
+		VaultKey:        []byte("vault_key"),
+	}
+	payloads := map[uint64]*pkgModels.Secret{5: {ID: 5, Payload: []byte("payload"), Title: "title", LabelsEncrypted: true, SearchTokens: []string{"token"}}}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		setupMock func()
+		expectErr string
+	}{
+		{
+			name: "Success",
+			ctx:  sessionContext(1, 7),
+			setupMock: func() {
+				mockService.EXPECT().UpgradeKDF(gomock.Any(), 1, "current", "hash", nil, []byte("vault_key"), payloads).Return(nil).Times(1)
+				mockSessions.EXPECT().RevokeOtherSessions(gomock.Any(), 1, uint64(7), events.PasswordChanged).Return(nil).Times(1)
+			},
+		},
+		{
+			name:      "No_User_In_Context",
+			ctx:       context.Background(),
+			setupMock: func() {},
+			expectErr: "rpc error: code = Internal desc = failed to extract user id from context",
+		},
+		{
+			name:      "No_Session_In_Context",
+			ctx:       context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(1)),
+			setupMock: func() {},
+			expectErr: "rpc error: code = Internal desc = failed to extract session id from context",
+		},
+		{
+			name: "Wrong_Current_Auth_Hash",
+			ctx:  sessionContext(1, 7),
+			setupMock: func() {
+				mockService.EXPECT().UpgradeKDF(gomock.Any(), 1, "current", "hash", nil, []byte("vault_key"), payloads).Return(service.ErrBadCredentials).Times(1)
+			},
+			expectErr: "rpc error: code = PermissionDenied desc = bad auth credentials",
+		},
+		{
+			name: "Invalid_KDF_Params",
+			ctx:  sessionContext(1, 7),
+			setupMock: func() {
+				mockService.EXPECT().UpgradeKDF(gomock.Any(), 1, "current", "hash", nil, []byte("vault_key"), payloads).Return(pkgModels.ErrInvalidKDFParams).Times(1)
+			},
+			expectErr: "rpc error: code = InvalidArgument desc = invalid kdf params",
+		},
+		{
+			name: "Upgrade_Not_Required",
+			ctx:  sessionContext(1, 7),
+			setupMock: func() {
+				mockService.EXPECT().UpgradeKDF(gomock.Any(), 1, "current", "hash", nil, []byte("vault_key"), payloads).Return(service.ErrKDFUpgradeNotRequired).Times(1)
+			},
+			expectErr: "rpc error: code = FailedPrecondition desc = kdf upgrade is not required",
+		},
+		{
+			name: "Incomplete_Rekey",
+			ctx:  sessionContext(1, 7),
+			setupMock: func() {
+				mockService.EXPECT().UpgradeKDF(gomock.Any(), 1, "current", "hash", nil, []byte("vault_key"), payloads).Return(repository.ErrIncompleteRekey).Times(1)
+			},
+			expectErr: "rpc error: code = FailedPrecondition desc = not all user secrets were re-encrypted",
+		},
+		{
+			name: "Internal_Error",
+			ctx:  sessionContext(1, 7),
+			setupMock: func() {
+				mockService.EXPECT().UpgradeKDF(gomock.Any(), 1, "current", "hash", nil, []byte("vault_key"), payloads).Return(errors.New("internal error")).Times(1)
+			},
+			expectErr: "rpc error: code = Internal desc = internal error",
+		},
+		{
+			name: "Revoke_Error",
+			ctx:  sessionContext(1, 7),
+			setupMock: func() {
+				mockService.EXPECT().UpgradeKDF(gomock.Any(), 1, "current", "hash", nil, []byte("vault_key"), payloads).Return(nil).Times(1)
+				mockSessions.EXPECT().RevokeOtherSessions(gomock.Any(), 1, uint64(7), events.PasswordChanged).Return(errors.New("db error")).Times(1)
+			},
+			expectErr: "rpc error: code = Internal desc = kdf upgraded, but other sessions were not revoked: db error",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMock()
+
+			_, err := handler.UpgradeKDF(tc.ctx, input)
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUserHandler_GetVaultKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIUserService(ctrl)
+	handler := NewUserHandler(&config.Config{SecretKey: "test-secret-key"}, mockService, mocks.NewMockISessionService(ctrl), mocks.NewMockITOTPService(ctrl), mocks.NewMockILoginAttemptService(ctrl))
+
+	ctx := context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(1))
+	mockService.EXPECT().GetVaultKey(gomock.Any(), 1).Return([]byte("vault_key"), nil).Times(1)
+
+	response, err := handler.GetVaultKey(ctx, &proto.GetVaultKeyRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("vault_key"), response.VaultKey)
+
+	mockService.EXPECT().GetVaultKey(gomock.Any(), 1).Return(nil, gophKeeperErrors.ErrNotFound).Times(1)
+	_, err = handler.GetVaultKey(ctx, &proto.GetVaultKeyRequest{})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestUserHandler_CreateVaultKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIUserService(ctrl)
+	handler := NewUserHandler(&config.Config{SecretKey: "test-secret-key"}, mockService, mocks.NewMockISessionService(ctrl), mocks.NewMockITOTPService(ctrl), mocks.NewMockILoginAttemptService(ctrl))
+
+	ctx := context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(1))
+	input := &proto.CreateVaultKeyRequest{
+		VaultKey: []byte("vault_key"),
+		Secrets:  []*proto.SecretPayload{{Id: 5, Payload: []byte("payload"), Title: "title", DataKey: []byte("data_key")}},
+	}
+	payloads := map[uint64]*pkgModels.Secret{5: {ID: 5, Payload: []byte("payload"), Title: "title", LabelsEncrypted: true, DataKey: []byte("data_key")}}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		setupMock func()
+		expectErr string
+	}{
+		{
+			name: "Success",
+			ctx:  ctx,
+			setupMock: func() {
+				mockService.EXPECT().CreateVaultKey(gomock.Any(), 1, []byte("vault_key"), payloads).Return(nil).Times(1)
+			},
+		},
+		{
+			name:      "No_User_In_Context",
+			ctx:       context.Background(),
+			setupMock: func() {},
+			expectErr: "rpc error: code = Internal desc = failed to extract user id from context",
+		},
+		{
+			name: "Already_Exists",
+			ctx:  ctx,
+			setupMock: func() {
+				mockService.EXPECT().CreateVaultKey(gomock.Any(), 1, []byte("vault_key"), payloads).Return(repository.ErrVaultKeyExists).Times(1)
+			},
+			expectErr: "rpc error: code = AlreadyExists desc = vault key already exists",
+		},
+		{
+			name: "Incomplete_Rekey",
+			ctx:  ctx,
+			setupMock: func() {
+				mockService.EXPECT().CreateVaultKey(gomock.Any(), 1, []byte("vault_key"), payloads).Return(repository.ErrIncompleteRekey).Times(1)
+			},
+			expectErr: "rpc error: code = FailedPrecondition desc = not all user secrets were re-encrypted",
+		},
+		{
+			name: "Invalid_Vault_Key",
+			ctx:  ctx,
+			setupMock: func() {
+				mockService.EXPECT().CreateVaultKey(gomock.Any(), 1, []byte("vault_key"), payloads).Return(service.ErrInvalidVaultKey).Times(1)
+			},
+			expectErr: "rpc error: code = InvalidArgument desc = invalid vault key",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMock()
+
+			_, err := handler.CreateVaultKey(tc.ctx, input)
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUserHandler_KeyPair(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIUserService(ctrl)
+	handler := NewUserHandler(&config.Config{SecretKey: "test-secret-key"}, mockService, mocks.NewMockISessionService(ctrl), mocks.NewMockITOTPService(ctrl), mocks.NewMockILoginAttemptService(ctrl))
+
+	ctx := context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(1))
+	keyPair := &pkgModels.KeyPair{PublicKey: []byte("public"), PrivateKey: []byte("private")}
+
+	mockService.EXPECT().GetKeyPair(gomock.Any(), 1).Return(keyPair, nil).Times(1)
+	response, err := handler.GetKeyPair(ctx, &proto.GetKeyPairRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("public"), response.PublicKey)
+	assert.Equal(t, []byte("private"), response.PrivateKey)
+
+	input := &proto.CreateKeyPairRequest{PublicKey: []byte("public"), PrivateKey: []byte("private")}
+
+	mockService.EXPECT().CreateKeyPair(gomock.Any(), 1, keyPair).Return(nil).Times(1)
+	_, err = handler.CreateKeyPair(ctx, input)
+	assert.NoError(t, err)
+
+	mockService.EXPECT().CreateKeyPair(gomock.Any(), 1, keyPair).Return(repository.ErrKeyPairExists).Times(1)
+	_, err = handler.CreateKeyPair(ctx, input)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	mockService.EXPECT().CreateKeyPair(gomock.Any(), 1, keyPair).Return(service.ErrInvalidKeyPair).Times(1)
+	_, err = handler.CreateKeyPair(ctx, input)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestUserHandler_ChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIUserService(ctrl)
+	mockSessions := mocks.NewMockISessionService(ctrl)
+	handler := NewUserHandler(&config.Config{SecretKey: "test-secret-key"}, mockService, mockSessions, mocks.NewMockITOTPService(ctrl), mocks.NewMockILoginAttemptService(ctrl))
+
+	input := &proto.ChangePasswordRequest{
+		CurrentAuthHash: "current",
+		AuthHash:        "new",
+		Secrets:         []*proto.SecretPayload{{Id: 5, Payload: []byte("payload"), Title: "title", SearchTokens: []string{"token"}}},
+		VaultKey:        []byte("vault_key"),
+	}
+	payloads := map[uint64]*pkgModels.Secret{5: {ID: 5, Payload: []byte("payload"), Title: "title", LabelsEncrypted: true, SearchTokens: []string{"token"}}}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		setupMock func()
+		expectErr string
+	}{
+		{
+			name: "Success",
+			ctx:  sessionContext(1, 7),
+			setupMock: func() {
+				mockService.EXPECT().ChangePassword(gomock.Any(), 1, "current", "new", nil, []byte("vault_key"), payloads).Return(nil).Times(1)
+				mockSessions.EXPECT().RevokeOtherSessions(gomock.Any(), 1, uint64(7), events.PasswordChanged).Return(nil).Times(1)
+			},
+		},
+		{
+			name:      "No_Session_In_Context",
+			ctx:       context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(1)),
+			setupMock: func() {},
+			expectErr: "rpc error: code = Internal desc = failed to extract session id from context",
+		},
+		{
+			name: "Wrong_Current_Password",
+			ctx:  sessionContext(1, 7),
+			setupMock: func() {
+				mockService.EXPECT().ChangePassword(gomock.Any(), 1, "current", "new", nil, []byte("vault_key"), payloads).Return(service.ErrBadCredentials).Times(1)
+			},
+			expectErr: "rpc error: code = PermissionDenied desc = bad auth credentials",
+		},
+		{
+			name: "Incomplete_Rekey",
+			ctx:  sessionContext(1, 7),
+			setupMock: func() {
+				mockService.EXPECT().ChangePassword(gomock.Any(), 1, "current", "new", nil, []byte("vault_key"), payloads).Return(repository.ErrIncompleteRekey).Times(1)
+			},
+			expectErr: "rpc error: code = FailedPrecondition desc = not all user secrets were re-encrypted",
+		},
+		{
+			name: "Vault_Key_Required",
+			ctx:  sessionContext(1, 7),
+			setupMock: func() {
+				mockService.EXPECT().ChangePassword(gomock.Any(), 1, "current", "new", nil, []byte("vault_key"), payloads).Return(repository.ErrVaultKeyRequired).Times(1)
+			},
+			expectErr: "rpc error: code = FailedPrecondition desc = vault key is required",
+		},
+		{
+			name: "Revoke_Error",
+			ctx:  sessionContext(1, 7),
+			setupMock: func() {
+				mockService.EXPECT().ChangePassword(gomock.Any(), 1, "current", "new", nil, []byte("vault_key"), payloads).Return(nil).Times(1)
+				mockSessions.EXPECT().RevokeOtherSessions(gomock.Any(), 1, uint64(7), events.PasswordChanged).Return(errors.New("db error")).Times(1)
+			},
+			expectErr: "rpc error: code = Internal desc = password changed, but other sessions were not revoked: db error",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMock()
+
+			_, err := handler.ChangePassword(tc.ctx, input)
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUserHandler_DeleteAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIUserService(ctrl)
+	mockSessions := mocks.NewMockISessionService(ctrl)
+	handler := NewUserHandler(&config.Config{SecretKey: "test-secret-key"}, mockService, mockSessions, mocks.NewMockITOTPService(ctrl), mocks.NewMockILoginAttemptService(ctrl))
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		setupMock func()
+		expectErr string
+	}{
+		{
+			name: "Success",
+			ctx:  sessionContext(1, 7),
+			setupMock: func() {
+				mockService.EXPECT().DeleteAccount(gomock.Any(), 1, "hash").Return(nil).Times(1)
+				mockSessions.EXPECT().CloseStreams(1, events.AccountDeleted).Times(1)
+			},
+		},
+		{
+			name:      "No_User_In_Context",
+			ctx:       context.Background(),
+			setupMock: func() {},
+			expectErr: "rpc error: code = Internal desc = failed to extract user id from context",
+		},
+		{
+			name: "Wrong_Password",
+			ctx:  sessionContext(1, 7),
+			setupMock: func() {
+				mockService.EXPECT().DeleteAccount(gomock.Any(), 1, "hash").Return(service.ErrBadCredentials).Times(1)
+			},
+			expectErr: "rpc error: code = PermissionDenied desc = bad auth credentials",
+		},
+		{
+			name: "Last_Owner",
+			ctx:  sessionContext(1, 7),
+			setupMock: func() {
+				mockService.EXPECT().DeleteAccount(gomock.Any(), 1, "hash").
+					Return(fmt.Errorf("failed to delete account: organization Team: %w", gophKeeperErrors.ErrLastOwner)).Times(1)
+			},
+			expectErr: "rpc error: code = FailedPrecondition desc = failed to delete account: organization Team: organization must keep at least one owner",
+		},
+		{
+			name: "Internal_Error",
+			ctx:  sessionContext(1, 7),
+			setupMock: func() {
+				mockService.EXPECT().DeleteAccount(gomock.Any(), 1, "hash").Return(errors.New("db error")).Times(1)
+			},
+			expectErr: "rpc error: code = Internal desc = db error",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMock()
+
+			_, err := handler.DeleteAccount(tc.ctx, &proto.DeleteAccountRequest{AuthHash: "hash"})
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUserHandler_RefreshToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessions := mocks.NewMockISessionService(ctrl)
+	handler := NewUserHandler(&config.Config{SecretKey: "test-secret-key"}, mocks.NewMockIUserService(ctrl), mockSessions, mocks.NewMockITOTPService(ctrl), mocks.NewMockILoginAttemptService(ctrl))
+
+	tests := []struct {
+		name      string
+		setupMock func()
+		expectRes *proto.RefreshTokenResponse
+		expectErr string
+	}{
+		{
+			name: "Success",
+			setupMock: func() {
+				mockSessions.EXPECT().RefreshSession(gomock.Any(), "refresh").Return(&models.Tokens{AccessToken: "new_access", RefreshToken: "new_refresh"}, nil).Times(1)
+			},
+			expectRes: &proto.RefreshTokenResponse{AccessToken: "new_access", RefreshToken: "new_refresh"},
+		},
+		{
+			name: "Invalid_Token",
+			setupMock: func() {
+				mockSessions.EXPECT().RefreshSession(gomock.Any(), "refresh").Return(nil, service.ErrInvalidRefreshToken).Times(1)
+			},
+			expectErr: "rpc error: code = Unauthenticated desc = invalid refresh token",
+		},
+		{
+			name: "Internal_Error",
+			setupMock: func() {
+				mockSessions.EXPECT().RefreshSession(gomock.Any(), "refresh").Return(nil, errors.New("db error")).Times(1)
+			},
+			expectErr: "rpc error: code = Internal desc = db error",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMock()
+
+			res, err := handler.RefreshToken(context.Background(), &proto.RefreshTokenRequest{RefreshToken: "refresh"})
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectRes.AccessToken, res.AccessToken)
+				assert.Equal(t, tc.expectRes.RefreshToken, res.RefreshToken)
+			}
+		})
+	}
+}
+
+func TestUserHandler_ListSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessions := mocks.NewMockISessionService(ctrl)
+	handler := NewUserHandler(&config.Config{SecretKey: "test-secret-key"}, mocks.NewMockIUserService(ctrl), mockSessions, mocks.NewMockITOTPService(ctrl), mocks.NewMockILoginAttemptService(ctrl))
+
+	ctx := context.WithValue(context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(1)), consts.CtxSessionIDKey, uint64(7))
+	now := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		mockSessions.EXPECT().ListSessions(gomock.Any(), 1).Return([]models.Session{
+			{ID: 7, DeviceID: 42, DeviceName: "laptop", CreatedAt: now, LastUsedAt: now},
+			{ID: 8, DeviceID: 43, DeviceName: "phone", CreatedAt: now, LastUsedAt: now},
+		}, nil).Times(1)
+
+		res, err := handler.ListSessions(ctx, &proto.ListSessionsRequest{})
+
+		assert.NoError(t, err)
+		if assert.Len(t, res.Sessions, 2) {
+			assert.Equal(t, uint64(42), res.Sessions[0].DeviceId)
+			assert.Equal(t, "laptop", res.Sessions[0].DeviceName)
+			assert.True(t, res.Sessions[0].Current)
+			assert.False(t, res.Sessions[1].Current)
+		}
+	})
+
+	t.Run("Internal_Error", func(t *testing.T) {
+		mockSessions.EXPECT().ListSessions(gomock.Any(), 1).Return(nil, errors.New("db error")).Times(1)
+
+		_, err := handler.ListSessions(ctx, &proto.ListSessionsRequest{})
+
+		assert.EqualError(t, err, "rpc error: code = Internal desc = db error")
+	})
+
+	t.Run("Missing_Session", func(t *testing.T) {
+		_, err := handler.ListSessions(context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(1)), &proto.ListSessionsRequest{})
+
+		assert.EqualError(t, err, "rpc error: code = Internal desc = failed to extract session id from context")
+	})
+}
+
+func TestUserHandler_ManageSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessions := mocks.NewMockISessionService(ctrl)
+	handler := NewUserHandler(&config.Config{SecretKey: "test-secret-key"}, mocks.NewMockIUserService(ctrl), mockSessions, mocks.NewMockITOTPService(ctrl), mocks.NewMockILoginAttemptService(ctrl))
+
+	ctx := context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(1))
+
+	tests := []struct {
+		name      string
+		call      func() error
+		expectErr string
+	}{
+		{
+			name: "Rename_Success",
+			call: func() error {
+				mockSessions.EXPECT().RenameSession(gomock.Any(), 1, uint64(8), "work laptop").Return(nil).Times(1)
+				_, err := handler.RenameSession(ctx, &proto.RenameSessionRequest{Id: 8, DeviceName: "work laptop"})
+				return err
+			},
+		},
+		{
+			name: "Rename_Invalid_Name",
+			call: func() error {
+				mockSessions.EXPECT().RenameSession(gomock.Any(), 1, uint64(8), "").Return(service.ErrInvalidDeviceName).Times(1)
+				_, err := handler.RenameSession(ctx, &proto.RenameSessionRequest{Id: 8})
+				return err
+			},
+			expectErr: "rpc error: code = InvalidArgument desc = invalid device name",
+		},
+		{
+			name: "Rename_NotFound",
+			call: func() error {
+				mockSessions.EXPECT().RenameSession(gomock.Any(), 1, uint64(9), "phone").Return(gophKeeperErrors.ErrNotFound).Times(1)
+				_, err := handler.RenameSession(ctx, &proto.RenameSessionRequest{Id: 9, DeviceName: "phone"})
+				return err
+			},
+			expectErr: "rpc error: code = NotFound desc = " + gophKeeperErrors.ErrNotFound.Error(),
+		},
+		{
+			name: "Revoke_Success",
+			call: func() error {
+				mockSessions.EXPECT().RevokeSession(gomock.Any(), 1, uint64(8)).Return(nil).Times(1)
+				_, err := handler.RevokeSession(ctx, &proto.RevokeSessionRequest{Id: 8})
+				return err
+			},
+		},
+		{
+			name: "Revoke_Foreign_Session",
+			call: func() error {
+				mockSessions.EXPECT().RevokeSession(gomock.Any(), 1, uint64(100)).Return(gophKeeperErrors.ErrNotFound).Times(1)
+				_, err := handler.RevokeSession(ctx, &proto.RevokeSessionRequest{Id: 100})
+				return err
+			},
+			expectErr: "rpc error: code = NotFound desc = " + gophKeeperErrors.ErrNotFound.Error(),
+		},
+		{
+			name: "LogoutAll_Success",
+			call: func() error {
+				mockSessions.EXPECT().LogoutAll(gomock.Any(), 1).Return(nil).Times(1)
+				_, err := handler.LogoutAll(ctx, &proto.LogoutAllRequest{})
+				return err
+			},
+		},
+		{
+			name: "LogoutAll_Internal_Error",
+			call: func() error {
+				mockSessions.EXPECT().LogoutAll(gomock.Any(), 1).Return(errors.New("db error")).Times(1)
+				_, err := handler.LogoutAll(ctx, &proto.LogoutAllRequest{})
+				return err
+			},
+			expectErr: "rpc error: code = Internal desc = db error",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.call()
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUserHandler_VerifyTOTP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessions := mocks.NewMockISessionService(ctrl)
+	mockTOTP := mocks.NewMockITOTPService(ctrl)
+	mockAttempts := mocks.NewMockILoginAttemptService(ctrl)
+	handler := NewUserHandler(&config.Config{SecretKey: "test-secret-key"}, mocks.NewMockIUserService(ctrl), mockSessions, mockTOTP, mockAttempts)
+
+	challenge := &auth.TOTPChallenge{UserID: 1, Login: "alice", DeviceID: 42, DeviceName: "laptop"}
+
+	tests := []struct {
+		name      string
+		setupMock func()
+		expectErr string
+	}{
+		{
+			name: "Success",
+			setupMock: func() {
+				mockTOTP.EXPECT().ParseChallenge("challenge").Return(challenge, nil).Times(1)
+				mockAttempts.EXPECT().Reserve(gomock.Any(), "alice", "").Return(uint64(1), nil).Times(1)
+				mockTOTP.EXPECT().VerifyCode(gomock.Any(), 1, "123456").Return(nil).Times(1)
+				mockAttempts.EXPECT().RecordSuccess(gomock.Any(), uint64(1)).Return(nil).Times(1)
+				mockSessions.EXPECT().CreateSession(gomock.Any(), 1, uint64(42), "laptop").Return(&models.Tokens{AccessToken: "access", RefreshToken: "refresh"}, nil).Times(1)
+			},
+		},
+		{
+			name: "Invalid_Code",
+			setupMock: func() {
+				mockTOTP.EXPECT().ParseChallenge("challenge").Return(challenge, nil).Times(1)
+				mockAttempts.EXPECT().Reserve(gomock.Any(), "alice", "").Return(uint64(1), nil).Times(1)
+				mockTOTP.EXPECT().VerifyCode(gomock.Any(), 1, "123456").Return(service.ErrInvalidTOTPCode).Times(1)
+			},
+			expectErr: "rpc error: code = Unauthenticated desc = invalid authentication code",
+		},
+		{
+			name: "Expired_Challenge",
+			setupMock: func() {
+				mockTOTP.EXPECT().ParseChallenge("challenge").Return(nil, service.ErrInvalidChallenge).Times(1)
+			},
+			expectErr: "rpc error: code = Unauthenticated desc = invalid or expired login challenge",
+		},
+		{
+			name: "Locked_Out",
+			setupMock: func() {
+				mockTOTP.EXPECT().ParseChallenge("challenge").Return(challenge, nil).Times(1)
+				mockAttempts.EXPECT().Reserve(gomock.Any(), "alice", "").Return(uint64(0), &service.LockoutError{RetryAfter: time.Minute}).Times(1)
+			},
+			expectErr: "rpc error: code = ResourceExhausted desc = too many failed login attempts, retry in 1m0s",
+		},
+		{
+			name: "Verify_Error_Released",
+			setupMock: func() {
+				mockTOTP.EXPECT().ParseChallenge("challenge").Return(challenge, nil).Times(1)
+				mockAttempts.EXPECT().Reserve(gomock.Any(), "alice", "").Return(uint64(1), nil).Times(1)
+				mockTOTP.EXPECT().VerifyCode(gomock.Any(), 1, "123456").Return(errors.New("db error")).Times(1)
+				mockAttempts.EXPECT().Release(gomock.Any(), uint64(1)).Return(nil).Times(1)
+			},
+			expectErr: "rpc error: code = Internal desc = db error",
+		},
+		{
+			name: "Session_Error",
+			setupMock: func() {
+				mockTOTP.EXPECT().ParseChallenge("challenge").Return(challenge, nil).Times(1)
+				mockAttempts.EXPECT().Reserve(gomock.Any(), "alice", "").Return(uint64(1), nil).Times(1)
+				mockTOTP.EXPECT().VerifyCode(gomock.Any(), 1, "123456").Return(nil).Times(1)
+				mockAttempts.EXPECT().RecordSuccess(gomock.Any(), uint64(1)).Return(nil).Times(1)
+				mockSessions.EXPECT().CreateSession(gomock.Any(), 1, uint64(42), "laptop").Return(nil, errors.New("db error")).Times(1)
+			},
+			expectErr: "rpc error: code = Internal desc = failed to auth: db error",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMock()
+
+			res, err := handler.VerifyTOTP(context.Background(), &proto.VerifyTOTPRequest{Challenge: "challenge", Code: "123456"})
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "access", res.AccessToken)
+			assert.Equal(t, "refresh", res.RefreshToken)
+		})
+	}
+}
+
+func TestLockoutStatus(t *testing.T) {
+	err := lockoutStatus(&service.LockoutError{RetryAfter: 30 * time.Second})
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	if assert.Len(t, st.Details(), 1) {
+		retryInfo, ok := st.Details()[0].(*errdetails.RetryInfo)
+		assert.True(t, ok)
+		assert.Equal(t, 30*time.Second, retryInfo.RetryDelay.AsDuration())
+	}
+
+	assert.Equal(t, codes.Internal, status.Code(lockoutStatus(errors.New("db error"))))
+}
+
+func TestExtractPeerAddress(t *testing.T) {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 50000}})
+
+	assert.Equal(t, "10.0.0.1", extractPeerAddress(ctx))
+	assert.Equal(t, "", extractPeerAddress(context.Background()))
+}
+
+func TestUserHandler_ManageTOTP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTOTP := mocks.NewMockITOTPService(ctrl)
+	handler := NewUserHandler(&config.Config{SecretKey: "test-secret-key"}, mocks.NewMockIUserService(ctrl), mocks.NewMockISessionService(ctrl), mockTOTP, mocks.NewMockILoginAttemptService(ctrl))
+
+	ctx := context.WithValue(context.Background(), consts.CtxUserIDKey, uint64(1))
+
+	tests := []struct {
+		name      string
+		call      func() error
+		expectErr string
+	}{
+		{
+			name: "Enable_Success",
+			call: func() error {
+				mockTOTP.EXPECT().Enable(gomock.Any(), 1).Return(&models.TOTPSetup{Secret: "SECRET", URI: "otpauth://totp/x"}, nil).Times(1)
+				res, err := handler.EnableTOTP(ctx, &proto.EnableTOTPRequest{})
+				if err == nil {
+					assert.Equal(t, "SECRET", res.Secret)
+					assert.Equal(t, "otpauth://totp/x", res.Uri)
+				}
+				return err
+			},
+		},
+		{
+			name: "Enable_Already_Enabled",
+			call: func() error {
+				mockTOTP.EXPECT().Enable(gomock.Any(), 1).Return(nil, service.ErrTOTPAlreadyEnabled).Times(1)
+				_, err := handler.EnableTOTP(ctx, &proto.EnableTOTPRequest{})
+				return err
+			},
+			expectErr: "rpc error: code = FailedPrecondition desc = two-factor authentication is already enabled",
+		},
+		{
+			name: "Confirm_Success",
+			call: func() error {
+				mockTOTP.EXPECT().Confirm(gomock.Any(), 1, "123456").Return([]string{"abcde-fghij"}, nil).Times(1)
+				res, err := handler.ConfirmTOTP(ctx, &proto.ConfirmTOTPRequest{Code: "123456"})
+				if err == nil {
+					assert.Equal(t, []string{"abcde-fghij"}, res.RecoveryCodes)
+				}
+				return err
+			},
+		},
+		{
+			name: "Confirm_Invalid_Code",
+			call: func() error {
+				mockTOTP.EXPECT().Confirm(gomock.Any(), 1, "000000").Return(nil, service.ErrInvalidTOTPCode).Times(1)
+				_, err := handler.ConfirmTOTP(ctx, &proto.ConfirmTOTPRequest{Code: "000000"})
+				return err
+			},
+			expectErr: "rpc error: code = InvalidArgument desc = invalid authentication code",
+		},
+		{
+			name: "Confirm_Not_Pending",
+			call: func() error {
+				mockTOTP.EXPECT().Confirm(gomock.Any(), 1, "123456").Return(nil, service.ErrTOTPNotPending).Times(1)
+				_, err := handler.ConfirmTOTP(ctx, &proto.ConfirmTOTPRequest{Code: "123456"})
+				return err
+			},
+			expectErr: "rpc error: code = FailedPrecondition desc = two-factor authentication setup was not started",
+		},
+		{
+			name: "Disable_Success",
+			call: func() error {
+				mockTOTP.EXPECT().Disable(gomock.Any(), 1, "123456").Return(nil).Times(1)
+				_, err := handler.DisableTOTP(ctx, &proto.DisableTOTPRequest{Code: "123456"})
+				return err
+			},
+		},
+		{
+			name: "Disable_Not_Enabled",
+			call: func() error {
+				mockTOTP.EXPECT().Disable(gomock.Any(), 1, "123456").Return(service.ErrTOTPNotEnabled).Times(1)
+				_, err := handler.DisableTOTP(ctx, &proto.DisableTOTPRequest{Code: "123456"})
+				return err
+			},
+			expectErr: "rpc error: code = FailedPrecondition desc = two-factor authentication is not enabled",
+		},
+		{
+			name: "Disable_Internal_Error",
+			call: func() error {
+				mockTOTP.EXPECT().Disable(gomock.Any(), 1, "123456").Return(errors.New("db error")).Times(1)
+				_, err := handler.DisableTOTP(ctx, &proto.DisableTOTPRequest{Code: "123456"})
+				return err
+			},
+			expectErr: "rpc error: code = Internal desc = db error",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.call()
 
 			if tc.expectErr != "" {
 				assert.EqualError(t, err, tc.expectErr)
@@ -4,18 +4,35 @@ package interceptors
 import (
 	"beliaev-aa/GophKeeper/internal/server/auth"
 	"beliaev-aa/GophKeeper/pkg/consts"
+	"beliaev-aa/GophKeeper/pkg/proto"
 	"context"
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
-	"strings"
 )
 
-// authContext производит проверку токена доступа из метаданных контекста и добавляет ID пользователя в контекст.
+// publicMethods перечисляет полные имена методов, доступных без токена доступа: получения параметров KDF,
+// регистрации, входа в систему, проверки второго фактора и обновления токенов.
+var publicMethods = map[string]struct{}{
+	proto.Users_PreRegister_FullMethodName:  {},
+	proto.Users_Register_FullMethodName:     {},
+	proto.Users_PreLogin_FullMethodName:     {},
+	proto.Users_Login_FullMethodName:        {},
+	proto.Users_VerifyTOTP_FullMethodName:   {},
+	proto.Users_RefreshToken_FullMethodName: {},
+}
+
+// SessionValidator проверяет, что сессия, к которой привязан токен доступа, не отозвана.
+type SessionValidator interface {
+	IsSessionActive(ctx context.Context, userID int, sessionID uint64) (bool, error)
+}
+
+// authContext производит проверку токена доступа из метаданных контекста и добавляет ID пользователя
+// и ID сессии в контекст. Токены отозванных сессий отклоняются, даже если срок их действия не истёк.
 // Возвращает обновленный контекст и ошибку, если аутентификация не пройдена.
-func authContext(secretKey []byte, ctx context.Context) (context.Context, error) {
+func authContext(secretKey []byte, sessions SessionValidator, ctx context.Context) (context.Context, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, status.Error(codes.Unauthenticated, "unable to extract metadata")
@@ -42,19 +59,40 @@ func authContext(secretKey []byte, ctx context.Context) (context.Context, error)
 		return nil, status.Error(codes.Unauthenticated, "invalid user id in claims")
 	}
 
+	sessionID, ok := tokenMap["session_id"].(float64)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no session id in claims")
+	}
+
+	active, err := sessions.IsSessionActive(ctx, int(userID), uint64(sessionID))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to check session: %s", err.Error())
+	}
+	if !active {
+		return nil, status.Error(codes.Unauthenticated, "session is revoked")
+	}
+
 	ctx = context.WithValue(ctx, consts.CtxUserIDKey, uint64(userID))
+	ctx = context.WithValue(ctx, consts.CtxSessionIDKey, uint64(sessionID))
 	return ctx, nil
 }
 
+// isPublicMethod проверяет, доступен ли метод без токена доступа. Полное имя метода должно точно
+// совпадать с одним из имён publicMethods.
+func isPublicMethod(fullMethod string) bool {
+	_, ok := publicMethods[fullMethod]
+	return ok
+}
+
 // Authentication создает и возвращает interceptor для серверных вызовов gRPC.
-// Автоматически применяется ко всем вызовам, кроме методов регистрации и входа в систему.
-func Authentication(secretKey []byte) grpc.UnaryServerInterceptor {
+// Автоматически применяется ко всем вызовам, кроме методов регистрации, входа в систему и обновления токенов.
+func Authentication(secretKey []byte, sessions SessionValidator) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		if strings.Contains(info.FullMethod, "Register") || strings.Contains(info.FullMethod, "Login") {
+		if isPublicMethod(info.FullMethod) {
 			return handler(ctx, req)
 		}
 
-		ctx, err := authContext(secretKey, ctx)
+		ctx, err := authContext(secretKey, sessions, ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -65,10 +103,10 @@ func Authentication(secretKey []byte) grpc.UnaryServerInterceptor {
 
 // StreamAuthentication создаёт interceptor для потоковых серверных вызовов gRPC.
 // Автоматически применяется ко всем потоковым вызовам для аутентификации пользователей
-// с помощью переданного секретного ключа.
-func StreamAuthentication(secretKey []byte) grpc.StreamServerInterceptor {
+// с помощью переданного секретного ключа и проверки сессии.
+func StreamAuthentication(secretKey []byte, sessions SessionValidator) grpc.StreamServerInterceptor {
 	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		ctx, err := authContext(secretKey, ss.Context())
+		ctx, err := authContext(secretKey, sessions, ss.Context())
 		if err != nil {
 			return err
 		}
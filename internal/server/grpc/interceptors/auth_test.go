@@ -3,7 +3,10 @@ package interceptors
 import (
 	"beliaev-aa/GophKeeper/internal/server/auth"
 	"beliaev-aa/GophKeeper/pkg/consts"
+	"beliaev-aa/GophKeeper/tests/mocks"
 	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
@@ -21,9 +24,24 @@ type AuthTestCase struct {
 	expectRes any
 }
 
+// tokenContext возвращает входящий контекст с токеном доступа сессии sessionID.
+func tokenContext(t *testing.T, secretKey []byte, userID int, sessionID uint64) context.Context {
+	token, err := auth.CreateToken(userID, sessionID, time.Now().Add(time.Hour), secretKey)
+	require.NoError(t, err)
+
+	md := metadata.New(map[string]string{
+		consts.AccessTokenHeader: token,
+	})
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
 func TestAuthentication(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
 	secretKey := "test"
-	authInterceptor := Authentication([]byte(secretKey))
+	sessions := mocks.NewMockISessionService(ctrl)
+	authInterceptor := Authentication([]byte(secretKey), sessions)
 
 	handler := func(ctx context.Context, req any) (any, error) {
 		var isAuth bool
@@ -42,7 +60,57 @@ func TestAuthentication(t *testing.T) {
 			setup: func() context.Context {
 				return context.Background()
 			},
-			method:    "..../RegisterV1",
+			method:    "/proto.Users/Register",
+			handler:   handler,
+			expectErr: "",
+			expectRes: false,
+		},
+		{
+			name: "login_methods_skip",
+			setup: func() context.Context {
+				return context.Background()
+			},
+			method:    "/proto.Users/PreLogin",
+			handler:   handler,
+			expectErr: "",
+			expectRes: false,
+		},
+		{
+			name: "similar_method_name_requires_auth",
+			setup: func() context.Context {
+				return context.Background()
+			},
+			method:    "/proto.Secrets/RegisterLoginHistory",
+			handler:   handler,
+			expectErr: "rpc error: code = Unauthenticated desc = unable to extract metadata",
+			expectRes: nil,
+		},
+		{
+			name: "other_service_method_requires_auth",
+			setup: func() context.Context {
+				return context.Background()
+			},
+			method:    "/proto.Secrets/Login",
+			handler:   handler,
+			expectErr: "rpc error: code = Unauthenticated desc = unable to extract metadata",
+			expectRes: nil,
+		},
+		{
+			name: "refresh_method_skip",
+			setup: func() context.Context {
+				return context.Background()
+			},
+			method:    "/proto.Users/RefreshToken",
+			handler:   handler,
+			expectErr: "",
+			expectRes: false,
+		},
+		{
+			name: "verify_totp_method_skip",
+			setup: func() context.Context {
+				return context.Background()
+			},
+			method:    "/proto.Users/VerifyTOTP",
 			handler:   handler,
 			expectErr: "",
 			expectRes: false,
@@ -50,21 +118,36 @@ func TestAuthentication(t *testing.T) {
 		{
 			name: "valid_auth",
 			setup: func() context.Context {
-				userID := uint64(111)
-				token, err := auth.CreateToken(int(userID), time.Now().Add(time.Hour), []byte(secretKey))
-				require.NoError(t, err)
-
-				md := metadata.New(map[string]string{
-					consts.AccessTokenHeader: token,
-				})
-
-				return metadata.NewIncomingContext(context.Background(), md)
+				sessions.EXPECT().IsSessionActive(gomock.Any(), 111, uint64(1)).Return(true, nil).Times(1)
+				return tokenContext(t, []byte(secretKey), 111, 1)
 			},
 			method:    "SomeMethod",
 			handler:   handler,
 			expectErr: "",
 			expectRes: true,
 		},
+		{
+			name: "revoked_session",
+			setup: func() context.Context {
+				sessions.EXPECT().IsSessionActive(gomock.Any(), 111, uint64(1)).Return(false, nil).Times(1)
+				return tokenContext(t, []byte(secretKey), 111, 1)
+			},
+			method:    "SomeMethod",
+			handler:   handler,
+			expectErr: "rpc error: code = Unauthenticated desc = session is revoked",
+			expectRes: nil,
+		},
+		{
+			name: "session_check_failed",
+			setup: func() context.Context {
+				sessions.EXPECT().IsSessionActive(gomock.Any(), 111, uint64(1)).Return(false, errors.New("db error")).Times(1)
+				return tokenContext(t, []byte(secretKey), 111, 1)
+			},
+			method:    "SomeMethod",
+			handler:   handler,
+			expectErr: "rpc error: code = Internal desc = failed to check session: db error",
+			expectRes: nil,
+		},
 		{
 			name: "failed_auth",
 			setup: func() context.Context {
@@ -96,7 +179,21 @@ func TestAuthentication(t *testing.T) {
 }
 
 func TestAuthContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
 	secretKey := []byte("test")
+	sessions := mocks.NewMockISessionService(ctrl)
+
+	t.Run("session_id_in_context", func(t *testing.T) {
+		sessions.EXPECT().IsSessionActive(gomock.Any(), 111, uint64(42)).Return(true, nil).Times(1)
+
+		ctx, err := authContext(secretKey, sessions, tokenContext(t, secretKey, 111, 42))
+
+		require.NoError(t, err)
+		assert.Equal(t, uint64(111), ctx.Value(consts.CtxUserIDKey))
+		assert.Equal(t, uint64(42), ctx.Value(consts.CtxSessionIDKey))
+	})
 
 	tests := []AuthTestCase{
 		{
@@ -123,7 +220,7 @@ func TestAuthContext(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			ctx := tc.setup()
 
-			_, err := authContext(secretKey, ctx)
+			_, err := authContext(secretKey, sessions, ctx)
 
 			if tc.expectErr != "" {
 				assert.EqualError(t, err, tc.expectErr)
@@ -133,3 +230,35 @@ func TestAuthContext(t *testing.T) {
 		})
 	}
 }
+
+// testServerStream подменяет контекст серверного потока в тестах.
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamAuthentication(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	secretKey := []byte("test")
+	sessions := mocks.NewMockISessionService(ctrl)
+	interceptor := StreamAuthentication(secretKey, sessions)
+
+	handler := func(_ any, stream grpc.ServerStream) error {
+		assert.Equal(t, uint64(7), stream.Context().Value(consts.CtxSessionIDKey))
+		return nil
+	}
+
+	sessions.EXPECT().IsSessionActive(gomock.Any(), 111, uint64(7)).Return(true, nil).Times(1)
+	err := interceptor(nil, &testServerStream{ctx: tokenContext(t, secretKey, 111, 7)}, &grpc.StreamServerInfo{}, handler)
+	assert.NoError(t, err)
+
+	sessions.EXPECT().IsSessionActive(gomock.Any(), 111, uint64(7)).Return(false, nil).Times(1)
+	err = interceptor(nil, &testServerStream{ctx: tokenContext(t, secretKey, 111, 7)}, &grpc.StreamServerInfo{}, handler)
+	assert.EqualError(t, err, "rpc error: code = Unauthenticated desc = session is revoked")
+}
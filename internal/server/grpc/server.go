@@ -5,6 +5,7 @@ package grpc
 import (
 	"beliaev-aa/GophKeeper/certs"
 	"beliaev-aa/GophKeeper/internal/server/config"
+	"beliaev-aa/GophKeeper/internal/server/events"
 	"beliaev-aa/GophKeeper/internal/server/grpc/handlers"
 	"beliaev-aa/GophKeeper/internal/server/grpc/interceptors"
 	"beliaev-aa/GophKeeper/internal/server/service"
@@ -24,29 +25,57 @@ import (
 	"time"
 )
 
+const (
+	// trashPurgeInterval определяет периодичность очистки корзины от секретов с истёкшим сроком хранения.
+	trashPurgeInterval = time.Hour
+	// emergencyGrantInterval определяет периодичность предоставления экстренных доступов,
+	// срок ожидания которых истёк.
+	emergencyGrantInterval = time.Minute
+)
+
 // Server представляет сервер gRPC, содержащий конфигурацию, логгер и сам gRPC сервер.
 type Server struct {
-	config     *config.Config
-	grpcServer *grpc.Server
-	logger     *zap.Logger
+	config           *config.Config
+	grpcServer       *grpc.Server
+	hub              *events.Hub
+	secretService    service.ISecretService
+	blobService      service.IBlobService
+	emergencyService service.IEmergencyService
+	attemptService   service.ILoginAttemptService
+	logger           *zap.Logger
 }
 
 // NewServer создает и инициализирует новый экземпляр сервера gRPC с заданными параметрами.
 // Принимает конфигурацию сервера, хранилище данных и логгер.
 func NewServer(config *config.Config, storage *storage.Storage, logger *zap.Logger) *Server {
-	grpcServer := setupGRPCServer(config, storage, logger)
+	blobService := service.NewBlobService(storage.BlobRepository, storage.BlobStore, config)
+	secretService := service.NewSecretService(storage.SecretRepository, storage.OrganizationRepository, storage.EmergencyRepository, blobService, config)
+	emergencyService := service.NewEmergencyService(storage.EmergencyRepository, storage.UserRepository)
+	attemptService := service.NewLoginAttemptService(storage.LoginAttemptRepository, config)
+	hub := events.NewHub(logger)
+	grpcServer := setupGRPCServer(config, storage, hub, secretService, blobService, emergencyService, attemptService, logger)
 	return &Server{
-		config:     config,
-		grpcServer: grpcServer,
-		logger:     logger,
+		config:           config,
+		grpcServer:       grpcServer,
+		hub:              hub,
+		secretService:    secretService,
+		blobService:      blobService,
+		emergencyService: emergencyService,
+		attemptService:   attemptService,
+		logger:           logger,
 	}
 }
 
 // setupGRPCServer настраивает и возвращает gRPC сервер с конфигурацией TLS и interceptors.
-func setupGRPCServer(cfg *config.Config, storage *storage.Storage, logger *zap.Logger) *grpc.Server {
+// Хаб событий и сервисы секретов, бинарных объектов, экстренного доступа и попыток входа передаются извне,
+// так как они используются и фоновыми задачами сервера: очисткой корзины и журнала попыток входа
+// и предоставлением экстренных доступов.
+func setupGRPCServer(cfg *config.Config, storage *storage.Storage, hub *events.Hub, secretService service.ISecretService, blobService service.IBlobService, emergencyService service.IEmergencyService, attemptService service.ILoginAttemptService, logger *zap.Logger) *grpc.Server {
+	sessionService := service.NewSessionService(storage.SessionRepository, cfg, hub)
+
 	opts := []grpc.ServerOption{
-		grpc.ChainUnaryInterceptor(interceptors.Authentication([]byte(cfg.SecretKey))),
-		grpc.StreamInterceptor(interceptors.StreamAuthentication([]byte(cfg.SecretKey))),
+		grpc.ChainUnaryInterceptor(interceptors.Authentication([]byte(cfg.SecretKey), sessionService)),
+		grpc.StreamInterceptor(interceptors.StreamAuthentication([]byte(cfg.SecretKey), sessionService)),
 	}
 
 	tlsCredentials, err := loadTLSConfig("ca-cert.pem", "server-cert.pem", "server-key.pem")
@@ -56,15 +85,28 @@ func setupGRPCServer(cfg *config.Config, storage *storage.Storage, logger *zap.L
 	opts = append(opts, grpc.Creds(tlsCredentials))
 
 	server := grpc.NewServer(opts...)
+	shareService := service.NewShareService(storage.ShareRepository, cfg)
 
-	proto.RegisterUsersServer(server, handlers.NewUserHandler(cfg, service.NewUserService(storage.UserRepository)))
-	proto.RegisterSecretsServer(server, handlers.NewSecretHandler(logger, service.NewSecretService(storage.SecretRepository)))
-	proto.RegisterNotificationServer(server, handlers.NewNotificationHandler(logger))
+	proto.RegisterUsersServer(server, handlers.NewUserHandler(
+		cfg,
+		service.NewUserService(storage.UserRepository, blobService, cfg),
+		sessionService,
+		service.NewTOTPService(storage.TOTPRepository, storage.UserRepository, cfg),
+		attemptService,
+	))
+	proto.RegisterSecretsServer(server, handlers.NewSecretHandler(logger, secretService, hub))
+	proto.RegisterBlobsServer(server, handlers.NewBlobHandler(logger, blobService, secretService, shareService))
+	proto.RegisterFoldersServer(server, handlers.NewFolderHandler(logger, service.NewFolderService(storage.FolderRepository), secretService))
+	proto.RegisterSharesServer(server, handlers.NewShareHandler(logger, shareService, hub))
+	proto.RegisterOrganizationsServer(server, handlers.NewOrganizationHandler(logger, service.NewOrganizationService(storage.OrganizationRepository, storage.UserRepository, blobService)))
+	proto.RegisterEmergencyServer(server, handlers.NewEmergencyHandler(logger, emergencyService, hub))
+	proto.RegisterNotificationServer(server, handlers.NewNotificationHandler(logger, hub))
 
 	return server
 }
 
-// Start запускает сервер gRPC и ожидает сигналы ОС для graceful завершения работы сервера.
+// Start запускает сервер gRPC, фоновую очистку корзины и журнала попыток входа и предоставление экстренных доступов,
+// после чего ожидает сигналы ОС для graceful завершения работы сервера.
 func (s *Server) Start() error {
 	listener, err := net.Listen("tcp", s.config.Address)
 	if err != nil {
@@ -77,16 +119,105 @@ func (s *Server) Start() error {
 		}
 	}()
 
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	defer stopPurge()
+	go s.runTrashPurge(purgeCtx, trashPurgeInterval)
+	go s.runEmergencyGrants(purgeCtx, emergencyGrantInterval)
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 
 	sig := <-quit
 	s.logger.Info("Interrupt signal received", zap.String("signal", sig.String()))
+	stopPurge()
 	s.shutdown()
 
 	return nil
 }
 
+// runTrashPurge периодически удаляет из корзины секреты, срок хранения которых истёк,
+// объекты, на которые больше не ссылаются секреты, и устаревшие попытки входа.
+// Первая очистка выполняется сразу при запуске; работа завершается при отмене контекста.
+func (s *Server) runTrashPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.purgeTrash(ctx)
+		s.purgeLoginAttempts(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeTrash выполняет одну очистку корзины, затем удаляет объекты, на которые больше не ссылаются секреты,
+// и записывает результаты в лог. Сборка объектов выполняется, даже если очистка корзины не удалась.
+func (s *Server) purgeTrash(ctx context.Context) {
+	purged, err := s.secretService.PurgeExpiredTrash(ctx)
+	if err != nil {
+		s.logger.Error("failed to purge trash", zap.Error(err))
+	} else if purged > 0 {
+		s.logger.Info("expired secrets purged from trash", zap.Int64("count", purged))
+	}
+
+	collected, err := s.blobService.CollectGarbage(ctx)
+	if err != nil {
+		s.logger.Error("failed to collect blobs", zap.Error(err))
+	}
+	if collected > 0 {
+		s.logger.Info("unreferenced blobs deleted", zap.Int("count", collected))
+	}
+}
+
+// purgeLoginAttempts удаляет попытки входа, которые больше не учитываются блокировками,
+// и записывает результат в лог.
+func (s *Server) purgeLoginAttempts(ctx context.Context) {
+	purged, err := s.attemptService.PurgeExpired(ctx)
+	if err != nil {
+		s.logger.Error("failed to purge login attempts", zap.Error(err))
+	} else if purged > 0 {
+		s.logger.Info("expired login attempts purged", zap.Int64("count", purged))
+	}
+}
+
+// runEmergencyGrants периодически предоставляет запрошенные экстренные доступы, срок ожидания которых истёк.
+// Первая проверка выполняется сразу при запуске; работа завершается при отмене контекста.
+func (s *Server) runEmergencyGrants(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.grantEmergencyAccess(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// grantEmergencyAccess предоставляет экстренные доступы с истёкшим сроком ожидания и уведомляет об этом
+// владельцев хранилищ и доверенных пользователей.
+func (s *Server) grantEmergencyAccess(ctx context.Context) {
+	granted, err := s.emergencyService.GrantExpired(ctx)
+	if err != nil {
+		s.logger.Error("failed to grant emergency access", zap.Error(err))
+		return
+	}
+
+	for _, access := range granted {
+		s.logger.Info("emergency access granted", zap.Uint64("access_id", access.ID))
+		for _, userID := range []uint64{access.GrantorID, access.GranteeID} {
+			s.hub.Publish(events.Event{UserID: userID, SecretID: access.ID, Kind: events.EmergencyAccessChanged})
+		}
+	}
+}
+
 // Останавливает сервер gRPC, осуществляя его graceful завершение.
 func (s *Server) shutdown() {
 	s.logger.Info("Shutting down server...")
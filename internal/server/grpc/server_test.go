@@ -2,13 +2,18 @@ package grpc
 
 import (
 	"beliaev-aa/GophKeeper/internal/server/config"
+	"beliaev-aa/GophKeeper/internal/server/events"
 	"beliaev-aa/GophKeeper/internal/server/storage"
+	"beliaev-aa/GophKeeper/pkg/models"
 	"beliaev-aa/GophKeeper/tests/mocks"
+	"context"
+	"errors"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/credentials"
 	"testing"
+	"time"
 )
 
 func TestNewServer(t *testing.T) {
@@ -24,6 +29,8 @@ func TestNewServer(t *testing.T) {
 	mockStorage := &storage.Storage{
 		UserRepository:   mocks.NewMockIUserRepository(ctrl),
 		SecretRepository: mocks.NewMockISecretRepository(ctrl),
+		BlobRepository:   mocks.NewMockIBlobRepository(ctrl),
+		BlobStore:        mocks.NewMockBlobStore(ctrl),
 	}
 
 	srv := NewServer(cfg, mockStorage, logger)
@@ -31,6 +38,10 @@ func TestNewServer(t *testing.T) {
 	assert.NotNil(t, srv)
 	assert.Equal(t, cfg, srv.config)
 	assert.NotNil(t, srv.grpcServer)
+	assert.NotNil(t, srv.secretService)
+	assert.NotNil(t, srv.blobService)
+	assert.NotNil(t, srv.emergencyService)
+	assert.NotNil(t, srv.hub)
 	assert.Equal(t, logger, srv.logger)
 }
 
@@ -45,13 +56,91 @@ func TestSetupGRPCServer(t *testing.T) {
 	mockStorage := &storage.Storage{
 		UserRepository:   mocks.NewMockIUserRepository(ctrl),
 		SecretRepository: mocks.NewMockISecretRepository(ctrl),
+		BlobRepository:   mocks.NewMockIBlobRepository(ctrl),
+		BlobStore:        mocks.NewMockBlobStore(ctrl),
 	}
 
-	server := setupGRPCServer(cfg, mockStorage, logger)
+	server := setupGRPCServer(cfg, mockStorage, events.NewHub(logger), mocks.NewMockISecretService(ctrl), mocks.NewMockIBlobService(ctrl), mocks.NewMockIEmergencyService(ctrl), mocks.NewMockILoginAttemptService(ctrl), logger)
 
 	assert.NotNil(t, server)
 }
 
+func TestRunTrashPurge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockISecretService(ctrl)
+	mockBlobService := mocks.NewMockIBlobService(ctrl)
+	mockAttemptService := mocks.NewMockILoginAttemptService(ctrl)
+	srv := &Server{secretService: mockService, blobService: mockBlobService, attemptService: mockAttemptService, logger: zap.NewNop()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	mockService.EXPECT().PurgeExpiredTrash(gomock.Any()).DoAndReturn(func(context.Context) (int64, error) {
+		calls++
+		if calls >= 2 {
+			cancel()
+			return 0, errors.New("database error")
+		}
+		return 1, nil
+	}).MinTimes(2)
+	mockBlobService.EXPECT().CollectGarbage(gomock.Any()).Return(1, nil).MinTimes(2)
+	mockAttemptService.EXPECT().PurgeExpired(gomock.Any()).Return(int64(1), nil).MinTimes(2)
+
+	done := make(chan struct{})
+	go func() {
+		srv.runTrashPurge(ctx, time.Millisecond)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("trash purge did not stop after context cancellation")
+	}
+}
+
+func TestRunEmergencyGrants(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := zap.NewNop()
+	hub := events.NewHub(logger)
+	grantor := hub.Subscribe(1, 1, 1)
+	defer hub.Unsubscribe(grantor)
+	grantee := hub.Subscribe(2, 2, 2)
+	defer hub.Unsubscribe(grantee)
+
+	mockService := mocks.NewMockIEmergencyService(ctrl)
+	srv := &Server{emergencyService: mockService, hub: hub, logger: logger}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	mockService.EXPECT().GrantExpired(gomock.Any()).DoAndReturn(func(context.Context) ([]*models.EmergencyAccess, error) {
+		calls++
+		if calls >= 2 {
+			cancel()
+			return nil, errors.New("database error")
+		}
+		return []*models.EmergencyAccess{{ID: 5, GrantorID: 1, GranteeID: 2}}, nil
+	}).MinTimes(2)
+
+	done := make(chan struct{})
+	go func() {
+		srv.runEmergencyGrants(ctx, time.Millisecond)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("emergency grants did not stop after context cancellation")
+	}
+
+	assert.Equal(t, events.Event{UserID: 1, SecretID: 5, Kind: events.EmergencyAccessChanged}, <-grantor.Events)
+	assert.Equal(t, events.Event{UserID: 2, SecretID: 5, Kind: events.EmergencyAccessChanged}, <-grantee.Events)
+}
+
 func TestLoadTLSConfig(t *testing.T) {
 	t.Run("Valid certificates", func(t *testing.T) {
 		tlsConfig, err := loadTLSConfig("ca-cert.pem", "server-cert.pem", "server-key.pem")
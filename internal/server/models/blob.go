@@ -0,0 +1,10 @@
+package models
+
+// BlobRef указывает на бинарный объект пользователя. Идентификатор объекта генерирует клиент,
+// поэтому объект однозначно определяется только вместе с владельцем.
+type BlobRef struct {
+	// UserID - идентификатор пользователя, владельца объекта.
+	UserID uint64 `db:"user_id"`
+	// ID - идентификатор объекта.
+	ID string `db:"id"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// LoginAttempt описывает попытку входа в учётную запись.
+type LoginAttempt struct {
+	// ID - уникальный идентификатор попытки.
+	ID uint64 `db:"id"`
+	// Login - логин, указанный при попытке входа. Сохраняется и для несуществующих пользователей.
+	Login string `db:"login"`
+	// UserID - идентификатор пользователя или nil, если логин не найден.
+	UserID *int `db:"user_id"`
+	// PeerAddress - IP-адрес клиента без порта.
+	PeerAddress string `db:"peer_address"`
+	// Success - признак успешного входа.
+	Success bool `db:"success"`
+	// CreatedAt - время попытки.
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// FailureStats содержит сводку неудачных попыток входа для логина или адреса клиента.
+type FailureStats struct {
+	// Count - количество учитываемых неудачных попыток.
+	Count int `db:"count"`
+	// LastAt - время последней неудачной попытки или nil, если их не было.
+	LastAt *time.Time `db:"last_at"`
+}
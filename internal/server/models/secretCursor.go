@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// SecretCursor указывает позицию в списке секретов: последний секрет предыдущей страницы.
+// Следующая страница начинается с секрета, идущего за ним в порядке списка.
+type SecretCursor struct {
+	// ID - идентификатор секрета; упорядочивает секреты с одинаковым временем изменения.
+	ID uint64 `json:"id"`
+	// UpdatedAt - время изменения секрета.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Session описывает сессию пользователя, к которой привязан refresh-токен.
+// Сам refresh-токен на сервере не хранится, сохраняется только его хэш.
+type Session struct {
+	// ID - уникальный идентификатор сессии. Передаётся в access-токене.
+	ID uint64 `db:"id"`
+	// UserID - идентификатор пользователя, владельца сессии.
+	UserID int `db:"user_id"`
+	// DeviceID - постоянный идентификатор устройства, на котором открыта сессия.
+	// Совпадает с идентификатором клиента в заголовке X-Client-ID.
+	DeviceID uint64 `db:"device_id"`
+	// DeviceName - имя устройства, заданное клиентом или пользователем.
+	DeviceName string `db:"device_name"`
+	// TokenHash - хэш текущего refresh-токена сессии.
+	TokenHash []byte `db:"token_hash"`
+	// PreviousTokenHash - хэш refresh-токена до последней ротации.
+	// Его повторное предъявление означает утечку токена, и сессия отзывается.
+	PreviousTokenHash []byte `db:"previous_token_hash"`
+	// CreatedAt - время создания сессии.
+	CreatedAt time.Time `db:"created_at"`
+	// LastUsedAt - время последнего обновления токенов сессии.
+	LastUsedAt time.Time `db:"last_used_at"`
+	// ExpiresAt - время истечения текущего refresh-токена.
+	ExpiresAt time.Time `db:"expires_at"`
+	// RevokedAt - время отзыва сессии или nil, если сессия активна.
+	RevokedAt *time.Time `db:"revoked_at"`
+}
+
+// Tokens содержит пару токенов, выдаваемую клиенту при входе и обновлении сессии.
+type Tokens struct {
+	// AccessToken - короткоживущий JWT токен доступа.
+	AccessToken string
+	// RefreshToken - долгоживущий одноразовый токен для получения новой пары токенов.
+	RefreshToken string
+}
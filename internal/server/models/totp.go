@@ -0,0 +1,23 @@
+package models
+
+// TOTP описывает настройки двухфакторной аутентификации пользователя по одноразовым кодам (RFC 6238).
+type TOTP struct {
+	// UserID - идентификатор пользователя.
+	UserID int `db:"user_id"`
+	// Secret - подтверждённый секрет TOTP или nil, если второй фактор не включён.
+	Secret []byte `db:"secret"`
+	// PendingSecret - секрет, выданный при включении и ещё не подтверждённый кодом.
+	PendingSecret []byte `db:"pending_secret"`
+	// Enabled - признак того, что при входе требуется второй фактор.
+	Enabled bool `db:"enabled"`
+	// LastStep - последний использованный шаг TOTP. Коды этого и более ранних шагов повторно не принимаются.
+	LastStep int64 `db:"last_step"`
+}
+
+// TOTPSetup содержит данные для добавления секрета TOTP в приложение-аутентификатор.
+type TOTPSetup struct {
+	// Secret - секрет в кодировке base32 для ручного ввода.
+	Secret string
+	// URI - ссылка otpauth:// для QR-кода.
+	URI string
+}
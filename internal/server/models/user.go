@@ -1,7 +1,21 @@
 // Package models содержит модели данных приложения.
 package models
 
-import "time"
+import (
+	"beliaev-aa/GophKeeper/pkg/models"
+	"time"
+)
+
+// VaultLoginPrefix - префикс логина служебной учётной записи хранилища организации.
+// Логины с этим префиксом недоступны для регистрации.
+const VaultLoginPrefix = "org:"
+
+const (
+	// AuthVersionLegacy - учётная запись хранит bcrypt-хэш мастер-пароля (устаревшая схема).
+	AuthVersionLegacy = 0
+	// AuthVersionHash - учётная запись хранит bcrypt-хэш от хэша аутентификации, вычисленного клиентом.
+	AuthVersionHash = 1
+)
 
 // User описывает структуру данных пользователя.
 // Она включает в себя поля для хранения времени создания и обновления пользователя,
@@ -11,8 +25,17 @@ type User struct {
 	ID int `json:"id" db:"id"`
 	// Login содержит логин пользователя, используемый для входа в систему.
 	Login string `json:"login" db:"login"`
-	// Password содержит пароль пользователя. Это поле не включается в JSON представление.
+	// Password содержит bcrypt-хэш учётных данных пользователя. Это поле не включается в JSON представление.
+	// Для AuthVersionHash хэшируется хэш аутентификации клиента, сам мастер-пароль сервер не получает.
 	Password string `json:"-" db:"password"`
+	// AuthVersion определяет схему хранения учётных данных пользователя.
+	AuthVersion int `json:"-" db:"auth_version"`
+	// KDF содержит параметры вывода ключей из мастер-пароля. Значение nil означает,
+	// что ключи выводятся по устаревшей схеме с солью из логина и хранилище требует перешифрования.
+	KDF *models.KDFParams `json:"-" db:"kdf"`
+	// VaultKey содержит ключ хранилища пользователя, зашифрованный клиентом ключом из мастер-пароля;
+	// nil, если ключ хранилища ещё не создан. Сервер хранит его как непрозрачные данные.
+	VaultKey []byte `json:"-" db:"vault_key"`
 	// CreatedAt содержит временную метку создания аккаунта пользователя.
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	// UpdatedAt содержит временную метку последнего обновления данных аккаунта пользователя.
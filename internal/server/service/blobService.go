@@ -0,0 +1,192 @@
+// Package service предоставляет бизнес-логику для потоковой передачи бинарных объектов.
+package service
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/config"
+	"beliaev-aa/GophKeeper/internal/server/storage"
+	"beliaev-aa/GophKeeper/internal/server/storage/repository"
+	"beliaev-aa/GophKeeper/pkg/consts"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// blobIDPattern задаёт формат идентификатора объекта: случайное значение в шестнадцатеричном виде.
+var blobIDPattern = regexp.MustCompile(`^[0-9a-f]{32,64}$`)
+
+// IBlobService интерфейс для сервиса приёма и выдачи бинарных объектов.
+type IBlobService interface {
+	StartUpload(ctx context.Context, userID uint64, blobID string, chunkCount uint32) (*models.BlobStatus, error)
+	SaveChunk(ctx context.Context, userID uint64, status *models.BlobStatus, index uint32, data []byte) error
+	GetBlobStatus(ctx context.Context, userID uint64, blobID string) (*models.BlobStatus, error)
+	GetChunk(ctx context.Context, userID uint64, blobID string, index uint32) ([]byte, error)
+	CheckBlob(ctx context.Context, userID uint64, blobID string) error
+	ReleaseBlobs(ctx context.Context, userID uint64, blobIDs []string) error
+	CollectGarbage(ctx context.Context) (int, error)
+	DeleteUserBlobs(ctx context.Context, userID uint64) error
+}
+
+// BlobService предоставляет методы для загрузки и скачивания бинарных объектов по фрагментам.
+// Фрагменты зашифрованы на клиенте, поэтому сервер проверяет только их порядок и размер.
+// Содержимое фрагментов хранится в хранилище объектов под ключами "<пользователь>/<объект>/<индекс>",
+// а в базе данных учитываются полученные фрагменты и ссылки на объекты из секретов.
+type BlobService struct {
+	blobRepository repository.IBlobRepository // blobRepository является репозиторием для доступа к объектам в базе данных.
+	blobStore      storage.BlobStore          // blobStore хранит содержимое фрагментов.
+	config         *config.Config             // config задаёт срок, после которого незавершённые загрузки удаляются.
+}
+
+// NewBlobService создает новый экземпляр BlobService.
+// Принимает в качестве аргументов репозиторий бинарных объектов, хранилище их содержимого
+// и конфигурацию сервера и возвращает ссылку на сервис.
+func NewBlobService(blobRepository repository.IBlobRepository, blobStore storage.BlobStore, config *config.Config) IBlobService {
+	return &BlobService{
+		blobRepository: blobRepository,
+		blobStore:      blobStore,
+		config:         config,
+	}
+}
+
+// StartUpload начинает или продолжает загрузку объекта и возвращает его текущее состояние.
+// Возвращает ErrInvalidBlob, если идентификатор или количество фрагментов некорректны
+// либо не совпадают с указанными при начале загрузки.
+func (s *BlobService) StartUpload(ctx context.Context, userID uint64, blobID string, chunkCount uint32) (*models.BlobStatus, error) {
+	if !blobIDPattern.MatchString(blobID) {
+		return nil, fmt.Errorf("%w: malformed id %q", gophKeeperErrors.ErrInvalidBlob, blobID)
+	}
+	if chunkCount == 0 || chunkCount > consts.MaxBlobChunks {
+		return nil, fmt.Errorf("%w: chunk count %d is out of range", gophKeeperErrors.ErrInvalidBlob, chunkCount)
+	}
+
+	status, err := s.blobRepository.CreateBlob(ctx, userID, blobID, chunkCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start blob upload: %w", err)
+	}
+	if status.ChunkCount != chunkCount {
+		return nil, fmt.Errorf("%w: blob %s has %d chunks, got %d", gophKeeperErrors.ErrInvalidBlob, blobID, status.ChunkCount, chunkCount)
+	}
+
+	return status, nil
+}
+
+// SaveChunk сохраняет очередной фрагмент объекта и отмечает его полученным в status.
+// Фрагменты принимаются строго по порядку: индекс должен совпадать с количеством уже полученных фрагментов.
+func (s *BlobService) SaveChunk(ctx context.Context, userID uint64, status *models.BlobStatus, index uint32, data []byte) error {
+	if index != status.ReceivedChunks || index >= status.ChunkCount {
+		return fmt.Errorf("%w: expected chunk %d, got %d", gophKeeperErrors.ErrInvalidBlob, status.ReceivedChunks, index)
+	}
+	if len(data) > consts.MaxBlobChunkSize {
+		return fmt.Errorf("%w: chunk %d exceeds %d bytes", gophKeeperErrors.ErrInvalidBlob, index, consts.MaxBlobChunkSize)
+	}
+
+	if err := s.blobStore.Put(ctx, chunkKey(userID, status.ID, index), data); err != nil {
+		return fmt.Errorf("failed to store blob chunk: %w", err)
+	}
+	if err := s.blobRepository.SaveChunk(ctx, userID, status.ID, index); err != nil {
+		return fmt.Errorf("failed to save blob chunk: %w", err)
+	}
+	status.ReceivedChunks++
+
+	return nil
+}
+
+// GetBlobStatus возвращает состояние загрузки объекта.
+// Возвращает ErrNotFound, если объект не найден или принадлежит другому пользователю.
+func (s *BlobService) GetBlobStatus(ctx context.Context, userID uint64, blobID string) (*models.BlobStatus, error) {
+	status, err := s.blobRepository.GetBlobStatus(ctx, userID, blobID)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("blob %w (id=%s)", gophKeeperErrors.ErrNotFound, blobID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// GetChunk возвращает фрагмент объекта по его индексу.
+// Возвращает ErrNotFound, если фрагмент не найден. Ключ фрагмента включает ID пользователя,
+// поэтому фрагменты других пользователей недоступны.
+func (s *BlobService) GetChunk(ctx context.Context, userID uint64, blobID string, index uint32) ([]byte, error) {
+	data, err := s.blobStore.Get(ctx, chunkKey(userID, blobID, index))
+	if isNotFound(err) {
+		return nil, fmt.Errorf("blob chunk %w (id=%s, index=%d)", gophKeeperErrors.ErrNotFound, blobID, index)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// CheckBlob проверяет, что секрет может сослаться на объект: объект принадлежит пользователю
+// и загружен полностью. Возвращает ErrInvalidBlob в противном случае.
+func (s *BlobService) CheckBlob(ctx context.Context, userID uint64, blobID string) error {
+	status, err := s.blobRepository.GetBlobStatus(ctx, userID, blobID)
+	if isNotFound(err) {
+		return fmt.Errorf("%w: unknown blob %q", gophKeeperErrors.ErrInvalidBlob, blobID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check blob: %w", err)
+	}
+	if !status.Complete() {
+		return fmt.Errorf("%w: blob %s has %d of %d chunks", gophKeeperErrors.ErrInvalidBlob, blobID, status.ReceivedChunks, status.ChunkCount)
+	}
+	return nil
+}
+
+// ReleaseBlobs удаляет перечисленные объекты пользователя, на которые больше не ссылаются секреты и их версии,
+// вместе с их содержимым. Объекты, на которые ещё есть ссылки, остаются нетронутыми.
+func (s *BlobService) ReleaseBlobs(ctx context.Context, userID uint64, blobIDs []string) error {
+	deleted, err := s.blobRepository.DeleteUnreferenced(ctx, userID, blobIDs)
+	if err != nil {
+		return fmt.Errorf("failed to release blobs: %w", err)
+	}
+
+	var errs []error
+	for _, blobID := range deleted {
+		errs = append(errs, s.deleteContent(ctx, userID, blobID))
+	}
+	return errors.Join(errs...)
+}
+
+// CollectGarbage удаляет объекты всех пользователей, на которые не ссылается ни один секрет
+// и которые созданы раньше, чем истёк срок незавершённой загрузки: брошенные загрузки и объекты
+// секретов, удалённых из корзины по сроку хранения или вытесненных из истории версий.
+// Возвращает количество удалённых объектов.
+func (s *BlobService) CollectGarbage(ctx context.Context) (int, error) {
+	deleted, err := s.blobRepository.DeleteOrphans(ctx, time.Now().Add(-s.config.BlobUploadTTL))
+	if err != nil {
+		return 0, fmt.Errorf("failed to collect blobs: %w", err)
+	}
+
+	var errs []error
+	for _, ref := range deleted {
+		errs = append(errs, s.deleteContent(ctx, ref.UserID, ref.ID))
+	}
+	return len(deleted), errors.Join(errs...)
+}
+
+// DeleteUserBlobs удаляет содержимое всех объектов пользователя.
+// Вызывается после удаления учётной записи, когда записи об объектах уже удалены из базы данных.
+func (s *BlobService) DeleteUserBlobs(ctx context.Context, userID uint64) error {
+	if err := s.blobStore.DeletePrefix(ctx, fmt.Sprintf("%d/", userID)); err != nil {
+		return fmt.Errorf("failed to delete user blobs: %w", err)
+	}
+	return nil
+}
+
+// deleteContent удаляет из хранилища все фрагменты объекта.
+func (s *BlobService) deleteContent(ctx context.Context, userID uint64, blobID string) error {
+	if err := s.blobStore.DeletePrefix(ctx, fmt.Sprintf("%d/%s/", userID, blobID)); err != nil {
+		return fmt.Errorf("failed to delete blob %s content: %w", blobID, err)
+	}
+	return nil
+}
+
+// chunkKey возвращает ключ фрагмента объекта в хранилище.
+func chunkKey(userID uint64, blobID string, index uint32) string {
+	return fmt.Sprintf("%d/%s/%d", userID, blobID, index)
+}
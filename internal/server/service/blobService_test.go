@@ -0,0 +1,339 @@
+package service
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/config"
+	serverModels "beliaev-aa/GophKeeper/internal/server/models"
+	"beliaev-aa/GophKeeper/pkg/consts"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testBlobID = "0123456789abcdef0123456789abcdef"
+
+func TestBlobService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIBlobRepository(ctrl)
+	mockStore := mocks.NewMockBlobStore(ctrl)
+	service := NewBlobService(mockRepo, mockStore, &config.Config{BlobUploadTTL: time.Hour})
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "StartUpload_Success",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().CreateBlob(ctx, uint64(1), testBlobID, uint32(3)).
+					Return(&models.BlobStatus{ID: testBlobID, ChunkCount: 3, ReceivedChunks: 1}, nil)
+
+				status, err := service.StartUpload(ctx, 1, testBlobID, 3)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if status.ReceivedChunks != 1 {
+					t.Errorf("Expected upload to resume from chunk 1, got %d", status.ReceivedChunks)
+				}
+			},
+		},
+		{
+			name: "StartUpload_Fail_MalformedID",
+			testFunc: func(t *testing.T) {
+				_, err := service.StartUpload(ctx, 1, "../etc/passwd", 3)
+				if !errors.Is(err, gophKeeperErrors.ErrInvalidBlob) {
+					t.Errorf("Expected error 'ErrInvalidBlob', got %v", err)
+				}
+			},
+		},
+		{
+			name: "StartUpload_Fail_ChunkCount",
+			testFunc: func(t *testing.T) {
+				for _, count := range []uint32{0, consts.MaxBlobChunks + 1} {
+					if _, err := service.StartUpload(ctx, 1, testBlobID, count); !errors.Is(err, gophKeeperErrors.ErrInvalidBlob) {
+						t.Errorf("Expected error 'ErrInvalidBlob' for %d chunks, got %v", count, err)
+					}
+				}
+			},
+		},
+		{
+			name: "StartUpload_Fail_ChunkCountMismatch",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().CreateBlob(ctx, uint64(1), testBlobID, uint32(4)).
+					Return(&models.BlobStatus{ID: testBlobID, ChunkCount: 3}, nil)
+
+				_, err := service.StartUpload(ctx, 1, testBlobID, 4)
+				if !errors.Is(err, gophKeeperErrors.ErrInvalidBlob) {
+					t.Errorf("Expected error 'ErrInvalidBlob', got %v", err)
+				}
+			},
+		},
+		{
+			name: "StartUpload_Fail_Repository",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().CreateBlob(ctx, uint64(1), testBlobID, uint32(3)).Return(nil, errors.New("database error"))
+
+				_, err := service.StartUpload(ctx, 1, testBlobID, 3)
+				if err == nil || !strings.Contains(err.Error(), "failed to start blob upload") {
+					t.Errorf("Expected wrapped repository error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "SaveChunk_Success",
+			testFunc: func(t *testing.T) {
+				status := &models.BlobStatus{ID: testBlobID, ChunkCount: 3, ReceivedChunks: 1}
+				gomock.InOrder(
+					mockStore.EXPECT().Put(ctx, "1/"+testBlobID+"/1", []byte("chunk")).Return(nil),
+					mockRepo.EXPECT().SaveChunk(ctx, uint64(1), testBlobID, uint32(1)).Return(nil),
+				)
+
+				if err := service.SaveChunk(ctx, 1, status, 1, []byte("chunk")); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if status.ReceivedChunks != 2 {
+					t.Errorf("Expected 2 received chunks, got %d", status.ReceivedChunks)
+				}
+			},
+		},
+		{
+			name: "SaveChunk_Fail_OutOfOrder",
+			testFunc: func(t *testing.T) {
+				status := &models.BlobStatus{ID: testBlobID, ChunkCount: 3, ReceivedChunks: 1}
+
+				if err := service.SaveChunk(ctx, 1, status, 2, []byte("chunk")); !errors.Is(err, gophKeeperErrors.ErrInvalidBlob) {
+					t.Errorf("Expected error 'ErrInvalidBlob', got %v", err)
+				}
+			},
+		},
+		{
+			name: "SaveChunk_Fail_ExtraChunk",
+			testFunc: func(t *testing.T) {
+				status := &models.BlobStatus{ID: testBlobID, ChunkCount: 3, ReceivedChunks: 3}
+
+				if err := service.SaveChunk(ctx, 1, status, 3, []byte("chunk")); !errors.Is(err, gophKeeperErrors.ErrInvalidBlob) {
+					t.Errorf("Expected error 'ErrInvalidBlob', got %v", err)
+				}
+			},
+		},
+		{
+			name: "SaveChunk_Fail_TooLarge",
+			testFunc: func(t *testing.T) {
+				status := &models.BlobStatus{ID: testBlobID, ChunkCount: 3}
+
+				if err := service.SaveChunk(ctx, 1, status, 0, make([]byte, consts.MaxBlobChunkSize+1)); !errors.Is(err, gophKeeperErrors.ErrInvalidBlob) {
+					t.Errorf("Expected error 'ErrInvalidBlob', got %v", err)
+				}
+			},
+		},
+		{
+			name: "SaveChunk_Fail_Repository",
+			testFunc: func(t *testing.T) {
+				status := &models.BlobStatus{ID: testBlobID, ChunkCount: 3}
+				mockStore.EXPECT().Put(ctx, "1/"+testBlobID+"/0", []byte("chunk")).Return(nil)
+				mockRepo.EXPECT().SaveChunk(ctx, uint64(1), testBlobID, uint32(0)).Return(errors.New("database error"))
+
+				if err := service.SaveChunk(ctx, 1, status, 0, []byte("chunk")); err == nil {
+					t.Errorf("Expected error, got nil")
+				}
+				if status.ReceivedChunks != 0 {
+					t.Errorf("Expected failed chunk not to be counted, got %d", status.ReceivedChunks)
+				}
+			},
+		},
+		{
+			name: "SaveChunk_Fail_Store",
+			testFunc: func(t *testing.T) {
+				status := &models.BlobStatus{ID: testBlobID, ChunkCount: 3}
+				mockStore.EXPECT().Put(ctx, "1/"+testBlobID+"/0", []byte("chunk")).Return(errors.New("disk full"))
+
+				err := service.SaveChunk(ctx, 1, status, 0, []byte("chunk"))
+				if err == nil || !strings.Contains(err.Error(), "failed to store blob chunk") {
+					t.Errorf("Expected wrapped store error, got %v", err)
+				}
+				if status.ReceivedChunks != 0 {
+					t.Errorf("Expected failed chunk not to be counted, got %d", status.ReceivedChunks)
+				}
+			},
+		},
+		{
+			name: "GetBlobStatus_Fail_NotFound",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().GetBlobStatus(ctx, uint64(2), testBlobID).Return(nil, gophKeeperErrors.ErrNotFound)
+
+				_, err := service.GetBlobStatus(ctx, 2, testBlobID)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+		{
+			name: "GetBlobStatus_Success",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().GetBlobStatus(ctx, uint64(1), testBlobID).Return(&models.BlobStatus{ID: testBlobID, ChunkCount: 3, ReceivedChunks: 3}, nil)
+
+				status, err := service.GetBlobStatus(ctx, 1, testBlobID)
+				if err != nil || !status.Complete() {
+					t.Errorf("Expected complete blob, got %+v, %v", status, err)
+				}
+			},
+		},
+		{
+			name: "GetChunk_Success",
+			testFunc: func(t *testing.T) {
+				mockStore.EXPECT().Get(ctx, "1/"+testBlobID+"/0").Return([]byte("chunk"), nil)
+
+				data, err := service.GetChunk(ctx, 1, testBlobID, 0)
+				if err != nil || string(data) != "chunk" {
+					t.Errorf("Unexpected chunk %q, %v", data, err)
+				}
+			},
+		},
+		{
+			name: "GetChunk_Fail_NotFound",
+			testFunc: func(t *testing.T) {
+				mockStore.EXPECT().Get(ctx, "1/"+testBlobID+"/7").Return(nil, gophKeeperErrors.ErrNotFound)
+
+				_, err := service.GetChunk(ctx, 1, testBlobID, 7)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+		{
+			name: "GetChunk_Fail_Store",
+			testFunc: func(t *testing.T) {
+				mockStore.EXPECT().Get(ctx, "1/"+testBlobID+"/0").Return(nil, errors.New("connection refused"))
+
+				if _, err := service.GetChunk(ctx, 1, testBlobID, 0); err == nil {
+					t.Errorf("Expected error, got nil")
+				}
+			},
+		},
+		{
+			name: "CheckBlob_Success",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().GetBlobStatus(ctx, uint64(1), testBlobID).Return(&models.BlobStatus{ID: testBlobID, ChunkCount: 2, ReceivedChunks: 2}, nil)
+
+				if err := service.CheckBlob(ctx, 1, testBlobID); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "CheckBlob_Fail_Unknown",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().GetBlobStatus(ctx, uint64(2), testBlobID).Return(nil, gophKeeperErrors.ErrNotFound)
+
+				if err := service.CheckBlob(ctx, 2, testBlobID); !errors.Is(err, gophKeeperErrors.ErrInvalidBlob) {
+					t.Errorf("Expected error 'ErrInvalidBlob', got %v", err)
+				}
+			},
+		},
+		{
+			name: "CheckBlob_Fail_Incomplete",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().GetBlobStatus(ctx, uint64(1), testBlobID).Return(&models.BlobStatus{ID: testBlobID, ChunkCount: 2, ReceivedChunks: 1}, nil)
+
+				if err := service.CheckBlob(ctx, 1, testBlobID); !errors.Is(err, gophKeeperErrors.ErrInvalidBlob) {
+					t.Errorf("Expected error 'ErrInvalidBlob', got %v", err)
+				}
+			},
+		},
+		{
+			name: "ReleaseBlobs_DeletesUnreferencedContent",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().DeleteUnreferenced(ctx, uint64(1), []string{"old", "shared"}).Return([]string{"old"}, nil)
+				mockStore.EXPECT().DeletePrefix(ctx, "1/old/").Return(nil)
+
+				if err := service.ReleaseBlobs(ctx, 1, []string{"old", "shared"}); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "ReleaseBlobs_Fail_Store",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().DeleteUnreferenced(ctx, uint64(1), []string{"a", "b"}).Return([]string{"a", "b"}, nil)
+				mockStore.EXPECT().DeletePrefix(ctx, "1/a/").Return(errors.New("connection refused"))
+				mockStore.EXPECT().DeletePrefix(ctx, "1/b/").Return(nil)
+
+				err := service.ReleaseBlobs(ctx, 1, []string{"a", "b"})
+				if err == nil || !strings.Contains(err.Error(), "failed to delete blob a content") {
+					t.Errorf("Expected store error for blob a, got %v", err)
+				}
+			},
+		},
+		{
+			name: "ReleaseBlobs_Fail_Repository",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().DeleteUnreferenced(ctx, uint64(1), []string{"a"}).Return(nil, errors.New("database error"))
+
+				if err := service.ReleaseBlobs(ctx, 1, []string{"a"}); err == nil {
+					t.Errorf("Expected error, got nil")
+				}
+			},
+		},
+		{
+			name: "CollectGarbage_Success",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().DeleteOrphans(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, before time.Time) ([]serverModels.BlobRef, error) {
+					if age := time.Since(before); age < time.Hour || age > time.Hour+time.Minute {
+						t.Errorf("Expected orphans older than upload TTL, got cutoff %v ago", age)
+					}
+					return []serverModels.BlobRef{{UserID: 1, ID: "abandoned"}, {UserID: 2, ID: "purged"}}, nil
+				})
+				mockStore.EXPECT().DeletePrefix(ctx, "1/abandoned/").Return(nil)
+				mockStore.EXPECT().DeletePrefix(ctx, "2/purged/").Return(nil)
+
+				collected, err := service.CollectGarbage(ctx)
+				if err != nil || collected != 2 {
+					t.Errorf("Expected 2 collected blobs, got %d, %v", collected, err)
+				}
+			},
+		},
+		{
+			name: "CollectGarbage_Fail_Repository",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().DeleteOrphans(ctx, gomock.Any()).Return(nil, errors.New("database error"))
+
+				if _, err := service.CollectGarbage(ctx); err == nil {
+					t.Errorf("Expected error, got nil")
+				}
+			},
+		},
+		{
+			name: "DeleteUserBlobs_Success",
+			testFunc: func(t *testing.T) {
+				mockStore.EXPECT().DeletePrefix(ctx, "3/").Return(nil)
+
+				if err := service.DeleteUserBlobs(ctx, 3); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "DeleteUserBlobs_Fail",
+			testFunc: func(t *testing.T) {
+				mockStore.EXPECT().DeletePrefix(ctx, "3/").Return(errors.New("connection refused"))
+
+				if err := service.DeleteUserBlobs(ctx, 3); err == nil {
+					t.Errorf("Expected error, got nil")
+				}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
@@ -0,0 +1,188 @@
+// Package service предоставляет бизнес-логику экстренного доступа доверенных пользователей к хранилищам.
+package service
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/storage/repository"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxEmergencyWaitDays - наибольший срок ожидания экстренного доступа в днях.
+const maxEmergencyWaitDays = 90
+
+// ErrInvalidEmergencyRequest определяет ошибку, возникающую, если клиент не передал логин доверенного
+// пользователя или ключ хранилища, указал срок ожидания вне допустимого диапазона или назначил
+// доверенным самого себя.
+var ErrInvalidEmergencyRequest = errors.New("invalid emergency access request")
+
+// IEmergencyService определяет интерфейс для сервиса экстренного доступа.
+type IEmergencyService interface {
+	// AddContact назначает пользователя доверенным для хранилища владельца.
+	AddContact(ctx context.Context, grantorID uint64, access *models.EmergencyAccess) (*models.EmergencyAccess, error)
+
+	// ListContacts возвращает доверенных пользователей владельца хранилища.
+	ListContacts(ctx context.Context, grantorID uint64) ([]*models.EmergencyAccess, error)
+
+	// ListGrants возвращает владельцев хранилищ, назначивших пользователя доверенным.
+	ListGrants(ctx context.Context, granteeID uint64) ([]*models.EmergencyAccess, error)
+
+	// RequestAccess запрашивает экстренный доступ от имени доверенного пользователя.
+	RequestAccess(ctx context.Context, granteeID, accessID uint64) (*models.EmergencyAccess, error)
+
+	// RejectAccess отклоняет запрос экстренного доступа или отзывает предоставленный доступ.
+	RejectAccess(ctx context.Context, grantorID, accessID uint64) (*models.EmergencyAccess, error)
+
+	// DeleteAccess удаляет экстренный доступ по запросу владельца или доверенного пользователя.
+	DeleteAccess(ctx context.Context, userID, accessID uint64) (*models.EmergencyAccess, error)
+
+	// GrantExpired предоставляет запрошенные доступы, срок ожидания которых истёк.
+	GrantExpired(ctx context.Context) ([]*models.EmergencyAccess, error)
+}
+
+// EmergencyService предоставляет методы для управления экстренным доступом к хранилищам.
+type EmergencyService struct {
+	emergencyRepository repository.IEmergencyRepository // emergencyRepository является репозиторием для доступа к экстренным доступам в базе данных.
+	userRepository      repository.IUserRepository      // userRepository находит доверенных пользователей по логину.
+}
+
+// NewEmergencyService создаёт новый экземпляр EmergencyService.
+// Принимает в качестве аргументов репозитории экстренных доступов и пользователей и возвращает ссылку на сервис.
+func NewEmergencyService(emergencyRepository repository.IEmergencyRepository, userRepository repository.IUserRepository) IEmergencyService {
+	return &EmergencyService{
+		emergencyRepository: emergencyRepository,
+		userRepository:      userRepository,
+	}
+}
+
+// AddContact назначает пользователя access.GranteeLogin доверенным для хранилища пользователя grantorID
+// со сроком ожидания access.WaitDays и ключом хранилища access.VaultKey, зашифрованным открытым ключом
+// доверенного пользователя. Повторное назначение заменяет срок ожидания и ключ и отменяет запрос доступа.
+// Возвращает ErrInvalidEmergencyRequest без логина или ключа, с недопустимым сроком ожидания или при
+// назначении самого себя и ErrNotFound, если пользователь не найден.
+func (s *EmergencyService) AddContact(ctx context.Context, grantorID uint64, access *models.EmergencyAccess) (*models.EmergencyAccess, error) {
+	access.GranteeLogin = strings.TrimSpace(access.GranteeLogin)
+	if access.GranteeLogin == "" || len(access.VaultKey) == 0 || access.WaitDays < 1 || access.WaitDays > maxEmergencyWaitDays {
+		return nil, ErrInvalidEmergencyRequest
+	}
+
+	user, err := s.userRepository.GetUserByLogin(ctx, access.GranteeLogin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add emergency contact %q: %w", access.GranteeLogin, err)
+	}
+	if uint64(user.ID) == grantorID {
+		return nil, ErrInvalidEmergencyRequest
+	}
+	access.GrantorID, access.GranteeID = grantorID, uint64(user.ID)
+
+	if err = s.emergencyRepository.Create(ctx, access); err != nil {
+		return nil, fmt.Errorf("failed to add emergency contact: %w", err)
+	}
+	access.VaultKey = nil
+	return access, nil
+}
+
+// ListContacts возвращает доверенных пользователей владельца хранилища grantorID и состояние их доступа.
+func (s *EmergencyService) ListContacts(ctx context.Context, grantorID uint64) ([]*models.EmergencyAccess, error) {
+	accesses, err := s.emergencyRepository.ListByGrantor(ctx, grantorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list emergency contacts: %w", err)
+	}
+	return accesses, nil
+}
+
+// ListGrants возвращает владельцев хранилищ, назначивших пользователя granteeID доверенным. Ключ хранилища
+// владельца возвращается только после предоставления доступа.
+func (s *EmergencyService) ListGrants(ctx context.Context, granteeID uint64) ([]*models.EmergencyAccess, error) {
+	accesses, err := s.emergencyRepository.ListByGrantee(ctx, granteeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list emergency grants: %w", err)
+	}
+	return accesses, nil
+}
+
+// RequestAccess запрашивает экстренный доступ accessID от имени доверенного пользователя granteeID.
+// Доступ будет предоставлен по истечении срока ожидания, если владелец не отклонит запрос.
+// Возвращает ErrNotFound, если доступ не найден или назначен другому пользователю,
+// и ErrEmergencyState, если доступ уже запрошен или предоставлен.
+func (s *EmergencyService) RequestAccess(ctx context.Context, granteeID, accessID uint64) (*models.EmergencyAccess, error) {
+	access, err := s.participantAccess(ctx, accessID, func(a *models.EmergencyAccess) bool { return a.GranteeID == granteeID })
+	if err != nil {
+		return nil, fmt.Errorf("failed to request emergency access: %w", err)
+	}
+	if access.Status != models.EmergencyIdle {
+		return nil, fmt.Errorf("failed to request emergency access: %w", gophKeeperErrors.ErrEmergencyState)
+	}
+
+	requestedAt, err := s.emergencyRepository.Request(ctx, accessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request emergency access: %w", err)
+	}
+	access.Status, access.RequestedAt = models.EmergencyRequested, &requestedAt
+	return access, nil
+}
+
+// RejectAccess отклоняет запрос экстренного доступа accessID или отзывает предоставленный доступ по запросу
+// владельца хранилища grantorID. Доверенный пользователь остаётся назначенным и может запросить доступ снова.
+// Возвращает ErrNotFound, если доступ не найден или принадлежит другому владельцу,
+// и ErrEmergencyState, если доступ не запрашивался.
+func (s *EmergencyService) RejectAccess(ctx context.Context, grantorID, accessID uint64) (*models.EmergencyAccess, error) {
+	access, err := s.participantAccess(ctx, accessID, func(a *models.EmergencyAccess) bool { return a.GrantorID == grantorID })
+	if err != nil {
+		return nil, fmt.Errorf("failed to reject emergency access: %w", err)
+	}
+	if access.Status == models.EmergencyIdle {
+		return nil, fmt.Errorf("failed to reject emergency access: %w", gophKeeperErrors.ErrEmergencyState)
+	}
+
+	if err = s.emergencyRepository.Reset(ctx, accessID); err != nil {
+		return nil, fmt.Errorf("failed to reject emergency access: %w", err)
+	}
+	access.Status, access.RequestedAt = models.EmergencyIdle, nil
+	return access, nil
+}
+
+// DeleteAccess удаляет экстренный доступ accessID. Удалить доступ может как владелец хранилища,
+// так и доверенный пользователь, отказавшийся от назначения.
+// Возвращает ErrNotFound, если доступ не найден или пользователь userID в нём не участвует.
+func (s *EmergencyService) DeleteAccess(ctx context.Context, userID, accessID uint64) (*models.EmergencyAccess, error) {
+	access, err := s.participantAccess(ctx, accessID, func(a *models.EmergencyAccess) bool {
+		return a.GrantorID == userID || a.GranteeID == userID
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete emergency access: %w", err)
+	}
+
+	if err = s.emergencyRepository.Delete(ctx, accessID); err != nil {
+		return nil, fmt.Errorf("failed to delete emergency access: %w", err)
+	}
+	return access, nil
+}
+
+// GrantExpired предоставляет все запрошенные экстренные доступы, срок ожидания которых истёк, и возвращает
+// их, чтобы уведомить владельцев и доверенных пользователей.
+func (s *EmergencyService) GrantExpired(ctx context.Context) ([]*models.EmergencyAccess, error) {
+	accesses, err := s.emergencyRepository.GrantExpired(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to grant expired emergency access: %w", err)
+	}
+	return accesses, nil
+}
+
+// participantAccess возвращает экстренный доступ accessID без ключа хранилища, если пользователь запроса
+// участвует в нём в нужной роли, что проверяет функция allowed. Чужой доступ не раскрывается:
+// для него, как и для несуществующего, возвращается ErrNotFound.
+func (s *EmergencyService) participantAccess(ctx context.Context, accessID uint64, allowed func(a *models.EmergencyAccess) bool) (*models.EmergencyAccess, error) {
+	access, err := s.emergencyRepository.Get(ctx, accessID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed(access) {
+		return nil, fmt.Errorf("emergency access %w (id=%d)", gophKeeperErrors.ErrNotFound, accessID)
+	}
+	access.VaultKey = nil
+	return access, nil
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	serverModels "beliaev-aa/GophKeeper/internal/server/models"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestEmergencyService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEmergencyRepo := mocks.NewMockIEmergencyRepository(ctrl)
+	mockUserRepo := mocks.NewMockIUserRepository(ctrl)
+	service := NewEmergencyService(mockEmergencyRepo, mockUserRepo)
+
+	ctx := context.Background()
+	stored := func(status models.EmergencyStatus) {
+		mockEmergencyRepo.EXPECT().Get(ctx, uint64(5)).Return(&models.EmergencyAccess{
+			ID: 5, GrantorID: 1, GranteeID: 2, WaitDays: 7, Status: status, VaultKey: []byte("wrapped"),
+		}, nil)
+	}
+
+	tests := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "AddContact_Success",
+			testFunc: func(t *testing.T) {
+				mockUserRepo.EXPECT().GetUserByLogin(ctx, "bob").Return(&serverModels.User{ID: 2, Login: "bob"}, nil)
+				mockEmergencyRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, access *models.EmergencyAccess) error {
+					if access.GrantorID != 1 || access.GranteeID != 2 || access.WaitDays != 7 || string(access.VaultKey) != "wrapped" {
+						t.Errorf("Unexpected access to create: %+v", access)
+					}
+					access.ID, access.Status = 5, models.EmergencyIdle
+					return nil
+				})
+
+				access, err := service.AddContact(ctx, 1, &models.EmergencyAccess{GranteeLogin: " bob ", WaitDays: 7, VaultKey: []byte("wrapped")})
+				if err != nil || access.ID != 5 || access.VaultKey != nil {
+					t.Errorf("Expected access 5 without vault key, got %+v, err = %v", access, err)
+				}
+			},
+		},
+		{
+			name: "AddContact_Fail_WaitDays",
+			testFunc: func(t *testing.T) {
+				_, err := service.AddContact(ctx, 1, &models.EmergencyAccess{GranteeLogin: "bob", WaitDays: 0, VaultKey: []byte("wrapped")})
+				if !errors.Is(err, ErrInvalidEmergencyRequest) {
+					t.Errorf("Expected error 'ErrInvalidEmergencyRequest', got %v", err)
+				}
+			},
+		},
+		{
+			name: "AddContact_Fail_Self",
+			testFunc: func(t *testing.T) {
+				mockUserRepo.EXPECT().GetUserByLogin(ctx, "alice").Return(&serverModels.User{ID: 1, Login: "alice"}, nil)
+
+				_, err := service.AddContact(ctx, 1, &models.EmergencyAccess{GranteeLogin: "alice", WaitDays: 7, VaultKey: []byte("wrapped")})
+				if !errors.Is(err, ErrInvalidEmergencyRequest) {
+					t.Errorf("Expected error 'ErrInvalidEmergencyRequest', got %v", err)
+				}
+			},
+		},
+		{
+			name: "AddContact_Fail_UnknownUser",
+			testFunc: func(t *testing.T) {
+				mockUserRepo.EXPECT().GetUserByLogin(ctx, "ghost").Return(nil, gophKeeperErrors.ErrNotFound)
+
+				_, err := service.AddContact(ctx, 1, &models.EmergencyAccess{GranteeLogin: "ghost", WaitDays: 7, VaultKey: []byte("wrapped")})
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+		{
+			name: "ListGrants_Fail",
+			testFunc: func(t *testing.T) {
+				mockEmergencyRepo.EXPECT().ListByGrantee(ctx, uint64(2)).Return(nil, errors.New("db down"))
+
+				if _, err := service.ListGrants(ctx, 2); err == nil {
+					t.Error("Expected error, got nil")
+				}
+			},
+		},
+		{
+			name: "RequestAccess_Success",
+			testFunc: func(t *testing.T) {
+				requestedAt := time.Now()
+				stored(models.EmergencyIdle)
+				mockEmergencyRepo.EXPECT().Request(ctx, uint64(5)).Return(requestedAt, nil)
+
+				access, err := service.RequestAccess(ctx, 2, 5)
+				if err != nil || access.Status != models.EmergencyRequested || !access.RequestedAt.Equal(requestedAt) || access.VaultKey != nil {
+					t.Errorf("Expected requested access without vault key, got %+v, err = %v", access, err)
+				}
+			},
+		},
+		{
+			name: "RequestAccess_Fail_Grantor",
+			testFunc: func(t *testing.T) {
+				stored(models.EmergencyIdle)
+
+				_, err := service.RequestAccess(ctx, 1, 5)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+		{
+			name: "RequestAccess_Fail_AlreadyRequested",
+			testFunc: func(t *testing.T) {
+				stored(models.EmergencyRequested)
+
+				_, err := service.RequestAccess(ctx, 2, 5)
+				if !errors.Is(err, gophKeeperErrors.ErrEmergencyState) {
+					t.Errorf("Expected error 'ErrEmergencyState', got %v", err)
+				}
+			},
+		},
+		{
+			name: "RejectAccess_Success_Granted",
+			testFunc: func(t *testing.T) {
+				stored(models.EmergencyGranted)
+				mockEmergencyRepo.EXPECT().Reset(ctx, uint64(5)).Return(nil)
+
+				access, err := service.RejectAccess(ctx, 1, 5)
+				if err != nil || access.Status != models.EmergencyIdle || access.RequestedAt != nil {
+					t.Errorf("Expected idle access, got %+v, err = %v", access, err)
+				}
+			},
+		},
+		{
+			name: "RejectAccess_Fail_Grantee",
+			testFunc: func(t *testing.T) {
+				stored(models.EmergencyRequested)
+
+				_, err := service.RejectAccess(ctx, 2, 5)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+		{
+			name: "RejectAccess_Fail_Idle",
+			testFunc: func(t *testing.T) {
+				stored(models.EmergencyIdle)
+
+				_, err := service.RejectAccess(ctx, 1, 5)
+				if !errors.Is(err, gophKeeperErrors.ErrEmergencyState) {
+					t.Errorf("Expected error 'ErrEmergencyState', got %v", err)
+				}
+			},
+		},
+		{
+			name: "DeleteAccess_Success_Grantee",
+			testFunc: func(t *testing.T) {
+				stored(models.EmergencyGranted)
+				mockEmergencyRepo.EXPECT().Delete(ctx, uint64(5)).Return(nil)
+
+				access, err := service.DeleteAccess(ctx, 2, 5)
+				if err != nil || access.GrantorID != 1 {
+					t.Errorf("Expected deleted access of grantor 1, got %+v, err = %v", access, err)
+				}
+			},
+		},
+		{
+			name: "DeleteAccess_Fail_Stranger",
+			testFunc: func(t *testing.T) {
+				stored(models.EmergencyIdle)
+
+				_, err := service.DeleteAccess(ctx, 3, 5)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+		{
+			name: "GrantExpired_Success",
+			testFunc: func(t *testing.T) {
+				mockEmergencyRepo.EXPECT().GrantExpired(ctx).Return([]*models.EmergencyAccess{{ID: 5, GrantorID: 1, GranteeID: 2}}, nil)
+
+				accesses, err := service.GrantExpired(ctx)
+				if err != nil || len(accesses) != 1 {
+					t.Errorf("Expected one granted access, got %v, err = %v", accesses, err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
@@ -0,0 +1,171 @@
+// Package service предоставляет бизнес-логику для управления папками и метками секретов.
+package service
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/storage/repository"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLabelNameLength - наибольшая длина названия папки или метки в символах.
+const maxLabelNameLength = 255
+
+// ErrInvalidLabelName определяет ошибку, возникающую при попытке задать папке или метке пустое или слишком длинное название.
+var ErrInvalidLabelName = errors.New("invalid folder or tag name")
+
+// IFolderService определяет интерфейс для сервиса папок и меток секретов.
+type IFolderService interface {
+	// ListFolders возвращает все папки пользователя.
+	ListFolders(ctx context.Context, userID uint64) ([]*models.Folder, error)
+
+	// CreateFolder создаёт папку пользователя и возвращает её.
+	CreateFolder(ctx context.Context, folder *models.Folder) (*models.Folder, error)
+
+	// UpdateFolder переименовывает папку или перемещает её в другую родительскую папку.
+	UpdateFolder(ctx context.Context, folder *models.Folder) error
+
+	// DeleteFolder удаляет папку вместе с вложенными папками.
+	DeleteFolder(ctx context.Context, userID, folderID uint64) error
+
+	// ListTags возвращает все метки пользователя.
+	ListTags(ctx context.Context, userID uint64) ([]*models.Tag, error)
+
+	// CreateTag создаёт метку пользователя и возвращает её.
+	CreateTag(ctx context.Context, tag *models.Tag) (*models.Tag, error)
+
+	// UpdateTag переименовывает метку.
+	UpdateTag(ctx context.Context, tag *models.Tag) error
+
+	// DeleteTag удаляет метку и снимает её с секретов.
+	DeleteTag(ctx context.Context, userID, tagID uint64) error
+}
+
+// FolderService предоставляет методы для управления папками и метками секретов.
+type FolderService struct {
+	folderRepository repository.IFolderRepository // folderRepository является репозиторием для доступа к папкам и меткам в базе данных.
+}
+
+// NewFolderService создаёт новый экземпляр FolderService.
+// Принимает в качестве аргумента репозиторий папок и меток и возвращает ссылку на сервис.
+func NewFolderService(folderRepository repository.IFolderRepository) IFolderService {
+	return &FolderService{
+		folderRepository: folderRepository,
+	}
+}
+
+// ListFolders возвращает все папки пользователя, упорядоченные по названию.
+func (s *FolderService) ListFolders(ctx context.Context, userID uint64) ([]*models.Folder, error) {
+	folders, err := s.folderRepository.ListFolders(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list folders: %w", err)
+	}
+	return folders, nil
+}
+
+// CreateFolder создаёт папку пользователя и возвращает её с присвоенным идентификатором.
+// Возвращает ErrInvalidLabelName, если название пустое или слишком длинное,
+// ErrNotFound, если родительская папка не найдена, и ErrAlreadyExists, если название занято.
+func (s *FolderService) CreateFolder(ctx context.Context, folder *models.Folder) (*models.Folder, error) {
+	name, err := labelName(folder.Name)
+	if err != nil {
+		return nil, err
+	}
+	folder.Name = name
+
+	id, err := s.folderRepository.CreateFolder(ctx, folder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create folder: %w", err)
+	}
+	folder.ID = id
+	return folder, nil
+}
+
+// UpdateFolder переименовывает папку пользователя или перемещает её в другую родительскую папку.
+// Возвращает ErrInvalidLabelName, если название пустое или слишком длинное, ErrNotFound, если папка
+// или родительская папка не найдены, ErrInvalidFolder при перемещении папки в саму себя или во вложенную
+// в неё папку и ErrAlreadyExists, если название занято.
+func (s *FolderService) UpdateFolder(ctx context.Context, folder *models.Folder) error {
+	name, err := labelName(folder.Name)
+	if err != nil {
+		return err
+	}
+	folder.Name = name
+
+	if err = s.folderRepository.UpdateFolder(ctx, folder); err != nil {
+		return fmt.Errorf("failed to update folder: %w", err)
+	}
+	return nil
+}
+
+// DeleteFolder удаляет папку пользователя вместе с вложенными папками; секреты из них остаются без папки.
+// Возвращает ErrNotFound, если папка не найдена.
+func (s *FolderService) DeleteFolder(ctx context.Context, userID, folderID uint64) error {
+	if err := s.folderRepository.DeleteFolder(ctx, userID, folderID); err != nil {
+		return fmt.Errorf("failed to delete folder: %w", err)
+	}
+	return nil
+}
+
+// ListTags возвращает все метки пользователя, упорядоченные по названию.
+func (s *FolderService) ListTags(ctx context.Context, userID uint64) ([]*models.Tag, error) {
+	tags, err := s.folderRepository.ListTags(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	return tags, nil
+}
+
+// CreateTag создаёт метку пользователя и возвращает её с присвоенным идентификатором.
+// Возвращает ErrInvalidLabelName, если название пустое или слишком длинное, и ErrAlreadyExists, если название занято.
+func (s *FolderService) CreateTag(ctx context.Context, tag *models.Tag) (*models.Tag, error) {
+	name, err := labelName(tag.Name)
+	if err != nil {
+		return nil, err
+	}
+	tag.Name = name
+
+	id, err := s.folderRepository.CreateTag(ctx, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+	tag.ID = id
+	return tag, nil
+}
+
+// UpdateTag переименовывает метку пользователя.
+// Возвращает ErrInvalidLabelName, если название пустое или слишком длинное, ErrNotFound, если метка не найдена,
+// и ErrAlreadyExists, если название занято.
+func (s *FolderService) UpdateTag(ctx context.Context, tag *models.Tag) error {
+	name, err := labelName(tag.Name)
+	if err != nil {
+		return err
+	}
+	tag.Name = name
+
+	if err = s.folderRepository.UpdateTag(ctx, tag); err != nil {
+		return fmt.Errorf("failed to update tag: %w", err)
+	}
+	return nil
+}
+
+// DeleteTag удаляет метку пользователя и снимает её с секретов.
+// Возвращает ErrNotFound, если метка не найдена.
+func (s *FolderService) DeleteTag(ctx context.Context, userID, tagID uint64) error {
+	if err := s.folderRepository.DeleteTag(ctx, userID, tagID); err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	return nil
+}
+
+// labelName проверяет название папки или метки и возвращает его без пробелов по краям.
+func labelName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxLabelNameLength {
+		return "", ErrInvalidLabelName
+	}
+	return name, nil
+}
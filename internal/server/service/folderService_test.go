@@ -0,0 +1,141 @@
+package service
+
+import (
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"strings"
+	"testing"
+)
+
+func TestFolderService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIFolderRepository(ctrl)
+	service := NewFolderService(mockRepo)
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "ListFolders_Success",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().ListFolders(ctx, uint64(1)).Return([]*models.Folder{{ID: 1, Name: "Banks"}}, nil)
+
+				folders, err := service.ListFolders(ctx, 1)
+				if err != nil || len(folders) != 1 {
+					t.Errorf("Expected 1 folder, got %v, %v", folders, err)
+				}
+			},
+		},
+		{
+			name: "CreateFolder_Success_TrimsName",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().CreateFolder(ctx, &models.Folder{UserID: 1, ParentID: 2, Name: "Cards"}).Return(uint64(3), nil)
+
+				folder, err := service.CreateFolder(ctx, &models.Folder{UserID: 1, ParentID: 2, Name: "  Cards "})
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if folder.ID != 3 || folder.Name != "Cards" {
+					t.Errorf("Expected folder 3 named 'Cards', got %+v", folder)
+				}
+			},
+		},
+		{
+			name: "CreateFolder_Fail_EmptyName",
+			testFunc: func(t *testing.T) {
+				_, err := service.CreateFolder(ctx, &models.Folder{UserID: 1, Name: "   "})
+				if !errors.Is(err, ErrInvalidLabelName) {
+					t.Errorf("Expected error 'ErrInvalidLabelName', got %v", err)
+				}
+			},
+		},
+		{
+			name: "CreateFolder_Fail_Duplicate",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().CreateFolder(ctx, gomock.Any()).Return(uint64(0), gophKeeperErrors.ErrAlreadyExists)
+
+				_, err := service.CreateFolder(ctx, &models.Folder{UserID: 1, Name: "Banks"})
+				if !errors.Is(err, gophKeeperErrors.ErrAlreadyExists) {
+					t.Errorf("Expected error 'ErrAlreadyExists', got %v", err)
+				}
+			},
+		},
+		{
+			name: "UpdateFolder_Fail_Cycle",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().UpdateFolder(ctx, gomock.Any()).Return(gophKeeperErrors.ErrInvalidFolder)
+
+				err := service.UpdateFolder(ctx, &models.Folder{ID: 1, UserID: 1, ParentID: 2, Name: "Banks"})
+				if !errors.Is(err, gophKeeperErrors.ErrInvalidFolder) {
+					t.Errorf("Expected error 'ErrInvalidFolder', got %v", err)
+				}
+			},
+		},
+		{
+			name: "DeleteFolder_Fail_NotFound",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().DeleteFolder(ctx, uint64(1), uint64(5)).Return(fmt.Errorf("folder %w", gophKeeperErrors.ErrNotFound))
+
+				err := service.DeleteFolder(ctx, 1, 5)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+		{
+			name: "ListTags_Fail",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().ListTags(ctx, uint64(1)).Return(nil, errors.New("some error"))
+
+				_, err := service.ListTags(ctx, 1)
+				if err == nil || err.Error() != "failed to list tags: some error" {
+					t.Errorf("Expected wrapped error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "CreateTag_Success",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().CreateTag(ctx, &models.Tag{UserID: 1, Name: "work"}).Return(uint64(4), nil)
+
+				tag, err := service.CreateTag(ctx, &models.Tag{UserID: 1, Name: "work"})
+				if err != nil || tag.ID != 4 {
+					t.Errorf("Expected tag 4, got %+v, %v", tag, err)
+				}
+			},
+		},
+		{
+			name: "UpdateTag_Fail_LongName",
+			testFunc: func(t *testing.T) {
+				err := service.UpdateTag(ctx, &models.Tag{ID: 4, UserID: 1, Name: strings.Repeat("x", maxLabelNameLength+1)})
+				if !errors.Is(err, ErrInvalidLabelName) {
+					t.Errorf("Expected error 'ErrInvalidLabelName', got %v", err)
+				}
+			},
+		},
+		{
+			name: "DeleteTag_Success",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().DeleteTag(ctx, uint64(1), uint64(4)).Return(nil)
+
+				if err := service.DeleteTag(ctx, 1, 4); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, tt.testFunc)
+	}
+}
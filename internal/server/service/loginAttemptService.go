@@ -0,0 +1,147 @@
+// Package service предоставляет бизнес-логику защиты входа от перебора паролей.
+package service
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/config"
+	"beliaev-aa/GophKeeper/internal/server/models"
+	"beliaev-aa/GophKeeper/internal/server/storage/repository"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// loginFailureWindow определяет, за какой период учитываются неудачные попытки входа под логином.
+	loginFailureWindow = 24 * time.Hour
+	// peerFailureWindow определяет, за какой период учитываются неудачные попытки входа с адреса клиента.
+	peerFailureWindow = time.Hour
+	// peerAttemptsFactor определяет, во сколько раз больше неудачных попыток допускается с одного адреса,
+	// чем для одного логина: за одним адресом могут находиться несколько пользователей.
+	peerAttemptsFactor = 4
+	// lockoutBaseDelay определяет задержку после первой попытки сверх допустимого количества.
+	// Каждая следующая неудачная попытка удваивает задержку вплоть до config.LoginLockout.
+	lockoutBaseDelay = time.Second
+)
+
+// ErrTooManyAttempts определяет ошибку, возникающую при превышении количества неудачных попыток входа.
+var ErrTooManyAttempts = errors.New("too many failed login attempts")
+
+// LockoutError сообщает, что вход временно заблокирован, и через какое время его можно повторить.
+type LockoutError struct {
+	// RetryAfter - время до окончания блокировки.
+	RetryAfter time.Duration
+}
+
+// Error возвращает текстовое описание блокировки.
+func (e *LockoutError) Error() string {
+	return fmt.Sprintf("%s, retry in %s", ErrTooManyAttempts, e.RetryAfter.Round(time.Second))
+}
+
+// Unwrap позволяет сравнивать LockoutError с ErrTooManyAttempts через errors.Is.
+func (e *LockoutError) Unwrap() error {
+	return ErrTooManyAttempts
+}
+
+// ILoginAttemptService определяет интерфейс для сервиса защиты входа от перебора.
+type ILoginAttemptService interface {
+	// Reserve проверяет, разрешена ли попытка входа под логином с адреса клиента, и сохраняет её как неудачную.
+	Reserve(ctx context.Context, login, peerAddress string) (uint64, error)
+
+	// RecordSuccess отмечает попытку входа успешной и сбрасывает счётчик неудачных попыток логина.
+	RecordSuccess(ctx context.Context, attemptID uint64) error
+
+	// Release удаляет попытку входа, завершившуюся без проверки учётных данных.
+	Release(ctx context.Context, attemptID uint64) error
+
+	// PurgeExpired удаляет попытки входа, которые больше не учитываются блокировками.
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+// LoginAttemptService ограничивает частоту попыток входа для логина и адреса клиента
+// экспоненциально растущей задержкой и ведёт журнал попыток входа.
+type LoginAttemptService struct {
+	attemptRepository repository.ILoginAttemptRepository // attemptRepository представляет журнал попыток входа.
+	config            *config.Config                     // config содержит допустимое количество попыток и максимальную блокировку.
+}
+
+// NewLoginAttemptService создаёт новый экземпляр LoginAttemptService.
+func NewLoginAttemptService(attemptRepository repository.ILoginAttemptRepository, config *config.Config) ILoginAttemptService {
+	return &LoginAttemptService{attemptRepository: attemptRepository, config: config}
+}
+
+// Reserve проверяет неудачные попытки входа под логином и с адреса клиента и, если блокировка не действует,
+// сохраняет новую попытку как неудачную. Проверка и сохранение выполняются атомарно, поэтому параллельные
+// попытки учитываются до сравнения учётных данных и не обходят блокировку. Попытку нужно отметить
+// RecordSuccess после успешного входа или освободить Release, если учётные данные не проверялись.
+// Возвращает *LockoutError, если одна из блокировок ещё действует: заблокированные попытки
+// не расходуют вычисление bcrypt.
+func (s *LoginAttemptService) Reserve(ctx context.Context, login, peerAddress string) (uint64, error) {
+	now := time.Now()
+
+	attemptID, err := s.attemptRepository.Reserve(ctx, login, peerAddress, now.Add(-loginFailureWindow), now.Add(-peerFailureWindow),
+		func(loginStats, peerStats *models.FailureStats) error {
+			retryAfter := max(
+				s.lockedFor(loginStats, s.config.LoginMaxAttempts, now),
+				s.lockedFor(peerStats, s.config.LoginMaxAttempts*peerAttemptsFactor, now),
+			)
+			if retryAfter > 0 {
+				return &LockoutError{RetryAfter: retryAfter}
+			}
+			return nil
+		})
+	if err != nil {
+		var lockout *LockoutError
+		if errors.As(err, &lockout) {
+			return 0, lockout
+		}
+		return 0, fmt.Errorf("failed to reserve login attempt: %w", err)
+	}
+	return attemptID, nil
+}
+
+// RecordSuccess отмечает попытку входа успешной.
+func (s *LoginAttemptService) RecordSuccess(ctx context.Context, attemptID uint64) error {
+	if err := s.attemptRepository.MarkSuccess(ctx, attemptID); err != nil {
+		return fmt.Errorf("failed to record login attempt: %w", err)
+	}
+	return nil
+}
+
+// Release удаляет попытку входа, не дошедшую до сравнения учётных данных
+// или прерванную внутренней ошибкой: такие попытки не должны приближать блокировку.
+func (s *LoginAttemptService) Release(ctx context.Context, attemptID uint64) error {
+	if err := s.attemptRepository.Delete(ctx, attemptID); err != nil {
+		return fmt.Errorf("failed to release login attempt: %w", err)
+	}
+	return nil
+}
+
+// PurgeExpired удаляет попытки входа старше loginFailureWindow - самого длинного периода,
+// за который учитываются неудачные попытки. Возвращает количество удалённых попыток.
+func (s *LoginAttemptService) PurgeExpired(ctx context.Context) (int64, error) {
+	count, err := s.attemptRepository.DeleteBefore(ctx, time.Now().Add(-loginFailureWindow))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge login attempts: %w", err)
+	}
+	return count, nil
+}
+
+// lockedFor возвращает, сколько ещё действует блокировка после неудачных попыток stats,
+// если допускается maxAttempts неудачных попыток без задержки.
+func (s *LoginAttemptService) lockedFor(stats *models.FailureStats, maxAttempts int, now time.Time) time.Duration {
+	if stats.Count < maxAttempts || stats.LastAt == nil {
+		return 0
+	}
+	return stats.LastAt.Add(lockoutDelay(stats.Count-maxAttempts, s.config.LoginLockout)).Sub(now)
+}
+
+// lockoutDelay возвращает задержку после excess попыток сверх допустимого количества:
+// lockoutBaseDelay, удвоенную excess раз, но не больше limit.
+func lockoutDelay(excess int, limit time.Duration) time.Duration {
+	delay := lockoutBaseDelay
+	for i := 0; i < excess && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/config"
+	"beliaev-aa/GophKeeper/internal/server/models"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLoginAttemptService_Reserve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockILoginAttemptRepository(ctrl)
+	svc := NewLoginAttemptService(mockRepo, &config.Config{LoginMaxAttempts: 3, LoginLockout: time.Minute})
+	ctx := context.Background()
+
+	stats := func(count int, ago time.Duration) *models.FailureStats {
+		lastAt := time.Now().Add(-ago)
+		return &models.FailureStats{Count: count, LastAt: &lastAt}
+	}
+
+	tests := []struct {
+		name        string
+		loginStats  *models.FailureStats
+		peerStats   *models.FailureStats
+		repoErr     error
+		expectRetry time.Duration
+		expectErr   string
+	}{
+		{
+			name:       "No_Failures",
+			loginStats: &models.FailureStats{},
+			peerStats:  &models.FailureStats{},
+		},
+		{
+			name:       "Below_Limit",
+			loginStats: stats(2, 0),
+			peerStats:  stats(2, 0),
+		},
+		{
+			name:        "Login_Locked_Exponential",
+			loginStats:  stats(6, 0),
+			peerStats:   stats(6, 0),
+			expectRetry: 8 * time.Second,
+		},
+		{
+			name:        "Login_Locked_Capped",
+			loginStats:  stats(50, 0),
+			peerStats:   stats(50, 0),
+			expectRetry: time.Minute,
+		},
+		{
+			name:       "Lockout_Expired",
+			loginStats: stats(6, 9*time.Second),
+			peerStats:  stats(6, 9*time.Second),
+		},
+		{
+			name:        "Peer_Locked",
+			loginStats:  &models.FailureStats{},
+			peerStats:   stats(12, 0),
+			expectRetry: time.Second,
+		},
+		{
+			name:      "Repository_Error",
+			repoErr:   errors.New("db error"),
+			expectErr: "failed to reserve login attempt: db error",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo.EXPECT().Reserve(ctx, "alice", "10.0.0.1", gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _, _ string, _, _ time.Time, check func(loginStats, peerStats *models.FailureStats) error) (uint64, error) {
+					if tc.repoErr != nil {
+						return 0, tc.repoErr
+					}
+					if err := check(tc.loginStats, tc.peerStats); err != nil {
+						return 0, err
+					}
+					return 7, nil
+				}).Times(1)
+
+			attemptID, err := svc.Reserve(ctx, "alice", "10.0.0.1")
+
+			switch {
+			case tc.expectErr != "":
+				assert.EqualError(t, err, tc.expectErr)
+			case tc.expectRetry > 0:
+				var lockout *LockoutError
+				if assert.ErrorAs(t, err, &lockout) {
+					assert.ErrorIs(t, err, ErrTooManyAttempts)
+					assert.InDelta(t, tc.expectRetry, lockout.RetryAfter, float64(time.Second))
+				}
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(7), attemptID)
+			}
+		})
+	}
+}
+
+func TestLoginAttemptService_Settle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockILoginAttemptRepository(ctrl)
+	svc := NewLoginAttemptService(mockRepo, &config.Config{LoginMaxAttempts: 3, LoginLockout: time.Minute})
+	ctx := context.Background()
+
+	mockRepo.EXPECT().MarkSuccess(ctx, uint64(7)).Return(nil).Times(1)
+	assert.NoError(t, svc.RecordSuccess(ctx, 7))
+
+	mockRepo.EXPECT().MarkSuccess(ctx, uint64(8)).Return(errors.New("db error")).Times(1)
+	assert.EqualError(t, svc.RecordSuccess(ctx, 8), "failed to record login attempt: db error")
+
+	mockRepo.EXPECT().Delete(ctx, uint64(9)).Return(errors.New("db error")).Times(1)
+	assert.EqualError(t, svc.Release(ctx, 9), "failed to release login attempt: db error")
+}
+
+func TestLoginAttemptService_PurgeExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockILoginAttemptRepository(ctrl)
+	svc := NewLoginAttemptService(mockRepo, &config.Config{LoginMaxAttempts: 3, LoginLockout: time.Minute})
+	ctx := context.Background()
+
+	mockRepo.EXPECT().DeleteBefore(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, before time.Time) (int64, error) {
+		assert.WithinDuration(t, time.Now().Add(-loginFailureWindow), before, time.Minute)
+		return 4, nil
+	}).Times(1)
+	purged, err := svc.PurgeExpired(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), purged)
+
+	mockRepo.EXPECT().DeleteBefore(ctx, gomock.Any()).Return(int64(0), errors.New("db error")).Times(1)
+	_, err = svc.PurgeExpired(ctx)
+	assert.EqualError(t, err, "failed to purge login attempts: db error")
+}
+
+func TestLockoutDelay(t *testing.T) {
+	assert.Equal(t, time.Second, lockoutDelay(0, time.Hour))
+	assert.Equal(t, 4*time.Second, lockoutDelay(2, time.Hour))
+	assert.Equal(t, time.Hour, lockoutDelay(1000, time.Hour))
+}
@@ -0,0 +1,232 @@
+// Package service предоставляет бизнес-логику для управления организациями и их участниками.
+package service
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/storage/repository"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxOrganizationNameLength - наибольшая длина названия организации в символах.
+const maxOrganizationNameLength = 100
+
+// ErrInvalidOrganizationRequest определяет ошибку, возникающую, если клиент не передал название организации,
+// логин участника, известную роль или ключ хранилища организации, зашифрованный для участника.
+var ErrInvalidOrganizationRequest = errors.New("invalid organization request")
+
+// IOrganizationService определяет интерфейс для сервиса управления организациями.
+type IOrganizationService interface {
+	// CreateOrganization создаёт организацию, владельцем которой становится пользователь.
+	CreateOrganization(ctx context.Context, userID uint64, org *models.Organization) (*models.Organization, error)
+
+	// ListOrganizations возвращает организации, в которых состоит пользователь.
+	ListOrganizations(ctx context.Context, userID uint64) ([]*models.Organization, error)
+
+	// ListMembers возвращает участников организации по запросу её участника.
+	ListMembers(ctx context.Context, userID, orgID uint64) ([]*models.OrgMember, error)
+
+	// AddMember добавляет пользователя в организацию или заново выдаёт ему ключ хранилища.
+	AddMember(ctx context.Context, userID uint64, member *models.OrgMember) (*models.OrgMember, error)
+
+	// UpdateMemberRole изменяет роль участника организации.
+	UpdateMemberRole(ctx context.Context, userID, orgID, memberID uint64, role models.OrgRole) error
+
+	// RemoveMember исключает участника из организации или выводит из неё самого пользователя.
+	RemoveMember(ctx context.Context, userID, orgID, memberID uint64) error
+
+	// DeleteOrganization удаляет организацию вместе с её хранилищем.
+	DeleteOrganization(ctx context.Context, userID, orgID uint64) error
+}
+
+// OrganizationService предоставляет методы для управления организациями и их участниками.
+type OrganizationService struct {
+	orgRepository  repository.IOrganizationRepository // orgRepository является репозиторием для доступа к организациям в базе данных.
+	userRepository repository.IUserRepository         // userRepository находит добавляемых участников по логину.
+	blobService    IBlobService                       // blobService удаляет файлы хранилища удаляемой организации.
+}
+
+// NewOrganizationService создаёт новый экземпляр OrganizationService.
+// Принимает в качестве аргументов репозитории организаций и пользователей и сервис бинарных объектов
+// и возвращает ссылку на сервис.
+func NewOrganizationService(orgRepository repository.IOrganizationRepository, userRepository repository.IUserRepository, blobService IBlobService) IOrganizationService {
+	return &OrganizationService{
+		orgRepository:  orgRepository,
+		userRepository: userRepository,
+		blobService:    blobService,
+	}
+}
+
+// CreateOrganization создаёт организацию org.Name с ключом хранилища org.VaultKey, зашифрованным открытым
+// ключом пользователя userID, и делает пользователя её владельцем.
+// Возвращает ErrInvalidOrganizationRequest без названия или ключа хранилища.
+func (s *OrganizationService) CreateOrganization(ctx context.Context, userID uint64, org *models.Organization) (*models.Organization, error) {
+	org.Name = strings.TrimSpace(org.Name)
+	if org.Name == "" || utf8.RuneCountInString(org.Name) > maxOrganizationNameLength || len(org.VaultKey) == 0 {
+		return nil, ErrInvalidOrganizationRequest
+	}
+
+	if err := s.orgRepository.Create(ctx, org, userID); err != nil {
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+	return org, nil
+}
+
+// ListOrganizations возвращает организации пользователя userID вместе с его ролью и ключом хранилища.
+func (s *OrganizationService) ListOrganizations(ctx context.Context, userID uint64) ([]*models.Organization, error) {
+	orgs, err := s.orgRepository.ListForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	return orgs, nil
+}
+
+// ListMembers возвращает участников организации orgID. Список доступен любому её участнику.
+// Возвращает ErrNotFound, если пользователь не состоит в организации.
+func (s *OrganizationService) ListMembers(ctx context.Context, userID, orgID uint64) ([]*models.OrgMember, error) {
+	if _, err := s.orgRepository.GetMembership(ctx, orgID, userID); err != nil {
+		return nil, fmt.Errorf("failed to list organization members: %w", err)
+	}
+
+	members, err := s.orgRepository.ListMembers(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization members: %w", err)
+	}
+	return members, nil
+}
+
+// AddMember добавляет пользователя member.Login в организацию member.OrgID с ролью member.Role и ключом
+// хранилища member.VaultKey, зашифрованным его открытым ключом. Повторное добавление участника заменяет
+// его роль и ключ хранилища. Добавлять участников могут администраторы и владельцы, а выдавать
+// и изменять роли администратора и владельца - только владельцы.
+// Возвращает ErrInvalidOrganizationRequest без логина, ключа или с неизвестной ролью, ErrNotFound, если
+// организация или пользователь не найдены, ErrPermissionDenied, если роли пользователя недостаточно,
+// и ErrLastOwner при понижении последнего владельца.
+func (s *OrganizationService) AddMember(ctx context.Context, userID uint64, member *models.OrgMember) (*models.OrgMember, error) {
+	member.Login = strings.TrimSpace(member.Login)
+	if member.Login == "" || len(member.VaultKey) == 0 || !member.Role.Valid() {
+		return nil, ErrInvalidOrganizationRequest
+	}
+
+	actor, err := s.requireRole(ctx, member.OrgID, userID, models.OrgAdmin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add organization member: %w", err)
+	}
+
+	user, err := s.userRepository.GetUserByLogin(ctx, member.Login)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add organization member %q: %w", member.Login, err)
+	}
+	member.UserID = uint64(user.ID)
+
+	if err = s.checkRoleChange(ctx, actor, member.OrgID, member.UserID, member.Role); err != nil {
+		return nil, fmt.Errorf("failed to add organization member: %w", err)
+	}
+
+	if err = s.orgRepository.AddMember(ctx, member); err != nil {
+		return nil, fmt.Errorf("failed to add organization member: %w", err)
+	}
+	return member, nil
+}
+
+// UpdateMemberRole изменяет роль участника memberID организации orgID на role по тем же правилам, что и AddMember.
+// Возвращает ErrInvalidOrganizationRequest с неизвестной ролью, ErrNotFound, если организация или участник
+// не найдены, ErrPermissionDenied, если роли пользователя недостаточно, и ErrLastOwner при понижении
+// последнего владельца.
+func (s *OrganizationService) UpdateMemberRole(ctx context.Context, userID, orgID, memberID uint64, role models.OrgRole) error {
+	if !role.Valid() {
+		return ErrInvalidOrganizationRequest
+	}
+
+	actor, err := s.requireRole(ctx, orgID, userID, models.OrgAdmin)
+	if err != nil {
+		return fmt.Errorf("failed to update organization member: %w", err)
+	}
+	if err = s.checkRoleChange(ctx, actor, orgID, memberID, role); err != nil {
+		return fmt.Errorf("failed to update organization member: %w", err)
+	}
+
+	if err = s.orgRepository.UpdateMemberRole(ctx, orgID, memberID, role); err != nil {
+		return fmt.Errorf("failed to update organization member: %w", err)
+	}
+	return nil
+}
+
+// RemoveMember исключает участника memberID из организации orgID. Покинуть организацию может любой участник;
+// исключать редакторов и читателей могут администраторы и владельцы, а администраторов и владельцев -
+// только владельцы. Удаляется только зашифрованный для участника ключ хранилища: сам ключ не меняется,
+// поэтому секреты, полученные участником до исключения, остаются доступны ему для расшифровки.
+// Возвращает ErrNotFound, если организация или участник не найдены, ErrPermissionDenied, если роли
+// пользователя недостаточно, и ErrLastOwner при исключении последнего владельца.
+func (s *OrganizationService) RemoveMember(ctx context.Context, userID, orgID, memberID uint64) error {
+	required := models.OrgAdmin
+	if memberID == userID {
+		required = models.OrgViewer
+	}
+	actor, err := s.requireRole(ctx, orgID, userID, required)
+	if err != nil {
+		return fmt.Errorf("failed to remove organization member: %w", err)
+	}
+
+	target, err := s.orgRepository.GetMembership(ctx, orgID, memberID)
+	if err != nil {
+		return fmt.Errorf("failed to remove organization member: %w", err)
+	}
+	if memberID != userID && target.Role.AtLeast(models.OrgAdmin) && actor.Role != models.OrgOwner {
+		return fmt.Errorf("failed to remove organization member: %w", gophKeeperErrors.ErrPermissionDenied)
+	}
+	if err = s.orgRepository.RemoveMember(ctx, orgID, memberID); err != nil {
+		return fmt.Errorf("failed to remove organization member: %w", err)
+	}
+	return nil
+}
+
+// DeleteOrganization удаляет организацию orgID вместе с секретами и файлами её хранилища. Удалить
+// организацию может только владелец.
+// Возвращает ErrNotFound, если пользователь не состоит в организации, и ErrPermissionDenied,
+// если он не является её владельцем.
+func (s *OrganizationService) DeleteOrganization(ctx context.Context, userID, orgID uint64) error {
+	membership, err := s.requireRole(ctx, orgID, userID, models.OrgOwner)
+	if err != nil {
+		return fmt.Errorf("failed to delete organization: %w", err)
+	}
+
+	if err = s.orgRepository.Delete(ctx, orgID); err != nil {
+		return fmt.Errorf("failed to delete organization: %w", err)
+	}
+	return s.blobService.DeleteUserBlobs(ctx, membership.VaultID)
+}
+
+// requireRole возвращает членство пользователя в организации, если его роль не ниже required.
+// Возвращает ErrNotFound, если пользователь не состоит в организации, и ErrPermissionDenied,
+// если его роли недостаточно.
+func (s *OrganizationService) requireRole(ctx context.Context, orgID, userID uint64, required models.OrgRole) (*models.Organization, error) {
+	membership, err := s.orgRepository.GetMembership(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !membership.Role.AtLeast(required) {
+		return nil, gophKeeperErrors.ErrPermissionDenied
+	}
+	return membership, nil
+}
+
+// checkRoleChange проверяет, может ли участник actor назначить пользователю memberID роль role:
+// роли администратора и владельца выдаёт и отзывает только владелец. Понижение последнего владельца
+// атомарно с изменением роли проверяет репозиторий.
+func (s *OrganizationService) checkRoleChange(ctx context.Context, actor *models.Organization, orgID, memberID uint64, role models.OrgRole) error {
+	current, err := s.orgRepository.GetMembership(ctx, orgID, memberID)
+	if err != nil && !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+		return err
+	}
+
+	privileged := role.AtLeast(models.OrgAdmin) || (current != nil && current.Role.AtLeast(models.OrgAdmin))
+	if privileged && actor.Role != models.OrgOwner {
+		return gophKeeperErrors.ErrPermissionDenied
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	serverModels "beliaev-aa/GophKeeper/internal/server/models"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"testing"
+)
+
+func TestOrganizationService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrgRepo := mocks.NewMockIOrganizationRepository(ctrl)
+	mockUserRepo := mocks.NewMockIUserRepository(ctrl)
+	mockBlobService := mocks.NewMockIBlobService(ctrl)
+	service := NewOrganizationService(mockOrgRepo, mockUserRepo, mockBlobService)
+
+	ctx := context.Background()
+	membership := func(userID uint64, role models.OrgRole) {
+		mockOrgRepo.EXPECT().GetMembership(ctx, uint64(7), userID).Return(&models.Organization{ID: 7, VaultID: 100, Role: role}, nil)
+	}
+
+	tests := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "CreateOrganization_Success",
+			testFunc: func(t *testing.T) {
+				mockOrgRepo.EXPECT().Create(ctx, &models.Organization{Name: "Team", VaultKey: []byte("wrapped")}, uint64(1)).
+					DoAndReturn(func(_ context.Context, org *models.Organization, _ uint64) error {
+						org.ID = 7
+						return nil
+					})
+
+				org, err := service.CreateOrganization(ctx, 1, &models.Organization{Name: " Team ", VaultKey: []byte("wrapped")})
+				if err != nil || org.ID != 7 {
+					t.Errorf("Expected organization 7, got %+v, err = %v", org, err)
+				}
+			},
+		},
+		{
+			name: "CreateOrganization_Fail_NoVaultKey",
+			testFunc: func(t *testing.T) {
+				_, err := service.CreateOrganization(ctx, 1, &models.Organization{Name: "Team"})
+				if !errors.Is(err, ErrInvalidOrganizationRequest) {
+					t.Errorf("Expected error 'ErrInvalidOrganizationRequest', got %v", err)
+				}
+			},
+		},
+		{
+			name: "ListMembers_Fail_NotMember",
+			testFunc: func(t *testing.T) {
+				mockOrgRepo.EXPECT().GetMembership(ctx, uint64(7), uint64(5)).Return(nil, gophKeeperErrors.ErrNotFound)
+
+				_, err := service.ListMembers(ctx, 5, 7)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+		{
+			name: "AddMember_Success_AdminAddsEditor",
+			testFunc: func(t *testing.T) {
+				membership(1, models.OrgAdmin)
+				mockUserRepo.EXPECT().GetUserByLogin(ctx, "bob").Return(&serverModels.User{ID: 2, Login: "bob"}, nil)
+				mockOrgRepo.EXPECT().GetMembership(ctx, uint64(7), uint64(2)).Return(nil, gophKeeperErrors.ErrNotFound)
+				mockOrgRepo.EXPECT().AddMember(ctx, gomock.Any()).Return(nil)
+
+				member, err := service.AddMember(ctx, 1, &models.OrgMember{OrgID: 7, Login: "bob", Role: models.OrgEditor, VaultKey: []byte("wrapped")})
+				if err != nil || member.UserID != 2 {
+					t.Errorf("Expected member 2, got %+v, err = %v", member, err)
+				}
+			},
+		},
+		{
+			name: "AddMember_Fail_EditorAdds",
+			testFunc: func(t *testing.T) {
+				membership(1, models.OrgEditor)
+
+				_, err := service.AddMember(ctx, 1, &models.OrgMember{OrgID: 7, Login: "bob", Role: models.OrgViewer, VaultKey: []byte("wrapped")})
+				if !errors.Is(err, gophKeeperErrors.ErrPermissionDenied) {
+					t.Errorf("Expected error 'ErrPermissionDenied', got %v", err)
+				}
+			},
+		},
+		{
+			name: "AddMember_Fail_AdminGrantsAdmin",
+			testFunc: func(t *testing.T) {
+				membership(1, models.OrgAdmin)
+				mockUserRepo.EXPECT().GetUserByLogin(ctx, "bob").Return(&serverModels.User{ID: 2, Login: "bob"}, nil)
+				mockOrgRepo.EXPECT().GetMembership(ctx, uint64(7), uint64(2)).Return(nil, gophKeeperErrors.ErrNotFound)
+
+				_, err := service.AddMember(ctx, 1, &models.OrgMember{OrgID: 7, Login: "bob", Role: models.OrgAdmin, VaultKey: []byte("wrapped")})
+				if !errors.Is(err, gophKeeperErrors.ErrPermissionDenied) {
+					t.Errorf("Expected error 'ErrPermissionDenied', got %v", err)
+				}
+			},
+		},
+		{
+			name: "AddMember_Fail_UnknownRole",
+			testFunc: func(t *testing.T) {
+				_, err := service.AddMember(ctx, 1, &models.OrgMember{OrgID: 7, Login: "bob", Role: "root", VaultKey: []byte("wrapped")})
+				if !errors.Is(err, ErrInvalidOrganizationRequest) {
+					t.Errorf("Expected error 'ErrInvalidOrganizationRequest', got %v", err)
+				}
+			},
+		},
+		{
+			name: "UpdateMemberRole_Success_OwnerPromotes",
+			testFunc: func(t *testing.T) {
+				membership(1, models.OrgOwner)
+				membership(2, models.OrgEditor)
+				mockOrgRepo.EXPECT().UpdateMemberRole(ctx, uint64(7), uint64(2), models.OrgAdmin).Return(nil)
+
+				if err := service.UpdateMemberRole(ctx, 1, 7, 2, models.OrgAdmin); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "UpdateMemberRole_Fail_LastOwner",
+			testFunc: func(t *testing.T) {
+				membership(1, models.OrgOwner)
+				membership(1, models.OrgOwner)
+				mockOrgRepo.EXPECT().UpdateMemberRole(ctx, uint64(7), uint64(1), models.OrgEditor).Return(gophKeeperErrors.ErrLastOwner)
+
+				err := service.UpdateMemberRole(ctx, 1, 7, 1, models.OrgEditor)
+				if !errors.Is(err, gophKeeperErrors.ErrLastOwner) {
+					t.Errorf("Expected error 'ErrLastOwner', got %v", err)
+				}
+			},
+		},
+		{
+			name: "RemoveMember_Success_Leave",
+			testFunc: func(t *testing.T) {
+				membership(3, models.OrgViewer)
+				membership(3, models.OrgViewer)
+				mockOrgRepo.EXPECT().RemoveMember(ctx, uint64(7), uint64(3)).Return(nil)
+
+				if err := service.RemoveMember(ctx, 3, 7, 3); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "RemoveMember_Success_OwnerLeavesWithAnotherOwner",
+			testFunc: func(t *testing.T) {
+				membership(1, models.OrgOwner)
+				membership(1, models.OrgOwner)
+				mockOrgRepo.EXPECT().RemoveMember(ctx, uint64(7), uint64(1)).Return(nil)
+
+				if err := service.RemoveMember(ctx, 1, 7, 1); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "RemoveMember_Fail_LastOwner",
+			testFunc: func(t *testing.T) {
+				membership(1, models.OrgOwner)
+				membership(1, models.OrgOwner)
+				mockOrgRepo.EXPECT().RemoveMember(ctx, uint64(7), uint64(1)).Return(gophKeeperErrors.ErrLastOwner)
+
+				err := service.RemoveMember(ctx, 1, 7, 1)
+				if !errors.Is(err, gophKeeperErrors.ErrLastOwner) {
+					t.Errorf("Expected error 'ErrLastOwner', got %v", err)
+				}
+			},
+		},
+		{
+			name: "RemoveMember_Fail_AdminRemovesOwner",
+			testFunc: func(t *testing.T) {
+				membership(2, models.OrgAdmin)
+				membership(1, models.OrgOwner)
+
+				err := service.RemoveMember(ctx, 2, 7, 1)
+				if !errors.Is(err, gophKeeperErrors.ErrPermissionDenied) {
+					t.Errorf("Expected error 'ErrPermissionDenied', got %v", err)
+				}
+			},
+		},
+		{
+			name: "DeleteOrganization_Success",
+			testFunc: func(t *testing.T) {
+				membership(1, models.OrgOwner)
+				mockOrgRepo.EXPECT().Delete(ctx, uint64(7)).Return(nil)
+				mockBlobService.EXPECT().DeleteUserBlobs(ctx, uint64(100)).Return(nil)
+
+				if err := service.DeleteOrganization(ctx, 1, 7); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "DeleteOrganization_Fail_Admin",
+			testFunc: func(t *testing.T) {
+				membership(2, models.OrgAdmin)
+
+				err := service.DeleteOrganization(ctx, 2, 7)
+				if !errors.Is(err, gophKeeperErrors.ErrPermissionDenied) {
+					t.Errorf("Expected error 'ErrPermissionDenied', got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
@@ -2,40 +2,91 @@
 package service
 
 import (
+	"beliaev-aa/GophKeeper/internal/server/config"
+	serverModels "beliaev-aa/GophKeeper/internal/server/models"
 	"beliaev-aa/GophKeeper/internal/server/storage/repository"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
 	"beliaev-aa/GophKeeper/pkg/models"
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 )
 
+const (
+	// defaultSecretsPageSize - размер страницы списка секретов, если клиент его не задал.
+	defaultSecretsPageSize = 50
+	// maxSecretsPageSize - наибольший размер страницы списка секретов.
+	maxSecretsPageSize = 500
+)
+
+// ErrInvalidPageToken определяет ошибку, возникающую при предъявлении повреждённого токена страницы
+// или токена, выданного для списка с другим порядком секретов.
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// ErrTitleQueryUnsupported определяет ошибку, возникающую при запросе списка секретов с началом заголовка
+// или порядком по заголовку: заголовки на сервере зашифрованы, и сравнение шифротекстов не имеет смысла.
+var ErrTitleQueryUnsupported = errors.New("title prefix and title order are not supported for encrypted titles")
+
+// pageToken описывает содержимое токена страницы списка секретов.
+type pageToken struct {
+	Order  models.SecretOrder        `json:"order"`
+	Cursor serverModels.SecretCursor `json:"cursor"`
+}
+
 // ISecretService интерфейс для сервиса управления секретами в хранилище.
 type ISecretService interface {
 	GetSecret(ctx context.Context, secretID uint64, userID uint64) (*models.Secret, error)
-	GetUserSecrets(ctx context.Context, userID uint64) (models.Secrets, error)
+	GetUserSecrets(ctx context.Context, userID uint64, filter models.SecretFilter) (models.Secrets, error)
+	SyncSecrets(ctx context.Context, userID uint64, sinceRevision uint64) (*models.SecretsDelta, error)
+	ListSecrets(ctx context.Context, userID uint64, query *models.SecretListQuery) (*models.SecretsPage, error)
+	ReserveSecretID(ctx context.Context, userID uint64) (uint64, error)
 	CreateSecret(ctx context.Context, secret *models.Secret) (*models.Secret, error)
 	UpdateSecret(ctx context.Context, secret *models.Secret) (*models.Secret, error)
 	DeleteSecret(ctx context.Context, secretID uint64, userID uint64) error
+	ListSecretVersions(ctx context.Context, secretID uint64, userID uint64) ([]*models.SecretVersion, error)
+	RestoreSecretVersion(ctx context.Context, secretID, versionID, userID uint64) (*models.Secret, error)
+	ListTrash(ctx context.Context, userID uint64) (models.Secrets, error)
+	RestoreSecret(ctx context.Context, secretID uint64, userID uint64) error
+	PurgeSecret(ctx context.Context, secretID uint64, userID uint64) error
+	PurgeExpiredTrash(ctx context.Context) (int64, error)
+	EncryptSecretLabels(ctx context.Context, userID uint64, secrets models.Secrets) error
+	ResolveVault(ctx context.Context, userID, orgID uint64, required models.OrgRole) (uint64, error)
+	VaultMembers(ctx context.Context, orgID uint64) ([]uint64, error)
+	ResolveEmergencyVault(ctx context.Context, userID, accessID uint64) (uint64, error)
 }
 
 // SecretService предоставляет методы для управления секретами в хранилище.
 type SecretService struct {
-	secretRepository repository.ISecretRepository // secretRepository является репозиторием для доступа к секретам в базе данных.
+	secretRepository    repository.ISecretRepository       // secretRepository является репозиторием для доступа к секретам в базе данных.
+	orgRepository       repository.IOrganizationRepository // orgRepository определяет хранилища организаций и роли их участников.
+	emergencyRepository repository.IEmergencyRepository    // emergencyRepository определяет хранилища, к которым пользователю предоставлен экстренный доступ.
+	blobService         IBlobService                       // blobService проверяет и удаляет объекты, на которые ссылаются секреты.
+	config              *config.Config                     // config задаёт лимит хранимых версий секретов и срок хранения корзины.
 }
 
 // NewSecretService создает новый экземпляр SecretService.
-// Принимает в качестве аргумента репозиторий секретов и возвращает ссылку на сервис.
-func NewSecretService(secretRepository repository.ISecretRepository) ISecretService {
-	return &SecretService{secretRepository: secretRepository}
+// Принимает в качестве аргументов репозитории секретов, организаций и экстренных доступов, сервис бинарных
+// объектов и конфигурацию сервера и возвращает ссылку на сервис.
+func NewSecretService(secretRepository repository.ISecretRepository, orgRepository repository.IOrganizationRepository, emergencyRepository repository.IEmergencyRepository, blobService IBlobService, config *config.Config) ISecretService {
+	return &SecretService{
+		secretRepository:    secretRepository,
+		orgRepository:       orgRepository,
+		emergencyRepository: emergencyRepository,
+		blobService:         blobService,
+		config:              config,
+	}
 }
 
 // GetSecret извлекает секрет по его ID и ID пользователя.
 // В случае отсутствия секрета возвращает ошибку.
 func (s *SecretService) GetSecret(ctx context.Context, secretID uint64, userID uint64) (*models.Secret, error) {
 	secret, err := s.secretRepository.GetSecret(ctx, secretID, userID)
-	if errors.Is(err, sql.ErrNoRows) {
-		return nil, fmt.Errorf("secret not found (id=%d)", secretID)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("secret %w (id=%d)", gophKeeperErrors.ErrNotFound, secretID)
 	}
 	if err != nil {
 		return nil, err
@@ -43,10 +94,10 @@ func (s *SecretService) GetSecret(ctx context.Context, secretID uint64, userID u
 	return secret, nil
 }
 
-// GetUserSecrets возвращает список всех секретов пользователя.
+// GetUserSecrets возвращает список секретов пользователя, подходящих под фильтр по папке и метке.
 // Если секреты не найдены, возвращает ошибку.
-func (s *SecretService) GetUserSecrets(ctx context.Context, userID uint64) (models.Secrets, error) {
-	secrets, err := s.secretRepository.GetUserSecrets(ctx, userID)
+func (s *SecretService) GetUserSecrets(ctx context.Context, userID uint64, filter models.SecretFilter) (models.Secrets, error) {
+	secrets, err := s.secretRepository.GetUserSecrets(ctx, userID, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -56,9 +107,78 @@ func (s *SecretService) GetUserSecrets(ctx context.Context, userID uint64) (mode
 	return secrets, nil
 }
 
-// CreateSecret создает новый секрет.
-// Возвращает созданный секрет или ошибку при неудаче.
+// SyncSecrets возвращает изменения секретов пользователя после ревизии хранилища sinceRevision
+// и ревизию, с которой следует запросить следующие изменения.
+func (s *SecretService) SyncSecrets(ctx context.Context, userID uint64, sinceRevision uint64) (*models.SecretsDelta, error) {
+	delta, err := s.secretRepository.Sync(ctx, userID, sinceRevision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sync secrets: %w", err)
+	}
+	return delta, nil
+}
+
+// ListSecrets возвращает страницу заголовков секретов пользователя, подходящих под фильтр запроса,
+// и токен следующей страницы. Возвращает ErrTitleQueryUnsupported, если запрошены начало заголовка
+// или порядок по заголовку, и ErrInvalidPageToken, если токен страницы не подходит к запросу.
+func (s *SecretService) ListSecrets(ctx context.Context, userID uint64, query *models.SecretListQuery) (*models.SecretsPage, error) {
+	if query.TitlePrefix != "" || query.Order == models.SecretOrderTitleAsc || query.Order == models.SecretOrderTitleDesc {
+		return nil, ErrTitleQueryUnsupported
+	}
+
+	pageSize := query.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultSecretsPageSize
+	}
+	if pageSize > maxSecretsPageSize {
+		pageSize = maxSecretsPageSize
+	}
+
+	var after *serverModels.SecretCursor
+	if query.PageToken != "" {
+		token, err := decodePageToken(query.PageToken)
+		if err != nil || token.Order != query.Order {
+			return nil, ErrInvalidPageToken
+		}
+		after = &token.Cursor
+	}
+
+	// Лишний секрет показывает, что за страницей есть продолжение.
+	secrets, err := s.secretRepository.List(ctx, userID, query, after, pageSize+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+
+	page := &models.SecretsPage{Secrets: secrets}
+	if len(secrets) > pageSize {
+		page.Secrets = secrets[:pageSize]
+		last := page.Secrets[pageSize-1]
+		page.NextPageToken = encodePageToken(pageToken{
+			Order:  query.Order,
+			Cursor: serverModels.SecretCursor{ID: last.ID, UpdatedAt: last.UpdatedAt},
+		})
+	}
+	return page, nil
+}
+
+// ReserveSecretID резервирует за пользователем идентификатор нового секрета.
+// Возвращает идентификатор или ошибку при неудаче.
+func (s *SecretService) ReserveSecretID(ctx context.Context, userID uint64) (uint64, error) {
+	id, err := s.secretRepository.ReserveID(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reserve secret ID: %w", err)
+	}
+	return id, nil
+}
+
+// CreateSecret создает новый секрет. Секрет с ненулевым идентификатором создаётся с ним,
+// если идентификатор зарезервирован пользователем через ReserveSecretID.
+// Возвращает созданный секрет, ErrNotFound, если идентификатор не зарезервирован пользователем, ErrInvalidBlob, если секрет ссылается на чужой или незагруженный объект,
+// или ошибку при неудаче.
 func (s *SecretService) CreateSecret(ctx context.Context, secret *models.Secret) (*models.Secret, error) {
+	if err := s.checkBlob(ctx, secret); err != nil {
+		return nil, err
+	}
+
 	var err error
 	secret.ID, err = s.secretRepository.Create(ctx, secret)
 	if err != nil {
@@ -67,12 +187,18 @@ func (s *SecretService) CreateSecret(ctx context.Context, secret *models.Secret)
 	return secret, nil
 }
 
-// UpdateSecret обновляет существующий секрет.
-// Возвращает обновленный секрет или ошибку, если секрет не найден или не удалось сохранить изменения.
+// UpdateSecret обновляет существующий секрет, сохраняя его прежнее состояние в историю версий.
+// Возвращает обновленный секрет с новой ревизией или ошибку, если секрет не найден, был изменён
+// после загрузки (RevisionConflictError), ссылается на чужой или незагруженный объект (ErrInvalidBlob)
+// или не удалось сохранить изменения.
 func (s *SecretService) UpdateSecret(ctx context.Context, secret *models.Secret) (*models.Secret, error) {
-	err := s.secretRepository.Update(ctx, secret)
-	if errors.Is(err, sql.ErrNoRows) {
-		return nil, fmt.Errorf("secret not found (id=%d)", secret.ID)
+	if err := s.checkBlob(ctx, secret); err != nil {
+		return nil, err
+	}
+
+	err := s.secretRepository.Update(ctx, secret, s.config.SecretVersionsLimit)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("secret %w (id=%d)", gophKeeperErrors.ErrNotFound, secret.ID)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to store secret: %w", err)
@@ -80,9 +206,173 @@ func (s *SecretService) UpdateSecret(ctx context.Context, secret *models.Secret)
 	return secret, nil
 }
 
-// DeleteSecret удаляет секрет по его ID и ID пользователя.
-// Возвращает ошибку, если удаление не произошло.
+// DeleteSecret перемещает секрет в корзину по его ID и ID пользователя.
+// Возвращает ошибку, если секрет не найден, принадлежит другому пользователю или удаление не произошло.
 func (s *SecretService) DeleteSecret(ctx context.Context, secretID uint64, userID uint64) error {
 	err := s.secretRepository.Delete(ctx, secretID, userID)
+	if isNotFound(err) {
+		return fmt.Errorf("secret %w (id=%d)", gophKeeperErrors.ErrNotFound, secretID)
+	}
 	return err
 }
+
+// ListSecretVersions возвращает сохранённые версии секрета пользователя от новых к старым.
+// Возвращает ошибку, если секрет не найден или принадлежит другому пользователю.
+func (s *SecretService) ListSecretVersions(ctx context.Context, secretID uint64, userID uint64) ([]*models.SecretVersion, error) {
+	versions, err := s.secretRepository.ListVersions(ctx, secretID, userID)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("secret %w (id=%d)", gophKeeperErrors.ErrNotFound, secretID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secret versions: %w", err)
+	}
+	return versions, nil
+}
+
+// RestoreSecretVersion восстанавливает секрет из сохранённой версии.
+// Возвращает восстановленный секрет или ошибку, если секрет или версия не найдены.
+func (s *SecretService) RestoreSecretVersion(ctx context.Context, secretID, versionID, userID uint64) (*models.Secret, error) {
+	secret, err := s.secretRepository.RestoreVersion(ctx, secretID, versionID, userID, s.config.SecretVersionsLimit)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("secret version %w (id=%d, version=%d)", gophKeeperErrors.ErrNotFound, secretID, versionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore secret version: %w", err)
+	}
+	return secret, nil
+}
+
+// ListTrash возвращает секреты пользователя, находящиеся в корзине.
+func (s *SecretService) ListTrash(ctx context.Context, userID uint64) (models.Secrets, error) {
+	secrets, err := s.secretRepository.ListTrash(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trash: %w", err)
+	}
+	return secrets, nil
+}
+
+// RestoreSecret возвращает секрет из корзины.
+// Возвращает ошибку, если секрета нет в корзине пользователя.
+func (s *SecretService) RestoreSecret(ctx context.Context, secretID uint64, userID uint64) error {
+	err := s.secretRepository.Restore(ctx, secretID, userID)
+	if isNotFound(err) {
+		return fmt.Errorf("trashed secret %w (id=%d)", gophKeeperErrors.ErrNotFound, secretID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restore secret: %w", err)
+	}
+	return nil
+}
+
+// PurgeSecret окончательно удаляет секрет из корзины вместе с объектами, на которые ссылались
+// только секрет и его версии.
+// Возвращает ошибку, если секрета нет в корзине пользователя.
+func (s *SecretService) PurgeSecret(ctx context.Context, secretID uint64, userID uint64) error {
+	blobIDs, err := s.secretRepository.Purge(ctx, secretID, userID)
+	if isNotFound(err) {
+		return fmt.Errorf("trashed secret %w (id=%d)", gophKeeperErrors.ErrNotFound, secretID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to purge secret: %w", err)
+	}
+	return s.blobService.ReleaseBlobs(ctx, userID, blobIDs)
+}
+
+// PurgeExpiredTrash окончательно удаляет секреты, пролежавшие в корзине дольше срока хранения.
+// Возвращает количество удалённых секретов.
+func (s *SecretService) PurgeExpiredTrash(ctx context.Context) (int64, error) {
+	purged, err := s.secretRepository.PurgeExpired(ctx, time.Now().Add(-s.config.TrashRetention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge expired trash: %w", err)
+	}
+	return purged, nil
+}
+
+// EncryptSecretLabels заменяет открытые заголовки и метаданные секретов пользователя зашифрованными клиентом.
+// Возвращает ошибку, если секрет не найден у пользователя; в этом случае ни один секрет не изменяется.
+func (s *SecretService) EncryptSecretLabels(ctx context.Context, userID uint64, secrets models.Secrets) error {
+	if err := s.secretRepository.EncryptLabels(ctx, userID, secrets); err != nil {
+		return fmt.Errorf("failed to encrypt secret labels: %w", err)
+	}
+	return nil
+}
+
+// checkBlob проверяет объект, на который ссылается секрет, если ссылка задана.
+func (s *SecretService) checkBlob(ctx context.Context, secret *models.Secret) error {
+	if secret.BlobID == "" {
+		return nil
+	}
+	return s.blobService.CheckBlob(ctx, uint64(secret.UserID), secret.BlobID)
+}
+
+// encodePageToken кодирует токен страницы в строку для передачи клиенту.
+func encodePageToken(token pageToken) string {
+	data, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodePageToken разбирает токен страницы, полученный от клиента.
+func decodePageToken(value string) (*pageToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	var token pageToken
+	if err = json.Unmarshal(data, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// isNotFound проверяет, что ошибка репозитория означает отсутствие секрета у пользователя.
+func isNotFound(err error) bool {
+	return errors.Is(err, sql.ErrNoRows) || errors.Is(err, gophKeeperErrors.ErrNotFound)
+}
+
+// ResolveVault возвращает идентификатор служебной учётной записи хранилища организации orgID, от имени
+// которой выполняются операции с его секретами, если роль пользователя userID в организации не ниже required.
+// Возвращает ErrNotFound, если организация не найдена или пользователь в ней не состоит,
+// и ErrPermissionDenied, если роли пользователя недостаточно.
+func (s *SecretService) ResolveVault(ctx context.Context, userID, orgID uint64, required models.OrgRole) (uint64, error) {
+	org, err := s.orgRepository.GetMembership(ctx, orgID, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve organization vault: %w", err)
+	}
+	if !org.Role.AtLeast(required) {
+		return 0, fmt.Errorf("%s role required in organization %d: %w", required, orgID, gophKeeperErrors.ErrPermissionDenied)
+	}
+	return org.VaultID, nil
+}
+
+// VaultMembers возвращает идентификаторы участников организации orgID, которых уведомляют
+// об изменениях секретов её хранилища.
+func (s *SecretService) VaultMembers(ctx context.Context, orgID uint64) ([]uint64, error) {
+	members, err := s.orgRepository.ListMembers(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization members: %w", err)
+	}
+
+	ids := make([]uint64, 0, len(members))
+	for _, member := range members {
+		ids = append(ids, member.UserID)
+	}
+	return ids, nil
+}
+
+// ResolveEmergencyVault возвращает идентификатор владельца хранилища, экстренный доступ accessID к которому
+// предоставлен пользователю userID. Секреты владельца доступны доверенному пользователю только для чтения.
+// Возвращает ErrNotFound, если доступ не найден или назначен другому пользователю,
+// и ErrPermissionDenied, если доступ ещё не предоставлен.
+func (s *SecretService) ResolveEmergencyVault(ctx context.Context, userID, accessID uint64) (uint64, error) {
+	access, err := s.emergencyRepository.Get(ctx, accessID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve emergency vault: %w", err)
+	}
+	if access.GranteeID != userID {
+		return 0, fmt.Errorf("failed to resolve emergency vault: emergency access %w (id=%d)", gophKeeperErrors.ErrNotFound, accessID)
+	}
+	if access.Status != models.EmergencyGranted {
+		return 0, fmt.Errorf("emergency access %d is not granted: %w", accessID, gophKeeperErrors.ErrPermissionDenied)
+	}
+	return access.GrantorID, nil
+}
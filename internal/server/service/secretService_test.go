@@ -1,6 +1,9 @@
 package service
 
 import (
+	"beliaev-aa/GophKeeper/internal/server/config"
+	serverModels "beliaev-aa/GophKeeper/internal/server/models"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
 	"beliaev-aa/GophKeeper/pkg/models"
 	"beliaev-aa/GophKeeper/tests/mocks"
 	"context"
@@ -9,6 +12,7 @@ import (
 	"fmt"
 	"github.com/golang/mock/gomock"
 	"testing"
+	"time"
 )
 
 func TestSecretService(t *testing.T) {
@@ -16,7 +20,10 @@ func TestSecretService(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockISecretRepository(ctrl)
-	service := NewSecretService(mockRepo)
+	mockOrgRepo := mocks.NewMockIOrganizationRepository(ctrl)
+	mockEmergencyRepo := mocks.NewMockIEmergencyRepository(ctrl)
+	mockBlobService := mocks.NewMockIBlobService(ctrl)
+	service := NewSecretService(mockRepo, mockOrgRepo, mockEmergencyRepo, mockBlobService, &config.Config{SecretVersionsLimit: 10, TrashRetention: time.Hour})
 
 	ctx := context.Background()
 	testSecret := &models.Secret{
@@ -58,6 +65,18 @@ func TestSecretService(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "GetSecret_Fail_OtherUser",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().GetSecret(ctx, uint64(1), uint64(2)).Return(nil, gophKeeperErrors.ErrNotFound)
+
+				_, err := service.GetSecret(ctx, 1, 2)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
 		{
 			name: "GetSecret_Fail",
 			testFunc: func(t *testing.T) {
@@ -74,9 +93,10 @@ func TestSecretService(t *testing.T) {
 			name: "GetUserSecrets_Success",
 			testFunc: func(t *testing.T) {
 				secrets := models.Secrets{testSecret}
-				mockRepo.EXPECT().GetUserSecrets(ctx, uint64(1)).Return(secrets, nil)
+				filter := models.SecretFilter{FolderID: 2, TagID: 3}
+				mockRepo.EXPECT().GetUserSecrets(ctx, uint64(1), filter).Return(secrets, nil)
 
-				result, err := service.GetUserSecrets(ctx, 1)
+				result, err := service.GetUserSecrets(ctx, 1, filter)
 				if err != nil {
 					t.Errorf("Expected no error, got %v", err)
 				}
@@ -89,9 +109,9 @@ func TestSecretService(t *testing.T) {
 		{
 			name: "GetUserSecrets_Fail_EmptyList",
 			testFunc: func(t *testing.T) {
-				mockRepo.EXPECT().GetUserSecrets(ctx, uint64(1)).Return(nil, nil)
+				mockRepo.EXPECT().GetUserSecrets(ctx, uint64(1), models.SecretFilter{}).Return(nil, nil)
 
-				_, err := service.GetUserSecrets(ctx, 1)
+				_, err := service.GetUserSecrets(ctx, 1, models.SecretFilter{})
 				if err == nil || err.Error() != "no secrets found" {
 					t.Errorf("Expected error 'no secrets found', got %v", err)
 				}
@@ -101,15 +121,155 @@ func TestSecretService(t *testing.T) {
 		{
 			name: "GetUserSecrets_Fail",
 			testFunc: func(t *testing.T) {
-				mockRepo.EXPECT().GetUserSecrets(ctx, uint64(1)).Return(nil, errors.New("some error"))
+				mockRepo.EXPECT().GetUserSecrets(ctx, uint64(1), models.SecretFilter{}).Return(nil, errors.New("some error"))
 
-				_, err := service.GetUserSecrets(ctx, 1)
+				_, err := service.GetUserSecrets(ctx, 1, models.SecretFilter{})
 				if err == nil || err.Error() != "some error" {
 					t.Errorf("Expected error 'some error', got %v", err)
 				}
 			},
 			expectErr: true,
 		},
+		{
+			name: "SyncSecrets_Success",
+			testFunc: func(t *testing.T) {
+				delta := &models.SecretsDelta{Secrets: models.Secrets{testSecret}, DeletedIDs: []uint64{2}, Revision: 7}
+				mockRepo.EXPECT().Sync(ctx, uint64(1), uint64(5)).Return(delta, nil)
+
+				result, err := service.SyncSecrets(ctx, 1, 5)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if result != delta {
+					t.Errorf("Expected delta %+v, got %+v", delta, result)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "SyncSecrets_Fail",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().Sync(ctx, uint64(1), uint64(5)).Return(nil, errors.New("some error"))
+
+				_, err := service.SyncSecrets(ctx, 1, 5)
+				if err == nil || err.Error() != "failed to sync secrets: some error" {
+					t.Errorf("Expected error 'failed to sync secrets: some error', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "ListSecrets_NextPage",
+			testFunc: func(t *testing.T) {
+				updated := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+				query := &models.SecretListQuery{PageSize: 2}
+				mockRepo.EXPECT().List(ctx, uint64(1), query, nil, 3).Return(models.Secrets{
+					{ID: 5, Title: "Fifth"},
+					{ID: 4, Title: "Fourth", UpdatedAt: updated},
+					{ID: 3, Title: "Third"},
+				}, nil)
+
+				page, err := service.ListSecrets(ctx, 1, query)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if len(page.Secrets) != 2 || page.NextPageToken == "" {
+					t.Fatalf("Expected 2 secrets and next page token, got %+v", page)
+				}
+
+				next := &models.SecretListQuery{PageSize: 2, PageToken: page.NextPageToken}
+				cursor := &serverModels.SecretCursor{ID: 4, UpdatedAt: updated}
+				mockRepo.EXPECT().List(ctx, uint64(1), next, cursor, 3).Return(models.Secrets{{ID: 3, Title: "Third"}}, nil)
+
+				page, err = service.ListSecrets(ctx, 1, next)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if len(page.Secrets) != 1 || page.NextPageToken != "" {
+					t.Errorf("Expected last page with 1 secret, got %+v", page)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "ListSecrets_PageSizeLimit",
+			testFunc: func(t *testing.T) {
+				query := &models.SecretListQuery{PageSize: 10000}
+				mockRepo.EXPECT().List(ctx, uint64(1), query, nil, maxSecretsPageSize+1).Return(models.Secrets{}, nil)
+
+				if _, err := service.ListSecrets(ctx, 1, query); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "ListSecrets_Fail_InvalidPageToken",
+			testFunc: func(t *testing.T) {
+				token := encodePageToken(pageToken{Order: models.SecretOrderUpdatedAsc, Cursor: serverModels.SecretCursor{ID: 1}})
+				for _, query := range []*models.SecretListQuery{
+					{PageToken: "not a token"},
+					{PageToken: token, Order: models.SecretOrderUpdatedDesc},
+				} {
+					if _, err := service.ListSecrets(ctx, 1, query); !errors.Is(err, ErrInvalidPageToken) {
+						t.Errorf("Expected ErrInvalidPageToken for %q, got %v", query.PageToken, err)
+					}
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "ListSecrets_Fail_TitleQuery",
+			testFunc: func(t *testing.T) {
+				for _, query := range []*models.SecretListQuery{
+					{TitlePrefix: "Ba"},
+					{Order: models.SecretOrderTitleAsc},
+					{Order: models.SecretOrderTitleDesc},
+				} {
+					if _, err := service.ListSecrets(ctx, 1, query); !errors.Is(err, ErrTitleQueryUnsupported) {
+						t.Errorf("Expected ErrTitleQueryUnsupported for %+v, got %v", query, err)
+					}
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "ListSecrets_Fail",
+			testFunc: func(t *testing.T) {
+				query := &models.SecretListQuery{}
+				mockRepo.EXPECT().List(ctx, uint64(1), query, nil, defaultSecretsPageSize+1).Return(nil, errors.New("some error"))
+
+				_, err := service.ListSecrets(ctx, 1, query)
+				if err == nil || err.Error() != "failed to list secrets: some error" {
+					t.Errorf("Expected error 'failed to list secrets: some error', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "ReserveSecretID_Success",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().ReserveID(ctx, uint64(1)).Return(uint64(12), nil)
+
+				id, err := service.ReserveSecretID(ctx, 1)
+				if err != nil || id != 12 {
+					t.Errorf("Expected ID 12, got %v, err = %v", id, err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "ReserveSecretID_Fail",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().ReserveID(ctx, uint64(1)).Return(uint64(0), fmt.Errorf("database error"))
+
+				_, err := service.ReserveSecretID(ctx, 1)
+				if err == nil || err.Error() != "failed to reserve secret ID: database error" {
+					t.Errorf("Expected reserve error, got %v", err)
+				}
+			},
+			expectErr: true,
+		},
 		{
 			name: "CreateSecret_Success",
 			testFunc: func(t *testing.T) {
@@ -125,6 +285,34 @@ func TestSecretService(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name: "CreateSecret_Blob_Success",
+			testFunc: func(t *testing.T) {
+				blobSecret := &models.Secret{UserID: 1, SecretType: string(models.BlobSecret), BlobID: testBlobID}
+				gomock.InOrder(
+					mockBlobService.EXPECT().CheckBlob(ctx, uint64(1), testBlobID).Return(nil),
+					mockRepo.EXPECT().Create(ctx, blobSecret).Return(uint64(2), nil),
+				)
+
+				if _, err := service.CreateSecret(ctx, blobSecret); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "CreateSecret_Fail_InvalidBlob",
+			testFunc: func(t *testing.T) {
+				blobSecret := &models.Secret{UserID: 1, SecretType: string(models.BlobSecret), BlobID: testBlobID}
+				mockBlobService.EXPECT().CheckBlob(ctx, uint64(1), testBlobID).Return(gophKeeperErrors.ErrInvalidBlob)
+
+				_, err := service.CreateSecret(ctx, blobSecret)
+				if !errors.Is(err, gophKeeperErrors.ErrInvalidBlob) {
+					t.Errorf("Expected error 'ErrInvalidBlob', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
 		{
 			name: "CreateSecret_Fail",
 			testFunc: func(t *testing.T) {
@@ -140,7 +328,7 @@ func TestSecretService(t *testing.T) {
 		{
 			name: "UpdateSecret_Success",
 			testFunc: func(t *testing.T) {
-				mockRepo.EXPECT().Update(ctx, testSecret).Return(nil)
+				mockRepo.EXPECT().Update(ctx, testSecret, 10).Return(nil)
 
 				updatedSecret, err := service.UpdateSecret(ctx, testSecret)
 				if err != nil {
@@ -152,10 +340,23 @@ func TestSecretService(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name: "UpdateSecret_Fail_InvalidBlob",
+			testFunc: func(t *testing.T) {
+				blobSecret := &models.Secret{ID: 2, UserID: 1, SecretType: string(models.BlobSecret), BlobID: testBlobID}
+				mockBlobService.EXPECT().CheckBlob(ctx, uint64(1), testBlobID).Return(gophKeeperErrors.ErrInvalidBlob)
+
+				_, err := service.UpdateSecret(ctx, blobSecret)
+				if !errors.Is(err, gophKeeperErrors.ErrInvalidBlob) {
+					t.Errorf("Expected error 'ErrInvalidBlob', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
 		{
 			name: "UpdateSecret_Fail_NotFound",
 			testFunc: func(t *testing.T) {
-				mockRepo.EXPECT().Update(ctx, testSecret).Return(sql.ErrNoRows)
+				mockRepo.EXPECT().Update(ctx, testSecret, 10).Return(sql.ErrNoRows)
 
 				_, err := service.UpdateSecret(ctx, testSecret)
 				if err == nil || err.Error() != "secret not found (id=1)" {
@@ -165,10 +366,35 @@ func TestSecretService(t *testing.T) {
 			expectErr: true,
 		},
 
+		{
+			name: "UpdateSecret_Fail_OtherUser",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().Update(ctx, testSecret, 10).Return(fmt.Errorf("secret with ID 1: %w", gophKeeperErrors.ErrNotFound))
+
+				_, err := service.UpdateSecret(ctx, testSecret)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) || err.Error() != "secret not found (id=1)" {
+					t.Errorf("Expected error 'secret not found (id=1)', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "UpdateSecret_Fail_RevisionConflict",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().Update(ctx, testSecret, 10).Return(&gophKeeperErrors.RevisionConflictError{Current: 5})
+
+				_, err := service.UpdateSecret(ctx, testSecret)
+				var conflict *gophKeeperErrors.RevisionConflictError
+				if !errors.As(err, &conflict) || conflict.Current != 5 {
+					t.Errorf("Expected revision conflict with current revision 5, got %v", err)
+				}
+			},
+			expectErr: true,
+		},
 		{
 			name: "UpdateSecret_Fail",
 			testFunc: func(t *testing.T) {
-				mockRepo.EXPECT().Update(ctx, testSecret).Return(errors.New("some error"))
+				mockRepo.EXPECT().Update(ctx, testSecret, 10).Return(errors.New("some error"))
 
 				_, err := service.UpdateSecret(ctx, testSecret)
 				if err == nil || err.Error() != "failed to store secret: some error" {
@@ -189,6 +415,18 @@ func TestSecretService(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name: "DeleteSecret_Fail_NotFound",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().Delete(ctx, uint64(1), uint64(2)).Return(gophKeeperErrors.ErrNotFound)
+
+				err := service.DeleteSecret(ctx, 1, 2)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) || err.Error() != "secret not found (id=1)" {
+					t.Errorf("Expected error 'secret not found (id=1)', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
 		{
 			name: "DeleteSecret_Fail",
 			testFunc: func(t *testing.T) {
@@ -201,6 +439,329 @@ func TestSecretService(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "ListSecretVersions_Success",
+			testFunc: func(t *testing.T) {
+				versions := []*models.SecretVersion{{Secret: *testSecret, VersionID: 3}}
+				mockRepo.EXPECT().ListVersions(ctx, uint64(1), uint64(1)).Return(versions, nil)
+
+				result, err := service.ListSecretVersions(ctx, 1, 1)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if len(result) != 1 || result[0].VersionID != 3 {
+					t.Errorf("Unexpected versions: %+v", result)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "ListSecretVersions_Fail_OtherUser",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().ListVersions(ctx, uint64(1), uint64(2)).Return(nil, gophKeeperErrors.ErrNotFound)
+
+				_, err := service.ListSecretVersions(ctx, 1, 2)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) || err.Error() != "secret not found (id=1)" {
+					t.Errorf("Expected error 'secret not found (id=1)', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "ListSecretVersions_Fail",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().ListVersions(ctx, uint64(1), uint64(1)).Return(nil, errors.New("some error"))
+
+				_, err := service.ListSecretVersions(ctx, 1, 1)
+				if err == nil || err.Error() != "failed to list secret versions: some error" {
+					t.Errorf("Expected error 'failed to list secret versions: some error', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "RestoreSecretVersion_Success",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().RestoreVersion(ctx, uint64(1), uint64(3), uint64(1), 10).Return(testSecret, nil)
+
+				secret, err := service.RestoreSecretVersion(ctx, 1, 3, 1)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if secret.ID != testSecret.ID {
+					t.Errorf("Expected secret ID %v, got %v", testSecret.ID, secret.ID)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "RestoreSecretVersion_Fail_NotFound",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().RestoreVersion(ctx, uint64(1), uint64(3), uint64(2), 10).Return(nil, fmt.Errorf("version with ID 3: %w", gophKeeperErrors.ErrNotFound))
+
+				_, err := service.RestoreSecretVersion(ctx, 1, 3, 2)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) || err.Error() != "secret version not found (id=1, version=3)" {
+					t.Errorf("Expected error 'secret version not found (id=1, version=3)', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "RestoreSecretVersion_Fail",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().RestoreVersion(ctx, uint64(1), uint64(3), uint64(1), 10).Return(nil, errors.New("some error"))
+
+				_, err := service.RestoreSecretVersion(ctx, 1, 3, 1)
+				if err == nil || err.Error() != "failed to restore secret version: some error" {
+					t.Errorf("Expected error 'failed to restore secret version: some error', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "ListTrash_Success",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().ListTrash(ctx, uint64(1)).Return(models.Secrets{testSecret}, nil)
+
+				secrets, err := service.ListTrash(ctx, 1)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if len(secrets) != 1 {
+					t.Errorf("Expected 1 secret, got %d", len(secrets))
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "ListTrash_Fail",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().ListTrash(ctx, uint64(1)).Return(nil, errors.New("some error"))
+
+				_, err := service.ListTrash(ctx, 1)
+				if err == nil || err.Error() != "failed to list trash: some error" {
+					t.Errorf("Expected error 'failed to list trash: some error', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "RestoreSecret_Success",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().Restore(ctx, uint64(1), uint64(1)).Return(nil)
+
+				if err := service.RestoreSecret(ctx, 1, 1); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "RestoreSecret_Fail_NotFound",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().Restore(ctx, uint64(1), uint64(2)).Return(gophKeeperErrors.ErrNotFound)
+
+				err := service.RestoreSecret(ctx, 1, 2)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) || err.Error() != "trashed secret not found (id=1)" {
+					t.Errorf("Expected error 'trashed secret not found (id=1)', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "EncryptSecretLabels_Success",
+			testFunc: func(t *testing.T) {
+				secrets := models.Secrets{{ID: 1, Title: "sealed", LabelsEncrypted: true}}
+				mockRepo.EXPECT().EncryptLabels(ctx, uint64(1), secrets).Return(nil)
+
+				if err := service.EncryptSecretLabels(ctx, 1, secrets); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "EncryptSecretLabels_Fail_NotFound",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().EncryptLabels(ctx, uint64(1), gomock.Any()).Return(fmt.Errorf("secret with ID 5: %w", gophKeeperErrors.ErrNotFound))
+
+				err := service.EncryptSecretLabels(ctx, 1, models.Secrets{{ID: 5}})
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "EncryptSecretLabels_Fail",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().EncryptLabels(ctx, uint64(1), gomock.Any()).Return(errors.New("database error"))
+
+				err := service.EncryptSecretLabels(ctx, 1, nil)
+				if err == nil || err.Error() != "failed to encrypt secret labels: database error" {
+					t.Errorf("Expected error 'failed to encrypt secret labels: database error', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "PurgeSecret_Success",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().Purge(ctx, uint64(1), uint64(1)).Return([]string{"current", "previous"}, nil)
+				mockBlobService.EXPECT().ReleaseBlobs(ctx, uint64(1), []string{"current", "previous"}).Return(nil)
+
+				if err := service.PurgeSecret(ctx, 1, 1); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "PurgeSecret_Fail_ReleaseBlobs",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().Purge(ctx, uint64(1), uint64(1)).Return([]string{"current"}, nil)
+				mockBlobService.EXPECT().ReleaseBlobs(ctx, uint64(1), []string{"current"}).Return(errors.New("store error"))
+
+				if err := service.PurgeSecret(ctx, 1, 1); err == nil {
+					t.Errorf("Expected error, got nil")
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "PurgeSecret_Fail",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().Purge(ctx, uint64(1), uint64(1)).Return(nil, errors.New("some error"))
+
+				err := service.PurgeSecret(ctx, 1, 1)
+				if err == nil || err.Error() != "failed to purge secret: some error" {
+					t.Errorf("Expected error 'failed to purge secret: some error', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "PurgeExpiredTrash_Success",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().PurgeExpired(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, before time.Time) (int64, error) {
+					if age := time.Since(before); age < time.Hour || age > time.Hour+time.Minute {
+						t.Errorf("Expected cutoff one hour ago, got %v", before)
+					}
+					return 2, nil
+				})
+
+				purged, err := service.PurgeExpiredTrash(ctx)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if purged != 2 {
+					t.Errorf("Expected 2 purged secrets, got %d", purged)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "PurgeExpiredTrash_Fail",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().PurgeExpired(ctx, gomock.Any()).Return(int64(0), errors.New("some error"))
+
+				_, err := service.PurgeExpiredTrash(ctx)
+				if err == nil || err.Error() != "failed to purge expired trash: some error" {
+					t.Errorf("Expected error 'failed to purge expired trash: some error', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "ResolveVault_Success",
+			testFunc: func(t *testing.T) {
+				mockOrgRepo.EXPECT().GetMembership(ctx, uint64(7), uint64(1)).
+					Return(&models.Organization{ID: 7, VaultID: 100, Role: models.OrgAdmin}, nil)
+
+				vaultID, err := service.ResolveVault(ctx, 1, 7, models.OrgEditor)
+				if err != nil || vaultID != 100 {
+					t.Errorf("Expected vault 100, got %d, err = %v", vaultID, err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "ResolveVault_Fail_ViewerWrites",
+			testFunc: func(t *testing.T) {
+				mockOrgRepo.EXPECT().GetMembership(ctx, uint64(7), uint64(1)).
+					Return(&models.Organization{ID: 7, VaultID: 100, Role: models.OrgViewer}, nil)
+
+				_, err := service.ResolveVault(ctx, 1, 7, models.OrgEditor)
+				if !errors.Is(err, gophKeeperErrors.ErrPermissionDenied) {
+					t.Errorf("Expected error 'ErrPermissionDenied', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "ResolveVault_Fail_NotMember",
+			testFunc: func(t *testing.T) {
+				mockOrgRepo.EXPECT().GetMembership(ctx, uint64(7), uint64(2)).Return(nil, gophKeeperErrors.ErrNotFound)
+
+				_, err := service.ResolveVault(ctx, 2, 7, models.OrgViewer)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "ResolveEmergencyVault_Success",
+			testFunc: func(t *testing.T) {
+				mockEmergencyRepo.EXPECT().Get(ctx, uint64(5)).
+					Return(&models.EmergencyAccess{ID: 5, GrantorID: 1, GranteeID: 2, Status: models.EmergencyGranted}, nil)
+
+				vaultID, err := service.ResolveEmergencyVault(ctx, 2, 5)
+				if err != nil || vaultID != 1 {
+					t.Errorf("Expected vault of the grantor 1, got %d, err = %v", vaultID, err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "ResolveEmergencyVault_Fail_NotGranted",
+			testFunc: func(t *testing.T) {
+				mockEmergencyRepo.EXPECT().Get(ctx, uint64(5)).
+					Return(&models.EmergencyAccess{ID: 5, GrantorID: 1, GranteeID: 2, Status: models.EmergencyRequested}, nil)
+
+				_, err := service.ResolveEmergencyVault(ctx, 2, 5)
+				if !errors.Is(err, gophKeeperErrors.ErrPermissionDenied) {
+					t.Errorf("Expected error 'ErrPermissionDenied', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "ResolveEmergencyVault_Fail_OtherGrantee",
+			testFunc: func(t *testing.T) {
+				mockEmergencyRepo.EXPECT().Get(ctx, uint64(5)).
+					Return(&models.EmergencyAccess{ID: 5, GrantorID: 1, GranteeID: 2, Status: models.EmergencyGranted}, nil)
+
+				_, err := service.ResolveEmergencyVault(ctx, 3, 5)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "VaultMembers_Success",
+			testFunc: func(t *testing.T) {
+				mockOrgRepo.EXPECT().ListMembers(ctx, uint64(7)).
+					Return([]*models.OrgMember{{OrgID: 7, UserID: 1}, {OrgID: 7, UserID: 2}}, nil)
+
+				ids, err := service.VaultMembers(ctx, 7)
+				if err != nil || len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+					t.Errorf("Expected members [1 2], got %v, err = %v", ids, err)
+				}
+			},
+			expectErr: false,
+		},
 	}
 
 	for _, tc := range tests {
@@ -0,0 +1,244 @@
+// Package service предоставляет бизнес-логику управления сессиями и обновления токенов доступа.
+package service
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/auth"
+	"beliaev-aa/GophKeeper/internal/server/config"
+	"beliaev-aa/GophKeeper/internal/server/events"
+	"beliaev-aa/GophKeeper/internal/server/models"
+	"beliaev-aa/GophKeeper/internal/server/storage/repository"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxDeviceNameLength определяет максимальную длину имени устройства в символах.
+const maxDeviceNameLength = 64
+
+// ErrInvalidRefreshToken определяет ошибку, возникающую при предъявлении неизвестного, истёкшего,
+// отозванного или уже использованного refresh-токена.
+var ErrInvalidRefreshToken = errors.New("invalid refresh token")
+
+// ErrInvalidDeviceName определяет ошибку, возникающую при попытке задать пустое или слишком длинное имя устройства.
+var ErrInvalidDeviceName = errors.New("invalid device name")
+
+// ISessionService определяет интерфейс для сервиса сессий пользователей.
+type ISessionService interface {
+	// CreateSession открывает новую сессию пользователя на устройстве и выдаёт для неё пару токенов.
+	CreateSession(ctx context.Context, userID int, deviceID uint64, deviceName string) (*models.Tokens, error)
+
+	// RefreshSession обменивает refresh-токен на новую пару токенов той же сессии.
+	RefreshSession(ctx context.Context, refreshToken string) (*models.Tokens, error)
+
+	// IsSessionActive проверяет, что сессия токена доступа не отозвана.
+	IsSessionActive(ctx context.Context, userID int, sessionID uint64) (bool, error)
+
+	// ListSessions возвращает активные сессии пользователя.
+	ListSessions(ctx context.Context, userID int) ([]models.Session, error)
+
+	// RenameSession изменяет имя устройства сессии.
+	RenameSession(ctx context.Context, userID int, sessionID uint64, deviceName string) error
+
+	// RevokeSession отзывает сессию пользователя и закрывает её потоки уведомлений.
+	RevokeSession(ctx context.Context, userID int, sessionID uint64) error
+
+	// LogoutAll отзывает все сессии пользователя и закрывает все его потоки уведомлений.
+	LogoutAll(ctx context.Context, userID int) error
+
+	// RevokeOtherSessions отзывает все сессии пользователя, кроме текущей, и сообщает их устройствам причину отзыва.
+	RevokeOtherSessions(ctx context.Context, userID int, sessionID uint64, reason events.Kind) error
+
+	// CloseStreams закрывает все потоки уведомлений пользователя, сообщая устройствам причину закрытия.
+	CloseStreams(userID int, reason events.Kind)
+}
+
+// SessionService предоставляет методы для выдачи и обновления токенов сессий.
+type SessionService struct {
+	sessionRepository repository.ISessionRepository // sessionRepository представляет репозиторий сессий.
+	config            *config.Config                // config содержит ключ подписи и время жизни токенов.
+	hub               *events.Hub                   // hub используется для закрытия потоков уведомлений отозванных сессий.
+}
+
+// NewSessionService создаёт новый экземпляр SessionService.
+func NewSessionService(sessionRepository repository.ISessionRepository, config *config.Config, hub *events.Hub) ISessionService {
+	return &SessionService{sessionRepository: sessionRepository, config: config, hub: hub}
+}
+
+// CreateSession открывает новую сессию пользователя на устройстве deviceID.
+// Предыдущие сессии того же устройства отзываются: на одном устройстве активна только одна сессия.
+// Возвращает токен доступа, привязанный к сессии, и её refresh-токен.
+func (s *SessionService) CreateSession(ctx context.Context, userID int, deviceID uint64, deviceName string) (*models.Tokens, error) {
+	refreshToken, tokenHash, err := auth.NewRefreshToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	if deviceID != 0 {
+		revoked, err := s.sessionRepository.RevokeDevice(ctx, userID, deviceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to revoke previous device sessions: %w", err)
+		}
+		if len(revoked) > 0 {
+			s.hub.Disconnect(uint64(userID), revoked...)
+		}
+	}
+
+	sessionID, err := s.sessionRepository.Create(ctx, models.Session{
+		UserID:     userID,
+		DeviceID:   deviceID,
+		DeviceName: truncateDeviceName(strings.TrimSpace(deviceName)),
+		TokenHash:  tokenHash,
+		ExpiresAt:  time.Now().Add(s.config.RefreshTokenTTL),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	accessToken, err := s.accessToken(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Tokens{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// RefreshSession обменивает refresh-токен на новую пару токенов.
+// Каждый refresh-токен одноразовый: повторное предъявление уже заменённого токена означает его утечку,
+// поэтому вся сессия отзывается. Возвращает ErrInvalidRefreshToken, если обмен невозможен.
+func (s *SessionService) RefreshSession(ctx context.Context, refreshToken string) (*models.Tokens, error) {
+	tokenHash := auth.HashRefreshToken(refreshToken)
+
+	session, err := s.sessionRepository.GetByTokenHash(ctx, tokenHash)
+	if errors.Is(err, gophKeeperErrors.ErrNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	if session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if !bytes.Equal(session.TokenHash, tokenHash) {
+		if err = s.RevokeSession(ctx, session.UserID, session.ID); err != nil && !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	newRefreshToken, newHash, err := auth.NewRefreshToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	err = s.sessionRepository.Rotate(ctx, session.ID, tokenHash, newHash, time.Now().Add(s.config.RefreshTokenTTL))
+	if errors.Is(err, gophKeeperErrors.ErrNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate session: %w", err)
+	}
+
+	accessToken, err := s.accessToken(session.UserID, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Tokens{AccessToken: accessToken, RefreshToken: newRefreshToken}, nil
+}
+
+// IsSessionActive проверяет, что сессия принадлежит пользователю и не отозвана.
+func (s *SessionService) IsSessionActive(ctx context.Context, userID int, sessionID uint64) (bool, error) {
+	active, err := s.sessionRepository.IsActive(ctx, userID, sessionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check session: %w", err)
+	}
+	return active, nil
+}
+
+// ListSessions возвращает активные сессии пользователя.
+func (s *SessionService) ListSessions(ctx context.Context, userID int) ([]models.Session, error) {
+	sessions, err := s.sessionRepository.ListActive(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// RenameSession изменяет имя устройства сессии.
+// Возвращает ErrInvalidDeviceName для пустого или слишком длинного имени
+// и ErrNotFound, если у пользователя нет такой активной сессии.
+func (s *SessionService) RenameSession(ctx context.Context, userID int, sessionID uint64, deviceName string) error {
+	deviceName = strings.TrimSpace(deviceName)
+	if deviceName == "" || utf8.RuneCountInString(deviceName) > maxDeviceNameLength {
+		return ErrInvalidDeviceName
+	}
+
+	if err := s.sessionRepository.Rename(ctx, userID, sessionID, deviceName); err != nil {
+		return fmt.Errorf("failed to rename session: %w", err)
+	}
+	return nil
+}
+
+// RevokeSession отзывает сессию пользователя и закрывает её потоки уведомлений.
+// Возвращает ErrNotFound, если у пользователя нет такой активной сессии.
+func (s *SessionService) RevokeSession(ctx context.Context, userID int, sessionID uint64) error {
+	if err := s.sessionRepository.Revoke(ctx, userID, sessionID); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	s.hub.Disconnect(uint64(userID), sessionID)
+	return nil
+}
+
+// LogoutAll отзывает все сессии пользователя, включая текущую, и закрывает все его потоки уведомлений.
+func (s *SessionService) LogoutAll(ctx context.Context, userID int) error {
+	if err := s.sessionRepository.RevokeAll(ctx, userID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	s.hub.DisconnectAll(uint64(userID))
+	return nil
+}
+
+// RevokeOtherSessions отзывает все сессии пользователя, кроме сессии sessionID, с которой выполнен запрос.
+// Потоки уведомлений отозванных сессий получают событие reason и закрываются,
+// чтобы устройства сразу предложили пользователю войти заново.
+func (s *SessionService) RevokeOtherSessions(ctx context.Context, userID int, sessionID uint64, reason events.Kind) error {
+	revoked, err := s.sessionRepository.RevokeOthers(ctx, userID, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	if len(revoked) > 0 {
+		s.hub.DisconnectWith(events.Event{UserID: uint64(userID), Kind: reason}, revoked...)
+	}
+	return nil
+}
+
+// CloseStreams закрывает все потоки уведомлений пользователя. Перед закрытием каждый поток получает
+// событие reason. Используется, когда сессии пользователя прекращают существовать вместе с учётной записью.
+func (s *SessionService) CloseStreams(userID int, reason events.Kind) {
+	s.hub.DisconnectAllWith(events.Event{UserID: uint64(userID), Kind: reason})
+}
+
+// truncateDeviceName обрезает имя устройства, присланное клиентом, до допустимой длины.
+func truncateDeviceName(name string) string {
+	runes := []rune(name)
+	if len(runes) > maxDeviceNameLength {
+		return string(runes[:maxDeviceNameLength])
+	}
+	return name
+}
+
+// accessToken выпускает токен доступа сессии.
+func (s *SessionService) accessToken(userID int, sessionID uint64) (string, error) {
+	token, err := auth.CreateToken(userID, sessionID, time.Now().Add(s.config.AccessTokenTTL), []byte(s.config.SecretKey))
+	if err != nil {
+		return "", fmt.Errorf("failed to create access token: %w", err)
+	}
+	return token, nil
+}
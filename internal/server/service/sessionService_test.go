@@ -0,0 +1,268 @@
+package service
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/auth"
+	"beliaev-aa/GophKeeper/internal/server/config"
+	"beliaev-aa/GophKeeper/internal/server/events"
+	"beliaev-aa/GophKeeper/internal/server/models"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionService_CreateSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockISessionRepository(ctrl)
+	cfg := &config.Config{SecretKey: "secret", AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour}
+	hub := events.NewHub(zap.NewNop())
+	svc := NewSessionService(mockRepo, cfg, hub)
+
+	t.Run("Success", func(t *testing.T) {
+		previous := hub.Subscribe(1, 42, 5)
+
+		var stored models.Session
+		mockRepo.EXPECT().RevokeDevice(gomock.Any(), 1, uint64(42)).Return([]uint64{5}, nil).Times(1)
+		mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, session models.Session) (uint64, error) {
+			stored = session
+			return 7, nil
+		}).Times(1)
+
+		tokens, err := svc.CreateSession(context.Background(), 1, 42, "  laptop ")
+
+		require.NoError(t, err)
+		_, open := <-previous.Events
+		assert.False(t, open)
+		assert.Equal(t, 1, stored.UserID)
+		assert.Equal(t, uint64(42), stored.DeviceID)
+		assert.Equal(t, "laptop", stored.DeviceName)
+		assert.Equal(t, auth.HashRefreshToken(tokens.RefreshToken), stored.TokenHash)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), stored.ExpiresAt, time.Minute)
+
+		claims, err := auth.VerifyToken(tokens.AccessToken, []byte(cfg.SecretKey))
+		require.NoError(t, err)
+		assert.Equal(t, float64(1), claims["user_id"])
+		assert.Equal(t, float64(7), claims["session_id"])
+	})
+
+	t.Run("Unknown_Device", func(t *testing.T) {
+		mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, session models.Session) (uint64, error) {
+			assert.Len(t, []rune(session.DeviceName), maxDeviceNameLength)
+			return 8, nil
+		}).Times(1)
+
+		_, err := svc.CreateSession(context.Background(), 1, 0, strings.Repeat("я", maxDeviceNameLength+10))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Repository_Error", func(t *testing.T) {
+		mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(uint64(0), errors.New("db error")).Times(1)
+
+		_, err := svc.CreateSession(context.Background(), 1, 0, "")
+
+		assert.EqualError(t, err, "failed to create session: db error")
+	})
+}
+
+func TestSessionService_RefreshSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockISessionRepository(ctrl)
+	cfg := &config.Config{SecretKey: "secret", AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour}
+	svc := NewSessionService(mockRepo, cfg, events.NewHub(zap.NewNop()))
+
+	const refreshToken = "refresh-token"
+	tokenHash := auth.HashRefreshToken(refreshToken)
+	revokedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name      string
+		setupMock func()
+		expectErr error
+	}{
+		{
+			name: "Success",
+			setupMock: func() {
+				mockRepo.EXPECT().GetByTokenHash(gomock.Any(), tokenHash).
+					Return(&models.Session{ID: 7, UserID: 1, TokenHash: tokenHash, ExpiresAt: time.Now().Add(time.Hour)}, nil).Times(1)
+				mockRepo.EXPECT().Rotate(gomock.Any(), uint64(7), tokenHash, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "Unknown_Token",
+			setupMock: func() {
+				mockRepo.EXPECT().GetByTokenHash(gomock.Any(), tokenHash).Return(nil, gophKeeperErrors.ErrNotFound).Times(1)
+			},
+			expectErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "Expired_Session",
+			setupMock: func() {
+				mockRepo.EXPECT().GetByTokenHash(gomock.Any(), tokenHash).
+					Return(&models.Session{ID: 7, UserID: 1, TokenHash: tokenHash, ExpiresAt: time.Now().Add(-time.Second)}, nil).Times(1)
+			},
+			expectErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "Revoked_Session",
+			setupMock: func() {
+				mockRepo.EXPECT().GetByTokenHash(gomock.Any(), tokenHash).
+					Return(&models.Session{ID: 7, UserID: 1, TokenHash: tokenHash, ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}, nil).Times(1)
+			},
+			expectErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "Reused_Token_Revokes_Session",
+			setupMock: func() {
+				mockRepo.EXPECT().GetByTokenHash(gomock.Any(), tokenHash).
+					Return(&models.Session{ID: 7, UserID: 1, TokenHash: []byte("rotated"), PreviousTokenHash: tokenHash, ExpiresAt: time.Now().Add(time.Hour)}, nil).Times(1)
+				mockRepo.EXPECT().Revoke(gomock.Any(), 1, uint64(7)).Return(nil).Times(1)
+			},
+			expectErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "Concurrent_Rotation",
+			setupMock: func() {
+				mockRepo.EXPECT().GetByTokenHash(gomock.Any(), tokenHash).
+					Return(&models.Session{ID: 7, UserID: 1, TokenHash: tokenHash, ExpiresAt: time.Now().Add(time.Hour)}, nil).Times(1)
+				mockRepo.EXPECT().Rotate(gomock.Any(), uint64(7), tokenHash, gomock.Any(), gomock.Any()).Return(gophKeeperErrors.ErrNotFound).Times(1)
+			},
+			expectErr: ErrInvalidRefreshToken,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMock()
+
+			tokens, err := svc.RefreshSession(context.Background(), refreshToken)
+
+			if tc.expectErr != nil {
+				assert.ErrorIs(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEqual(t, refreshToken, tokens.RefreshToken)
+			claims, err := auth.VerifyToken(tokens.AccessToken, []byte(cfg.SecretKey))
+			require.NoError(t, err)
+			assert.Equal(t, float64(7), claims["session_id"])
+		})
+	}
+}
+
+func TestSessionService_ManageSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockISessionRepository(ctrl)
+	hub := events.NewHub(zap.NewNop())
+	svc := NewSessionService(mockRepo, &config.Config{SecretKey: "secret"}, hub)
+	ctx := context.Background()
+
+	t.Run("IsSessionActive", func(t *testing.T) {
+		mockRepo.EXPECT().IsActive(ctx, 1, uint64(7)).Return(false, nil).Times(1)
+
+		active, err := svc.IsSessionActive(ctx, 1, 7)
+
+		assert.NoError(t, err)
+		assert.False(t, active)
+	})
+
+	t.Run("ListSessions", func(t *testing.T) {
+		mockRepo.EXPECT().ListActive(ctx, 1).Return([]models.Session{{ID: 7}}, nil).Times(1)
+
+		sessions, err := svc.ListSessions(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, sessions, 1)
+	})
+
+	t.Run("RenameSession", func(t *testing.T) {
+		mockRepo.EXPECT().Rename(ctx, 1, uint64(7), "work laptop").Return(nil).Times(1)
+
+		assert.NoError(t, svc.RenameSession(ctx, 1, 7, " work laptop "))
+	})
+
+	t.Run("RenameSession_InvalidName", func(t *testing.T) {
+		assert.ErrorIs(t, svc.RenameSession(ctx, 1, 7, "   "), ErrInvalidDeviceName)
+		assert.ErrorIs(t, svc.RenameSession(ctx, 1, 7, strings.Repeat("a", maxDeviceNameLength+1)), ErrInvalidDeviceName)
+	})
+
+	t.Run("RevokeSession_ClosesStream", func(t *testing.T) {
+		revoked := hub.Subscribe(1, 42, 7)
+		active := hub.Subscribe(1, 43, 8)
+		defer hub.Unsubscribe(active)
+		mockRepo.EXPECT().Revoke(ctx, 1, uint64(7)).Return(nil).Times(1)
+
+		assert.NoError(t, svc.RevokeSession(ctx, 1, 7))
+
+		_, open := <-revoked.Events
+		assert.False(t, open)
+		hub.Publish(events.Event{UserID: 1, SecretID: 1, Kind: events.SecretCreated})
+		assert.Len(t, active.Events, 1)
+	})
+
+	t.Run("RevokeSession_NotFound", func(t *testing.T) {
+		mockRepo.EXPECT().Revoke(ctx, 1, uint64(100)).Return(gophKeeperErrors.ErrNotFound).Times(1)
+
+		assert.ErrorIs(t, svc.RevokeSession(ctx, 1, 100), gophKeeperErrors.ErrNotFound)
+	})
+
+	t.Run("LogoutAll_ClosesStreams", func(t *testing.T) {
+		first := hub.Subscribe(1, 42, 7)
+		second := hub.Subscribe(1, 43, 8)
+		mockRepo.EXPECT().RevokeAll(ctx, 1).Return(nil).Times(1)
+
+		assert.NoError(t, svc.LogoutAll(ctx, 1))
+
+		_, open := <-first.Events
+		assert.False(t, open)
+		_, open = <-second.Events
+		assert.False(t, open)
+	})
+
+	t.Run("RevokeOtherSessions_NotifiesDevices", func(t *testing.T) {
+		other := hub.Subscribe(1, 42, 7)
+		current := hub.Subscribe(1, 43, 8)
+		defer hub.Unsubscribe(current)
+		mockRepo.EXPECT().RevokeOthers(ctx, 1, uint64(8)).Return([]uint64{7}, nil).Times(1)
+
+		assert.NoError(t, svc.RevokeOtherSessions(ctx, 1, 8, events.PasswordChanged))
+
+		event, open := <-other.Events
+		assert.True(t, open)
+		assert.Equal(t, events.PasswordChanged, event.Kind)
+		_, open = <-other.Events
+		assert.False(t, open)
+		assert.Empty(t, current.Events)
+	})
+
+	t.Run("CloseStreams_NotifiesDevices", func(t *testing.T) {
+		sub := hub.Subscribe(1, 42, 7)
+
+		svc.CloseStreams(1, events.AccountDeleted)
+
+		event, open := <-sub.Events
+		assert.True(t, open)
+		assert.Equal(t, events.AccountDeleted, event.Kind)
+		_, open = <-sub.Events
+		assert.False(t, open)
+	})
+
+	t.Run("RevokeOtherSessions_Error", func(t *testing.T) {
+		mockRepo.EXPECT().RevokeOthers(ctx, 1, uint64(8)).Return(nil, errors.New("db error")).Times(1)
+
+		assert.EqualError(t, svc.RevokeOtherSessions(ctx, 1, 8, events.PasswordChanged), "failed to revoke sessions: db error")
+	})
+}
@@ -0,0 +1,132 @@
+// Package service предоставляет бизнес-логику для передачи секретов другим пользователям.
+package service
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/config"
+	"beliaev-aa/GophKeeper/internal/server/storage/repository"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidShareRequest определяет ошибку, возникающую, если клиент не передал логин получателя
+// или ключ данных секрета, зашифрованный для получателя.
+var ErrInvalidShareRequest = errors.New("invalid share request")
+
+// IShareService определяет интерфейс для сервиса передачи секретов другим пользователям.
+type IShareService interface {
+	// GetPublicKey возвращает открытый ключ пользователя, которому передаётся секрет.
+	GetPublicKey(ctx context.Context, login string) ([]byte, error)
+
+	// ShareSecret передаёт секрет владельца другому пользователю и возвращает доступ.
+	ShareSecret(ctx context.Context, share *models.Share) (*models.Share, error)
+
+	// ListShares возвращает доступы, выданные владельцем к секрету.
+	ListShares(ctx context.Context, ownerID, secretID uint64) ([]*models.Share, error)
+
+	// ListSharedWithMe возвращает секреты, переданные пользователю.
+	ListSharedWithMe(ctx context.Context, recipientID uint64) (models.Secrets, error)
+
+	// RevokeShare удаляет доступ к секрету по запросу владельца или получателя.
+	RevokeShare(ctx context.Context, userID, shareID uint64) error
+
+	// UpdateSharedSecret сохраняет изменения переданного секрета, сделанные получателем.
+	UpdateSharedSecret(ctx context.Context, recipientID uint64, secret *models.Secret) (*models.Secret, error)
+
+	// ResolveSharedBlob возвращает владельца объекта переданного секрета-файла, который читает получатель.
+	ResolveSharedBlob(ctx context.Context, recipientID, shareID uint64, blobID string) (uint64, error)
+}
+
+// ShareService предоставляет методы для передачи секретов другим пользователям.
+type ShareService struct {
+	shareRepository repository.IShareRepository // shareRepository является репозиторием для доступа к доступам в базе данных.
+	config          *config.Config              // config задаёт лимит хранимых версий секретов.
+}
+
+// NewShareService создаёт новый экземпляр ShareService.
+// Принимает в качестве аргументов репозиторий доступов и конфигурацию сервера и возвращает ссылку на сервис.
+func NewShareService(shareRepository repository.IShareRepository, config *config.Config) IShareService {
+	return &ShareService{
+		shareRepository: shareRepository,
+		config:          config,
+	}
+}
+
+// GetPublicKey возвращает открытый ключ пользователя с логином login.
+// Возвращает ErrNotFound, если пользователь не найден или ещё не создал пару ключей.
+func (s *ShareService) GetPublicKey(ctx context.Context, login string) ([]byte, error) {
+	publicKey, err := s.shareRepository.GetPublicKey(ctx, strings.TrimSpace(login))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public key: %w", err)
+	}
+	return publicKey, nil
+}
+
+// ShareSecret передаёт секрет владельца share.OwnerID пользователю share.RecipientLogin. Повторная передача
+// тому же пользователю заменяет ключ данных и право изменения.
+// Возвращает ErrInvalidShareRequest без логина получателя или ключа данных, ErrNotFound, если получатель
+// или секрет не найдены, и ErrInvalidShare при передаче секрета самому себе.
+func (s *ShareService) ShareSecret(ctx context.Context, share *models.Share) (*models.Share, error) {
+	share.RecipientLogin = strings.TrimSpace(share.RecipientLogin)
+	if share.RecipientLogin == "" || len(share.DataKey) == 0 {
+		return nil, ErrInvalidShareRequest
+	}
+
+	if err := s.shareRepository.CreateShare(ctx, share); err != nil {
+		return nil, fmt.Errorf("failed to share secret: %w", err)
+	}
+	return share, nil
+}
+
+// ListShares возвращает доступы, выданные владельцем к секрету secretID; при нулевом secretID -
+// ко всем его секретам.
+func (s *ShareService) ListShares(ctx context.Context, ownerID, secretID uint64) ([]*models.Share, error) {
+	shares, err := s.shareRepository.ListShares(ctx, ownerID, secretID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shares: %w", err)
+	}
+	return shares, nil
+}
+
+// ListSharedWithMe возвращает секреты других пользователей, переданные пользователю recipientID.
+func (s *ShareService) ListSharedWithMe(ctx context.Context, recipientID uint64) (models.Secrets, error) {
+	secrets, err := s.shareRepository.ListSharedWithMe(ctx, recipientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shared secrets: %w", err)
+	}
+	return secrets, nil
+}
+
+// RevokeShare удаляет доступ к секрету. Возвращает ErrNotFound, если доступ не найден
+// или пользователь не является ни владельцем секрета, ни получателем.
+func (s *ShareService) RevokeShare(ctx context.Context, userID, shareID uint64) error {
+	if err := s.shareRepository.DeleteShare(ctx, userID, shareID); err != nil {
+		return fmt.Errorf("failed to revoke share: %w", err)
+	}
+	return nil
+}
+
+// UpdateSharedSecret сохраняет изменения переданного секрета, сделанные получателем с правом изменения,
+// и возвращает секрет с новой ревизией и идентификатором владельца.
+// Возвращает ErrNotFound, если секрет не передан получателю, ErrShareReadOnly без права изменения
+// и RevisionConflictError, если секрет был изменён после загрузки.
+func (s *ShareService) UpdateSharedSecret(ctx context.Context, recipientID uint64, secret *models.Secret) (*models.Secret, error) {
+	if err := s.shareRepository.UpdateSharedSecret(ctx, recipientID, secret, s.config.SecretVersionsLimit); err != nil {
+		return nil, fmt.Errorf("failed to update shared secret: %w", err)
+	}
+	return secret, nil
+}
+
+// ResolveSharedBlob возвращает идентификатор владельца объекта blobID, на который ссылается секрет-файл,
+// переданный получателю recipientID доступом shareID. Получатель читает объект от имени владельца,
+// но не может загружать в него фрагменты.
+// Возвращает ErrNotFound, если доступ не выдан получателю или переданный секрет не ссылается на объект.
+func (s *ShareService) ResolveSharedBlob(ctx context.Context, recipientID, shareID uint64, blobID string) (uint64, error) {
+	ownerID, err := s.shareRepository.GetSharedBlobOwner(ctx, recipientID, shareID, blobID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve shared blob: %w", err)
+	}
+	return ownerID, nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/config"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"testing"
+)
+
+func TestShareService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIShareRepository(ctrl)
+	service := NewShareService(mockRepo, &config.Config{SecretVersionsLimit: 10})
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "GetPublicKey_Success_TrimsLogin",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().GetPublicKey(ctx, "bob").Return([]byte("public"), nil)
+
+				publicKey, err := service.GetPublicKey(ctx, " bob ")
+				if err != nil || string(publicKey) != "public" {
+					t.Errorf("Expected public key, got %q, err = %v", publicKey, err)
+				}
+			},
+		},
+		{
+			name: "ShareSecret_Success",
+			testFunc: func(t *testing.T) {
+				expected := &models.Share{SecretID: 10, OwnerID: 1, RecipientLogin: "bob", DataKey: []byte("wrapped")}
+				mockRepo.EXPECT().CreateShare(ctx, expected).DoAndReturn(func(_ context.Context, share *models.Share) error {
+					share.ID = 5
+					return nil
+				})
+
+				share, err := service.ShareSecret(ctx, &models.Share{SecretID: 10, OwnerID: 1, RecipientLogin: " bob", DataKey: []byte("wrapped")})
+				if err != nil || share.ID != 5 {
+					t.Errorf("Expected share 5, got %+v, err = %v", share, err)
+				}
+			},
+		},
+		{
+			name: "ShareSecret_Fail_NoDataKey",
+			testFunc: func(t *testing.T) {
+				_, err := service.ShareSecret(ctx, &models.Share{SecretID: 10, OwnerID: 1, RecipientLogin: "bob"})
+				if !errors.Is(err, ErrInvalidShareRequest) {
+					t.Errorf("Expected error 'ErrInvalidShareRequest', got %v", err)
+				}
+			},
+		},
+		{
+			name: "ShareSecret_Fail_Self",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().CreateShare(ctx, gomock.Any()).Return(gophKeeperErrors.ErrInvalidShare)
+
+				_, err := service.ShareSecret(ctx, &models.Share{SecretID: 10, OwnerID: 1, RecipientLogin: "alice", DataKey: []byte("wrapped")})
+				if !errors.Is(err, gophKeeperErrors.ErrInvalidShare) {
+					t.Errorf("Expected error 'ErrInvalidShare', got %v", err)
+				}
+			},
+		},
+		{
+			name: "ListSharedWithMe_Success",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().ListSharedWithMe(ctx, uint64(2)).Return(models.Secrets{{ID: 10, Share: &models.Share{ID: 5}}}, nil)
+
+				secrets, err := service.ListSharedWithMe(ctx, 2)
+				if err != nil || len(secrets) != 1 {
+					t.Errorf("Expected 1 shared secret, got %v, err = %v", secrets, err)
+				}
+			},
+		},
+		{
+			name: "RevokeShare_Fail_NotFound",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().DeleteShare(ctx, uint64(2), uint64(5)).Return(gophKeeperErrors.ErrNotFound)
+
+				if err := service.RevokeShare(ctx, 2, 5); !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+		{
+			name: "UpdateSharedSecret_Success",
+			testFunc: func(t *testing.T) {
+				secret := &models.Secret{ID: 10, Revision: 3}
+				mockRepo.EXPECT().UpdateSharedSecret(ctx, uint64(2), secret, 10).DoAndReturn(
+					func(_ context.Context, _ uint64, secret *models.Secret, _ int) error {
+						secret.Revision++
+						return nil
+					})
+
+				updated, err := service.UpdateSharedSecret(ctx, 2, secret)
+				if err != nil || updated.Revision != 4 {
+					t.Errorf("Expected revision 4, got %+v, err = %v", updated, err)
+				}
+			},
+		},
+		{
+			name: "UpdateSharedSecret_Fail_ReadOnly",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().UpdateSharedSecret(ctx, uint64(2), gomock.Any(), 10).Return(gophKeeperErrors.ErrShareReadOnly)
+
+				_, err := service.UpdateSharedSecret(ctx, 2, &models.Secret{ID: 10})
+				if !errors.Is(err, gophKeeperErrors.ErrShareReadOnly) {
+					t.Errorf("Expected error 'ErrShareReadOnly', got %v", err)
+				}
+			},
+		},
+		{
+			name: "ResolveSharedBlob_Success",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().GetSharedBlobOwner(ctx, uint64(2), uint64(5), "blob").Return(uint64(1), nil)
+
+				ownerID, err := service.ResolveSharedBlob(ctx, 2, 5, "blob")
+				if err != nil || ownerID != 1 {
+					t.Errorf("Expected owner 1, got %d, err = %v", ownerID, err)
+				}
+			},
+		},
+		{
+			name: "ResolveSharedBlob_Fail_NotShared",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().GetSharedBlobOwner(ctx, uint64(2), uint64(5), "blob").Return(uint64(0), gophKeeperErrors.ErrNotFound)
+
+				if _, err := service.ResolveSharedBlob(ctx, 2, 5, "blob"); !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
@@ -0,0 +1,248 @@
+// Package service предоставляет бизнес-логику двухфакторной аутентификации по одноразовым кодам TOTP.
+package service
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/auth"
+	"beliaev-aa/GophKeeper/internal/server/config"
+	"beliaev-aa/GophKeeper/internal/server/models"
+	"beliaev-aa/GophKeeper/internal/server/storage/repository"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// totpIssuer определяет имя сервиса, отображаемое в приложении-аутентификаторе.
+	totpIssuer = "GophKeeper"
+	// recoveryCodesCount определяет количество кодов восстановления, выдаваемых при включении второго фактора.
+	recoveryCodesCount = 10
+	// totpChallengeTTL определяет, сколько времени после ввода пароля можно ввести код второго фактора.
+	totpChallengeTTL = 5 * time.Minute
+)
+
+var (
+	// ErrTOTPAlreadyEnabled определяет ошибку, возникающую при повторном включении второго фактора.
+	ErrTOTPAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	// ErrTOTPNotEnabled определяет ошибку, возникающую при отключении невключённого второго фактора.
+	ErrTOTPNotEnabled = errors.New("two-factor authentication is not enabled")
+	// ErrTOTPNotPending определяет ошибку, возникающую при подтверждении второго фактора без предварительного включения.
+	ErrTOTPNotPending = errors.New("two-factor authentication setup was not started")
+	// ErrInvalidTOTPCode определяет ошибку, возникающую при неверном, устаревшем или уже использованном коде.
+	ErrInvalidTOTPCode = errors.New("invalid authentication code")
+	// ErrInvalidChallenge определяет ошибку, возникающую при неизвестном или истёкшем токене второго шага входа.
+	ErrInvalidChallenge = errors.New("invalid or expired login challenge")
+)
+
+// ITOTPService определяет интерфейс для сервиса двухфакторной аутентификации.
+type ITOTPService interface {
+	// Enable генерирует новый секрет TOTP, который вступит в силу после подтверждения кодом.
+	Enable(ctx context.Context, userID int) (*models.TOTPSetup, error)
+
+	// Confirm подтверждает секрет кодом из приложения-аутентификатора и выдаёт коды восстановления.
+	Confirm(ctx context.Context, userID int, code string) ([]string, error)
+
+	// Disable отключает второй фактор после проверки кода или кода восстановления.
+	Disable(ctx context.Context, userID int, code string) error
+
+	// IsEnabled проверяет, требуется ли пользователю второй фактор при входе.
+	IsEnabled(ctx context.Context, userID int) (bool, error)
+
+	// CreateChallenge создаёт токен второго шага входа для пользователя, подтвердившего пароль.
+	CreateChallenge(challenge auth.TOTPChallenge) (string, error)
+
+	// ParseChallenge проверяет токен второго шага входа и возвращает данные завершаемого входа.
+	ParseChallenge(token string) (*auth.TOTPChallenge, error)
+
+	// VerifyCode проверяет код второго фактора или код восстановления пользователя при входе.
+	VerifyCode(ctx context.Context, userID int, code string) error
+}
+
+// TOTPService предоставляет методы для управления вторым фактором и его проверки при входе.
+type TOTPService struct {
+	totpRepository repository.ITOTPRepository // totpRepository представляет репозиторий секретов TOTP и кодов восстановления.
+	userRepository repository.IUserRepository // userRepository используется для получения логина в ссылке otpauth://.
+	config         *config.Config             // config содержит ключ подписи токенов второго шага входа.
+}
+
+// NewTOTPService создаёт новый экземпляр TOTPService.
+func NewTOTPService(totpRepository repository.ITOTPRepository, userRepository repository.IUserRepository, config *config.Config) ITOTPService {
+	return &TOTPService{totpRepository: totpRepository, userRepository: userRepository, config: config}
+}
+
+// Enable генерирует секрет TOTP и сохраняет его как ожидающий подтверждения.
+// Возвращает ErrTOTPAlreadyEnabled, если второй фактор уже включён.
+func (s *TOTPService) Enable(ctx context.Context, userID int) (*models.TOTPSetup, error) {
+	totp, err := s.get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if totp.Enabled {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+
+	user, err := s.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate TOTP secret: %w", err)
+	}
+	if err = s.totpRepository.SetPending(ctx, userID, secret); err != nil {
+		return nil, fmt.Errorf("failed to save TOTP secret: %w", err)
+	}
+
+	return &models.TOTPSetup{
+		Secret: auth.EncodeTOTPSecret(secret),
+		URI:    auth.TOTPProvisioningURI(totpIssuer, user.Login, secret),
+	}, nil
+}
+
+// Confirm проверяет код для ожидающего подтверждения секрета и включает второй фактор.
+// Возвращает одноразовые коды восстановления, которые сервер больше не сможет показать.
+func (s *TOTPService) Confirm(ctx context.Context, userID int, code string) ([]string, error) {
+	totp, err := s.get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if totp.PendingSecret == nil {
+		return nil, ErrTOTPNotPending
+	}
+
+	step, ok := auth.ValidateTOTP(totp.PendingSecret, code, time.Now(), 0)
+	if !ok {
+		return nil, ErrInvalidTOTPCode
+	}
+
+	codes, hashes, err := auth.NewRecoveryCodes(recoveryCodesCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+	}
+
+	err = s.totpRepository.Enable(ctx, userID, step, hashes)
+	if errors.Is(err, gophKeeperErrors.ErrNotFound) {
+		return nil, ErrTOTPNotPending
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable TOTP: %w", err)
+	}
+
+	return codes, nil
+}
+
+// Disable отключает второй фактор. Для отключения требуется действующий код или код восстановления,
+// чтобы похищенный токен доступа не позволял снять защиту учётной записи.
+func (s *TOTPService) Disable(ctx context.Context, userID int, code string) error {
+	totp, err := s.get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !totp.Enabled {
+		return ErrTOTPNotEnabled
+	}
+
+	if err = s.verifyCode(ctx, totp, code); err != nil {
+		return err
+	}
+
+	if err = s.totpRepository.Disable(ctx, userID); err != nil {
+		return fmt.Errorf("failed to disable TOTP: %w", err)
+	}
+	return nil
+}
+
+// IsEnabled проверяет, включён ли у пользователя второй фактор.
+func (s *TOTPService) IsEnabled(ctx context.Context, userID int) (bool, error) {
+	totp, err := s.get(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return totp.Enabled, nil
+}
+
+// CreateChallenge создаёт подписанный токен второго шага входа, действующий totpChallengeTTL.
+func (s *TOTPService) CreateChallenge(challenge auth.TOTPChallenge) (string, error) {
+	token, err := auth.CreateChallengeToken(challenge, time.Now().Add(totpChallengeTTL), []byte(s.config.SecretKey))
+	if err != nil {
+		return "", fmt.Errorf("failed to create login challenge: %w", err)
+	}
+	return token, nil
+}
+
+// ParseChallenge проверяет подпись и срок действия токена второго шага входа.
+// Возвращает ErrInvalidChallenge для неизвестного или истёкшего токена.
+func (s *TOTPService) ParseChallenge(token string) (*auth.TOTPChallenge, error) {
+	challenge, err := auth.VerifyChallengeToken(token, []byte(s.config.SecretKey))
+	if err != nil {
+		return nil, ErrInvalidChallenge
+	}
+	return challenge, nil
+}
+
+// VerifyCode проверяет код TOTP или код восстановления пользователя и отмечает его использованным.
+// Возвращает ErrInvalidChallenge, если второй фактор был отключён после первого шага входа,
+// и ErrInvalidTOTPCode для неверного кода.
+func (s *TOTPService) VerifyCode(ctx context.Context, userID int, code string) error {
+	totp, err := s.get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !totp.Enabled {
+		return ErrInvalidChallenge
+	}
+
+	return s.verifyCode(ctx, totp, code)
+}
+
+// get возвращает настройки TOTP пользователя. Для пользователя без настроек возвращается выключенный второй фактор.
+func (s *TOTPService) get(ctx context.Context, userID int) (*models.TOTP, error) {
+	totp, err := s.totpRepository.Get(ctx, userID)
+	if errors.Is(err, gophKeeperErrors.ErrNotFound) {
+		return &models.TOTP{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get TOTP settings: %w", err)
+	}
+	return totp, nil
+}
+
+// verifyCode проверяет код TOTP или код восстановления и отмечает его использованным.
+func (s *TOTPService) verifyCode(ctx context.Context, totp *models.TOTP, code string) error {
+	code = strings.TrimSpace(code)
+
+	var err error
+	if isTOTPCode(code) {
+		step, ok := auth.ValidateTOTP(totp.Secret, code, time.Now(), totp.LastStep)
+		if !ok {
+			return ErrInvalidTOTPCode
+		}
+		err = s.totpRepository.UpdateStep(ctx, totp.UserID, step)
+	} else {
+		err = s.totpRepository.UseRecoveryCode(ctx, totp.UserID, auth.HashRecoveryCode(code))
+	}
+
+	if errors.Is(err, gophKeeperErrors.ErrNotFound) {
+		return ErrInvalidTOTPCode
+	}
+	if err != nil {
+		return fmt.Errorf("failed to verify authentication code: %w", err)
+	}
+	return nil
+}
+
+// isTOTPCode проверяет, что код состоит из шести цифр, а не является кодом восстановления.
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
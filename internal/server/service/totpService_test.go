@@ -0,0 +1,265 @@
+package service
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/auth"
+	"beliaev-aa/GophKeeper/internal/server/config"
+	"beliaev-aa/GophKeeper/internal/server/models"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/tests/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTOTPService_Enable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTOTPRepo := mocks.NewMockITOTPRepository(ctrl)
+	mockUserRepo := mocks.NewMockIUserRepository(ctrl)
+	svc := NewTOTPService(mockTOTPRepo, mockUserRepo, &config.Config{SecretKey: "secret"})
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		var pending []byte
+		mockTOTPRepo.EXPECT().Get(ctx, 1).Return(nil, gophKeeperErrors.ErrNotFound).Times(1)
+		mockUserRepo.EXPECT().GetUserByID(ctx, 1).Return(&models.User{ID: 1, Login: "alice"}, nil).Times(1)
+		mockTOTPRepo.EXPECT().SetPending(ctx, 1, gomock.Any()).DoAndReturn(func(_ context.Context, _ int, secret []byte) error {
+			pending = secret
+			return nil
+		}).Times(1)
+
+		setup, err := svc.Enable(ctx, 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, auth.EncodeTOTPSecret(pending), setup.Secret)
+		assert.True(t, strings.HasPrefix(setup.URI, "otpauth://totp/GophKeeper:alice?"))
+	})
+
+	t.Run("Already_Enabled", func(t *testing.T) {
+		mockTOTPRepo.EXPECT().Get(ctx, 1).Return(&models.TOTP{UserID: 1, Enabled: true}, nil).Times(1)
+
+		_, err := svc.Enable(ctx, 1)
+
+		assert.ErrorIs(t, err, ErrTOTPAlreadyEnabled)
+	})
+
+	t.Run("Repository_Error", func(t *testing.T) {
+		mockTOTPRepo.EXPECT().Get(ctx, 1).Return(nil, errors.New("db error")).Times(1)
+
+		_, err := svc.Enable(ctx, 1)
+
+		assert.EqualError(t, err, "failed to get TOTP settings: db error")
+	})
+}
+
+func TestTOTPService_Confirm(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTOTPRepo := mocks.NewMockITOTPRepository(ctrl)
+	svc := NewTOTPService(mockTOTPRepo, mocks.NewMockIUserRepository(ctrl), &config.Config{SecretKey: "secret"})
+	ctx := context.Background()
+	secret := []byte("12345678901234567890")
+	step := auth.TOTPStep(time.Now())
+
+	t.Run("Success", func(t *testing.T) {
+		var stored [][]byte
+		mockTOTPRepo.EXPECT().Get(ctx, 1).Return(&models.TOTP{UserID: 1, PendingSecret: secret}, nil).Times(1)
+		mockTOTPRepo.EXPECT().Enable(ctx, 1, step, gomock.Any()).DoAndReturn(func(_ context.Context, _ int, _ int64, hashes [][]byte) error {
+			stored = hashes
+			return nil
+		}).Times(1)
+
+		codes, err := svc.Confirm(ctx, 1, auth.TOTPCode(secret, step))
+
+		require.NoError(t, err)
+		require.Len(t, codes, recoveryCodesCount)
+		assert.Equal(t, auth.HashRecoveryCode(codes[0]), stored[0])
+	})
+
+	t.Run("Not_Pending", func(t *testing.T) {
+		mockTOTPRepo.EXPECT().Get(ctx, 1).Return(&models.TOTP{UserID: 1, Secret: secret, Enabled: true}, nil).Times(1)
+
+		_, err := svc.Confirm(ctx, 1, auth.TOTPCode(secret, step))
+
+		assert.ErrorIs(t, err, ErrTOTPNotPending)
+	})
+
+	t.Run("Invalid_Code", func(t *testing.T) {
+		mockTOTPRepo.EXPECT().Get(ctx, 1).Return(&models.TOTP{UserID: 1, PendingSecret: secret}, nil).Times(1)
+
+		_, err := svc.Confirm(ctx, 1, "000000x")
+
+		assert.ErrorIs(t, err, ErrInvalidTOTPCode)
+	})
+}
+
+func TestTOTPService_Disable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTOTPRepo := mocks.NewMockITOTPRepository(ctrl)
+	svc := NewTOTPService(mockTOTPRepo, mocks.NewMockIUserRepository(ctrl), &config.Config{SecretKey: "secret"})
+	ctx := context.Background()
+	secret := []byte("12345678901234567890")
+	step := auth.TOTPStep(time.Now())
+	enabled := &models.TOTP{UserID: 1, Secret: secret, Enabled: true, LastStep: step - 10}
+
+	tests := []struct {
+		name      string
+		code      string
+		setupMock func()
+		expectErr error
+	}{
+		{
+			name: "Success_With_TOTP",
+			code: auth.TOTPCode(secret, step),
+			setupMock: func() {
+				mockTOTPRepo.EXPECT().Get(ctx, 1).Return(enabled, nil).Times(1)
+				mockTOTPRepo.EXPECT().UpdateStep(ctx, 1, step).Return(nil).Times(1)
+				mockTOTPRepo.EXPECT().Disable(ctx, 1).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "Success_With_Recovery_Code",
+			code: "abcde-fghij",
+			setupMock: func() {
+				mockTOTPRepo.EXPECT().Get(ctx, 1).Return(enabled, nil).Times(1)
+				mockTOTPRepo.EXPECT().UseRecoveryCode(ctx, 1, auth.HashRecoveryCode("abcdefghij")).Return(nil).Times(1)
+				mockTOTPRepo.EXPECT().Disable(ctx, 1).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "Used_Recovery_Code",
+			code: "abcde-fghij",
+			setupMock: func() {
+				mockTOTPRepo.EXPECT().Get(ctx, 1).Return(enabled, nil).Times(1)
+				mockTOTPRepo.EXPECT().UseRecoveryCode(ctx, 1, gomock.Any()).Return(gophKeeperErrors.ErrNotFound).Times(1)
+			},
+			expectErr: ErrInvalidTOTPCode,
+		},
+		{
+			name: "Not_Enabled",
+			code: auth.TOTPCode(secret, step),
+			setupMock: func() {
+				mockTOTPRepo.EXPECT().Get(ctx, 1).Return(nil, gophKeeperErrors.ErrNotFound).Times(1)
+			},
+			expectErr: ErrTOTPNotEnabled,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMock()
+
+			err := svc.Disable(ctx, 1, tc.code)
+
+			if tc.expectErr != nil {
+				assert.ErrorIs(t, err, tc.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTOTPService_Challenge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{SecretKey: "secret"}
+	svc := NewTOTPService(mocks.NewMockITOTPRepository(ctrl), mocks.NewMockIUserRepository(ctrl), cfg)
+	expected := auth.TOTPChallenge{UserID: 1, Login: "alice", DeviceID: 42, DeviceName: "laptop"}
+
+	t.Run("Round_Trip", func(t *testing.T) {
+		token, err := svc.CreateChallenge(expected)
+		require.NoError(t, err)
+
+		challenge, err := svc.ParseChallenge(token)
+
+		require.NoError(t, err)
+		assert.Equal(t, expected, *challenge)
+	})
+
+	t.Run("Access_Token_As_Challenge", func(t *testing.T) {
+		accessToken, err := auth.CreateToken(1, 7, time.Now().Add(time.Minute), []byte(cfg.SecretKey))
+		require.NoError(t, err)
+
+		_, err = svc.ParseChallenge(accessToken)
+
+		assert.ErrorIs(t, err, ErrInvalidChallenge)
+	})
+}
+
+func TestTOTPService_VerifyCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTOTPRepo := mocks.NewMockITOTPRepository(ctrl)
+	svc := NewTOTPService(mockTOTPRepo, mocks.NewMockIUserRepository(ctrl), &config.Config{SecretKey: "secret"})
+	ctx := context.Background()
+	secret := []byte("12345678901234567890")
+	step := auth.TOTPStep(time.Now())
+	enabled := &models.TOTP{UserID: 1, Secret: secret, Enabled: true}
+
+	tests := []struct {
+		name      string
+		code      string
+		setupMock func()
+		expectErr error
+	}{
+		{
+			name: "Success",
+			code: auth.TOTPCode(secret, step),
+			setupMock: func() {
+				mockTOTPRepo.EXPECT().Get(ctx, 1).Return(enabled, nil).Times(1)
+				mockTOTPRepo.EXPECT().UpdateStep(ctx, 1, step).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "Replayed_Code",
+			code: auth.TOTPCode(secret, step),
+			setupMock: func() {
+				mockTOTPRepo.EXPECT().Get(ctx, 1).Return(enabled, nil).Times(1)
+				mockTOTPRepo.EXPECT().UpdateStep(ctx, 1, step).Return(gophKeeperErrors.ErrNotFound).Times(1)
+			},
+			expectErr: ErrInvalidTOTPCode,
+		},
+		{
+			name: "Wrong_Code",
+			code: auth.TOTPCode(secret, step+5),
+			setupMock: func() {
+				mockTOTPRepo.EXPECT().Get(ctx, 1).Return(enabled, nil).Times(1)
+			},
+			expectErr: ErrInvalidTOTPCode,
+		},
+		{
+			name: "TOTP_Disabled_Meanwhile",
+			code: auth.TOTPCode(secret, step),
+			setupMock: func() {
+				mockTOTPRepo.EXPECT().Get(ctx, 1).Return(nil, gophKeeperErrors.ErrNotFound).Times(1)
+			},
+			expectErr: ErrInvalidChallenge,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMock()
+
+			err := svc.VerifyCode(ctx, 1, tc.code)
+
+			if tc.expectErr != nil {
+				assert.ErrorIs(t, err, tc.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -3,43 +3,120 @@
 package service
 
 import (
+	"beliaev-aa/GophKeeper/internal/server/config"
 	"beliaev-aa/GophKeeper/internal/server/models"
 	"beliaev-aa/GophKeeper/internal/server/storage/repository"
 	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	pkgModels "beliaev-aa/GophKeeper/pkg/models"
 	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"golang.org/x/crypto/bcrypt"
+	"strings"
 )
 
-// ErrBadCredentials определяет ошибку, возникающую при неверных учетных данных для аутентификации.
-var ErrBadCredentials = errors.New("bad auth credentials")
+const (
+	// authHashLength определяет длину хэша аутентификации в hex-представлении (32 байта).
+	authHashLength = 64
+	// kdfSaltSize определяет размер соли, генерируемой для новых параметров KDF.
+	kdfSaltSize = 16
+)
+
+var (
+	// ErrBadCredentials определяет ошибку, возникающую при неверных учетных данных для аутентификации.
+	ErrBadCredentials = errors.New("bad auth credentials")
+	// ErrInvalidAuthHash определяет ошибку, возникающую, если клиент прислал хэш аутентификации неверного формата.
+	ErrInvalidAuthHash = errors.New("invalid auth hash")
+	// ErrReservedLogin определяет ошибку, возникающую при регистрации логина, зарезервированного
+	// для служебных учётных записей хранилищ организаций.
+	ErrReservedLogin = errors.New("login is reserved")
+	// ErrLegacyAuth определяет ошибку, возникающую при входе в учётную запись, которая ещё хранит
+	// хэш мастер-пароля. Для миграции клиент должен повторить вход, передав мастер-пароль.
+	ErrLegacyAuth = errors.New("legacy account requires password migration")
+	// ErrInvalidVaultKey определяет ошибку, возникающую, если клиент не передал ключ хранилища.
+	ErrInvalidVaultKey = errors.New("invalid vault key")
+	// ErrInvalidKeyPair определяет ошибку, возникающую, если клиент передал открытый ключ неверного размера
+	// или не передал закрытый ключ.
+	ErrInvalidKeyPair = errors.New("invalid key pair")
+	// ErrKDFUpgradeNotRequired определяет ошибку, возникающую при попытке перешифровать хранилище,
+	// параметры KDF которого уже соответствуют настроенному алгоритму.
+	ErrKDFUpgradeNotRequired = errors.New("kdf upgrade is not required")
+)
+
+// publicKeySize - размер открытого ключа X25519 в байтах.
+const publicKeySize = 32
 
 // IUserService определяет интерфейс для сервиса пользователей.
 type IUserService interface {
 	// RegisterUser регистрирует нового пользователя в системе.
-	RegisterUser(ctx context.Context, login string, password string) (*models.User, error)
+	RegisterUser(ctx context.Context, login string, authHash string, kdf *pkgModels.KDFParams) (*models.User, error)
+
+	// LoginUser аутентифицирует пользователя по логину и хэшу аутентификации.
+	LoginUser(ctx context.Context, login string, authHash string, legacyPassword string) (*models.User, error)
+
+	// PreLogin возвращает параметры KDF пользователя и признак необходимости перешифровать хранилище.
+	PreLogin(ctx context.Context, login string) (*pkgModels.KDFParams, bool, error)
+
+	// NewKDFParams генерирует параметры KDF со случайной солью для новой учётной записи или перешифрования.
+	NewKDFParams() (*pkgModels.KDFParams, error)
 
-	// LoginUser аутентифицирует пользователя по логину и паролю.
-	LoginUser(ctx context.Context, login string, password string) (*models.User, error)
+	// UpgradeKDF проверяет текущий хэш аутентификации и заменяет параметры KDF пользователя,
+	// атомарно сохраняя перешифрованные ключ хранилища и секреты.
+	UpgradeKDF(ctx context.Context, userID int, currentAuthHash string, authHash string, kdf *pkgModels.KDFParams, vaultKey []byte, secrets map[uint64]*pkgModels.Secret) error
+
+	// ChangePassword проверяет текущий хэш аутентификации и атомарно заменяет учётные данные,
+	// параметры KDF, ключ хранилища и данные секретов пользователя.
+	ChangePassword(ctx context.Context, userID int, currentAuthHash string, authHash string, kdf *pkgModels.KDFParams, vaultKey []byte, secrets map[uint64]*pkgModels.Secret) error
+
+	// GetVaultKey возвращает зашифрованный ключ хранилища пользователя или nil, если он ещё не создан.
+	GetVaultKey(ctx context.Context, userID int) ([]byte, error)
+
+	// CreateVaultKey сохраняет новый ключ хранилища вместе с секретами, перешифрованными ключами данных.
+	CreateVaultKey(ctx context.Context, userID int, vaultKey []byte, secrets map[uint64]*pkgModels.Secret) error
+
+	// GetKeyPair возвращает пару ключей пользователя; ключи пустые, если пара ещё не создана.
+	GetKeyPair(ctx context.Context, userID int) (*pkgModels.KeyPair, error)
+
+	// CreateKeyPair сохраняет пару ключей пользователя, через которую ему передают секреты.
+	CreateKeyPair(ctx context.Context, userID int, keyPair *pkgModels.KeyPair) error
+
+	// DeleteAccount проверяет хэш аутентификации и удаляет пользователя вместе со всеми его данными.
+	DeleteAccount(ctx context.Context, userID int, authHash string) error
 }
 
 // UserService предоставляет методы для регистрации и аутентификации пользователей.
 type UserService struct {
 	userRepository repository.IUserRepository // userRepository представляет репозиторий для работы с пользователями.
+	blobService    IBlobService               // blobService удаляет файлы пользователя вместе с учётной записью.
+	config         *config.Config             // config содержит алгоритм KDF и ключ для параметров несуществующих пользователей.
 }
 
 // NewUserService создает новый экземпляр UserService с использованием заданного репозитория пользователей.
-func NewUserService(userRepository repository.IUserRepository) IUserService {
-	return &UserService{userRepository: userRepository}
+func NewUserService(userRepository repository.IUserRepository, blobService IBlobService, config *config.Config) IUserService {
+	return &UserService{userRepository: userRepository, blobService: blobService, config: config}
 }
 
 // RegisterUser регистрирует нового пользователя в системе.
-// Принимает контекст, логин и пароль. Возвращает зарегистрированного пользователя или ошибку.
-func (s *UserService) RegisterUser(ctx context.Context, login string, password string) (*models.User, error) {
+// Принимает контекст, логин, хэш аутентификации и параметры KDF, с которыми он был выведен.
+// Возвращает зарегистрированного пользователя или ошибку.
+func (s *UserService) RegisterUser(ctx context.Context, login string, authHash string, kdf *pkgModels.KDFParams) (*models.User, error) {
 	var newUser models.User
 
+	if strings.HasPrefix(login, models.VaultLoginPrefix) {
+		return nil, ErrReservedLogin
+	}
+	if !isValidAuthHash(authHash) {
+		return nil, ErrInvalidAuthHash
+	}
+	if err := kdf.Validate(); err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepository.GetUserByLogin(ctx, login)
 	if err != nil && !errors.Is(err, gophKeeperErrors.ErrNotFound) {
 		return nil, fmt.Errorf("failed to fetch user: %w", err)
@@ -48,12 +125,12 @@ func (s *UserService) RegisterUser(ctx context.Context, login string, password s
 		return nil, fmt.Errorf("user already exists (%s)", login)
 	}
 
-	hashedPassword, err := s.hashPassword(password)
+	hashedPassword, err := s.hashPassword(authHash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate password hash: %w", err)
 	}
 
-	newUser = models.User{Login: login, Password: hashedPassword}
+	newUser = models.User{Login: login, Password: hashedPassword, AuthVersion: models.AuthVersionHash, KDF: kdf}
 
 	newUserID, err := s.userRepository.Create(ctx, newUser)
 	if err != nil {
@@ -64,22 +141,244 @@ func (s *UserService) RegisterUser(ctx context.Context, login string, password s
 	return &newUser, nil
 }
 
-// LoginUser аутентифицирует пользователя по логину и паролю.
+// LoginUser аутентифицирует пользователя по логину и хэшу аутентификации.
+// Для учётных записей, созданных до перехода на хэш аутентификации, требуется legacyPassword:
+// после его проверки сохранённый хэш заменяется хэшем от authHash. Без него возвращается ErrLegacyAuth.
+// ErrLegacyAuth возвращается и учётным записям, уже перешедшим на хэш аутентификации, но ещё не получившим
+// параметры KDF: PreLogin не сообщает им параметры, выведенные из логина, поэтому клиент узнаёт о них только здесь.
 // Возвращает пользователя или ошибку, если аутентификация не удалась.
-func (s *UserService) LoginUser(ctx context.Context, login string, password string) (*models.User, error) {
+func (s *UserService) LoginUser(ctx context.Context, login string, authHash string, legacyPassword string) (*models.User, error) {
+	if !isValidAuthHash(authHash) {
+		return nil, ErrInvalidAuthHash
+	}
+
 	user, err := s.userRepository.GetUserByLogin(ctx, login)
-	if errors.Is(err, sql.ErrNoRows) {
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, gophKeeperErrors.ErrNotFound) {
 		return nil, ErrBadCredentials
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to authenticate user: %w", err)
 	}
-	if !s.comparePassword(user.Password, password) {
+
+	if user.AuthVersion == models.AuthVersionLegacy || user.KDF == nil {
+		return s.migrateLegacyUser(ctx, user, authHash, legacyPassword)
+	}
+
+	if !s.comparePassword(user.Password, authHash) {
 		return nil, ErrBadCredentials
 	}
 	return user, nil
 }
 
+// migrateLegacyUser проверяет мастер-пароль пользователя со старой схемой хранения
+// и заменяет сохранённый хэш хэшем от authHash. Если хэш уже заменён, а параметры KDF ещё не обновлены,
+// проверяется authHash.
+func (s *UserService) migrateLegacyUser(ctx context.Context, user *models.User, authHash, legacyPassword string) (*models.User, error) {
+	if legacyPassword == "" {
+		return nil, ErrLegacyAuth
+	}
+	if user.AuthVersion == models.AuthVersionHash {
+		if !s.comparePassword(user.Password, authHash) {
+			return nil, ErrBadCredentials
+		}
+		return user, nil
+	}
+	if !s.comparePassword(user.Password, legacyPassword) {
+		return nil, ErrBadCredentials
+	}
+
+	hashedPassword, err := s.hashPassword(authHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate password hash: %w", err)
+	}
+
+	err = s.userRepository.UpdateCredentials(ctx, user.ID, hashedPassword, models.AuthVersionHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate user credentials: %w", err)
+	}
+
+	user.Password = hashedPassword
+	user.AuthVersion = models.AuthVersionHash
+	return user, nil
+}
+
+// PreLogin возвращает параметры KDF пользователя по логину и признак того, что хранилище
+// нужно перешифровать: для учётных записей с алгоритмом, отличным от настроенного, возвращаются текущие параметры.
+// Для несуществующего логина и для учётных записей с устаревшей схемой без параметров KDF возвращаются
+// одинаковые детерминированные фиктивные параметры, чтобы ответ не позволял определить, зарегистрирован ли логин
+// и использует ли он устаревшую схему. О переходе с устаревшей схемы клиент узнаёт из ошибки входа.
+func (s *UserService) PreLogin(ctx context.Context, login string) (*pkgModels.KDFParams, bool, error) {
+	user, err := s.userRepository.GetUserByLogin(ctx, login)
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, gophKeeperErrors.ErrNotFound) {
+		return s.decoyKDFParams(login), false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch user: %w", err)
+	}
+	if user.KDF == nil {
+		return s.decoyKDFParams(login), false, nil
+	}
+	return user.KDF, user.KDF.Algorithm != s.config.KDFAlgorithm, nil
+}
+
+// NewKDFParams генерирует параметры KDF для настроенного алгоритма со случайной солью.
+func (s *UserService) NewKDFParams() (*pkgModels.KDFParams, error) {
+	salt := make([]byte, kdfSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate kdf salt: %w", err)
+	}
+	return defaultKDFParams(s.config.KDFAlgorithm, salt), nil
+}
+
+// UpgradeKDF переводит хранилище пользователя на настроенный алгоритм KDF. Клиент подтверждает
+// перешифрование хэшем аутентификации, выведенным с текущими параметрами, и передаёт новые параметры KDF
+// с хэшем аутентификации, выведенным с ними, ключ хранилища и секреты, перешифрованные новым ключом.
+// Изменения применяются атомарно.
+// Возвращает ErrBadCredentials, если текущий хэш аутентификации не совпадает с сохранённым,
+// и ErrKDFUpgradeNotRequired, если параметры KDF пользователя уже соответствуют настроенному алгоритму.
+func (s *UserService) UpgradeKDF(ctx context.Context, userID int, currentAuthHash string, authHash string, kdf *pkgModels.KDFParams, vaultKey []byte, secrets map[uint64]*pkgModels.Secret) error {
+	user, err := s.verifyAuthHash(ctx, userID, currentAuthHash)
+	if err != nil {
+		return err
+	}
+	if user.KDF != nil && user.KDF.Algorithm == s.config.KDFAlgorithm {
+		return ErrKDFUpgradeNotRequired
+	}
+	if kdf != nil && kdf.Algorithm != s.config.KDFAlgorithm {
+		return fmt.Errorf("%w: expected %s algorithm", pkgModels.ErrInvalidKDFParams, s.config.KDFAlgorithm)
+	}
+
+	if err = s.rekey(ctx, userID, authHash, kdf, vaultKey, secrets); err != nil {
+		return fmt.Errorf("failed to upgrade kdf: %w", err)
+	}
+	return nil
+}
+
+// ChangePassword заменяет мастер-пароль пользователя. Сервер не знает ни старого, ни нового пароля:
+// клиент подтверждает смену хэшем аутентификации от текущего пароля и передаёт хэш от нового пароля,
+// новые параметры KDF, ключ хранилища, зашифрованный новым ключом, и секреты без ключа данных,
+// перешифрованные новым ключом. Изменения применяются атомарно.
+// Возвращает ErrBadCredentials, если текущий хэш аутентификации не совпадает с сохранённым.
+func (s *UserService) ChangePassword(ctx context.Context, userID int, currentAuthHash string, authHash string, kdf *pkgModels.KDFParams, vaultKey []byte, secrets map[uint64]*pkgModels.Secret) error {
+	if _, err := s.verifyAuthHash(ctx, userID, currentAuthHash); err != nil {
+		return err
+	}
+
+	if err := s.rekey(ctx, userID, authHash, kdf, vaultKey, secrets); err != nil {
+		return fmt.Errorf("failed to change password: %w", err)
+	}
+	return nil
+}
+
+// GetVaultKey возвращает ключ хранилища пользователя, зашифрованный ключом из мастер-пароля,
+// или nil, если ключ хранилища ещё не создан.
+func (s *UserService) GetVaultKey(ctx context.Context, userID int) ([]byte, error) {
+	vaultKey, err := s.userRepository.GetVaultKey(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vault key: %w", err)
+	}
+	return vaultKey, nil
+}
+
+// CreateVaultKey сохраняет ключ хранилища пользователя, зашифрованный ключом из мастер-пароля, вместе
+// с секретами, перешифрованными собственными ключами данных. Версии секретов без ключа данных удаляются.
+// Изменения применяются атомарно.
+// Возвращает ErrVaultKeyExists, если ключ хранилища уже создан, например, с другого устройства.
+func (s *UserService) CreateVaultKey(ctx context.Context, userID int, vaultKey []byte, secrets map[uint64]*pkgModels.Secret) error {
+	if len(vaultKey) == 0 {
+		return ErrInvalidVaultKey
+	}
+	if err := s.userRepository.CreateVaultKey(ctx, userID, vaultKey, secrets); err != nil {
+		return fmt.Errorf("failed to create vault key: %w", err)
+	}
+	return nil
+}
+
+// GetKeyPair возвращает пару ключей пользователя: открытый ключ и закрытый ключ, зашифрованный
+// ключом хранилища. Если пара ещё не создана, ключи пустые.
+func (s *UserService) GetKeyPair(ctx context.Context, userID int) (*pkgModels.KeyPair, error) {
+	keyPair, err := s.userRepository.GetKeyPair(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key pair: %w", err)
+	}
+	return keyPair, nil
+}
+
+// CreateKeyPair сохраняет пару ключей пользователя. Возвращает ErrInvalidKeyPair, если открытый ключ
+// неверного размера или закрытый ключ пустой, и ErrKeyPairExists, если пара уже создана,
+// например, с другого устройства.
+func (s *UserService) CreateKeyPair(ctx context.Context, userID int, keyPair *pkgModels.KeyPair) error {
+	if len(keyPair.PublicKey) != publicKeySize || len(keyPair.PrivateKey) == 0 {
+		return ErrInvalidKeyPair
+	}
+	if err := s.userRepository.CreateKeyPair(ctx, userID, keyPair); err != nil {
+		return fmt.Errorf("failed to create key pair: %w", err)
+	}
+	return nil
+}
+
+// DeleteAccount удаляет учётную запись пользователя после повторной проверки мастер-пароля
+// по хэшу аутентификации. Все секреты, файлы и сессии пользователя удаляются вместе с ней,
+// как и хранилища организаций, в которых не осталось других участников.
+// Возвращает ErrBadCredentials, если хэш аутентификации не совпадает с сохранённым, и ErrLastOwner,
+// если пользователь - единственный владелец организации с другими участниками: сначала владельцем
+// нужно назначить другого участника.
+func (s *UserService) DeleteAccount(ctx context.Context, userID int, authHash string) error {
+	if _, err := s.verifyAuthHash(ctx, userID, authHash); err != nil {
+		return err
+	}
+
+	vaultIDs, err := s.userRepository.Delete(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+	for _, vaultID := range vaultIDs {
+		if err = s.blobService.DeleteUserBlobs(ctx, vaultID); err != nil {
+			return err
+		}
+	}
+	return s.blobService.DeleteUserBlobs(ctx, uint64(userID))
+}
+
+// verifyAuthHash сверяет хэш аутентификации с сохранённым хэшем учётных данных пользователя
+// и возвращает пользователя. Возвращает ErrBadCredentials при несовпадении.
+func (s *UserService) verifyAuthHash(ctx context.Context, userID int, authHash string) (*models.User, error) {
+	user, err := s.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user: %w", err)
+	}
+	if user.AuthVersion != models.AuthVersionHash || !s.comparePassword(user.Password, authHash) {
+		return nil, ErrBadCredentials
+	}
+	return user, nil
+}
+
+// rekey проверяет новые параметры KDF и хэш аутентификации, выведенный с ними,
+// и атомарно сохраняет их вместе с ключом хранилища и секретами, перешифрованными новым ключом.
+func (s *UserService) rekey(ctx context.Context, userID int, authHash string, kdf *pkgModels.KDFParams, vaultKey []byte, secrets map[uint64]*pkgModels.Secret) error {
+	if !isValidAuthHash(authHash) {
+		return ErrInvalidAuthHash
+	}
+	if err := kdf.Validate(); err != nil {
+		return err
+	}
+
+	hashedPassword, err := s.hashPassword(authHash)
+	if err != nil {
+		return fmt.Errorf("failed to generate password hash: %w", err)
+	}
+
+	return s.userRepository.Rekey(ctx, userID, hashedPassword, kdf, vaultKey, secrets)
+}
+
+// decoyKDFParams формирует фиктивные параметры KDF для несуществующего логина или учётной записи без параметров KDF.
+// Соль выводится из логина и секретного ключа сервера, поэтому повторные запросы получают одинаковый ответ.
+func (s *UserService) decoyKDFParams(login string) *pkgModels.KDFParams {
+	mac := hmac.New(sha256.New, []byte(s.config.SecretKey))
+	mac.Write([]byte("kdf-decoy:" + login))
+	return defaultKDFParams(s.config.KDFAlgorithm, mac.Sum(nil)[:kdfSaltSize])
+}
+
 // hashPassword хэширует пароль с использованием bcrypt.
 func (s *UserService) hashPassword(password string) (string, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -95,3 +394,32 @@ func (s *UserService) comparePassword(hash, password string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
+
+// isValidAuthHash проверяет, что хэш аутентификации имеет ожидаемую длину и hex-кодировку.
+func isValidAuthHash(authHash string) bool {
+	if len(authHash) != authHashLength {
+		return false
+	}
+	_, err := hex.DecodeString(authHash)
+	return err == nil
+}
+
+// defaultKDFParams возвращает параметры стоимости по умолчанию для заданного алгоритма и соли.
+func defaultKDFParams(algorithm pkgModels.KDFAlgorithm, salt []byte) *pkgModels.KDFParams {
+	if algorithm == pkgModels.KDFScrypt {
+		return &pkgModels.KDFParams{
+			Algorithm: pkgModels.KDFScrypt,
+			Salt:      salt,
+			ScryptN:   1 << 15,
+			ScryptR:   8,
+			ScryptP:   1,
+		}
+	}
+	return &pkgModels.KDFParams{
+		Algorithm:     pkgModels.KDFArgon2id,
+		Salt:          salt,
+		Argon2Memory:  64 * 1024,
+		Argon2Time:    3,
+		Argon2Threads: 4,
+	}
+}
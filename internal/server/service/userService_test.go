@@ -1,23 +1,42 @@
 package service
 
 import (
+	"beliaev-aa/GophKeeper/internal/server/config"
 	"beliaev-aa/GophKeeper/internal/server/models"
+	"beliaev-aa/GophKeeper/internal/server/storage/repository"
 	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	pkgModels "beliaev-aa/GophKeeper/pkg/models"
 	"beliaev-aa/GophKeeper/tests/mocks"
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/golang/mock/gomock"
 	"golang.org/x/crypto/bcrypt"
+	"reflect"
 	"testing"
 )
 
+var testKDFParams = &pkgModels.KDFParams{
+	Algorithm:     pkgModels.KDFArgon2id,
+	Salt:          []byte("0123456789abcdef"),
+	Argon2Memory:  64 * 1024,
+	Argon2Time:    3,
+	Argon2Threads: 4,
+}
+
+const (
+	testAuthHash      = "0f0e0d0c0b0a09080706050403020100f0e0d0c0b0a090807060504030201000"
+	testWrongAuthHash = "1111111111111111111111111111111111111111111111111111111111111111"
+)
+
 func TestUserService(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockIUserRepository(ctrl)
-	svc := NewUserService(mockRepo)
+	mockBlobService := mocks.NewMockIBlobService(ctrl)
+	svc := NewUserService(mockRepo, mockBlobService, &config.Config{SecretKey: "secret", KDFAlgorithm: pkgModels.KDFArgon2id})
 
 	ctx := context.Background()
 	tests := []struct {
@@ -31,13 +50,19 @@ func TestUserService(t *testing.T) {
 				mockRepo.EXPECT().GetUserByLogin(ctx, "new_user").Return(nil, gophKeeperErrors.ErrNotFound).Times(1)
 				mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(1, nil).Times(1)
 
-				user, err := svc.RegisterUser(ctx, "new_user", "password123")
+				user, err := svc.RegisterUser(ctx, "new_user", testAuthHash, testKDFParams)
 				if err != nil {
 					t.Errorf("Expected no error, got %v", err)
 				}
 				if user.Login != "new_user" {
 					t.Errorf("Expected login 'newuser', got %v", user.Login)
 				}
+				if user.AuthVersion != models.AuthVersionHash {
+					t.Errorf("Expected auth version %d, got %v", models.AuthVersionHash, user.AuthVersion)
+				}
+				if user.KDF != testKDFParams {
+					t.Errorf("Expected kdf params to be stored, got %v", user.KDF)
+				}
 			},
 			expectErr: false,
 		},
@@ -47,7 +72,7 @@ func TestUserService(t *testing.T) {
 				mockRepo.EXPECT().GetUserByLogin(ctx, "new_user").Return(nil, gophKeeperErrors.ErrNotFound).Times(1)
 				mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(1, errors.New("some error")).Times(1)
 
-				_, err := svc.RegisterUser(ctx, "new_user", "password123")
+				_, err := svc.RegisterUser(ctx, "new_user", testAuthHash, testKDFParams)
 				if err == nil || err.Error() != "failed to create user: some error" {
 					t.Errorf("Expected error 'failed to create user: some error', got %v", err)
 				}
@@ -59,7 +84,7 @@ func TestUserService(t *testing.T) {
 			testFunc: func(t *testing.T) {
 				mockRepo.EXPECT().GetUserByLogin(ctx, "existing_user").Return(&models.User{Login: "existing_user"}, nil).Times(1)
 
-				_, err := svc.RegisterUser(ctx, "existing_user", "password123")
+				_, err := svc.RegisterUser(ctx, "existing_user", testAuthHash, testKDFParams)
 				if err == nil || err.Error() != "user already exists (existing_user)" {
 					t.Errorf("Expected error 'user already exists (existing_user)', got %v", err)
 				}
@@ -71,7 +96,7 @@ func TestUserService(t *testing.T) {
 			testFunc: func(t *testing.T) {
 				mockRepo.EXPECT().GetUserByLogin(ctx, "existing_user").Return(nil, errors.New("some error")).Times(1)
 
-				_, err := svc.RegisterUser(ctx, "existing_user", "password123")
+				_, err := svc.RegisterUser(ctx, "existing_user", testAuthHash, testKDFParams)
 				if err == nil || err.Error() != "failed to fetch user: some error" {
 					t.Errorf("Expected error 'failed to fetch user: some error', got %v", err)
 				}
@@ -81,10 +106,10 @@ func TestUserService(t *testing.T) {
 		{
 			name: "LoginUser_Success",
 			testFunc: func(t *testing.T) {
-				hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
-				mockRepo.EXPECT().GetUserByLogin(ctx, "valid_user").Return(&models.User{Login: "valid_user", Password: string(hashedPassword)}, nil).Times(1)
+				hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(testAuthHash), bcrypt.DefaultCost)
+				mockRepo.EXPECT().GetUserByLogin(ctx, "valid_user").Return(&models.User{Login: "valid_user", Password: string(hashedPassword), AuthVersion: models.AuthVersionHash, KDF: testKDFParams}, nil).Times(1)
 
-				user, err := svc.LoginUser(ctx, "valid_user", "password123")
+				user, err := svc.LoginUser(ctx, "valid_user", testAuthHash, "")
 				if err != nil {
 					t.Errorf("Expected no error, got %v", err)
 				}
@@ -97,10 +122,10 @@ func TestUserService(t *testing.T) {
 		{
 			name: "LoginUser_Fail_WrongPassword",
 			testFunc: func(t *testing.T) {
-				hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
-				mockRepo.EXPECT().GetUserByLogin(ctx, "valid_user").Return(&models.User{Login: "valid_user", Password: string(hashedPassword)}, nil).Times(1)
+				hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(testAuthHash), bcrypt.DefaultCost)
+				mockRepo.EXPECT().GetUserByLogin(ctx, "valid_user").Return(&models.User{Login: "valid_user", Password: string(hashedPassword), AuthVersion: models.AuthVersionHash, KDF: testKDFParams}, nil).Times(1)
 
-				_, err := svc.LoginUser(ctx, "valid_user", "wrong_password")
+				_, err := svc.LoginUser(ctx, "valid_user", testWrongAuthHash, "")
 				if err == nil || !errors.Is(err, ErrBadCredentials) {
 					t.Errorf("Expected error 'bad auth credentials', got %v", err)
 				}
@@ -110,10 +135,10 @@ func TestUserService(t *testing.T) {
 		{
 			name: "LoginUser_Fail_Authenticate",
 			testFunc: func(t *testing.T) {
-				_, _ = bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+				_, _ = bcrypt.GenerateFromPassword([]byte(testAuthHash), bcrypt.DefaultCost)
 				mockRepo.EXPECT().GetUserByLogin(ctx, "valid_user").Return(nil, errors.New("some error")).Times(1)
 
-				_, err := svc.LoginUser(ctx, "valid_user", "wrong_password")
+				_, err := svc.LoginUser(ctx, "valid_user", testWrongAuthHash, "")
 				if err == nil || err.Error() != "failed to authenticate user: some error" {
 					t.Errorf("Expected error 'failed to authenticate user: some error', got %v", err)
 				}
@@ -125,13 +150,551 @@ func TestUserService(t *testing.T) {
 			testFunc: func(t *testing.T) {
 				mockRepo.EXPECT().GetUserByLogin(ctx, "nonexistent_user").Return(nil, sql.ErrNoRows).Times(1)
 
-				_, err := svc.LoginUser(ctx, "nonexistent_user", "password123")
+				_, err := svc.LoginUser(ctx, "nonexistent_user", testAuthHash, "")
 				if err == nil || !errors.Is(err, ErrBadCredentials) {
 					t.Errorf("Expected error 'bad auth credentials', got %v", err)
 				}
 			},
 			expectErr: true,
 		},
+		{
+			name: "LoginUser_Fail_UserNotFound_Repository",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().GetUserByLogin(ctx, "nonexistent_user").Return(nil, gophKeeperErrors.ErrNotFound).Times(1)
+
+				_, err := svc.LoginUser(ctx, "nonexistent_user", testAuthHash, "")
+				if err == nil || !errors.Is(err, ErrBadCredentials) {
+					t.Errorf("Expected error 'bad auth credentials', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "LoginUser_Fail_InvalidAuthHash",
+			testFunc: func(t *testing.T) {
+				_, err := svc.LoginUser(ctx, "valid_user", "password123", "")
+				if !errors.Is(err, ErrInvalidAuthHash) {
+					t.Errorf("Expected error 'invalid auth hash', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "RegisterUser_Fail_ReservedLogin",
+			testFunc: func(t *testing.T) {
+				_, err := svc.RegisterUser(ctx, "org:0123456789abcdef", testAuthHash, testKDFParams)
+				if !errors.Is(err, ErrReservedLogin) {
+					t.Errorf("Expected error 'login is reserved', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "RegisterUser_Fail_InvalidAuthHash",
+			testFunc: func(t *testing.T) {
+				_, err := svc.RegisterUser(ctx, "new_user", "zz", testKDFParams)
+				if !errors.Is(err, ErrInvalidAuthHash) {
+					t.Errorf("Expected error 'invalid auth hash', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "LoginUser_Fail_LegacyWithoutPassword",
+			testFunc: func(t *testing.T) {
+				hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("master"), bcrypt.MinCost)
+				mockRepo.EXPECT().GetUserByLogin(ctx, "legacy_user").Return(&models.User{ID: 1, Login: "legacy_user", Password: string(hashedPassword)}, nil).Times(1)
+
+				_, err := svc.LoginUser(ctx, "legacy_user", testAuthHash, "")
+				if !errors.Is(err, ErrLegacyAuth) {
+					t.Errorf("Expected error 'legacy account requires password migration', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "LoginUser_Fail_LegacyWrongPassword",
+			testFunc: func(t *testing.T) {
+				hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("master"), bcrypt.MinCost)
+				mockRepo.EXPECT().GetUserByLogin(ctx, "legacy_user").Return(&models.User{ID: 1, Login: "legacy_user", Password: string(hashedPassword)}, nil).Times(1)
+
+				_, err := svc.LoginUser(ctx, "legacy_user", testAuthHash, "wrong")
+				if !errors.Is(err, ErrBadCredentials) {
+					t.Errorf("Expected error 'bad auth credentials', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "LoginUser_Success_LegacyMigration",
+			testFunc: func(t *testing.T) {
+				hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("master"), bcrypt.MinCost)
+				mockRepo.EXPECT().GetUserByLogin(ctx, "legacy_user").Return(&models.User{ID: 1, Login: "legacy_user", Password: string(hashedPassword)}, nil).Times(1)
+				mockRepo.EXPECT().UpdateCredentials(ctx, 1, gomock.Any(), models.AuthVersionHash).
+					DoAndReturn(func(_ context.Context, _ int, hash string, _ int) error {
+						if bcrypt.CompareHashAndPassword([]byte(hash), []byte(testAuthHash)) != nil {
+							t.Errorf("Expected stored hash of auth hash")
+						}
+						return nil
+					}).Times(1)
+
+				user, err := svc.LoginUser(ctx, "legacy_user", testAuthHash, "master")
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if user == nil || user.AuthVersion != models.AuthVersionHash {
+					t.Errorf("Expected migrated user, got %v", user)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "LoginUser_Fail_MigratedWithoutKDFParams",
+			testFunc: func(t *testing.T) {
+				hashedAuth, _ := bcrypt.GenerateFromPassword([]byte(testAuthHash), bcrypt.MinCost)
+				mockRepo.EXPECT().GetUserByLogin(ctx, "legacy_user").Return(&models.User{ID: 1, Login: "legacy_user", Password: string(hashedAuth), AuthVersion: models.AuthVersionHash}, nil).Times(1)
+
+				if _, err := svc.LoginUser(ctx, "legacy_user", testAuthHash, ""); !errors.Is(err, ErrLegacyAuth) {
+					t.Errorf("Expected error 'legacy account requires password migration', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "LoginUser_Success_MigratedWithoutKDFParams",
+			testFunc: func(t *testing.T) {
+				hashedAuth, _ := bcrypt.GenerateFromPassword([]byte(testAuthHash), bcrypt.MinCost)
+				mockRepo.EXPECT().GetUserByLogin(ctx, "legacy_user").Return(&models.User{ID: 1, Login: "legacy_user", Password: string(hashedAuth), AuthVersion: models.AuthVersionHash}, nil).Times(1)
+
+				if _, err := svc.LoginUser(ctx, "legacy_user", testAuthHash, "master"); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "LoginUser_Fail_LegacyMigrationError",
+			testFunc: func(t *testing.T) {
+				hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("master"), bcrypt.MinCost)
+				mockRepo.EXPECT().GetUserByLogin(ctx, "legacy_user").Return(&models.User{ID: 1, Login: "legacy_user", Password: string(hashedPassword)}, nil).Times(1)
+				mockRepo.EXPECT().UpdateCredentials(ctx, 1, gomock.Any(), models.AuthVersionHash).Return(errors.New("some error")).Times(1)
+
+				_, err := svc.LoginUser(ctx, "legacy_user", testAuthHash, "master")
+				if err == nil || err.Error() != "failed to migrate user credentials: some error" {
+					t.Errorf("Expected error 'failed to migrate user credentials: some error', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "RegisterUser_Fail_InvalidKDFParams",
+			testFunc: func(t *testing.T) {
+				_, err := svc.RegisterUser(ctx, "new_user", testAuthHash, &pkgModels.KDFParams{Algorithm: pkgModels.KDFScrypt, Salt: []byte("salt")})
+				if !errors.Is(err, pkgModels.ErrInvalidKDFParams) {
+					t.Errorf("Expected error 'invalid kdf params', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "PreLogin_Success",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().GetUserByLogin(ctx, "valid_user").Return(&models.User{Login: "valid_user", KDF: testKDFParams}, nil).Times(1)
+
+				kdf, upgrade, err := svc.PreLogin(ctx, "valid_user")
+				if err != nil || kdf != testKDFParams || upgrade {
+					t.Errorf("Expected stored kdf params without upgrade, got %v, %v, %v", kdf, upgrade, err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "PreLogin_Legacy",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().GetUserByLogin(ctx, "legacy_user").Return(&models.User{Login: "legacy_user"}, nil).Times(1)
+
+				mockRepo.EXPECT().GetUserByLogin(ctx, "legacy_user").Return(nil, gophKeeperErrors.ErrNotFound).Times(1)
+
+				kdf, upgrade, err := svc.PreLogin(ctx, "legacy_user")
+				if err != nil || upgrade || kdf.Validate() != nil {
+					t.Fatalf("Expected decoy kdf params without upgrade, got %v, %v, %v", kdf, upgrade, err)
+				}
+				unknown, upgrade, _ := svc.PreLogin(ctx, "legacy_user")
+				if upgrade || !reflect.DeepEqual(kdf, unknown) {
+					t.Errorf("Expected legacy account to look like an unknown login, got %v and %v", kdf, unknown)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "PreLogin_OutdatedAlgorithm",
+			testFunc: func(t *testing.T) {
+				scrypt := &pkgModels.KDFParams{Algorithm: pkgModels.KDFScrypt, Salt: []byte("0123456789abcdef"), ScryptN: 1 << 15, ScryptR: 8, ScryptP: 1}
+				mockRepo.EXPECT().GetUserByLogin(ctx, "valid_user").Return(&models.User{Login: "valid_user", KDF: scrypt}, nil).Times(1)
+
+				kdf, upgrade, err := svc.PreLogin(ctx, "valid_user")
+				if err != nil || kdf != scrypt || !upgrade {
+					t.Errorf("Expected stored kdf params with upgrade, got %v, %v, %v", kdf, upgrade, err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "PreLogin_UnknownLogin",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().GetUserByLogin(ctx, "nonexistent_user").Return(nil, gophKeeperErrors.ErrNotFound).Times(2)
+				mockRepo.EXPECT().GetUserByLogin(ctx, "another_user").Return(nil, gophKeeperErrors.ErrNotFound).Times(1)
+
+				first, upgrade, err := svc.PreLogin(ctx, "nonexistent_user")
+				if err != nil || upgrade || first.Validate() != nil {
+					t.Fatalf("Expected valid decoy kdf params, got %v, %v, %v", first, upgrade, err)
+				}
+				second, _, _ := svc.PreLogin(ctx, "nonexistent_user")
+				other, _, _ := svc.PreLogin(ctx, "another_user")
+				if string(first.Salt) != string(second.Salt) {
+					t.Errorf("Expected decoy params to be stable for the same login")
+				}
+				if string(first.Salt) == string(other.Salt) {
+					t.Errorf("Expected decoy params to differ between logins")
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "PreLogin_Fail_Fetch_User",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().GetUserByLogin(ctx, "valid_user").Return(nil, errors.New("some error")).Times(1)
+
+				_, _, err := svc.PreLogin(ctx, "valid_user")
+				if err == nil || err.Error() != "failed to fetch user: some error" {
+					t.Errorf("Expected error 'failed to fetch user: some error', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "NewKDFParams_Success",
+			testFunc: func(t *testing.T) {
+				first, err := svc.NewKDFParams()
+				if err != nil || first.Validate() != nil || first.Algorithm != pkgModels.KDFArgon2id {
+					t.Fatalf("Expected valid argon2id params, got %v, %v", first, err)
+				}
+				second, _ := svc.NewKDFParams()
+				if string(first.Salt) == string(second.Salt) {
+					t.Errorf("Expected random salt")
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "UpgradeKDF_Success",
+			testFunc: func(t *testing.T) {
+				hashed, _ := bcrypt.GenerateFromPassword([]byte(testAuthHash), bcrypt.MinCost)
+				payloads := map[uint64]*pkgModels.Secret{1: {ID: 1, Payload: []byte("payload")}}
+				mockRepo.EXPECT().GetUserByID(ctx, 1).Return(&models.User{ID: 1, Password: string(hashed), AuthVersion: models.AuthVersionHash}, nil).Times(1)
+				mockRepo.EXPECT().Rekey(ctx, 1, gomock.Any(), testKDFParams, []byte("vault_key"), payloads).
+					DoAndReturn(func(_ context.Context, _ int, password string, _ *pkgModels.KDFParams, _ []byte, _ map[uint64]*pkgModels.Secret) error {
+						if bcrypt.CompareHashAndPassword([]byte(password), []byte(testWrongAuthHash)) != nil {
+							t.Errorf("Expected new auth hash to be stored")
+						}
+						return nil
+					}).Times(1)
+
+				err := svc.UpgradeKDF(ctx, 1, testAuthHash, testWrongAuthHash, testKDFParams, []byte("vault_key"), payloads)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "UpgradeKDF_Fail_WrongCurrentAuthHash",
+			testFunc: func(t *testing.T) {
+				hashed, _ := bcrypt.GenerateFromPassword([]byte(testAuthHash), bcrypt.MinCost)
+				mockRepo.EXPECT().GetUserByID(ctx, 1).Return(&models.User{ID: 1, Password: string(hashed), AuthVersion: models.AuthVersionHash}, nil).Times(1)
+
+				err := svc.UpgradeKDF(ctx, 1, testWrongAuthHash, testAuthHash, testKDFParams, nil, nil)
+				if !errors.Is(err, ErrBadCredentials) {
+					t.Errorf("Expected error 'ErrBadCredentials', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "UpgradeKDF_Fail_NotRequired",
+			testFunc: func(t *testing.T) {
+				hashed, _ := bcrypt.GenerateFromPassword([]byte(testAuthHash), bcrypt.MinCost)
+				mockRepo.EXPECT().GetUserByID(ctx, 1).Return(&models.User{ID: 1, Password: string(hashed), AuthVersion: models.AuthVersionHash, KDF: testKDFParams}, nil).Times(1)
+
+				err := svc.UpgradeKDF(ctx, 1, testAuthHash, testAuthHash, testKDFParams, []byte("vault_key"), nil)
+				if !errors.Is(err, ErrKDFUpgradeNotRequired) {
+					t.Errorf("Expected error 'ErrKDFUpgradeNotRequired', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "UpgradeKDF_Fail_OutdatedAlgorithm",
+			testFunc: func(t *testing.T) {
+				hashed, _ := bcrypt.GenerateFromPassword([]byte(testAuthHash), bcrypt.MinCost)
+				mockRepo.EXPECT().GetUserByID(ctx, 1).Return(&models.User{ID: 1, Password: string(hashed), AuthVersion: models.AuthVersionHash}, nil).Times(1)
+
+				err := svc.UpgradeKDF(ctx, 1, testAuthHash, testAuthHash, pkgModels.LegacyKDFParams("user"), nil, nil)
+				if !errors.Is(err, pkgModels.ErrInvalidKDFParams) {
+					t.Errorf("Expected error 'invalid kdf params', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "UpgradeKDF_Fail_InvalidKDFParams",
+			testFunc: func(t *testing.T) {
+				hashed, _ := bcrypt.GenerateFromPassword([]byte(testAuthHash), bcrypt.MinCost)
+				mockRepo.EXPECT().GetUserByID(ctx, 1).Return(&models.User{ID: 1, Password: string(hashed), AuthVersion: models.AuthVersionHash}, nil).Times(1)
+
+				err := svc.UpgradeKDF(ctx, 1, testAuthHash, testAuthHash, nil, nil, nil)
+				if !errors.Is(err, pkgModels.ErrInvalidKDFParams) {
+					t.Errorf("Expected error 'invalid kdf params', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "UpgradeKDF_Fail_Incomplete",
+			testFunc: func(t *testing.T) {
+				hashed, _ := bcrypt.GenerateFromPassword([]byte(testAuthHash), bcrypt.MinCost)
+				mockRepo.EXPECT().GetUserByID(ctx, 1).Return(&models.User{ID: 1, Password: string(hashed), AuthVersion: models.AuthVersionHash, KDF: pkgModels.LegacyKDFParams("user")}, nil).Times(1)
+				mockRepo.EXPECT().Rekey(ctx, 1, gomock.Any(), testKDFParams, gomock.Any(), gomock.Any()).Return(repository.ErrIncompleteRekey).Times(1)
+
+				err := svc.UpgradeKDF(ctx, 1, testAuthHash, testAuthHash, testKDFParams, nil, nil)
+				if !errors.Is(err, repository.ErrIncompleteRekey) {
+					t.Errorf("Expected error 'ErrIncompleteRekey', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "ChangePassword_Success",
+			testFunc: func(t *testing.T) {
+				hashed, _ := bcrypt.GenerateFromPassword([]byte(testAuthHash), bcrypt.MinCost)
+				payloads := map[uint64]*pkgModels.Secret{1: {ID: 1, Payload: []byte("payload")}}
+				mockRepo.EXPECT().GetUserByID(ctx, 1).Return(&models.User{ID: 1, Password: string(hashed), AuthVersion: models.AuthVersionHash}, nil).Times(1)
+				mockRepo.EXPECT().Rekey(ctx, 1, gomock.Any(), testKDFParams, []byte("vault_key"), payloads).
+					DoAndReturn(func(_ context.Context, _ int, password string, _ *pkgModels.KDFParams, _ []byte, _ map[uint64]*pkgModels.Secret) error {
+						if bcrypt.CompareHashAndPassword([]byte(password), []byte(testWrongAuthHash)) != nil {
+							t.Errorf("Expected new auth hash to be stored")
+						}
+						return nil
+					}).Times(1)
+
+				err := svc.ChangePassword(ctx, 1, testAuthHash, testWrongAuthHash, testKDFParams, []byte("vault_key"), payloads)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "ChangePassword_Fail_WrongCurrentPassword",
+			testFunc: func(t *testing.T) {
+				hashed, _ := bcrypt.GenerateFromPassword([]byte(testAuthHash), bcrypt.MinCost)
+				mockRepo.EXPECT().GetUserByID(ctx, 1).Return(&models.User{ID: 1, Password: string(hashed), AuthVersion: models.AuthVersionHash}, nil).Times(1)
+
+				err := svc.ChangePassword(ctx, 1, testWrongAuthHash, testAuthHash, testKDFParams, nil, nil)
+				if !errors.Is(err, ErrBadCredentials) {
+					t.Errorf("Expected error 'ErrBadCredentials', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "ChangePassword_Fail_InvalidAuthHash",
+			testFunc: func(t *testing.T) {
+				hashed, _ := bcrypt.GenerateFromPassword([]byte(testAuthHash), bcrypt.MinCost)
+				mockRepo.EXPECT().GetUserByID(ctx, 1).Return(&models.User{ID: 1, Password: string(hashed), AuthVersion: models.AuthVersionHash}, nil).Times(1)
+
+				err := svc.ChangePassword(ctx, 1, testAuthHash, "short", testKDFParams, nil, nil)
+				if !errors.Is(err, ErrInvalidAuthHash) {
+					t.Errorf("Expected error 'ErrInvalidAuthHash', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "ChangePassword_Fail_UserNotFound",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().GetUserByID(ctx, 1).Return(nil, gophKeeperErrors.ErrNotFound).Times(1)
+
+				err := svc.ChangePassword(ctx, 1, testAuthHash, testAuthHash, testKDFParams, nil, nil)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "GetVaultKey_Success",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().GetVaultKey(ctx, 1).Return([]byte("vault_key"), nil).Times(1)
+
+				vaultKey, err := svc.GetVaultKey(ctx, 1)
+				if err != nil || string(vaultKey) != "vault_key" {
+					t.Errorf("Expected vault key, got %q, err = %v", vaultKey, err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "CreateVaultKey_Success",
+			testFunc: func(t *testing.T) {
+				payloads := map[uint64]*pkgModels.Secret{1: {ID: 1, Payload: []byte("payload"), DataKey: []byte("data_key")}}
+				mockRepo.EXPECT().CreateVaultKey(ctx, 1, []byte("vault_key"), payloads).Return(nil).Times(1)
+
+				if err := svc.CreateVaultKey(ctx, 1, []byte("vault_key"), payloads); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "CreateVaultKey_Fail_Exists",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().CreateVaultKey(ctx, 1, []byte("vault_key"), nil).Return(repository.ErrVaultKeyExists).Times(1)
+
+				err := svc.CreateVaultKey(ctx, 1, []byte("vault_key"), nil)
+				if !errors.Is(err, repository.ErrVaultKeyExists) {
+					t.Errorf("Expected error 'ErrVaultKeyExists', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "CreateVaultKey_Fail_Empty",
+			testFunc: func(t *testing.T) {
+				if err := svc.CreateVaultKey(ctx, 1, nil, nil); !errors.Is(err, ErrInvalidVaultKey) {
+					t.Errorf("Expected error 'ErrInvalidVaultKey', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "CreateKeyPair_Success",
+			testFunc: func(t *testing.T) {
+				keyPair := &pkgModels.KeyPair{PublicKey: make([]byte, 32), PrivateKey: []byte("private")}
+				mockRepo.EXPECT().CreateKeyPair(ctx, 1, keyPair).Return(nil).Times(1)
+
+				if err := svc.CreateKeyPair(ctx, 1, keyPair); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "CreateKeyPair_Fail_InvalidPublicKey",
+			testFunc: func(t *testing.T) {
+				err := svc.CreateKeyPair(ctx, 1, &pkgModels.KeyPair{PublicKey: []byte("short"), PrivateKey: []byte("private")})
+				if !errors.Is(err, ErrInvalidKeyPair) {
+					t.Errorf("Expected error 'ErrInvalidKeyPair', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "GetKeyPair_Fail_NotFound",
+			testFunc: func(t *testing.T) {
+				mockRepo.EXPECT().GetKeyPair(ctx, 1).Return(nil, gophKeeperErrors.ErrNotFound).Times(1)
+
+				if _, err := svc.GetKeyPair(ctx, 1); !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "DeleteAccount_Success",
+			testFunc: func(t *testing.T) {
+				hashed, _ := bcrypt.GenerateFromPassword([]byte(testAuthHash), bcrypt.MinCost)
+				mockRepo.EXPECT().GetUserByID(ctx, 1).Return(&models.User{ID: 1, Password: string(hashed), AuthVersion: models.AuthVersionHash}, nil).Times(1)
+				mockRepo.EXPECT().Delete(ctx, 1).Return(nil, nil).Times(1)
+				mockBlobService.EXPECT().DeleteUserBlobs(ctx, uint64(1)).Return(nil).Times(1)
+
+				err := svc.DeleteAccount(ctx, 1, testAuthHash)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "DeleteAccount_Fail_Blobs",
+			testFunc: func(t *testing.T) {
+				hashed, _ := bcrypt.GenerateFromPassword([]byte(testAuthHash), bcrypt.MinCost)
+				mockRepo.EXPECT().GetUserByID(ctx, 1).Return(&models.User{ID: 1, Password: string(hashed), AuthVersion: models.AuthVersionHash}, nil).Times(1)
+				mockRepo.EXPECT().Delete(ctx, 1).Return(nil, nil).Times(1)
+				mockBlobService.EXPECT().DeleteUserBlobs(ctx, uint64(1)).Return(errors.New("failed to delete user blobs: store error")).Times(1)
+
+				err := svc.DeleteAccount(ctx, 1, testAuthHash)
+				if err == nil || err.Error() != "failed to delete user blobs: store error" {
+					t.Errorf("Expected error 'failed to delete user blobs: store error', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "DeleteAccount_Success_OrganizationVaults",
+			testFunc: func(t *testing.T) {
+				hashed, _ := bcrypt.GenerateFromPassword([]byte(testAuthHash), bcrypt.MinCost)
+				mockRepo.EXPECT().GetUserByID(ctx, 1).Return(&models.User{ID: 1, Password: string(hashed), AuthVersion: models.AuthVersionHash}, nil).Times(1)
+				mockRepo.EXPECT().Delete(ctx, 1).Return([]uint64{70}, nil).Times(1)
+				mockBlobService.EXPECT().DeleteUserBlobs(ctx, uint64(70)).Return(nil).Times(1)
+				mockBlobService.EXPECT().DeleteUserBlobs(ctx, uint64(1)).Return(nil).Times(1)
+
+				if err := svc.DeleteAccount(ctx, 1, testAuthHash); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "DeleteAccount_Fail_LastOwner",
+			testFunc: func(t *testing.T) {
+				hashed, _ := bcrypt.GenerateFromPassword([]byte(testAuthHash), bcrypt.MinCost)
+				mockRepo.EXPECT().GetUserByID(ctx, 1).Return(&models.User{ID: 1, Password: string(hashed), AuthVersion: models.AuthVersionHash}, nil).Times(1)
+				mockRepo.EXPECT().Delete(ctx, 1).Return(nil, fmt.Errorf("organization Team: %w", gophKeeperErrors.ErrLastOwner)).Times(1)
+
+				if err := svc.DeleteAccount(ctx, 1, testAuthHash); !errors.Is(err, gophKeeperErrors.ErrLastOwner) {
+					t.Errorf("Expected error 'ErrLastOwner', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "DeleteAccount_Fail_WrongPassword",
+			testFunc: func(t *testing.T) {
+				hashed, _ := bcrypt.GenerateFromPassword([]byte(testAuthHash), bcrypt.MinCost)
+				mockRepo.EXPECT().GetUserByID(ctx, 1).Return(&models.User{ID: 1, Password: string(hashed), AuthVersion: models.AuthVersionHash}, nil).Times(1)
+
+				err := svc.DeleteAccount(ctx, 1, testWrongAuthHash)
+				if !errors.Is(err, ErrBadCredentials) {
+					t.Errorf("Expected error 'ErrBadCredentials', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "DeleteAccount_Fail_Repository",
+			testFunc: func(t *testing.T) {
+				hashed, _ := bcrypt.GenerateFromPassword([]byte(testAuthHash), bcrypt.MinCost)
+				mockRepo.EXPECT().GetUserByID(ctx, 1).Return(&models.User{ID: 1, Password: string(hashed), AuthVersion: models.AuthVersionHash}, nil).Times(1)
+				mockRepo.EXPECT().Delete(ctx, 1).Return(nil, errors.New("db error")).Times(1)
+
+				err := svc.DeleteAccount(ctx, 1, testAuthHash)
+				if err == nil || err.Error() != "failed to delete account: db error" {
+					t.Errorf("Expected error 'failed to delete account: db error', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/config"
+	"context"
+	"fmt"
+)
+
+// BlobStore определяет хранилище объектов с зашифрованным содержимым файлов.
+// Объекты адресуются ключами вида "каталог/…/имя"; отсутствие объекта сообщается ошибкой ErrNotFound.
+// Хранилище не знает о пользователях и секретах: за права доступа и ссылки на объекты отвечает PostgreSQL.
+type BlobStore interface {
+	// Put сохраняет объект, заменяя существующий с тем же ключом.
+	Put(ctx context.Context, key string, data []byte) error
+	// Get возвращает содержимое объекта.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// DeletePrefix удаляет все объекты, ключи которых начинаются с prefix.
+	// Префикс должен оканчиваться на "/", то есть обозначать каталог ключей.
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// NewBlobStore создаёт хранилище объектов, выбранное в конфигурации сервера.
+func NewBlobStore(cfg *config.Config) (BlobStore, error) {
+	switch cfg.BlobStore {
+	case config.BlobStoreFS:
+		return NewFSBlobStore(cfg.BlobDir)
+	case config.BlobStoreS3:
+		return NewS3BlobStore(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown blob store %q", cfg.BlobStore)
+	}
+}
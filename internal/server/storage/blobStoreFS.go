@@ -0,0 +1,107 @@
+package storage
+
+import (
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FSBlobStore хранит объекты в виде файлов в локальном каталоге; ключ объекта задаёт путь к файлу.
+type FSBlobStore struct {
+	root string
+}
+
+// NewFSBlobStore создаёт хранилище объектов в каталоге root, создавая каталог при необходимости.
+func NewFSBlobStore(root string) (BlobStore, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve blob directory: %w", err)
+	}
+	if err = os.MkdirAll(root, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	return &FSBlobStore{root: root}, nil
+}
+
+// Put сохраняет объект во временный файл и переименовывает его, поэтому объект
+// либо сохраняется целиком, либо остаётся прежним.
+func (s *FSBlobStore) Put(_ context.Context, key string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create object directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create object: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write object: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write object: %w", err)
+	}
+
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save object: %w", err)
+	}
+
+	return nil
+}
+
+// Get возвращает содержимое объекта или ErrNotFound, если объекта нет.
+func (s *FSBlobStore) Get(_ context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("object %s: %w", key, gophKeeperErrors.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %w", err)
+	}
+
+	return data, nil
+}
+
+// DeletePrefix удаляет каталог объектов с ключами, начинающимися с prefix.
+func (s *FSBlobStore) DeletePrefix(_ context.Context, prefix string) error {
+	if !strings.HasSuffix(prefix, "/") {
+		return fmt.Errorf("prefix %q must end with /", prefix)
+	}
+
+	path, err := s.path(strings.TrimSuffix(prefix, "/"))
+	if err != nil {
+		return err
+	}
+
+	if err = os.RemoveAll(path); err != nil {
+		return fmt.Errorf("failed to delete objects: %w", err)
+	}
+
+	return nil
+}
+
+// path возвращает путь к файлу объекта, не допуская выхода за пределы каталога хранилища.
+func (s *FSBlobStore) path(key string) (string, error) {
+	path := filepath.Join(s.root, filepath.FromSlash(key))
+	if key == "" || !strings.HasPrefix(path, s.root+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+	return path, nil
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFSBlobStore(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	store, err := NewFSBlobStore(root)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put(ctx, "1/blob/0", []byte("first")))
+	require.NoError(t, store.Put(ctx, "1/blob/1", []byte("second")))
+	require.NoError(t, store.Put(ctx, "1/other/0", []byte("other")))
+	require.NoError(t, store.Put(ctx, "1/blob/0", []byte("replaced")))
+
+	data, err := store.Get(ctx, "1/blob/0")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("replaced"), data)
+
+	_, err = store.Get(ctx, "1/blob/2")
+	assert.ErrorIs(t, err, gophKeeperErrors.ErrNotFound)
+
+	require.NoError(t, store.DeletePrefix(ctx, "1/blob/"))
+	_, err = store.Get(ctx, "1/blob/1")
+	assert.ErrorIs(t, err, gophKeeperErrors.ErrNotFound)
+
+	data, err = store.Get(ctx, "1/other/0")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("other"), data)
+
+	assert.NoError(t, store.DeletePrefix(ctx, "2/"), "deleting a missing prefix is not an error")
+
+	entries, err := os.ReadDir(filepath.Join(root, "1", "other"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files must not be left behind")
+}
+
+func TestFSBlobStore_InvalidKeys(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFSBlobStore(t.TempDir())
+	require.NoError(t, err)
+
+	assert.Error(t, store.Put(ctx, "../escape", []byte("data")))
+	assert.Error(t, store.Put(ctx, "", []byte("data")))
+	_, err = store.Get(ctx, "1/../../escape")
+	assert.Error(t, err)
+	assert.Error(t, store.DeletePrefix(ctx, "1/blob"))
+	assert.Error(t, store.DeletePrefix(ctx, "/"))
+}
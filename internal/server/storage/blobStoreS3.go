@@ -0,0 +1,277 @@
+package storage
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/config"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// s3Service - имя сервиса, используемое при подписи запросов.
+	s3Service = "s3"
+	// s3DateFormat - формат времени запроса в заголовке X-Amz-Date.
+	s3DateFormat = "20060102T150405Z"
+	// s3ErrorBodyLimit - сколько байт ответа с ошибкой включается в текст ошибки.
+	s3ErrorBodyLimit = 512
+)
+
+// S3BlobStore хранит объекты в бакете S3-совместимого хранилища. Запросы адресуют бакет
+// в пути (path-style) и подписываются по схеме AWS Signature Version 4, поэтому хранилище
+// работает как с Amazon S3, так и с совместимыми серверами вроде MinIO.
+type S3BlobStore struct {
+	client   *http.Client
+	endpoint *url.URL
+	cfg      config.S3Config
+	now      func() time.Time
+}
+
+// NewS3BlobStore создаёт хранилище объектов в бакете S3-совместимого хранилища.
+func NewS3BlobStore(cfg config.S3Config) (BlobStore, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q: scheme must be http or https", cfg.Endpoint)
+	}
+
+	return &S3BlobStore{
+		client:   &http.Client{Timeout: time.Minute},
+		endpoint: endpoint,
+		cfg:      cfg,
+		now:      time.Now,
+	}, nil
+}
+
+// Put сохраняет объект в бакете.
+func (s *S3BlobStore) Put(ctx context.Context, key string, data []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, key, nil, data)
+	if err != nil {
+		return fmt.Errorf("failed to put object: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return checkS3Response(resp)
+}
+
+// Get возвращает содержимое объекта или ErrNotFound, если объекта нет в бакете.
+func (s *S3BlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("object %s: %w", key, gophKeeperErrors.ErrNotFound)
+	}
+	if err = checkS3Response(resp); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %w", err)
+	}
+
+	return data, nil
+}
+
+// s3ListResult описывает ответ ListObjectsV2.
+type s3ListResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// DeletePrefix постранично перечисляет объекты с ключами, начинающимися с prefix, и удаляет их.
+func (s *S3BlobStore) DeletePrefix(ctx context.Context, prefix string) error {
+	if !strings.HasSuffix(prefix, "/") {
+		return fmt.Errorf("prefix %q must end with /", prefix)
+	}
+
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+	for {
+		list, err := s.list(ctx, query)
+		if err != nil {
+			return err
+		}
+
+		for _, object := range list.Contents {
+			if err = s.delete(ctx, object.Key); err != nil {
+				return err
+			}
+		}
+
+		if !list.IsTruncated {
+			return nil
+		}
+		query.Set("continuation-token", list.NextContinuationToken)
+	}
+}
+
+// list выполняет запрос ListObjectsV2.
+func (s *S3BlobStore) list(ctx context.Context, query url.Values) (*s3ListResult, error) {
+	resp, err := s.do(ctx, http.MethodGet, "", query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err = checkS3Response(resp); err != nil {
+		return nil, err
+	}
+
+	var list s3ListResult
+	if err = xml.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to decode object list: %w", err)
+	}
+
+	return &list, nil
+}
+
+// delete удаляет объект. Удаление отсутствующего объекта не считается ошибкой.
+func (s *S3BlobStore) delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return checkS3Response(resp)
+}
+
+// do выполняет подписанный запрос к объекту key бакета; пустой key адресует сам бакет.
+func (s *S3BlobStore) do(ctx context.Context, method, key string, query url.Values, body []byte) (*http.Response, error) {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.cfg.Bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawPath = s3EncodePath(u.Path)
+	u.RawQuery = s3CanonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+
+	s.sign(req, body)
+
+	return s.client.Do(req)
+}
+
+// sign подписывает запрос по схеме AWS Signature Version 4.
+func (s *S3BlobStore) sign(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format(s3DateFormat)
+	date := amzDate[:8]
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host + "\n" +
+			"x-amz-content-sha256:" + payloadHash + "\n" +
+			"x-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, s.cfg.Region, s3Service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), date)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKey, scope, signedHeaders, signature))
+}
+
+// checkS3Response возвращает ошибку, если хранилище ответило неуспешным статусом.
+func checkS3Response(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, s3ErrorBodyLimit))
+	return fmt.Errorf("S3 request failed with status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
+
+// s3EncodePath кодирует путь запроса так, как того требует подпись: каждый сегмент
+// кодируется по RFC 3986, разделители "/" сохраняются.
+func s3EncodePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = s3Escape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// s3CanonicalQuery кодирует параметры запроса, отсортированные по имени, как того требует подпись.
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, k := range keys {
+		for _, v := range query[k] {
+			parts = append(parts, s3Escape(k)+"="+s3Escape(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// s3Escape кодирует строку по RFC 3986, оставляя без изменений только незарезервированные символы.
+func s3Escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
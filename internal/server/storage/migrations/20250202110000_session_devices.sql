@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE sessions ADD COLUMN device_id bigint NOT NULL DEFAULT 0;
+ALTER TABLE sessions ADD COLUMN device_name text NOT NULL DEFAULT '';
+CREATE INDEX sessions_user_id_device_id_idx ON sessions (user_id, device_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX sessions_user_id_device_id_idx;
+ALTER TABLE sessions DROP COLUMN device_name;
+ALTER TABLE sessions DROP COLUMN device_id;
+-- +goose StatementEnd
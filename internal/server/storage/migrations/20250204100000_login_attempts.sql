@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS login_attempts (
+    id bigserial PRIMARY KEY,
+    login text NOT NULL,
+    user_id integer REFERENCES users (id) ON DELETE CASCADE,
+    peer_address text NOT NULL,
+    success boolean NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT NOW()
+);
+CREATE INDEX login_attempts_login_created_at_idx ON login_attempts (login, created_at);
+CREATE INDEX login_attempts_peer_address_created_at_idx ON login_attempts (peer_address, created_at);
+CREATE INDEX login_attempts_user_id_created_at_idx ON login_attempts (user_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE login_attempts;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+DELETE FROM secrets WHERE user_id IS NULL OR user_id NOT IN (SELECT id FROM users);
+ALTER TABLE secrets ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE secrets ADD CONSTRAINT secrets_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+CREATE INDEX secrets_user_id_idx ON secrets (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX secrets_user_id_idx;
+ALTER TABLE secrets DROP CONSTRAINT secrets_user_id_fkey;
+ALTER TABLE secrets ALTER COLUMN user_id DROP NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- Построчная защита секретов: приложение выставляет app.user_id в каждой транзакции,
+-- и запросы видят только строки этого пользователя. Без параметра строки недоступны.
+-- FORCE распространяет политику и на владельца таблицы, под которым работает сервер.
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE secrets ENABLE ROW LEVEL SECURITY;
+ALTER TABLE secrets FORCE ROW LEVEL SECURITY;
+CREATE POLICY secrets_tenant_isolation ON secrets
+    USING (user_id = NULLIF(current_setting('app.user_id', true), '')::integer)
+    WITH CHECK (user_id = NULLIF(current_setting('app.user_id', true), '')::integer);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP POLICY secrets_tenant_isolation ON secrets;
+ALTER TABLE secrets NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE secrets DISABLE ROW LEVEL SECURITY;
+-- +goose StatementEnd
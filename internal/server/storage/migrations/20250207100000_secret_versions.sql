@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS secret_versions (
+    id serial PRIMARY KEY,
+    secret_id integer NOT NULL REFERENCES secrets (id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    title varchar(255) NOT NULL,
+    metadata TEXT,
+    secret_type secret_type NOT NULL,
+    payload bytea NOT NULL,
+    updated_at timestamp NOT NULL,
+    archived_at timestamp NOT NULL DEFAULT NOW()
+);
+CREATE INDEX secret_versions_secret_id_idx ON secret_versions (secret_id, id DESC);
+CREATE INDEX secret_versions_user_id_idx ON secret_versions (user_id, id DESC);
+
+ALTER TABLE secret_versions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE secret_versions FORCE ROW LEVEL SECURITY;
+CREATE POLICY secret_versions_tenant_isolation ON secret_versions
+    USING (user_id = NULLIF(current_setting('app.user_id', true), '')::integer)
+    WITH CHECK (user_id = NULLIF(current_setting('app.user_id', true), '')::integer);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE secret_versions;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- Корзина: удалённые секреты помечаются временем удаления и окончательно удаляются фоновой задачей.
+-- Задача очистки работает вне контекста пользователя, поэтому ей выдаётся отдельная политика,
+-- которая по параметру app.purge_trash открывает только строки, уже находящиеся в корзине.
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE secrets ADD COLUMN deleted_at timestamp;
+CREATE INDEX secrets_deleted_at_idx ON secrets (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE POLICY secrets_trash_purge_select ON secrets FOR SELECT
+    USING (current_setting('app.purge_trash', true) = 'on' AND deleted_at IS NOT NULL);
+CREATE POLICY secrets_trash_purge_delete ON secrets FOR DELETE
+    USING (current_setting('app.purge_trash', true) = 'on' AND deleted_at IS NOT NULL);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP POLICY secrets_trash_purge_delete ON secrets;
+DROP POLICY secrets_trash_purge_select ON secrets;
+DROP INDEX secrets_deleted_at_idx;
+ALTER TABLE secrets DROP COLUMN deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- Ревизия секрета увеличивается при каждом изменении и используется для обнаружения конкурентных правок.
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE secrets ADD COLUMN revision bigint NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE secrets DROP COLUMN revision;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- Бинарные объекты: содержимое файлов хранится вне секретов, разбитым на зашифрованные фрагменты.
+-- Идентификатор объекта генерирует клиент, поэтому он уникален только в пределах пользователя.
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS blobs (
+    id varchar(64) NOT NULL,
+    user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    chunk_count integer NOT NULL,
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, id)
+);
+
+CREATE TABLE IF NOT EXISTS blob_chunks (
+    user_id integer NOT NULL,
+    blob_id varchar(64) NOT NULL,
+    chunk_index integer NOT NULL,
+    data bytea NOT NULL,
+    PRIMARY KEY (user_id, blob_id, chunk_index),
+    FOREIGN KEY (user_id, blob_id) REFERENCES blobs (user_id, id) ON DELETE CASCADE
+);
+
+ALTER TABLE blobs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE blobs FORCE ROW LEVEL SECURITY;
+CREATE POLICY blobs_tenant_isolation ON blobs
+    USING (user_id = NULLIF(current_setting('app.user_id', true), '')::integer)
+    WITH CHECK (user_id = NULLIF(current_setting('app.user_id', true), '')::integer);
+
+ALTER TABLE blob_chunks ENABLE ROW LEVEL SECURITY;
+ALTER TABLE blob_chunks FORCE ROW LEVEL SECURITY;
+CREATE POLICY blob_chunks_tenant_isolation ON blob_chunks
+    USING (user_id = NULLIF(current_setting('app.user_id', true), '')::integer)
+    WITH CHECK (user_id = NULLIF(current_setting('app.user_id', true), '')::integer);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE blob_chunks;
+DROP TABLE blobs;
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- Хранилище объектов: содержимое фрагментов файлов переносится из PostgreSQL во внешнее хранилище,
+-- а в blob_chunks остаются только отметки о полученных фрагментах. Секреты и их версии хранят ссылку
+-- на объект, чтобы сервер мог удалить его, когда на объект больше не ссылается ни один секрет.
+-- Сборщик мусора работает вне контекста пользователя, поэтому ему выдаются отдельные политики:
+-- параметр app.collect_blobs открывает объекты и только ссылающиеся на объекты строки секретов.
+-- Объекты, загруженные до появления хранилища, удаляются: их фрагменты нельзя перенести средствами SQL.
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE secrets ADD COLUMN blob_id varchar(64);
+ALTER TABLE secrets ADD FOREIGN KEY (user_id, blob_id) REFERENCES blobs (user_id, id);
+CREATE INDEX secrets_blob_id_idx ON secrets (user_id, blob_id) WHERE blob_id IS NOT NULL;
+
+ALTER TABLE secret_versions ADD COLUMN blob_id varchar(64);
+ALTER TABLE secret_versions ADD FOREIGN KEY (user_id, blob_id) REFERENCES blobs (user_id, id);
+CREATE INDEX secret_versions_blob_id_idx ON secret_versions (user_id, blob_id) WHERE blob_id IS NOT NULL;
+
+CREATE POLICY blobs_gc_select ON blobs FOR SELECT
+    USING (current_setting('app.collect_blobs', true) = 'on');
+CREATE POLICY blobs_gc_delete ON blobs FOR DELETE
+    USING (current_setting('app.collect_blobs', true) = 'on');
+CREATE POLICY secrets_blob_gc_select ON secrets FOR SELECT
+    USING (current_setting('app.collect_blobs', true) = 'on' AND blob_id IS NOT NULL);
+CREATE POLICY secret_versions_blob_gc_select ON secret_versions FOR SELECT
+    USING (current_setting('app.collect_blobs', true) = 'on' AND blob_id IS NOT NULL);
+
+SELECT set_config('app.collect_blobs', 'on', true);
+DELETE FROM blobs;
+ALTER TABLE blob_chunks DROP COLUMN data;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP POLICY secret_versions_blob_gc_select ON secret_versions;
+DROP POLICY secrets_blob_gc_select ON secrets;
+ALTER TABLE secret_versions DROP COLUMN blob_id;
+ALTER TABLE secrets DROP COLUMN blob_id;
+
+SELECT set_config('app.collect_blobs', 'on', true);
+DELETE FROM blobs;
+DROP POLICY blobs_gc_delete ON blobs;
+DROP POLICY blobs_gc_select ON blobs;
+ALTER TABLE blob_chunks ADD COLUMN data bytea NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,69 @@
+-- Инкрементальная синхронизация: каждое изменение секретов пользователя получает номер из счётчика
+-- users.sync_revision, который записывается в secrets.change_revision. Окончательно удалённые секреты
+-- оставляют запись в secret_tombstones, чтобы клиент узнал об удалении, даже если пропустил перемещение в корзину.
+-- Номера выдают триггеры, поэтому их получает любое изменение, включая перешифрование при смене пароля.
+-- Счётчик увеличивается под блокировкой строки пользователя, поэтому изменения одного пользователя
+-- фиксируются в порядке номеров, и клиент не пропускает изменения незавершённых транзакций.
+-- При удалении пользователя строка счётчика уже удалена, и записи об удалении секретов не создаются.
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN sync_revision bigint NOT NULL DEFAULT 0;
+ALTER TABLE secrets ADD COLUMN change_revision bigint NOT NULL DEFAULT 0;
+CREATE INDEX secrets_change_revision_idx ON secrets (user_id, change_revision);
+
+CREATE TABLE IF NOT EXISTS secret_tombstones (
+    secret_id integer NOT NULL,
+    user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    change_revision bigint NOT NULL,
+    PRIMARY KEY (user_id, secret_id)
+);
+
+ALTER TABLE secret_tombstones ENABLE ROW LEVEL SECURITY;
+ALTER TABLE secret_tombstones FORCE ROW LEVEL SECURITY;
+CREATE POLICY secret_tombstones_tenant_isolation ON secret_tombstones
+    USING (user_id = NULLIF(current_setting('app.user_id', true), '')::integer)
+    WITH CHECK (user_id = NULLIF(current_setting('app.user_id', true), '')::integer);
+CREATE POLICY secret_tombstones_trash_purge_insert ON secret_tombstones FOR INSERT
+    WITH CHECK (current_setting('app.purge_trash', true) = 'on');
+
+CREATE FUNCTION next_sync_revision(uid integer) RETURNS bigint AS $$
+    UPDATE users SET sync_revision = sync_revision + 1 WHERE id = uid RETURNING sync_revision;
+$$ LANGUAGE sql;
+
+CREATE FUNCTION secrets_track_change() RETURNS trigger AS $$
+BEGIN
+    NEW.change_revision := COALESCE(next_sync_revision(NEW.user_id), 0);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION secrets_track_delete() RETURNS trigger AS $$
+DECLARE
+    revision bigint;
+BEGIN
+    revision := next_sync_revision(OLD.user_id);
+    IF revision IS NOT NULL THEN
+        INSERT INTO secret_tombstones (secret_id, user_id, change_revision) VALUES (OLD.id, OLD.user_id, revision);
+    END IF;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER secrets_track_change BEFORE INSERT OR UPDATE ON secrets
+    FOR EACH ROW EXECUTE FUNCTION secrets_track_change();
+CREATE TRIGGER secrets_track_delete AFTER DELETE ON secrets
+    FOR EACH ROW EXECUTE FUNCTION secrets_track_delete();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER secrets_track_delete ON secrets;
+DROP TRIGGER secrets_track_change ON secrets;
+DROP FUNCTION secrets_track_delete();
+DROP FUNCTION secrets_track_change();
+DROP FUNCTION next_sync_revision(integer);
+DROP TABLE secret_tombstones;
+DROP INDEX secrets_change_revision_idx;
+ALTER TABLE secrets DROP COLUMN change_revision;
+ALTER TABLE users DROP COLUMN sync_revision;
+-- +goose StatementEnd
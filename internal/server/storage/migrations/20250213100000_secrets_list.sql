@@ -0,0 +1,13 @@
+-- Индексы для постраничного списка секретов: страница продолжается с позиции курсора
+-- в порядке времени изменения или заголовка, а одинаковые ключи сортировки упорядочиваются по id.
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX secrets_list_updated_idx ON secrets (user_id, updated_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX secrets_list_title_idx ON secrets (user_id, title, id) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX secrets_list_title_idx;
+DROP INDEX secrets_list_updated_idx;
+-- +goose StatementEnd
@@ -0,0 +1,61 @@
+-- Папки и метки секретов. Папки образуют дерево: при удалении папки удаляются вложенные папки,
+-- а секреты из них остаются без папки. Изменение папки секрета обновляет его строку, поэтому
+-- попадает в инкрементальную синхронизацию так же, как любое другое изменение секрета.
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS folders (
+    id serial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    parent_id integer REFERENCES folders (id) ON DELETE CASCADE,
+    name varchar(255) NOT NULL,
+    created_at timestamp NOT NULL DEFAULT NOW()
+);
+CREATE UNIQUE INDEX folders_name_idx ON folders (user_id, COALESCE(parent_id, 0), name);
+CREATE INDEX folders_parent_id_idx ON folders (parent_id);
+
+CREATE TABLE IF NOT EXISTS tags (
+    id serial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name varchar(255) NOT NULL,
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+
+ALTER TABLE secrets ADD COLUMN folder_id integer REFERENCES folders (id) ON DELETE SET NULL;
+CREATE INDEX secrets_folder_id_idx ON secrets (folder_id);
+
+CREATE TABLE IF NOT EXISTS secret_tags (
+    secret_id integer NOT NULL REFERENCES secrets (id) ON DELETE CASCADE,
+    tag_id integer NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    PRIMARY KEY (secret_id, tag_id)
+);
+CREATE INDEX secret_tags_tag_id_idx ON secret_tags (tag_id);
+
+ALTER TABLE folders ENABLE ROW LEVEL SECURITY;
+ALTER TABLE folders FORCE ROW LEVEL SECURITY;
+CREATE POLICY folders_tenant_isolation ON folders
+    USING (user_id = NULLIF(current_setting('app.user_id', true), '')::integer)
+    WITH CHECK (user_id = NULLIF(current_setting('app.user_id', true), '')::integer);
+
+ALTER TABLE tags ENABLE ROW LEVEL SECURITY;
+ALTER TABLE tags FORCE ROW LEVEL SECURITY;
+CREATE POLICY tags_tenant_isolation ON tags
+    USING (user_id = NULLIF(current_setting('app.user_id', true), '')::integer)
+    WITH CHECK (user_id = NULLIF(current_setting('app.user_id', true), '')::integer);
+
+ALTER TABLE secret_tags ENABLE ROW LEVEL SECURITY;
+ALTER TABLE secret_tags FORCE ROW LEVEL SECURITY;
+CREATE POLICY secret_tags_tenant_isolation ON secret_tags
+    USING (user_id = NULLIF(current_setting('app.user_id', true), '')::integer)
+    WITH CHECK (user_id = NULLIF(current_setting('app.user_id', true), '')::integer);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE secret_tags;
+DROP INDEX secrets_folder_id_idx;
+ALTER TABLE secrets DROP COLUMN folder_id;
+DROP TABLE tags;
+DROP TABLE folders;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- Шифрование заголовков и метаданных секретов на клиенте. Зашифрованный заголовок длиннее открытого,
+-- поэтому столбцы title переводятся в text. Признак labels_encrypted отмечает секреты с зашифрованными
+-- заголовком и метаданными; существующие строки остаются открытыми, пока клиент не перешифрует их.
+-- Поиск по словам заголовка выполняется по токенам слепого индекса search_tokens: клиент вычисляет их
+-- как HMAC нормализованных слов на ключе, недоступном серверу.
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE secrets ALTER COLUMN title TYPE text;
+ALTER TABLE secrets ADD COLUMN labels_encrypted boolean NOT NULL DEFAULT false;
+ALTER TABLE secrets ADD COLUMN search_tokens text[] NOT NULL DEFAULT '{}';
+CREATE INDEX secrets_search_tokens_idx ON secrets USING gin (search_tokens);
+
+ALTER TABLE secret_versions ALTER COLUMN title TYPE text;
+ALTER TABLE secret_versions ADD COLUMN labels_encrypted boolean NOT NULL DEFAULT false;
+ALTER TABLE secret_versions ADD COLUMN search_tokens text[] NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE secret_versions DROP COLUMN search_tokens;
+ALTER TABLE secret_versions DROP COLUMN labels_encrypted;
+ALTER TABLE secret_versions ALTER COLUMN title TYPE varchar(255) USING left(title, 255);
+
+DROP INDEX secrets_search_tokens_idx;
+ALTER TABLE secrets DROP COLUMN search_tokens;
+ALTER TABLE secrets DROP COLUMN labels_encrypted;
+ALTER TABLE secrets ALTER COLUMN title TYPE varchar(255) USING left(title, 255);
+-- +goose StatementEnd
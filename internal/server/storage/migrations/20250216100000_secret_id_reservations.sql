@@ -0,0 +1,23 @@
+-- Резервирование идентификаторов секретов. Клиент привязывает шифротекст к идентификатору секрета,
+-- поэтому получает идентификатор нового секрета до шифрования и создаёт секрет с ним. Резерв выдаётся
+-- из последовательности secrets и принадлежит пользователю: создать секрет с чужим или не выданным
+-- идентификатором нельзя. Резерв удаляется при создании секрета.
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS secret_id_reservations (
+    id integer PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at timestamp NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE secret_id_reservations ENABLE ROW LEVEL SECURITY;
+ALTER TABLE secret_id_reservations FORCE ROW LEVEL SECURITY;
+CREATE POLICY secret_id_reservations_tenant_isolation ON secret_id_reservations
+    USING (user_id = NULLIF(current_setting('app.user_id', true), '')::integer)
+    WITH CHECK (user_id = NULLIF(current_setting('app.user_id', true), '')::integer);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE secret_id_reservations;
+-- +goose StatementEnd
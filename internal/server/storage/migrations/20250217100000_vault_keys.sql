@@ -0,0 +1,18 @@
+-- Ключ хранилища и ключи данных секретов. Данные каждого секрета шифруются собственным случайным ключом
+-- данных, который клиент шифрует ключом хранилища, а ключ хранилища - ключом, выведенным из мастер-пароля.
+-- Сервер хранит оба ключа только в зашифрованном виде и не может их расшифровать. Поэтому при смене пароля
+-- перешифровывается один ключ хранилища, а не все секреты. Секреты и версии без ключа данных зашифрованы
+-- ключом из мастер-пароля напрямую, как до введения ключа хранилища.
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN vault_key bytea;
+ALTER TABLE secrets ADD COLUMN data_key bytea;
+ALTER TABLE secret_versions ADD COLUMN data_key bytea;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE secret_versions DROP COLUMN data_key;
+ALTER TABLE secrets DROP COLUMN data_key;
+ALTER TABLE users DROP COLUMN vault_key;
+-- +goose StatementEnd
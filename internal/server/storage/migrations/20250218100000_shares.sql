@@ -0,0 +1,38 @@
+-- Передача секретов другим пользователям. У каждого пользователя есть пара ключей X25519: открытый ключ
+-- хранится открыто, закрытый - зашифрованным ключом хранилища пользователя. Владелец секрета шифрует
+-- его ключ данных открытым ключом получателя и сохраняет в shares. Получатель видит переданные ему секреты
+-- через отдельную политику построчной защиты, а изменяет их только при доступе can_edit.
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN public_key bytea;
+ALTER TABLE users ADD COLUMN private_key bytea;
+
+CREATE TABLE IF NOT EXISTS shares (
+    id serial PRIMARY KEY,
+    secret_id integer NOT NULL REFERENCES secrets (id) ON DELETE CASCADE,
+    owner_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    recipient_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    data_key bytea NOT NULL,
+    can_edit boolean NOT NULL DEFAULT false,
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    UNIQUE (secret_id, recipient_id)
+);
+CREATE INDEX shares_recipient_id_idx ON shares (recipient_id);
+
+ALTER TABLE shares ENABLE ROW LEVEL SECURITY;
+ALTER TABLE shares FORCE ROW LEVEL SECURITY;
+CREATE POLICY shares_participant_isolation ON shares
+    USING (NULLIF(current_setting('app.user_id', true), '')::integer IN (owner_id, recipient_id))
+    WITH CHECK (owner_id = NULLIF(current_setting('app.user_id', true), '')::integer);
+
+CREATE POLICY secrets_shared_read ON secrets FOR SELECT
+    USING (id IN (SELECT secret_id FROM shares WHERE recipient_id = NULLIF(current_setting('app.user_id', true), '')::integer));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP POLICY secrets_shared_read ON secrets;
+DROP TABLE shares;
+ALTER TABLE users DROP COLUMN private_key;
+ALTER TABLE users DROP COLUMN public_key;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- Организации и их общие хранилища. Секреты организации хранятся так же, как секреты пользователя,
+-- от имени служебной учётной записи хранилища organizations.vault_id, под которой сервер выставляет
+-- app.user_id. Поэтому построчная защита, ревизии синхронизации, история версий и корзина работают
+-- для хранилища организации без изменений. Войти под служебной учётной записью нельзя: у неё нет пароля.
+-- Ключ хранилища организации хранится для каждого участника зашифрованным его открытым ключом.
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE org_role AS ENUM ('owner', 'admin', 'editor', 'viewer');
+
+CREATE TABLE IF NOT EXISTS organizations (
+    id serial PRIMARY KEY,
+    name varchar(100) NOT NULL,
+    vault_id integer NOT NULL UNIQUE REFERENCES users (id) ON DELETE CASCADE,
+    created_at timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS org_members (
+    org_id integer NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role org_role NOT NULL,
+    vault_key bytea NOT NULL,
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (org_id, user_id)
+);
+CREATE INDEX org_members_user_id_idx ON org_members (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM users WHERE id IN (SELECT vault_id FROM organizations);
+DROP TABLE org_members;
+DROP TABLE organizations;
+DROP TYPE org_role;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- Экстренный доступ к хранилищу. Владелец назначает доверенного пользователя, шифрует ключ своего хранилища
+-- его открытым ключом X25519 и задаёт срок ожидания в днях. Доверенный пользователь запрашивает доступ;
+-- если владелец не отклонит запрос до истечения срока ожидания, сервер предоставляет доступ, и доверенный
+-- пользователь получает зашифрованный для него ключ хранилища и читает секреты владельца.
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE emergency_status AS ENUM ('idle', 'requested', 'granted');
+
+CREATE TABLE IF NOT EXISTS emergency_access (
+    id serial PRIMARY KEY,
+    grantor_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    grantee_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    wait_days integer NOT NULL CHECK (wait_days > 0),
+    status emergency_status NOT NULL DEFAULT 'idle',
+    vault_key bytea NOT NULL,
+    requested_at timestamp,
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    UNIQUE (grantor_id, grantee_id),
+    CHECK (grantor_id <> grantee_id)
+);
+CREATE INDEX emergency_access_grantee_id_idx ON emergency_access (grantee_id);
+CREATE INDEX emergency_access_requested_idx ON emergency_access (requested_at) WHERE status = 'requested';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE emergency_access;
+DROP TYPE emergency_status;
+-- +goose StatementEnd
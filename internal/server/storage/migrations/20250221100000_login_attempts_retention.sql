@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX login_attempts_created_at_idx ON login_attempts (created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX login_attempts_created_at_idx;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- Служебные учётные записи хранилищ организаций отмечаются признаком is_vault и исключаются из поиска
+-- пользователей по логину: их нельзя добавить в организацию, назначить получателем секрета или доверенным
+-- пользователем, а вход и PreLogin отвечают для них так же, как для несуществующего логина.
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN is_vault boolean NOT NULL DEFAULT false;
+UPDATE users SET is_vault = true WHERE id IN (SELECT vault_id FROM organizations);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN is_vault;
+-- +goose StatementEnd
@@ -0,0 +1,142 @@
+// Package repository предоставляет доступ к сведениям о бинарных объектах, хранящимся в базе данных.
+package repository
+
+import (
+	serverModels "beliaev-aa/GophKeeper/internal/server/models"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+// IBlobRepository определяет интерфейс для репозитория бинарных объектов,
+// хранящих содержимое файлов в виде последовательности зашифрованных фрагментов.
+// Сами фрагменты хранятся в хранилище объектов, репозиторий учитывает только полученные фрагменты
+// и ссылки на объекты из секретов.
+type IBlobRepository interface {
+	CreateBlob(ctx context.Context, userID uint64, blobID string, chunkCount uint32) (*models.BlobStatus, error)
+	GetBlobStatus(ctx context.Context, userID uint64, blobID string) (*models.BlobStatus, error)
+	SaveChunk(ctx context.Context, userID uint64, blobID string, index uint32) error
+	DeleteUnreferenced(ctx context.Context, userID uint64, blobIDs []string) ([]string, error)
+	DeleteOrphans(ctx context.Context, before time.Time) ([]serverModels.BlobRef, error)
+}
+
+// BlobRepository обеспечивает методы для работы с бинарными объектами в базе данных.
+type BlobRepository struct {
+	db *sqlx.DB
+}
+
+// blobStatusQuery выбирает объект пользователя вместе с количеством полученных фрагментов.
+const blobStatusQuery = `SELECT b.id, b.chunk_count,
+	(SELECT count(*) FROM blob_chunks c WHERE c.user_id = b.user_id AND c.blob_id = b.id) AS received_chunks
+	FROM blobs b WHERE b.user_id = $1 AND b.id = $2`
+
+// NewBlobRepository создаёт новый экземпляр BlobRepository.
+// Принимает подключение к базе данных sqlx.DB и возвращает указатель на BlobRepository.
+func NewBlobRepository(db *sqlx.DB) IBlobRepository {
+	return &BlobRepository{
+		db: db,
+	}
+}
+
+// CreateBlob регистрирует объект пользователя, если он ещё не существует, и возвращает его состояние.
+// Повторный вызов для уже начатого объекта позволяет продолжить прерванную загрузку.
+func (r *BlobRepository) CreateBlob(ctx context.Context, userID uint64, blobID string, chunkCount uint32) (*models.BlobStatus, error) {
+	var status models.BlobStatus
+
+	err := runAsUser(ctx, r.db, userID, func(tx *sqlx.Tx) error {
+		query := `INSERT INTO blobs (id, user_id, chunk_count) VALUES ($1, $2, $3) ON CONFLICT (user_id, id) DO NOTHING`
+		if _, err := tx.ExecContext(ctx, query, blobID, userID, chunkCount); err != nil {
+			return err
+		}
+
+		return tx.QueryRowxContext(ctx, blobStatusQuery, userID, blobID).StructScan(&status)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// GetBlobStatus возвращает состояние загрузки объекта пользователя.
+// Возвращает ErrNotFound, если объект не найден или принадлежит другому пользователю.
+func (r *BlobRepository) GetBlobStatus(ctx context.Context, userID uint64, blobID string) (*models.BlobStatus, error) {
+	var status models.BlobStatus
+
+	err := runAsUser(ctx, r.db, userID, func(tx *sqlx.Tx) error {
+		err := tx.QueryRowxContext(ctx, blobStatusQuery, userID, blobID).StructScan(&status)
+		if errors.Is(err, sql.ErrNoRows) {
+			return gophKeeperErrors.ErrNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// SaveChunk отмечает фрагмент объекта полученным. Повторная отметка фрагмента не считается ошибкой.
+func (r *BlobRepository) SaveChunk(ctx context.Context, userID uint64, blobID string, index uint32) error {
+	return runAsUser(ctx, r.db, userID, func(tx *sqlx.Tx) error {
+		query := `INSERT INTO blob_chunks (user_id, blob_id, chunk_index) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, blob_id, chunk_index) DO NOTHING`
+		_, err := tx.ExecContext(ctx, query, userID, blobID, index)
+		return err
+	})
+}
+
+// DeleteUnreferenced удаляет из перечисленных объектов пользователя те, на которые не ссылаются
+// ни секреты, ни их версии, и возвращает идентификаторы удалённых объектов.
+func (r *BlobRepository) DeleteUnreferenced(ctx context.Context, userID uint64, blobIDs []string) ([]string, error) {
+	var deleted []string
+	if len(blobIDs) == 0 {
+		return deleted, nil
+	}
+
+	err := runAsUser(ctx, r.db, userID, func(tx *sqlx.Tx) error {
+		query, args, err := sqlx.In(`DELETE FROM blobs b WHERE b.user_id = ? AND b.id IN (?) AND `+unreferencedBlob+` RETURNING b.id`, userID, blobIDs)
+		if err != nil {
+			return err
+		}
+		return tx.SelectContext(ctx, &deleted, sqlx.Rebind(sqlx.DOLLAR, query), args...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deleted, nil
+}
+
+// DeleteOrphans удаляет объекты всех пользователей, созданные раньше before, на которые не ссылаются
+// ни секреты, ни их версии: загрузки, так и не привязанные к секрету, и объекты окончательно удалённых секретов.
+// Выполняется вне контекста пользователя: параметр app.collect_blobs открывает сборщику мусора
+// объекты и ссылки на них. Возвращает удалённые объекты.
+func (r *BlobRepository) DeleteOrphans(ctx context.Context, before time.Time) ([]serverModels.BlobRef, error) {
+	var deleted []serverModels.BlobRef
+
+	err := runInTx(r.db, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, "SELECT set_config('app.collect_blobs', 'on', true)")
+		if err != nil {
+			return fmt.Errorf("failed to enable blob collection: %w", err)
+		}
+
+		query := `DELETE FROM blobs b WHERE b.created_at < $1 AND ` + unreferencedBlob + ` RETURNING b.user_id, b.id`
+		return tx.SelectContext(ctx, &deleted, query, before)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deleted, nil
+}
+
+// unreferencedBlob - условие отсутствия ссылок на объект b из секретов и их версий.
+const unreferencedBlob = `NOT EXISTS (SELECT 1 FROM secrets s WHERE s.user_id = b.user_id AND s.blob_id = b.id)
+	AND NOT EXISTS (SELECT 1 FROM secret_versions v WHERE v.user_id = b.user_id AND v.blob_id = b.id)`
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	serverModels "beliaev-aa/GophKeeper/internal/server/models"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBlobRepository(t *testing.T) {
+	ctx := context.Background()
+
+	statusRows := func(received int) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "chunk_count", "received_chunks"}).AddRow("blob", 3, received)
+	}
+
+	tests := []struct {
+		name     string
+		testFunc func(t *testing.T, repo IBlobRepository, mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "CreateBlob_Success",
+			testFunc: func(t *testing.T, repo IBlobRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectExec(`INSERT INTO blobs \(id, user_id, chunk_count\) VALUES \(\$1, \$2, \$3\) ON CONFLICT \(user_id, id\) DO NOTHING`).
+					WithArgs("blob", 1, 3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`SELECT b.id, b.chunk_count, (.+) AS received_chunks FROM blobs b WHERE b.user_id = \$1 AND b.id = \$2`).
+					WithArgs(1, "blob").
+					WillReturnRows(statusRows(1))
+				mock.ExpectCommit()
+
+				status, err := repo.CreateBlob(ctx, 1, "blob", 3)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if status.ID != "blob" || status.ChunkCount != 3 || status.ReceivedChunks != 1 {
+					t.Errorf("Unexpected blob status %+v", status)
+				}
+			},
+		},
+		{
+			name: "CreateBlob_Fail",
+			testFunc: func(t *testing.T, repo IBlobRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectExec(`INSERT INTO blobs`).
+					WithArgs("blob", 1, 3).
+					WillReturnError(fmt.Errorf("database error"))
+				mock.ExpectRollback()
+
+				if _, err := repo.CreateBlob(ctx, 1, "blob", 3); err == nil {
+					t.Errorf("Expected error, got nil")
+				}
+			},
+		},
+		{
+			name: "GetBlobStatus_Success",
+			testFunc: func(t *testing.T, repo IBlobRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT b.id, b.chunk_count, (.+) FROM blobs b WHERE b.user_id = \$1 AND b.id = \$2`).
+					WithArgs(1, "blob").
+					WillReturnRows(statusRows(3))
+				mock.ExpectCommit()
+
+				status, err := repo.GetBlobStatus(ctx, 1, "blob")
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if !status.Complete() {
+					t.Errorf("Expected complete blob, got %+v", status)
+				}
+			},
+		},
+		{
+			name: "GetBlobStatus_Fail_NotFound",
+			testFunc: func(t *testing.T, repo IBlobRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "2")
+				mock.ExpectQuery(`SELECT b.id, b.chunk_count, (.+) FROM blobs b`).
+					WithArgs(2, "blob").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+
+				_, err := repo.GetBlobStatus(ctx, 2, "blob")
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+		{
+			name: "SaveChunk_Success",
+			testFunc: func(t *testing.T, repo IBlobRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectExec(`INSERT INTO blob_chunks \(user_id, blob_id, chunk_index\) VALUES \(\$1, \$2, \$3\)\s+ON CONFLICT \(user_id, blob_id, chunk_index\) DO NOTHING`).
+					WithArgs(1, "blob", 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				if err := repo.SaveChunk(ctx, 1, "blob", 2); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "SaveChunk_Fail",
+			testFunc: func(t *testing.T, repo IBlobRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectExec(`INSERT INTO blob_chunks`).
+					WithArgs(1, "blob", 2).
+					WillReturnError(fmt.Errorf("foreign key violation"))
+				mock.ExpectRollback()
+
+				if err := repo.SaveChunk(ctx, 1, "blob", 2); err == nil {
+					t.Errorf("Expected error, got nil")
+				}
+			},
+		},
+		{
+			name: "DeleteUnreferenced_Success",
+			testFunc: func(t *testing.T, repo IBlobRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`DELETE FROM blobs b WHERE b.user_id = \$1 AND b.id IN \(\$2, \$3\) AND NOT EXISTS (.+)\s+AND NOT EXISTS (.+) RETURNING b.id`).
+					WithArgs(1, "old", "shared").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("old"))
+				mock.ExpectCommit()
+
+				deleted, err := repo.DeleteUnreferenced(ctx, 1, []string{"old", "shared"})
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if len(deleted) != 1 || deleted[0] != "old" {
+					t.Errorf("Expected deleted blobs [old], got %v", deleted)
+				}
+			},
+		},
+		{
+			name: "DeleteUnreferenced_Empty",
+			testFunc: func(t *testing.T, repo IBlobRepository, mock sqlmock.Sqlmock) {
+				deleted, err := repo.DeleteUnreferenced(ctx, 1, nil)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if len(deleted) != 0 {
+					t.Errorf("Expected no deleted blobs, got %v", deleted)
+				}
+			},
+		},
+		{
+			name: "DeleteUnreferenced_Fail",
+			testFunc: func(t *testing.T, repo IBlobRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`DELETE FROM blobs b`).
+					WithArgs(1, "old").
+					WillReturnError(fmt.Errorf("database error"))
+				mock.ExpectRollback()
+
+				if _, err := repo.DeleteUnreferenced(ctx, 1, []string{"old"}); err == nil {
+					t.Errorf("Expected error, got nil")
+				}
+			},
+		},
+		{
+			name: "DeleteOrphans_Success",
+			testFunc: func(t *testing.T, repo IBlobRepository, mock sqlmock.Sqlmock) {
+				before := time.Now()
+
+				mock.ExpectBegin()
+				mock.ExpectExec(`SELECT set_config\('app.collect_blobs', 'on', true\)`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`DELETE FROM blobs b WHERE b.created_at < \$1 AND NOT EXISTS (.+)\s+AND NOT EXISTS (.+) RETURNING b.user_id, b.id`).
+					WithArgs(before).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "id"}).AddRow(1, "abandoned").AddRow(2, "purged"))
+				mock.ExpectCommit()
+
+				deleted, err := repo.DeleteOrphans(ctx, before)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				expected := []serverModels.BlobRef{{UserID: 1, ID: "abandoned"}, {UserID: 2, ID: "purged"}}
+				if !reflect.DeepEqual(deleted, expected) {
+					t.Errorf("Expected deleted blobs %v, got %v", expected, deleted)
+				}
+			},
+		},
+		{
+			name: "DeleteOrphans_Fail",
+			testFunc: func(t *testing.T, repo IBlobRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`SELECT set_config\('app.collect_blobs', 'on', true\)`).
+					WillReturnError(fmt.Errorf("database error"))
+				mock.ExpectRollback()
+
+				_, err := repo.DeleteOrphans(ctx, time.Now())
+				if err == nil || err.Error() != "failed to enable blob collection: database error" {
+					t.Errorf("Expected error 'failed to enable blob collection: database error', got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock: %v", err)
+			}
+			defer db.Close()
+
+			repo := NewBlobRepository(sqlx.NewDb(db, "sqlmock"))
+
+			tc.testFunc(t, repo, mock)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+// Package repository предоставляет доступ к экстренным доступам доверенных пользователей к хранилищам.
+package repository
+
+import (
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+// IEmergencyRepository определяет интерфейс для репозитория экстренных доступов.
+type IEmergencyRepository interface {
+	Create(ctx context.Context, access *models.EmergencyAccess) error
+	ListByGrantor(ctx context.Context, grantorID uint64) ([]*models.EmergencyAccess, error)
+	ListByGrantee(ctx context.Context, granteeID uint64) ([]*models.EmergencyAccess, error)
+	Get(ctx context.Context, accessID uint64) (*models.EmergencyAccess, error)
+	Request(ctx context.Context, accessID uint64) (time.Time, error)
+	Reset(ctx context.Context, accessID uint64) error
+	Delete(ctx context.Context, accessID uint64) error
+	GrantExpired(ctx context.Context) ([]*models.EmergencyAccess, error)
+}
+
+// EmergencyRepository обеспечивает методы для работы с экстренными доступами в базе данных.
+type EmergencyRepository struct {
+	db *sqlx.DB
+}
+
+// emergencyColumns - столбцы экстренного доступа a вместе с логинами владельца g и доверенного пользователя e.
+const emergencyColumns = `a.id, a.grantor_id, g.login AS grantor_login, a.grantee_id, e.login AS grantee_login,
+	a.wait_days, a.status, a.requested_at, a.created_at`
+
+// emergencyJoins - соединение экстренных доступов a с владельцами g и доверенными пользователями e.
+const emergencyJoins = ` FROM emergency_access a JOIN users g ON g.id = a.grantor_id JOIN users e ON e.id = a.grantee_id`
+
+// NewEmergencyRepository создаёт новый экземпляр EmergencyRepository.
+// Принимает подключение к базе данных sqlx.DB и возвращает указатель на EmergencyRepository.
+func NewEmergencyRepository(db *sqlx.DB) IEmergencyRepository {
+	return &EmergencyRepository{
+		db: db,
+	}
+}
+
+// Create назначает пользователя access.GranteeID доверенным для хранилища пользователя access.GrantorID.
+// Повторное назначение заменяет срок ожидания и ключ хранилища и отменяет запрос доступа, если он был:
+// так владелец заново выдаёт ключ доверенному пользователю, сменившему пару ключей.
+// Идентификатор, состояние и время назначения записываются в access.
+func (r *EmergencyRepository) Create(ctx context.Context, access *models.EmergencyAccess) error {
+	query := `INSERT INTO emergency_access (grantor_id, grantee_id, wait_days, vault_key) VALUES ($1, $2, $3, $4)
+	ON CONFLICT (grantor_id, grantee_id) DO UPDATE
+	SET wait_days = EXCLUDED.wait_days, vault_key = EXCLUDED.vault_key, status = 'idle', requested_at = NULL
+	RETURNING id, status, created_at`
+	return r.db.QueryRowxContext(ctx, query, access.GrantorID, access.GranteeID, access.WaitDays, access.VaultKey).
+		Scan(&access.ID, &access.Status, &access.CreatedAt)
+}
+
+// ListByGrantor возвращает доверенных пользователей владельца хранилища grantorID, упорядоченных по логину.
+// Ключи хранилища не возвращаются.
+func (r *EmergencyRepository) ListByGrantor(ctx context.Context, grantorID uint64) ([]*models.EmergencyAccess, error) {
+	var accesses []*models.EmergencyAccess
+
+	query := "SELECT " + emergencyColumns + emergencyJoins + " WHERE a.grantor_id = $1 ORDER BY e.login"
+	if err := r.db.SelectContext(ctx, &accesses, query, grantorID); err != nil {
+		return nil, err
+	}
+
+	return accesses, nil
+}
+
+// ListByGrantee возвращает владельцев хранилищ, назначивших пользователя granteeID доверенным,
+// упорядоченных по логину. Ключ хранилища владельца возвращается только после предоставления доступа.
+func (r *EmergencyRepository) ListByGrantee(ctx context.Context, granteeID uint64) ([]*models.EmergencyAccess, error) {
+	var accesses []*models.EmergencyAccess
+
+	query := "SELECT " + emergencyColumns + `, CASE WHEN a.status = 'granted' THEN a.vault_key END AS vault_key` +
+		emergencyJoins + " WHERE a.grantee_id = $1 ORDER BY g.login"
+	if err := r.db.SelectContext(ctx, &accesses, query, granteeID); err != nil {
+		return nil, err
+	}
+
+	return accesses, nil
+}
+
+// Get возвращает экстренный доступ accessID вместе с ключом хранилища.
+// Возвращает ErrNotFound, если доступ не найден.
+func (r *EmergencyRepository) Get(ctx context.Context, accessID uint64) (*models.EmergencyAccess, error) {
+	var access models.EmergencyAccess
+
+	query := "SELECT " + emergencyColumns + ", a.vault_key" + emergencyJoins + " WHERE a.id = $1"
+	if err := r.db.QueryRowxContext(ctx, query, accessID).StructScan(&access); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("emergency access %w (id=%d)", gophKeeperErrors.ErrNotFound, accessID)
+		}
+		return nil, err
+	}
+
+	return &access, nil
+}
+
+// Request переводит неактивный экстренный доступ accessID в состояние запроса и возвращает время запроса.
+// Возвращает ErrNotFound, если доступ не найден или уже запрошен.
+func (r *EmergencyRepository) Request(ctx context.Context, accessID uint64) (time.Time, error) {
+	var requestedAt time.Time
+
+	query := `UPDATE emergency_access SET status = 'requested', requested_at = NOW()
+	WHERE id = $1 AND status = 'idle' RETURNING requested_at`
+	if err := r.db.QueryRowxContext(ctx, query, accessID).Scan(&requestedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, fmt.Errorf("emergency access %w (id=%d)", gophKeeperErrors.ErrNotFound, accessID)
+		}
+		return time.Time{}, err
+	}
+
+	return requestedAt, nil
+}
+
+// Reset отклоняет запрос экстренного доступа accessID или отзывает предоставленный доступ,
+// возвращая его в неактивное состояние. Возвращает ErrNotFound, если доступ не найден или неактивен.
+func (r *EmergencyRepository) Reset(ctx context.Context, accessID uint64) error {
+	query := "UPDATE emergency_access SET status = 'idle', requested_at = NULL WHERE id = $1 AND status <> 'idle'"
+	result, err := r.db.ExecContext(ctx, query, accessID)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result, "emergency access", accessID)
+}
+
+// Delete удаляет экстренный доступ accessID.
+// Возвращает ErrNotFound, если доступ не найден.
+func (r *EmergencyRepository) Delete(ctx context.Context, accessID uint64) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM emergency_access WHERE id = $1", accessID)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result, "emergency access", accessID)
+}
+
+// GrantExpired предоставляет все запрошенные экстренные доступы, срок ожидания которых истёк,
+// и возвращает их идентификаторы вместе с идентификаторами владельцев и доверенных пользователей.
+func (r *EmergencyRepository) GrantExpired(ctx context.Context) ([]*models.EmergencyAccess, error) {
+	var accesses []*models.EmergencyAccess
+
+	query := `UPDATE emergency_access SET status = 'granted'
+	WHERE status = 'requested' AND requested_at + wait_days * INTERVAL '1 day' <= NOW()
+	RETURNING id, grantor_id, grantee_id`
+	if err := r.db.SelectContext(ctx, &accesses, query); err != nil {
+		return nil, err
+	}
+
+	return accesses, nil
+}
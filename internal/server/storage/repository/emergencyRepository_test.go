@@ -0,0 +1,186 @@
+package repository
+
+import (
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"testing"
+	"time"
+)
+
+func TestEmergencyRepository(t *testing.T) {
+	ctx := context.Background()
+	accessRows := []string{"id", "grantor_id", "grantor_login", "grantee_id", "grantee_login", "wait_days", "status", "requested_at", "created_at"}
+
+	tests := []struct {
+		name     string
+		testFunc func(t *testing.T, repo IEmergencyRepository, mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "Create_Success",
+			testFunc: func(t *testing.T, repo IEmergencyRepository, mock sqlmock.Sqlmock) {
+				createdAt := time.Now()
+				mock.ExpectQuery(`INSERT INTO emergency_access \(grantor_id, grantee_id, wait_days, vault_key\) VALUES \(\$1, \$2, \$3, \$4\)
+	ON CONFLICT \(grantor_id, grantee_id\) DO UPDATE`).
+					WithArgs(1, 2, 7, []byte("vault_key")).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(5, "idle", createdAt))
+
+				access := &models.EmergencyAccess{GrantorID: 1, GranteeID: 2, WaitDays: 7, VaultKey: []byte("vault_key")}
+				if err := repo.Create(ctx, access); err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if access.ID != 5 || access.Status != models.EmergencyIdle || !access.CreatedAt.Equal(createdAt) {
+					t.Errorf("Expected idle access 5, got %+v", access)
+				}
+			},
+		},
+		{
+			name: "ListByGrantor_Success",
+			testFunc: func(t *testing.T, repo IEmergencyRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT a.id, .+ FROM emergency_access a JOIN users g ON g.id = a.grantor_id JOIN users e ON e.id = a.grantee_id WHERE a.grantor_id = \$1 ORDER BY e.login`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(accessRows).
+						AddRow(5, 1, "alice", 2, "bob", 7, "requested", time.Now(), time.Now()).
+						AddRow(6, 1, "alice", 3, "carol", 3, "idle", nil, time.Now()))
+
+				accesses, err := repo.ListByGrantor(ctx, 1)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if len(accesses) != 2 || accesses[0].GranteeLogin != "bob" || accesses[0].RequestedAt == nil || accesses[1].RequestedAt != nil {
+					t.Errorf("Unexpected accesses: %+v", accesses)
+				}
+			},
+		},
+		{
+			name: "ListByGrantee_Success",
+			testFunc: func(t *testing.T, repo IEmergencyRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT a.id, .+, CASE WHEN a.status = 'granted' THEN a.vault_key END AS vault_key FROM emergency_access a .+ WHERE a.grantee_id = \$1 ORDER BY g.login`).
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows(append(accessRows, "vault_key")).
+						AddRow(5, 1, "alice", 2, "bob", 7, "granted", time.Now(), time.Now(), []byte("vault_key")))
+
+				accesses, err := repo.ListByGrantee(ctx, 2)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if len(accesses) != 1 || accesses[0].Status != models.EmergencyGranted || string(accesses[0].VaultKey) != "vault_key" {
+					t.Errorf("Unexpected accesses: %+v", accesses)
+				}
+			},
+		},
+		{
+			name: "Get_Fail_NotFound",
+			testFunc: func(t *testing.T, repo IEmergencyRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT a.id, .+, a.vault_key FROM emergency_access a .+ WHERE a.id = \$1`).
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows(append(accessRows, "vault_key")))
+
+				_, err := repo.Get(ctx, 5)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+		{
+			name: "Request_Success",
+			testFunc: func(t *testing.T, repo IEmergencyRepository, mock sqlmock.Sqlmock) {
+				requestedAt := time.Now()
+				mock.ExpectQuery(`UPDATE emergency_access SET status = 'requested', requested_at = NOW\(\)
+	WHERE id = \$1 AND status = 'idle' RETURNING requested_at`).
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows([]string{"requested_at"}).AddRow(requestedAt))
+
+				got, err := repo.Request(ctx, 5)
+				if err != nil || !got.Equal(requestedAt) {
+					t.Errorf("Expected request time %v, got %v, err = %v", requestedAt, got, err)
+				}
+			},
+		},
+		{
+			name: "Request_Fail_NotIdle",
+			testFunc: func(t *testing.T, repo IEmergencyRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`UPDATE emergency_access SET status = 'requested'`).
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows([]string{"requested_at"}))
+
+				if _, err := repo.Request(ctx, 5); !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+		{
+			name: "Reset_Success",
+			testFunc: func(t *testing.T, repo IEmergencyRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE emergency_access SET status = 'idle', requested_at = NULL WHERE id = \$1 AND status <> 'idle'`).
+					WithArgs(5).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				if err := repo.Reset(ctx, 5); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Delete_Fail_NotFound",
+			testFunc: func(t *testing.T, repo IEmergencyRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`DELETE FROM emergency_access WHERE id = \$1`).
+					WithArgs(5).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				if err := repo.Delete(ctx, 5); !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+		{
+			name: "GrantExpired_Success",
+			testFunc: func(t *testing.T, repo IEmergencyRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`UPDATE emergency_access SET status = 'granted'
+	WHERE status = 'requested' AND requested_at \+ wait_days \* INTERVAL '1 day' <= NOW\(\)
+	RETURNING id, grantor_id, grantee_id`).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "grantor_id", "grantee_id"}).AddRow(5, 1, 2))
+
+				accesses, err := repo.GrantExpired(ctx)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if len(accesses) != 1 || accesses[0].ID != 5 || accesses[0].GrantorID != 1 || accesses[0].GranteeID != 2 {
+					t.Errorf("Unexpected granted accesses: %+v", accesses)
+				}
+			},
+		},
+		{
+			name: "GrantExpired_Fail",
+			testFunc: func(t *testing.T, repo IEmergencyRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`UPDATE emergency_access SET status = 'granted'`).
+					WillReturnError(errors.New("db down"))
+
+				if _, err := repo.GrantExpired(ctx); err == nil {
+					t.Error("Expected error, got nil")
+				}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock: %v", err)
+			}
+			defer db.Close()
+
+			repo := NewEmergencyRepository(sqlx.NewDb(db, "sqlmock"))
+
+			tc.testFunc(t, repo, mock)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+// Package repository предоставляет доступ к папкам и меткам секретов, хранящимся в базе данных.
+package repository
+
+import (
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"slices"
+)
+
+// uniqueViolation - код ошибки PostgreSQL о нарушении ограничения уникальности.
+const uniqueViolation = "23505"
+
+// IFolderRepository определяет интерфейс для репозитория папок и меток секретов.
+type IFolderRepository interface {
+	ListFolders(ctx context.Context, userID uint64) ([]*models.Folder, error)
+	CreateFolder(ctx context.Context, folder *models.Folder) (uint64, error)
+	UpdateFolder(ctx context.Context, folder *models.Folder) error
+	DeleteFolder(ctx context.Context, userID, folderID uint64) error
+	ListTags(ctx context.Context, userID uint64) ([]*models.Tag, error)
+	CreateTag(ctx context.Context, tag *models.Tag) (uint64, error)
+	UpdateTag(ctx context.Context, tag *models.Tag) error
+	DeleteTag(ctx context.Context, userID, tagID uint64) error
+}
+
+// FolderRepository обеспечивает методы для работы с папками и метками секретов в базе данных.
+type FolderRepository struct {
+	db *sqlx.DB
+}
+
+// folderColumns - столбцы папки; у папки верхнего уровня идентификатор родителя равен 0.
+const folderColumns = `id, user_id, COALESCE(parent_id, 0) AS parent_id, name, created_at`
+
+// NewFolderRepository создаёт новый экземпляр FolderRepository.
+// Принимает подключение к базе данных sqlx.DB и возвращает указатель на FolderRepository.
+func NewFolderRepository(db *sqlx.DB) IFolderRepository {
+	return &FolderRepository{
+		db: db,
+	}
+}
+
+// ListFolders возвращает все папки пользователя, упорядоченные по названию.
+func (r *FolderRepository) ListFolders(ctx context.Context, userID uint64) ([]*models.Folder, error) {
+	var folders []*models.Folder
+
+	err := runAsUser(ctx, r.db, userID, func(tx *sqlx.Tx) error {
+		query := "SELECT " + folderColumns + " FROM folders WHERE user_id = $1 ORDER BY name, id"
+		return tx.SelectContext(ctx, &folders, query, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return folders, nil
+}
+
+// CreateFolder добавляет папку пользователя и возвращает её идентификатор.
+// Возвращает ErrNotFound, если родительская папка не найдена, и ErrAlreadyExists, если название занято.
+func (r *FolderRepository) CreateFolder(ctx context.Context, folder *models.Folder) (uint64, error) {
+	var folderID uint64
+
+	err := runAsUser(ctx, r.db, folder.UserID, func(tx *sqlx.Tx) error {
+		if err := checkFolder(ctx, tx, folder.UserID, folder.ParentID); err != nil {
+			return err
+		}
+
+		query := `INSERT INTO folders (user_id, parent_id, name) VALUES ($1, NULLIF($2, 0), $3) RETURNING id`
+		return tx.QueryRowxContext(ctx, query, folder.UserID, folder.ParentID, folder.Name).Scan(&folderID)
+	})
+	if err != nil {
+		return 0, folderError(err, folder.Name)
+	}
+
+	return folderID, nil
+}
+
+// UpdateFolder переименовывает папку пользователя или перемещает её в другую родительскую папку.
+// Возвращает ErrNotFound, если папка или родительская папка не найдены, ErrInvalidFolder при перемещении
+// папки в саму себя или во вложенную в неё папку и ErrAlreadyExists, если название занято.
+func (r *FolderRepository) UpdateFolder(ctx context.Context, folder *models.Folder) error {
+	err := runAsUser(ctx, r.db, folder.UserID, func(tx *sqlx.Tx) error {
+		if err := checkFolder(ctx, tx, folder.UserID, folder.ParentID); err != nil {
+			return err
+		}
+
+		if folder.ParentID != 0 {
+			var cycle bool
+			query := `WITH RECURSIVE subtree AS (
+				SELECT id FROM folders WHERE id = $1
+				UNION ALL SELECT f.id FROM folders f JOIN subtree s ON f.parent_id = s.id
+			) SELECT EXISTS (SELECT 1 FROM subtree WHERE id = $2)`
+			if err := tx.QueryRowxContext(ctx, query, folder.ID, folder.ParentID).Scan(&cycle); err != nil {
+				return err
+			}
+			if cycle {
+				return gophKeeperErrors.ErrInvalidFolder
+			}
+		}
+
+		query := `UPDATE folders SET parent_id = NULLIF($1, 0), name = $2 WHERE id = $3 AND user_id = $4`
+		result, err := tx.ExecContext(ctx, query, folder.ParentID, folder.Name, folder.ID, folder.UserID)
+		if err != nil {
+			return err
+		}
+		return expectAffected(result, "folder", folder.ID)
+	})
+	if err != nil {
+		return folderError(err, folder.Name)
+	}
+
+	return nil
+}
+
+// DeleteFolder удаляет папку пользователя вместе с вложенными папками.
+// Секреты из удалённых папок остаются без папки. Возвращает ErrNotFound, если папка не найдена.
+func (r *FolderRepository) DeleteFolder(ctx context.Context, userID, folderID uint64) error {
+	return runAsUser(ctx, r.db, userID, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, "DELETE FROM folders WHERE id = $1 AND user_id = $2", folderID, userID)
+		if err != nil {
+			return err
+		}
+		return expectAffected(result, "folder", folderID)
+	})
+}
+
+// ListTags возвращает все метки пользователя, упорядоченные по названию.
+func (r *FolderRepository) ListTags(ctx context.Context, userID uint64) ([]*models.Tag, error) {
+	var tags []*models.Tag
+
+	err := runAsUser(ctx, r.db, userID, func(tx *sqlx.Tx) error {
+		query := "SELECT id, user_id, name, created_at FROM tags WHERE user_id = $1 ORDER BY name, id"
+		return tx.SelectContext(ctx, &tags, query, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// CreateTag добавляет метку пользователя и возвращает её идентификатор.
+// Возвращает ErrAlreadyExists, если название занято.
+func (r *FolderRepository) CreateTag(ctx context.Context, tag *models.Tag) (uint64, error) {
+	var tagID uint64
+
+	err := runAsUser(ctx, r.db, tag.UserID, func(tx *sqlx.Tx) error {
+		query := `INSERT INTO tags (user_id, name) VALUES ($1, $2) RETURNING id`
+		return tx.QueryRowxContext(ctx, query, tag.UserID, tag.Name).Scan(&tagID)
+	})
+	if err != nil {
+		return 0, folderError(err, tag.Name)
+	}
+
+	return tagID, nil
+}
+
+// UpdateTag переименовывает метку пользователя.
+// Возвращает ErrNotFound, если метка не найдена, и ErrAlreadyExists, если название занято.
+func (r *FolderRepository) UpdateTag(ctx context.Context, tag *models.Tag) error {
+	err := runAsUser(ctx, r.db, tag.UserID, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, "UPDATE tags SET name = $1 WHERE id = $2 AND user_id = $3", tag.Name, tag.ID, tag.UserID)
+		if err != nil {
+			return err
+		}
+		return expectAffected(result, "tag", tag.ID)
+	})
+	if err != nil {
+		return folderError(err, tag.Name)
+	}
+
+	return nil
+}
+
+// DeleteTag удаляет метку пользователя и снимает её с секретов. Секреты с этой меткой получают
+// новый номер изменения, чтобы клиенты узнали о снятии метки при инкрементальной синхронизации.
+// Возвращает ErrNotFound, если метка не найдена.
+func (r *FolderRepository) DeleteTag(ctx context.Context, userID, tagID uint64) error {
+	return runAsUser(ctx, r.db, userID, func(tx *sqlx.Tx) error {
+		query := `UPDATE secrets SET change_revision = change_revision
+		WHERE id IN (SELECT secret_id FROM secret_tags WHERE tag_id = $1 AND user_id = $2)`
+		if _, err := tx.ExecContext(ctx, query, tagID, userID); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, "DELETE FROM tags WHERE id = $1 AND user_id = $2", tagID, userID)
+		if err != nil {
+			return err
+		}
+		return expectAffected(result, "tag", tagID)
+	})
+}
+
+// checkFolder проверяет, что папка принадлежит пользователю. Нулевой идентификатор означает отсутствие папки.
+func checkFolder(ctx context.Context, tx *sqlx.Tx, userID, folderID uint64) error {
+	if folderID == 0 {
+		return nil
+	}
+
+	var exists bool
+	query := "SELECT EXISTS (SELECT 1 FROM folders WHERE id = $1 AND user_id = $2)"
+	if err := tx.QueryRowxContext(ctx, query, folderID, userID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("folder %w (id=%d)", gophKeeperErrors.ErrNotFound, folderID)
+	}
+	return nil
+}
+
+// replaceSecretTags заменяет метки секрета, предварительно проверив, что все они принадлежат пользователю.
+// Возвращает ErrNotFound, если какая-либо метка не найдена.
+func replaceSecretTags(ctx context.Context, tx *sqlx.Tx, userID, secretID uint64, tagIDs []uint64) error {
+	tagIDs = slices.Compact(slices.Sorted(slices.Values(tagIDs)))
+
+	if len(tagIDs) > 0 {
+		query, args, err := sqlx.In("SELECT count(*) FROM tags WHERE user_id = ? AND id IN (?)", userID, tagIDs)
+		if err != nil {
+			return err
+		}
+
+		var found int
+		if err = tx.QueryRowxContext(ctx, sqlx.Rebind(sqlx.DOLLAR, query), args...).Scan(&found); err != nil {
+			return err
+		}
+		if found != len(tagIDs) {
+			return fmt.Errorf("tag %w", gophKeeperErrors.ErrNotFound)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM secret_tags WHERE secret_id = $1 AND user_id = $2", secretID, userID); err != nil {
+		return err
+	}
+
+	for _, tagID := range tagIDs {
+		query := "INSERT INTO secret_tags (secret_id, tag_id, user_id) VALUES ($1, $2, $3)"
+		if _, err := tx.ExecContext(ctx, query, secretID, tagID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expectAffected возвращает ErrNotFound, если запрос не изменил ни одной строки.
+func expectAffected(result sql.Result, entity string, id uint64) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s %w (id=%d)", entity, gophKeeperErrors.ErrNotFound, id)
+	}
+	return nil
+}
+
+// folderError заменяет ошибку нарушения уникальности названия папки или метки на ErrAlreadyExists.
+func folderError(err error, name string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return fmt.Errorf("%q %w", name, gophKeeperErrors.ErrAlreadyExists)
+	}
+	return err
+}
@@ -0,0 +1,256 @@
+package repository
+
+import (
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"testing"
+	"time"
+)
+
+func TestFolderRepository(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		testFunc func(t *testing.T, repo IFolderRepository, mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "ListFolders_Success",
+			testFunc: func(t *testing.T, repo IFolderRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT id, user_id, COALESCE\(parent_id, 0\) AS parent_id, name, created_at FROM folders WHERE user_id = \$1 ORDER BY name, id`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "parent_id", "name", "created_at"}).
+						AddRow(1, 1, 0, "Banks", time.Now()).
+						AddRow(2, 1, 1, "Cards", time.Now()))
+				mock.ExpectCommit()
+
+				folders, err := repo.ListFolders(ctx, 1)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if len(folders) != 2 || folders[1].ParentID != 1 {
+					t.Errorf("Expected 2 folders with nested Cards, got %+v", folders)
+				}
+			},
+		},
+		{
+			name: "CreateFolder_Success",
+			testFunc: func(t *testing.T, repo IFolderRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM folders WHERE id = \$1 AND user_id = \$2\)`).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery(`INSERT INTO folders \(user_id, parent_id, name\) VALUES \(\$1, NULLIF\(\$2, 0\), \$3\) RETURNING id`).
+					WithArgs(1, 1, "Cards").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectCommit()
+
+				id, err := repo.CreateFolder(ctx, &models.Folder{UserID: 1, ParentID: 1, Name: "Cards"})
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if id != 2 {
+					t.Errorf("Expected ID 2, got %d", id)
+				}
+			},
+		},
+		{
+			name: "CreateFolder_Fail_Duplicate",
+			testFunc: func(t *testing.T, repo IFolderRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`INSERT INTO folders (.+) RETURNING id`).
+					WithArgs(1, 0, "Banks").
+					WillReturnError(&pgconn.PgError{Code: uniqueViolation})
+				mock.ExpectRollback()
+
+				_, err := repo.CreateFolder(ctx, &models.Folder{UserID: 1, Name: "Banks"})
+				if !errors.Is(err, gophKeeperErrors.ErrAlreadyExists) {
+					t.Errorf("Expected error 'ErrAlreadyExists', got %v", err)
+				}
+			},
+		},
+		{
+			name: "UpdateFolder_Success",
+			testFunc: func(t *testing.T, repo IFolderRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM folders WHERE id = \$1 AND user_id = \$2\)`).
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery(`WITH RECURSIVE subtree AS (.+) SELECT EXISTS \(SELECT 1 FROM subtree WHERE id = \$2\)`).
+					WithArgs(2, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectExec(`UPDATE folders SET parent_id = NULLIF\(\$1, 0\), name = \$2 WHERE id = \$3 AND user_id = \$4`).
+					WithArgs(3, "Cards", 2, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				if err := repo.UpdateFolder(ctx, &models.Folder{ID: 2, UserID: 1, ParentID: 3, Name: "Cards"}); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "UpdateFolder_Fail_Cycle",
+			testFunc: func(t *testing.T, repo IFolderRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM folders WHERE id = \$1 AND user_id = \$2\)`).
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery(`WITH RECURSIVE subtree AS (.+)`).
+					WithArgs(2, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectRollback()
+
+				err := repo.UpdateFolder(ctx, &models.Folder{ID: 2, UserID: 1, ParentID: 3, Name: "Cards"})
+				if !errors.Is(err, gophKeeperErrors.ErrInvalidFolder) {
+					t.Errorf("Expected error 'ErrInvalidFolder', got %v", err)
+				}
+			},
+		},
+		{
+			name: "UpdateFolder_Fail_NotFound",
+			testFunc: func(t *testing.T, repo IFolderRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectExec(`UPDATE folders SET (.+)`).
+					WithArgs(0, "Cards", 2, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+
+				err := repo.UpdateFolder(ctx, &models.Folder{ID: 2, UserID: 1, Name: "Cards"})
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+		{
+			name: "DeleteFolder_Success",
+			testFunc: func(t *testing.T, repo IFolderRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectExec(`DELETE FROM folders WHERE id = \$1 AND user_id = \$2`).
+					WithArgs(2, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				if err := repo.DeleteFolder(ctx, 1, 2); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "ListTags_Success",
+			testFunc: func(t *testing.T, repo IFolderRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT id, user_id, name, created_at FROM tags WHERE user_id = \$1 ORDER BY name, id`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "created_at"}).AddRow(1, 1, "work", time.Now()))
+				mock.ExpectCommit()
+
+				tags, err := repo.ListTags(ctx, 1)
+				if err != nil || len(tags) != 1 || tags[0].Name != "work" {
+					t.Errorf("Expected tag 'work', got %+v, %v", tags, err)
+				}
+			},
+		},
+		{
+			name: "CreateTag_Success",
+			testFunc: func(t *testing.T, repo IFolderRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`INSERT INTO tags \(user_id, name\) VALUES \(\$1, \$2\) RETURNING id`).
+					WithArgs(1, "work").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+				mock.ExpectCommit()
+
+				id, err := repo.CreateTag(ctx, &models.Tag{UserID: 1, Name: "work"})
+				if err != nil || id != 4 {
+					t.Errorf("Expected tag ID 4, got %d, %v", id, err)
+				}
+			},
+		},
+		{
+			name: "UpdateTag_Fail_Duplicate",
+			testFunc: func(t *testing.T, repo IFolderRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectExec(`UPDATE tags SET name = \$1 WHERE id = \$2 AND user_id = \$3`).
+					WithArgs("home", 4, 1).
+					WillReturnError(&pgconn.PgError{Code: uniqueViolation})
+				mock.ExpectRollback()
+
+				err := repo.UpdateTag(ctx, &models.Tag{ID: 4, UserID: 1, Name: "home"})
+				if !errors.Is(err, gophKeeperErrors.ErrAlreadyExists) {
+					t.Errorf("Expected error 'ErrAlreadyExists', got %v", err)
+				}
+			},
+		},
+		{
+			name: "DeleteTag_Success",
+			testFunc: func(t *testing.T, repo IFolderRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectExec(`UPDATE secrets SET change_revision = change_revision\s+WHERE id IN \(SELECT secret_id FROM secret_tags WHERE tag_id = \$1 AND user_id = \$2\)`).
+					WithArgs(4, 1).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(`DELETE FROM tags WHERE id = \$1 AND user_id = \$2`).
+					WithArgs(4, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				if err := repo.DeleteTag(ctx, 1, 4); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "DeleteTag_Fail_NotFound",
+			testFunc: func(t *testing.T, repo IFolderRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectExec(`UPDATE secrets SET change_revision = change_revision (.+)`).
+					WithArgs(4, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`DELETE FROM tags WHERE id = \$1 AND user_id = \$2`).
+					WithArgs(4, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+
+				err := repo.DeleteTag(ctx, 1, 4)
+				if err == nil || err.Error() != "tag not found (id=4)" {
+					t.Errorf("Expected error 'tag not found (id=4)', got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock: %v", err)
+			}
+			defer db.Close()
+
+			repo := NewFolderRepository(sqlx.NewDb(db, "sqlmock"))
+
+			tc.testFunc(t, repo, mock)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
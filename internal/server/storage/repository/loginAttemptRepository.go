@@ -0,0 +1,129 @@
+// Package repository предоставляет доступ к журналу попыток входа.
+package repository
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/models"
+	"context"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+// ILoginAttemptRepository определяет интерфейс для репозитория попыток входа.
+type ILoginAttemptRepository interface {
+	Reserve(ctx context.Context, login, peerAddress string, loginSince, peerSince time.Time, check func(loginStats, peerStats *models.FailureStats) error) (uint64, error)
+	MarkSuccess(ctx context.Context, attemptID uint64) error
+	Delete(ctx context.Context, attemptID uint64) error
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// LoginAttemptRepository предоставляет методы для учёта попыток входа в базе данных.
+type LoginAttemptRepository struct {
+	db *sqlx.DB
+}
+
+// NewLoginAttemptRepository создаёт новый экземпляр LoginAttemptRepository.
+// Функция принимает подключение к базе данных SQLX и возвращает указатель на LoginAttemptRepository.
+func NewLoginAttemptRepository(db *sqlx.DB) ILoginAttemptRepository {
+	return &LoginAttemptRepository{
+		db: db,
+	}
+}
+
+// Reserve в одной транзакции получает неудачные попытки входа под логином login, сделанные после loginSince,
+// и с адреса peerAddress, сделанные после peerSince, и передаёт их check. Если check не вернул ошибку,
+// сохраняется новая попытка, которая считается неудачной, пока не отмечена MarkSuccess или не удалена.
+// Транзакции для одного логина и одного адреса выполняются по очереди под рекомендательными блокировками,
+// поэтому параллельные попытки не проходят проверку до того, как учтены предыдущие.
+// Попытка привязывается к пользователю с этим логином, если он существует. Возвращает идентификатор попытки
+// или ошибку check.
+func (r *LoginAttemptRepository) Reserve(ctx context.Context, login, peerAddress string, loginSince, peerSince time.Time, check func(loginStats, peerStats *models.FailureStats) error) (uint64, error) {
+	var attemptID uint64
+	err := runInTx(r.db, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext('login:' || $1))", login); err != nil {
+			return err
+		}
+		loginStats, err := loginFailures(ctx, tx, login, loginSince)
+		if err != nil {
+			return err
+		}
+
+		peerStats := &models.FailureStats{}
+		if peerAddress != "" {
+			if _, err = tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext('peer:' || $1))", peerAddress); err != nil {
+				return err
+			}
+			if peerStats, err = peerFailures(ctx, tx, peerAddress, peerSince); err != nil {
+				return err
+			}
+		}
+
+		if err = check(loginStats, peerStats); err != nil {
+			return err
+		}
+
+		return tx.QueryRowxContext(ctx,
+			`INSERT INTO login_attempts (login, user_id, peer_address, success)
+			VALUES ($1, (SELECT id FROM users WHERE login = $1 AND NOT is_vault), $2, false) RETURNING id`,
+			login,
+			peerAddress,
+		).Scan(&attemptID)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return attemptID, nil
+}
+
+// MarkSuccess отмечает попытку входа attemptID как успешную: успешный вход сбрасывает счётчик логина.
+func (r *LoginAttemptRepository) MarkSuccess(ctx context.Context, attemptID uint64) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE login_attempts SET success = true WHERE id = $1", attemptID)
+	return err
+}
+
+// Delete удаляет попытку входа attemptID, завершившуюся без проверки учётных данных.
+func (r *LoginAttemptRepository) Delete(ctx context.Context, attemptID uint64) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM login_attempts WHERE id = $1", attemptID)
+	return err
+}
+
+// DeleteBefore удаляет попытки входа, сделанные до before, и возвращает их количество.
+func (r *LoginAttemptRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM login_attempts WHERE created_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// loginFailures возвращает неудачные попытки входа под логином, сделанные после since
+// и после последнего успешного входа: успешный вход сбрасывает счётчик логина.
+func loginFailures(ctx context.Context, q sqlx.QueryerContext, login string, since time.Time) (*models.FailureStats, error) {
+	var stats models.FailureStats
+	err := q.QueryRowxContext(ctx,
+		`SELECT count(*) AS count, max(created_at) AS last_at FROM login_attempts
+		WHERE login = $1 AND NOT success AND created_at > GREATEST($2,
+			COALESCE((SELECT max(created_at) FROM login_attempts WHERE login = $1 AND success), $2))`,
+		login,
+		since,
+	).StructScan(&stats)
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// peerFailures возвращает неудачные попытки входа с адреса клиента, сделанные после since.
+// Успешный вход с того же адреса счётчик не сбрасывает, иначе перебор чужих логинов
+// можно было бы чередовать со входом в собственную учётную запись.
+func peerFailures(ctx context.Context, q sqlx.QueryerContext, peerAddress string, since time.Time) (*models.FailureStats, error) {
+	var stats models.FailureStats
+	err := q.QueryRowxContext(ctx,
+		"SELECT count(*) AS count, max(created_at) AS last_at FROM login_attempts WHERE peer_address = $1 AND NOT success AND created_at > $2",
+		peerAddress,
+		since,
+	).StructScan(&stats)
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/models"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"testing"
+	"time"
+)
+
+func TestLoginAttemptRepository(t *testing.T) {
+	ctx := context.Background()
+	since := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		testFunc func(t *testing.T, repo ILoginAttemptRepository, mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "Reserve_Success",
+			testFunc: func(t *testing.T, repo ILoginAttemptRepository, mock sqlmock.Sqlmock) {
+				last := time.Now()
+				mock.ExpectBegin()
+				mock.ExpectExec(`SELECT pg_advisory_xact_lock\(hashtext\('login:' \|\| \$1\)\)`).
+					WithArgs("alice").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`SELECT count\(\*\) AS count, max\(created_at\) AS last_at FROM login_attempts\s+WHERE login = \$1 AND NOT success AND created_at > GREATEST\(\$2,\s+COALESCE\(\(SELECT max\(created_at\) FROM login_attempts WHERE login = \$1 AND success\), \$2\)\)`).
+					WithArgs("alice", since).
+					WillReturnRows(sqlmock.NewRows([]string{"count", "last_at"}).AddRow(3, last))
+				mock.ExpectExec(`SELECT pg_advisory_xact_lock\(hashtext\('peer:' \|\| \$1\)\)`).
+					WithArgs("10.0.0.1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`SELECT count\(\*\) AS count, max\(created_at\) AS last_at FROM login_attempts WHERE peer_address = \$1 AND NOT success AND created_at > \$2`).
+					WithArgs("10.0.0.1", since).
+					WillReturnRows(sqlmock.NewRows([]string{"count", "last_at"}).AddRow(0, nil))
+				mock.ExpectQuery(`INSERT INTO login_attempts \(login, user_id, peer_address, success\)\s+VALUES \(\$1, \(SELECT id FROM users WHERE login = \$1 AND NOT is_vault\), \$2, false\) RETURNING id`).
+					WithArgs("alice", "10.0.0.1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+				mock.ExpectCommit()
+
+				attemptID, err := repo.Reserve(ctx, "alice", "10.0.0.1", since, since, func(loginStats, peerStats *models.FailureStats) error {
+					if loginStats.Count != 3 || loginStats.LastAt == nil || !loginStats.LastAt.Equal(last) {
+						t.Errorf("Unexpected login stats %+v", loginStats)
+					}
+					if peerStats.Count != 0 || peerStats.LastAt != nil {
+						t.Errorf("Unexpected peer stats %+v", peerStats)
+					}
+					return nil
+				})
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if attemptID != 7 {
+					t.Errorf("Expected attempt 7, got %d", attemptID)
+				}
+			},
+		},
+		{
+			name: "Reserve_Rejected",
+			testFunc: func(t *testing.T, repo ILoginAttemptRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
+					WithArgs("alice").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`SELECT count\(\*\) AS count, max\(created_at\) AS last_at FROM login_attempts`).
+					WithArgs("alice", since).
+					WillReturnRows(sqlmock.NewRows([]string{"count", "last_at"}).AddRow(9, time.Now()))
+				mock.ExpectRollback()
+
+				locked := errors.New("locked")
+				_, err := repo.Reserve(ctx, "alice", "", since, since, func(_, peerStats *models.FailureStats) error {
+					if peerStats.Count != 0 {
+						t.Errorf("Expected empty peer stats for unknown peer, got %+v", peerStats)
+					}
+					return locked
+				})
+				if !errors.Is(err, locked) {
+					t.Errorf("Expected check error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Reserve_DatabaseError",
+			testFunc: func(t *testing.T, repo ILoginAttemptRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
+					WithArgs("alice").
+					WillReturnError(fmt.Errorf("database error"))
+				mock.ExpectRollback()
+
+				_, err := repo.Reserve(ctx, "alice", "10.0.0.1", since, since, func(_, _ *models.FailureStats) error {
+					t.Errorf("Check must not be called")
+					return nil
+				})
+				if err == nil || err.Error() != "database error" {
+					t.Errorf("Expected error 'database error', got %v", err)
+				}
+			},
+		},
+		{
+			name: "MarkSuccess_Success",
+			testFunc: func(t *testing.T, repo ILoginAttemptRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE login_attempts SET success = true WHERE id = \$1`).
+					WithArgs(uint64(7)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				if err := repo.MarkSuccess(ctx, 7); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Delete_Success",
+			testFunc: func(t *testing.T, repo ILoginAttemptRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`DELETE FROM login_attempts WHERE id = \$1`).
+					WithArgs(uint64(7)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				if err := repo.Delete(ctx, 7); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "DeleteBefore_Success",
+			testFunc: func(t *testing.T, repo ILoginAttemptRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`DELETE FROM login_attempts WHERE created_at < \$1`).
+					WithArgs(since).
+					WillReturnResult(sqlmock.NewResult(0, 5))
+
+				purged, err := repo.DeleteBefore(ctx, since)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if purged != 5 {
+					t.Errorf("Expected 5 purged attempts, got %d", purged)
+				}
+			},
+		},
+		{
+			name: "DeleteBefore_DatabaseError",
+			testFunc: func(t *testing.T, repo ILoginAttemptRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`DELETE FROM login_attempts WHERE created_at < \$1`).
+					WithArgs(since).
+					WillReturnError(fmt.Errorf("database error"))
+
+				if _, err := repo.DeleteBefore(ctx, since); err == nil || err.Error() != "database error" {
+					t.Errorf("Expected error 'database error', got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock: %v", err)
+			}
+			defer db.Close()
+
+			repo := NewLoginAttemptRepository(sqlx.NewDb(db, "sqlmock"))
+
+			tc.testFunc(t, repo, mock)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
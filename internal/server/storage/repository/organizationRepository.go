@@ -0,0 +1,198 @@
+// Package repository предоставляет доступ к организациям, их участникам и общим хранилищам.
+package repository
+
+import (
+	serverModels "beliaev-aa/GophKeeper/internal/server/models"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"slices"
+)
+
+// IOrganizationRepository определяет интерфейс для репозитория организаций.
+type IOrganizationRepository interface {
+	Create(ctx context.Context, org *models.Organization, ownerID uint64) error
+	ListForUser(ctx context.Context, userID uint64) ([]*models.Organization, error)
+	GetMembership(ctx context.Context, orgID, userID uint64) (*models.Organization, error)
+	ListMembers(ctx context.Context, orgID uint64) ([]*models.OrgMember, error)
+	AddMember(ctx context.Context, member *models.OrgMember) error
+	UpdateMemberRole(ctx context.Context, orgID, userID uint64, role models.OrgRole) error
+	RemoveMember(ctx context.Context, orgID, userID uint64) error
+	Delete(ctx context.Context, orgID uint64) error
+}
+
+// OrganizationRepository обеспечивает методы для работы с организациями в базе данных.
+type OrganizationRepository struct {
+	db *sqlx.DB
+}
+
+// membershipColumns - столбцы организации вместе с ролью и ключом хранилища участника из таблицы org_members m.
+const membershipColumns = "o.id, o.name, o.vault_id, m.role, m.vault_key, o.created_at"
+
+// NewOrganizationRepository создаёт новый экземпляр OrganizationRepository.
+// Принимает подключение к базе данных sqlx.DB и возвращает указатель на OrganizationRepository.
+func NewOrganizationRepository(db *sqlx.DB) IOrganizationRepository {
+	return &OrganizationRepository{
+		db: db,
+	}
+}
+
+// Create создаёт организацию org вместе со служебной учётной записью её хранилища и делает пользователя
+// ownerID её владельцем с ключом хранилища org.VaultKey. Идентификаторы организации и хранилища,
+// роль и время создания записываются в org.
+func (r *OrganizationRepository) Create(ctx context.Context, org *models.Organization, ownerID uint64) error {
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		return fmt.Errorf("failed to generate vault login: %w", err)
+	}
+
+	return runInTx(r.db, func(tx *sqlx.Tx) error {
+		query := "INSERT INTO users (login, password, is_vault) VALUES ($1, '', true) RETURNING id"
+		if err := tx.QueryRowxContext(ctx, query, serverModels.VaultLoginPrefix+hex.EncodeToString(suffix)).Scan(&org.VaultID); err != nil {
+			return err
+		}
+
+		query = "INSERT INTO organizations (name, vault_id) VALUES ($1, $2) RETURNING id, created_at"
+		if err := tx.QueryRowxContext(ctx, query, org.Name, org.VaultID).Scan(&org.ID, &org.CreatedAt); err != nil {
+			return err
+		}
+
+		org.Role = models.OrgOwner
+		query = "INSERT INTO org_members (org_id, user_id, role, vault_key) VALUES ($1, $2, $3, $4)"
+		_, err := tx.ExecContext(ctx, query, org.ID, ownerID, org.Role, org.VaultKey)
+		return err
+	})
+}
+
+// ListForUser возвращает организации, в которых состоит пользователь, упорядоченные по названию,
+// вместе с его ролью и ключом хранилища, зашифрованным его открытым ключом.
+func (r *OrganizationRepository) ListForUser(ctx context.Context, userID uint64) ([]*models.Organization, error) {
+	var orgs []*models.Organization
+
+	query := "SELECT " + membershipColumns + ` FROM org_members m JOIN organizations o ON o.id = m.org_id
+	WHERE m.user_id = $1 ORDER BY o.name, o.id`
+	if err := r.db.SelectContext(ctx, &orgs, query, userID); err != nil {
+		return nil, err
+	}
+
+	return orgs, nil
+}
+
+// GetMembership возвращает организацию orgID вместе с ролью и ключом хранилища пользователя userID.
+// Возвращает ErrNotFound, если организация не найдена или пользователь в ней не состоит.
+func (r *OrganizationRepository) GetMembership(ctx context.Context, orgID, userID uint64) (*models.Organization, error) {
+	var org models.Organization
+
+	query := "SELECT " + membershipColumns + ` FROM org_members m JOIN organizations o ON o.id = m.org_id
+	WHERE m.org_id = $1 AND m.user_id = $2`
+	err := r.db.QueryRowxContext(ctx, query, orgID, userID).StructScan(&org)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("organization %w (id=%d)", gophKeeperErrors.ErrNotFound, orgID)
+		}
+		return nil, err
+	}
+
+	return &org, nil
+}
+
+// ListMembers возвращает участников организации, упорядоченных по логину. Ключи хранилища участников
+// не возвращаются.
+func (r *OrganizationRepository) ListMembers(ctx context.Context, orgID uint64) ([]*models.OrgMember, error) {
+	var members []*models.OrgMember
+
+	query := `SELECT m.org_id, m.user_id, u.login, m.role, m.created_at FROM org_members m JOIN users u ON u.id = m.user_id
+	WHERE m.org_id = $1 ORDER BY u.login`
+	if err := r.db.SelectContext(ctx, &members, query, orgID); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// AddMember добавляет пользователя member.UserID в организацию member.OrgID. Повторное добавление
+// заменяет роль и ключ хранилища участника: так администратор заново выдаёт ключ участнику,
+// сменившему пару ключей. Время добавления записывается в member.
+// Возвращает ErrLastOwner, если повторное добавление понижает последнего владельца.
+func (r *OrganizationRepository) AddMember(ctx context.Context, member *models.OrgMember) error {
+	return runInTx(r.db, func(tx *sqlx.Tx) error {
+		if member.Role != models.OrgOwner {
+			if err := requireAnotherOwner(ctx, tx, member.OrgID, member.UserID); err != nil {
+				return err
+			}
+		}
+
+		query := `INSERT INTO org_members (org_id, user_id, role, vault_key) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (org_id, user_id) DO UPDATE SET role = EXCLUDED.role, vault_key = EXCLUDED.vault_key
+		RETURNING created_at`
+		return tx.QueryRowxContext(ctx, query, member.OrgID, member.UserID, member.Role, member.VaultKey).Scan(&member.CreatedAt)
+	})
+}
+
+// UpdateMemberRole изменяет роль участника организации.
+// Возвращает ErrNotFound, если пользователь не состоит в организации, и ErrLastOwner при понижении
+// последнего владельца.
+func (r *OrganizationRepository) UpdateMemberRole(ctx context.Context, orgID, userID uint64, role models.OrgRole) error {
+	return runInTx(r.db, func(tx *sqlx.Tx) error {
+		if role != models.OrgOwner {
+			if err := requireAnotherOwner(ctx, tx, orgID, userID); err != nil {
+				return err
+			}
+		}
+
+		result, err := tx.ExecContext(ctx, "UPDATE org_members SET role = $1 WHERE org_id = $2 AND user_id = $3", role, orgID, userID)
+		if err != nil {
+			return err
+		}
+		return expectAffected(result, "organization member", userID)
+	})
+}
+
+// RemoveMember исключает пользователя из организации.
+// Возвращает ErrNotFound, если пользователь не состоит в организации, и ErrLastOwner при исключении
+// последнего владельца.
+func (r *OrganizationRepository) RemoveMember(ctx context.Context, orgID, userID uint64) error {
+	return runInTx(r.db, func(tx *sqlx.Tx) error {
+		if err := requireAnotherOwner(ctx, tx, orgID, userID); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, "DELETE FROM org_members WHERE org_id = $1 AND user_id = $2", orgID, userID)
+		if err != nil {
+			return err
+		}
+		return expectAffected(result, "organization member", userID)
+	})
+}
+
+// requireAnotherOwner блокирует до конца транзакции tx строки владельцев организации orgID и возвращает
+// ErrLastOwner, если пользователь userID - её единственный владелец. Блокировка не даёт двум владельцам
+// одновременно понизить или исключить друг друга: вторая транзакция дождётся первой и увидит её результат.
+func requireAnotherOwner(ctx context.Context, tx *sqlx.Tx, orgID, userID uint64) error {
+	var owners []uint64
+	err := tx.SelectContext(ctx, &owners, "SELECT user_id FROM org_members WHERE org_id = $1 AND role = $2 FOR UPDATE", orgID, models.OrgOwner)
+	if err != nil {
+		return err
+	}
+	if len(owners) < 2 && slices.Contains(owners, userID) {
+		return gophKeeperErrors.ErrLastOwner
+	}
+	return nil
+}
+
+// Delete удаляет организацию вместе со служебной учётной записью её хранилища. Секреты хранилища
+// и участники организации удаляются каскадно.
+// Возвращает ErrNotFound, если организация не найдена.
+func (r *OrganizationRepository) Delete(ctx context.Context, orgID uint64) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM users WHERE id = (SELECT vault_id FROM organizations WHERE id = $1)", orgID)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result, "organization", orgID)
+}
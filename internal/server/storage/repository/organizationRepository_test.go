@@ -0,0 +1,248 @@
+package repository
+
+import (
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"testing"
+	"time"
+)
+
+func TestOrganizationRepository(t *testing.T) {
+	ctx := context.Background()
+	membershipRows := []string{"id", "name", "vault_id", "role", "vault_key", "created_at"}
+
+	tests := []struct {
+		name     string
+		testFunc func(t *testing.T, repo IOrganizationRepository, mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "Create_Success",
+			testFunc: func(t *testing.T, repo IOrganizationRepository, mock sqlmock.Sqlmock) {
+				createdAt := time.Now()
+
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO users \(login, password, is_vault\) VALUES \(\$1, '', true\) RETURNING id`).
+					WithArgs(sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(100))
+				mock.ExpectQuery(`INSERT INTO organizations \(name, vault_id\) VALUES \(\$1, \$2\) RETURNING id, created_at`).
+					WithArgs("Team", 100).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(7, createdAt))
+				mock.ExpectExec(`INSERT INTO org_members \(org_id, user_id, role, vault_key\) VALUES \(\$1, \$2, \$3, \$4\)`).
+					WithArgs(7, 1, models.OrgOwner, []byte("vault_key")).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				org := &models.Organization{Name: "Team", VaultKey: []byte("vault_key")}
+				if err := repo.Create(ctx, org, 1); err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if org.ID != 7 || org.VaultID != 100 || org.Role != models.OrgOwner || !org.CreatedAt.Equal(createdAt) {
+					t.Errorf("Expected organization 7 with vault 100 owned by the user, got %+v", org)
+				}
+			},
+		},
+		{
+			name: "Create_Fail_Rollback",
+			testFunc: func(t *testing.T, repo IOrganizationRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO users \(login, password, is_vault\) VALUES \(\$1, '', true\) RETURNING id`).
+					WithArgs(sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(100))
+				mock.ExpectQuery(`INSERT INTO organizations \(name, vault_id\) VALUES \(\$1, \$2\) RETURNING id, created_at`).
+					WithArgs("Team", 100).
+					WillReturnError(errors.New("insert failed"))
+				mock.ExpectRollback()
+
+				if err := repo.Create(ctx, &models.Organization{Name: "Team"}, 1); err == nil {
+					t.Errorf("Expected error, got nil")
+				}
+			},
+		},
+		{
+			name: "ListForUser_Success",
+			testFunc: func(t *testing.T, repo IOrganizationRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT o.id, o.name, o.vault_id, m.role, m.vault_key, o.created_at FROM org_members m JOIN organizations o ON o.id = m.org_id\s+WHERE m.user_id = \$1 ORDER BY o.name, o.id`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(membershipRows).
+						AddRow(7, "Team", 100, "editor", []byte("vault_key"), time.Now()))
+
+				orgs, err := repo.ListForUser(ctx, 1)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if len(orgs) != 1 || orgs[0].ID != 7 || orgs[0].Role != models.OrgEditor || string(orgs[0].VaultKey) != "vault_key" {
+					t.Errorf("Expected editor membership in organization 7, got %+v", orgs)
+				}
+			},
+		},
+		{
+			name: "GetMembership_Success",
+			testFunc: func(t *testing.T, repo IOrganizationRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT (.+) FROM org_members m JOIN organizations o ON o.id = m.org_id\s+WHERE m.org_id = \$1 AND m.user_id = \$2`).
+					WithArgs(7, 1).
+					WillReturnRows(sqlmock.NewRows(membershipRows).
+						AddRow(7, "Team", 100, "viewer", []byte("vault_key"), time.Now()))
+
+				org, err := repo.GetMembership(ctx, 7, 1)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if org.VaultID != 100 || org.Role != models.OrgViewer {
+					t.Errorf("Expected viewer of vault 100, got %+v", org)
+				}
+			},
+		},
+		{
+			name: "GetMembership_Fail_NotMember",
+			testFunc: func(t *testing.T, repo IOrganizationRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT (.+) FROM org_members m JOIN organizations o ON o.id = m.org_id\s+WHERE m.org_id = \$1 AND m.user_id = \$2`).
+					WithArgs(7, 2).
+					WillReturnError(sql.ErrNoRows)
+
+				_, err := repo.GetMembership(ctx, 7, 2)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+		{
+			name: "ListMembers_Success",
+			testFunc: func(t *testing.T, repo IOrganizationRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT m.org_id, m.user_id, u.login, m.role, m.created_at FROM org_members m JOIN users u ON u.id = m.user_id\s+WHERE m.org_id = \$1 ORDER BY u.login`).
+					WithArgs(7).
+					WillReturnRows(sqlmock.NewRows([]string{"org_id", "user_id", "login", "role", "created_at"}).
+						AddRow(7, 1, "alice", "owner", time.Now()).
+						AddRow(7, 2, "bob", "editor", time.Now()))
+
+				members, err := repo.ListMembers(ctx, 7)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if len(members) != 2 || members[1].Login != "bob" || members[1].Role != models.OrgEditor {
+					t.Errorf("Expected alice and bob, got %+v", members)
+				}
+			},
+		},
+		{
+			name: "AddMember_Success",
+			testFunc: func(t *testing.T, repo IOrganizationRepository, mock sqlmock.Sqlmock) {
+				createdAt := time.Now()
+
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT user_id FROM org_members WHERE org_id = \$1 AND role = \$2 FOR UPDATE`).
+					WithArgs(7, models.OrgOwner).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+				mock.ExpectQuery(`INSERT INTO org_members \(org_id, user_id, role, vault_key\) VALUES \(\$1, \$2, \$3, \$4\)\s+ON CONFLICT \(org_id, user_id\) DO UPDATE SET role = EXCLUDED.role, vault_key = EXCLUDED.vault_key\s+RETURNING created_at`).
+					WithArgs(7, 2, models.OrgEditor, []byte("wrapped")).
+					WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+				mock.ExpectCommit()
+
+				member := &models.OrgMember{OrgID: 7, UserID: 2, Role: models.OrgEditor, VaultKey: []byte("wrapped")}
+				if err := repo.AddMember(ctx, member); err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if !member.CreatedAt.Equal(createdAt) {
+					t.Errorf("Expected creation time %v, got %v", createdAt, member.CreatedAt)
+				}
+			},
+		},
+		{
+			name: "UpdateMemberRole_Fail_NotFound",
+			testFunc: func(t *testing.T, repo IOrganizationRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT user_id FROM org_members WHERE org_id = \$1 AND role = \$2 FOR UPDATE`).
+					WithArgs(7, models.OrgOwner).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+				mock.ExpectExec(`UPDATE org_members SET role = \$1 WHERE org_id = \$2 AND user_id = \$3`).
+					WithArgs(models.OrgAdmin, 7, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+
+				err := repo.UpdateMemberRole(ctx, 7, 2, models.OrgAdmin)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+		{
+			name: "RemoveMember_Success",
+			testFunc: func(t *testing.T, repo IOrganizationRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT user_id FROM org_members WHERE org_id = \$1 AND role = \$2 FOR UPDATE`).
+					WithArgs(7, models.OrgOwner).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+				mock.ExpectExec(`DELETE FROM org_members WHERE org_id = \$1 AND user_id = \$2`).
+					WithArgs(7, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				if err := repo.RemoveMember(ctx, 7, 2); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "RemoveMember_Fail_LastOwner",
+			testFunc: func(t *testing.T, repo IOrganizationRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT user_id FROM org_members WHERE org_id = \$1 AND role = \$2 FOR UPDATE`).
+					WithArgs(7, models.OrgOwner).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2))
+				mock.ExpectRollback()
+
+				err := repo.RemoveMember(ctx, 7, 2)
+				if !errors.Is(err, gophKeeperErrors.ErrLastOwner) {
+					t.Errorf("Expected error 'ErrLastOwner', got %v", err)
+				}
+			},
+		},
+		{
+			name: "Delete_Success",
+			testFunc: func(t *testing.T, repo IOrganizationRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`DELETE FROM users WHERE id = \(SELECT vault_id FROM organizations WHERE id = \$1\)`).
+					WithArgs(7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				if err := repo.Delete(ctx, 7); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Delete_Fail_NotFound",
+			testFunc: func(t *testing.T, repo IOrganizationRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`DELETE FROM users WHERE id = \(SELECT vault_id FROM organizations WHERE id = \$1\)`).
+					WithArgs(7).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				err := repo.Delete(ctx, 7)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock: %v", err)
+			}
+			defer db.Close()
+
+			repo := NewOrganizationRepository(sqlx.NewDb(db, "sqlmock"))
+
+			tc.testFunc(t, repo, mock)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
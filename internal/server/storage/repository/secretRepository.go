@@ -2,6 +2,7 @@
 package repository
 
 import (
+	serverModels "beliaev-aa/GophKeeper/internal/server/models"
 	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
 	"beliaev-aa/GophKeeper/pkg/models"
 	"context"
@@ -9,18 +10,46 @@ import (
 	"errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // ISecretRepository определяет интерфейс для репозитория секретов,
 // предоставляющего методы для работы с секретами в базе данных.
 type ISecretRepository interface {
 	GetSecret(ctx context.Context, secretID uint64, userID uint64) (*models.Secret, error)
-	GetUserSecrets(ctx context.Context, userID uint64) (models.Secrets, error)
+	GetUserSecrets(ctx context.Context, userID uint64, filter models.SecretFilter) (models.Secrets, error)
+	Sync(ctx context.Context, userID uint64, sinceRevision uint64) (*models.SecretsDelta, error)
+	List(ctx context.Context, userID uint64, query *models.SecretListQuery, after *serverModels.SecretCursor, limit int) (models.Secrets, error)
+	ReserveID(ctx context.Context, userID uint64) (uint64, error)
 	Create(ctx context.Context, secret *models.Secret) (uint64, error)
-	Update(ctx context.Context, secret *models.Secret) error
+	Update(ctx context.Context, secret *models.Secret, versionsLimit int) error
 	Delete(ctx context.Context, secretID uint64, userID uint64) error
+	ListVersions(ctx context.Context, secretID uint64, userID uint64) ([]*models.SecretVersion, error)
+	RestoreVersion(ctx context.Context, secretID, versionID, userID uint64, versionsLimit int) (*models.Secret, error)
+	ListTrash(ctx context.Context, userID uint64) (models.Secrets, error)
+	Restore(ctx context.Context, secretID uint64, userID uint64) error
+	Purge(ctx context.Context, secretID uint64, userID uint64) ([]string, error)
+	PurgeExpired(ctx context.Context, before time.Time) (int64, error)
+	EncryptLabels(ctx context.Context, userID uint64, secrets models.Secrets) error
 }
 
+// labelColumns - папка и метки секрета; секрет без папки читается с нулевым идентификатором папки,
+// а метки - строкой идентификаторов через запятую.
+const labelColumns = `COALESCE(folder_id, 0) AS folder_id,
+	(SELECT string_agg(tag_id::text, ',' ORDER BY tag_id) FROM secret_tags WHERE secret_tags.secret_id = secrets.id) AS tag_ids`
+
+// secretColumns - столбцы секрета, читаемые в модель Secret; отсутствующая ссылка на объект читается как пустая строка.
+const secretColumns = `id, user_id, title, metadata, labels_encrypted, secret_type, payload, data_key, created_at, updated_at, revision, deleted_at, COALESCE(blob_id, '') AS blob_id, ` + labelColumns
+
+// headerColumns - столбцы заголовка секрета: все столбцы секрета, кроме зашифрованных данных. Ключ данных
+// входит в заголовок, так как им зашифрованы заголовок и метаданные секрета.
+const headerColumns = `id, user_id, title, metadata, labels_encrypted, secret_type, data_key, created_at, updated_at, revision, deleted_at, COALESCE(blob_id, '') AS blob_id, ` + labelColumns
+
+// versionColumns - столбцы версии секрета, читаемые в модель SecretVersion.
+const versionColumns = `id AS version_id, secret_id AS id, user_id, title, metadata, labels_encrypted, secret_type, payload, data_key, COALESCE(blob_id, '') AS blob_id, updated_at, archived_at`
+
 // SecretRepository обеспечивает методы для работы с данными секретов в базе данных.
 type SecretRepository struct {
 	db *sqlx.DB
@@ -35,27 +64,41 @@ func NewSecretRepository(db *sqlx.DB) ISecretRepository {
 }
 
 // GetSecret извлекает секрет по его ID и ID пользователя.
-// Возвращает указатель на модель Secret или ошибку, если секрет не найден или возникла другая ошибка.
+// Секреты из корзины не возвращаются.
+// Возвращает указатель на модель Secret или ErrNotFound, если секрет не найден или принадлежит другому пользователю.
 func (r *SecretRepository) GetSecret(ctx context.Context, secretID uint64, userID uint64) (*models.Secret, error) {
 	var secret models.Secret
 
-	query := `SELECT * FROM secrets WHERE id = $1 AND user_id = $2`
+	err := runAsUser(ctx, r.db, userID, func(tx *sqlx.Tx) error {
+		query := `SELECT ` + secretColumns + ` FROM secrets WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`
 
-	err := r.db.QueryRowxContext(ctx, query, secretID, userID).StructScan(&secret)
-	if errors.Is(err, sql.ErrNoRows) {
-		return nil, gophKeeperErrors.ErrNotFound
+		err := tx.QueryRowxContext(ctx, query, secretID, userID).StructScan(&secret)
+		if errors.Is(err, sql.ErrNoRows) {
+			return gophKeeperErrors.ErrNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &secret, err
+	return &secret, nil
 }
 
-// GetUserSecrets извлекает все секреты пользователя по его ID.
+// GetUserSecrets извлекает все секреты пользователя по его ID, кроме находящихся в корзине,
+// которые лежат в папке filter.FolderID или вложенных в неё папках и отмечены меткой filter.TagID.
 // Возвращает срез секретов или ошибку.
-func (r *SecretRepository) GetUserSecrets(ctx context.Context, userID uint64) (models.Secrets, error) {
+func (r *SecretRepository) GetUserSecrets(ctx context.Context, userID uint64, filter models.SecretFilter) (models.Secrets, error) {
 	var secrets models.Secrets
 
-	query := "SELECT * FROM secrets WHERE user_id = $1 ORDER BY updated_at DESC"
-	err := r.db.SelectContext(ctx, &secrets, query, userID)
+	conditions := []string{"user_id = $1", "deleted_at IS NULL"}
+	args := []any{userID}
+	conditions = append(conditions, filterConditions(filter, &args)...)
+
+	err := runAsUser(ctx, r.db, userID, func(tx *sqlx.Tx) error {
+		query := "SELECT " + secretColumns + " FROM secrets WHERE " + strings.Join(conditions, " AND ") + " ORDER BY updated_at DESC"
+		return tx.SelectContext(ctx, &secrets, query, args...)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -63,18 +106,166 @@ func (r *SecretRepository) GetUserSecrets(ctx context.Context, userID uint64) (m
 	return secrets, nil
 }
 
+// Sync возвращает изменения секретов пользователя с ревизией хранилища больше sinceRevision:
+// изменённые секреты вне корзины и идентификаторы секретов, перемещённых в корзину или удалённых окончательно.
+// Ревизия хранилища читается до изменений, и изменения новее неё не возвращаются: они попадут
+// в следующую синхронизацию. Если sinceRevision равна нулю или больше текущей ревизии,
+// возвращаются все секреты пользователя вне корзины с признаком Full.
+func (r *SecretRepository) Sync(ctx context.Context, userID uint64, sinceRevision uint64) (*models.SecretsDelta, error) {
+	delta := &models.SecretsDelta{}
+
+	err := runAsUser(ctx, r.db, userID, func(tx *sqlx.Tx) error {
+		err := tx.QueryRowxContext(ctx, "SELECT sync_revision FROM users WHERE id = $1", userID).Scan(&delta.Revision)
+		if err != nil {
+			return fmt.Errorf("failed to read sync revision: %w", err)
+		}
+
+		delta.Full = sinceRevision == 0 || sinceRevision > delta.Revision
+		if delta.Full {
+			query := "SELECT " + secretColumns + ` FROM secrets
+			WHERE user_id = $1 AND deleted_at IS NULL AND change_revision <= $2 ORDER BY updated_at DESC`
+			return tx.SelectContext(ctx, &delta.Secrets, query, userID, delta.Revision)
+		}
+
+		query := "SELECT " + secretColumns + ` FROM secrets
+		WHERE user_id = $1 AND deleted_at IS NULL AND change_revision > $2 AND change_revision <= $3 ORDER BY updated_at DESC`
+		if err = tx.SelectContext(ctx, &delta.Secrets, query, userID, sinceRevision, delta.Revision); err != nil {
+			return err
+		}
+
+		query = `SELECT id FROM secrets WHERE user_id = $1 AND deleted_at IS NOT NULL AND change_revision > $2 AND change_revision <= $3
+		UNION SELECT secret_id FROM secret_tombstones WHERE user_id = $1 AND change_revision > $2 AND change_revision <= $3`
+		return tx.SelectContext(ctx, &delta.DeletedIDs, query, userID, sinceRevision, delta.Revision)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return delta, nil
+}
+
+// List возвращает не больше limit заголовков секретов пользователя вне корзины, подходящих под фильтр query,
+// в порядке времени изменения query.Order. Если задан курсор after, список начинается с секрета, следующего за ним.
+// Секреты с одинаковым временем изменения упорядочиваются по ID, поэтому курсор однозначно задаёт позицию.
+// Начало заголовка и порядок по заголовку не поддерживаются: заголовки зашифрованы и проверяются сервисом.
+func (r *SecretRepository) List(ctx context.Context, userID uint64, query *models.SecretListQuery, after *serverModels.SecretCursor, limit int) (models.Secrets, error) {
+	var secrets models.Secrets
+
+	conditions := []string{"user_id = $1", "deleted_at IS NULL"}
+	args := []any{userID}
+	conditions = append(conditions, filterConditions(query.SecretFilter, &args)...)
+	arg := func(value any) string {
+		return addArg(&args, value)
+	}
+
+	if query.SecretType != "" {
+		conditions = append(conditions, "secret_type = "+arg(query.SecretType))
+	}
+	if len(query.SearchTokens) > 0 {
+		conditions = append(conditions, "search_tokens @> "+tokensArray(arg(strings.Join(query.SearchTokens, " "))))
+	}
+	if !query.UpdatedSince.IsZero() {
+		conditions = append(conditions, "updated_at >= "+arg(query.UpdatedSince))
+	}
+
+	direction, compare := "DESC", "<"
+	if query.Order == models.SecretOrderUpdatedAsc {
+		direction, compare = "ASC", ">"
+	}
+
+	if after != nil {
+		conditions = append(conditions, fmt.Sprintf("(updated_at, id) %s (%s, %s)", compare, arg(after.UpdatedAt), arg(after.ID)))
+	}
+
+	statement := fmt.Sprintf("SELECT %s FROM secrets WHERE %s ORDER BY updated_at %s, id %s LIMIT %s",
+		headerColumns, strings.Join(conditions, " AND "), direction, direction, arg(limit))
+
+	err := runAsUser(ctx, r.db, userID, func(tx *sqlx.Tx) error {
+		return tx.SelectContext(ctx, &secrets, statement, args...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return secrets, nil
+}
+
+// filterConditions возвращает условия отбора секретов по папке и метке, добавляя их параметры в args.
+// В папку входят и секреты вложенных в неё папок.
+func filterConditions(filter models.SecretFilter, args *[]any) []string {
+	var conditions []string
+	if filter.FolderID != 0 {
+		conditions = append(conditions, `folder_id IN (WITH RECURSIVE subtree AS (
+			SELECT id FROM folders WHERE id = `+addArg(args, filter.FolderID)+`
+			UNION ALL SELECT f.id FROM folders f JOIN subtree s ON f.parent_id = s.id
+		) SELECT id FROM subtree)`)
+	}
+	if filter.TagID != 0 {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM secret_tags WHERE secret_tags.secret_id = secrets.id AND tag_id = "+addArg(args, filter.TagID)+")")
+	}
+	return conditions
+}
+
+// addArg добавляет параметр запроса и возвращает его позиционное обозначение.
+func addArg(args *[]any, value any) string {
+	*args = append(*args, value)
+	return "$" + strconv.Itoa(len(*args))
+}
+
+// ReserveID выдаёт пользователю идентификатор для нового секрета из последовательности идентификаторов секретов.
+// Клиент шифрует данные секрета с привязкой к этому идентификатору и затем создаёт секрет с ним через Create.
+func (r *SecretRepository) ReserveID(ctx context.Context, userID uint64) (uint64, error) {
+	var id uint64
+
+	err := runAsUser(ctx, r.db, userID, func(tx *sqlx.Tx) error {
+		query := `INSERT INTO secret_id_reservations (id, user_id) VALUES (nextval(pg_get_serial_sequence('secrets', 'id')), $1) RETURNING id`
+		return tx.QueryRowxContext(ctx, query, userID).Scan(&id)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
 // Create добавляет новый секрет в базу данных.
-// Принимает контекст и указатель на модель Secret.
-// Возвращает ID нового секрета или ошибку.
+// Принимает контекст и указатель на модель Secret. Секрет с ненулевым ID создаётся с этим идентификатором,
+// если он зарезервирован пользователем через ReserveID; резерв при этом погашается.
+// Возвращает ID нового секрета, ErrNotFound, если идентификатор не зарезервирован пользователем,
+// папка или метки секрета не принадлежат пользователю, или ошибку.
 func (r *SecretRepository) Create(ctx context.Context, secret *models.Secret) (uint64, error) {
 	var newSecretID uint64
 
-	query := `INSERT INTO secrets (user_id, title, metadata, secret_type, payload)
-		VALUES ($1, $2, $3, $4, $5)
+	err := runAsUser(ctx, r.db, uint64(secret.UserID), func(tx *sqlx.Tx) error {
+		if secret.ID > 0 {
+			result, err := tx.ExecContext(ctx, "DELETE FROM secret_id_reservations WHERE id = $1 AND user_id = $2", secret.ID, secret.UserID)
+			if err != nil {
+				return err
+			}
+			if err = requireAffected(result); err != nil {
+				return fmt.Errorf("secret ID %d reservation: %w", secret.ID, err)
+			}
+		}
+
+		if err := checkFolder(ctx, tx, uint64(secret.UserID), secret.FolderID); err != nil {
+			return err
+		}
+
+		query := `INSERT INTO secrets (id, user_id, title, metadata, secret_type, payload, blob_id, folder_id, labels_encrypted, search_tokens, data_key)
+		VALUES (COALESCE(NULLIF($1, 0), nextval(pg_get_serial_sequence('secrets', 'id'))), $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, 0), $9, ` + tokensArray("$10") + `, $11)
 		RETURNING id`
 
-	result := r.db.QueryRowxContext(ctx, query, secret.UserID, secret.Title, secret.Metadata, secret.SecretType, secret.Payload)
-	err := result.Scan(&newSecretID)
+		result := tx.QueryRowxContext(ctx, query, secret.ID, secret.UserID, secret.Title, secret.Metadata, secret.SecretType, secret.Payload, secret.BlobID, secret.FolderID,
+			secret.LabelsEncrypted, strings.Join(secret.SearchTokens, " "), secret.DataKey)
+		if err := result.Scan(&newSecretID); err != nil {
+			return err
+		}
+
+		if len(secret.TagIDs) == 0 {
+			return nil
+		}
+		return replaceSecretTags(ctx, tx, uint64(secret.UserID), newSecretID, secret.TagIDs)
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -83,40 +274,331 @@ func (r *SecretRepository) Create(ctx context.Context, secret *models.Secret) (u
 }
 
 // Update обновляет данные секрета в базе данных.
-// Принимает контекст и указатель на модель Secret.
-// Возвращает ошибку, если обновление не удалось.
-func (r *SecretRepository) Update(ctx context.Context, secret *models.Secret) error {
-	return runInTx(r.db, func(tx *sqlx.Tx) error {
-		err := tx.QueryRowxContext(ctx, "SELECT 1 FROM secrets WHERE id = $1 FOR UPDATE", secret.ID).Scan(new(int))
+// Принимает контекст, указатель на модель Secret и максимальное количество версий, хранимых для пользователя;
+// секрет ищется по ID и ID владельца. Обновление применяется, только если ревизия секрета совпадает
+// с текущей; при успехе ревизия увеличивается и записывается в secret.
+// Прежнее состояние секрета сохраняется в историю версий в той же транзакции.
+// Папка и метки секрета заменяются переданными; история версий их не хранит.
+// Возвращает ErrNotFound, если секрет, его папка или метки не найдены или принадлежат другому пользователю,
+// и RevisionConflictError, если секрет был изменён после загрузки.
+func (r *SecretRepository) Update(ctx context.Context, secret *models.Secret, versionsLimit int) error {
+	return runAsUser(ctx, r.db, uint64(secret.UserID), func(tx *sqlx.Tx) error {
+		var revision uint64
+		err := tx.QueryRowxContext(ctx, "SELECT revision FROM secrets WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL FOR UPDATE", secret.ID, secret.UserID).Scan(&revision)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
-				return fmt.Errorf("secret with ID %d not found: %w", secret.ID, err)
+				return fmt.Errorf("secret with ID %d: %w", secret.ID, gophKeeperErrors.ErrNotFound)
 			}
 			return err
 		}
+		if revision != secret.Revision {
+			return &gophKeeperErrors.RevisionConflictError{Current: revision}
+		}
+
+		if err = checkFolder(ctx, tx, uint64(secret.UserID), secret.FolderID); err != nil {
+			return err
+		}
 
-		query := `UPDATE secrets SET updated_at = $1, title = $2, metadata = $3, secret_type = $4, payload = $5 WHERE id = $6;`
-		_, err = tx.ExecContext(ctx, query,
+		if err = archiveSecret(ctx, tx, secret.ID, uint64(secret.UserID)); err != nil {
+			return err
+		}
+
+		query := `UPDATE secrets SET updated_at = $1, title = $2, metadata = $3, secret_type = $4, payload = $5, blob_id = NULLIF($6, ''),
+		folder_id = NULLIF($7, 0), labels_encrypted = $8, search_tokens = ` + tokensArray("$9") + `, data_key = $10,
+		revision = revision + 1 WHERE id = $11 AND user_id = $12 RETURNING revision`
+		err = tx.QueryRowxContext(ctx, query,
 			secret.UpdatedAt,
 			secret.Title,
 			secret.Metadata,
 			secret.SecretType,
 			secret.Payload,
+			secret.BlobID,
+			secret.FolderID,
+			secret.LabelsEncrypted,
+			strings.Join(secret.SearchTokens, " "),
+			secret.DataKey,
 			secret.ID,
-		)
+			secret.UserID,
+		).Scan(&secret.Revision)
+		if err != nil {
+			return err
+		}
 
-		return err
+		if err = replaceSecretTags(ctx, tx, uint64(secret.UserID), secret.ID, secret.TagIDs); err != nil {
+			return err
+		}
+
+		return pruneVersions(ctx, tx, uint64(secret.UserID), versionsLimit)
 	})
 }
 
-// Delete удаляет секрет из базы данных по его ID и ID пользователя.
+// Delete перемещает секрет в корзину по его ID и ID пользователя.
 // Принимает контекст, ID секрета и ID пользователя.
-// Возвращает ошибку, если удаление не удалось.
+// Возвращает ErrNotFound, если секрет не найден, уже находится в корзине или принадлежит другому пользователю.
 func (r *SecretRepository) Delete(ctx context.Context, secretID uint64, userID uint64) error {
-	query := `DELETE FROM secrets WHERE id = $1 AND user_id = $2`
-	_, err := r.db.ExecContext(ctx, query, secretID, userID)
+	return runAsUser(ctx, r.db, userID, func(tx *sqlx.Tx) error {
+		query := `UPDATE secrets SET deleted_at = now() WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`
+		result, err := tx.ExecContext(ctx, query, secretID, userID)
+		if err != nil {
+			return err
+		}
 
-	return err
+		return requireAffected(result)
+	})
+}
+
+// ListVersions возвращает сохранённые версии секрета от новых к старым.
+// Возвращает ErrNotFound, если секрет не найден или принадлежит другому пользователю.
+func (r *SecretRepository) ListVersions(ctx context.Context, secretID uint64, userID uint64) ([]*models.SecretVersion, error) {
+	var versions []*models.SecretVersion
+
+	err := runAsUser(ctx, r.db, userID, func(tx *sqlx.Tx) error {
+		err := tx.QueryRowxContext(ctx, "SELECT 1 FROM secrets WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL", secretID, userID).Scan(new(int))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return gophKeeperErrors.ErrNotFound
+			}
+			return err
+		}
+
+		query := `SELECT ` + versionColumns + ` FROM secret_versions WHERE secret_id = $1 AND user_id = $2 ORDER BY id DESC`
+		return tx.SelectContext(ctx, &versions, query, secretID, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// RestoreVersion заменяет текущее состояние секрета сохранённой версией.
+// Текущее состояние при этом сохраняется в историю, поэтому восстановление можно отменить.
+// Возвращает восстановленный секрет или ErrNotFound, если секрет или версия не найдены у пользователя.
+func (r *SecretRepository) RestoreVersion(ctx context.Context, secretID, versionID, userID uint64, versionsLimit int) (*models.Secret, error) {
+	var secret models.Secret
+
+	err := runAsUser(ctx, r.db, userID, func(tx *sqlx.Tx) error {
+		err := tx.QueryRowxContext(ctx, "SELECT 1 FROM secrets WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL FOR UPDATE", secretID, userID).Scan(new(int))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("secret with ID %d: %w", secretID, gophKeeperErrors.ErrNotFound)
+			}
+			return err
+		}
+
+		var version models.SecretVersion
+		query := `SELECT ` + versionColumns + ` FROM secret_versions WHERE id = $1 AND secret_id = $2 AND user_id = $3`
+		err = tx.QueryRowxContext(ctx, query, versionID, secretID, userID).StructScan(&version)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("version with ID %d: %w", versionID, gophKeeperErrors.ErrNotFound)
+			}
+			return err
+		}
+
+		if err = archiveSecret(ctx, tx, secretID, userID); err != nil {
+			return err
+		}
+
+		query = `UPDATE secrets SET updated_at = now(), title = $1, metadata = $2, secret_type = $3, payload = $4, blob_id = NULLIF($5, ''),
+		labels_encrypted = $6, search_tokens = (SELECT search_tokens FROM secret_versions WHERE id = $7), data_key = $8,
+		revision = revision + 1 WHERE id = $9 AND user_id = $10 RETURNING ` + secretColumns
+		err = tx.QueryRowxContext(ctx, query,
+			version.Title,
+			version.Metadata,
+			version.SecretType,
+			version.Payload,
+			version.BlobID,
+			version.LabelsEncrypted,
+			versionID,
+			version.DataKey,
+			secretID,
+			userID,
+		).StructScan(&secret)
+		if err != nil {
+			return err
+		}
+
+		return pruneVersions(ctx, tx, userID, versionsLimit)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &secret, nil
+}
+
+// ListTrash возвращает секреты пользователя, находящиеся в корзине, начиная с удалённых последними.
+func (r *SecretRepository) ListTrash(ctx context.Context, userID uint64) (models.Secrets, error) {
+	var secrets models.Secrets
+
+	err := runAsUser(ctx, r.db, userID, func(tx *sqlx.Tx) error {
+		query := "SELECT " + secretColumns + " FROM secrets WHERE user_id = $1 AND deleted_at IS NOT NULL ORDER BY deleted_at DESC"
+		return tx.SelectContext(ctx, &secrets, query, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return secrets, nil
+}
+
+// Restore возвращает секрет из корзины.
+// Возвращает ErrNotFound, если секрета нет в корзине пользователя.
+func (r *SecretRepository) Restore(ctx context.Context, secretID uint64, userID uint64) error {
+	return runAsUser(ctx, r.db, userID, func(tx *sqlx.Tx) error {
+		query := `UPDATE secrets SET deleted_at = NULL, updated_at = now(), revision = revision + 1 WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL`
+		result, err := tx.ExecContext(ctx, query, secretID, userID)
+		if err != nil {
+			return err
+		}
+
+		return requireAffected(result)
+	})
+}
+
+// Purge окончательно удаляет секрет из корзины вместе с его историей версий.
+// Возвращает идентификаторы объектов, на которые ссылались секрет и его версии,
+// или ErrNotFound, если секрета нет в корзине пользователя.
+func (r *SecretRepository) Purge(ctx context.Context, secretID uint64, userID uint64) ([]string, error) {
+	var blobIDs []string
+
+	err := runAsUser(ctx, r.db, userID, func(tx *sqlx.Tx) error {
+		query := `SELECT blob_id FROM secrets WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL AND blob_id IS NOT NULL
+		UNION SELECT blob_id FROM secret_versions WHERE secret_id = $1 AND user_id = $2 AND blob_id IS NOT NULL`
+		if err := tx.SelectContext(ctx, &blobIDs, query, secretID, userID); err != nil {
+			return fmt.Errorf("failed to collect secret blobs: %w", err)
+		}
+
+		query = `DELETE FROM secrets WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL`
+		result, err := tx.ExecContext(ctx, query, secretID, userID)
+		if err != nil {
+			return err
+		}
+
+		return requireAffected(result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return blobIDs, nil
+}
+
+// PurgeExpired окончательно удаляет секреты всех пользователей, перемещённые в корзину раньше before.
+// Выполняется вне контекста пользователя: параметр app.purge_trash открывает задаче очистки
+// доступ только к строкам, уже находящимся в корзине.
+// Возвращает количество удалённых секретов.
+func (r *SecretRepository) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+
+	err := runInTx(r.db, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, "SELECT set_config('app.purge_trash', 'on', true)")
+		if err != nil {
+			return fmt.Errorf("failed to enable trash purge: %w", err)
+		}
+
+		result, err := tx.ExecContext(ctx, "DELETE FROM secrets WHERE deleted_at IS NOT NULL AND deleted_at < $1", before)
+		if err != nil {
+			return err
+		}
+
+		purged, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, nil
+}
+
+// EncryptLabels заменяет открытые заголовки и метаданные секретов пользователя зашифрованными клиентом
+// вместе с токенами слепого индекса. Заголовки версий этих секретов, сохранённые открытыми, заменяются
+// теми же значениями, чтобы открытый текст не оставался в истории. Секреты, заголовки которых уже
+// зашифрованы, не изменяются, поэтому повторный вызов безопасен. Ревизия секретов не увеличивается:
+// содержимое секретов не меняется. Возвращает ErrNotFound, если секрет не найден у пользователя.
+// При любой ошибке изменения не применяются.
+func (r *SecretRepository) EncryptLabels(ctx context.Context, userID uint64, secrets models.Secrets) error {
+	return runAsUser(ctx, r.db, userID, func(tx *sqlx.Tx) error {
+		for _, secret := range secrets {
+			tokens := strings.Join(secret.SearchTokens, " ")
+
+			var encrypted bool
+			err := tx.QueryRowxContext(ctx, "SELECT labels_encrypted FROM secrets WHERE id = $1 AND user_id = $2 FOR UPDATE", secret.ID, userID).Scan(&encrypted)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return fmt.Errorf("secret with ID %d: %w", secret.ID, gophKeeperErrors.ErrNotFound)
+				}
+				return err
+			}
+			if encrypted {
+				continue
+			}
+
+			query := `UPDATE secrets SET title = $1, metadata = $2, labels_encrypted = true, search_tokens = ` + tokensArray("$3") + `
+			WHERE id = $4 AND user_id = $5`
+			if _, err = tx.ExecContext(ctx, query, secret.Title, secret.Metadata, tokens, secret.ID, userID); err != nil {
+				return err
+			}
+
+			query = `UPDATE secret_versions SET title = $1, metadata = $2, labels_encrypted = true, search_tokens = ` + tokensArray("$3") + `
+			WHERE secret_id = $4 AND user_id = $5 AND NOT labels_encrypted`
+			if _, err = tx.ExecContext(ctx, query, secret.Title, secret.Metadata, tokens, secret.ID, userID); err != nil {
+				return fmt.Errorf("failed to encrypt version labels: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// tokensArray возвращает выражение, разбирающее параметр param со строкой токенов слепого индекса
+// через пробел в массив; пустая строка даёт пустой массив.
+func tokensArray(param string) string {
+	return "string_to_array(" + param + ", ' ')"
+}
+
+// archiveSecret сохраняет текущее состояние секрета в историю версий.
+func archiveSecret(ctx context.Context, tx *sqlx.Tx, secretID, userID uint64) error {
+	query := `INSERT INTO secret_versions (secret_id, user_id, title, metadata, labels_encrypted, search_tokens, secret_type, payload, data_key, blob_id, updated_at)
+		SELECT id, user_id, title, metadata, labels_encrypted, search_tokens, secret_type, payload, data_key, blob_id, updated_at FROM secrets WHERE id = $1 AND user_id = $2`
+	_, err := tx.ExecContext(ctx, query, secretID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to archive secret: %w", err)
+	}
+	return nil
+}
+
+// pruneVersions удаляет самые старые версии секретов пользователя сверх limit.
+func pruneVersions(ctx context.Context, tx *sqlx.Tx, userID uint64, limit int) error {
+	query := `DELETE FROM secret_versions WHERE user_id = $1 AND id NOT IN (
+		SELECT id FROM secret_versions WHERE user_id = $1 ORDER BY id DESC LIMIT $2)`
+	_, err := tx.ExecContext(ctx, query, userID, limit)
+	if err != nil {
+		return fmt.Errorf("failed to prune versions: %w", err)
+	}
+	return nil
+}
+
+// runAsUser выполняет функцию fn в транзакции от имени пользователя userID.
+// Идентификатор пользователя записывается в параметр app.user_id на время транзакции;
+// по нему политики построчной защиты ограничивают доступные строки таблицы secrets.
+func runAsUser(ctx context.Context, db *sqlx.DB, userID uint64, fn func(tx *sqlx.Tx) error) error {
+	return runInTx(db, func(tx *sqlx.Tx) error {
+		if err := setTenant(ctx, tx, userID); err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
+
+// setTenant записывает идентификатор пользователя userID в параметр app.user_id до конца транзакции.
+func setTenant(ctx context.Context, tx *sqlx.Tx, userID uint64) error {
+	_, err := tx.ExecContext(ctx, "SELECT set_config('app.user_id', $1, true)", strconv.FormatUint(userID, 10))
+	if err != nil {
+		return fmt.Errorf("failed to set tenant: %w", err)
+	}
+	return nil
 }
 
 // runInTx выполняет функцию fn в рамках транзакции.
@@ -1,9 +1,12 @@
 package repository
 
 import (
+	serverModels "beliaev-aa/GophKeeper/internal/server/models"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
 	"beliaev-aa/GophKeeper/pkg/models"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
@@ -25,9 +28,12 @@ func TestSecretRepository(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "metadata", "secret_type", "payload", "created_at", "updated_at"}).
 					AddRow(1, 1, "Test Secret", "Metadata", "text", []byte("payload"), time.Now(), time.Now())
 
-				mock.ExpectQuery(`SELECT \* FROM secrets WHERE id = \$1 AND user_id = \$2`).
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT (.+) FROM secrets WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NULL`).
 					WithArgs(1, 1).
 					WillReturnRows(rows)
+				mock.ExpectCommit()
 
 				secret, err := repo.GetSecret(ctx, 1, 1)
 				if err != nil {
@@ -42,9 +48,12 @@ func TestSecretRepository(t *testing.T) {
 		{
 			name: "GetSecret_Fail_NotFound",
 			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(`SELECT \* FROM secrets WHERE id = \$1 AND user_id = \$2`).
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT (.+) FROM secrets WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NULL`).
 					WithArgs(1, 1).
 					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
 
 				_, err := repo.GetSecret(ctx, 1, 1)
 				if err == nil {
@@ -53,6 +62,39 @@ func TestSecretRepository(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "GetSecret_Fail_OtherUser",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "2")
+				mock.ExpectQuery(`SELECT (.+) FROM secrets WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NULL`).
+					WithArgs(1, 2).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+
+				_, err := repo.GetSecret(ctx, 1, 2)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "GetSecret_Fail_Tenant",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`SELECT set_config\('app.user_id', \$1, true\)`).
+					WithArgs("1").
+					WillReturnError(fmt.Errorf("database error"))
+				mock.ExpectRollback()
+
+				_, err := repo.GetSecret(ctx, 1, 1)
+				if err == nil || err.Error() != "failed to set tenant: database error" {
+					t.Errorf("Expected error 'failed to set tenant: database error', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
 		{
 			name: "GetUserSecrets_Success",
 			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
@@ -60,11 +102,14 @@ func TestSecretRepository(t *testing.T) {
 					AddRow(1, 1, "Secret 1", "Metadata 1", "text", []byte("payload1"), time.Now(), time.Now()).
 					AddRow(2, 1, "Secret 2", "Metadata 2", "text", []byte("payload2"), time.Now(), time.Now())
 
-				mock.ExpectQuery(`SELECT \* FROM secrets WHERE user_id = \$1 ORDER BY updated_at DESC`).
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT (.+) FROM secrets WHERE user_id = \$1 AND deleted_at IS NULL ORDER BY updated_at DESC`).
 					WithArgs(1).
 					WillReturnRows(rows)
+				mock.ExpectCommit()
 
-				secrets, err := repo.GetUserSecrets(ctx, 1)
+				secrets, err := repo.GetUserSecrets(ctx, 1, models.SecretFilter{})
 				if err != nil {
 					t.Errorf("Expected no error, got %v", err)
 				}
@@ -74,14 +119,195 @@ func TestSecretRepository(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name: "GetUserSecrets_FolderAndTag",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT (.+) FROM secrets WHERE user_id = \$1 AND deleted_at IS NULL AND folder_id IN \(WITH RECURSIVE subtree AS (.+) WHERE id = \$2\s+(.+) AND EXISTS \(SELECT 1 FROM secret_tags WHERE secret_tags.secret_id = secrets.id AND tag_id = \$3\) ORDER BY updated_at DESC`).
+					WithArgs(1, 4, 7).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "folder_id", "tag_ids"}).AddRow(3, 1, 6, "2,7"))
+				mock.ExpectCommit()
+
+				secrets, err := repo.GetUserSecrets(ctx, 1, models.SecretFilter{FolderID: 4, TagID: 7})
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if len(secrets) != 1 || secrets[0].FolderID != 6 || len(secrets[0].TagIDs) != 2 || secrets[0].TagIDs[1] != 7 {
+					t.Errorf("Expected secret 3 in folder 6 with tags [2 7], got %+v", secrets)
+				}
+			},
+			expectErr: false,
+		},
 		{
 			name: "GetUserSecrets_Fail_QueryError",
 			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(`SELECT \* FROM secrets WHERE user_id = \$1 ORDER BY updated_at DESC`).
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT (.+) FROM secrets WHERE user_id = \$1 AND deleted_at IS NULL ORDER BY updated_at DESC`).
 					WithArgs(1).
 					WillReturnError(fmt.Errorf("database error"))
+				mock.ExpectRollback()
 
-				_, err := repo.GetUserSecrets(ctx, 1)
+				_, err := repo.GetUserSecrets(ctx, 1, models.SecretFilter{})
+				if err == nil || err.Error() != "database error" {
+					t.Errorf("Expected error 'database error', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "Sync_Delta",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT sync_revision FROM users WHERE id = \$1`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"sync_revision"}).AddRow(12))
+				mock.ExpectQuery(`SELECT (.+) FROM secrets\s+WHERE user_id = \$1 AND deleted_at IS NULL AND change_revision > \$2 AND change_revision <= \$3 ORDER BY updated_at DESC`).
+					WithArgs(1, 10, 12).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title"}).AddRow(3, 1, "Changed"))
+				mock.ExpectQuery(`SELECT id FROM secrets WHERE user_id = \$1 AND deleted_at IS NOT NULL AND change_revision > \$2 AND change_revision <= \$3\s+UNION SELECT secret_id FROM secret_tombstones WHERE user_id = \$1 AND change_revision > \$2 AND change_revision <= \$3`).
+					WithArgs(1, 10, 12).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4).AddRow(5))
+				mock.ExpectCommit()
+
+				delta, err := repo.Sync(ctx, 1, 10)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if delta.Full || delta.Revision != 12 {
+					t.Errorf("Expected delta up to revision 12, got %+v", delta)
+				}
+				if len(delta.Secrets) != 1 || delta.Secrets[0].ID != 3 {
+					t.Errorf("Expected changed secret 3, got %v", delta.Secrets)
+				}
+				if len(delta.DeletedIDs) != 2 || delta.DeletedIDs[0] != 4 || delta.DeletedIDs[1] != 5 {
+					t.Errorf("Expected deleted secrets [4 5], got %v", delta.DeletedIDs)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "Sync_Full_UnknownRevision",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT sync_revision FROM users WHERE id = \$1`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"sync_revision"}).AddRow(5))
+				mock.ExpectQuery(`SELECT (.+) FROM secrets\s+WHERE user_id = \$1 AND deleted_at IS NULL AND change_revision <= \$2 ORDER BY updated_at DESC`).
+					WithArgs(1, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title"}).AddRow(1, 1, "First").AddRow(2, 1, "Second"))
+				mock.ExpectCommit()
+
+				delta, err := repo.Sync(ctx, 1, 9)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if !delta.Full || delta.Revision != 5 || len(delta.Secrets) != 2 || len(delta.DeletedIDs) != 0 {
+					t.Errorf("Expected full sync of 2 secrets at revision 5, got %+v", delta)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "Sync_Fail_Revision",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT sync_revision FROM users WHERE id = \$1`).
+					WithArgs(1).
+					WillReturnError(fmt.Errorf("database error"))
+				mock.ExpectRollback()
+
+				_, err := repo.Sync(ctx, 1, 0)
+				if err == nil || err.Error() != "failed to read sync revision: database error" {
+					t.Errorf("Expected error 'failed to read sync revision: database error', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "List_FirstPage",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT id, user_id, title, metadata, labels_encrypted, secret_type, data_key, created_at, (.+) FROM secrets WHERE user_id = \$1 AND deleted_at IS NULL ORDER BY updated_at DESC, id DESC LIMIT \$2`).
+					WithArgs(1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title"}).AddRow(2, 1, "Second").AddRow(1, 1, "First"))
+				mock.ExpectCommit()
+
+				secrets, err := repo.List(ctx, 1, &models.SecretListQuery{}, nil, 3)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if len(secrets) != 2 || secrets[0].ID != 2 || secrets[1].ID != 1 {
+					t.Errorf("Expected secrets [2 1], got %v", secrets)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "List_FiltersAfterCursor",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				since := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				cursorAt := since.Add(time.Hour)
+				mock.ExpectQuery(`SELECT (.+) FROM secrets WHERE user_id = \$1 AND deleted_at IS NULL AND secret_type = \$2 AND updated_at >= \$3 AND \(updated_at, id\) > \(\$4, \$5\) ORDER BY updated_at ASC, id ASC LIMIT \$6`).
+					WithArgs(1, "credentials", since, cursorAt, 4, 11).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title"}).AddRow(7, 1, "Bank 2"))
+				mock.ExpectCommit()
+
+				query := &models.SecretListQuery{
+					SecretType:   "credentials",
+					UpdatedSince: since,
+					Order:        models.SecretOrderUpdatedAsc,
+				}
+				secrets, err := repo.List(ctx, 1, query, &serverModels.SecretCursor{ID: 4, UpdatedAt: cursorAt}, 11)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if len(secrets) != 1 || secrets[0].ID != 7 {
+					t.Errorf("Expected secret 7, got %v", secrets)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "List_SearchTokens",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT (.+) FROM secrets WHERE user_id = \$1 AND deleted_at IS NULL AND search_tokens @> string_to_array\(\$2, ' '\) ORDER BY updated_at DESC, id DESC LIMIT \$3`).
+					WithArgs(1, "token1 token2", 5).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "labels_encrypted"}).AddRow(3, 1, "encrypted", true))
+				mock.ExpectCommit()
+
+				secrets, err := repo.List(ctx, 1, &models.SecretListQuery{SearchTokens: []string{"token1", "token2"}}, nil, 5)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if len(secrets) != 1 || !secrets[0].LabelsEncrypted {
+					t.Errorf("Expected secret with encrypted labels, got %v", secrets)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "List_Fail",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				updated := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT (.+) FROM secrets WHERE user_id = \$1 AND deleted_at IS NULL AND \(updated_at, id\) > \(\$2, \$3\) ORDER BY updated_at ASC, id ASC LIMIT \$4`).
+					WithArgs(1, updated, 4, 10).
+					WillReturnError(fmt.Errorf("database error"))
+				mock.ExpectRollback()
+
+				query := &models.SecretListQuery{Order: models.SecretOrderUpdatedAsc}
+				_, err := repo.List(ctx, 1, query, &serverModels.SecretCursor{ID: 4, UpdatedAt: updated}, 10)
 				if err == nil || err.Error() != "database error" {
 					t.Errorf("Expected error 'database error', got %v", err)
 				}
@@ -91,16 +317,22 @@ func TestSecretRepository(t *testing.T) {
 		{
 			name: "Create_Success",
 			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(`INSERT INTO secrets \(user_id, title, metadata, secret_type, payload\) VALUES \(\$1, \$2, \$3, \$4, \$5\) RETURNING id`).
-					WithArgs(1, "Test Secret", "Metadata", "text", []byte("payload")).
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`INSERT INTO secrets \(id, user_id, title, metadata, secret_type, payload, blob_id, folder_id, labels_encrypted, search_tokens, data_key\)\s+VALUES \(COALESCE\(NULLIF\(\$1, 0\), nextval\(pg_get_serial_sequence\('secrets', 'id'\)\)\), \$2, \$3, \$4, \$5, \$6, NULLIF\(\$7, ''\), NULLIF\(\$8, 0\), \$9, string_to_array\(\$10, ' '\), \$11\)\s+RETURNING id`).
+					WithArgs(0, 1, "Test Secret", "Metadata", "text", []byte("payload"), "", 0, true, "token1 token2", []byte("wrapped")).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectCommit()
 
 				secret := &models.Secret{
-					UserID:     1,
-					Title:      "Test Secret",
-					Metadata:   "Metadata",
-					SecretType: "text",
-					Payload:    []byte("payload"),
+					UserID:          1,
+					Title:           "Test Secret",
+					Metadata:        "Metadata",
+					SecretType:      "text",
+					Payload:         []byte("payload"),
+					LabelsEncrypted: true,
+					SearchTokens:    []string{"token1", "token2"},
+					DataKey:         []byte("wrapped"),
 				}
 				id, err := repo.Create(ctx, secret)
 				if err != nil {
@@ -116,35 +348,222 @@ func TestSecretRepository(t *testing.T) {
 			name: "Update_Success",
 			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectQuery(`SELECT 1 FROM secrets WHERE id = \$1 FOR UPDATE`).
-					WithArgs(1).
-					WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
-				mock.ExpectExec(`UPDATE secrets SET updated_at = \$1, title = \$2, metadata = \$3, secret_type = \$4, payload = \$5 WHERE id = \$6`).
-					WithArgs(sqlmock.AnyArg(), "Updated Title", "Updated Metadata", "text", []byte("updated payload"), 1).
-					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT revision FROM secrets WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NULL FOR UPDATE`).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(3))
+				expectArchive(mock, 1, 1)
+				mock.ExpectQuery(`UPDATE secrets SET updated_at = \$1, title = \$2, metadata = \$3, secret_type = \$4, payload = \$5, blob_id = NULLIF\(\$6, ''\),\s+folder_id = NULLIF\(\$7, 0\), labels_encrypted = \$8, search_tokens = string_to_array\(\$9, ' '\), data_key = \$10,\s+revision = revision \+ 1 WHERE id = \$11 AND user_id = \$12 RETURNING revision`).
+					WithArgs(sqlmock.AnyArg(), "Updated Title", "Updated Metadata", "text", []byte("updated payload"), "", 0, false, "", []byte("wrapped"), 1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(4))
+				mock.ExpectExec(`DELETE FROM secret_tags WHERE secret_id = \$1 AND user_id = \$2`).
+					WithArgs(1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				expectPrune(mock, 1, 10)
 				mock.ExpectCommit()
 
 				secret := &models.Secret{
 					ID:         1,
+					UserID:     1,
 					Title:      "Updated Title",
 					Metadata:   "Updated Metadata",
 					SecretType: "text",
 					Payload:    []byte("updated payload"),
+					DataKey:    []byte("wrapped"),
 					UpdatedAt:  time.Now(),
+					Revision:   3,
 				}
-				err := repo.Update(ctx, secret)
+				err := repo.Update(ctx, secret, 10)
 				if err != nil {
 					t.Errorf("Expected no error, got %v", err)
 				}
+				if secret.Revision != 4 {
+					t.Errorf("Expected revision 4, got %d", secret.Revision)
+				}
 			},
 			expectErr: false,
 		},
+		{
+			name: "ReserveID_Success",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`INSERT INTO secret_id_reservations \(id, user_id\) VALUES \(nextval\(pg_get_serial_sequence\('secrets', 'id'\)\), \$1\) RETURNING id`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+				mock.ExpectCommit()
+
+				id, err := repo.ReserveID(ctx, 1)
+				if err != nil || id != 12 {
+					t.Errorf("Expected ID 12, got %v, err = %v", id, err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "Create_ReservedID",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectExec(`DELETE FROM secret_id_reservations WHERE id = \$1 AND user_id = \$2`).
+					WithArgs(12, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`INSERT INTO secrets (.+) RETURNING id`).
+					WithArgs(12, 1, "Bank", "", "text", []byte("payload"), "", 0, false, "", []byte(nil)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+				mock.ExpectCommit()
+
+				id, err := repo.Create(ctx, &models.Secret{ID: 12, UserID: 1, Title: "Bank", SecretType: "text", Payload: []byte("payload")})
+				if err != nil || id != 12 {
+					t.Errorf("Expected ID 12, got %v, err = %v", id, err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "Create_Fail_NotReserved",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectExec(`DELETE FROM secret_id_reservations WHERE id = \$1 AND user_id = \$2`).
+					WithArgs(12, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+
+				_, err := repo.Create(ctx, &models.Secret{ID: 12, UserID: 1, Title: "Bank", SecretType: "text", Payload: []byte("payload")})
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected ErrNotFound, got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "Create_WithFolderAndTags",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM folders WHERE id = \$1 AND user_id = \$2\)`).
+					WithArgs(5, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery(`INSERT INTO secrets (.+) RETURNING id`).
+					WithArgs(0, 1, "Bank", "", "text", []byte("payload"), "", 5, false, "", []byte(nil)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+				mock.ExpectQuery(`SELECT count\(\*\) FROM tags WHERE user_id = \$1 AND id IN \(\$2, \$3\)`).
+					WithArgs(1, 2, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				mock.ExpectExec(`DELETE FROM secret_tags WHERE secret_id = \$1 AND user_id = \$2`).
+					WithArgs(8, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				for _, tagID := range []int{2, 3} {
+					mock.ExpectExec(`INSERT INTO secret_tags \(secret_id, tag_id, user_id\) VALUES \(\$1, \$2, \$3\)`).
+						WithArgs(8, tagID, 1).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+				mock.ExpectCommit()
+
+				secret := &models.Secret{UserID: 1, Title: "Bank", SecretType: "text", Payload: []byte("payload"), FolderID: 5, TagIDs: models.IDList{3, 2, 3}}
+				id, err := repo.Create(ctx, secret)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if id != 8 {
+					t.Errorf("Expected ID 8, got %v", id)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "Create_Fail_ForeignFolder",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM folders WHERE id = \$1 AND user_id = \$2\)`).
+					WithArgs(5, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectRollback()
+
+				_, err := repo.Create(ctx, &models.Secret{UserID: 1, FolderID: 5})
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "Update_Fail_ForeignTag",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT revision FROM secrets WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NULL FOR UPDATE`).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(3))
+				expectArchive(mock, 1, 1)
+				mock.ExpectQuery(`UPDATE secrets SET (.+) RETURNING revision`).
+					WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(4))
+				mock.ExpectQuery(`SELECT count\(\*\) FROM tags WHERE user_id = \$1 AND id IN \(\$2\)`).
+					WithArgs(1, 9).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectRollback()
+
+				err := repo.Update(ctx, &models.Secret{ID: 1, UserID: 1, Revision: 3, TagIDs: models.IDList{9}}, 10)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "Update_Fail_StaleRevision",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT revision FROM secrets WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NULL FOR UPDATE`).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(5))
+				mock.ExpectRollback()
+
+				err := repo.Update(ctx, &models.Secret{ID: 1, UserID: 1, Revision: 3}, 10)
+				var conflict *gophKeeperErrors.RevisionConflictError
+				if !errors.As(err, &conflict) || conflict.Current != 5 {
+					t.Errorf("Expected revision conflict with current revision 5, got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "Update_Fail_OtherUser",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "2")
+				mock.ExpectQuery(`SELECT revision FROM secrets WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NULL FOR UPDATE`).
+					WithArgs(1, 2).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+
+				secret := &models.Secret{
+					ID:         1,
+					UserID:     2,
+					Title:      "Hijacked",
+					SecretType: "text",
+					Payload:    []byte("payload"),
+					UpdatedAt:  time.Now(),
+				}
+				err := repo.Update(ctx, secret, 10)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
 		{
 			name: "Delete_Success",
 			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
-				mock.ExpectExec(`DELETE FROM secrets WHERE id = \$1 AND user_id = \$2`).
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectExec(`UPDATE secrets SET deleted_at = now\(\) WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NULL`).
 					WithArgs(1, 1).
 					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
 
 				err := repo.Delete(ctx, 1, 1)
 				if err != nil {
@@ -153,6 +572,338 @@ func TestSecretRepository(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name: "Delete_Fail_OtherUser",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "2")
+				mock.ExpectExec(`UPDATE secrets SET deleted_at = now\(\) WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NULL`).
+					WithArgs(1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+
+				err := repo.Delete(ctx, 1, 2)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "Update_Fail_Archive",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT revision FROM secrets WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NULL FOR UPDATE`).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(0))
+				mock.ExpectExec(`INSERT INTO secret_versions`).
+					WithArgs(1, 1).
+					WillReturnError(fmt.Errorf("database error"))
+				mock.ExpectRollback()
+
+				err := repo.Update(ctx, &models.Secret{ID: 1, UserID: 1}, 10)
+				if err == nil || err.Error() != "failed to archive secret: database error" {
+					t.Errorf("Expected error 'failed to archive secret: database error', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "ListVersions_Success",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"version_id", "id", "user_id", "title", "metadata", "secret_type", "payload", "updated_at", "archived_at"}).
+					AddRow(5, 1, 1, "Title v2", "", "text", []byte("payload2"), time.Now(), time.Now()).
+					AddRow(4, 1, 1, "Title v1", "", "text", []byte("payload1"), time.Now(), time.Now())
+
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT 1 FROM secrets WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NULL`).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+				mock.ExpectQuery(`SELECT id AS version_id, secret_id AS id, (.+) FROM secret_versions WHERE secret_id = \$1 AND user_id = \$2 ORDER BY id DESC`).
+					WithArgs(1, 1).
+					WillReturnRows(rows)
+				mock.ExpectCommit()
+
+				versions, err := repo.ListVersions(ctx, 1, 1)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if len(versions) != 2 || versions[0].VersionID != 5 || versions[0].ID != 1 || versions[1].Title != "Title v1" {
+					t.Errorf("Unexpected versions: %+v", versions)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "ListVersions_Fail_OtherUser",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "2")
+				mock.ExpectQuery(`SELECT 1 FROM secrets WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NULL`).
+					WithArgs(1, 2).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+
+				_, err := repo.ListVersions(ctx, 1, 2)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "RestoreVersion_Success",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT 1 FROM secrets WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NULL FOR UPDATE`).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+				mock.ExpectQuery(`SELECT id AS version_id, secret_id AS id, (.+) FROM secret_versions WHERE id = \$1 AND secret_id = \$2 AND user_id = \$3`).
+					WithArgs(4, 1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"version_id", "id", "user_id", "title", "metadata", "labels_encrypted", "secret_type", "payload", "data_key", "updated_at", "archived_at"}).
+						AddRow(4, 1, 1, "Title v1", "Meta", true, "text", []byte("payload1"), []byte("wrapped"), time.Now(), time.Now()))
+				expectArchive(mock, 1, 1)
+				mock.ExpectQuery(`UPDATE secrets SET updated_at = now\(\), title = \$1, metadata = \$2, secret_type = \$3, payload = \$4, blob_id = NULLIF\(\$5, ''\),\s+labels_encrypted = \$6, search_tokens = \(SELECT search_tokens FROM secret_versions WHERE id = \$7\), data_key = \$8,\s+revision = revision \+ 1 WHERE id = \$9 AND user_id = \$10 RETURNING (.+)`).
+					WithArgs("Title v1", "Meta", "text", []byte("payload1"), "", true, 4, []byte("wrapped"), 1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "metadata", "secret_type", "payload", "created_at", "updated_at"}).
+						AddRow(1, 1, "Title v1", "Meta", "text", []byte("payload1"), time.Now(), time.Now()))
+				expectPrune(mock, 1, 10)
+				mock.ExpectCommit()
+
+				secret, err := repo.RestoreVersion(ctx, 1, 4, 1, 10)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if secret.ID != 1 || secret.Title != "Title v1" || string(secret.Payload) != "payload1" {
+					t.Errorf("Unexpected secret data: %+v", secret)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "EncryptLabels_Success",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT labels_encrypted FROM secrets WHERE id = \$1 AND user_id = \$2 FOR UPDATE`).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"labels_encrypted"}).AddRow(false))
+				mock.ExpectExec(`UPDATE secrets SET title = \$1, metadata = \$2, labels_encrypted = true, search_tokens = string_to_array\(\$3, ' '\)\s+WHERE id = \$4 AND user_id = \$5`).
+					WithArgs("sealed title", "sealed metadata", "token1", 1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE secret_versions SET title = \$1, metadata = \$2, labels_encrypted = true, search_tokens = string_to_array\(\$3, ' '\)\s+WHERE secret_id = \$4 AND user_id = \$5 AND NOT labels_encrypted`).
+					WithArgs("sealed title", "sealed metadata", "token1", 1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectQuery(`SELECT labels_encrypted FROM secrets WHERE id = \$1 AND user_id = \$2 FOR UPDATE`).
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"labels_encrypted"}).AddRow(true))
+				mock.ExpectCommit()
+
+				err := repo.EncryptLabels(ctx, 1, models.Secrets{
+					{ID: 1, Title: "sealed title", Metadata: "sealed metadata", SearchTokens: []string{"token1"}},
+					{ID: 2, Title: "sealed again", Metadata: "sealed again"},
+				})
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "EncryptLabels_Fail_NotFound",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT labels_encrypted FROM secrets WHERE id = \$1 AND user_id = \$2 FOR UPDATE`).
+					WithArgs(9, 1).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+
+				err := repo.EncryptLabels(ctx, 1, models.Secrets{{ID: 9, Title: "sealed"}})
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "RestoreVersion_Fail_VersionNotFound",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT 1 FROM secrets WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NULL FOR UPDATE`).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+				mock.ExpectQuery(`SELECT id AS version_id, secret_id AS id, (.+) FROM secret_versions WHERE id = \$1 AND secret_id = \$2 AND user_id = \$3`).
+					WithArgs(9, 1, 1).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+
+				_, err := repo.RestoreVersion(ctx, 1, 9, 1, 10)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "RestoreVersion_Fail_OtherUser",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "2")
+				mock.ExpectQuery(`SELECT 1 FROM secrets WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NULL FOR UPDATE`).
+					WithArgs(1, 2).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+
+				_, err := repo.RestoreVersion(ctx, 1, 4, 2, 10)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "ListTrash_Success",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "metadata", "secret_type", "payload", "created_at", "updated_at", "deleted_at"}).
+					AddRow(1, 1, "Deleted Secret", "Metadata", "text", []byte("payload"), time.Now(), time.Now(), time.Now())
+
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT (.+) FROM secrets WHERE user_id = \$1 AND deleted_at IS NOT NULL ORDER BY deleted_at DESC`).
+					WithArgs(1).
+					WillReturnRows(rows)
+				mock.ExpectCommit()
+
+				secrets, err := repo.ListTrash(ctx, 1)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if len(secrets) != 1 || secrets[0].DeletedAt == nil {
+					t.Errorf("Expected 1 trashed secret, got %v", secrets)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "Restore_Success",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectExec(`UPDATE secrets SET deleted_at = NULL, updated_at = now\(\), revision = revision \+ 1 WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NOT NULL`).
+					WithArgs(1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				err := repo.Restore(ctx, 1, 1)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "Restore_Fail_NotInTrash",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectExec(`UPDATE secrets SET deleted_at = NULL, updated_at = now\(\), revision = revision \+ 1 WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NOT NULL`).
+					WithArgs(1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+
+				err := repo.Restore(ctx, 1, 1)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "Purge_Success",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT blob_id FROM secrets WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NOT NULL AND blob_id IS NOT NULL\s+UNION SELECT blob_id FROM secret_versions WHERE secret_id = \$1 AND user_id = \$2 AND blob_id IS NOT NULL`).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"blob_id"}).AddRow("current").AddRow("previous"))
+				mock.ExpectExec(`DELETE FROM secrets WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NOT NULL`).
+					WithArgs(1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				blobIDs, err := repo.Purge(ctx, 1, 1)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if len(blobIDs) != 2 || blobIDs[0] != "current" || blobIDs[1] != "previous" {
+					t.Errorf("Expected blobs [current previous], got %v", blobIDs)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "Purge_Fail_NotInTrash",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT blob_id FROM secrets`).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"blob_id"}))
+				mock.ExpectExec(`DELETE FROM secrets WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NOT NULL`).
+					WithArgs(1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+
+				_, err := repo.Purge(ctx, 1, 1)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "PurgeExpired_Success",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				before := time.Now()
+
+				mock.ExpectBegin()
+				mock.ExpectExec(`SELECT set_config\('app.purge_trash', 'on', true\)`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`DELETE FROM secrets WHERE deleted_at IS NOT NULL AND deleted_at < \$1`).
+					WithArgs(before).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectCommit()
+
+				purged, err := repo.PurgeExpired(ctx, before)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if purged != 3 {
+					t.Errorf("Expected 3 purged secrets, got %d", purged)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "PurgeExpired_Fail",
+			testFunc: func(t *testing.T, repo ISecretRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`SELECT set_config\('app.purge_trash', 'on', true\)`).
+					WillReturnError(fmt.Errorf("database error"))
+				mock.ExpectRollback()
+
+				_, err := repo.PurgeExpired(ctx, time.Now())
+				if err == nil || err.Error() != "failed to enable trash purge: database error" {
+					t.Errorf("Expected error 'failed to enable trash purge: database error', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -173,3 +924,24 @@ func TestSecretRepository(t *testing.T) {
 		})
 	}
 }
+
+// expectTenant ожидает установку параметра app.user_id в начале транзакции.
+func expectTenant(mock sqlmock.Sqlmock, userID string) {
+	mock.ExpectExec(`SELECT set_config\('app.user_id', \$1, true\)`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+// expectArchive ожидает сохранение текущего состояния секрета в историю версий.
+func expectArchive(mock sqlmock.Sqlmock, secretID, userID int) {
+	mock.ExpectExec(`INSERT INTO secret_versions \(secret_id, user_id, title, metadata, labels_encrypted, search_tokens, secret_type, payload, data_key, blob_id, updated_at\)\s+SELECT (.+) FROM secrets WHERE id = \$1 AND user_id = \$2`).
+		WithArgs(secretID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+// expectPrune ожидает удаление версий пользователя сверх лимита.
+func expectPrune(mock sqlmock.Sqlmock, userID, limit int) {
+	mock.ExpectExec(`DELETE FROM secret_versions WHERE user_id = \$1 AND id NOT IN \(\s*SELECT id FROM secret_versions WHERE user_id = \$1 ORDER BY id DESC LIMIT \$2\)`).
+		WithArgs(userID, limit).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
@@ -0,0 +1,194 @@
+// Package repository предоставляет доступ к сессиям пользователей и их токенам обновления.
+package repository
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/models"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+// ISessionRepository определяет интерфейс для репозитория сессий пользователей.
+type ISessionRepository interface {
+	Create(ctx context.Context, session models.Session) (uint64, error)
+	GetByTokenHash(ctx context.Context, tokenHash []byte) (*models.Session, error)
+	ListActive(ctx context.Context, userID int) ([]models.Session, error)
+	IsActive(ctx context.Context, userID int, sessionID uint64) (bool, error)
+	Rotate(ctx context.Context, sessionID uint64, oldHash, newHash []byte, expiresAt time.Time) error
+	Rename(ctx context.Context, userID int, sessionID uint64, deviceName string) error
+	Revoke(ctx context.Context, userID int, sessionID uint64) error
+	RevokeDevice(ctx context.Context, userID int, deviceID uint64) ([]uint64, error)
+	RevokeAll(ctx context.Context, userID int) error
+	RevokeOthers(ctx context.Context, userID int, sessionID uint64) ([]uint64, error)
+}
+
+// sessionColumns перечисляет колонки таблицы sessions, читаемые в models.Session.
+const sessionColumns = `id, user_id, device_id, device_name, token_hash, previous_token_hash,
+	created_at, last_used_at, expires_at, revoked_at`
+
+// SessionRepository предоставляет методы для работы с сессиями пользователей в базе данных.
+type SessionRepository struct {
+	db *sqlx.DB
+}
+
+// NewSessionRepository создаёт новый экземпляр SessionRepository.
+// Функция принимает подключение к базе данных SQLX и возвращает указатель на SessionRepository.
+func NewSessionRepository(db *sqlx.DB) ISessionRepository {
+	return &SessionRepository{
+		db: db,
+	}
+}
+
+// Create сохраняет новую сессию пользователя.
+// Возвращает идентификатор созданной сессии или ошибку.
+func (r *SessionRepository) Create(ctx context.Context, session models.Session) (uint64, error) {
+	var sessionID uint64
+	err := r.db.QueryRowxContext(ctx,
+		"INSERT INTO sessions (user_id, device_id, device_name, token_hash, expires_at) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+		session.UserID,
+		session.DeviceID,
+		session.DeviceName,
+		session.TokenHash,
+		session.ExpiresAt,
+	).Scan(&sessionID)
+	if err != nil {
+		return 0, err
+	}
+	return sessionID, nil
+}
+
+// GetByTokenHash возвращает сессию, текущий или предыдущий refresh-токен которой имеет заданный хэш.
+// Возвращает ErrNotFound, если такой сессии нет.
+func (r *SessionRepository) GetByTokenHash(ctx context.Context, tokenHash []byte) (*models.Session, error) {
+	var session models.Session
+	err := r.db.QueryRowxContext(ctx,
+		"SELECT "+sessionColumns+" FROM sessions WHERE token_hash = $1 OR previous_token_hash = $1",
+		tokenHash,
+	).StructScan(&session)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, gophKeeperErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListActive возвращает неотозванные и неистёкшие сессии пользователя, начиная с последней использованной.
+func (r *SessionRepository) ListActive(ctx context.Context, userID int) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.SelectContext(ctx, &sessions,
+		"SELECT "+sessionColumns+` FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > now()
+		ORDER BY last_used_at DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// IsActive проверяет, что сессия принадлежит пользователю и не отозвана.
+func (r *SessionRepository) IsActive(ctx context.Context, userID int, sessionID uint64) (bool, error) {
+	var active bool
+	err := r.db.QueryRowxContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM sessions WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL)",
+		sessionID,
+		userID,
+	).Scan(&active)
+	if err != nil {
+		return false, err
+	}
+	return active, nil
+}
+
+// Rotate заменяет refresh-токен активной сессии, сохраняя хэш предыдущего токена.
+// Замена выполняется только если текущий токен сессии всё ещё равен oldHash,
+// поэтому из двух одновременных обновлений успешным будет только одно.
+// Возвращает ErrNotFound, если сессия не найдена, отозвана или токен уже заменён.
+func (r *SessionRepository) Rotate(ctx context.Context, sessionID uint64, oldHash, newHash []byte, expiresAt time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET previous_token_hash = token_hash, token_hash = $1, expires_at = $2, last_used_at = now()
+		WHERE id = $3 AND token_hash = $4 AND revoked_at IS NULL`,
+		newHash,
+		expiresAt,
+		sessionID,
+		oldHash,
+	)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// Rename изменяет имя устройства активной сессии пользователя.
+// Возвращает ErrNotFound, если у пользователя нет такой активной сессии.
+func (r *SessionRepository) Rename(ctx context.Context, userID int, sessionID uint64, deviceName string) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE sessions SET device_name = $1 WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL",
+		deviceName,
+		sessionID,
+		userID,
+	)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// Revoke отзывает активную сессию пользователя.
+// Возвращает ErrNotFound, если у пользователя нет такой активной сессии.
+func (r *SessionRepository) Revoke(ctx context.Context, userID int, sessionID uint64) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE sessions SET revoked_at = now() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL",
+		sessionID,
+		userID,
+	)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// RevokeDevice отзывает все активные сессии пользователя на устройстве.
+// Возвращает идентификаторы отозванных сессий.
+func (r *SessionRepository) RevokeDevice(ctx context.Context, userID int, deviceID uint64) ([]uint64, error) {
+	var sessionIDs []uint64
+	err := r.db.SelectContext(ctx, &sessionIDs,
+		"UPDATE sessions SET revoked_at = now() WHERE user_id = $1 AND device_id = $2 AND revoked_at IS NULL RETURNING id",
+		userID,
+		deviceID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return sessionIDs, nil
+}
+
+// RevokeAll отзывает все активные сессии пользователя.
+func (r *SessionRepository) RevokeAll(ctx context.Context, userID int) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE sessions SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL",
+		userID,
+	)
+	return err
+}
+
+// RevokeOthers отзывает все активные сессии пользователя, кроме сессии sessionID.
+// Возвращает идентификаторы отозванных сессий.
+func (r *SessionRepository) RevokeOthers(ctx context.Context, userID int, sessionID uint64) ([]uint64, error) {
+	var sessionIDs []uint64
+	err := r.db.SelectContext(ctx, &sessionIDs,
+		"UPDATE sessions SET revoked_at = now() WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL RETURNING id",
+		userID,
+		sessionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return sessionIDs, nil
+}
@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/models"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"testing"
+	"time"
+)
+
+func TestSessionRepository(t *testing.T) {
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Hour)
+	columns := []string{"id", "user_id", "device_id", "device_name", "token_hash", "previous_token_hash", "created_at", "last_used_at", "expires_at", "revoked_at"}
+
+	tests := []struct {
+		name     string
+		testFunc func(t *testing.T, repo ISessionRepository, mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "Create_Success",
+			testFunc: func(t *testing.T, repo ISessionRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO sessions \(user_id, device_id, device_name, token_hash, expires_at\) VALUES \(\$1, \$2, \$3, \$4, \$5\) RETURNING id`).
+					WithArgs(1, 42, "laptop", []byte("hash"), expiresAt).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+				id, err := repo.Create(ctx, models.Session{UserID: 1, DeviceID: 42, DeviceName: "laptop", TokenHash: []byte("hash"), ExpiresAt: expiresAt})
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if id != 7 {
+					t.Errorf("Expected ID 7, got %d", id)
+				}
+			},
+		},
+		{
+			name: "Create_Fail_DatabaseError",
+			testFunc: func(t *testing.T, repo ISessionRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO sessions`).
+					WithArgs(1, 0, "", []byte("hash"), expiresAt).
+					WillReturnError(fmt.Errorf("database error"))
+
+				_, err := repo.Create(ctx, models.Session{UserID: 1, TokenHash: []byte("hash"), ExpiresAt: expiresAt})
+				if err == nil || err.Error() != "database error" {
+					t.Errorf("Expected error 'database error', got %v", err)
+				}
+			},
+		},
+		{
+			name: "GetByTokenHash_Success",
+			testFunc: func(t *testing.T, repo ISessionRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT id, user_id, device_id, device_name, token_hash, previous_token_hash,\s+created_at, last_used_at, expires_at, revoked_at FROM sessions WHERE token_hash = \$1 OR previous_token_hash = \$1`).
+					WithArgs([]byte("hash")).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(7, 1, 42, "laptop", []byte("hash"), nil, time.Now(), time.Now(), expiresAt, nil))
+
+				session, err := repo.GetByTokenHash(ctx, []byte("hash"))
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if session.ID != 7 || session.UserID != 1 {
+					t.Errorf("Unexpected session %+v", session)
+				}
+				if session.RevokedAt != nil {
+					t.Errorf("Expected active session, got revoked at %v", session.RevokedAt)
+				}
+			},
+		},
+		{
+			name: "GetByTokenHash_NotFound",
+			testFunc: func(t *testing.T, repo ISessionRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT .* FROM sessions`).
+					WithArgs([]byte("hash")).
+					WillReturnRows(sqlmock.NewRows(columns))
+
+				_, err := repo.GetByTokenHash(ctx, []byte("hash"))
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected ErrNotFound, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Rotate_Success",
+			testFunc: func(t *testing.T, repo ISessionRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE sessions SET previous_token_hash = token_hash, token_hash = \$1, expires_at = \$2, last_used_at = now\(\)\s+WHERE id = \$3 AND token_hash = \$4 AND revoked_at IS NULL`).
+					WithArgs([]byte("new"), expiresAt, 7, []byte("old")).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				if err := repo.Rotate(ctx, 7, []byte("old"), []byte("new"), expiresAt); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Rotate_AlreadyRotated",
+			testFunc: func(t *testing.T, repo ISessionRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE sessions SET previous_token_hash`).
+					WithArgs([]byte("new"), expiresAt, 7, []byte("old")).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				err := repo.Rotate(ctx, 7, []byte("old"), []byte("new"), expiresAt)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected ErrNotFound, got %v", err)
+				}
+			},
+		},
+		{
+			name: "ListActive_Success",
+			testFunc: func(t *testing.T, repo ISessionRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT .* FROM sessions\s+WHERE user_id = \$1 AND revoked_at IS NULL AND expires_at > now\(\)\s+ORDER BY last_used_at DESC`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(8, 1, 43, "phone", []byte("h2"), nil, time.Now(), time.Now(), expiresAt, nil).
+						AddRow(7, 1, 42, "laptop", []byte("h1"), nil, time.Now(), time.Now(), expiresAt, nil))
+
+				sessions, err := repo.ListActive(ctx, 1)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if len(sessions) != 2 || sessions[0].DeviceName != "phone" {
+					t.Errorf("Unexpected sessions %+v", sessions)
+				}
+			},
+		},
+		{
+			name: "IsActive_Revoked",
+			testFunc: func(t *testing.T, repo ISessionRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM sessions WHERE id = \$1 AND user_id = \$2 AND revoked_at IS NULL\)`).
+					WithArgs(7, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+				active, err := repo.IsActive(ctx, 1, 7)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if active {
+					t.Error("Expected revoked session to be inactive")
+				}
+			},
+		},
+		{
+			name: "Rename_Success",
+			testFunc: func(t *testing.T, repo ISessionRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE sessions SET device_name = \$1 WHERE id = \$2 AND user_id = \$3 AND revoked_at IS NULL`).
+					WithArgs("work laptop", 7, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				if err := repo.Rename(ctx, 1, 7, "work laptop"); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Revoke_Success",
+			testFunc: func(t *testing.T, repo ISessionRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE sessions SET revoked_at = now\(\) WHERE id = \$1 AND user_id = \$2 AND revoked_at IS NULL`).
+					WithArgs(7, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				if err := repo.Revoke(ctx, 1, 7); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Revoke_ForeignSession",
+			testFunc: func(t *testing.T, repo ISessionRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE sessions SET revoked_at = now\(\)`).
+					WithArgs(100, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				err := repo.Revoke(ctx, 1, 100)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected ErrNotFound, got %v", err)
+				}
+			},
+		},
+		{
+			name: "RevokeDevice_Success",
+			testFunc: func(t *testing.T, repo ISessionRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`UPDATE sessions SET revoked_at = now\(\) WHERE user_id = \$1 AND device_id = \$2 AND revoked_at IS NULL RETURNING id`).
+					WithArgs(1, 42).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5).AddRow(6))
+
+				ids, err := repo.RevokeDevice(ctx, 1, 42)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if len(ids) != 2 {
+					t.Errorf("Expected 2 revoked sessions, got %v", ids)
+				}
+			},
+		},
+		{
+			name: "RevokeAll_Success",
+			testFunc: func(t *testing.T, repo ISessionRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE sessions SET revoked_at = now\(\) WHERE user_id = \$1 AND revoked_at IS NULL`).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+
+				if err := repo.RevokeAll(ctx, 1); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "RevokeOthers_Success",
+			testFunc: func(t *testing.T, repo ISessionRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`UPDATE sessions SET revoked_at = now\(\) WHERE user_id = \$1 AND id <> \$2 AND revoked_at IS NULL RETURNING id`).
+					WithArgs(1, 7).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8).AddRow(9))
+
+				ids, err := repo.RevokeOthers(ctx, 1, 7)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if len(ids) != 2 || ids[0] != 8 {
+					t.Errorf("Expected sessions [8 9] to be revoked, got %v", ids)
+				}
+			},
+		},
+		{
+			name: "RevokeOthers_DatabaseError",
+			testFunc: func(t *testing.T, repo ISessionRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`UPDATE sessions SET revoked_at`).
+					WithArgs(1, 7).
+					WillReturnError(fmt.Errorf("database error"))
+
+				_, err := repo.RevokeOthers(ctx, 1, 7)
+				if err == nil || err.Error() != "database error" {
+					t.Errorf("Expected error 'database error', got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock: %v", err)
+			}
+			defer db.Close()
+
+			repo := NewSessionRepository(sqlx.NewDb(db, "sqlmock"))
+
+			tc.testFunc(t, repo, mock)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
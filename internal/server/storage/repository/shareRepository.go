@@ -0,0 +1,256 @@
+// Package repository предоставляет доступ к секретам, переданным другим пользователям.
+package repository
+
+import (
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+// IShareRepository определяет интерфейс для репозитория доступов к секретам других пользователей.
+type IShareRepository interface {
+	GetPublicKey(ctx context.Context, login string) ([]byte, error)
+	CreateShare(ctx context.Context, share *models.Share) error
+	ListShares(ctx context.Context, ownerID, secretID uint64) ([]*models.Share, error)
+	ListSharedWithMe(ctx context.Context, recipientID uint64) (models.Secrets, error)
+	DeleteShare(ctx context.Context, userID, shareID uint64) error
+	UpdateSharedSecret(ctx context.Context, recipientID uint64, secret *models.Secret, versionsLimit int) error
+	GetSharedBlobOwner(ctx context.Context, recipientID, shareID uint64, blobID string) (uint64, error)
+}
+
+// ShareRepository обеспечивает методы для работы с доступами к секретам в базе данных.
+type ShareRepository struct {
+	db *sqlx.DB
+}
+
+// shareColumns - столбцы доступа вместе с логинами владельца и получателя из таблиц users o и r.
+const shareColumns = `sh.id, sh.secret_id, sh.owner_id, o.login AS owner_login, sh.recipient_id, r.login AS recipient_login,
+sh.data_key, sh.can_edit, sh.created_at`
+
+// sharedSecretRow - строка переданного секрета вместе с доступом, через который он передан.
+type sharedSecretRow struct {
+	models.Secret
+	ShareID       uint64    `db:"share_id"`
+	OwnerLogin    string    `db:"owner_login"`
+	RecipientID   uint64    `db:"recipient_id"`
+	RecipientName string    `db:"recipient_login"`
+	ShareDataKey  []byte    `db:"share_data_key"`
+	CanEdit       bool      `db:"can_edit"`
+	SharedAt      time.Time `db:"shared_at"`
+}
+
+// NewShareRepository создаёт новый экземпляр ShareRepository.
+// Принимает подключение к базе данных sqlx.DB и возвращает указатель на ShareRepository.
+func NewShareRepository(db *sqlx.DB) IShareRepository {
+	return &ShareRepository{
+		db: db,
+	}
+}
+
+// GetPublicKey возвращает открытый ключ пользователя с логином login.
+// Возвращает ErrNotFound, если пользователь не найден или ещё не создал пару ключей.
+func (r *ShareRepository) GetPublicKey(ctx context.Context, login string) ([]byte, error) {
+	var publicKey []byte
+	err := r.db.QueryRowxContext(ctx, "SELECT public_key FROM users WHERE login = $1 AND NOT is_vault", login).Scan(&publicKey)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if len(publicKey) == 0 {
+		return nil, fmt.Errorf("public key of user %q: %w", login, gophKeeperErrors.ErrNotFound)
+	}
+	return publicKey, nil
+}
+
+// CreateShare передаёт секрет владельца share.OwnerID пользователю с логином share.RecipientLogin.
+// Повторная передача тому же пользователю заменяет ключ данных и право изменения. Идентификаторы доступа
+// и получателя и время передачи записываются в share.
+// Возвращает ErrNotFound, если получатель или секрет вне корзины не найдены, и ErrInvalidShare
+// при передаче секрета самому себе.
+func (r *ShareRepository) CreateShare(ctx context.Context, share *models.Share) error {
+	return runAsUser(ctx, r.db, share.OwnerID, func(tx *sqlx.Tx) error {
+		err := tx.QueryRowxContext(ctx, "SELECT id FROM users WHERE login = $1 AND NOT is_vault", share.RecipientLogin).Scan(&share.RecipientID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("user %q: %w", share.RecipientLogin, gophKeeperErrors.ErrNotFound)
+			}
+			return err
+		}
+		if share.RecipientID == share.OwnerID {
+			return gophKeeperErrors.ErrInvalidShare
+		}
+
+		var secretID uint64
+		query := "SELECT id FROM secrets WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL"
+		if err = tx.QueryRowxContext(ctx, query, share.SecretID, share.OwnerID).Scan(&secretID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("secret with ID %d: %w", share.SecretID, gophKeeperErrors.ErrNotFound)
+			}
+			return err
+		}
+
+		query = `INSERT INTO shares (secret_id, owner_id, recipient_id, data_key, can_edit) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (secret_id, recipient_id) DO UPDATE SET data_key = EXCLUDED.data_key, can_edit = EXCLUDED.can_edit
+		RETURNING id, created_at`
+		return tx.QueryRowxContext(ctx, query, share.SecretID, share.OwnerID, share.RecipientID, share.DataKey, share.CanEdit).
+			Scan(&share.ID, &share.CreatedAt)
+	})
+}
+
+// ListShares возвращает доступы, выданные владельцем к секрету secretID, упорядоченные по логину получателя.
+// При нулевом secretID возвращаются доступы ко всем секретам владельца.
+func (r *ShareRepository) ListShares(ctx context.Context, ownerID, secretID uint64) ([]*models.Share, error) {
+	var shares []*models.Share
+
+	err := runAsUser(ctx, r.db, ownerID, func(tx *sqlx.Tx) error {
+		query := "SELECT " + shareColumns + ` FROM shares sh JOIN users o ON o.id = sh.owner_id JOIN users r ON r.id = sh.recipient_id
+		WHERE sh.owner_id = $1 AND ($2 = 0 OR sh.secret_id = $2) ORDER BY r.login, sh.secret_id`
+		return tx.SelectContext(ctx, &shares, query, ownerID, secretID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return shares, nil
+}
+
+// ListSharedWithMe возвращает секреты вне корзины, переданные пользователю, начиная с изменённых последними.
+// У каждого секрета заполнен доступ Share; ключ данных секрета, зашифрованный ключом хранилища владельца,
+// папка и метки владельца не возвращаются.
+func (r *ShareRepository) ListSharedWithMe(ctx context.Context, recipientID uint64) (models.Secrets, error) {
+	var rows []*sharedSecretRow
+
+	err := runAsUser(ctx, r.db, recipientID, func(tx *sqlx.Tx) error {
+		query := `SELECT s.id, s.user_id, s.title, s.metadata, s.labels_encrypted, s.secret_type, s.payload, s.created_at,
+		s.updated_at, s.revision, sh.id AS share_id, o.login AS owner_login, sh.recipient_id, r.login AS recipient_login,
+		sh.data_key AS share_data_key, sh.can_edit, sh.created_at AS shared_at
+		FROM shares sh JOIN secrets s ON s.id = sh.secret_id JOIN users o ON o.id = sh.owner_id JOIN users r ON r.id = sh.recipient_id
+		WHERE sh.recipient_id = $1 AND s.deleted_at IS NULL ORDER BY s.updated_at DESC, s.id`
+		return tx.SelectContext(ctx, &rows, query, recipientID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make(models.Secrets, 0, len(rows))
+	for _, row := range rows {
+		secret := row.Secret
+		secret.Share = &models.Share{
+			ID:             row.ShareID,
+			SecretID:       secret.ID,
+			OwnerID:        uint64(secret.UserID),
+			OwnerLogin:     row.OwnerLogin,
+			RecipientID:    row.RecipientID,
+			RecipientLogin: row.RecipientName,
+			DataKey:        row.ShareDataKey,
+			CanEdit:        row.CanEdit,
+			CreatedAt:      row.SharedAt,
+		}
+		secrets = append(secrets, &secret)
+	}
+
+	return secrets, nil
+}
+
+// DeleteShare удаляет доступ к секрету. Доступ может удалить как владелец секрета, так и получатель.
+// Возвращает ErrNotFound, если доступ не найден или пользователь в нём не участвует.
+func (r *ShareRepository) DeleteShare(ctx context.Context, userID, shareID uint64) error {
+	return runAsUser(ctx, r.db, userID, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, "DELETE FROM shares WHERE id = $1 AND (owner_id = $2 OR recipient_id = $2)", shareID, userID)
+		if err != nil {
+			return err
+		}
+		return expectAffected(result, "share", shareID)
+	})
+}
+
+// UpdateSharedSecret сохраняет изменения переданного секрета, сделанные получателем с правом изменения.
+// Изменяются только заголовок, метаданные и зашифрованные данные: ключ данных, тип, папка, метки
+// и токены слепого индекса остаются владельца. Изменение выполняется от имени владельца: прежнее состояние
+// сохраняется в его историю версий, а ревизия проверяется и увеличивается так же, как в SecretRepository.Update.
+// Идентификатор владельца записывается в secret.UserID.
+// Возвращает ErrNotFound, если секрет не передан получателю или находится в корзине, ErrShareReadOnly
+// без права изменения и RevisionConflictError, если секрет был изменён после загрузки.
+func (r *ShareRepository) UpdateSharedSecret(ctx context.Context, recipientID uint64, secret *models.Secret, versionsLimit int) error {
+	return runAsUser(ctx, r.db, recipientID, func(tx *sqlx.Tx) error {
+		var (
+			ownerID uint64
+			canEdit bool
+		)
+		query := "SELECT owner_id, can_edit FROM shares WHERE secret_id = $1 AND recipient_id = $2"
+		err := tx.QueryRowxContext(ctx, query, secret.ID, recipientID).Scan(&ownerID, &canEdit)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("shared secret with ID %d: %w", secret.ID, gophKeeperErrors.ErrNotFound)
+			}
+			return err
+		}
+		if !canEdit {
+			return gophKeeperErrors.ErrShareReadOnly
+		}
+
+		secret.UserID = int(ownerID)
+		if err = setTenant(ctx, tx, ownerID); err != nil {
+			return err
+		}
+
+		var revision uint64
+		query = "SELECT revision FROM secrets WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL FOR UPDATE"
+		if err = tx.QueryRowxContext(ctx, query, secret.ID, ownerID).Scan(&revision); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("shared secret with ID %d: %w", secret.ID, gophKeeperErrors.ErrNotFound)
+			}
+			return err
+		}
+		if revision != secret.Revision {
+			return &gophKeeperErrors.RevisionConflictError{Current: revision}
+		}
+
+		if err = archiveSecret(ctx, tx, secret.ID, ownerID); err != nil {
+			return err
+		}
+
+		query = `UPDATE secrets SET updated_at = $1, title = $2, metadata = $3, payload = $4, labels_encrypted = $5,
+		revision = revision + 1 WHERE id = $6 AND user_id = $7 RETURNING revision`
+		err = tx.QueryRowxContext(ctx, query,
+			secret.UpdatedAt,
+			secret.Title,
+			secret.Metadata,
+			secret.Payload,
+			secret.LabelsEncrypted,
+			secret.ID,
+			ownerID,
+		).Scan(&secret.Revision)
+		if err != nil {
+			return err
+		}
+
+		return pruneVersions(ctx, tx, ownerID, versionsLimit)
+	})
+}
+
+// GetSharedBlobOwner возвращает идентификатор владельца объекта blobID, если на объект ссылается секрет вне корзины,
+// переданный получателю recipientID доступом shareID. Через доступ объект только читается.
+// Возвращает ErrNotFound, если доступ не выдан получателю или переданный секрет не ссылается на объект.
+func (r *ShareRepository) GetSharedBlobOwner(ctx context.Context, recipientID, shareID uint64, blobID string) (uint64, error) {
+	var ownerID uint64
+
+	err := runAsUser(ctx, r.db, recipientID, func(tx *sqlx.Tx) error {
+		query := `SELECT sh.owner_id FROM shares sh JOIN secrets s ON s.id = sh.secret_id
+		WHERE sh.id = $1 AND sh.recipient_id = $2 AND s.blob_id = $3 AND s.deleted_at IS NULL`
+		err := tx.QueryRowxContext(ctx, query, shareID, recipientID, blobID).Scan(&ownerID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("blob %s shared by share %d: %w", blobID, shareID, gophKeeperErrors.ErrNotFound)
+		}
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return ownerID, nil
+}
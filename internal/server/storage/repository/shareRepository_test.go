@@ -0,0 +1,311 @@
+package repository
+
+import (
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"beliaev-aa/GophKeeper/pkg/models"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"testing"
+	"time"
+)
+
+func TestShareRepository(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		testFunc func(t *testing.T, repo IShareRepository, mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "GetPublicKey_Success",
+			testFunc: func(t *testing.T, repo IShareRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT public_key FROM users WHERE login = \$1 AND NOT is_vault`).
+					WithArgs("bob").
+					WillReturnRows(sqlmock.NewRows([]string{"public_key"}).AddRow([]byte("public")))
+
+				publicKey, err := repo.GetPublicKey(ctx, "bob")
+				if err != nil || string(publicKey) != "public" {
+					t.Errorf("Expected public key, got %q, err = %v", publicKey, err)
+				}
+			},
+		},
+		{
+			name: "GetPublicKey_Fail_NoKeyPair",
+			testFunc: func(t *testing.T, repo IShareRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT public_key FROM users WHERE login = \$1 AND NOT is_vault`).
+					WithArgs("bob").
+					WillReturnRows(sqlmock.NewRows([]string{"public_key"}).AddRow(nil))
+
+				_, err := repo.GetPublicKey(ctx, "bob")
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+		{
+			name: "CreateShare_Success",
+			testFunc: func(t *testing.T, repo IShareRepository, mock sqlmock.Sqlmock) {
+				createdAt := time.Now()
+
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT id FROM users WHERE login = \$1 AND NOT is_vault`).
+					WithArgs("bob").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery(`SELECT id FROM secrets WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NULL`).
+					WithArgs(10, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+				mock.ExpectQuery(`INSERT INTO shares \(secret_id, owner_id, recipient_id, data_key, can_edit\) VALUES \(\$1, \$2, \$3, \$4, \$5\)\s+ON CONFLICT \(secret_id, recipient_id\) DO UPDATE SET data_key = EXCLUDED.data_key, can_edit = EXCLUDED.can_edit\s+RETURNING id, created_at`).
+					WithArgs(10, 1, 2, []byte("data_key"), true).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, createdAt))
+				mock.ExpectCommit()
+
+				share := &models.Share{SecretID: 10, OwnerID: 1, RecipientLogin: "bob", DataKey: []byte("data_key"), CanEdit: true}
+				if err := repo.CreateShare(ctx, share); err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if share.ID != 5 || share.RecipientID != 2 || !share.CreatedAt.Equal(createdAt) {
+					t.Errorf("Expected share 5 for recipient 2, got %+v", share)
+				}
+			},
+		},
+		{
+			name: "CreateShare_Fail_RecipientNotFound",
+			testFunc: func(t *testing.T, repo IShareRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT id FROM users WHERE login = \$1 AND NOT is_vault`).
+					WithArgs("bob").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+
+				err := repo.CreateShare(ctx, &models.Share{SecretID: 10, OwnerID: 1, RecipientLogin: "bob"})
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+		{
+			name: "CreateShare_Fail_Self",
+			testFunc: func(t *testing.T, repo IShareRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT id FROM users WHERE login = \$1 AND NOT is_vault`).
+					WithArgs("alice").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectRollback()
+
+				err := repo.CreateShare(ctx, &models.Share{SecretID: 10, OwnerID: 1, RecipientLogin: "alice"})
+				if !errors.Is(err, gophKeeperErrors.ErrInvalidShare) {
+					t.Errorf("Expected error 'ErrInvalidShare', got %v", err)
+				}
+			},
+		},
+		{
+			name: "CreateShare_Fail_SecretNotFound",
+			testFunc: func(t *testing.T, repo IShareRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT id FROM users WHERE login = \$1 AND NOT is_vault`).
+					WithArgs("bob").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery(`SELECT id FROM secrets WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NULL`).
+					WithArgs(10, 1).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+
+				err := repo.CreateShare(ctx, &models.Share{SecretID: 10, OwnerID: 1, RecipientLogin: "bob"})
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+		{
+			name: "ListShares_Success",
+			testFunc: func(t *testing.T, repo IShareRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT sh.id, (.+) FROM shares sh JOIN users o ON o.id = sh.owner_id JOIN users r ON r.id = sh.recipient_id\s+WHERE sh.owner_id = \$1 AND \(\$2 = 0 OR sh.secret_id = \$2\) ORDER BY r.login, sh.secret_id`).
+					WithArgs(1, 10).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "secret_id", "owner_id", "owner_login", "recipient_id", "recipient_login", "data_key", "can_edit", "created_at"}).
+						AddRow(5, 10, 1, "alice", 2, "bob", []byte("data_key"), false, time.Now()))
+				mock.ExpectCommit()
+
+				shares, err := repo.ListShares(ctx, 1, 10)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if len(shares) != 1 || shares[0].RecipientLogin != "bob" || shares[0].OwnerLogin != "alice" {
+					t.Errorf("Expected share with bob, got %+v", shares)
+				}
+			},
+		},
+		{
+			name: "ListSharedWithMe_Success",
+			testFunc: func(t *testing.T, repo IShareRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "2")
+				mock.ExpectQuery(`SELECT s.id, (.+) FROM shares sh JOIN secrets s ON s.id = sh.secret_id (.+) WHERE sh.recipient_id = \$1 AND s.deleted_at IS NULL ORDER BY s.updated_at DESC, s.id`).
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "metadata", "labels_encrypted", "secret_type", "payload",
+						"created_at", "updated_at", "revision", "share_id", "owner_login", "recipient_id", "recipient_login",
+						"share_data_key", "can_edit", "shared_at"}).
+						AddRow(10, 1, "title", "metadata", true, "credential", []byte("payload"),
+							time.Now(), time.Now(), 3, 5, "alice", 2, "bob", []byte("wrapped"), true, time.Now()))
+				mock.ExpectCommit()
+
+				secrets, err := repo.ListSharedWithMe(ctx, 2)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if len(secrets) != 1 {
+					t.Fatalf("Expected 1 secret, got %d", len(secrets))
+				}
+				share := secrets[0].Share
+				if share == nil || share.ID != 5 || share.OwnerID != 1 || share.OwnerLogin != "alice" || share.RecipientLogin != "bob" ||
+					string(share.DataKey) != "wrapped" || !share.CanEdit || share.SecretID != 10 {
+					t.Errorf("Unexpected share %+v", share)
+				}
+				if secrets[0].DataKey != nil || secrets[0].Revision != 3 {
+					t.Errorf("Unexpected secret %+v", secrets[0])
+				}
+			},
+		},
+		{
+			name: "DeleteShare_Fail_NotFound",
+			testFunc: func(t *testing.T, repo IShareRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "2")
+				mock.ExpectExec(`DELETE FROM shares WHERE id = \$1 AND \(owner_id = \$2 OR recipient_id = \$2\)`).
+					WithArgs(5, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+
+				err := repo.DeleteShare(ctx, 2, 5)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+		{
+			name: "UpdateSharedSecret_Success",
+			testFunc: func(t *testing.T, repo IShareRepository, mock sqlmock.Sqlmock) {
+				updatedAt := time.Now()
+
+				mock.ExpectBegin()
+				expectTenant(mock, "2")
+				mock.ExpectQuery(`SELECT owner_id, can_edit FROM shares WHERE secret_id = \$1 AND recipient_id = \$2`).
+					WithArgs(10, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"owner_id", "can_edit"}).AddRow(1, true))
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT revision FROM secrets WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NULL FOR UPDATE`).
+					WithArgs(10, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(3))
+				expectArchive(mock, 10, 1)
+				mock.ExpectQuery(`UPDATE secrets SET updated_at = \$1, title = \$2, metadata = \$3, payload = \$4, labels_encrypted = \$5,\s+revision = revision \+ 1 WHERE id = \$6 AND user_id = \$7 RETURNING revision`).
+					WithArgs(updatedAt, "title", "metadata", []byte("payload"), true, 10, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(4))
+				expectPrune(mock, 1, 10)
+				mock.ExpectCommit()
+
+				secret := &models.Secret{ID: 10, Title: "title", Metadata: "metadata", Payload: []byte("payload"), LabelsEncrypted: true, UpdatedAt: updatedAt, Revision: 3}
+				if err := repo.UpdateSharedSecret(ctx, 2, secret, 10); err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if secret.Revision != 4 || secret.UserID != 1 {
+					t.Errorf("Expected revision 4 of owner 1, got %d of %d", secret.Revision, secret.UserID)
+				}
+			},
+		},
+		{
+			name: "UpdateSharedSecret_Fail_ReadOnly",
+			testFunc: func(t *testing.T, repo IShareRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "2")
+				mock.ExpectQuery(`SELECT owner_id, can_edit FROM shares WHERE secret_id = \$1 AND recipient_id = \$2`).
+					WithArgs(10, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"owner_id", "can_edit"}).AddRow(1, false))
+				mock.ExpectRollback()
+
+				err := repo.UpdateSharedSecret(ctx, 2, &models.Secret{ID: 10, Revision: 3}, 10)
+				if !errors.Is(err, gophKeeperErrors.ErrShareReadOnly) {
+					t.Errorf("Expected error 'ErrShareReadOnly', got %v", err)
+				}
+			},
+		},
+		{
+			name: "UpdateSharedSecret_Fail_Conflict",
+			testFunc: func(t *testing.T, repo IShareRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "2")
+				mock.ExpectQuery(`SELECT owner_id, can_edit FROM shares WHERE secret_id = \$1 AND recipient_id = \$2`).
+					WithArgs(10, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"owner_id", "can_edit"}).AddRow(1, true))
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT revision FROM secrets WHERE id = \$1 AND user_id = \$2 AND deleted_at IS NULL FOR UPDATE`).
+					WithArgs(10, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(5))
+				mock.ExpectRollback()
+
+				err := repo.UpdateSharedSecret(ctx, 2, &models.Secret{ID: 10, Revision: 3}, 10)
+				var conflict *gophKeeperErrors.RevisionConflictError
+				if !errors.As(err, &conflict) || conflict.Current != 5 {
+					t.Errorf("Expected revision conflict at 5, got %v", err)
+				}
+			},
+		},
+		{
+			name: "GetSharedBlobOwner_Success",
+			testFunc: func(t *testing.T, repo IShareRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "2")
+				mock.ExpectQuery(`SELECT sh.owner_id FROM shares sh JOIN secrets s ON s.id = sh.secret_id\s+WHERE sh.id = \$1 AND sh.recipient_id = \$2 AND s.blob_id = \$3 AND s.deleted_at IS NULL`).
+					WithArgs(5, 2, "blob").
+					WillReturnRows(sqlmock.NewRows([]string{"owner_id"}).AddRow(1))
+				mock.ExpectCommit()
+
+				ownerID, err := repo.GetSharedBlobOwner(ctx, 2, 5, "blob")
+				if err != nil || ownerID != 1 {
+					t.Errorf("Expected owner 1, got %d, err = %v", ownerID, err)
+				}
+			},
+		},
+		{
+			name: "GetSharedBlobOwner_Fail_NotShared",
+			testFunc: func(t *testing.T, repo IShareRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "2")
+				mock.ExpectQuery(`SELECT sh.owner_id FROM shares sh`).
+					WithArgs(5, 2, "other").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+
+				_, err := repo.GetSharedBlobOwner(ctx, 2, 5, "other")
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock: %v", err)
+			}
+			defer db.Close()
+
+			repo := NewShareRepository(sqlx.NewDb(db, "sqlmock"))
+
+			tc.testFunc(t, repo, mock)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Package repository предоставляет доступ к настройкам второго фактора и кодам восстановления.
+package repository
+
+import (
+	"beliaev-aa/GophKeeper/internal/server/models"
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+)
+
+// ITOTPRepository определяет интерфейс для репозитория настроек двухфакторной аутентификации.
+type ITOTPRepository interface {
+	Get(ctx context.Context, userID int) (*models.TOTP, error)
+	SetPending(ctx context.Context, userID int, secret []byte) error
+	Enable(ctx context.Context, userID int, step int64, recoveryCodeHashes [][]byte) error
+	Disable(ctx context.Context, userID int) error
+	UpdateStep(ctx context.Context, userID int, step int64) error
+	UseRecoveryCode(ctx context.Context, userID int, codeHash []byte) error
+}
+
+// TOTPRepository предоставляет методы для работы с секретами TOTP и кодами восстановления в базе данных.
+type TOTPRepository struct {
+	db *sqlx.DB
+}
+
+// NewTOTPRepository создаёт новый экземпляр TOTPRepository.
+// Функция принимает подключение к базе данных SQLX и возвращает указатель на TOTPRepository.
+func NewTOTPRepository(db *sqlx.DB) ITOTPRepository {
+	return &TOTPRepository{
+		db: db,
+	}
+}
+
+// Get возвращает настройки TOTP пользователя.
+// Возвращает ErrNotFound, если пользователь никогда не включал второй фактор.
+func (r *TOTPRepository) Get(ctx context.Context, userID int) (*models.TOTP, error) {
+	var totp models.TOTP
+	err := r.db.QueryRowxContext(ctx,
+		"SELECT user_id, secret, pending_secret, enabled, last_step FROM user_totp WHERE user_id = $1",
+		userID,
+	).StructScan(&totp)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, gophKeeperErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &totp, nil
+}
+
+// SetPending сохраняет новый секрет TOTP, ожидающий подтверждения.
+// Действующий секрет при этом не изменяется.
+func (r *TOTPRepository) SetPending(ctx context.Context, userID int, secret []byte) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO user_totp (user_id, pending_secret) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET pending_secret = EXCLUDED.pending_secret`,
+		userID,
+		secret,
+	)
+	return err
+}
+
+// Enable делает ожидающий подтверждения секрет действующим и заменяет коды восстановления пользователя.
+// Принимает шаг TOTP, которым подтверждён секрет, чтобы этот код нельзя было повторно использовать при входе.
+// Возвращает ErrNotFound, если у пользователя нет ожидающего подтверждения секрета.
+func (r *TOTPRepository) Enable(ctx context.Context, userID int, step int64, recoveryCodeHashes [][]byte) error {
+	return runInTx(r.db, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx,
+			`UPDATE user_totp SET secret = pending_secret, pending_secret = NULL, enabled = true, last_step = $1
+			WHERE user_id = $2 AND pending_secret IS NOT NULL`,
+			step,
+			userID,
+		)
+		if err != nil {
+			return err
+		}
+		if err = requireAffected(result); err != nil {
+			return err
+		}
+
+		if _, err = tx.ExecContext(ctx, "DELETE FROM recovery_codes WHERE user_id = $1", userID); err != nil {
+			return err
+		}
+		for _, codeHash := range recoveryCodeHashes {
+			_, err = tx.ExecContext(ctx,
+				"INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)",
+				userID,
+				codeHash,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Disable удаляет секрет TOTP и коды восстановления пользователя.
+func (r *TOTPRepository) Disable(ctx context.Context, userID int) error {
+	return runInTx(r.db, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM recovery_codes WHERE user_id = $1", userID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "DELETE FROM user_totp WHERE user_id = $1", userID)
+		return err
+	})
+}
+
+// UpdateStep запоминает использованный шаг TOTP.
+// Шаг сохраняется только если он новее последнего использованного, поэтому из двух
+// одновременных входов с одним кодом успешным будет только один.
+// Возвращает ErrNotFound, если шаг уже был использован.
+func (r *TOTPRepository) UpdateStep(ctx context.Context, userID int, step int64) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE user_totp SET last_step = $1 WHERE user_id = $2 AND last_step < $1",
+		step,
+		userID,
+	)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// UseRecoveryCode помечает код восстановления пользователя использованным.
+// Возвращает ErrNotFound, если код не найден или уже использован.
+func (r *TOTPRepository) UseRecoveryCode(ctx context.Context, userID int, codeHash []byte) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE recovery_codes SET used_at = now() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL",
+		userID,
+		codeHash,
+	)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"testing"
+)
+
+func TestTOTPRepository(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		testFunc func(t *testing.T, repo ITOTPRepository, mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "Get_Success",
+			testFunc: func(t *testing.T, repo ITOTPRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT user_id, secret, pending_secret, enabled, last_step FROM user_totp WHERE user_id = \$1`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "secret", "pending_secret", "enabled", "last_step"}).
+						AddRow(1, []byte("secret"), nil, true, 100))
+
+				totp, err := repo.Get(ctx, 1)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if !totp.Enabled || string(totp.Secret) != "secret" || totp.LastStep != 100 {
+					t.Errorf("Unexpected TOTP settings %+v", totp)
+				}
+			},
+		},
+		{
+			name: "Get_NotFound",
+			testFunc: func(t *testing.T, repo ITOTPRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT .* FROM user_totp`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "secret", "pending_secret", "enabled", "last_step"}))
+
+				_, err := repo.Get(ctx, 1)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected ErrNotFound, got %v", err)
+				}
+			},
+		},
+		{
+			name: "SetPending_Success",
+			testFunc: func(t *testing.T, repo ITOTPRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`INSERT INTO user_totp \(user_id, pending_secret\) VALUES \(\$1, \$2\)\s+ON CONFLICT \(user_id\) DO UPDATE SET pending_secret = EXCLUDED.pending_secret`).
+					WithArgs(1, []byte("pending")).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				if err := repo.SetPending(ctx, 1, []byte("pending")); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Enable_Success",
+			testFunc: func(t *testing.T, repo ITOTPRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE user_totp SET secret = pending_secret, pending_secret = NULL, enabled = true, last_step = \$1\s+WHERE user_id = \$2 AND pending_secret IS NOT NULL`).
+					WithArgs(100, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM recovery_codes WHERE user_id = \$1`).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 10))
+				mock.ExpectExec(`INSERT INTO recovery_codes \(user_id, code_hash\) VALUES \(\$1, \$2\)`).
+					WithArgs(1, []byte("first")).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`INSERT INTO recovery_codes`).
+					WithArgs(1, []byte("second")).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectCommit()
+
+				if err := repo.Enable(ctx, 1, 100, [][]byte{[]byte("first"), []byte("second")}); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Enable_NotPending",
+			testFunc: func(t *testing.T, repo ITOTPRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE user_totp SET secret = pending_secret`).
+					WithArgs(100, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+
+				err := repo.Enable(ctx, 1, 100, [][]byte{[]byte("first")})
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected ErrNotFound, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Disable_Success",
+			testFunc: func(t *testing.T, repo ITOTPRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`DELETE FROM recovery_codes WHERE user_id = \$1`).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 10))
+				mock.ExpectExec(`DELETE FROM user_totp WHERE user_id = \$1`).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				if err := repo.Disable(ctx, 1); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Disable_DatabaseError",
+			testFunc: func(t *testing.T, repo ITOTPRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`DELETE FROM recovery_codes`).
+					WithArgs(1).
+					WillReturnError(fmt.Errorf("database error"))
+				mock.ExpectRollback()
+
+				err := repo.Disable(ctx, 1)
+				if err == nil || err.Error() != "database error" {
+					t.Errorf("Expected error 'database error', got %v", err)
+				}
+			},
+		},
+		{
+			name: "UpdateStep_Success",
+			testFunc: func(t *testing.T, repo ITOTPRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE user_totp SET last_step = \$1 WHERE user_id = \$2 AND last_step < \$1`).
+					WithArgs(101, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				if err := repo.UpdateStep(ctx, 1, 101); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "UpdateStep_Replayed",
+			testFunc: func(t *testing.T, repo ITOTPRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE user_totp SET last_step`).
+					WithArgs(100, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				err := repo.UpdateStep(ctx, 1, 100)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected ErrNotFound, got %v", err)
+				}
+			},
+		},
+		{
+			name: "UseRecoveryCode_Success",
+			testFunc: func(t *testing.T, repo ITOTPRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE recovery_codes SET used_at = now\(\) WHERE user_id = \$1 AND code_hash = \$2 AND used_at IS NULL`).
+					WithArgs(1, []byte("hash")).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				if err := repo.UseRecoveryCode(ctx, 1, []byte("hash")); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "UseRecoveryCode_AlreadyUsed",
+			testFunc: func(t *testing.T, repo ITOTPRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE recovery_codes SET used_at`).
+					WithArgs(1, []byte("hash")).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				err := repo.UseRecoveryCode(ctx, 1, []byte("hash"))
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected ErrNotFound, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock: %v", err)
+			}
+			defer db.Close()
+
+			repo := NewTOTPRepository(sqlx.NewDb(db, "sqlmock"))
+
+			tc.testFunc(t, repo, mock)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
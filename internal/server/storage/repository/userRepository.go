@@ -5,18 +5,40 @@ package repository
 import (
 	"beliaev-aa/GophKeeper/internal/server/models"
 	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	pkgModels "beliaev-aa/GophKeeper/pkg/models"
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/jmoiron/sqlx"
+	"strings"
 )
 
+// ErrIncompleteRekey возникает, если при перешифровании хранилища переданы не все секреты пользователя.
+var ErrIncompleteRekey = errors.New("not all user secrets were re-encrypted")
+
+// ErrVaultKeyExists возникает при создании ключа хранилища пользователя, у которого он уже есть.
+var ErrVaultKeyExists = errors.New("vault key already exists")
+
+// ErrVaultKeyRequired возникает при перешифровании хранилища без ключа хранилища пользователем, у которого он уже есть.
+var ErrVaultKeyRequired = errors.New("vault key is required")
+
+// ErrKeyPairExists возникает при создании пары ключей пользователя, у которого она уже есть.
+var ErrKeyPairExists = errors.New("key pair already exists")
+
 // IUserRepository определяет интерфейс для репозитория пользователя,
 // предоставляющего методы для работы с пользователями в базе данных.
 type IUserRepository interface {
 	Create(ctx context.Context, user models.User) (int, error)
 	GetUserByID(ctx context.Context, ID int) (*models.User, error)
 	GetUserByLogin(ctx context.Context, login string) (*models.User, error)
+	UpdateCredentials(ctx context.Context, userID int, password string, authVersion int) error
+	Rekey(ctx context.Context, userID int, password string, kdf *pkgModels.KDFParams, vaultKey []byte, secrets map[uint64]*pkgModels.Secret) error
+	GetVaultKey(ctx context.Context, userID int) ([]byte, error)
+	CreateVaultKey(ctx context.Context, userID int, vaultKey []byte, secrets map[uint64]*pkgModels.Secret) error
+	GetKeyPair(ctx context.Context, userID int) (*pkgModels.KeyPair, error)
+	CreateKeyPair(ctx context.Context, userID int, keyPair *pkgModels.KeyPair) error
+	Delete(ctx context.Context, userID int) ([]uint64, error)
 }
 
 // UserRepository предоставляет методы для работы с пользователями в базе данных.
@@ -38,9 +60,11 @@ func NewUserRepository(db *sqlx.DB) IUserRepository {
 func (r *UserRepository) Create(ctx context.Context, user models.User) (int, error) {
 	var newUserID int
 	result := r.db.QueryRowContext(ctx,
-		"INSERT INTO users (login, password) VALUES ($1, $2) RETURNING id",
+		"INSERT INTO users (login, password, auth_version, kdf) VALUES ($1, $2, $3, $4) RETURNING id",
 		user.Login,
 		user.Password,
+		user.AuthVersion,
+		user.KDF,
 	)
 	err := result.Scan(&newUserID)
 	if err != nil {
@@ -54,21 +78,263 @@ func (r *UserRepository) Create(ctx context.Context, user models.User) (int, err
 // В случае успеха возвращает объект пользователя или ошибку.
 func (r *UserRepository) GetUserByID(ctx context.Context, ID int) (*models.User, error) {
 	var user models.User
-	err := r.db.QueryRowxContext(ctx, "SELECT id, login, created_at, password FROM users WHERE id = $1", ID).StructScan(&user)
+	err := r.db.QueryRowxContext(ctx, "SELECT id, login, created_at, password, auth_version, kdf FROM users WHERE id = $1", ID).StructScan(&user)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, gophKeeperErrors.ErrNotFound
 	}
 	return &user, err
 }
 
-// GetUserByLogin возвращает пользователя по его логину.
+// GetUserByLogin возвращает пользователя по его логину. Служебные учётные записи хранилищ организаций
+// не находятся: под ними нельзя войти, и их нельзя выбрать участником, получателем или доверенным пользователем.
 // Принимает контекст выполнения и логин пользователя.
 // В случае успеха возвращает объект пользователя или ошибку.
 func (r *UserRepository) GetUserByLogin(ctx context.Context, login string) (*models.User, error) {
 	var user models.User
-	err := r.db.QueryRowxContext(ctx, "SELECT id, login, created_at, password FROM users WHERE login = $1", login).StructScan(&user)
+	err := r.db.QueryRowxContext(ctx, "SELECT id, login, created_at, password, auth_version, kdf FROM users WHERE login = $1 AND NOT is_vault", login).StructScan(&user)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, gophKeeperErrors.ErrNotFound
 	}
 	return &user, err
 }
+
+// UpdateCredentials заменяет хэш учётных данных пользователя и схему их хранения.
+// Принимает контекст выполнения, идентификатор пользователя, новый хэш и версию схемы.
+// Возвращает ErrNotFound, если пользователь не найден.
+func (r *UserRepository) UpdateCredentials(ctx context.Context, userID int, password string, authVersion int) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE users SET password = $1, auth_version = $2 WHERE id = $3",
+		password,
+		authVersion,
+		userID,
+	)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// Rekey атомарно заменяет учётные данные и параметры KDF пользователя вместе с ключом хранилища,
+// зашифрованным новым ключом, и перешифрованными секретами. Принимает контекст, идентификатор пользователя,
+// хэш учётных данных, новые параметры KDF, ключ хранилища и перешифрованные секреты по их идентификаторам.
+// Если ключ хранилища передан, секреты с ключом данных не меняются, и достаточно передать секреты
+// без него; иначе ключ хранилища удаляется, и передаются все секреты пользователя. Заголовки
+// и метаданные переданных секретов после этого считаются зашифрованными. Версии секретов без ключа
+// данных удаляются, так как прежний ключ после замены недоступен клиенту. Без ключа хранилища удаляются
+// также пара ключей пользователя, доступы к секретам и экстренные доступы, в которых он участвует: закрытый
+// ключ зашифрован удаляемым ключом хранилища, а переданные ключи больше не соответствуют секретам.
+// Перешифрование без ключа хранилища допускается только для пользователя, у которого его ещё нет.
+// Возвращает ErrVaultKeyRequired, если ключ хранилища не передан, но уже сохранён, ErrIncompleteRekey,
+// если переданы не все требуемые секреты, и ErrNotFound, если секрет или пользователь не найдены.
+// При любой ошибке изменения не применяются.
+func (r *UserRepository) Rekey(ctx context.Context, userID int, password string, kdf *pkgModels.KDFParams, vaultKey []byte, secrets map[uint64]*pkgModels.Secret) error {
+	return runAsUser(ctx, r.db, uint64(userID), func(tx *sqlx.Tx) error {
+		var hasVaultKey bool
+		err := tx.QueryRowxContext(ctx, "SELECT vault_key IS NOT NULL FROM users WHERE id = $1 FOR UPDATE", userID).Scan(&hasVaultKey)
+		if errors.Is(err, sql.ErrNoRows) {
+			return gophKeeperErrors.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		all := len(vaultKey) == 0
+		if all && hasVaultKey {
+			return ErrVaultKeyRequired
+		}
+		if err = rekeySecrets(ctx, tx, userID, all, secrets); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM secret_versions WHERE user_id = $1 AND ($2 OR data_key IS NULL)", userID, all)
+		if err != nil {
+			return err
+		}
+
+		if all {
+			if _, err = tx.ExecContext(ctx, "DELETE FROM shares WHERE owner_id = $1 OR recipient_id = $1", userID); err != nil {
+				return err
+			}
+			if _, err = tx.ExecContext(ctx, "DELETE FROM emergency_access WHERE grantor_id = $1 OR grantee_id = $1", userID); err != nil {
+				return err
+			}
+			if _, err = tx.ExecContext(ctx, "UPDATE users SET public_key = NULL, private_key = NULL WHERE id = $1", userID); err != nil {
+				return err
+			}
+		}
+
+		result, err := tx.ExecContext(ctx,
+			"UPDATE users SET password = $1, auth_version = $2, kdf = $3, vault_key = $4 WHERE id = $5",
+			password,
+			models.AuthVersionHash,
+			kdf,
+			vaultKey,
+			userID,
+		)
+		if err != nil {
+			return err
+		}
+		return requireAffected(result)
+	})
+}
+
+// GetVaultKey возвращает зашифрованный ключ хранилища пользователя или nil, если он ещё не создан.
+// Возвращает ErrNotFound, если пользователь не найден.
+func (r *UserRepository) GetVaultKey(ctx context.Context, userID int) ([]byte, error) {
+	var vaultKey []byte
+	err := r.db.QueryRowxContext(ctx, "SELECT vault_key FROM users WHERE id = $1", userID).Scan(&vaultKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, gophKeeperErrors.ErrNotFound
+	}
+	return vaultKey, err
+}
+
+// CreateVaultKey атомарно сохраняет зашифрованный ключ хранилища пользователя вместе с секретами,
+// перешифрованными собственными ключами данных. Должны быть переданы все секреты пользователя без ключа данных.
+// Версии секретов без ключа данных удаляются: клиент не принимает их после создания ключа хранилища.
+// Возвращает ErrVaultKeyExists, если ключ хранилища уже создан или пользователь не найден,
+// и ErrIncompleteRekey, если переданы не все секреты без ключа данных. При любой ошибке изменения не применяются.
+func (r *UserRepository) CreateVaultKey(ctx context.Context, userID int, vaultKey []byte, secrets map[uint64]*pkgModels.Secret) error {
+	return runAsUser(ctx, r.db, uint64(userID), func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, "UPDATE users SET vault_key = $1 WHERE id = $2 AND vault_key IS NULL", vaultKey, userID)
+		if err != nil {
+			return err
+		}
+		if err = requireAffected(result); err != nil {
+			if errors.Is(err, gophKeeperErrors.ErrNotFound) {
+				return ErrVaultKeyExists
+			}
+			return err
+		}
+
+		if err = rekeySecrets(ctx, tx, userID, false, secrets); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM secret_versions WHERE user_id = $1 AND data_key IS NULL", userID)
+		return err
+	})
+}
+
+// GetKeyPair возвращает пару ключей пользователя; ключи пустые, если пара ещё не создана.
+// Возвращает ErrNotFound, если пользователь не найден.
+func (r *UserRepository) GetKeyPair(ctx context.Context, userID int) (*pkgModels.KeyPair, error) {
+	var keyPair pkgModels.KeyPair
+	err := r.db.GetContext(ctx, &keyPair, "SELECT public_key, private_key FROM users WHERE id = $1", userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, gophKeeperErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &keyPair, nil
+}
+
+// CreateKeyPair сохраняет пару ключей пользователя. Возвращает ErrKeyPairExists, если пара ключей
+// уже создана или пользователь не найден.
+func (r *UserRepository) CreateKeyPair(ctx context.Context, userID int, keyPair *pkgModels.KeyPair) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE users SET public_key = $1, private_key = $2 WHERE id = $3 AND public_key IS NULL",
+		keyPair.PublicKey,
+		keyPair.PrivateKey,
+		userID,
+	)
+	if err != nil {
+		return err
+	}
+	if err = requireAffected(result); errors.Is(err, gophKeeperErrors.ErrNotFound) {
+		return ErrKeyPairExists
+	}
+	return err
+}
+
+// rekeySecrets заменяет зашифрованные данные, заголовки, метаданные, токены слепого индекса и ключи данных
+// секретов пользователя перешифрованными. Если all = true, должны быть переданы все секреты пользователя,
+// иначе - все секреты без ключа данных. Возвращает ErrIncompleteRekey, если переданы не все такие секреты,
+// и ErrNotFound, если переданный секрет не найден.
+func rekeySecrets(ctx context.Context, tx *sqlx.Tx, userID int, all bool, secrets map[uint64]*pkgModels.Secret) error {
+	var required []uint64
+	err := tx.SelectContext(ctx, &required, "SELECT id FROM secrets WHERE user_id = $1 AND ($2 OR data_key IS NULL)", userID, all)
+	if err != nil {
+		return err
+	}
+	for _, id := range required {
+		if _, ok := secrets[id]; !ok {
+			return ErrIncompleteRekey
+		}
+	}
+
+	for secretID, secret := range secrets {
+		result, err := tx.ExecContext(ctx,
+			`UPDATE secrets SET payload = $1, title = $2, metadata = $3, labels_encrypted = true, search_tokens = `+tokensArray("$4")+`,
+			data_key = $5, updated_at = now(), revision = revision + 1 WHERE id = $6 AND user_id = $7`,
+			secret.Payload,
+			secret.Title,
+			secret.Metadata,
+			strings.Join(secret.SearchTokens, " "),
+			secret.DataKey,
+			secretID,
+			userID,
+		)
+		if err != nil {
+			return err
+		}
+		if err = requireAffected(result); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete удаляет пользователя. Секреты, сессии, настройки второго фактора и журнал попыток входа
+// пользователя удаляются каскадно в той же транзакции, что и сама учётная запись. Организации,
+// единственным участником которых был пользователь, удаляются вместе с хранилищами.
+// Возвращает идентификаторы удалённых хранилищ организаций, ErrLastOwner, если пользователь - единственный
+// владелец организации, в которой остаются другие участники, и ErrNotFound, если пользователь не найден.
+func (r *UserRepository) Delete(ctx context.Context, userID int) ([]uint64, error) {
+	var vaultIDs []uint64
+	err := runInTx(r.db, func(tx *sqlx.Tx) error {
+		var orgName string
+		query := `SELECT o.name FROM organizations o
+		JOIN org_members m ON m.org_id = o.id AND m.user_id = $1 AND m.role = 'owner'
+		WHERE NOT EXISTS (SELECT 1 FROM org_members x WHERE x.org_id = o.id AND x.role = 'owner' AND x.user_id <> $1)
+		AND EXISTS (SELECT 1 FROM org_members x WHERE x.org_id = o.id AND x.user_id <> $1) LIMIT 1`
+		err := tx.QueryRowxContext(ctx, query, userID).Scan(&orgName)
+		if err == nil {
+			return fmt.Errorf("organization %s: %w", orgName, gophKeeperErrors.ErrLastOwner)
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		query = `DELETE FROM users WHERE id IN (SELECT o.vault_id FROM organizations o
+		JOIN org_members m ON m.org_id = o.id AND m.user_id = $1
+		WHERE NOT EXISTS (SELECT 1 FROM org_members x WHERE x.org_id = o.id AND x.user_id <> $1)) RETURNING id`
+		if err = tx.SelectContext(ctx, &vaultIDs, query, userID); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, "DELETE FROM users WHERE id = $1", userID)
+		if err != nil {
+			return err
+		}
+		return requireAffected(result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vaultIDs, nil
+}
+
+// requireAffected возвращает ErrNotFound, если запрос не изменил ни одной строки.
+func requireAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return gophKeeperErrors.ErrNotFound
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"beliaev-aa/GophKeeper/internal/server/models"
 	gophKeeperErrors "beliaev-aa/GophKeeper/pkg/errors"
+	pkgModels "beliaev-aa/GophKeeper/pkg/models"
 	"context"
 	"database/sql"
 	"errors"
@@ -15,6 +16,9 @@ import (
 
 func TestUserRepository(t *testing.T) {
 	ctx := context.Background()
+	rekeyedSecrets := map[uint64]*pkgModels.Secret{
+		10: {ID: 10, Payload: []byte("new_payload"), Title: "new_title", Metadata: "new_metadata", SearchTokens: []string{"token1", "token2"}, DataKey: []byte("data_key")},
+	}
 	tests := []struct {
 		name      string
 		testFunc  func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock)
@@ -23,13 +27,14 @@ func TestUserRepository(t *testing.T) {
 		{
 			name: "Create_Success",
 			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(`INSERT INTO users \(login, password\) VALUES \(\$1, \$2\) RETURNING id`).
-					WithArgs("new_user", "hashed_password").
+				mock.ExpectQuery(`INSERT INTO users \(login, password, auth_version, kdf\) VALUES \(\$1, \$2, \$3, \$4\) RETURNING id`).
+					WithArgs("new_user", "hashed_password", 1, nil).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 				user := models.User{
-					Login:    "new_user",
-					Password: "hashed_password",
+					Login:       "new_user",
+					Password:    "hashed_password",
+					AuthVersion: models.AuthVersionHash,
 				}
 				id, err := repo.Create(ctx, user)
 				if err != nil {
@@ -44,13 +49,14 @@ func TestUserRepository(t *testing.T) {
 		{
 			name: "Create_Fail_DatabaseError",
 			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(`INSERT INTO users \(login, password\) VALUES \(\$1, \$2\) RETURNING id`).
-					WithArgs("new_user", "hashed_password").
+				mock.ExpectQuery(`INSERT INTO users \(login, password, auth_version, kdf\) VALUES \(\$1, \$2, \$3, \$4\) RETURNING id`).
+					WithArgs("new_user", "hashed_password", 1, nil).
 					WillReturnError(fmt.Errorf("database error"))
 
 				user := models.User{
-					Login:    "new_user",
-					Password: "hashed_password",
+					Login:       "new_user",
+					Password:    "hashed_password",
+					AuthVersion: models.AuthVersionHash,
 				}
 				_, err := repo.Create(ctx, user)
 				if err == nil || err.Error() != "database error" {
@@ -62,10 +68,10 @@ func TestUserRepository(t *testing.T) {
 		{
 			name: "GetUserByID_Success",
 			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(`SELECT id, login, created_at, password FROM users WHERE id = \$1`).
+				mock.ExpectQuery(`SELECT id, login, created_at, password, auth_version, kdf FROM users WHERE id = \$1`).
 					WithArgs(1).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "login", "created_at", "password"}).
-						AddRow(1, "existing_user", time.Now(), "hashed_password"))
+					WillReturnRows(sqlmock.NewRows([]string{"id", "login", "created_at", "password", "auth_version", "kdf"}).
+						AddRow(1, "existing_user", time.Now(), "hashed_password", 1, []byte(`{"algorithm":"argon2id","salt":"MDEyMzQ1Njc4OWFiY2RlZg==","argon2_memory":65536,"argon2_time":3,"argon2_threads":4}`)))
 
 				user, err := repo.GetUserByID(ctx, 1)
 				if err != nil {
@@ -74,13 +80,16 @@ func TestUserRepository(t *testing.T) {
 				if user.Login != "existing_user" {
 					t.Errorf("Expected login 'existing_user', got %v", user.Login)
 				}
+				if user.KDF == nil || user.KDF.Algorithm != pkgModels.KDFArgon2id {
+					t.Errorf("Expected argon2id kdf params, got %v", user.KDF)
+				}
 			},
 			expectErr: false,
 		},
 		{
 			name: "GetUserByID_Fail_NotFound",
 			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(`SELECT id, login, created_at, password FROM users WHERE id = \$1`).
+				mock.ExpectQuery(`SELECT id, login, created_at, password, auth_version, kdf FROM users WHERE id = \$1`).
 					WithArgs(1).
 					WillReturnError(sql.ErrNoRows)
 
@@ -94,10 +103,10 @@ func TestUserRepository(t *testing.T) {
 		{
 			name: "GetUserByLogin_Success",
 			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(`SELECT id, login, created_at, password FROM users WHERE login = \$1`).
+				mock.ExpectQuery(`SELECT id, login, created_at, password, auth_version, kdf FROM users WHERE login = \$1 AND NOT is_vault`).
 					WithArgs("existing_user").
-					WillReturnRows(sqlmock.NewRows([]string{"id", "login", "created_at", "password"}).
-						AddRow(1, "existing_user", time.Now(), "hashed_password"))
+					WillReturnRows(sqlmock.NewRows([]string{"id", "login", "created_at", "password", "auth_version", "kdf"}).
+						AddRow(1, "existing_user", time.Now(), "hashed_password", 1, nil))
 
 				user, err := repo.GetUserByLogin(ctx, "existing_user")
 				if err != nil {
@@ -112,7 +121,7 @@ func TestUserRepository(t *testing.T) {
 		{
 			name: "GetUserByLogin_Fail_NotFound",
 			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(`SELECT id, login, created_at, password FROM users WHERE login = \$1`).
+				mock.ExpectQuery(`SELECT id, login, created_at, password, auth_version, kdf FROM users WHERE login = \$1 AND NOT is_vault`).
 					WithArgs("nonexistent_user").
 					WillReturnError(sql.ErrNoRows)
 
@@ -123,6 +132,350 @@ func TestUserRepository(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "UpdateCredentials_Success",
+			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE users SET password = \$1, auth_version = \$2 WHERE id = \$3`).
+					WithArgs("new_hash", 1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				err := repo.UpdateCredentials(ctx, 1, "new_hash", models.AuthVersionHash)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "UpdateCredentials_Fail_NotFound",
+			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE users SET password = \$1, auth_version = \$2 WHERE id = \$3`).
+					WithArgs("new_hash", 1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				err := repo.UpdateCredentials(ctx, 1, "new_hash", models.AuthVersionHash)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "Delete_Success",
+			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectLastOwnerCheck(mock, nil)
+				expectOwnedOrganizationsDelete(mock, 70)
+				mock.ExpectExec(`DELETE FROM users WHERE id = \$1`).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				vaultIDs, err := repo.Delete(ctx, 1)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if len(vaultIDs) != 1 || vaultIDs[0] != 70 {
+					t.Errorf("Expected deleted vault 70, got %v", vaultIDs)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "Delete_Fail_LastOwner",
+			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectLastOwnerCheck(mock, sqlmock.NewRows([]string{"name"}).AddRow("Team"))
+				mock.ExpectRollback()
+
+				_, err := repo.Delete(ctx, 1)
+				if !errors.Is(err, gophKeeperErrors.ErrLastOwner) {
+					t.Errorf("Expected error 'ErrLastOwner', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "Delete_Fail_NotFound",
+			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectLastOwnerCheck(mock, nil)
+				expectOwnedOrganizationsDelete(mock)
+				mock.ExpectExec(`DELETE FROM users WHERE id = \$1`).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+
+				_, err := repo.Delete(ctx, 1)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "Rekey_Success",
+			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
+				kdf := &pkgModels.KDFParams{Algorithm: pkgModels.KDFArgon2id, Salt: []byte("0123456789abcdef"), Argon2Memory: 65536, Argon2Time: 3, Argon2Threads: 4}
+
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT vault_key IS NOT NULL FROM users WHERE id = \$1 FOR UPDATE`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"has_vault_key"}).AddRow(false))
+				mock.ExpectQuery(`SELECT id FROM secrets WHERE user_id = \$1 AND \(\$2 OR data_key IS NULL\)`).
+					WithArgs(1, true).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+				expectRekeySecret(mock, 1)
+				mock.ExpectExec(`DELETE FROM secret_versions WHERE user_id = \$1 AND \(\$2 OR data_key IS NULL\)`).
+					WithArgs(1, true).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec(`DELETE FROM shares WHERE owner_id = \$1 OR recipient_id = \$1`).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(`DELETE FROM emergency_access WHERE grantor_id = \$1 OR grantee_id = \$1`).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE users SET public_key = NULL, private_key = NULL WHERE id = \$1`).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE users SET password = \$1, auth_version = \$2, kdf = \$3, vault_key = \$4 WHERE id = \$5`).
+					WithArgs("new_hash", models.AuthVersionHash, sqlmock.AnyArg(), []byte(nil), 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				err := repo.Rekey(ctx, 1, "new_hash", kdf, nil, rekeyedSecrets)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "Rekey_VaultKey",
+			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT vault_key IS NOT NULL FROM users WHERE id = \$1 FOR UPDATE`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"has_vault_key"}).AddRow(true))
+				mock.ExpectQuery(`SELECT id FROM secrets WHERE user_id = \$1 AND \(\$2 OR data_key IS NULL\)`).
+					WithArgs(1, false).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectExec(`DELETE FROM secret_versions WHERE user_id = \$1 AND \(\$2 OR data_key IS NULL\)`).
+					WithArgs(1, false).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`UPDATE users SET password = \$1, auth_version = \$2, kdf = \$3, vault_key = \$4 WHERE id = \$5`).
+					WithArgs("new_hash", models.AuthVersionHash, sqlmock.AnyArg(), []byte("vault_key"), 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				err := repo.Rekey(ctx, 1, "new_hash", nil, []byte("vault_key"), nil)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "Rekey_Fail_Incomplete",
+			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT vault_key IS NOT NULL FROM users WHERE id = \$1 FOR UPDATE`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"has_vault_key"}).AddRow(false))
+				mock.ExpectQuery(`SELECT id FROM secrets WHERE user_id = \$1 AND \(\$2 OR data_key IS NULL\)`).
+					WithArgs(1, true).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10).AddRow(11))
+				mock.ExpectRollback()
+
+				err := repo.Rekey(ctx, 1, "new_hash", nil, nil, rekeyedSecrets)
+				if !errors.Is(err, ErrIncompleteRekey) {
+					t.Errorf("Expected error 'ErrIncompleteRekey', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "Rekey_Fail_SecretNotFound",
+			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT vault_key IS NOT NULL FROM users WHERE id = \$1 FOR UPDATE`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"has_vault_key"}).AddRow(true))
+				mock.ExpectQuery(`SELECT id FROM secrets WHERE user_id = \$1 AND \(\$2 OR data_key IS NULL\)`).
+					WithArgs(1, false).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				expectRekeySecret(mock, 0)
+				mock.ExpectRollback()
+
+				err := repo.Rekey(ctx, 1, "new_hash", nil, []byte("vault_key"), rekeyedSecrets)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "Rekey_Fail_VaultKeyRequired",
+			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT vault_key IS NOT NULL FROM users WHERE id = \$1 FOR UPDATE`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"has_vault_key"}).AddRow(true))
+				mock.ExpectRollback()
+
+				err := repo.Rekey(ctx, 1, "new_hash", nil, nil, rekeyedSecrets)
+				if !errors.Is(err, ErrVaultKeyRequired) {
+					t.Errorf("Expected error 'ErrVaultKeyRequired', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "Rekey_Fail_UserNotFound",
+			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectQuery(`SELECT vault_key IS NOT NULL FROM users WHERE id = \$1 FOR UPDATE`).
+					WithArgs(1).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+
+				err := repo.Rekey(ctx, 1, "new_hash", nil, []byte("vault_key"), nil)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "GetVaultKey_Success",
+			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT vault_key FROM users WHERE id = \$1`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"vault_key"}).AddRow([]byte("vault_key")))
+
+				vaultKey, err := repo.GetVaultKey(ctx, 1)
+				if err != nil || string(vaultKey) != "vault_key" {
+					t.Errorf("Expected vault key, got %q, err = %v", vaultKey, err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "GetVaultKey_Fail_NotFound",
+			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT vault_key FROM users WHERE id = \$1`).
+					WithArgs(1).
+					WillReturnError(sql.ErrNoRows)
+
+				_, err := repo.GetVaultKey(ctx, 1)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "CreateVaultKey_Success",
+			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectExec(`UPDATE users SET vault_key = \$1 WHERE id = \$2 AND vault_key IS NULL`).
+					WithArgs([]byte("vault_key"), 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`SELECT id FROM secrets WHERE user_id = \$1 AND \(\$2 OR data_key IS NULL\)`).
+					WithArgs(1, false).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+				expectRekeySecret(mock, 1)
+				mock.ExpectExec(`DELETE FROM secret_versions WHERE user_id = \$1 AND data_key IS NULL`).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+
+				if err := repo.CreateVaultKey(ctx, 1, []byte("vault_key"), rekeyedSecrets); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "CreateVaultKey_Fail_Exists",
+			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectTenant(mock, "1")
+				mock.ExpectExec(`UPDATE users SET vault_key = \$1 WHERE id = \$2 AND vault_key IS NULL`).
+					WithArgs([]byte("vault_key"), 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+
+				err := repo.CreateVaultKey(ctx, 1, []byte("vault_key"), rekeyedSecrets)
+				if !errors.Is(err, ErrVaultKeyExists) {
+					t.Errorf("Expected error 'ErrVaultKeyExists', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "GetKeyPair_Success",
+			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT public_key, private_key FROM users WHERE id = \$1`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"public_key", "private_key"}).AddRow([]byte("public"), []byte("private")))
+
+				keyPair, err := repo.GetKeyPair(ctx, 1)
+				if err != nil || string(keyPair.PublicKey) != "public" || string(keyPair.PrivateKey) != "private" {
+					t.Errorf("Expected key pair, got %+v, err = %v", keyPair, err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "GetKeyPair_Fail_NotFound",
+			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT public_key, private_key FROM users WHERE id = \$1`).
+					WithArgs(1).
+					WillReturnError(sql.ErrNoRows)
+
+				_, err := repo.GetKeyPair(ctx, 1)
+				if !errors.Is(err, gophKeeperErrors.ErrNotFound) {
+					t.Errorf("Expected error 'ErrNotFound', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "CreateKeyPair_Success",
+			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE users SET public_key = \$1, private_key = \$2 WHERE id = \$3 AND public_key IS NULL`).
+					WithArgs([]byte("public"), []byte("private"), 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				err := repo.CreateKeyPair(ctx, 1, &pkgModels.KeyPair{PublicKey: []byte("public"), PrivateKey: []byte("private")})
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			},
+			expectErr: false,
+		},
+		{
+			name: "CreateKeyPair_Fail_Exists",
+			testFunc: func(t *testing.T, repo IUserRepository, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE users SET public_key = \$1, private_key = \$2 WHERE id = \$3 AND public_key IS NULL`).
+					WithArgs([]byte("public"), []byte("private"), 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				err := repo.CreateKeyPair(ctx, 1, &pkgModels.KeyPair{PublicKey: []byte("public"), PrivateKey: []byte("private")})
+				if !errors.Is(err, ErrKeyPairExists) {
+					t.Errorf("Expected error 'ErrKeyPairExists', got %v", err)
+				}
+			},
+			expectErr: true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -143,3 +496,33 @@ func TestUserRepository(t *testing.T) {
 		})
 	}
 }
+
+// expectRekeySecret ожидает замену зашифрованных данных секрета 10 пользователя 1.
+func expectRekeySecret(mock sqlmock.Sqlmock, affected int64) {
+	mock.ExpectExec(`UPDATE secrets SET payload = \$1, title = \$2, metadata = \$3, labels_encrypted = true, search_tokens = string_to_array\(\$4, ' '\),\s+data_key = \$5, updated_at = now\(\), revision = revision \+ 1 WHERE id = \$6 AND user_id = \$7`).
+		WithArgs([]byte("new_payload"), "new_title", "new_metadata", "token1 token2", []byte("data_key"), 10, 1).
+		WillReturnResult(sqlmock.NewResult(0, affected))
+}
+
+// expectLastOwnerCheck ожидает поиск организации, единственным владельцем которой является пользователь 1
+// и в которой есть другие участники. При пустом rows такой организации нет.
+func expectLastOwnerCheck(mock sqlmock.Sqlmock, rows *sqlmock.Rows) {
+	if rows == nil {
+		rows = sqlmock.NewRows([]string{"name"})
+	}
+	mock.ExpectQuery(`SELECT o.name FROM organizations o\s+JOIN org_members m ON (.+) LIMIT 1`).
+		WithArgs(1).
+		WillReturnRows(rows)
+}
+
+// expectOwnedOrganizationsDelete ожидает удаление хранилищ vaultIDs организаций, единственным участником
+// которых был пользователь 1.
+func expectOwnedOrganizationsDelete(mock sqlmock.Sqlmock, vaultIDs ...uint64) {
+	rows := sqlmock.NewRows([]string{"id"})
+	for _, id := range vaultIDs {
+		rows.AddRow(id)
+	}
+	mock.ExpectQuery(`DELETE FROM users WHERE id IN \(SELECT o.vault_id FROM organizations o\s+JOIN org_members m ON (.+) RETURNING id`).
+		WithArgs(1).
+		WillReturnRows(rows)
+}
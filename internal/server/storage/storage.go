@@ -15,4 +15,24 @@ type Storage struct {
 	// Этот репозиторий используется для управления секретными данными, такими как пароли,
 	// банковские карты и другие конфиденциальные материалы.
 	SecretRepository repository.ISecretRepository
+	// BlobRepository предоставляет доступ к бинарным объектам с содержимым файлов,
+	// которые хранятся отдельно от секретов в виде зашифрованных фрагментов.
+	BlobRepository repository.IBlobRepository
+	// BlobStore хранит зашифрованные фрагменты файлов вне PostgreSQL; в базе остаются только
+	// сведения об объектах и ссылки на них из секретов.
+	BlobStore BlobStore
+	// FolderRepository предоставляет доступ к папкам и меткам, которыми пользователь упорядочивает секреты.
+	FolderRepository repository.IFolderRepository
+	// ShareRepository предоставляет доступ к секретам, переданным другим пользователям, и доступам к ним.
+	ShareRepository repository.IShareRepository
+	// OrganizationRepository предоставляет доступ к организациям, их участникам и общим хранилищам секретов.
+	OrganizationRepository repository.IOrganizationRepository
+	// EmergencyRepository предоставляет доступ к экстренным доступам доверенных пользователей к хранилищам.
+	EmergencyRepository repository.IEmergencyRepository
+	// SessionRepository предоставляет доступ к сессиям пользователей и их refresh-токенам.
+	SessionRepository repository.ISessionRepository
+	// TOTPRepository предоставляет доступ к секретам двухфакторной аутентификации и кодам восстановления.
+	TOTPRepository repository.ITOTPRepository
+	// LoginAttemptRepository предоставляет доступ к журналу попыток входа.
+	LoginAttemptRepository repository.ILoginAttemptRepository
 }
@@ -28,8 +28,16 @@ func NewStorage(dsn string) (*Storage, error) {
 	}
 
 	return &Storage{
-		UserRepository:   repository.NewUserRepository(db),
-		SecretRepository: repository.NewSecretRepository(db),
+		UserRepository:         repository.NewUserRepository(db),
+		SecretRepository:       repository.NewSecretRepository(db),
+		BlobRepository:         repository.NewBlobRepository(db),
+		FolderRepository:       repository.NewFolderRepository(db),
+		ShareRepository:        repository.NewShareRepository(db),
+		OrganizationRepository: repository.NewOrganizationRepository(db),
+		EmergencyRepository:    repository.NewEmergencyRepository(db),
+		SessionRepository:      repository.NewSessionRepository(db),
+		TOTPRepository:         repository.NewTOTPRepository(db),
+		LoginAttemptRepository: repository.NewLoginAttemptRepository(db),
 	}, nil
 }
 
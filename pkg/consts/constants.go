@@ -8,7 +8,39 @@ var (
 	// ClientIDHeader определяет название HTTP-заголовка, используемого для передачи идентификатора клиента.
 	ClientIDHeader = "X-Client-ID"
 
+	// OrganizationIDHeader определяет название HTTP-заголовка, в котором клиент передаёт идентификатор
+	// организации, чтобы работать с секретами её хранилища вместо личного.
+	OrganizationIDHeader = "X-Organization-ID"
+
+	// EmergencyAccessIDHeader определяет название HTTP-заголовка, в котором доверенный пользователь передаёт
+	// идентификатор предоставленного ему экстренного доступа, чтобы читать секреты хранилища владельца.
+	EmergencyAccessIDHeader = "X-Emergency-Access-ID"
+
+	// ShareIDHeader определяет название HTTP-заголовка, в котором получатель секрета-файла передаёт
+	// идентификатор доступа, чтобы скачать файл из хранилища владельца.
+	ShareIDHeader = "X-Share-ID"
+
 	// CtxUserIDKey представляет ключ, используемый для сохранения и извлечения идентификатора пользователя
 	// из контекста запроса. Этот ключ помогает в передаче данных пользователя между различными слоями приложения.
 	CtxUserIDKey = "user_id"
+
+	// CtxSessionIDKey представляет ключ, используемый для сохранения и извлечения идентификатора сессии
+	// пользователя, к которой привязан токен доступа запроса.
+	CtxSessionIDKey = "session_id"
+
+	// RevisionConflictReason определяет причину в детали ErrorInfo ошибки Aborted,
+	// которой сервер отклоняет сохранение секрета с устаревшей ревизией.
+	RevisionConflictReason = "REVISION_CONFLICT"
+
+	// CurrentRevisionKey определяет ключ метаданных ErrorInfo с текущей ревизией секрета на сервере.
+	CurrentRevisionKey = "current_revision"
+)
+
+const (
+	// MaxBlobChunkSize определяет максимальный размер зашифрованного фрагмента бинарного объекта в байтах.
+	// Включает запас на nonce и тег аутентификации поверх фрагмента открытых данных размером 1 МиБ.
+	MaxBlobChunkSize = 1<<20 + 1024
+
+	// MaxBlobChunks определяет максимальное количество фрагментов одного бинарного объекта.
+	MaxBlobChunks = 1 << 16
 )
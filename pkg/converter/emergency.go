@@ -0,0 +1,85 @@
+package converter
+
+import (
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/pkg/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// emergencyStatusesToProto сопоставляет состояния экстренного доступа модели данных значениям protobuf.
+var emergencyStatusesToProto = map[models.EmergencyStatus]proto.EmergencyStatus{
+	models.EmergencyIdle:      proto.EmergencyStatus_EMERGENCY_STATUS_IDLE,
+	models.EmergencyRequested: proto.EmergencyStatus_EMERGENCY_STATUS_REQUESTED,
+	models.EmergencyGranted:   proto.EmergencyStatus_EMERGENCY_STATUS_GRANTED,
+}
+
+// EmergencyStatusToProto конвертирует состояние экстренного доступа из модели данных в состояние protobuf.
+// Неизвестное состояние конвертируется в EMERGENCY_STATUS_UNSPECIFIED.
+func EmergencyStatusToProto(status models.EmergencyStatus) proto.EmergencyStatus {
+	return emergencyStatusesToProto[status]
+}
+
+// ProtoToEmergencyStatus конвертирует состояние экстренного доступа из protobuf в состояние модели данных.
+// Для EMERGENCY_STATUS_UNSPECIFIED и неизвестных значений возвращается пустое состояние.
+func ProtoToEmergencyStatus(pbStatus proto.EmergencyStatus) models.EmergencyStatus {
+	for status, value := range emergencyStatusesToProto {
+		if value == pbStatus {
+			return status
+		}
+	}
+	return ""
+}
+
+// EmergencyAccessesToProto конвертирует список экстренных доступов из модели данных в список protobuf.
+func EmergencyAccessesToProto(accesses []*models.EmergencyAccess) []*proto.EmergencyAccess {
+	var pbAccesses []*proto.EmergencyAccess
+	for _, access := range accesses {
+		pbAccesses = append(pbAccesses, EmergencyAccessToProto(access))
+	}
+	return pbAccesses
+}
+
+// EmergencyAccessToProto конвертирует экстренный доступ из модели данных в экстренный доступ protobuf.
+// Идентификаторы участников не передаются.
+func EmergencyAccessToProto(access *models.EmergencyAccess) *proto.EmergencyAccess {
+	pbAccess := &proto.EmergencyAccess{
+		Id:           access.ID,
+		GrantorLogin: access.GrantorLogin,
+		GranteeLogin: access.GranteeLogin,
+		WaitDays:     uint32(access.WaitDays),
+		Status:       EmergencyStatusToProto(access.Status),
+		VaultKey:     access.VaultKey,
+		CreatedAt:    timestamppb.New(access.CreatedAt),
+	}
+	if access.RequestedAt != nil {
+		pbAccess.RequestedAt = timestamppb.New(*access.RequestedAt)
+	}
+	return pbAccess
+}
+
+// ProtoToEmergencyAccesses конвертирует список экстренных доступов из protobuf в список модели данных.
+func ProtoToEmergencyAccesses(pbAccesses []*proto.EmergencyAccess) []*models.EmergencyAccess {
+	var accesses []*models.EmergencyAccess
+	for _, access := range pbAccesses {
+		accesses = append(accesses, ProtoToEmergencyAccess(access))
+	}
+	return accesses
+}
+
+// ProtoToEmergencyAccess конвертирует экстренный доступ из protobuf в экстренный доступ модели данных.
+func ProtoToEmergencyAccess(pbAccess *proto.EmergencyAccess) *models.EmergencyAccess {
+	access := &models.EmergencyAccess{
+		ID:           pbAccess.Id,
+		GrantorLogin: pbAccess.GrantorLogin,
+		GranteeLogin: pbAccess.GranteeLogin,
+		WaitDays:     int(pbAccess.WaitDays),
+		Status:       ProtoToEmergencyStatus(pbAccess.Status),
+		VaultKey:     pbAccess.VaultKey,
+		CreatedAt:    pbAccess.CreatedAt.AsTime(),
+	}
+	if pbAccess.RequestedAt != nil {
+		requestedAt := pbAccess.RequestedAt.AsTime()
+		access.RequestedAt = &requestedAt
+	}
+	return access
+}
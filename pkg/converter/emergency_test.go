@@ -0,0 +1,23 @@
+package converter
+
+import (
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestEmergencyAccessesRoundTrip(t *testing.T) {
+	requestedAt := time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)
+	accesses := []*models.EmergencyAccess{
+		{ID: 5, GrantorLogin: "alice", GranteeLogin: "bob", WaitDays: 7, Status: models.EmergencyRequested, RequestedAt: &requestedAt, CreatedAt: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: 6, GrantorLogin: "alice", GranteeLogin: "carol", WaitDays: 3, Status: models.EmergencyGranted, VaultKey: []byte("wrapped"), CreatedAt: time.Date(2025, 2, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	assert.Equal(t, accesses, ProtoToEmergencyAccesses(EmergencyAccessesToProto(accesses)))
+}
+
+func TestProtoToEmergencyStatus_Unspecified(t *testing.T) {
+	assert.Equal(t, models.EmergencyStatus(""), ProtoToEmergencyStatus(proto.EmergencyStatus_EMERGENCY_STATUS_UNSPECIFIED))
+}
@@ -0,0 +1,85 @@
+package converter
+
+import (
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/pkg/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// FoldersToProto конвертирует список папок из модели данных в список папок protobuf.
+// Возвращает новый список объектов Folder protobuf.
+func FoldersToProto(folders []*models.Folder) []*proto.Folder {
+	var pbFolders []*proto.Folder
+	for _, f := range folders {
+		pbFolders = append(pbFolders, FolderToProto(f))
+	}
+	return pbFolders
+}
+
+// FolderToProto конвертирует папку из модели данных в папку protobuf.
+func FolderToProto(folder *models.Folder) *proto.Folder {
+	return &proto.Folder{
+		Id:        folder.ID,
+		ParentId:  folder.ParentID,
+		Name:      folder.Name,
+		CreatedAt: timestamppb.New(folder.CreatedAt),
+	}
+}
+
+// ProtoToFolders конвертирует список папок из protobuf в список папок модели данных.
+// Возвращает новый список объектов Folder модели данных.
+func ProtoToFolders(pbFolders []*proto.Folder) []*models.Folder {
+	var folders []*models.Folder
+	for _, f := range pbFolders {
+		folders = append(folders, ProtoToFolder(f))
+	}
+	return folders
+}
+
+// ProtoToFolder конвертирует папку из protobuf в папку модели данных.
+func ProtoToFolder(pbFolder *proto.Folder) *models.Folder {
+	return &models.Folder{
+		ID:        pbFolder.Id,
+		ParentID:  pbFolder.ParentId,
+		Name:      pbFolder.Name,
+		CreatedAt: pbFolder.CreatedAt.AsTime(),
+	}
+}
+
+// TagsToProto конвертирует список меток из модели данных в список меток protobuf.
+// Возвращает новый список объектов Tag protobuf.
+func TagsToProto(tags []*models.Tag) []*proto.Tag {
+	var pbTags []*proto.Tag
+	for _, t := range tags {
+		pbTags = append(pbTags, TagToProto(t))
+	}
+	return pbTags
+}
+
+// TagToProto конвертирует метку из модели данных в метку protobuf.
+func TagToProto(tag *models.Tag) *proto.Tag {
+	return &proto.Tag{
+		Id:        tag.ID,
+		Name:      tag.Name,
+		CreatedAt: timestamppb.New(tag.CreatedAt),
+	}
+}
+
+// ProtoToTags конвертирует список меток из protobuf в список меток модели данных.
+// Возвращает новый список объектов Tag модели данных.
+func ProtoToTags(pbTags []*proto.Tag) []*models.Tag {
+	var tags []*models.Tag
+	for _, t := range pbTags {
+		tags = append(tags, ProtoToTag(t))
+	}
+	return tags
+}
+
+// ProtoToTag конвертирует метку из protobuf в метку модели данных.
+func ProtoToTag(pbTag *proto.Tag) *models.Tag {
+	return &models.Tag{
+		ID:        pbTag.Id,
+		Name:      pbTag.Name,
+		CreatedAt: pbTag.CreatedAt.AsTime(),
+	}
+}
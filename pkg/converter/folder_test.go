@@ -0,0 +1,32 @@
+package converter
+
+import (
+	"beliaev-aa/GophKeeper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestFoldersRoundTrip(t *testing.T) {
+	createdAt := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	folders := []*models.Folder{
+		{ID: 1, Name: "Banks", CreatedAt: createdAt},
+		{ID: 2, ParentID: 1, Name: "Cards", CreatedAt: createdAt},
+	}
+
+	assert.Equal(t, folders, ProtoToFolders(FoldersToProto(folders)))
+}
+
+func TestTagsRoundTrip(t *testing.T) {
+	tags := []*models.Tag{{ID: 3, Name: "work", CreatedAt: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)}}
+
+	assert.Equal(t, tags, ProtoToTags(TagsToProto(tags)))
+}
+
+func TestSecretLabelsRoundTrip(t *testing.T) {
+	secret := &models.Secret{ID: 1, FolderID: 2, TagIDs: models.IDList{3, 4}}
+
+	result := ProtoToSecret(SecretToProto(secret))
+	assert.Equal(t, secret.FolderID, result.FolderID)
+	assert.Equal(t, secret.TagIDs, result.TagIDs)
+}
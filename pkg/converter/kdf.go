@@ -0,0 +1,44 @@
+package converter
+
+import (
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/pkg/proto"
+)
+
+// ProtoToKDFParams конвертирует параметры KDF из protobuf в модель данных.
+// Возвращает nil, если параметры не переданы.
+func ProtoToKDFParams(pbParams *proto.KDFParams) *models.KDFParams {
+	if pbParams == nil {
+		return nil
+	}
+
+	return &models.KDFParams{
+		Algorithm:     models.KDFAlgorithm(pbParams.Algorithm),
+		Salt:          pbParams.Salt,
+		ScryptN:       pbParams.ScryptN,
+		ScryptR:       pbParams.ScryptR,
+		ScryptP:       pbParams.ScryptP,
+		Argon2Memory:  pbParams.Argon2Memory,
+		Argon2Time:    pbParams.Argon2Time,
+		Argon2Threads: pbParams.Argon2Threads,
+	}
+}
+
+// KDFParamsToProto конвертирует параметры KDF из модели данных в protobuf.
+// Возвращает nil, если параметры не заданы.
+func KDFParamsToProto(params *models.KDFParams) *proto.KDFParams {
+	if params == nil {
+		return nil
+	}
+
+	return &proto.KDFParams{
+		Algorithm:     string(params.Algorithm),
+		Salt:          params.Salt,
+		ScryptN:       params.ScryptN,
+		ScryptR:       params.ScryptR,
+		ScryptP:       params.ScryptP,
+		Argon2Memory:  params.Argon2Memory,
+		Argon2Time:    params.Argon2Time,
+		Argon2Threads: params.Argon2Threads,
+	}
+}
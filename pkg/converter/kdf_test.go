@@ -0,0 +1,46 @@
+package converter
+
+import (
+	"beliaev-aa/GophKeeper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestKDFParamsConversion(t *testing.T) {
+	tests := []struct {
+		name   string
+		params *models.KDFParams
+	}{
+		{
+			name:   "Nil",
+			params: nil,
+		},
+		{
+			name: "Scrypt",
+			params: &models.KDFParams{
+				Algorithm: models.KDFScrypt,
+				Salt:      []byte("0123456789abcdef"),
+				ScryptN:   32768,
+				ScryptR:   8,
+				ScryptP:   1,
+			},
+		},
+		{
+			name: "Argon2id",
+			params: &models.KDFParams{
+				Algorithm:     models.KDFArgon2id,
+				Salt:          []byte("0123456789abcdef"),
+				Argon2Memory:  65536,
+				Argon2Time:    3,
+				Argon2Threads: 4,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ProtoToKDFParams(KDFParamsToProto(tt.params))
+			assert.Equal(t, tt.params, result)
+		})
+	}
+}
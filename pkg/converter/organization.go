@@ -0,0 +1,112 @@
+package converter
+
+import (
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/pkg/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// orgRolesToProto сопоставляет роли участников организации модели данных значениям protobuf.
+var orgRolesToProto = map[models.OrgRole]proto.OrgRole{
+	models.OrgOwner:  proto.OrgRole_ORG_ROLE_OWNER,
+	models.OrgAdmin:  proto.OrgRole_ORG_ROLE_ADMIN,
+	models.OrgEditor: proto.OrgRole_ORG_ROLE_EDITOR,
+	models.OrgViewer: proto.OrgRole_ORG_ROLE_VIEWER,
+}
+
+// OrgRoleToProto конвертирует роль участника организации из модели данных в роль protobuf.
+// Неизвестная роль конвертируется в ORG_ROLE_UNSPECIFIED.
+func OrgRoleToProto(role models.OrgRole) proto.OrgRole {
+	return orgRolesToProto[role]
+}
+
+// ProtoToOrgRole конвертирует роль участника организации из protobuf в роль модели данных.
+// Для ORG_ROLE_UNSPECIFIED и неизвестных значений возвращается пустая роль.
+func ProtoToOrgRole(pbRole proto.OrgRole) models.OrgRole {
+	for role, value := range orgRolesToProto {
+		if value == pbRole {
+			return role
+		}
+	}
+	return ""
+}
+
+// OrganizationsToProto конвертирует список организаций из модели данных в список организаций protobuf.
+func OrganizationsToProto(orgs []*models.Organization) []*proto.Organization {
+	var pbOrgs []*proto.Organization
+	for _, org := range orgs {
+		pbOrgs = append(pbOrgs, OrganizationToProto(org))
+	}
+	return pbOrgs
+}
+
+// OrganizationToProto конвертирует организацию из модели данных в организацию protobuf.
+// Идентификатор служебной учётной записи хранилища не передаётся.
+func OrganizationToProto(org *models.Organization) *proto.Organization {
+	return &proto.Organization{
+		Id:        org.ID,
+		Name:      org.Name,
+		Role:      OrgRoleToProto(org.Role),
+		VaultKey:  org.VaultKey,
+		CreatedAt: timestamppb.New(org.CreatedAt),
+	}
+}
+
+// ProtoToOrganizations конвертирует список организаций из protobuf в список организаций модели данных.
+func ProtoToOrganizations(pbOrgs []*proto.Organization) []*models.Organization {
+	var orgs []*models.Organization
+	for _, org := range pbOrgs {
+		orgs = append(orgs, ProtoToOrganization(org))
+	}
+	return orgs
+}
+
+// ProtoToOrganization конвертирует организацию из protobuf в организацию модели данных.
+func ProtoToOrganization(pbOrg *proto.Organization) *models.Organization {
+	return &models.Organization{
+		ID:        pbOrg.Id,
+		Name:      pbOrg.Name,
+		Role:      ProtoToOrgRole(pbOrg.Role),
+		VaultKey:  pbOrg.VaultKey,
+		CreatedAt: pbOrg.CreatedAt.AsTime(),
+	}
+}
+
+// OrgMembersToProto конвертирует список участников организации из модели данных в список участников protobuf.
+func OrgMembersToProto(members []*models.OrgMember) []*proto.OrgMember {
+	var pbMembers []*proto.OrgMember
+	for _, member := range members {
+		pbMembers = append(pbMembers, OrgMemberToProto(member))
+	}
+	return pbMembers
+}
+
+// OrgMemberToProto конвертирует участника организации из модели данных в участника protobuf.
+// Ключ хранилища участника не передаётся.
+func OrgMemberToProto(member *models.OrgMember) *proto.OrgMember {
+	return &proto.OrgMember{
+		UserId:    member.UserID,
+		Login:     member.Login,
+		Role:      OrgRoleToProto(member.Role),
+		CreatedAt: timestamppb.New(member.CreatedAt),
+	}
+}
+
+// ProtoToOrgMembers конвертирует список участников организации из protobuf в список участников модели данных.
+func ProtoToOrgMembers(pbMembers []*proto.OrgMember) []*models.OrgMember {
+	var members []*models.OrgMember
+	for _, member := range pbMembers {
+		members = append(members, ProtoToOrgMember(member))
+	}
+	return members
+}
+
+// ProtoToOrgMember конвертирует участника организации из protobuf в участника модели данных.
+func ProtoToOrgMember(pbMember *proto.OrgMember) *models.OrgMember {
+	return &models.OrgMember{
+		UserID:    pbMember.UserId,
+		Login:     pbMember.Login,
+		Role:      ProtoToOrgRole(pbMember.Role),
+		CreatedAt: pbMember.CreatedAt.AsTime(),
+	}
+}
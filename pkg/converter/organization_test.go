@@ -0,0 +1,30 @@
+package converter
+
+import (
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestOrganizationsRoundTrip(t *testing.T) {
+	orgs := []*models.Organization{
+		{ID: 7, Name: "Team", Role: models.OrgAdmin, VaultKey: []byte("wrapped"), CreatedAt: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	assert.Equal(t, orgs, ProtoToOrganizations(OrganizationsToProto(orgs)))
+}
+
+func TestOrgMembersRoundTrip(t *testing.T) {
+	members := []*models.OrgMember{
+		{UserID: 1, Login: "alice", Role: models.OrgOwner, CreatedAt: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{UserID: 2, Login: "bob", Role: models.OrgViewer, CreatedAt: time.Date(2025, 2, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	assert.Equal(t, members, ProtoToOrgMembers(OrgMembersToProto(members)))
+}
+
+func TestProtoToOrgRole_Unspecified(t *testing.T) {
+	assert.Equal(t, models.OrgRole(""), ProtoToOrgRole(proto.OrgRole_ORG_ROLE_UNSPECIFIED))
+}
@@ -44,29 +44,52 @@ func TypeToProto(sType string) proto.SecretType {
 // SecretToProto конвертирует объект Secret из модели данных в объект Secret protobuf.
 // Возвращает новый объект Secret protobuf.
 func SecretToProto(secret *models.Secret) *proto.Secret {
-	return &proto.Secret{
-		Id:         secret.ID,
-		Title:      secret.Title,
-		Metadata:   secret.Metadata,
-		Payload:    secret.Payload,
-		SecretType: TypeToProto(secret.SecretType),
-		CreatedAt:  timestamppb.New(secret.CreatedAt),
-		UpdatedAt:  timestamppb.New(secret.UpdatedAt),
+	pbSecret := &proto.Secret{
+		Id:              secret.ID,
+		Title:           secret.Title,
+		Metadata:        secret.Metadata,
+		Payload:         secret.Payload,
+		SecretType:      TypeToProto(secret.SecretType),
+		CreatedAt:       timestamppb.New(secret.CreatedAt),
+		UpdatedAt:       timestamppb.New(secret.UpdatedAt),
+		Revision:        secret.Revision,
+		BlobId:          secret.BlobID,
+		FolderId:        secret.FolderID,
+		TagIds:          secret.TagIDs,
+		LabelsEncrypted: secret.LabelsEncrypted,
+		SearchTokens:    secret.SearchTokens,
+		DataKey:         secret.DataKey,
 	}
+	if secret.DeletedAt != nil {
+		pbSecret.DeletedAt = timestamppb.New(*secret.DeletedAt)
+	}
+	return pbSecret
 }
 
 // ProtoToSecret конвертирует объект Secret из protobuf в объект Secret модели данных.
 // Возвращает новый объект Secret модели данных.
 func ProtoToSecret(pbSecret *proto.Secret) *models.Secret {
-	return &models.Secret{
-		ID:         pbSecret.Id,
-		Title:      pbSecret.Title,
-		Metadata:   pbSecret.Metadata,
-		SecretType: string(ProtoToType(pbSecret.SecretType)),
-		Payload:    pbSecret.Payload,
-		CreatedAt:  pbSecret.CreatedAt.AsTime(),
-		UpdatedAt:  pbSecret.UpdatedAt.AsTime(),
+	secret := &models.Secret{
+		ID:              pbSecret.Id,
+		Title:           pbSecret.Title,
+		Metadata:        pbSecret.Metadata,
+		SecretType:      string(ProtoToType(pbSecret.SecretType)),
+		Payload:         pbSecret.Payload,
+		CreatedAt:       pbSecret.CreatedAt.AsTime(),
+		UpdatedAt:       pbSecret.UpdatedAt.AsTime(),
+		Revision:        pbSecret.Revision,
+		BlobID:          pbSecret.BlobId,
+		FolderID:        pbSecret.FolderId,
+		TagIDs:          pbSecret.TagIds,
+		LabelsEncrypted: pbSecret.LabelsEncrypted,
+		SearchTokens:    pbSecret.SearchTokens,
+		DataKey:         pbSecret.DataKey,
+	}
+	if pbSecret.DeletedAt != nil {
+		deletedAt := pbSecret.DeletedAt.AsTime()
+		secret.DeletedAt = &deletedAt
 	}
+	return secret
 }
 
 // ProtoToSecrets конвертирует список объектов Secret из protobuf в список объектов Secret модели данных.
@@ -88,3 +111,141 @@ func SecretsToProto(secrets []*models.Secret) []*proto.Secret {
 	}
 	return pbSecrets
 }
+
+// SecretVersionsToProto конвертирует список версий секрета из модели данных в список версий protobuf.
+// Возвращает новый список объектов SecretVersion protobuf.
+func SecretVersionsToProto(versions []*models.SecretVersion) []*proto.SecretVersion {
+	var pbVersions []*proto.SecretVersion
+	for _, v := range versions {
+		pbVersions = append(pbVersions, &proto.SecretVersion{
+			Id:         v.VersionID,
+			Secret:     SecretToProto(&v.Secret),
+			ArchivedAt: timestamppb.New(v.ArchivedAt),
+		})
+	}
+	return pbVersions
+}
+
+// ProtoToSecretVersions конвертирует список версий секрета из protobuf в список версий модели данных.
+// Возвращает новый список объектов SecretVersion модели данных.
+func ProtoToSecretVersions(pbVersions []*proto.SecretVersion) []*models.SecretVersion {
+	var versions []*models.SecretVersion
+	for _, v := range pbVersions {
+		versions = append(versions, &models.SecretVersion{
+			Secret:     *ProtoToSecret(v.Secret),
+			VersionID:  v.Id,
+			ArchivedAt: v.ArchivedAt.AsTime(),
+		})
+	}
+	return versions
+}
+
+// SecretListQueryToProto конвертирует запрос страницы списка секретов из модели данных в запрос protobuf.
+// Пустой тип секрета передаётся как SECRET_TYPE_UNSPECIFIED и не ограничивает список.
+func SecretListQueryToProto(query *models.SecretListQuery) *proto.ListSecretsRequest {
+	request := &proto.ListSecretsRequest{
+		PageSize:     uint32(query.PageSize),
+		PageToken:    query.PageToken,
+		TitlePrefix:  query.TitlePrefix,
+		Order:        proto.SecretOrder(query.Order),
+		FolderId:     query.FolderID,
+		TagId:        query.TagID,
+		SearchTokens: query.SearchTokens,
+	}
+	if query.SecretType != "" {
+		request.SecretType = TypeToProto(query.SecretType)
+	}
+	if !query.UpdatedSince.IsZero() {
+		request.UpdatedSince = timestamppb.New(query.UpdatedSince)
+	}
+	return request
+}
+
+// ProtoToSecretListQuery конвертирует запрос страницы списка секретов из protobuf в запрос модели данных.
+// Возвращает новый запрос модели данных.
+func ProtoToSecretListQuery(request *proto.ListSecretsRequest) *models.SecretListQuery {
+	query := &models.SecretListQuery{
+		PageSize:     int(request.PageSize),
+		PageToken:    request.PageToken,
+		TitlePrefix:  request.TitlePrefix,
+		Order:        models.SecretOrder(request.Order),
+		SearchTokens: request.SearchTokens,
+		SecretFilter: models.SecretFilter{
+			FolderID: request.FolderId,
+			TagID:    request.TagId,
+		},
+	}
+	if request.SecretType != proto.SecretType_SECRET_TYPE_UNSPECIFIED {
+		query.SecretType = string(ProtoToType(request.SecretType))
+	}
+	if request.UpdatedSince != nil {
+		query.UpdatedSince = request.UpdatedSince.AsTime()
+	}
+	return query
+}
+
+// SecretPayloadsToProto конвертирует перешифрованные секреты по их идентификаторам в список объектов
+// SecretPayload protobuf: зашифрованные данные, заголовок, метаданные, токены слепого индекса и ключ данных.
+func SecretPayloadsToProto(secrets map[uint64]*models.Secret) []*proto.SecretPayload {
+	pbSecrets := make([]*proto.SecretPayload, 0, len(secrets))
+	for id, secret := range secrets {
+		pbSecrets = append(pbSecrets, &proto.SecretPayload{
+			Id:           id,
+			Payload:      secret.Payload,
+			Title:        secret.Title,
+			Metadata:     secret.Metadata,
+			SearchTokens: secret.SearchTokens,
+			DataKey:      secret.DataKey,
+		})
+	}
+	return pbSecrets
+}
+
+// ProtoToSecretPayloads конвертирует список объектов SecretPayload protobuf в перешифрованные секреты
+// по их идентификаторам.
+func ProtoToSecretPayloads(pbSecrets []*proto.SecretPayload) map[uint64]*models.Secret {
+	secrets := make(map[uint64]*models.Secret, len(pbSecrets))
+	for _, s := range pbSecrets {
+		secrets[s.Id] = &models.Secret{
+			ID:              s.Id,
+			Payload:         s.Payload,
+			Title:           s.Title,
+			Metadata:        s.Metadata,
+			LabelsEncrypted: true,
+			SearchTokens:    s.SearchTokens,
+			DataKey:         s.DataKey,
+		}
+	}
+	return secrets
+}
+
+// SecretLabelsToProto конвертирует секреты в список объектов SecretLabels protobuf:
+// зашифрованные заголовок и метаданные с токенами слепого индекса.
+func SecretLabelsToProto(secrets []*models.Secret) []*proto.SecretLabels {
+	pbLabels := make([]*proto.SecretLabels, 0, len(secrets))
+	for _, s := range secrets {
+		pbLabels = append(pbLabels, &proto.SecretLabels{
+			Id:           s.ID,
+			Title:        s.Title,
+			Metadata:     s.Metadata,
+			SearchTokens: s.SearchTokens,
+		})
+	}
+	return pbLabels
+}
+
+// ProtoToSecretLabels конвертирует список объектов SecretLabels protobuf в секреты
+// с зашифрованными заголовком и метаданными.
+func ProtoToSecretLabels(pbLabels []*proto.SecretLabels) models.Secrets {
+	secrets := make(models.Secrets, 0, len(pbLabels))
+	for _, l := range pbLabels {
+		secrets = append(secrets, &models.Secret{
+			ID:              l.Id,
+			Title:           l.Title,
+			Metadata:        l.Metadata,
+			LabelsEncrypted: true,
+			SearchTokens:    l.SearchTokens,
+		})
+	}
+	return secrets
+}
@@ -181,3 +181,112 @@ func TestSecretsToProto(t *testing.T) {
 		assert.Equal(t, expected[i], result[i])
 	}
 }
+
+func TestSecretVersionsRoundTrip(t *testing.T) {
+	updatedAt := time.Now().UTC()
+	archivedAt := updatedAt.Add(1 * time.Hour)
+	versions := []*models.SecretVersion{
+		{
+			Secret: models.Secret{
+				ID:         1,
+				Title:      "Old Title",
+				Metadata:   "metadata",
+				Payload:    []byte("payload"),
+				SecretType: string(models.TextSecret),
+				UpdatedAt:  updatedAt,
+			},
+			VersionID:  7,
+			ArchivedAt: archivedAt,
+		},
+	}
+
+	pbVersions := SecretVersionsToProto(versions)
+	assert.Len(t, pbVersions, 1)
+	assert.Equal(t, uint64(7), pbVersions[0].Id)
+	assert.Equal(t, uint64(1), pbVersions[0].Secret.Id)
+	assert.Equal(t, proto.SecretType_SECRET_TYPE_TEXT, pbVersions[0].Secret.SecretType)
+	assert.Equal(t, timestamppb.New(archivedAt), pbVersions[0].ArchivedAt)
+
+	result := ProtoToSecretVersions(pbVersions)
+	assert.Len(t, result, 1)
+	assert.Equal(t, versions[0].VersionID, result[0].VersionID)
+	assert.Equal(t, versions[0].Title, result[0].Title)
+	assert.Equal(t, versions[0].Payload, result[0].Payload)
+	assert.True(t, versions[0].UpdatedAt.Equal(result[0].UpdatedAt))
+	assert.True(t, versions[0].ArchivedAt.Equal(result[0].ArchivedAt))
+}
+
+func TestSecretDeletedAtRoundTrip(t *testing.T) {
+	deletedAt := time.Now().UTC()
+	secret := &models.Secret{ID: 1, SecretType: string(models.TextSecret), DeletedAt: &deletedAt, Revision: 7}
+
+	pbSecret := SecretToProto(secret)
+	assert.Equal(t, timestamppb.New(deletedAt), pbSecret.DeletedAt)
+	assert.Equal(t, uint64(7), pbSecret.Revision)
+
+	result := ProtoToSecret(pbSecret)
+	assert.Equal(t, uint64(7), result.Revision)
+	if assert.NotNil(t, result.DeletedAt) {
+		assert.True(t, deletedAt.Equal(*result.DeletedAt))
+	}
+
+	assert.Nil(t, ProtoToSecret(&proto.Secret{Id: 2}).DeletedAt)
+}
+
+func TestSecretBlobIDRoundTrip(t *testing.T) {
+	secret := &models.Secret{ID: 1, SecretType: string(models.BlobSecret), BlobID: "0123456789abcdef0123456789abcdef"}
+
+	pbSecret := SecretToProto(secret)
+	assert.Equal(t, secret.BlobID, pbSecret.BlobId)
+	assert.Equal(t, secret.BlobID, ProtoToSecret(pbSecret).BlobID)
+}
+
+func TestSecretListQueryRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *models.SecretListQuery
+	}{
+		{"Empty", &models.SecretListQuery{}},
+		{"Filtered", &models.SecretListQuery{
+			SecretType:   string(models.CardSecret),
+			TitlePrefix:  "Bank",
+			UpdatedSince: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+			Order:        models.SecretOrderTitleDesc,
+			SearchTokens: []string{"token1", "token2"},
+			SecretFilter: models.SecretFilter{FolderID: 2, TagID: 3},
+			PageSize:     20,
+			PageToken:    "token",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := SecretListQueryToProto(tt.query)
+			assert.Equal(t, tt.query, ProtoToSecretListQuery(request))
+		})
+	}
+}
+
+func TestEncryptedLabelsRoundTrip(t *testing.T) {
+	secret := &models.Secret{ID: 1, Title: "sealed", Metadata: "meta", LabelsEncrypted: true, SearchTokens: []string{"token"}}
+
+	assert.Equal(t, models.Secrets{secret}, ProtoToSecretLabels(SecretLabelsToProto([]*models.Secret{secret})))
+
+	converted := ProtoToSecret(SecretToProto(secret))
+	assert.True(t, converted.LabelsEncrypted)
+	assert.Equal(t, secret.SearchTokens, converted.SearchTokens)
+}
+
+func TestSecretDataKeyRoundTrip(t *testing.T) {
+	secret := &models.Secret{ID: 1, Payload: []byte("payload"), DataKey: []byte("wrapped")}
+
+	assert.Equal(t, secret.DataKey, ProtoToSecret(SecretToProto(secret)).DataKey)
+}
+
+func TestSecretPayloadsRoundTrip(t *testing.T) {
+	secrets := map[uint64]*models.Secret{
+		1: {ID: 1, Payload: []byte("payload"), Title: "sealed", Metadata: "meta", LabelsEncrypted: true, SearchTokens: []string{"token"}, DataKey: []byte("wrapped")},
+	}
+
+	assert.Equal(t, secrets, ProtoToSecretPayloads(SecretPayloadsToProto(secrets)))
+}
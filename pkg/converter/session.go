@@ -0,0 +1,22 @@
+package converter
+
+import (
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/pkg/proto"
+)
+
+// ProtoToDeviceSessions конвертирует список сессий устройств из protobuf в модели данных.
+func ProtoToDeviceSessions(pbSessions []*proto.DeviceSession) []*models.DeviceSession {
+	sessions := make([]*models.DeviceSession, 0, len(pbSessions))
+	for _, pbSession := range pbSessions {
+		sessions = append(sessions, &models.DeviceSession{
+			ID:         pbSession.Id,
+			DeviceID:   pbSession.DeviceId,
+			DeviceName: pbSession.DeviceName,
+			CreatedAt:  pbSession.CreatedAt.AsTime(),
+			LastUsedAt: pbSession.LastUsedAt.AsTime(),
+			Current:    pbSession.Current,
+		})
+	}
+	return sessions
+}
@@ -0,0 +1,30 @@
+package converter
+
+import (
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"testing"
+	"time"
+)
+
+func TestProtoToDeviceSessions(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	sessions := ProtoToDeviceSessions([]*proto.DeviceSession{
+		{
+			Id:         7,
+			DeviceId:   42,
+			DeviceName: "laptop",
+			CreatedAt:  timestamppb.New(now),
+			LastUsedAt: timestamppb.New(now),
+			Current:    true,
+		},
+	})
+
+	assert.Equal(t, []*models.DeviceSession{
+		{ID: 7, DeviceID: 42, DeviceName: "laptop", CreatedAt: now, LastUsedAt: now, Current: true},
+	}, sessions)
+	assert.Empty(t, ProtoToDeviceSessions(nil))
+}
@@ -0,0 +1,75 @@
+package converter
+
+import (
+	"beliaev-aa/GophKeeper/pkg/models"
+	"beliaev-aa/GophKeeper/pkg/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SharesToProto конвертирует список доступов к секретам из модели данных в список доступов protobuf.
+func SharesToProto(shares []*models.Share) []*proto.Share {
+	var pbShares []*proto.Share
+	for _, share := range shares {
+		pbShares = append(pbShares, ShareToProto(share))
+	}
+	return pbShares
+}
+
+// ShareToProto конвертирует доступ к секрету из модели данных в доступ protobuf.
+func ShareToProto(share *models.Share) *proto.Share {
+	return &proto.Share{
+		Id:             share.ID,
+		SecretId:       share.SecretID,
+		OwnerLogin:     share.OwnerLogin,
+		RecipientLogin: share.RecipientLogin,
+		DataKey:        share.DataKey,
+		CanEdit:        share.CanEdit,
+		CreatedAt:      timestamppb.New(share.CreatedAt),
+	}
+}
+
+// ProtoToShares конвертирует список доступов к секретам из protobuf в список доступов модели данных.
+func ProtoToShares(pbShares []*proto.Share) []*models.Share {
+	var shares []*models.Share
+	for _, share := range pbShares {
+		shares = append(shares, ProtoToShare(share))
+	}
+	return shares
+}
+
+// ProtoToShare конвертирует доступ к секрету из protobuf в доступ модели данных.
+func ProtoToShare(pbShare *proto.Share) *models.Share {
+	return &models.Share{
+		ID:             pbShare.Id,
+		SecretID:       pbShare.SecretId,
+		OwnerLogin:     pbShare.OwnerLogin,
+		RecipientLogin: pbShare.RecipientLogin,
+		DataKey:        pbShare.DataKey,
+		CanEdit:        pbShare.CanEdit,
+		CreatedAt:      pbShare.CreatedAt.AsTime(),
+	}
+}
+
+// SharedSecretsToProto конвертирует переданные пользователю секреты вместе с доступами к ним в список protobuf.
+func SharedSecretsToProto(secrets models.Secrets) []*proto.SharedSecret {
+	var pbSecrets []*proto.SharedSecret
+	for _, secret := range secrets {
+		pbSecrets = append(pbSecrets, &proto.SharedSecret{
+			Secret: SecretToProto(secret),
+			Share:  ShareToProto(secret.Share),
+		})
+	}
+	return pbSecrets
+}
+
+// ProtoToSharedSecrets конвертирует переданные пользователю секреты из protobuf в секреты модели данных
+// с заполненным доступом Share.
+func ProtoToSharedSecrets(pbSecrets []*proto.SharedSecret) models.Secrets {
+	var secrets models.Secrets
+	for _, pbSecret := range pbSecrets {
+		secret := ProtoToSecret(pbSecret.Secret)
+		secret.Share = ProtoToShare(pbSecret.Share)
+		secrets = append(secrets, secret)
+	}
+	return secrets
+}
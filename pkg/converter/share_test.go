@@ -0,0 +1,27 @@
+package converter
+
+import (
+	"beliaev-aa/GophKeeper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSharesRoundTrip(t *testing.T) {
+	shares := []*models.Share{
+		{ID: 1, SecretID: 7, OwnerLogin: "alice", RecipientLogin: "bob", DataKey: []byte("wrapped"), CanEdit: true, CreatedAt: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	assert.Equal(t, shares, ProtoToShares(SharesToProto(shares)))
+}
+
+func TestSharedSecretsRoundTrip(t *testing.T) {
+	share := &models.Share{ID: 1, SecretID: 7, OwnerLogin: "alice", RecipientLogin: "bob", CreatedAt: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)}
+	secrets := models.Secrets{{ID: 7, Title: "title", SecretType: string(models.CredSecret), Share: share}}
+
+	result := ProtoToSharedSecrets(SharedSecretsToProto(secrets))
+	assert.Len(t, result, 1)
+	assert.Equal(t, uint64(7), result[0].ID)
+	assert.Equal(t, "title", result[0].Title)
+	assert.Equal(t, share, result[0].Share)
+}
@@ -1,7 +1,41 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrNotFound = errors.New("not found")
+	// ErrInvalidBlob возникает при нарушении протокола передачи бинарного объекта:
+	// неверном идентификаторе, порядке или размере фрагментов, а также при ссылке секрета
+	// на чужой или не загруженный полностью объект.
+	ErrInvalidBlob = errors.New("invalid blob")
+	// ErrAlreadyExists возникает при создании или переименовании папки или метки в название,
+	// которое уже занято другой папкой с тем же родителем или другой меткой пользователя.
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrInvalidFolder возникает при попытке переместить папку в саму себя или во вложенную в неё папку.
+	ErrInvalidFolder = errors.New("folder cannot be moved into itself or its subfolder")
+	// ErrInvalidShare возникает при попытке передать секрет самому себе.
+	ErrInvalidShare = errors.New("secret cannot be shared with this user")
+	// ErrShareReadOnly возникает при изменении переданного секрета получателем без права изменения.
+	ErrShareReadOnly = errors.New("shared secret is read-only")
+	// ErrPermissionDenied возникает, когда роли участника организации недостаточно для действия
+	// с хранилищем организации или с её участниками.
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrLastOwner возникает при попытке удалить или понизить последнего владельца организации.
+	ErrLastOwner = errors.New("organization must keep at least one owner")
+	// ErrEmergencyState возникает при действии с экстренным доступом, недопустимом в его текущем
+	// состоянии: повторном запросе доступа или отклонении незапрошенного доступа.
+	ErrEmergencyState = errors.New("emergency access is not in a suitable state")
 )
+
+// RevisionConflictError возникает при сохранении секрета, изменённого с момента его загрузки.
+type RevisionConflictError struct {
+	// Current - текущая ревизия секрета на сервере.
+	Current uint64
+}
+
+func (e *RevisionConflictError) Error() string {
+	return fmt.Sprintf("secret was modified concurrently (current revision %d)", e.Current)
+}
@@ -0,0 +1,17 @@
+package models
+
+// BlobStatus описывает состояние загрузки бинарного объекта на сервер.
+type BlobStatus struct {
+	// ID - идентификатор объекта, сгенерированный клиентом.
+	ID string `db:"id"`
+	// ChunkCount - общее количество фрагментов объекта.
+	ChunkCount uint32 `db:"chunk_count"`
+	// ReceivedChunks - количество фрагментов, уже полученных сервером.
+	// Фрагменты принимаются строго по порядку, поэтому получены фрагменты с индексами меньше этого значения.
+	ReceivedChunks uint32 `db:"received_chunks"`
+}
+
+// Complete возвращает true, если сервер получил все фрагменты объекта.
+func (s *BlobStatus) Complete() bool {
+	return s.ReceivedChunks >= s.ChunkCount
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// EmergencyStatus - состояние экстренного доступа к хранилищу пользователя.
+type EmergencyStatus string
+
+const (
+	// EmergencyIdle - доверенный пользователь назначен, но доступ не запрашивал или запрос отклонён.
+	EmergencyIdle EmergencyStatus = "idle"
+	// EmergencyRequested - доверенный пользователь запросил доступ; владелец может отклонить запрос
+	// до истечения срока ожидания.
+	EmergencyRequested EmergencyStatus = "requested"
+	// EmergencyGranted - срок ожидания истёк, доверенный пользователь получил ключ хранилища владельца.
+	EmergencyGranted EmergencyStatus = "granted"
+)
+
+// EmergencyAccess описывает экстренный доступ доверенного пользователя к хранилищу владельца.
+type EmergencyAccess struct {
+	// ID - уникальный идентификатор экстренного доступа.
+	ID uint64 `db:"id"`
+	// GrantorID - идентификатор владельца хранилища.
+	GrantorID uint64 `db:"grantor_id"`
+	// GrantorLogin - логин владельца хранилища; к нему привязаны шифротексты его секретов.
+	GrantorLogin string `db:"grantor_login"`
+	// GranteeID - идентификатор доверенного пользователя.
+	GranteeID uint64 `db:"grantee_id"`
+	// GranteeLogin - логин доверенного пользователя.
+	GranteeLogin string `db:"grantee_login"`
+	// WaitDays - срок ожидания в днях, в течение которого владелец может отклонить запрос доступа.
+	WaitDays int `db:"wait_days"`
+	// Status - состояние доступа.
+	Status EmergencyStatus `db:"status"`
+	// VaultKey - ключ хранилища владельца, зашифрованный открытым ключом доверенного пользователя.
+	// Доверенному пользователю передаётся только после предоставления доступа.
+	VaultKey []byte `db:"vault_key"`
+	// RequestedAt - время запроса доступа; nil, если доступ не запрашивался.
+	RequestedAt *time.Time `db:"requested_at"`
+	// CreatedAt - время назначения доверенного пользователя.
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// GrantsAt возвращает время, когда запрошенный доступ будет предоставлен автоматически,
+// или нулевое время, если доступ не запрашивался.
+func (a *EmergencyAccess) GrantsAt() time.Time {
+	if a.RequestedAt == nil {
+		return time.Time{}
+	}
+	return a.RequestedAt.Add(time.Duration(a.WaitDays) * 24 * time.Hour)
+}
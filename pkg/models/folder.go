@@ -0,0 +1,75 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Folder описывает папку секретов пользователя. Папки вкладываются друг в друга и образуют дерево.
+type Folder struct {
+	// ID - уникальный идентификатор папки.
+	ID uint64 `db:"id"`
+	// UserID - идентификатор пользователя, владельца папки.
+	UserID uint64 `db:"user_id"`
+	// ParentID - идентификатор родительской папки; 0 для папки верхнего уровня.
+	ParentID uint64 `db:"parent_id"`
+	// Name - название папки, уникальное среди папок с общим родителем.
+	Name string `db:"name"`
+	// CreatedAt - время создания папки.
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Tag описывает метку секретов пользователя. Секрет может быть отмечен несколькими метками.
+type Tag struct {
+	// ID - уникальный идентификатор метки.
+	ID uint64 `db:"id"`
+	// UserID - идентификатор пользователя, владельца метки.
+	UserID uint64 `db:"user_id"`
+	// Name - название метки, уникальное среди меток пользователя.
+	Name string `db:"name"`
+	// CreatedAt - время создания метки.
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// SecretFilter ограничивает список секретов папкой и меткой. Нулевые поля не ограничивают список.
+type SecretFilter struct {
+	// FolderID - папка секретов; в список входят и секреты вложенных в неё папок.
+	FolderID uint64
+	// TagID - метка, которой отмечены секреты.
+	TagID uint64
+}
+
+// IDList - список идентификаторов, который база данных возвращает строкой через запятую.
+type IDList []uint64
+
+// Scan разбирает список идентификаторов из строки через запятую; NULL означает пустой список.
+func (l *IDList) Scan(src any) error {
+	var value string
+	switch src := src.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case string:
+		value = src
+	case []byte:
+		value = string(src)
+	default:
+		return fmt.Errorf("unsupported id list type %T", src)
+	}
+
+	var ids IDList
+	for _, part := range strings.Split(value, ",") {
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid id list %q: %w", value, err)
+		}
+		ids = append(ids, id)
+	}
+	*l = ids
+	return nil
+}
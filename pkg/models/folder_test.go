@@ -0,0 +1,35 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIDList_Scan(t *testing.T) {
+	tests := []struct {
+		name      string
+		src       any
+		expected  IDList
+		expectErr bool
+	}{
+		{name: "Null", src: nil, expected: nil},
+		{name: "String", src: "1,2,30", expected: IDList{1, 2, 30}},
+		{name: "Bytes", src: []byte("7"), expected: IDList{7}},
+		{name: "Empty", src: "", expected: nil},
+		{name: "Invalid", src: "1,x", expectErr: true},
+		{name: "UnsupportedType", src: 42, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids IDList
+			err := ids.Scan(tt.src)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("Scan() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if !tt.expectErr && !reflect.DeepEqual(ids, tt.expected) {
+				t.Errorf("Scan() got %v, want %v", ids, tt.expected)
+			}
+		})
+	}
+}
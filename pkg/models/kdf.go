@@ -0,0 +1,135 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KDFAlgorithm определяет алгоритм вывода мастер-ключа из мастер-пароля.
+type KDFAlgorithm string
+
+const (
+	// KDFScrypt - алгоритм scrypt.
+	KDFScrypt KDFAlgorithm = "scrypt"
+	// KDFArgon2id - алгоритм Argon2id.
+	KDFArgon2id KDFAlgorithm = "argon2id"
+)
+
+// Ограничения параметров KDF. Нижние границы не позволяют ослабить вывод ключа,
+// верхние - заставить клиента выполнять заведомо неподъёмные вычисления.
+const (
+	// MinKDFSaltSize - минимальный размер соли в байтах.
+	MinKDFSaltSize = 16
+	// MaxKDFSaltSize - максимальный размер соли в байтах.
+	MaxKDFSaltSize = 64
+
+	minScryptN = 1 << 15
+	maxScryptN = 1 << 20
+	minScryptR = 8
+	maxScryptR = 32
+	maxScryptP = 16
+
+	minArgon2Memory  = 19 * 1024
+	maxArgon2Memory  = 1024 * 1024
+	maxArgon2Time    = 10
+	maxArgon2Threads = 16
+)
+
+// ErrInvalidKDFParams возникает, если параметры KDF не соответствуют допустимым ограничениям.
+var ErrInvalidKDFParams = errors.New("invalid kdf params")
+
+// KDFParams описывает параметры вывода мастер-ключа пользователя.
+// Хранятся на сервере и передаются клиенту перед входом; сам мастер-пароль сервер не получает.
+type KDFParams struct {
+	// Algorithm - алгоритм KDF.
+	Algorithm KDFAlgorithm `json:"algorithm"`
+	// Salt - соль пользователя.
+	Salt []byte `json:"salt"`
+	// ScryptN - параметр стоимости scrypt (степень двойки).
+	ScryptN uint32 `json:"scrypt_n,omitempty"`
+	// ScryptR - размер блока scrypt.
+	ScryptR uint32 `json:"scrypt_r,omitempty"`
+	// ScryptP - параметр параллелизма scrypt.
+	ScryptP uint32 `json:"scrypt_p,omitempty"`
+	// Argon2Memory - объём памяти Argon2id в KiB.
+	Argon2Memory uint32 `json:"argon2_memory,omitempty"`
+	// Argon2Time - число проходов Argon2id.
+	Argon2Time uint32 `json:"argon2_time,omitempty"`
+	// Argon2Threads - степень параллелизма Argon2id.
+	Argon2Threads uint32 `json:"argon2_threads,omitempty"`
+}
+
+// LegacyKDFParams возвращает параметры, которыми выводились ключи до появления пользовательской соли:
+// scrypt с солью, полученной из логина. Используются для учётных записей, ещё не прошедших обновление.
+func LegacyKDFParams(login string) *KDFParams {
+	return &KDFParams{
+		Algorithm: KDFScrypt,
+		Salt:      []byte("gophkeeper:" + strings.ToLower(strings.TrimSpace(login))),
+		ScryptN:   minScryptN,
+		ScryptR:   minScryptR,
+		ScryptP:   1,
+	}
+}
+
+// Validate проверяет, что параметры KDF находятся в допустимых пределах.
+func (p *KDFParams) Validate() error {
+	if p == nil {
+		return fmt.Errorf("%w: params are not set", ErrInvalidKDFParams)
+	}
+	if len(p.Salt) < MinKDFSaltSize || len(p.Salt) > MaxKDFSaltSize {
+		return fmt.Errorf("%w: salt size must be between %d and %d bytes", ErrInvalidKDFParams, MinKDFSaltSize, MaxKDFSaltSize)
+	}
+	return p.validateCost()
+}
+
+// validateCost проверяет параметры стоимости выбранного алгоритма.
+func (p *KDFParams) validateCost() error {
+	switch p.Algorithm {
+	case KDFScrypt:
+		if p.ScryptN < minScryptN || p.ScryptN > maxScryptN || p.ScryptN&(p.ScryptN-1) != 0 {
+			return fmt.Errorf("%w: scrypt N must be a power of two between %d and %d", ErrInvalidKDFParams, minScryptN, maxScryptN)
+		}
+		if p.ScryptR < minScryptR || p.ScryptR > maxScryptR {
+			return fmt.Errorf("%w: scrypt r must be between %d and %d", ErrInvalidKDFParams, minScryptR, maxScryptR)
+		}
+		if p.ScryptP < 1 || p.ScryptP > maxScryptP {
+			return fmt.Errorf("%w: scrypt p must be between 1 and %d", ErrInvalidKDFParams, maxScryptP)
+		}
+	case KDFArgon2id:
+		if p.Argon2Memory < minArgon2Memory || p.Argon2Memory > maxArgon2Memory {
+			return fmt.Errorf("%w: argon2id memory must be between %d and %d KiB", ErrInvalidKDFParams, minArgon2Memory, maxArgon2Memory)
+		}
+		if p.Argon2Time < 1 || p.Argon2Time > maxArgon2Time {
+			return fmt.Errorf("%w: argon2id time must be between 1 and %d", ErrInvalidKDFParams, maxArgon2Time)
+		}
+		if p.Argon2Threads < 1 || p.Argon2Threads > maxArgon2Threads {
+			return fmt.Errorf("%w: argon2id threads must be between 1 and %d", ErrInvalidKDFParams, maxArgon2Threads)
+		}
+	default:
+		return fmt.Errorf("%w: unknown algorithm %q", ErrInvalidKDFParams, p.Algorithm)
+	}
+	return nil
+}
+
+// Value реализует интерфейс driver.Valuer для хранения параметров в колонке jsonb.
+func (p *KDFParams) Value() (driver.Value, error) {
+	if p == nil {
+		return nil, nil
+	}
+	return json.Marshal(p)
+}
+
+// Scan реализует интерфейс sql.Scanner для чтения параметров из колонки jsonb.
+func (p *KDFParams) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	default:
+		return fmt.Errorf("unsupported kdf params type %T", src)
+	}
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestKDFParams_Validate(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+
+	tests := []struct {
+		name      string
+		params    *KDFParams
+		expectErr bool
+	}{
+		{
+			name:      "Nil",
+			params:    nil,
+			expectErr: true,
+		},
+		{
+			name:      "Scrypt_Valid",
+			params:    &KDFParams{Algorithm: KDFScrypt, Salt: salt, ScryptN: 1 << 15, ScryptR: 8, ScryptP: 1},
+			expectErr: false,
+		},
+		{
+			name:      "Scrypt_WeakN",
+			params:    &KDFParams{Algorithm: KDFScrypt, Salt: salt, ScryptN: 1024, ScryptR: 8, ScryptP: 1},
+			expectErr: true,
+		},
+		{
+			name:      "Scrypt_NotPowerOfTwo",
+			params:    &KDFParams{Algorithm: KDFScrypt, Salt: salt, ScryptN: 40000, ScryptR: 8, ScryptP: 1},
+			expectErr: true,
+		},
+		{
+			name:      "Argon2id_Valid",
+			params:    &KDFParams{Algorithm: KDFArgon2id, Salt: salt, Argon2Memory: 64 * 1024, Argon2Time: 3, Argon2Threads: 4},
+			expectErr: false,
+		},
+		{
+			name:      "Argon2id_TooMuchMemory",
+			params:    &KDFParams{Algorithm: KDFArgon2id, Salt: salt, Argon2Memory: 4 * 1024 * 1024, Argon2Time: 3, Argon2Threads: 4},
+			expectErr: true,
+		},
+		{
+			name:      "Argon2id_ZeroTime",
+			params:    &KDFParams{Algorithm: KDFArgon2id, Salt: salt, Argon2Memory: 64 * 1024, Argon2Threads: 4},
+			expectErr: true,
+		},
+		{
+			name:      "ShortSalt",
+			params:    &KDFParams{Algorithm: KDFScrypt, Salt: []byte("salt"), ScryptN: 1 << 15, ScryptR: 8, ScryptP: 1},
+			expectErr: true,
+		},
+		{
+			name:      "UnknownAlgorithm",
+			params:    &KDFParams{Algorithm: "pbkdf2", Salt: salt},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.expectErr {
+				t.Errorf("Validate() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidKDFParams) {
+				t.Errorf("Validate() error = %v, want ErrInvalidKDFParams", err)
+			}
+		})
+	}
+}
+
+func TestKDFParams_ScanValue(t *testing.T) {
+	params := &KDFParams{Algorithm: KDFArgon2id, Salt: []byte("0123456789abcdef"), Argon2Memory: 65536, Argon2Time: 3, Argon2Threads: 4}
+
+	value, err := params.Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+
+	var scanned KDFParams
+	if err = scanned.Scan(value); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if scanned.Algorithm != params.Algorithm || string(scanned.Salt) != string(params.Salt) || scanned.Argon2Memory != params.Argon2Memory {
+		t.Errorf("Scan() = %+v, want %+v", scanned, params)
+	}
+
+	if err = scanned.Scan(42); err == nil {
+		t.Error("Scan() expected error for unsupported type")
+	}
+
+	var nilParams *KDFParams
+	if value, err = nilParams.Value(); value != nil || err != nil {
+		t.Errorf("Value() of nil params = %v, %v", value, err)
+	}
+}
@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// OrgRole - роль участника организации. Роли упорядочены по убыванию прав: owner, admin, editor, viewer.
+type OrgRole string
+
+const (
+	// OrgOwner - владелец: все права, включая назначение администраторов и удаление организации.
+	OrgOwner OrgRole = "owner"
+	// OrgAdmin - администратор: изменяет секреты, удаляет их из корзины и управляет редакторами и читателями.
+	OrgAdmin OrgRole = "admin"
+	// OrgEditor - редактор: создаёт, изменяет и удаляет секреты хранилища организации.
+	OrgEditor OrgRole = "editor"
+	// OrgViewer - читатель: только читает секреты хранилища организации.
+	OrgViewer OrgRole = "viewer"
+)
+
+// orgRoleRanks - ранги ролей участников: чем больше ранг, тем больше прав.
+var orgRoleRanks = map[OrgRole]int{
+	OrgViewer: 1,
+	OrgEditor: 2,
+	OrgAdmin:  3,
+	OrgOwner:  4,
+}
+
+// Valid возвращает true, если роль известна.
+func (r OrgRole) Valid() bool {
+	_, ok := orgRoleRanks[r]
+	return ok
+}
+
+// AtLeast возвращает true, если роль даёт не меньше прав, чем роль required.
+func (r OrgRole) AtLeast(required OrgRole) bool {
+	return r.Valid() && orgRoleRanks[r] >= orgRoleRanks[required]
+}
+
+// Organization описывает организацию с общим хранилищем секретов.
+type Organization struct {
+	// ID - уникальный идентификатор организации.
+	ID uint64 `db:"id"`
+	// Name - название организации.
+	Name string `db:"name"`
+	// VaultID - идентификатор служебной учётной записи, от имени которой хранятся секреты организации.
+	// Клиенту не передаётся.
+	VaultID uint64 `db:"vault_id"`
+	// Role - роль текущего пользователя в организации.
+	Role OrgRole `db:"role"`
+	// VaultKey - ключ хранилища организации, зашифрованный открытым ключом текущего пользователя.
+	VaultKey []byte `db:"vault_key"`
+	// CreatedAt - время создания организации.
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// OrgMember описывает участника организации.
+type OrgMember struct {
+	// OrgID - идентификатор организации.
+	OrgID uint64 `db:"org_id"`
+	// UserID - идентификатор участника.
+	UserID uint64 `db:"user_id"`
+	// Login - логин участника.
+	Login string `db:"login"`
+	// Role - роль участника.
+	Role OrgRole `db:"role"`
+	// VaultKey - ключ хранилища организации, зашифрованный открытым ключом участника.
+	// Передаётся только при добавлении участника.
+	VaultKey []byte `db:"vault_key"`
+	// CreatedAt - время добавления участника.
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -19,7 +19,7 @@ type Secret struct {
 	Payload []byte `db:"payload" json:"payload"`
 	// SecretType - тип секрета.
 	SecretType string `db:"secret_type" json:"secret_type"`
-	// Title - заголовок секрета.
+	// Title - заголовок секрета. Если LabelsEncrypted = true, на сервере хранится в зашифрованном виде.
 	Title string `db:"title" json:"title"`
 	// UserID - идентификатор пользователя, владельца секрета.
 	UserID int `db:"user_id"`
@@ -27,6 +27,28 @@ type Secret struct {
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 	// UpdatedAt - время последнего обновления секрета.
 	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	// Revision - ревизия секрета, увеличивающаяся при каждом изменении на сервере.
+	// Обновление с устаревшей ревизией отклоняется.
+	Revision uint64 `db:"revision" json:"revision"`
+	// DeletedAt - время перемещения секрета в корзину; nil, если секрет не удалён.
+	DeletedAt *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
+	// BlobID - идентификатор объекта с содержимым файла, если SecretType = "blob".
+	// Передаётся открыто, чтобы сервер мог удалить объект вместе с секретом.
+	BlobID string `db:"blob_id" json:"blob_id,omitempty"`
+	// FolderID - идентификатор папки секрета; 0, если секрет не лежит в папке.
+	FolderID uint64 `db:"folder_id" json:"folder_id,omitempty"`
+	// TagIDs - идентификаторы меток секрета в порядке возрастания.
+	TagIDs IDList `db:"tag_ids" json:"tag_ids,omitempty"`
+	// LabelsEncrypted - true, если заголовок и метаданные зашифрованы клиентом; false у секретов,
+	// сохранённых до шифрования заголовков.
+	LabelsEncrypted bool `db:"labels_encrypted" json:"labels_encrypted,omitempty"`
+	// DataKey - ключ данных секрета, зашифрованный ключом хранилища пользователя. Данные, заголовок
+	// и метаданные секрета шифруются ключом данных. Пустой у секретов, зашифрованных ключом из мастер-пароля
+	// напрямую.
+	DataKey []byte `db:"data_key" json:"data_key,omitempty"`
+	// SearchTokens - токены слепого индекса слов заголовка для поиска на сервере.
+	// Передаются только при сохранении и сервером не возвращаются.
+	SearchTokens []string `db:"-" json:"-"`
 
 	// Следующие поля не включаются в базу данных, только во временные операции.
 	// Creds - учетные данные, если SecretType = "credential".
@@ -37,6 +59,9 @@ type Secret struct {
 	Blob *Blob `db:"-"`
 	// Card - данные карты, если SecretType = "card".
 	Card *Card `db:"-"`
+	// Share - доступ, через который секрет другого пользователя открыт текущему пользователю;
+	// nil у собственных секретов.
+	Share *Share `db:"-" json:"-"`
 }
 
 // NewSecret создаёт новый экземпляр Secret с указанным типом секрета.
@@ -75,11 +100,22 @@ type Text struct {
 }
 
 // Blob описывает бинарные данные файла.
+// Содержимое файла хранится на сервере отдельным объектом, разбитым на зашифрованные фрагменты,
+// а в секрете остаются только ссылка на объект и ключ его фрагментов.
 type Blob struct {
 	// FileName - имя файла.
 	FileName string `json:"file_name"`
-	// FileBytes - байты файла.
-	FileBytes []byte `json:"file_bytes"`
+	// FileBytes - байты файла. Заполняется только у файлов, сохранённых целиком в секрете
+	// до появления потоковой передачи.
+	FileBytes []byte `json:"file_bytes,omitempty"`
+	// BlobID - идентификатор объекта с содержимым файла на сервере.
+	BlobID string `json:"blob_id,omitempty"`
+	// Key - ключ шифрования фрагментов объекта.
+	Key []byte `json:"key,omitempty"`
+	// Size - размер файла в байтах.
+	Size int64 `json:"size,omitempty"`
+	// ChunkCount - количество фрагментов объекта.
+	ChunkCount uint32 `json:"chunk_count,omitempty"`
 }
 
 // Card описывает данные банковской карты.
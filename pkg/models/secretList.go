@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// SecretOrder определяет порядок, в котором возвращается список секретов.
+type SecretOrder int
+
+const (
+	// SecretOrderUpdatedDesc - сначала изменённые последними. Порядок по умолчанию.
+	SecretOrderUpdatedDesc SecretOrder = iota
+	// SecretOrderUpdatedAsc - сначала изменённые раньше всех.
+	SecretOrderUpdatedAsc
+	// SecretOrderTitleAsc - по заголовку в алфавитном порядке. Сервер такой порядок не поддерживает, так как
+	// заголовки зашифрованы, поэтому клиент упорядочивает секреты каждой страницы после расшифровки.
+	SecretOrderTitleAsc
+	// SecretOrderTitleDesc - по заголовку в обратном алфавитном порядке.
+	SecretOrderTitleDesc
+)
+
+// SecretListQuery описывает запрос страницы списка секретов пользователя.
+// Пустые поля фильтра не ограничивают список.
+type SecretListQuery struct {
+	// SecretFilter - папка и метка возвращаемых секретов.
+	SecretFilter
+	// SecretType - тип возвращаемых секретов.
+	SecretType string
+	// TitlePrefix - начало заголовка возвращаемых секретов. Не передаётся на сервер: заголовки на сервере
+	// зашифрованы, поэтому клиент передаёт токены слепого индекса законченных слов начала заголовка
+	// и отбирает секреты страницы по расшифрованным заголовкам.
+	TitlePrefix string
+	// Search - слова, которые должен содержать заголовок возвращаемых секретов. Не передаётся на сервер:
+	// клиент заменяет слова токенами слепого индекса SearchTokens.
+	Search string
+	// SearchTokens - токены слепого индекса слов, которые должен содержать заголовок возвращаемых секретов.
+	SearchTokens []string
+	// UpdatedSince - возвращаются только секреты, изменённые в этот момент или позже.
+	UpdatedSince time.Time
+	// Order - порядок секретов в списке.
+	Order SecretOrder
+	// PageSize - максимальное количество секретов на странице; при нуле используется размер по умолчанию.
+	PageSize int
+	// PageToken - токен страницы, полученный с предыдущей страницей; пустой токен запрашивает первую страницу.
+	PageToken string
+}
+
+// SecretsPage описывает страницу списка секретов.
+type SecretsPage struct {
+	// Secrets - заголовки секретов: все поля, кроме зашифрованных данных Payload.
+	Secrets Secrets
+	// NextPageToken - токен следующей страницы; пустой, если страница последняя.
+	NextPageToken string
+}
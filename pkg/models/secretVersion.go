@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// SecretVersion описывает предыдущую версию секрета, сохранённую при его изменении.
+type SecretVersion struct {
+	// Secret - состояние секрета на момент сохранения версии. ID совпадает с ID исходного секрета,
+	// а UpdatedAt - время, когда это состояние было записано.
+	Secret
+	// VersionID - уникальный идентификатор версии.
+	VersionID uint64 `db:"version_id" json:"version_id"`
+	// ArchivedAt - время, когда версия была заменена более новой и перенесена в историю.
+	ArchivedAt time.Time `db:"archived_at" json:"archived_at"`
+}
@@ -0,0 +1,14 @@
+package models
+
+// SecretsDelta описывает изменения секретов пользователя после ревизии хранилища, известной клиенту.
+type SecretsDelta struct {
+	// Secrets - созданные, изменённые или восстановленные из корзины секреты.
+	Secrets Secrets
+	// DeletedIDs - идентификаторы секретов, перемещённых в корзину или удалённых окончательно.
+	DeletedIDs []uint64
+	// Revision - ревизия хранилища, начиная с которой следует запросить следующие изменения.
+	Revision uint64
+	// Full - true, если Secrets содержит все секреты пользователя и известные клиенту секреты
+	// следует заменить ими: например, при первой синхронизации или если ревизия клиента неизвестна серверу.
+	Full bool
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// DeviceSession описывает активную сессию пользователя на одном из его устройств.
+type DeviceSession struct {
+	// ID - идентификатор сессии.
+	ID uint64
+	// DeviceID - постоянный идентификатор устройства.
+	DeviceID uint64
+	// DeviceName - имя устройства.
+	DeviceName string
+	// CreatedAt - время входа на устройстве.
+	CreatedAt time.Time
+	// LastUsedAt - время последнего обновления токенов сессии.
+	LastUsedAt time.Time
+	// Current - признак сессии, из которой выполнен запрос.
+	Current bool
+}
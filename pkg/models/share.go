@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// KeyPair описывает пару ключей X25519 пользователя, которой другие пользователи передают ему секреты.
+type KeyPair struct {
+	// PublicKey - открытый ключ; им другие пользователи шифруют ключи данных передаваемых секретов.
+	PublicKey []byte `db:"public_key"`
+	// PrivateKey - закрытый ключ, зашифрованный ключом хранилища пользователя.
+	PrivateKey []byte `db:"private_key"`
+}
+
+// Share описывает доступ пользователя к секрету другого пользователя.
+type Share struct {
+	// ID - уникальный идентификатор доступа.
+	ID uint64 `db:"id"`
+	// SecretID - идентификатор переданного секрета.
+	SecretID uint64 `db:"secret_id"`
+	// OwnerID - идентификатор владельца секрета.
+	OwnerID uint64 `db:"owner_id"`
+	// OwnerLogin - логин владельца секрета; к нему привязаны шифротексты секрета.
+	OwnerLogin string `db:"owner_login"`
+	// RecipientID - идентификатор пользователя, получившего доступ.
+	RecipientID uint64 `db:"recipient_id"`
+	// RecipientLogin - логин пользователя, получившего доступ.
+	RecipientLogin string `db:"recipient_login"`
+	// DataKey - ключ данных секрета, зашифрованный открытым ключом получателя.
+	DataKey []byte `db:"data_key"`
+	// CanEdit - true, если получатель может изменять секрет; иначе секрет доступен только для чтения.
+	CanEdit bool `db:"can_edit"`
+	// CreatedAt - время предоставления доступа.
+	CreatedAt time.Time `db:"created_at"`
+	// OwnerPublicKey - открытый ключ владельца, которым получатель проверяет, что ключ данных зашифровал
+	// владелец. Заполняется клиентом получателя и не хранится на сервере.
+	OwnerPublicKey []byte `db:"-"`
+}
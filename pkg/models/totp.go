@@ -0,0 +1,9 @@
+package models
+
+// TOTPSetup содержит данные для добавления секрета второго фактора в приложение-аутентификатор.
+type TOTPSetup struct {
+	// Secret - секрет в кодировке base32 для ручного ввода.
+	Secret string
+	// URI - ссылка otpauth:// для QR-кода.
+	URI string
+}
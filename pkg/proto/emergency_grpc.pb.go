@@ -0,0 +1,330 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.2
+// source: emergency.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Emergency_AddContact_FullMethodName    = "/proto.Emergency/AddContact"
+	Emergency_ListContacts_FullMethodName  = "/proto.Emergency/ListContacts"
+	Emergency_ListGrants_FullMethodName    = "/proto.Emergency/ListGrants"
+	Emergency_RequestAccess_FullMethodName = "/proto.Emergency/RequestAccess"
+	Emergency_RejectAccess_FullMethodName  = "/proto.Emergency/RejectAccess"
+	Emergency_DeleteAccess_FullMethodName  = "/proto.Emergency/DeleteAccess"
+)
+
+// EmergencyClient is the client API for Emergency service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Секреты владельца читаются через сервис Secrets с идентификатором предоставленного доступа
+// в заголовке X-Emergency-Access-ID; изменять их доверенный пользователь не может.
+type EmergencyClient interface {
+	// Назначение доверенного пользователя владельцем хранилища.
+	AddContact(ctx context.Context, in *AddEmergencyContactRequest, opts ...grpc.CallOption) (*AddEmergencyContactResponse, error)
+	// Доверенные пользователи текущего пользователя.
+	ListContacts(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListEmergencyAccessResponse, error)
+	// Хранилища, владельцы которых назначили текущего пользователя доверенным.
+	ListGrants(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListEmergencyAccessResponse, error)
+	// Запрос доступа доверенным пользователем.
+	RequestAccess(ctx context.Context, in *EmergencyAccessRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Отклонение запроса или отзыв предоставленного доступа владельцем.
+	RejectAccess(ctx context.Context, in *EmergencyAccessRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Удаление доступа владельцем или доверенным пользователем.
+	DeleteAccess(ctx context.Context, in *EmergencyAccessRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type emergencyClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEmergencyClient(cc grpc.ClientConnInterface) EmergencyClient {
+	return &emergencyClient{cc}
+}
+
+func (c *emergencyClient) AddContact(ctx context.Context, in *AddEmergencyContactRequest, opts ...grpc.CallOption) (*AddEmergencyContactResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddEmergencyContactResponse)
+	err := c.cc.Invoke(ctx, Emergency_AddContact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emergencyClient) ListContacts(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListEmergencyAccessResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEmergencyAccessResponse)
+	err := c.cc.Invoke(ctx, Emergency_ListContacts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emergencyClient) ListGrants(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListEmergencyAccessResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEmergencyAccessResponse)
+	err := c.cc.Invoke(ctx, Emergency_ListGrants_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emergencyClient) RequestAccess(ctx context.Context, in *EmergencyAccessRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Emergency_RequestAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emergencyClient) RejectAccess(ctx context.Context, in *EmergencyAccessRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Emergency_RejectAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emergencyClient) DeleteAccess(ctx context.Context, in *EmergencyAccessRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Emergency_DeleteAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EmergencyServer is the server API for Emergency service.
+// All implementations must embed UnimplementedEmergencyServer
+// for forward compatibility.
+//
+// Секреты владельца читаются через сервис Secrets с идентификатором предоставленного доступа
+// в заголовке X-Emergency-Access-ID; изменять их доверенный пользователь не может.
+type EmergencyServer interface {
+	// Назначение доверенного пользователя владельцем хранилища.
+	AddContact(context.Context, *AddEmergencyContactRequest) (*AddEmergencyContactResponse, error)
+	// Доверенные пользователи текущего пользователя.
+	ListContacts(context.Context, *emptypb.Empty) (*ListEmergencyAccessResponse, error)
+	// Хранилища, владельцы которых назначили текущего пользователя доверенным.
+	ListGrants(context.Context, *emptypb.Empty) (*ListEmergencyAccessResponse, error)
+	// Запрос доступа доверенным пользователем.
+	RequestAccess(context.Context, *EmergencyAccessRequest) (*emptypb.Empty, error)
+	// Отклонение запроса или отзыв предоставленного доступа владельцем.
+	RejectAccess(context.Context, *EmergencyAccessRequest) (*emptypb.Empty, error)
+	// Удаление доступа владельцем или доверенным пользователем.
+	DeleteAccess(context.Context, *EmergencyAccessRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedEmergencyServer()
+}
+
+// UnimplementedEmergencyServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedEmergencyServer struct{}
+
+func (UnimplementedEmergencyServer) AddContact(context.Context, *AddEmergencyContactRequest) (*AddEmergencyContactResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddContact not implemented")
+}
+func (UnimplementedEmergencyServer) ListContacts(context.Context, *emptypb.Empty) (*ListEmergencyAccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListContacts not implemented")
+}
+func (UnimplementedEmergencyServer) ListGrants(context.Context, *emptypb.Empty) (*ListEmergencyAccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGrants not implemented")
+}
+func (UnimplementedEmergencyServer) RequestAccess(context.Context, *EmergencyAccessRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestAccess not implemented")
+}
+func (UnimplementedEmergencyServer) RejectAccess(context.Context, *EmergencyAccessRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectAccess not implemented")
+}
+func (UnimplementedEmergencyServer) DeleteAccess(context.Context, *EmergencyAccessRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAccess not implemented")
+}
+func (UnimplementedEmergencyServer) mustEmbedUnimplementedEmergencyServer() {}
+func (UnimplementedEmergencyServer) testEmbeddedByValue()                   {}
+
+// UnsafeEmergencyServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EmergencyServer will
+// result in compilation errors.
+type UnsafeEmergencyServer interface {
+	mustEmbedUnimplementedEmergencyServer()
+}
+
+func RegisterEmergencyServer(s grpc.ServiceRegistrar, srv EmergencyServer) {
+	// If the following call pancis, it indicates UnimplementedEmergencyServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Emergency_ServiceDesc, srv)
+}
+
+func _Emergency_AddContact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddEmergencyContactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyServer).AddContact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Emergency_AddContact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyServer).AddContact(ctx, req.(*AddEmergencyContactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Emergency_ListContacts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyServer).ListContacts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Emergency_ListContacts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyServer).ListContacts(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Emergency_ListGrants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyServer).ListGrants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Emergency_ListGrants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyServer).ListGrants(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Emergency_RequestAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmergencyAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyServer).RequestAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Emergency_RequestAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyServer).RequestAccess(ctx, req.(*EmergencyAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Emergency_RejectAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmergencyAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyServer).RejectAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Emergency_RejectAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyServer).RejectAccess(ctx, req.(*EmergencyAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Emergency_DeleteAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmergencyAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyServer).DeleteAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Emergency_DeleteAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyServer).DeleteAccess(ctx, req.(*EmergencyAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Emergency_ServiceDesc is the grpc.ServiceDesc for Emergency service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Emergency_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.Emergency",
+	HandlerType: (*EmergencyServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddContact",
+			Handler:    _Emergency_AddContact_Handler,
+		},
+		{
+			MethodName: "ListContacts",
+			Handler:    _Emergency_ListContacts_Handler,
+		},
+		{
+			MethodName: "ListGrants",
+			Handler:    _Emergency_ListGrants_Handler,
+		},
+		{
+			MethodName: "RequestAccess",
+			Handler:    _Emergency_RequestAccess_Handler,
+		},
+		{
+			MethodName: "RejectAccess",
+			Handler:    _Emergency_RejectAccess_Handler,
+		},
+		{
+			MethodName: "DeleteAccess",
+			Handler:    _Emergency_DeleteAccess_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "emergency.proto",
+}
@@ -20,20 +20,38 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Secrets_GetUserSecrets_FullMethodName   = "/proto.Secrets/GetUserSecrets"
-	Secrets_GetUserSecret_FullMethodName    = "/proto.Secrets/GetUserSecret"
-	Secrets_SaveUserSecret_FullMethodName   = "/proto.Secrets/SaveUserSecret"
-	Secrets_DeleteUserSecret_FullMethodName = "/proto.Secrets/DeleteUserSecret"
+	Secrets_GetUserSecrets_FullMethodName       = "/proto.Secrets/GetUserSecrets"
+	Secrets_SyncSecrets_FullMethodName          = "/proto.Secrets/SyncSecrets"
+	Secrets_ListSecrets_FullMethodName          = "/proto.Secrets/ListSecrets"
+	Secrets_GetUserSecret_FullMethodName        = "/proto.Secrets/GetUserSecret"
+	Secrets_SaveUserSecret_FullMethodName       = "/proto.Secrets/SaveUserSecret"
+	Secrets_DeleteUserSecret_FullMethodName     = "/proto.Secrets/DeleteUserSecret"
+	Secrets_ListSecretVersions_FullMethodName   = "/proto.Secrets/ListSecretVersions"
+	Secrets_RestoreSecretVersion_FullMethodName = "/proto.Secrets/RestoreSecretVersion"
+	Secrets_ListTrash_FullMethodName            = "/proto.Secrets/ListTrash"
+	Secrets_RestoreSecret_FullMethodName        = "/proto.Secrets/RestoreSecret"
+	Secrets_PurgeSecret_FullMethodName          = "/proto.Secrets/PurgeSecret"
+	Secrets_EncryptSecretLabels_FullMethodName  = "/proto.Secrets/EncryptSecretLabels"
+	Secrets_ReserveSecretID_FullMethodName      = "/proto.Secrets/ReserveSecretID"
 )
 
 // SecretsClient is the client API for Secrets service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type SecretsClient interface {
-	GetUserSecrets(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetUserSecretsResponse, error)
+	GetUserSecrets(ctx context.Context, in *GetUserSecretsRequest, opts ...grpc.CallOption) (*GetUserSecretsResponse, error)
+	SyncSecrets(ctx context.Context, in *SyncSecretsRequest, opts ...grpc.CallOption) (*SyncSecretsResponse, error)
+	ListSecrets(ctx context.Context, in *ListSecretsRequest, opts ...grpc.CallOption) (*ListSecretsResponse, error)
 	GetUserSecret(ctx context.Context, in *GetUserSecretRequest, opts ...grpc.CallOption) (*GetUserSecretResponse, error)
 	SaveUserSecret(ctx context.Context, in *SaveUserSecretRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	DeleteUserSecret(ctx context.Context, in *DeleteUserSecretRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListSecretVersions(ctx context.Context, in *ListSecretVersionsRequest, opts ...grpc.CallOption) (*ListSecretVersionsResponse, error)
+	RestoreSecretVersion(ctx context.Context, in *RestoreSecretVersionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListTrash(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListTrashResponse, error)
+	RestoreSecret(ctx context.Context, in *RestoreSecretRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	PurgeSecret(ctx context.Context, in *PurgeSecretRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	EncryptSecretLabels(ctx context.Context, in *EncryptSecretLabelsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ReserveSecretID(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ReserveSecretIDResponse, error)
 }
 
 type secretsClient struct {
@@ -44,7 +62,7 @@ func NewSecretsClient(cc grpc.ClientConnInterface) SecretsClient {
 	return &secretsClient{cc}
 }
 
-func (c *secretsClient) GetUserSecrets(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetUserSecretsResponse, error) {
+func (c *secretsClient) GetUserSecrets(ctx context.Context, in *GetUserSecretsRequest, opts ...grpc.CallOption) (*GetUserSecretsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetUserSecretsResponse)
 	err := c.cc.Invoke(ctx, Secrets_GetUserSecrets_FullMethodName, in, out, cOpts...)
@@ -54,6 +72,26 @@ func (c *secretsClient) GetUserSecrets(ctx context.Context, in *emptypb.Empty, o
 	return out, nil
 }
 
+func (c *secretsClient) SyncSecrets(ctx context.Context, in *SyncSecretsRequest, opts ...grpc.CallOption) (*SyncSecretsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SyncSecretsResponse)
+	err := c.cc.Invoke(ctx, Secrets_SyncSecrets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secretsClient) ListSecrets(ctx context.Context, in *ListSecretsRequest, opts ...grpc.CallOption) (*ListSecretsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSecretsResponse)
+	err := c.cc.Invoke(ctx, Secrets_ListSecrets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *secretsClient) GetUserSecret(ctx context.Context, in *GetUserSecretRequest, opts ...grpc.CallOption) (*GetUserSecretResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetUserSecretResponse)
@@ -84,14 +122,93 @@ func (c *secretsClient) DeleteUserSecret(ctx context.Context, in *DeleteUserSecr
 	return out, nil
 }
 
+func (c *secretsClient) ListSecretVersions(ctx context.Context, in *ListSecretVersionsRequest, opts ...grpc.CallOption) (*ListSecretVersionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSecretVersionsResponse)
+	err := c.cc.Invoke(ctx, Secrets_ListSecretVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secretsClient) RestoreSecretVersion(ctx context.Context, in *RestoreSecretVersionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Secrets_RestoreSecretVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secretsClient) ListTrash(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListTrashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTrashResponse)
+	err := c.cc.Invoke(ctx, Secrets_ListTrash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secretsClient) RestoreSecret(ctx context.Context, in *RestoreSecretRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Secrets_RestoreSecret_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secretsClient) PurgeSecret(ctx context.Context, in *PurgeSecretRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Secrets_PurgeSecret_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secretsClient) EncryptSecretLabels(ctx context.Context, in *EncryptSecretLabelsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Secrets_EncryptSecretLabels_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secretsClient) ReserveSecretID(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ReserveSecretIDResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReserveSecretIDResponse)
+	err := c.cc.Invoke(ctx, Secrets_ReserveSecretID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SecretsServer is the server API for Secrets service.
 // All implementations must embed UnimplementedSecretsServer
 // for forward compatibility.
 type SecretsServer interface {
-	GetUserSecrets(context.Context, *emptypb.Empty) (*GetUserSecretsResponse, error)
+	GetUserSecrets(context.Context, *GetUserSecretsRequest) (*GetUserSecretsResponse, error)
+	SyncSecrets(context.Context, *SyncSecretsRequest) (*SyncSecretsResponse, error)
+	ListSecrets(context.Context, *ListSecretsRequest) (*ListSecretsResponse, error)
 	GetUserSecret(context.Context, *GetUserSecretRequest) (*GetUserSecretResponse, error)
 	SaveUserSecret(context.Context, *SaveUserSecretRequest) (*emptypb.Empty, error)
 	DeleteUserSecret(context.Context, *DeleteUserSecretRequest) (*emptypb.Empty, error)
+	ListSecretVersions(context.Context, *ListSecretVersionsRequest) (*ListSecretVersionsResponse, error)
+	RestoreSecretVersion(context.Context, *RestoreSecretVersionRequest) (*emptypb.Empty, error)
+	ListTrash(context.Context, *emptypb.Empty) (*ListTrashResponse, error)
+	RestoreSecret(context.Context, *RestoreSecretRequest) (*emptypb.Empty, error)
+	PurgeSecret(context.Context, *PurgeSecretRequest) (*emptypb.Empty, error)
+	EncryptSecretLabels(context.Context, *EncryptSecretLabelsRequest) (*emptypb.Empty, error)
+	ReserveSecretID(context.Context, *emptypb.Empty) (*ReserveSecretIDResponse, error)
 	mustEmbedUnimplementedSecretsServer()
 }
 
@@ -102,9 +219,15 @@ type SecretsServer interface {
 // pointer dereference when methods are called.
 type UnimplementedSecretsServer struct{}
 
-func (UnimplementedSecretsServer) GetUserSecrets(context.Context, *emptypb.Empty) (*GetUserSecretsResponse, error) {
+func (UnimplementedSecretsServer) GetUserSecrets(context.Context, *GetUserSecretsRequest) (*GetUserSecretsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUserSecrets not implemented")
 }
+func (UnimplementedSecretsServer) SyncSecrets(context.Context, *SyncSecretsRequest) (*SyncSecretsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncSecrets not implemented")
+}
+func (UnimplementedSecretsServer) ListSecrets(context.Context, *ListSecretsRequest) (*ListSecretsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSecrets not implemented")
+}
 func (UnimplementedSecretsServer) GetUserSecret(context.Context, *GetUserSecretRequest) (*GetUserSecretResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUserSecret not implemented")
 }
@@ -114,6 +237,27 @@ func (UnimplementedSecretsServer) SaveUserSecret(context.Context, *SaveUserSecre
 func (UnimplementedSecretsServer) DeleteUserSecret(context.Context, *DeleteUserSecretRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteUserSecret not implemented")
 }
+func (UnimplementedSecretsServer) ListSecretVersions(context.Context, *ListSecretVersionsRequest) (*ListSecretVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSecretVersions not implemented")
+}
+func (UnimplementedSecretsServer) RestoreSecretVersion(context.Context, *RestoreSecretVersionRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreSecretVersion not implemented")
+}
+func (UnimplementedSecretsServer) ListTrash(context.Context, *emptypb.Empty) (*ListTrashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTrash not implemented")
+}
+func (UnimplementedSecretsServer) RestoreSecret(context.Context, *RestoreSecretRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreSecret not implemented")
+}
+func (UnimplementedSecretsServer) PurgeSecret(context.Context, *PurgeSecretRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeSecret not implemented")
+}
+func (UnimplementedSecretsServer) EncryptSecretLabels(context.Context, *EncryptSecretLabelsRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EncryptSecretLabels not implemented")
+}
+func (UnimplementedSecretsServer) ReserveSecretID(context.Context, *emptypb.Empty) (*ReserveSecretIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveSecretID not implemented")
+}
 func (UnimplementedSecretsServer) mustEmbedUnimplementedSecretsServer() {}
 func (UnimplementedSecretsServer) testEmbeddedByValue()                 {}
 
@@ -136,7 +280,7 @@ func RegisterSecretsServer(s grpc.ServiceRegistrar, srv SecretsServer) {
 }
 
 func _Secrets_GetUserSecrets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(emptypb.Empty)
+	in := new(GetUserSecretsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -148,7 +292,43 @@ func _Secrets_GetUserSecrets_Handler(srv interface{}, ctx context.Context, dec f
 		FullMethod: Secrets_GetUserSecrets_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SecretsServer).GetUserSecrets(ctx, req.(*emptypb.Empty))
+		return srv.(SecretsServer).GetUserSecrets(ctx, req.(*GetUserSecretsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Secrets_SyncSecrets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncSecretsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretsServer).SyncSecrets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Secrets_SyncSecrets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretsServer).SyncSecrets(ctx, req.(*SyncSecretsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Secrets_ListSecrets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSecretsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretsServer).ListSecrets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Secrets_ListSecrets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretsServer).ListSecrets(ctx, req.(*ListSecretsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -207,6 +387,132 @@ func _Secrets_DeleteUserSecret_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Secrets_ListSecretVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSecretVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretsServer).ListSecretVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Secrets_ListSecretVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretsServer).ListSecretVersions(ctx, req.(*ListSecretVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Secrets_RestoreSecretVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreSecretVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretsServer).RestoreSecretVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Secrets_RestoreSecretVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretsServer).RestoreSecretVersion(ctx, req.(*RestoreSecretVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Secrets_ListTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretsServer).ListTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Secrets_ListTrash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretsServer).ListTrash(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Secrets_RestoreSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretsServer).RestoreSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Secrets_RestoreSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretsServer).RestoreSecret(ctx, req.(*RestoreSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Secrets_PurgeSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretsServer).PurgeSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Secrets_PurgeSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretsServer).PurgeSecret(ctx, req.(*PurgeSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Secrets_EncryptSecretLabels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EncryptSecretLabelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretsServer).EncryptSecretLabels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Secrets_EncryptSecretLabels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretsServer).EncryptSecretLabels(ctx, req.(*EncryptSecretLabelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Secrets_ReserveSecretID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretsServer).ReserveSecretID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Secrets_ReserveSecretID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretsServer).ReserveSecretID(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Secrets_ServiceDesc is the grpc.ServiceDesc for Secrets service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -218,6 +524,14 @@ var Secrets_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetUserSecrets",
 			Handler:    _Secrets_GetUserSecrets_Handler,
 		},
+		{
+			MethodName: "SyncSecrets",
+			Handler:    _Secrets_SyncSecrets_Handler,
+		},
+		{
+			MethodName: "ListSecrets",
+			Handler:    _Secrets_ListSecrets_Handler,
+		},
 		{
 			MethodName: "GetUserSecret",
 			Handler:    _Secrets_GetUserSecret_Handler,
@@ -230,6 +544,34 @@ var Secrets_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteUserSecret",
 			Handler:    _Secrets_DeleteUserSecret_Handler,
 		},
+		{
+			MethodName: "ListSecretVersions",
+			Handler:    _Secrets_ListSecretVersions_Handler,
+		},
+		{
+			MethodName: "RestoreSecretVersion",
+			Handler:    _Secrets_RestoreSecretVersion_Handler,
+		},
+		{
+			MethodName: "ListTrash",
+			Handler:    _Secrets_ListTrash_Handler,
+		},
+		{
+			MethodName: "RestoreSecret",
+			Handler:    _Secrets_RestoreSecret_Handler,
+		},
+		{
+			MethodName: "PurgeSecret",
+			Handler:    _Secrets_PurgeSecret_Handler,
+		},
+		{
+			MethodName: "EncryptSecretLabels",
+			Handler:    _Secrets_EncryptSecretLabels_Handler,
+		},
+		{
+			MethodName: "ReserveSecretID",
+			Handler:    _Secrets_ReserveSecretID_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "secrets.proto",
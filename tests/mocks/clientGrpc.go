@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	crypto "beliaev-aa/GophKeeper/internal/client/crypto"
 	models "beliaev-aa/GophKeeper/pkg/models"
 	context "context"
 	reflect "reflect"
@@ -37,6 +38,194 @@ func (m *MockClientGRPCInterface) EXPECT() *MockClientGRPCInterfaceMockRecorder
 	return m.recorder
 }
 
+// AddEmergencyContact mocks base method.
+func (m *MockClientGRPCInterface) AddEmergencyContact(ctx context.Context, access *models.EmergencyAccess) (*models.EmergencyAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddEmergencyContact", ctx, access)
+	ret0, _ := ret[0].(*models.EmergencyAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddEmergencyContact indicates an expected call of AddEmergencyContact.
+func (mr *MockClientGRPCInterfaceMockRecorder) AddEmergencyContact(ctx, access interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEmergencyContact", reflect.TypeOf((*MockClientGRPCInterface)(nil).AddEmergencyContact), ctx, access)
+}
+
+// AddOrgMember mocks base method.
+func (m *MockClientGRPCInterface) AddOrgMember(ctx context.Context, member *models.OrgMember) (*models.OrgMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOrgMember", ctx, member)
+	ret0, _ := ret[0].(*models.OrgMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddOrgMember indicates an expected call of AddOrgMember.
+func (mr *MockClientGRPCInterfaceMockRecorder) AddOrgMember(ctx, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOrgMember", reflect.TypeOf((*MockClientGRPCInterface)(nil).AddOrgMember), ctx, member)
+}
+
+// ChangePassword mocks base method.
+func (m *MockClientGRPCInterface) ChangePassword(ctx context.Context, currentAuthHash string, params *models.KDFParams, keys *crypto.Keys, vaultKey []byte, secrets map[uint64]*models.Secret) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, currentAuthHash, params, keys, vaultKey, secrets)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockClientGRPCInterfaceMockRecorder) ChangePassword(ctx, currentAuthHash, params, keys, vaultKey, secrets interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockClientGRPCInterface)(nil).ChangePassword), ctx, currentAuthHash, params, keys, vaultKey, secrets)
+}
+
+// ConfirmTOTP mocks base method.
+func (m *MockClientGRPCInterface) ConfirmTOTP(ctx context.Context, code string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTOTP", ctx, code)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTOTP indicates an expected call of ConfirmTOTP.
+func (mr *MockClientGRPCInterfaceMockRecorder) ConfirmTOTP(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTOTP", reflect.TypeOf((*MockClientGRPCInterface)(nil).ConfirmTOTP), ctx, code)
+}
+
+// CreateFolder mocks base method.
+func (m *MockClientGRPCInterface) CreateFolder(ctx context.Context, parentID uint64, name string) (*models.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFolder", ctx, parentID, name)
+	ret0, _ := ret[0].(*models.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFolder indicates an expected call of CreateFolder.
+func (mr *MockClientGRPCInterfaceMockRecorder) CreateFolder(ctx, parentID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFolder", reflect.TypeOf((*MockClientGRPCInterface)(nil).CreateFolder), ctx, parentID, name)
+}
+
+// CreateKeyPair mocks base method.
+func (m *MockClientGRPCInterface) CreateKeyPair(ctx context.Context, keyPair *models.KeyPair) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateKeyPair", ctx, keyPair)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateKeyPair indicates an expected call of CreateKeyPair.
+func (mr *MockClientGRPCInterfaceMockRecorder) CreateKeyPair(ctx, keyPair interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKeyPair", reflect.TypeOf((*MockClientGRPCInterface)(nil).CreateKeyPair), ctx, keyPair)
+}
+
+// CreateOrganization mocks base method.
+func (m *MockClientGRPCInterface) CreateOrganization(ctx context.Context, name string, vaultKey []byte) (*models.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrganization", ctx, name, vaultKey)
+	ret0, _ := ret[0].(*models.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrganization indicates an expected call of CreateOrganization.
+func (mr *MockClientGRPCInterfaceMockRecorder) CreateOrganization(ctx, name, vaultKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrganization", reflect.TypeOf((*MockClientGRPCInterface)(nil).CreateOrganization), ctx, name, vaultKey)
+}
+
+// CreateTag mocks base method.
+func (m *MockClientGRPCInterface) CreateTag(ctx context.Context, name string) (*models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTag", ctx, name)
+	ret0, _ := ret[0].(*models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTag indicates an expected call of CreateTag.
+func (mr *MockClientGRPCInterfaceMockRecorder) CreateTag(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTag", reflect.TypeOf((*MockClientGRPCInterface)(nil).CreateTag), ctx, name)
+}
+
+// CreateVaultKey mocks base method.
+func (m *MockClientGRPCInterface) CreateVaultKey(ctx context.Context, vaultKey []byte, secrets map[uint64]*models.Secret) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVaultKey", ctx, vaultKey, secrets)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateVaultKey indicates an expected call of CreateVaultKey.
+func (mr *MockClientGRPCInterfaceMockRecorder) CreateVaultKey(ctx, vaultKey, secrets interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVaultKey", reflect.TypeOf((*MockClientGRPCInterface)(nil).CreateVaultKey), ctx, vaultKey, secrets)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockClientGRPCInterface) DeleteAccount(ctx context.Context, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", ctx, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockClientGRPCInterfaceMockRecorder) DeleteAccount(ctx, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockClientGRPCInterface)(nil).DeleteAccount), ctx, password)
+}
+
+// DeleteEmergencyAccess mocks base method.
+func (m *MockClientGRPCInterface) DeleteEmergencyAccess(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEmergencyAccess", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEmergencyAccess indicates an expected call of DeleteEmergencyAccess.
+func (mr *MockClientGRPCInterfaceMockRecorder) DeleteEmergencyAccess(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEmergencyAccess", reflect.TypeOf((*MockClientGRPCInterface)(nil).DeleteEmergencyAccess), ctx, id)
+}
+
+// DeleteFolder mocks base method.
+func (m *MockClientGRPCInterface) DeleteFolder(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFolder", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFolder indicates an expected call of DeleteFolder.
+func (mr *MockClientGRPCInterfaceMockRecorder) DeleteFolder(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFolder", reflect.TypeOf((*MockClientGRPCInterface)(nil).DeleteFolder), ctx, id)
+}
+
+// DeleteOrganization mocks base method.
+func (m *MockClientGRPCInterface) DeleteOrganization(ctx context.Context, orgID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOrganization", ctx, orgID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOrganization indicates an expected call of DeleteOrganization.
+func (mr *MockClientGRPCInterfaceMockRecorder) DeleteOrganization(ctx, orgID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrganization", reflect.TypeOf((*MockClientGRPCInterface)(nil).DeleteOrganization), ctx, orgID)
+}
+
 // DeleteSecret mocks base method.
 func (m *MockClientGRPCInterface) DeleteSecret(ctx context.Context, id uint64) error {
 	m.ctrl.T.Helper()
@@ -51,6 +240,149 @@ func (mr *MockClientGRPCInterfaceMockRecorder) DeleteSecret(ctx, id interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecret", reflect.TypeOf((*MockClientGRPCInterface)(nil).DeleteSecret), ctx, id)
 }
 
+// DeleteTag mocks base method.
+func (m *MockClientGRPCInterface) DeleteTag(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTag", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTag indicates an expected call of DeleteTag.
+func (mr *MockClientGRPCInterfaceMockRecorder) DeleteTag(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockClientGRPCInterface)(nil).DeleteTag), ctx, id)
+}
+
+// DisableTOTP mocks base method.
+func (m *MockClientGRPCInterface) DisableTOTP(ctx context.Context, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTOTP", ctx, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTOTP indicates an expected call of DisableTOTP.
+func (mr *MockClientGRPCInterfaceMockRecorder) DisableTOTP(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTP", reflect.TypeOf((*MockClientGRPCInterface)(nil).DisableTOTP), ctx, code)
+}
+
+// DownloadBlob mocks base method.
+func (m *MockClientGRPCInterface) DownloadBlob(ctx context.Context, blobID string, from uint32, handle func(uint32, uint32, []byte) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadBlob", ctx, blobID, from, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DownloadBlob indicates an expected call of DownloadBlob.
+func (mr *MockClientGRPCInterfaceMockRecorder) DownloadBlob(ctx, blobID, from, handle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadBlob", reflect.TypeOf((*MockClientGRPCInterface)(nil).DownloadBlob), ctx, blobID, from, handle)
+}
+
+// EnableTOTP mocks base method.
+func (m *MockClientGRPCInterface) EnableTOTP(ctx context.Context) (*models.TOTPSetup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableTOTP", ctx)
+	ret0, _ := ret[0].(*models.TOTPSetup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableTOTP indicates an expected call of EnableTOTP.
+func (mr *MockClientGRPCInterfaceMockRecorder) EnableTOTP(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableTOTP", reflect.TypeOf((*MockClientGRPCInterface)(nil).EnableTOTP), ctx)
+}
+
+// EncryptSecretLabels mocks base method.
+func (m *MockClientGRPCInterface) EncryptSecretLabels(ctx context.Context, secrets []*models.Secret) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EncryptSecretLabels", ctx, secrets)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EncryptSecretLabels indicates an expected call of EncryptSecretLabels.
+func (mr *MockClientGRPCInterfaceMockRecorder) EncryptSecretLabels(ctx, secrets interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptSecretLabels", reflect.TypeOf((*MockClientGRPCInterface)(nil).EncryptSecretLabels), ctx, secrets)
+}
+
+// GetBlobStatus mocks base method.
+func (m *MockClientGRPCInterface) GetBlobStatus(ctx context.Context, blobID string) (*models.BlobStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlobStatus", ctx, blobID)
+	ret0, _ := ret[0].(*models.BlobStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlobStatus indicates an expected call of GetBlobStatus.
+func (mr *MockClientGRPCInterfaceMockRecorder) GetBlobStatus(ctx, blobID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlobStatus", reflect.TypeOf((*MockClientGRPCInterface)(nil).GetBlobStatus), ctx, blobID)
+}
+
+// GetEncryptionKey mocks base method.
+func (m *MockClientGRPCInterface) GetEncryptionKey() []byte {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEncryptionKey")
+	ret0, _ := ret[0].([]byte)
+	return ret0
+}
+
+// GetEncryptionKey indicates an expected call of GetEncryptionKey.
+func (mr *MockClientGRPCInterfaceMockRecorder) GetEncryptionKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEncryptionKey", reflect.TypeOf((*MockClientGRPCInterface)(nil).GetEncryptionKey))
+}
+
+// GetKDFParams mocks base method.
+func (m *MockClientGRPCInterface) GetKDFParams() *models.KDFParams {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKDFParams")
+	ret0, _ := ret[0].(*models.KDFParams)
+	return ret0
+}
+
+// GetKDFParams indicates an expected call of GetKDFParams.
+func (mr *MockClientGRPCInterfaceMockRecorder) GetKDFParams() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKDFParams", reflect.TypeOf((*MockClientGRPCInterface)(nil).GetKDFParams))
+}
+
+// GetKeyPair mocks base method.
+func (m *MockClientGRPCInterface) GetKeyPair(ctx context.Context) (*models.KeyPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeyPair", ctx)
+	ret0, _ := ret[0].(*models.KeyPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeyPair indicates an expected call of GetKeyPair.
+func (mr *MockClientGRPCInterfaceMockRecorder) GetKeyPair(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyPair", reflect.TypeOf((*MockClientGRPCInterface)(nil).GetKeyPair), ctx)
+}
+
+// GetLogin mocks base method.
+func (m *MockClientGRPCInterface) GetLogin() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLogin")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetLogin indicates an expected call of GetLogin.
+func (mr *MockClientGRPCInterfaceMockRecorder) GetLogin() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogin", reflect.TypeOf((*MockClientGRPCInterface)(nil).GetLogin))
+}
+
 // GetPassword mocks base method.
 func (m *MockClientGRPCInterface) GetPassword() string {
 	m.ctrl.T.Helper()
@@ -65,6 +397,21 @@ func (mr *MockClientGRPCInterfaceMockRecorder) GetPassword() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPassword", reflect.TypeOf((*MockClientGRPCInterface)(nil).GetPassword))
 }
 
+// GetPublicKey mocks base method.
+func (m *MockClientGRPCInterface) GetPublicKey(ctx context.Context, login string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublicKey", ctx, login)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublicKey indicates an expected call of GetPublicKey.
+func (mr *MockClientGRPCInterfaceMockRecorder) GetPublicKey(ctx, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicKey", reflect.TypeOf((*MockClientGRPCInterface)(nil).GetPublicKey), ctx, login)
+}
+
 // GetToken mocks base method.
 func (m *MockClientGRPCInterface) GetToken() string {
 	m.ctrl.T.Helper()
@@ -79,6 +426,185 @@ func (mr *MockClientGRPCInterfaceMockRecorder) GetToken() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetToken", reflect.TypeOf((*MockClientGRPCInterface)(nil).GetToken))
 }
 
+// GetVaultKey mocks base method.
+func (m *MockClientGRPCInterface) GetVaultKey(ctx context.Context) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVaultKey", ctx)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVaultKey indicates an expected call of GetVaultKey.
+func (mr *MockClientGRPCInterfaceMockRecorder) GetVaultKey(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVaultKey", reflect.TypeOf((*MockClientGRPCInterface)(nil).GetVaultKey), ctx)
+}
+
+// KDFUpgradeRequired mocks base method.
+func (m *MockClientGRPCInterface) KDFUpgradeRequired() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KDFUpgradeRequired")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// KDFUpgradeRequired indicates an expected call of KDFUpgradeRequired.
+func (mr *MockClientGRPCInterfaceMockRecorder) KDFUpgradeRequired() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KDFUpgradeRequired", reflect.TypeOf((*MockClientGRPCInterface)(nil).KDFUpgradeRequired))
+}
+
+// ListEmergencyContacts mocks base method.
+func (m *MockClientGRPCInterface) ListEmergencyContacts(ctx context.Context) ([]*models.EmergencyAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEmergencyContacts", ctx)
+	ret0, _ := ret[0].([]*models.EmergencyAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEmergencyContacts indicates an expected call of ListEmergencyContacts.
+func (mr *MockClientGRPCInterfaceMockRecorder) ListEmergencyContacts(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEmergencyContacts", reflect.TypeOf((*MockClientGRPCInterface)(nil).ListEmergencyContacts), ctx)
+}
+
+// ListEmergencyGrants mocks base method.
+func (m *MockClientGRPCInterface) ListEmergencyGrants(ctx context.Context) ([]*models.EmergencyAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEmergencyGrants", ctx)
+	ret0, _ := ret[0].([]*models.EmergencyAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEmergencyGrants indicates an expected call of ListEmergencyGrants.
+func (mr *MockClientGRPCInterfaceMockRecorder) ListEmergencyGrants(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEmergencyGrants", reflect.TypeOf((*MockClientGRPCInterface)(nil).ListEmergencyGrants), ctx)
+}
+
+// ListFolders mocks base method.
+func (m *MockClientGRPCInterface) ListFolders(ctx context.Context) ([]*models.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFolders", ctx)
+	ret0, _ := ret[0].([]*models.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFolders indicates an expected call of ListFolders.
+func (mr *MockClientGRPCInterfaceMockRecorder) ListFolders(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFolders", reflect.TypeOf((*MockClientGRPCInterface)(nil).ListFolders), ctx)
+}
+
+// ListOrgMembers mocks base method.
+func (m *MockClientGRPCInterface) ListOrgMembers(ctx context.Context, orgID uint64) ([]*models.OrgMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrgMembers", ctx, orgID)
+	ret0, _ := ret[0].([]*models.OrgMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrgMembers indicates an expected call of ListOrgMembers.
+func (mr *MockClientGRPCInterfaceMockRecorder) ListOrgMembers(ctx, orgID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrgMembers", reflect.TypeOf((*MockClientGRPCInterface)(nil).ListOrgMembers), ctx, orgID)
+}
+
+// ListOrganizations mocks base method.
+func (m *MockClientGRPCInterface) ListOrganizations(ctx context.Context) ([]*models.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrganizations", ctx)
+	ret0, _ := ret[0].([]*models.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrganizations indicates an expected call of ListOrganizations.
+func (mr *MockClientGRPCInterfaceMockRecorder) ListOrganizations(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrganizations", reflect.TypeOf((*MockClientGRPCInterface)(nil).ListOrganizations), ctx)
+}
+
+// ListSecrets mocks base method.
+func (m *MockClientGRPCInterface) ListSecrets(ctx context.Context, query *models.SecretListQuery) (*models.SecretsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSecrets", ctx, query)
+	ret0, _ := ret[0].(*models.SecretsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSecrets indicates an expected call of ListSecrets.
+func (mr *MockClientGRPCInterfaceMockRecorder) ListSecrets(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSecrets", reflect.TypeOf((*MockClientGRPCInterface)(nil).ListSecrets), ctx, query)
+}
+
+// ListSessions mocks base method.
+func (m *MockClientGRPCInterface) ListSessions(ctx context.Context) ([]*models.DeviceSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessions", ctx)
+	ret0, _ := ret[0].([]*models.DeviceSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockClientGRPCInterfaceMockRecorder) ListSessions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockClientGRPCInterface)(nil).ListSessions), ctx)
+}
+
+// ListSharedWithMe mocks base method.
+func (m *MockClientGRPCInterface) ListSharedWithMe(ctx context.Context) ([]*models.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSharedWithMe", ctx)
+	ret0, _ := ret[0].([]*models.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSharedWithMe indicates an expected call of ListSharedWithMe.
+func (mr *MockClientGRPCInterfaceMockRecorder) ListSharedWithMe(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSharedWithMe", reflect.TypeOf((*MockClientGRPCInterface)(nil).ListSharedWithMe), ctx)
+}
+
+// ListShares mocks base method.
+func (m *MockClientGRPCInterface) ListShares(ctx context.Context, secretID uint64) ([]*models.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListShares", ctx, secretID)
+	ret0, _ := ret[0].([]*models.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListShares indicates an expected call of ListShares.
+func (mr *MockClientGRPCInterfaceMockRecorder) ListShares(ctx, secretID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShares", reflect.TypeOf((*MockClientGRPCInterface)(nil).ListShares), ctx, secretID)
+}
+
+// ListTags mocks base method.
+func (m *MockClientGRPCInterface) ListTags(ctx context.Context) ([]*models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTags", ctx)
+	ret0, _ := ret[0].([]*models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTags indicates an expected call of ListTags.
+func (mr *MockClientGRPCInterfaceMockRecorder) ListTags(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTags", reflect.TypeOf((*MockClientGRPCInterface)(nil).ListTags), ctx)
+}
+
 // LoadSecret mocks base method.
 func (m *MockClientGRPCInterface) LoadSecret(ctx context.Context, ID uint64) (*models.Secret, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +620,21 @@ func (mr *MockClientGRPCInterfaceMockRecorder) LoadSecret(ctx, ID interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadSecret", reflect.TypeOf((*MockClientGRPCInterface)(nil).LoadSecret), ctx, ID)
 }
 
+// LoadSecretVersions mocks base method.
+func (m *MockClientGRPCInterface) LoadSecretVersions(ctx context.Context, id uint64) ([]*models.SecretVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadSecretVersions", ctx, id)
+	ret0, _ := ret[0].([]*models.SecretVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadSecretVersions indicates an expected call of LoadSecretVersions.
+func (mr *MockClientGRPCInterfaceMockRecorder) LoadSecretVersions(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadSecretVersions", reflect.TypeOf((*MockClientGRPCInterface)(nil).LoadSecretVersions), ctx, id)
+}
+
 // LoadSecrets mocks base method.
 func (m *MockClientGRPCInterface) LoadSecrets(ctx context.Context) ([]*models.Secret, error) {
 	m.ctrl.T.Helper()
@@ -109,6 +650,21 @@ func (mr *MockClientGRPCInterfaceMockRecorder) LoadSecrets(ctx interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadSecrets", reflect.TypeOf((*MockClientGRPCInterface)(nil).LoadSecrets), ctx)
 }
 
+// LoadTrash mocks base method.
+func (m *MockClientGRPCInterface) LoadTrash(ctx context.Context) ([]*models.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadTrash", ctx)
+	ret0, _ := ret[0].([]*models.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadTrash indicates an expected call of LoadTrash.
+func (mr *MockClientGRPCInterfaceMockRecorder) LoadTrash(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadTrash", reflect.TypeOf((*MockClientGRPCInterface)(nil).LoadTrash), ctx)
+}
+
 // Login mocks base method.
 func (m *MockClientGRPCInterface) Login(ctx context.Context, login, password string) (string, error) {
 	m.ctrl.T.Helper()
@@ -124,6 +680,50 @@ func (mr *MockClientGRPCInterfaceMockRecorder) Login(ctx, login, password interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockClientGRPCInterface)(nil).Login), ctx, login, password)
 }
 
+// LogoutAll mocks base method.
+func (m *MockClientGRPCInterface) LogoutAll(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogoutAll", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogoutAll indicates an expected call of LogoutAll.
+func (mr *MockClientGRPCInterfaceMockRecorder) LogoutAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutAll", reflect.TypeOf((*MockClientGRPCInterface)(nil).LogoutAll), ctx)
+}
+
+// MigrateLegacyLogin mocks base method.
+func (m *MockClientGRPCInterface) MigrateLegacyLogin(ctx context.Context, login, password string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MigrateLegacyLogin", ctx, login, password)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MigrateLegacyLogin indicates an expected call of MigrateLegacyLogin.
+func (mr *MockClientGRPCInterfaceMockRecorder) MigrateLegacyLogin(ctx, login, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MigrateLegacyLogin", reflect.TypeOf((*MockClientGRPCInterface)(nil).MigrateLegacyLogin), ctx, login, password)
+}
+
+// NewKDFParams mocks base method.
+func (m *MockClientGRPCInterface) NewKDFParams(ctx context.Context) (*models.KDFParams, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewKDFParams", ctx)
+	ret0, _ := ret[0].(*models.KDFParams)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewKDFParams indicates an expected call of NewKDFParams.
+func (mr *MockClientGRPCInterfaceMockRecorder) NewKDFParams(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewKDFParams", reflect.TypeOf((*MockClientGRPCInterface)(nil).NewKDFParams), ctx)
+}
+
 // Notifications mocks base method.
 func (m *MockClientGRPCInterface) Notifications(p *tea.Program, logger *zap.Logger) {
 	m.ctrl.T.Helper()
@@ -136,6 +736,20 @@ func (mr *MockClientGRPCInterfaceMockRecorder) Notifications(p, logger interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notifications", reflect.TypeOf((*MockClientGRPCInterface)(nil).Notifications), p, logger)
 }
 
+// PurgeSecret mocks base method.
+func (m *MockClientGRPCInterface) PurgeSecret(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeSecret", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeSecret indicates an expected call of PurgeSecret.
+func (mr *MockClientGRPCInterfaceMockRecorder) PurgeSecret(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeSecret", reflect.TypeOf((*MockClientGRPCInterface)(nil).PurgeSecret), ctx, id)
+}
+
 // Register mocks base method.
 func (m *MockClientGRPCInterface) Register(ctx context.Context, login, password string) (string, error) {
 	m.ctrl.T.Helper()
@@ -151,6 +765,133 @@ func (mr *MockClientGRPCInterfaceMockRecorder) Register(ctx, login, password int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockClientGRPCInterface)(nil).Register), ctx, login, password)
 }
 
+// RejectEmergencyAccess mocks base method.
+func (m *MockClientGRPCInterface) RejectEmergencyAccess(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectEmergencyAccess", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectEmergencyAccess indicates an expected call of RejectEmergencyAccess.
+func (mr *MockClientGRPCInterfaceMockRecorder) RejectEmergencyAccess(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectEmergencyAccess", reflect.TypeOf((*MockClientGRPCInterface)(nil).RejectEmergencyAccess), ctx, id)
+}
+
+// RemoveOrgMember mocks base method.
+func (m *MockClientGRPCInterface) RemoveOrgMember(ctx context.Context, orgID, userID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveOrgMember", ctx, orgID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveOrgMember indicates an expected call of RemoveOrgMember.
+func (mr *MockClientGRPCInterfaceMockRecorder) RemoveOrgMember(ctx, orgID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveOrgMember", reflect.TypeOf((*MockClientGRPCInterface)(nil).RemoveOrgMember), ctx, orgID, userID)
+}
+
+// RenameSession mocks base method.
+func (m *MockClientGRPCInterface) RenameSession(ctx context.Context, id uint64, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameSession", ctx, id, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameSession indicates an expected call of RenameSession.
+func (mr *MockClientGRPCInterfaceMockRecorder) RenameSession(ctx, id, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameSession", reflect.TypeOf((*MockClientGRPCInterface)(nil).RenameSession), ctx, id, name)
+}
+
+// RequestEmergencyAccess mocks base method.
+func (m *MockClientGRPCInterface) RequestEmergencyAccess(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestEmergencyAccess", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestEmergencyAccess indicates an expected call of RequestEmergencyAccess.
+func (mr *MockClientGRPCInterfaceMockRecorder) RequestEmergencyAccess(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestEmergencyAccess", reflect.TypeOf((*MockClientGRPCInterface)(nil).RequestEmergencyAccess), ctx, id)
+}
+
+// ReserveSecretID mocks base method.
+func (m *MockClientGRPCInterface) ReserveSecretID(ctx context.Context) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveSecretID", ctx)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveSecretID indicates an expected call of ReserveSecretID.
+func (mr *MockClientGRPCInterfaceMockRecorder) ReserveSecretID(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveSecretID", reflect.TypeOf((*MockClientGRPCInterface)(nil).ReserveSecretID), ctx)
+}
+
+// RestoreSecret mocks base method.
+func (m *MockClientGRPCInterface) RestoreSecret(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSecret", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreSecret indicates an expected call of RestoreSecret.
+func (mr *MockClientGRPCInterfaceMockRecorder) RestoreSecret(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSecret", reflect.TypeOf((*MockClientGRPCInterface)(nil).RestoreSecret), ctx, id)
+}
+
+// RestoreSecretVersion mocks base method.
+func (m *MockClientGRPCInterface) RestoreSecretVersion(ctx context.Context, id, versionID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSecretVersion", ctx, id, versionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreSecretVersion indicates an expected call of RestoreSecretVersion.
+func (mr *MockClientGRPCInterfaceMockRecorder) RestoreSecretVersion(ctx, id, versionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSecretVersion", reflect.TypeOf((*MockClientGRPCInterface)(nil).RestoreSecretVersion), ctx, id, versionID)
+}
+
+// RevokeSession mocks base method.
+func (m *MockClientGRPCInterface) RevokeSession(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockClientGRPCInterfaceMockRecorder) RevokeSession(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockClientGRPCInterface)(nil).RevokeSession), ctx, id)
+}
+
+// RevokeShare mocks base method.
+func (m *MockClientGRPCInterface) RevokeShare(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeShare", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeShare indicates an expected call of RevokeShare.
+func (mr *MockClientGRPCInterfaceMockRecorder) RevokeShare(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeShare", reflect.TypeOf((*MockClientGRPCInterface)(nil).RevokeShare), ctx, id)
+}
+
 // SaveSecret mocks base method.
 func (m *MockClientGRPCInterface) SaveSecret(ctx context.Context, secret *models.Secret) error {
 	m.ctrl.T.Helper()
@@ -188,3 +929,134 @@ func (mr *MockClientGRPCInterfaceMockRecorder) SetToken(token interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetToken", reflect.TypeOf((*MockClientGRPCInterface)(nil).SetToken), token)
 }
+
+// ShareSecret mocks base method.
+func (m *MockClientGRPCInterface) ShareSecret(ctx context.Context, share *models.Share) (*models.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShareSecret", ctx, share)
+	ret0, _ := ret[0].(*models.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShareSecret indicates an expected call of ShareSecret.
+func (mr *MockClientGRPCInterfaceMockRecorder) ShareSecret(ctx, share interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShareSecret", reflect.TypeOf((*MockClientGRPCInterface)(nil).ShareSecret), ctx, share)
+}
+
+// SyncSecrets mocks base method.
+func (m *MockClientGRPCInterface) SyncSecrets(ctx context.Context, sinceRevision uint64) (*models.SecretsDelta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncSecrets", ctx, sinceRevision)
+	ret0, _ := ret[0].(*models.SecretsDelta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncSecrets indicates an expected call of SyncSecrets.
+func (mr *MockClientGRPCInterfaceMockRecorder) SyncSecrets(ctx, sinceRevision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncSecrets", reflect.TypeOf((*MockClientGRPCInterface)(nil).SyncSecrets), ctx, sinceRevision)
+}
+
+// UpdateFolder mocks base method.
+func (m *MockClientGRPCInterface) UpdateFolder(ctx context.Context, id, parentID uint64, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFolder", ctx, id, parentID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFolder indicates an expected call of UpdateFolder.
+func (mr *MockClientGRPCInterfaceMockRecorder) UpdateFolder(ctx, id, parentID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFolder", reflect.TypeOf((*MockClientGRPCInterface)(nil).UpdateFolder), ctx, id, parentID, name)
+}
+
+// UpdateOrgMemberRole mocks base method.
+func (m *MockClientGRPCInterface) UpdateOrgMemberRole(ctx context.Context, orgID, userID uint64, role models.OrgRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrgMemberRole", ctx, orgID, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOrgMemberRole indicates an expected call of UpdateOrgMemberRole.
+func (mr *MockClientGRPCInterfaceMockRecorder) UpdateOrgMemberRole(ctx, orgID, userID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrgMemberRole", reflect.TypeOf((*MockClientGRPCInterface)(nil).UpdateOrgMemberRole), ctx, orgID, userID, role)
+}
+
+// UpdateSharedSecret mocks base method.
+func (m *MockClientGRPCInterface) UpdateSharedSecret(ctx context.Context, secret *models.Secret) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSharedSecret", ctx, secret)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSharedSecret indicates an expected call of UpdateSharedSecret.
+func (mr *MockClientGRPCInterfaceMockRecorder) UpdateSharedSecret(ctx, secret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSharedSecret", reflect.TypeOf((*MockClientGRPCInterface)(nil).UpdateSharedSecret), ctx, secret)
+}
+
+// UpdateTag mocks base method.
+func (m *MockClientGRPCInterface) UpdateTag(ctx context.Context, id uint64, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTag", ctx, id, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTag indicates an expected call of UpdateTag.
+func (mr *MockClientGRPCInterfaceMockRecorder) UpdateTag(ctx, id, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTag", reflect.TypeOf((*MockClientGRPCInterface)(nil).UpdateTag), ctx, id, name)
+}
+
+// UpgradeKDF mocks base method.
+func (m *MockClientGRPCInterface) UpgradeKDF(ctx context.Context, currentAuthHash string, params *models.KDFParams, keys *crypto.Keys, vaultKey []byte, secrets map[uint64]*models.Secret) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpgradeKDF", ctx, currentAuthHash, params, keys, vaultKey, secrets)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpgradeKDF indicates an expected call of UpgradeKDF.
+func (mr *MockClientGRPCInterfaceMockRecorder) UpgradeKDF(ctx, currentAuthHash, params, keys, vaultKey, secrets interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeKDF", reflect.TypeOf((*MockClientGRPCInterface)(nil).UpgradeKDF), ctx, currentAuthHash, params, keys, vaultKey, secrets)
+}
+
+// UploadBlob mocks base method.
+func (m *MockClientGRPCInterface) UploadBlob(ctx context.Context, blobID string, chunkCount, from uint32, chunk func(uint32) ([]byte, error)) (uint32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadBlob", ctx, blobID, chunkCount, from, chunk)
+	ret0, _ := ret[0].(uint32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadBlob indicates an expected call of UploadBlob.
+func (mr *MockClientGRPCInterfaceMockRecorder) UploadBlob(ctx, blobID, chunkCount, from, chunk interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadBlob", reflect.TypeOf((*MockClientGRPCInterface)(nil).UploadBlob), ctx, blobID, chunkCount, from, chunk)
+}
+
+// VerifyTOTP mocks base method.
+func (m *MockClientGRPCInterface) VerifyTOTP(ctx context.Context, code string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyTOTP", ctx, code)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyTOTP indicates an expected call of VerifyTOTP.
+func (mr *MockClientGRPCInterfaceMockRecorder) VerifyTOTP(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTOTP", reflect.TypeOf((*MockClientGRPCInterface)(nil).VerifyTOTP), ctx, code)
+}
@@ -5,9 +5,11 @@
 package mocks
 
 import (
-	models "beliaev-aa/GophKeeper/pkg/models"
+	models "beliaev-aa/GophKeeper/internal/server/models"
+	models0 "beliaev-aa/GophKeeper/pkg/models"
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -36,7 +38,7 @@ func (m *MockISecretRepository) EXPECT() *MockISecretRepositoryMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockISecretRepository) Create(ctx context.Context, secret *models.Secret) (uint64, error) {
+func (m *MockISecretRepository) Create(ctx context.Context, secret *models0.Secret) (uint64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", ctx, secret)
 	ret0, _ := ret[0].(uint64)
@@ -64,11 +66,25 @@ func (mr *MockISecretRepositoryMockRecorder) Delete(ctx, secretID, userID interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockISecretRepository)(nil).Delete), ctx, secretID, userID)
 }
 
+// EncryptLabels mocks base method.
+func (m *MockISecretRepository) EncryptLabels(ctx context.Context, userID uint64, secrets models0.Secrets) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EncryptLabels", ctx, userID, secrets)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EncryptLabels indicates an expected call of EncryptLabels.
+func (mr *MockISecretRepositoryMockRecorder) EncryptLabels(ctx, userID, secrets interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptLabels", reflect.TypeOf((*MockISecretRepository)(nil).EncryptLabels), ctx, userID, secrets)
+}
+
 // GetSecret mocks base method.
-func (m *MockISecretRepository) GetSecret(ctx context.Context, secretID, userID uint64) (*models.Secret, error) {
+func (m *MockISecretRepository) GetSecret(ctx context.Context, secretID, userID uint64) (*models0.Secret, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetSecret", ctx, secretID, userID)
-	ret0, _ := ret[0].(*models.Secret)
+	ret0, _ := ret[0].(*models0.Secret)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -80,30 +96,164 @@ func (mr *MockISecretRepositoryMockRecorder) GetSecret(ctx, secretID, userID int
 }
 
 // GetUserSecrets mocks base method.
-func (m *MockISecretRepository) GetUserSecrets(ctx context.Context, userID uint64) (models.Secrets, error) {
+func (m *MockISecretRepository) GetUserSecrets(ctx context.Context, userID uint64, filter models0.SecretFilter) (models0.Secrets, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserSecrets", ctx, userID)
-	ret0, _ := ret[0].(models.Secrets)
+	ret := m.ctrl.Call(m, "GetUserSecrets", ctx, userID, filter)
+	ret0, _ := ret[0].(models0.Secrets)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserSecrets indicates an expected call of GetUserSecrets.
-func (mr *MockISecretRepositoryMockRecorder) GetUserSecrets(ctx, userID interface{}) *gomock.Call {
+func (mr *MockISecretRepositoryMockRecorder) GetUserSecrets(ctx, userID, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSecrets", reflect.TypeOf((*MockISecretRepository)(nil).GetUserSecrets), ctx, userID, filter)
+}
+
+// List mocks base method.
+func (m *MockISecretRepository) List(ctx context.Context, userID uint64, query *models0.SecretListQuery, after *models.SecretCursor, limit int) (models0.Secrets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID, query, after, limit)
+	ret0, _ := ret[0].(models0.Secrets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockISecretRepositoryMockRecorder) List(ctx, userID, query, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockISecretRepository)(nil).List), ctx, userID, query, after, limit)
+}
+
+// ListTrash mocks base method.
+func (m *MockISecretRepository) ListTrash(ctx context.Context, userID uint64) (models0.Secrets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", ctx, userID)
+	ret0, _ := ret[0].(models0.Secrets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockISecretRepositoryMockRecorder) ListTrash(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockISecretRepository)(nil).ListTrash), ctx, userID)
+}
+
+// ListVersions mocks base method.
+func (m *MockISecretRepository) ListVersions(ctx context.Context, secretID, userID uint64) ([]*models0.SecretVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVersions", ctx, secretID, userID)
+	ret0, _ := ret[0].([]*models0.SecretVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVersions indicates an expected call of ListVersions.
+func (mr *MockISecretRepositoryMockRecorder) ListVersions(ctx, secretID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVersions", reflect.TypeOf((*MockISecretRepository)(nil).ListVersions), ctx, secretID, userID)
+}
+
+// Purge mocks base method.
+func (m *MockISecretRepository) Purge(ctx context.Context, secretID, userID uint64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, secretID, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockISecretRepositoryMockRecorder) Purge(ctx, secretID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockISecretRepository)(nil).Purge), ctx, secretID, userID)
+}
+
+// PurgeExpired mocks base method.
+func (m *MockISecretRepository) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpired", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpired indicates an expected call of PurgeExpired.
+func (mr *MockISecretRepositoryMockRecorder) PurgeExpired(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpired", reflect.TypeOf((*MockISecretRepository)(nil).PurgeExpired), ctx, before)
+}
+
+// ReserveID mocks base method.
+func (m *MockISecretRepository) ReserveID(ctx context.Context, userID uint64) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveID", ctx, userID)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveID indicates an expected call of ReserveID.
+func (mr *MockISecretRepositoryMockRecorder) ReserveID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveID", reflect.TypeOf((*MockISecretRepository)(nil).ReserveID), ctx, userID)
+}
+
+// Restore mocks base method.
+func (m *MockISecretRepository) Restore(ctx context.Context, secretID, userID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, secretID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockISecretRepositoryMockRecorder) Restore(ctx, secretID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockISecretRepository)(nil).Restore), ctx, secretID, userID)
+}
+
+// RestoreVersion mocks base method.
+func (m *MockISecretRepository) RestoreVersion(ctx context.Context, secretID, versionID, userID uint64, versionsLimit int) (*models0.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreVersion", ctx, secretID, versionID, userID, versionsLimit)
+	ret0, _ := ret[0].(*models0.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreVersion indicates an expected call of RestoreVersion.
+func (mr *MockISecretRepositoryMockRecorder) RestoreVersion(ctx, secretID, versionID, userID, versionsLimit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreVersion", reflect.TypeOf((*MockISecretRepository)(nil).RestoreVersion), ctx, secretID, versionID, userID, versionsLimit)
+}
+
+// Sync mocks base method.
+func (m *MockISecretRepository) Sync(ctx context.Context, userID, sinceRevision uint64) (*models0.SecretsDelta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sync", ctx, userID, sinceRevision)
+	ret0, _ := ret[0].(*models0.SecretsDelta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockISecretRepositoryMockRecorder) Sync(ctx, userID, sinceRevision interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSecrets", reflect.TypeOf((*MockISecretRepository)(nil).GetUserSecrets), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockISecretRepository)(nil).Sync), ctx, userID, sinceRevision)
 }
 
 // Update mocks base method.
-func (m *MockISecretRepository) Update(ctx context.Context, secret *models.Secret) error {
+func (m *MockISecretRepository) Update(ctx context.Context, secret *models0.Secret, versionsLimit int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, secret)
+	ret := m.ctrl.Call(m, "Update", ctx, secret, versionsLimit)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockISecretRepositoryMockRecorder) Update(ctx, secret interface{}) *gomock.Call {
+func (mr *MockISecretRepositoryMockRecorder) Update(ctx, secret, versionsLimit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockISecretRepository)(nil).Update), ctx, secret)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockISecretRepository)(nil).Update), ctx, secret, versionsLimit)
 }
@@ -64,6 +64,20 @@ func (mr *MockISecretServiceMockRecorder) DeleteSecret(ctx, secretID, userID int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecret", reflect.TypeOf((*MockISecretService)(nil).DeleteSecret), ctx, secretID, userID)
 }
 
+// EncryptSecretLabels mocks base method.
+func (m *MockISecretService) EncryptSecretLabels(ctx context.Context, userID uint64, secrets models.Secrets) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EncryptSecretLabels", ctx, userID, secrets)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EncryptSecretLabels indicates an expected call of EncryptSecretLabels.
+func (mr *MockISecretServiceMockRecorder) EncryptSecretLabels(ctx, userID, secrets interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptSecretLabels", reflect.TypeOf((*MockISecretService)(nil).EncryptSecretLabels), ctx, userID, secrets)
+}
+
 // GetSecret mocks base method.
 func (m *MockISecretService) GetSecret(ctx context.Context, secretID, userID uint64) (*models.Secret, error) {
 	m.ctrl.T.Helper()
@@ -80,18 +94,181 @@ func (mr *MockISecretServiceMockRecorder) GetSecret(ctx, secretID, userID interf
 }
 
 // GetUserSecrets mocks base method.
-func (m *MockISecretService) GetUserSecrets(ctx context.Context, userID uint64) (models.Secrets, error) {
+func (m *MockISecretService) GetUserSecrets(ctx context.Context, userID uint64, filter models.SecretFilter) (models.Secrets, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserSecrets", ctx, userID)
+	ret := m.ctrl.Call(m, "GetUserSecrets", ctx, userID, filter)
 	ret0, _ := ret[0].(models.Secrets)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserSecrets indicates an expected call of GetUserSecrets.
-func (mr *MockISecretServiceMockRecorder) GetUserSecrets(ctx, userID interface{}) *gomock.Call {
+func (mr *MockISecretServiceMockRecorder) GetUserSecrets(ctx, userID, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSecrets", reflect.TypeOf((*MockISecretService)(nil).GetUserSecrets), ctx, userID, filter)
+}
+
+// ListSecretVersions mocks base method.
+func (m *MockISecretService) ListSecretVersions(ctx context.Context, secretID, userID uint64) ([]*models.SecretVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSecretVersions", ctx, secretID, userID)
+	ret0, _ := ret[0].([]*models.SecretVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSecretVersions indicates an expected call of ListSecretVersions.
+func (mr *MockISecretServiceMockRecorder) ListSecretVersions(ctx, secretID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSecretVersions", reflect.TypeOf((*MockISecretService)(nil).ListSecretVersions), ctx, secretID, userID)
+}
+
+// ListSecrets mocks base method.
+func (m *MockISecretService) ListSecrets(ctx context.Context, userID uint64, query *models.SecretListQuery) (*models.SecretsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSecrets", ctx, userID, query)
+	ret0, _ := ret[0].(*models.SecretsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSecrets indicates an expected call of ListSecrets.
+func (mr *MockISecretServiceMockRecorder) ListSecrets(ctx, userID, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSecrets", reflect.TypeOf((*MockISecretService)(nil).ListSecrets), ctx, userID, query)
+}
+
+// ListTrash mocks base method.
+func (m *MockISecretService) ListTrash(ctx context.Context, userID uint64) (models.Secrets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", ctx, userID)
+	ret0, _ := ret[0].(models.Secrets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockISecretServiceMockRecorder) ListTrash(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockISecretService)(nil).ListTrash), ctx, userID)
+}
+
+// PurgeExpiredTrash mocks base method.
+func (m *MockISecretService) PurgeExpiredTrash(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpiredTrash", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpiredTrash indicates an expected call of PurgeExpiredTrash.
+func (mr *MockISecretServiceMockRecorder) PurgeExpiredTrash(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpiredTrash", reflect.TypeOf((*MockISecretService)(nil).PurgeExpiredTrash), ctx)
+}
+
+// PurgeSecret mocks base method.
+func (m *MockISecretService) PurgeSecret(ctx context.Context, secretID, userID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeSecret", ctx, secretID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeSecret indicates an expected call of PurgeSecret.
+func (mr *MockISecretServiceMockRecorder) PurgeSecret(ctx, secretID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeSecret", reflect.TypeOf((*MockISecretService)(nil).PurgeSecret), ctx, secretID, userID)
+}
+
+// ReserveSecretID mocks base method.
+func (m *MockISecretService) ReserveSecretID(ctx context.Context, userID uint64) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveSecretID", ctx, userID)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveSecretID indicates an expected call of ReserveSecretID.
+func (mr *MockISecretServiceMockRecorder) ReserveSecretID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveSecretID", reflect.TypeOf((*MockISecretService)(nil).ReserveSecretID), ctx, userID)
+}
+
+// ResolveEmergencyVault mocks base method.
+func (m *MockISecretService) ResolveEmergencyVault(ctx context.Context, userID, accessID uint64) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveEmergencyVault", ctx, userID, accessID)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveEmergencyVault indicates an expected call of ResolveEmergencyVault.
+func (mr *MockISecretServiceMockRecorder) ResolveEmergencyVault(ctx, userID, accessID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSecrets", reflect.TypeOf((*MockISecretService)(nil).GetUserSecrets), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveEmergencyVault", reflect.TypeOf((*MockISecretService)(nil).ResolveEmergencyVault), ctx, userID, accessID)
+}
+
+// ResolveVault mocks base method.
+func (m *MockISecretService) ResolveVault(ctx context.Context, userID, orgID uint64, required models.OrgRole) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveVault", ctx, userID, orgID, required)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveVault indicates an expected call of ResolveVault.
+func (mr *MockISecretServiceMockRecorder) ResolveVault(ctx, userID, orgID, required interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveVault", reflect.TypeOf((*MockISecretService)(nil).ResolveVault), ctx, userID, orgID, required)
+}
+
+// RestoreSecret mocks base method.
+func (m *MockISecretService) RestoreSecret(ctx context.Context, secretID, userID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSecret", ctx, secretID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreSecret indicates an expected call of RestoreSecret.
+func (mr *MockISecretServiceMockRecorder) RestoreSecret(ctx, secretID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSecret", reflect.TypeOf((*MockISecretService)(nil).RestoreSecret), ctx, secretID, userID)
+}
+
+// RestoreSecretVersion mocks base method.
+func (m *MockISecretService) RestoreSecretVersion(ctx context.Context, secretID, versionID, userID uint64) (*models.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSecretVersion", ctx, secretID, versionID, userID)
+	ret0, _ := ret[0].(*models.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreSecretVersion indicates an expected call of RestoreSecretVersion.
+func (mr *MockISecretServiceMockRecorder) RestoreSecretVersion(ctx, secretID, versionID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSecretVersion", reflect.TypeOf((*MockISecretService)(nil).RestoreSecretVersion), ctx, secretID, versionID, userID)
+}
+
+// SyncSecrets mocks base method.
+func (m *MockISecretService) SyncSecrets(ctx context.Context, userID, sinceRevision uint64) (*models.SecretsDelta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncSecrets", ctx, userID, sinceRevision)
+	ret0, _ := ret[0].(*models.SecretsDelta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncSecrets indicates an expected call of SyncSecrets.
+func (mr *MockISecretServiceMockRecorder) SyncSecrets(ctx, userID, sinceRevision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncSecrets", reflect.TypeOf((*MockISecretService)(nil).SyncSecrets), ctx, userID, sinceRevision)
 }
 
 // UpdateSecret mocks base method.
@@ -108,3 +285,18 @@ func (mr *MockISecretServiceMockRecorder) UpdateSecret(ctx, secret interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSecret", reflect.TypeOf((*MockISecretService)(nil).UpdateSecret), ctx, secret)
 }
+
+// VaultMembers mocks base method.
+func (m *MockISecretService) VaultMembers(ctx context.Context, orgID uint64) ([]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VaultMembers", ctx, orgID)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VaultMembers indicates an expected call of VaultMembers.
+func (mr *MockISecretServiceMockRecorder) VaultMembers(ctx, orgID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VaultMembers", reflect.TypeOf((*MockISecretService)(nil).VaultMembers), ctx, orgID)
+}
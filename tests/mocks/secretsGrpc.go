@@ -57,6 +57,26 @@ func (mr *MockSecretsClientMockRecorder) DeleteUserSecret(ctx, in interface{}, o
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSecret", reflect.TypeOf((*MockSecretsClient)(nil).DeleteUserSecret), varargs...)
 }
 
+// EncryptSecretLabels mocks base method.
+func (m *MockSecretsClient) EncryptSecretLabels(ctx context.Context, in *proto.EncryptSecretLabelsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EncryptSecretLabels", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EncryptSecretLabels indicates an expected call of EncryptSecretLabels.
+func (mr *MockSecretsClientMockRecorder) EncryptSecretLabels(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptSecretLabels", reflect.TypeOf((*MockSecretsClient)(nil).EncryptSecretLabels), varargs...)
+}
+
 // GetUserSecret mocks base method.
 func (m *MockSecretsClient) GetUserSecret(ctx context.Context, in *proto.GetUserSecretRequest, opts ...grpc.CallOption) (*proto.GetUserSecretResponse, error) {
 	m.ctrl.T.Helper()
@@ -78,7 +98,7 @@ func (mr *MockSecretsClientMockRecorder) GetUserSecret(ctx, in interface{}, opts
 }
 
 // GetUserSecrets mocks base method.
-func (m *MockSecretsClient) GetUserSecrets(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*proto.GetUserSecretsResponse, error) {
+func (m *MockSecretsClient) GetUserSecrets(ctx context.Context, in *proto.GetUserSecretsRequest, opts ...grpc.CallOption) (*proto.GetUserSecretsResponse, error) {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{ctx, in}
 	for _, a := range opts {
@@ -97,6 +117,146 @@ func (mr *MockSecretsClientMockRecorder) GetUserSecrets(ctx, in interface{}, opt
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSecrets", reflect.TypeOf((*MockSecretsClient)(nil).GetUserSecrets), varargs...)
 }
 
+// ListSecretVersions mocks base method.
+func (m *MockSecretsClient) ListSecretVersions(ctx context.Context, in *proto.ListSecretVersionsRequest, opts ...grpc.CallOption) (*proto.ListSecretVersionsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListSecretVersions", varargs...)
+	ret0, _ := ret[0].(*proto.ListSecretVersionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSecretVersions indicates an expected call of ListSecretVersions.
+func (mr *MockSecretsClientMockRecorder) ListSecretVersions(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSecretVersions", reflect.TypeOf((*MockSecretsClient)(nil).ListSecretVersions), varargs...)
+}
+
+// ListSecrets mocks base method.
+func (m *MockSecretsClient) ListSecrets(ctx context.Context, in *proto.ListSecretsRequest, opts ...grpc.CallOption) (*proto.ListSecretsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListSecrets", varargs...)
+	ret0, _ := ret[0].(*proto.ListSecretsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSecrets indicates an expected call of ListSecrets.
+func (mr *MockSecretsClientMockRecorder) ListSecrets(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSecrets", reflect.TypeOf((*MockSecretsClient)(nil).ListSecrets), varargs...)
+}
+
+// ListTrash mocks base method.
+func (m *MockSecretsClient) ListTrash(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*proto.ListTrashResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListTrash", varargs...)
+	ret0, _ := ret[0].(*proto.ListTrashResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockSecretsClientMockRecorder) ListTrash(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockSecretsClient)(nil).ListTrash), varargs...)
+}
+
+// PurgeSecret mocks base method.
+func (m *MockSecretsClient) PurgeSecret(ctx context.Context, in *proto.PurgeSecretRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PurgeSecret", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeSecret indicates an expected call of PurgeSecret.
+func (mr *MockSecretsClientMockRecorder) PurgeSecret(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeSecret", reflect.TypeOf((*MockSecretsClient)(nil).PurgeSecret), varargs...)
+}
+
+// ReserveSecretID mocks base method.
+func (m *MockSecretsClient) ReserveSecretID(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*proto.ReserveSecretIDResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ReserveSecretID", varargs...)
+	ret0, _ := ret[0].(*proto.ReserveSecretIDResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveSecretID indicates an expected call of ReserveSecretID.
+func (mr *MockSecretsClientMockRecorder) ReserveSecretID(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveSecretID", reflect.TypeOf((*MockSecretsClient)(nil).ReserveSecretID), varargs...)
+}
+
+// RestoreSecret mocks base method.
+func (m *MockSecretsClient) RestoreSecret(ctx context.Context, in *proto.RestoreSecretRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RestoreSecret", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreSecret indicates an expected call of RestoreSecret.
+func (mr *MockSecretsClientMockRecorder) RestoreSecret(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSecret", reflect.TypeOf((*MockSecretsClient)(nil).RestoreSecret), varargs...)
+}
+
+// RestoreSecretVersion mocks base method.
+func (m *MockSecretsClient) RestoreSecretVersion(ctx context.Context, in *proto.RestoreSecretVersionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RestoreSecretVersion", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreSecretVersion indicates an expected call of RestoreSecretVersion.
+func (mr *MockSecretsClientMockRecorder) RestoreSecretVersion(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSecretVersion", reflect.TypeOf((*MockSecretsClient)(nil).RestoreSecretVersion), varargs...)
+}
+
 // SaveUserSecret mocks base method.
 func (m *MockSecretsClient) SaveUserSecret(ctx context.Context, in *proto.SaveUserSecretRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	m.ctrl.T.Helper()
@@ -117,6 +277,26 @@ func (mr *MockSecretsClientMockRecorder) SaveUserSecret(ctx, in interface{}, opt
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUserSecret", reflect.TypeOf((*MockSecretsClient)(nil).SaveUserSecret), varargs...)
 }
 
+// SyncSecrets mocks base method.
+func (m *MockSecretsClient) SyncSecrets(ctx context.Context, in *proto.SyncSecretsRequest, opts ...grpc.CallOption) (*proto.SyncSecretsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SyncSecrets", varargs...)
+	ret0, _ := ret[0].(*proto.SyncSecretsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncSecrets indicates an expected call of SyncSecrets.
+func (mr *MockSecretsClientMockRecorder) SyncSecrets(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncSecrets", reflect.TypeOf((*MockSecretsClient)(nil).SyncSecrets), varargs...)
+}
+
 // MockSecretsServer is a mock of SecretsServer interface.
 type MockSecretsServer struct {
 	ctrl     *gomock.Controller
@@ -155,6 +335,21 @@ func (mr *MockSecretsServerMockRecorder) DeleteUserSecret(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSecret", reflect.TypeOf((*MockSecretsServer)(nil).DeleteUserSecret), arg0, arg1)
 }
 
+// EncryptSecretLabels mocks base method.
+func (m *MockSecretsServer) EncryptSecretLabels(arg0 context.Context, arg1 *proto.EncryptSecretLabelsRequest) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EncryptSecretLabels", arg0, arg1)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EncryptSecretLabels indicates an expected call of EncryptSecretLabels.
+func (mr *MockSecretsServerMockRecorder) EncryptSecretLabels(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptSecretLabels", reflect.TypeOf((*MockSecretsServer)(nil).EncryptSecretLabels), arg0, arg1)
+}
+
 // GetUserSecret mocks base method.
 func (m *MockSecretsServer) GetUserSecret(arg0 context.Context, arg1 *proto.GetUserSecretRequest) (*proto.GetUserSecretResponse, error) {
 	m.ctrl.T.Helper()
@@ -171,7 +366,7 @@ func (mr *MockSecretsServerMockRecorder) GetUserSecret(arg0, arg1 interface{}) *
 }
 
 // GetUserSecrets mocks base method.
-func (m *MockSecretsServer) GetUserSecrets(arg0 context.Context, arg1 *emptypb.Empty) (*proto.GetUserSecretsResponse, error) {
+func (m *MockSecretsServer) GetUserSecrets(arg0 context.Context, arg1 *proto.GetUserSecretsRequest) (*proto.GetUserSecretsResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetUserSecrets", arg0, arg1)
 	ret0, _ := ret[0].(*proto.GetUserSecretsResponse)
@@ -185,6 +380,111 @@ func (mr *MockSecretsServerMockRecorder) GetUserSecrets(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSecrets", reflect.TypeOf((*MockSecretsServer)(nil).GetUserSecrets), arg0, arg1)
 }
 
+// ListSecretVersions mocks base method.
+func (m *MockSecretsServer) ListSecretVersions(arg0 context.Context, arg1 *proto.ListSecretVersionsRequest) (*proto.ListSecretVersionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSecretVersions", arg0, arg1)
+	ret0, _ := ret[0].(*proto.ListSecretVersionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSecretVersions indicates an expected call of ListSecretVersions.
+func (mr *MockSecretsServerMockRecorder) ListSecretVersions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSecretVersions", reflect.TypeOf((*MockSecretsServer)(nil).ListSecretVersions), arg0, arg1)
+}
+
+// ListSecrets mocks base method.
+func (m *MockSecretsServer) ListSecrets(arg0 context.Context, arg1 *proto.ListSecretsRequest) (*proto.ListSecretsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSecrets", arg0, arg1)
+	ret0, _ := ret[0].(*proto.ListSecretsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSecrets indicates an expected call of ListSecrets.
+func (mr *MockSecretsServerMockRecorder) ListSecrets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSecrets", reflect.TypeOf((*MockSecretsServer)(nil).ListSecrets), arg0, arg1)
+}
+
+// ListTrash mocks base method.
+func (m *MockSecretsServer) ListTrash(arg0 context.Context, arg1 *emptypb.Empty) (*proto.ListTrashResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", arg0, arg1)
+	ret0, _ := ret[0].(*proto.ListTrashResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockSecretsServerMockRecorder) ListTrash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockSecretsServer)(nil).ListTrash), arg0, arg1)
+}
+
+// PurgeSecret mocks base method.
+func (m *MockSecretsServer) PurgeSecret(arg0 context.Context, arg1 *proto.PurgeSecretRequest) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeSecret", arg0, arg1)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeSecret indicates an expected call of PurgeSecret.
+func (mr *MockSecretsServerMockRecorder) PurgeSecret(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeSecret", reflect.TypeOf((*MockSecretsServer)(nil).PurgeSecret), arg0, arg1)
+}
+
+// ReserveSecretID mocks base method.
+func (m *MockSecretsServer) ReserveSecretID(arg0 context.Context, arg1 *emptypb.Empty) (*proto.ReserveSecretIDResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveSecretID", arg0, arg1)
+	ret0, _ := ret[0].(*proto.ReserveSecretIDResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveSecretID indicates an expected call of ReserveSecretID.
+func (mr *MockSecretsServerMockRecorder) ReserveSecretID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveSecretID", reflect.TypeOf((*MockSecretsServer)(nil).ReserveSecretID), arg0, arg1)
+}
+
+// RestoreSecret mocks base method.
+func (m *MockSecretsServer) RestoreSecret(arg0 context.Context, arg1 *proto.RestoreSecretRequest) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSecret", arg0, arg1)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreSecret indicates an expected call of RestoreSecret.
+func (mr *MockSecretsServerMockRecorder) RestoreSecret(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSecret", reflect.TypeOf((*MockSecretsServer)(nil).RestoreSecret), arg0, arg1)
+}
+
+// RestoreSecretVersion mocks base method.
+func (m *MockSecretsServer) RestoreSecretVersion(arg0 context.Context, arg1 *proto.RestoreSecretVersionRequest) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSecretVersion", arg0, arg1)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreSecretVersion indicates an expected call of RestoreSecretVersion.
+func (mr *MockSecretsServerMockRecorder) RestoreSecretVersion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSecretVersion", reflect.TypeOf((*MockSecretsServer)(nil).RestoreSecretVersion), arg0, arg1)
+}
+
 // SaveUserSecret mocks base method.
 func (m *MockSecretsServer) SaveUserSecret(arg0 context.Context, arg1 *proto.SaveUserSecretRequest) (*emptypb.Empty, error) {
 	m.ctrl.T.Helper()
@@ -200,6 +500,21 @@ func (mr *MockSecretsServerMockRecorder) SaveUserSecret(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUserSecret", reflect.TypeOf((*MockSecretsServer)(nil).SaveUserSecret), arg0, arg1)
 }
 
+// SyncSecrets mocks base method.
+func (m *MockSecretsServer) SyncSecrets(arg0 context.Context, arg1 *proto.SyncSecretsRequest) (*proto.SyncSecretsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncSecrets", arg0, arg1)
+	ret0, _ := ret[0].(*proto.SyncSecretsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncSecrets indicates an expected call of SyncSecrets.
+func (mr *MockSecretsServerMockRecorder) SyncSecrets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncSecrets", reflect.TypeOf((*MockSecretsServer)(nil).SyncSecrets), arg0, arg1)
+}
+
 // mustEmbedUnimplementedSecretsServer mocks base method.
 func (m *MockSecretsServer) mustEmbedUnimplementedSecretsServer() {
 	m.ctrl.T.Helper()
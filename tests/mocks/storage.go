@@ -35,6 +35,48 @@ func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
 	return m.recorder
 }
 
+// AddEmergencyContact mocks base method.
+func (m *MockStorage) AddEmergencyContact(ctx context.Context, login string, waitDays int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddEmergencyContact", ctx, login, waitDays)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddEmergencyContact indicates an expected call of AddEmergencyContact.
+func (mr *MockStorageMockRecorder) AddEmergencyContact(ctx, login, waitDays interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEmergencyContact", reflect.TypeOf((*MockStorage)(nil).AddEmergencyContact), ctx, login, waitDays)
+}
+
+// AddOrgMember mocks base method.
+func (m *MockStorage) AddOrgMember(ctx context.Context, org *models.Organization, login string, role models.OrgRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOrgMember", ctx, org, login, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddOrgMember indicates an expected call of AddOrgMember.
+func (mr *MockStorageMockRecorder) AddOrgMember(ctx, org, login, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOrgMember", reflect.TypeOf((*MockStorage)(nil).AddOrgMember), ctx, org, login, role)
+}
+
+// ChangePassword mocks base method.
+func (m *MockStorage) ChangePassword(ctx context.Context, currentPassword, newPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, currentPassword, newPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockStorageMockRecorder) ChangePassword(ctx, currentPassword, newPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockStorage)(nil).ChangePassword), ctx, currentPassword, newPassword)
+}
+
 // Create mocks base method.
 func (m *MockStorage) Create(ctx context.Context, secret *models.Secret) error {
 	m.ctrl.T.Helper()
@@ -49,6 +91,51 @@ func (mr *MockStorageMockRecorder) Create(ctx, secret interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockStorage)(nil).Create), ctx, secret)
 }
 
+// CreateFolder mocks base method.
+func (m *MockStorage) CreateFolder(ctx context.Context, parentID uint64, name string) (*models.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFolder", ctx, parentID, name)
+	ret0, _ := ret[0].(*models.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFolder indicates an expected call of CreateFolder.
+func (mr *MockStorageMockRecorder) CreateFolder(ctx, parentID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFolder", reflect.TypeOf((*MockStorage)(nil).CreateFolder), ctx, parentID, name)
+}
+
+// CreateOrganization mocks base method.
+func (m *MockStorage) CreateOrganization(ctx context.Context, name string) (*models.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrganization", ctx, name)
+	ret0, _ := ret[0].(*models.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrganization indicates an expected call of CreateOrganization.
+func (mr *MockStorageMockRecorder) CreateOrganization(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrganization", reflect.TypeOf((*MockStorage)(nil).CreateOrganization), ctx, name)
+}
+
+// CreateTag mocks base method.
+func (m *MockStorage) CreateTag(ctx context.Context, name string) (*models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTag", ctx, name)
+	ret0, _ := ret[0].(*models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTag indicates an expected call of CreateTag.
+func (mr *MockStorageMockRecorder) CreateTag(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTag", reflect.TypeOf((*MockStorage)(nil).CreateTag), ctx, name)
+}
+
 // Delete mocks base method.
 func (m *MockStorage) Delete(ctx context.Context, id uint64) error {
 	m.ctrl.T.Helper()
@@ -63,6 +150,91 @@ func (mr *MockStorageMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStorage)(nil).Delete), ctx, id)
 }
 
+// DeleteEmergencyAccess mocks base method.
+func (m *MockStorage) DeleteEmergencyAccess(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEmergencyAccess", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEmergencyAccess indicates an expected call of DeleteEmergencyAccess.
+func (mr *MockStorageMockRecorder) DeleteEmergencyAccess(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEmergencyAccess", reflect.TypeOf((*MockStorage)(nil).DeleteEmergencyAccess), ctx, id)
+}
+
+// DeleteFolder mocks base method.
+func (m *MockStorage) DeleteFolder(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFolder", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFolder indicates an expected call of DeleteFolder.
+func (mr *MockStorageMockRecorder) DeleteFolder(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFolder", reflect.TypeOf((*MockStorage)(nil).DeleteFolder), ctx, id)
+}
+
+// DeleteOrganization mocks base method.
+func (m *MockStorage) DeleteOrganization(ctx context.Context, orgID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOrganization", ctx, orgID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOrganization indicates an expected call of DeleteOrganization.
+func (mr *MockStorageMockRecorder) DeleteOrganization(ctx, orgID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrganization", reflect.TypeOf((*MockStorage)(nil).DeleteOrganization), ctx, orgID)
+}
+
+// DeleteTag mocks base method.
+func (m *MockStorage) DeleteTag(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTag", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTag indicates an expected call of DeleteTag.
+func (mr *MockStorageMockRecorder) DeleteTag(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockStorage)(nil).DeleteTag), ctx, id)
+}
+
+// DownloadFile mocks base method.
+func (m *MockStorage) DownloadFile(ctx context.Context, secret *models.Secret, path string, progress func(int64, int64)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadFile", ctx, secret, path, progress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DownloadFile indicates an expected call of DownloadFile.
+func (mr *MockStorageMockRecorder) DownloadFile(ctx, secret, path, progress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadFile", reflect.TypeOf((*MockStorage)(nil).DownloadFile), ctx, secret, path, progress)
+}
+
+// Fingerprint mocks base method.
+func (m *MockStorage) Fingerprint() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fingerprint")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fingerprint indicates an expected call of Fingerprint.
+func (mr *MockStorageMockRecorder) Fingerprint() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fingerprint", reflect.TypeOf((*MockStorage)(nil).Fingerprint))
+}
+
 // Get mocks base method.
 func (m *MockStorage) Get(ctx context.Context, id uint64) (*models.Secret, error) {
 	m.ctrl.T.Helper()
@@ -93,6 +265,297 @@ func (mr *MockStorageMockRecorder) GetAll(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockStorage)(nil).GetAll), ctx)
 }
 
+// GetEmergencyContacts mocks base method.
+func (m *MockStorage) GetEmergencyContacts(ctx context.Context) ([]*models.EmergencyAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmergencyContacts", ctx)
+	ret0, _ := ret[0].([]*models.EmergencyAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmergencyContacts indicates an expected call of GetEmergencyContacts.
+func (mr *MockStorageMockRecorder) GetEmergencyContacts(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmergencyContacts", reflect.TypeOf((*MockStorage)(nil).GetEmergencyContacts), ctx)
+}
+
+// GetEmergencyGrants mocks base method.
+func (m *MockStorage) GetEmergencyGrants(ctx context.Context) ([]*models.EmergencyAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmergencyGrants", ctx)
+	ret0, _ := ret[0].([]*models.EmergencyAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmergencyGrants indicates an expected call of GetEmergencyGrants.
+func (mr *MockStorageMockRecorder) GetEmergencyGrants(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmergencyGrants", reflect.TypeOf((*MockStorage)(nil).GetEmergencyGrants), ctx)
+}
+
+// GetFolders mocks base method.
+func (m *MockStorage) GetFolders(ctx context.Context) ([]*models.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFolders", ctx)
+	ret0, _ := ret[0].([]*models.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFolders indicates an expected call of GetFolders.
+func (mr *MockStorageMockRecorder) GetFolders(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFolders", reflect.TypeOf((*MockStorage)(nil).GetFolders), ctx)
+}
+
+// GetOrgMembers mocks base method.
+func (m *MockStorage) GetOrgMembers(ctx context.Context, orgID uint64) ([]*models.OrgMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrgMembers", ctx, orgID)
+	ret0, _ := ret[0].([]*models.OrgMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrgMembers indicates an expected call of GetOrgMembers.
+func (mr *MockStorageMockRecorder) GetOrgMembers(ctx, orgID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrgMembers", reflect.TypeOf((*MockStorage)(nil).GetOrgMembers), ctx, orgID)
+}
+
+// GetOrganizations mocks base method.
+func (m *MockStorage) GetOrganizations(ctx context.Context) ([]*models.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizations", ctx)
+	ret0, _ := ret[0].([]*models.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizations indicates an expected call of GetOrganizations.
+func (mr *MockStorageMockRecorder) GetOrganizations(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizations", reflect.TypeOf((*MockStorage)(nil).GetOrganizations), ctx)
+}
+
+// GetSharedWithMe mocks base method.
+func (m *MockStorage) GetSharedWithMe(ctx context.Context) ([]*models.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedWithMe", ctx)
+	ret0, _ := ret[0].([]*models.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedWithMe indicates an expected call of GetSharedWithMe.
+func (mr *MockStorageMockRecorder) GetSharedWithMe(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedWithMe", reflect.TypeOf((*MockStorage)(nil).GetSharedWithMe), ctx)
+}
+
+// GetShares mocks base method.
+func (m *MockStorage) GetShares(ctx context.Context, secretID uint64) ([]*models.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShares", ctx, secretID)
+	ret0, _ := ret[0].([]*models.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShares indicates an expected call of GetShares.
+func (mr *MockStorageMockRecorder) GetShares(ctx, secretID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShares", reflect.TypeOf((*MockStorage)(nil).GetShares), ctx, secretID)
+}
+
+// GetTags mocks base method.
+func (m *MockStorage) GetTags(ctx context.Context) ([]*models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTags", ctx)
+	ret0, _ := ret[0].([]*models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTags indicates an expected call of GetTags.
+func (mr *MockStorageMockRecorder) GetTags(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTags", reflect.TypeOf((*MockStorage)(nil).GetTags), ctx)
+}
+
+// GetTrash mocks base method.
+func (m *MockStorage) GetTrash(ctx context.Context) ([]*models.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", ctx)
+	ret0, _ := ret[0].([]*models.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockStorageMockRecorder) GetTrash(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockStorage)(nil).GetTrash), ctx)
+}
+
+// GetVersions mocks base method.
+func (m *MockStorage) GetVersions(ctx context.Context, id uint64) ([]*models.SecretVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersions", ctx, id)
+	ret0, _ := ret[0].([]*models.SecretVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersions indicates an expected call of GetVersions.
+func (mr *MockStorageMockRecorder) GetVersions(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersions", reflect.TypeOf((*MockStorage)(nil).GetVersions), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockStorage) List(ctx context.Context, query *models.SecretListQuery) (*models.SecretsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, query)
+	ret0, _ := ret[0].(*models.SecretsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockStorageMockRecorder) List(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockStorage)(nil).List), ctx, query)
+}
+
+// Organization mocks base method.
+func (m *MockStorage) Organization() *models.Organization {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Organization")
+	ret0, _ := ret[0].(*models.Organization)
+	return ret0
+}
+
+// Organization indicates an expected call of Organization.
+func (mr *MockStorageMockRecorder) Organization() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Organization", reflect.TypeOf((*MockStorage)(nil).Organization))
+}
+
+// Purge mocks base method.
+func (m *MockStorage) Purge(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockStorageMockRecorder) Purge(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockStorage)(nil).Purge), ctx, id)
+}
+
+// RejectEmergencyAccess mocks base method.
+func (m *MockStorage) RejectEmergencyAccess(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectEmergencyAccess", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectEmergencyAccess indicates an expected call of RejectEmergencyAccess.
+func (mr *MockStorageMockRecorder) RejectEmergencyAccess(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectEmergencyAccess", reflect.TypeOf((*MockStorage)(nil).RejectEmergencyAccess), ctx, id)
+}
+
+// RemoveOrgMember mocks base method.
+func (m *MockStorage) RemoveOrgMember(ctx context.Context, orgID, userID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveOrgMember", ctx, orgID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveOrgMember indicates an expected call of RemoveOrgMember.
+func (mr *MockStorageMockRecorder) RemoveOrgMember(ctx, orgID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveOrgMember", reflect.TypeOf((*MockStorage)(nil).RemoveOrgMember), ctx, orgID, userID)
+}
+
+// RequestEmergencyAccess mocks base method.
+func (m *MockStorage) RequestEmergencyAccess(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestEmergencyAccess", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestEmergencyAccess indicates an expected call of RequestEmergencyAccess.
+func (mr *MockStorageMockRecorder) RequestEmergencyAccess(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestEmergencyAccess", reflect.TypeOf((*MockStorage)(nil).RequestEmergencyAccess), ctx, id)
+}
+
+// Restore mocks base method.
+func (m *MockStorage) Restore(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockStorageMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockStorage)(nil).Restore), ctx, id)
+}
+
+// RestoreVersion mocks base method.
+func (m *MockStorage) RestoreVersion(ctx context.Context, id, versionID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreVersion", ctx, id, versionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreVersion indicates an expected call of RestoreVersion.
+func (mr *MockStorageMockRecorder) RestoreVersion(ctx, id, versionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreVersion", reflect.TypeOf((*MockStorage)(nil).RestoreVersion), ctx, id, versionID)
+}
+
+// RevokeShare mocks base method.
+func (m *MockStorage) RevokeShare(ctx context.Context, shareID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeShare", ctx, shareID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeShare indicates an expected call of RevokeShare.
+func (mr *MockStorageMockRecorder) RevokeShare(ctx, shareID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeShare", reflect.TypeOf((*MockStorage)(nil).RevokeShare), ctx, shareID)
+}
+
+// ShareSecret mocks base method.
+func (m *MockStorage) ShareSecret(ctx context.Context, secret *models.Secret, recipient, fingerprint string, canEdit bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShareSecret", ctx, secret, recipient, fingerprint, canEdit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ShareSecret indicates an expected call of ShareSecret.
+func (mr *MockStorageMockRecorder) ShareSecret(ctx, secret, recipient, fingerprint, canEdit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShareSecret", reflect.TypeOf((*MockStorage)(nil).ShareSecret), ctx, secret, recipient, fingerprint, canEdit)
+}
+
 // String mocks base method.
 func (m *MockStorage) String() string {
 	m.ctrl.T.Helper()
@@ -120,3 +583,59 @@ func (mr *MockStorageMockRecorder) Update(ctx, secret interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockStorage)(nil).Update), ctx, secret)
 }
+
+// UpdateFolder mocks base method.
+func (m *MockStorage) UpdateFolder(ctx context.Context, id, parentID uint64, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFolder", ctx, id, parentID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFolder indicates an expected call of UpdateFolder.
+func (mr *MockStorageMockRecorder) UpdateFolder(ctx, id, parentID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFolder", reflect.TypeOf((*MockStorage)(nil).UpdateFolder), ctx, id, parentID, name)
+}
+
+// UpdateOrgMemberRole mocks base method.
+func (m *MockStorage) UpdateOrgMemberRole(ctx context.Context, orgID, userID uint64, role models.OrgRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrgMemberRole", ctx, orgID, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOrgMemberRole indicates an expected call of UpdateOrgMemberRole.
+func (mr *MockStorageMockRecorder) UpdateOrgMemberRole(ctx, orgID, userID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrgMemberRole", reflect.TypeOf((*MockStorage)(nil).UpdateOrgMemberRole), ctx, orgID, userID, role)
+}
+
+// UpdateTag mocks base method.
+func (m *MockStorage) UpdateTag(ctx context.Context, id uint64, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTag", ctx, id, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTag indicates an expected call of UpdateTag.
+func (mr *MockStorageMockRecorder) UpdateTag(ctx, id, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTag", reflect.TypeOf((*MockStorage)(nil).UpdateTag), ctx, id, name)
+}
+
+// UploadFile mocks base method.
+func (m *MockStorage) UploadFile(ctx context.Context, secret *models.Secret, path string, progress func(int64, int64)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadFile", ctx, secret, path, progress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UploadFile indicates an expected call of UploadFile.
+func (mr *MockStorageMockRecorder) UploadFile(ctx, secret, path, progress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFile", reflect.TypeOf((*MockStorage)(nil).UploadFile), ctx, secret, path, progress)
+}
@@ -6,6 +6,7 @@ package mocks
 
 import (
 	models "beliaev-aa/GophKeeper/internal/server/models"
+	models0 "beliaev-aa/GophKeeper/pkg/models"
 	context "context"
 	reflect "reflect"
 
@@ -50,6 +51,64 @@ func (mr *MockIUserRepositoryMockRecorder) Create(ctx, user interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIUserRepository)(nil).Create), ctx, user)
 }
 
+// CreateKeyPair mocks base method.
+func (m *MockIUserRepository) CreateKeyPair(ctx context.Context, userID int, keyPair *models0.KeyPair) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateKeyPair", ctx, userID, keyPair)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateKeyPair indicates an expected call of CreateKeyPair.
+func (mr *MockIUserRepositoryMockRecorder) CreateKeyPair(ctx, userID, keyPair interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKeyPair", reflect.TypeOf((*MockIUserRepository)(nil).CreateKeyPair), ctx, userID, keyPair)
+}
+
+// CreateVaultKey mocks base method.
+func (m *MockIUserRepository) CreateVaultKey(ctx context.Context, userID int, vaultKey []byte, secrets map[uint64]*models0.Secret) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVaultKey", ctx, userID, vaultKey, secrets)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateVaultKey indicates an expected call of CreateVaultKey.
+func (mr *MockIUserRepositoryMockRecorder) CreateVaultKey(ctx, userID, vaultKey, secrets interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVaultKey", reflect.TypeOf((*MockIUserRepository)(nil).CreateVaultKey), ctx, userID, vaultKey, secrets)
+}
+
+// Delete mocks base method.
+func (m *MockIUserRepository) Delete(ctx context.Context, userID int) ([]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockIUserRepositoryMockRecorder) Delete(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIUserRepository)(nil).Delete), ctx, userID)
+}
+
+// GetKeyPair mocks base method.
+func (m *MockIUserRepository) GetKeyPair(ctx context.Context, userID int) (*models0.KeyPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeyPair", ctx, userID)
+	ret0, _ := ret[0].(*models0.KeyPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeyPair indicates an expected call of GetKeyPair.
+func (mr *MockIUserRepositoryMockRecorder) GetKeyPair(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyPair", reflect.TypeOf((*MockIUserRepository)(nil).GetKeyPair), ctx, userID)
+}
+
 // GetUserByID mocks base method.
 func (m *MockIUserRepository) GetUserByID(ctx context.Context, ID int) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -79,3 +138,46 @@ func (mr *MockIUserRepositoryMockRecorder) GetUserByLogin(ctx, login interface{}
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByLogin", reflect.TypeOf((*MockIUserRepository)(nil).GetUserByLogin), ctx, login)
 }
+
+// GetVaultKey mocks base method.
+func (m *MockIUserRepository) GetVaultKey(ctx context.Context, userID int) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVaultKey", ctx, userID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVaultKey indicates an expected call of GetVaultKey.
+func (mr *MockIUserRepositoryMockRecorder) GetVaultKey(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVaultKey", reflect.TypeOf((*MockIUserRepository)(nil).GetVaultKey), ctx, userID)
+}
+
+// Rekey mocks base method.
+func (m *MockIUserRepository) Rekey(ctx context.Context, userID int, password string, kdf *models0.KDFParams, vaultKey []byte, secrets map[uint64]*models0.Secret) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rekey", ctx, userID, password, kdf, vaultKey, secrets)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rekey indicates an expected call of Rekey.
+func (mr *MockIUserRepositoryMockRecorder) Rekey(ctx, userID, password, kdf, vaultKey, secrets interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rekey", reflect.TypeOf((*MockIUserRepository)(nil).Rekey), ctx, userID, password, kdf, vaultKey, secrets)
+}
+
+// UpdateCredentials mocks base method.
+func (m *MockIUserRepository) UpdateCredentials(ctx context.Context, userID int, password string, authVersion int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCredentials", ctx, userID, password, authVersion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCredentials indicates an expected call of UpdateCredentials.
+func (mr *MockIUserRepositoryMockRecorder) UpdateCredentials(ctx, userID, password, authVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCredentials", reflect.TypeOf((*MockIUserRepository)(nil).UpdateCredentials), ctx, userID, password, authVersion)
+}
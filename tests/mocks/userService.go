@@ -6,6 +6,7 @@ package mocks
 
 import (
 	models "beliaev-aa/GophKeeper/internal/server/models"
+	models0 "beliaev-aa/GophKeeper/pkg/models"
 	context "context"
 	reflect "reflect"
 
@@ -35,32 +36,163 @@ func (m *MockIUserService) EXPECT() *MockIUserServiceMockRecorder {
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockIUserService) ChangePassword(ctx context.Context, userID int, currentAuthHash, authHash string, kdf *models0.KDFParams, vaultKey []byte, secrets map[uint64]*models0.Secret) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, userID, currentAuthHash, authHash, kdf, vaultKey, secrets)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockIUserServiceMockRecorder) ChangePassword(ctx, userID, currentAuthHash, authHash, kdf, vaultKey, secrets interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockIUserService)(nil).ChangePassword), ctx, userID, currentAuthHash, authHash, kdf, vaultKey, secrets)
+}
+
+// CreateKeyPair mocks base method.
+func (m *MockIUserService) CreateKeyPair(ctx context.Context, userID int, keyPair *models0.KeyPair) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateKeyPair", ctx, userID, keyPair)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateKeyPair indicates an expected call of CreateKeyPair.
+func (mr *MockIUserServiceMockRecorder) CreateKeyPair(ctx, userID, keyPair interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKeyPair", reflect.TypeOf((*MockIUserService)(nil).CreateKeyPair), ctx, userID, keyPair)
+}
+
+// CreateVaultKey mocks base method.
+func (m *MockIUserService) CreateVaultKey(ctx context.Context, userID int, vaultKey []byte, secrets map[uint64]*models0.Secret) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVaultKey", ctx, userID, vaultKey, secrets)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateVaultKey indicates an expected call of CreateVaultKey.
+func (mr *MockIUserServiceMockRecorder) CreateVaultKey(ctx, userID, vaultKey, secrets interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVaultKey", reflect.TypeOf((*MockIUserService)(nil).CreateVaultKey), ctx, userID, vaultKey, secrets)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockIUserService) DeleteAccount(ctx context.Context, userID int, authHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", ctx, userID, authHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockIUserServiceMockRecorder) DeleteAccount(ctx, userID, authHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockIUserService)(nil).DeleteAccount), ctx, userID, authHash)
+}
+
+// GetKeyPair mocks base method.
+func (m *MockIUserService) GetKeyPair(ctx context.Context, userID int) (*models0.KeyPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeyPair", ctx, userID)
+	ret0, _ := ret[0].(*models0.KeyPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeyPair indicates an expected call of GetKeyPair.
+func (mr *MockIUserServiceMockRecorder) GetKeyPair(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyPair", reflect.TypeOf((*MockIUserService)(nil).GetKeyPair), ctx, userID)
+}
+
+// GetVaultKey mocks base method.
+func (m *MockIUserService) GetVaultKey(ctx context.Context, userID int) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVaultKey", ctx, userID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVaultKey indicates an expected call of GetVaultKey.
+func (mr *MockIUserServiceMockRecorder) GetVaultKey(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVaultKey", reflect.TypeOf((*MockIUserService)(nil).GetVaultKey), ctx, userID)
+}
+
 // LoginUser mocks base method.
-func (m *MockIUserService) LoginUser(ctx context.Context, login, password string) (*models.User, error) {
+func (m *MockIUserService) LoginUser(ctx context.Context, login, authHash, legacyPassword string) (*models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LoginUser", ctx, login, password)
+	ret := m.ctrl.Call(m, "LoginUser", ctx, login, authHash, legacyPassword)
 	ret0, _ := ret[0].(*models.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // LoginUser indicates an expected call of LoginUser.
-func (mr *MockIUserServiceMockRecorder) LoginUser(ctx, login, password interface{}) *gomock.Call {
+func (mr *MockIUserServiceMockRecorder) LoginUser(ctx, login, authHash, legacyPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginUser", reflect.TypeOf((*MockIUserService)(nil).LoginUser), ctx, login, authHash, legacyPassword)
+}
+
+// NewKDFParams mocks base method.
+func (m *MockIUserService) NewKDFParams() (*models0.KDFParams, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewKDFParams")
+	ret0, _ := ret[0].(*models0.KDFParams)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewKDFParams indicates an expected call of NewKDFParams.
+func (mr *MockIUserServiceMockRecorder) NewKDFParams() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginUser", reflect.TypeOf((*MockIUserService)(nil).LoginUser), ctx, login, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewKDFParams", reflect.TypeOf((*MockIUserService)(nil).NewKDFParams))
+}
+
+// PreLogin mocks base method.
+func (m *MockIUserService) PreLogin(ctx context.Context, login string) (*models0.KDFParams, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreLogin", ctx, login)
+	ret0, _ := ret[0].(*models0.KDFParams)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PreLogin indicates an expected call of PreLogin.
+func (mr *MockIUserServiceMockRecorder) PreLogin(ctx, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreLogin", reflect.TypeOf((*MockIUserService)(nil).PreLogin), ctx, login)
 }
 
 // RegisterUser mocks base method.
-func (m *MockIUserService) RegisterUser(ctx context.Context, login, password string) (*models.User, error) {
+func (m *MockIUserService) RegisterUser(ctx context.Context, login, authHash string, kdf *models0.KDFParams) (*models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RegisterUser", ctx, login, password)
+	ret := m.ctrl.Call(m, "RegisterUser", ctx, login, authHash, kdf)
 	ret0, _ := ret[0].(*models.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RegisterUser indicates an expected call of RegisterUser.
-func (mr *MockIUserServiceMockRecorder) RegisterUser(ctx, login, password interface{}) *gomock.Call {
+func (mr *MockIUserServiceMockRecorder) RegisterUser(ctx, login, authHash, kdf interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUser", reflect.TypeOf((*MockIUserService)(nil).RegisterUser), ctx, login, authHash, kdf)
+}
+
+// UpgradeKDF mocks base method.
+func (m *MockIUserService) UpgradeKDF(ctx context.Context, userID int, currentAuthHash, authHash string, kdf *models0.KDFParams, vaultKey []byte, secrets map[uint64]*models0.Secret) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpgradeKDF", ctx, userID, currentAuthHash, authHash, kdf, vaultKey, secrets)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpgradeKDF indicates an expected call of UpgradeKDF.
+func (mr *MockIUserServiceMockRecorder) UpgradeKDF(ctx, userID, currentAuthHash, authHash, kdf, vaultKey, secrets interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUser", reflect.TypeOf((*MockIUserService)(nil).RegisterUser), ctx, login, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeKDF", reflect.TypeOf((*MockIUserService)(nil).UpgradeKDF), ctx, userID, currentAuthHash, authHash, kdf, vaultKey, secrets)
 }
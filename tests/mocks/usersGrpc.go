@@ -36,6 +36,206 @@ func (m *MockUsersClient) EXPECT() *MockUsersClientMockRecorder {
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockUsersClient) ChangePassword(ctx context.Context, in *proto.ChangePasswordRequest, opts ...grpc.CallOption) (*proto.ChangePasswordResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ChangePassword", varargs...)
+	ret0, _ := ret[0].(*proto.ChangePasswordResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockUsersClientMockRecorder) ChangePassword(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUsersClient)(nil).ChangePassword), varargs...)
+}
+
+// ConfirmTOTP mocks base method.
+func (m *MockUsersClient) ConfirmTOTP(ctx context.Context, in *proto.ConfirmTOTPRequest, opts ...grpc.CallOption) (*proto.ConfirmTOTPResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ConfirmTOTP", varargs...)
+	ret0, _ := ret[0].(*proto.ConfirmTOTPResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTOTP indicates an expected call of ConfirmTOTP.
+func (mr *MockUsersClientMockRecorder) ConfirmTOTP(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTOTP", reflect.TypeOf((*MockUsersClient)(nil).ConfirmTOTP), varargs...)
+}
+
+// CreateKeyPair mocks base method.
+func (m *MockUsersClient) CreateKeyPair(ctx context.Context, in *proto.CreateKeyPairRequest, opts ...grpc.CallOption) (*proto.CreateKeyPairResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateKeyPair", varargs...)
+	ret0, _ := ret[0].(*proto.CreateKeyPairResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateKeyPair indicates an expected call of CreateKeyPair.
+func (mr *MockUsersClientMockRecorder) CreateKeyPair(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKeyPair", reflect.TypeOf((*MockUsersClient)(nil).CreateKeyPair), varargs...)
+}
+
+// CreateVaultKey mocks base method.
+func (m *MockUsersClient) CreateVaultKey(ctx context.Context, in *proto.CreateVaultKeyRequest, opts ...grpc.CallOption) (*proto.CreateVaultKeyResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateVaultKey", varargs...)
+	ret0, _ := ret[0].(*proto.CreateVaultKeyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVaultKey indicates an expected call of CreateVaultKey.
+func (mr *MockUsersClientMockRecorder) CreateVaultKey(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVaultKey", reflect.TypeOf((*MockUsersClient)(nil).CreateVaultKey), varargs...)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockUsersClient) DeleteAccount(ctx context.Context, in *proto.DeleteAccountRequest, opts ...grpc.CallOption) (*proto.DeleteAccountResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteAccount", varargs...)
+	ret0, _ := ret[0].(*proto.DeleteAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockUsersClientMockRecorder) DeleteAccount(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockUsersClient)(nil).DeleteAccount), varargs...)
+}
+
+// DisableTOTP mocks base method.
+func (m *MockUsersClient) DisableTOTP(ctx context.Context, in *proto.DisableTOTPRequest, opts ...grpc.CallOption) (*proto.DisableTOTPResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DisableTOTP", varargs...)
+	ret0, _ := ret[0].(*proto.DisableTOTPResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableTOTP indicates an expected call of DisableTOTP.
+func (mr *MockUsersClientMockRecorder) DisableTOTP(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTP", reflect.TypeOf((*MockUsersClient)(nil).DisableTOTP), varargs...)
+}
+
+// EnableTOTP mocks base method.
+func (m *MockUsersClient) EnableTOTP(ctx context.Context, in *proto.EnableTOTPRequest, opts ...grpc.CallOption) (*proto.EnableTOTPResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EnableTOTP", varargs...)
+	ret0, _ := ret[0].(*proto.EnableTOTPResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableTOTP indicates an expected call of EnableTOTP.
+func (mr *MockUsersClientMockRecorder) EnableTOTP(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableTOTP", reflect.TypeOf((*MockUsersClient)(nil).EnableTOTP), varargs...)
+}
+
+// GetKeyPair mocks base method.
+func (m *MockUsersClient) GetKeyPair(ctx context.Context, in *proto.GetKeyPairRequest, opts ...grpc.CallOption) (*proto.GetKeyPairResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetKeyPair", varargs...)
+	ret0, _ := ret[0].(*proto.GetKeyPairResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeyPair indicates an expected call of GetKeyPair.
+func (mr *MockUsersClientMockRecorder) GetKeyPair(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyPair", reflect.TypeOf((*MockUsersClient)(nil).GetKeyPair), varargs...)
+}
+
+// GetVaultKey mocks base method.
+func (m *MockUsersClient) GetVaultKey(ctx context.Context, in *proto.GetVaultKeyRequest, opts ...grpc.CallOption) (*proto.GetVaultKeyResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetVaultKey", varargs...)
+	ret0, _ := ret[0].(*proto.GetVaultKeyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVaultKey indicates an expected call of GetVaultKey.
+func (mr *MockUsersClientMockRecorder) GetVaultKey(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVaultKey", reflect.TypeOf((*MockUsersClient)(nil).GetVaultKey), varargs...)
+}
+
+// ListSessions mocks base method.
+func (m *MockUsersClient) ListSessions(ctx context.Context, in *proto.ListSessionsRequest, opts ...grpc.CallOption) (*proto.ListSessionsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListSessions", varargs...)
+	ret0, _ := ret[0].(*proto.ListSessionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockUsersClientMockRecorder) ListSessions(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockUsersClient)(nil).ListSessions), varargs...)
+}
+
 // Login mocks base method.
 func (m *MockUsersClient) Login(ctx context.Context, in *proto.LoginRequest, opts ...grpc.CallOption) (*proto.LoginResponse, error) {
 	m.ctrl.T.Helper()
@@ -56,6 +256,86 @@ func (mr *MockUsersClientMockRecorder) Login(ctx, in interface{}, opts ...interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUsersClient)(nil).Login), varargs...)
 }
 
+// LogoutAll mocks base method.
+func (m *MockUsersClient) LogoutAll(ctx context.Context, in *proto.LogoutAllRequest, opts ...grpc.CallOption) (*proto.LogoutAllResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LogoutAll", varargs...)
+	ret0, _ := ret[0].(*proto.LogoutAllResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LogoutAll indicates an expected call of LogoutAll.
+func (mr *MockUsersClientMockRecorder) LogoutAll(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutAll", reflect.TypeOf((*MockUsersClient)(nil).LogoutAll), varargs...)
+}
+
+// PreLogin mocks base method.
+func (m *MockUsersClient) PreLogin(ctx context.Context, in *proto.PreLoginRequest, opts ...grpc.CallOption) (*proto.PreLoginResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PreLogin", varargs...)
+	ret0, _ := ret[0].(*proto.PreLoginResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreLogin indicates an expected call of PreLogin.
+func (mr *MockUsersClientMockRecorder) PreLogin(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreLogin", reflect.TypeOf((*MockUsersClient)(nil).PreLogin), varargs...)
+}
+
+// PreRegister mocks base method.
+func (m *MockUsersClient) PreRegister(ctx context.Context, in *proto.PreRegisterRequest, opts ...grpc.CallOption) (*proto.PreRegisterResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PreRegister", varargs...)
+	ret0, _ := ret[0].(*proto.PreRegisterResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreRegister indicates an expected call of PreRegister.
+func (mr *MockUsersClientMockRecorder) PreRegister(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreRegister", reflect.TypeOf((*MockUsersClient)(nil).PreRegister), varargs...)
+}
+
+// RefreshToken mocks base method.
+func (m *MockUsersClient) RefreshToken(ctx context.Context, in *proto.RefreshTokenRequest, opts ...grpc.CallOption) (*proto.RefreshTokenResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RefreshToken", varargs...)
+	ret0, _ := ret[0].(*proto.RefreshTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *MockUsersClientMockRecorder) RefreshToken(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockUsersClient)(nil).RefreshToken), varargs...)
+}
+
 // Register mocks base method.
 func (m *MockUsersClient) Register(ctx context.Context, in *proto.RegisterRequest, opts ...grpc.CallOption) (*proto.RegisterResponse, error) {
 	m.ctrl.T.Helper()
@@ -76,6 +356,86 @@ func (mr *MockUsersClientMockRecorder) Register(ctx, in interface{}, opts ...int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUsersClient)(nil).Register), varargs...)
 }
 
+// RenameSession mocks base method.
+func (m *MockUsersClient) RenameSession(ctx context.Context, in *proto.RenameSessionRequest, opts ...grpc.CallOption) (*proto.RenameSessionResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RenameSession", varargs...)
+	ret0, _ := ret[0].(*proto.RenameSessionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameSession indicates an expected call of RenameSession.
+func (mr *MockUsersClientMockRecorder) RenameSession(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameSession", reflect.TypeOf((*MockUsersClient)(nil).RenameSession), varargs...)
+}
+
+// RevokeSession mocks base method.
+func (m *MockUsersClient) RevokeSession(ctx context.Context, in *proto.RevokeSessionRequest, opts ...grpc.CallOption) (*proto.RevokeSessionResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RevokeSession", varargs...)
+	ret0, _ := ret[0].(*proto.RevokeSessionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockUsersClientMockRecorder) RevokeSession(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockUsersClient)(nil).RevokeSession), varargs...)
+}
+
+// UpgradeKDF mocks base method.
+func (m *MockUsersClient) UpgradeKDF(ctx context.Context, in *proto.UpgradeKDFRequest, opts ...grpc.CallOption) (*proto.UpgradeKDFResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpgradeKDF", varargs...)
+	ret0, _ := ret[0].(*proto.UpgradeKDFResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpgradeKDF indicates an expected call of UpgradeKDF.
+func (mr *MockUsersClientMockRecorder) UpgradeKDF(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeKDF", reflect.TypeOf((*MockUsersClient)(nil).UpgradeKDF), varargs...)
+}
+
+// VerifyTOTP mocks base method.
+func (m *MockUsersClient) VerifyTOTP(ctx context.Context, in *proto.VerifyTOTPRequest, opts ...grpc.CallOption) (*proto.VerifyTOTPResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "VerifyTOTP", varargs...)
+	ret0, _ := ret[0].(*proto.VerifyTOTPResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyTOTP indicates an expected call of VerifyTOTP.
+func (mr *MockUsersClientMockRecorder) VerifyTOTP(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTOTP", reflect.TypeOf((*MockUsersClient)(nil).VerifyTOTP), varargs...)
+}
+
 // MockUsersServer is a mock of UsersServer interface.
 type MockUsersServer struct {
 	ctrl     *gomock.Controller
@@ -99,6 +459,156 @@ func (m *MockUsersServer) EXPECT() *MockUsersServerMockRecorder {
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockUsersServer) ChangePassword(arg0 context.Context, arg1 *proto.ChangePasswordRequest) (*proto.ChangePasswordResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", arg0, arg1)
+	ret0, _ := ret[0].(*proto.ChangePasswordResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockUsersServerMockRecorder) ChangePassword(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUsersServer)(nil).ChangePassword), arg0, arg1)
+}
+
+// ConfirmTOTP mocks base method.
+func (m *MockUsersServer) ConfirmTOTP(arg0 context.Context, arg1 *proto.ConfirmTOTPRequest) (*proto.ConfirmTOTPResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTOTP", arg0, arg1)
+	ret0, _ := ret[0].(*proto.ConfirmTOTPResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTOTP indicates an expected call of ConfirmTOTP.
+func (mr *MockUsersServerMockRecorder) ConfirmTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTOTP", reflect.TypeOf((*MockUsersServer)(nil).ConfirmTOTP), arg0, arg1)
+}
+
+// CreateKeyPair mocks base method.
+func (m *MockUsersServer) CreateKeyPair(arg0 context.Context, arg1 *proto.CreateKeyPairRequest) (*proto.CreateKeyPairResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateKeyPair", arg0, arg1)
+	ret0, _ := ret[0].(*proto.CreateKeyPairResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateKeyPair indicates an expected call of CreateKeyPair.
+func (mr *MockUsersServerMockRecorder) CreateKeyPair(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKeyPair", reflect.TypeOf((*MockUsersServer)(nil).CreateKeyPair), arg0, arg1)
+}
+
+// CreateVaultKey mocks base method.
+func (m *MockUsersServer) CreateVaultKey(arg0 context.Context, arg1 *proto.CreateVaultKeyRequest) (*proto.CreateVaultKeyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVaultKey", arg0, arg1)
+	ret0, _ := ret[0].(*proto.CreateVaultKeyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVaultKey indicates an expected call of CreateVaultKey.
+func (mr *MockUsersServerMockRecorder) CreateVaultKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVaultKey", reflect.TypeOf((*MockUsersServer)(nil).CreateVaultKey), arg0, arg1)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockUsersServer) DeleteAccount(arg0 context.Context, arg1 *proto.DeleteAccountRequest) (*proto.DeleteAccountResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
+	ret0, _ := ret[0].(*proto.DeleteAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockUsersServerMockRecorder) DeleteAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockUsersServer)(nil).DeleteAccount), arg0, arg1)
+}
+
+// DisableTOTP mocks base method.
+func (m *MockUsersServer) DisableTOTP(arg0 context.Context, arg1 *proto.DisableTOTPRequest) (*proto.DisableTOTPResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTOTP", arg0, arg1)
+	ret0, _ := ret[0].(*proto.DisableTOTPResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableTOTP indicates an expected call of DisableTOTP.
+func (mr *MockUsersServerMockRecorder) DisableTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTP", reflect.TypeOf((*MockUsersServer)(nil).DisableTOTP), arg0, arg1)
+}
+
+// EnableTOTP mocks base method.
+func (m *MockUsersServer) EnableTOTP(arg0 context.Context, arg1 *proto.EnableTOTPRequest) (*proto.EnableTOTPResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableTOTP", arg0, arg1)
+	ret0, _ := ret[0].(*proto.EnableTOTPResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableTOTP indicates an expected call of EnableTOTP.
+func (mr *MockUsersServerMockRecorder) EnableTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableTOTP", reflect.TypeOf((*MockUsersServer)(nil).EnableTOTP), arg0, arg1)
+}
+
+// GetKeyPair mocks base method.
+func (m *MockUsersServer) GetKeyPair(arg0 context.Context, arg1 *proto.GetKeyPairRequest) (*proto.GetKeyPairResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeyPair", arg0, arg1)
+	ret0, _ := ret[0].(*proto.GetKeyPairResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeyPair indicates an expected call of GetKeyPair.
+func (mr *MockUsersServerMockRecorder) GetKeyPair(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyPair", reflect.TypeOf((*MockUsersServer)(nil).GetKeyPair), arg0, arg1)
+}
+
+// GetVaultKey mocks base method.
+func (m *MockUsersServer) GetVaultKey(arg0 context.Context, arg1 *proto.GetVaultKeyRequest) (*proto.GetVaultKeyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVaultKey", arg0, arg1)
+	ret0, _ := ret[0].(*proto.GetVaultKeyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVaultKey indicates an expected call of GetVaultKey.
+func (mr *MockUsersServerMockRecorder) GetVaultKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVaultKey", reflect.TypeOf((*MockUsersServer)(nil).GetVaultKey), arg0, arg1)
+}
+
+// ListSessions mocks base method.
+func (m *MockUsersServer) ListSessions(arg0 context.Context, arg1 *proto.ListSessionsRequest) (*proto.ListSessionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessions", arg0, arg1)
+	ret0, _ := ret[0].(*proto.ListSessionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockUsersServerMockRecorder) ListSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockUsersServer)(nil).ListSessions), arg0, arg1)
+}
+
 // Login mocks base method.
 func (m *MockUsersServer) Login(arg0 context.Context, arg1 *proto.LoginRequest) (*proto.LoginResponse, error) {
 	m.ctrl.T.Helper()
@@ -114,6 +624,66 @@ func (mr *MockUsersServerMockRecorder) Login(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUsersServer)(nil).Login), arg0, arg1)
 }
 
+// LogoutAll mocks base method.
+func (m *MockUsersServer) LogoutAll(arg0 context.Context, arg1 *proto.LogoutAllRequest) (*proto.LogoutAllResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogoutAll", arg0, arg1)
+	ret0, _ := ret[0].(*proto.LogoutAllResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LogoutAll indicates an expected call of LogoutAll.
+func (mr *MockUsersServerMockRecorder) LogoutAll(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutAll", reflect.TypeOf((*MockUsersServer)(nil).LogoutAll), arg0, arg1)
+}
+
+// PreLogin mocks base method.
+func (m *MockUsersServer) PreLogin(arg0 context.Context, arg1 *proto.PreLoginRequest) (*proto.PreLoginResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreLogin", arg0, arg1)
+	ret0, _ := ret[0].(*proto.PreLoginResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreLogin indicates an expected call of PreLogin.
+func (mr *MockUsersServerMockRecorder) PreLogin(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreLogin", reflect.TypeOf((*MockUsersServer)(nil).PreLogin), arg0, arg1)
+}
+
+// PreRegister mocks base method.
+func (m *MockUsersServer) PreRegister(arg0 context.Context, arg1 *proto.PreRegisterRequest) (*proto.PreRegisterResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreRegister", arg0, arg1)
+	ret0, _ := ret[0].(*proto.PreRegisterResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreRegister indicates an expected call of PreRegister.
+func (mr *MockUsersServerMockRecorder) PreRegister(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreRegister", reflect.TypeOf((*MockUsersServer)(nil).PreRegister), arg0, arg1)
+}
+
+// RefreshToken mocks base method.
+func (m *MockUsersServer) RefreshToken(arg0 context.Context, arg1 *proto.RefreshTokenRequest) (*proto.RefreshTokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshToken", arg0, arg1)
+	ret0, _ := ret[0].(*proto.RefreshTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *MockUsersServerMockRecorder) RefreshToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockUsersServer)(nil).RefreshToken), arg0, arg1)
+}
+
 // Register mocks base method.
 func (m *MockUsersServer) Register(arg0 context.Context, arg1 *proto.RegisterRequest) (*proto.RegisterResponse, error) {
 	m.ctrl.T.Helper()
@@ -129,6 +699,66 @@ func (mr *MockUsersServerMockRecorder) Register(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUsersServer)(nil).Register), arg0, arg1)
 }
 
+// RenameSession mocks base method.
+func (m *MockUsersServer) RenameSession(arg0 context.Context, arg1 *proto.RenameSessionRequest) (*proto.RenameSessionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameSession", arg0, arg1)
+	ret0, _ := ret[0].(*proto.RenameSessionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameSession indicates an expected call of RenameSession.
+func (mr *MockUsersServerMockRecorder) RenameSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameSession", reflect.TypeOf((*MockUsersServer)(nil).RenameSession), arg0, arg1)
+}
+
+// RevokeSession mocks base method.
+func (m *MockUsersServer) RevokeSession(arg0 context.Context, arg1 *proto.RevokeSessionRequest) (*proto.RevokeSessionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", arg0, arg1)
+	ret0, _ := ret[0].(*proto.RevokeSessionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockUsersServerMockRecorder) RevokeSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockUsersServer)(nil).RevokeSession), arg0, arg1)
+}
+
+// UpgradeKDF mocks base method.
+func (m *MockUsersServer) UpgradeKDF(arg0 context.Context, arg1 *proto.UpgradeKDFRequest) (*proto.UpgradeKDFResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpgradeKDF", arg0, arg1)
+	ret0, _ := ret[0].(*proto.UpgradeKDFResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpgradeKDF indicates an expected call of UpgradeKDF.
+func (mr *MockUsersServerMockRecorder) UpgradeKDF(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeKDF", reflect.TypeOf((*MockUsersServer)(nil).UpgradeKDF), arg0, arg1)
+}
+
+// VerifyTOTP mocks base method.
+func (m *MockUsersServer) VerifyTOTP(arg0 context.Context, arg1 *proto.VerifyTOTPRequest) (*proto.VerifyTOTPResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyTOTP", arg0, arg1)
+	ret0, _ := ret[0].(*proto.VerifyTOTPResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyTOTP indicates an expected call of VerifyTOTP.
+func (mr *MockUsersServerMockRecorder) VerifyTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTOTP", reflect.TypeOf((*MockUsersServer)(nil).VerifyTOTP), arg0, arg1)
+}
+
 // mustEmbedUnimplementedUsersServer mocks base method.
 func (m *MockUsersServer) mustEmbedUnimplementedUsersServer() {
 	m.ctrl.T.Helper()